@@ -7,21 +7,72 @@ package gen
 
 import (
 	"context"
+	"database/sql"
 )
 
+const createBankAccount = `-- name: CreateBankAccount :one
+INSERT INTO bank_accounts (id, user_id, bank_name, bank_code, account_number, account_name, currency, provider, account_number_hash)
+VALUES (gen_random_uuid()::text, $1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, user_id, bank_name, bank_code, account_number, account_name, currency, provider, created_at, updated_at, account_number_hash
+`
+
+type CreateBankAccountParams struct {
+	UserID            string         `db:"user_id" json:"user_id"`
+	BankName          string         `db:"bank_name" json:"bank_name"`
+	BankCode          string         `db:"bank_code" json:"bank_code"`
+	AccountNumber     string         `db:"account_number" json:"account_number"`
+	AccountName       sql.NullString `db:"account_name" json:"account_name"`
+	Currency          string         `db:"currency" json:"currency"`
+	Provider          string         `db:"provider" json:"provider"`
+	AccountNumberHash sql.NullString `db:"account_number_hash" json:"account_number_hash"`
+}
+
+func (q *Queries) CreateBankAccount(ctx context.Context, arg CreateBankAccountParams) (BankAccount, error) {
+	row := q.db.QueryRowContext(ctx, createBankAccount,
+		arg.UserID,
+		arg.BankName,
+		arg.BankCode,
+		arg.AccountNumber,
+		arg.AccountName,
+		arg.Currency,
+		arg.Provider,
+		arg.AccountNumberHash,
+	)
+	var i BankAccount
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.BankName,
+		&i.BankCode,
+		&i.AccountNumber,
+		&i.AccountName,
+		&i.Currency,
+		&i.Provider,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AccountNumberHash,
+	)
+	return i, err
+}
+
 const getBankAccountByAccountAndBankCode = `-- name: GetBankAccountByAccountAndBankCode :one
-SELECT id, user_id, bank_name, bank_code, account_number, account_name, currency, provider, created_at, updated_at
+SELECT id, user_id, bank_name, bank_code, account_number, account_name, currency, provider, created_at, updated_at, account_number_hash
 FROM bank_accounts
-WHERE account_number = $1 AND bank_code = $2
+WHERE bank_code = $1
+  AND (account_number_hash = $2
+       OR (account_number_hash IS NULL AND account_number = $3))
 `
 
 type GetBankAccountByAccountAndBankCodeParams struct {
-	AccountNumber string `db:"account_number" json:"account_number"`
-	BankCode      string `db:"bank_code" json:"bank_code"`
+	BankCode          string         `db:"bank_code" json:"bank_code"`
+	AccountNumberHash sql.NullString `db:"account_number_hash" json:"account_number_hash"`
+	AccountNumber     string         `db:"account_number" json:"account_number"`
 }
 
+// Rows written before encryption was enabled have no blind index yet and are
+// matched on the plaintext number until the re-encryption worker reaches them.
 func (q *Queries) GetBankAccountByAccountAndBankCode(ctx context.Context, arg GetBankAccountByAccountAndBankCodeParams) (BankAccount, error) {
-	row := q.db.QueryRowContext(ctx, getBankAccountByAccountAndBankCode, arg.AccountNumber, arg.BankCode)
+	row := q.db.QueryRowContext(ctx, getBankAccountByAccountAndBankCode, arg.BankCode, arg.AccountNumberHash, arg.AccountNumber)
 	var i BankAccount
 	err := row.Scan(
 		&i.ID,
@@ -34,12 +85,13 @@ func (q *Queries) GetBankAccountByAccountAndBankCode(ctx context.Context, arg Ge
 		&i.Provider,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AccountNumberHash,
 	)
 	return i, err
 }
 
 const getBankAccountByID = `-- name: GetBankAccountByID :one
-SELECT id, user_id, bank_name, bank_code, account_number, account_name, currency, provider, created_at, updated_at
+SELECT id, user_id, bank_name, bank_code, account_number, account_name, currency, provider, created_at, updated_at, account_number_hash
 FROM bank_accounts
 WHERE id = $1
 `
@@ -58,6 +110,79 @@ func (q *Queries) GetBankAccountByID(ctx context.Context, id string) (BankAccoun
 		&i.Provider,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AccountNumberHash,
 	)
 	return i, err
 }
+
+const listBankAccountsToEncrypt = `-- name: ListBankAccountsToEncrypt :many
+SELECT id, account_number
+FROM bank_accounts
+WHERE id > $1
+  AND (NOT starts_with(account_number, $2::text) OR account_number_hash IS NULL)
+ORDER BY id
+LIMIT $3
+`
+
+type ListBankAccountsToEncryptParams struct {
+	AfterID   string `db:"after_id" json:"after_id"`
+	KeyPrefix string `db:"key_prefix" json:"key_prefix"`
+	RowLimit  int32  `db:"row_limit" json:"row_limit"`
+}
+
+type ListBankAccountsToEncryptRow struct {
+	ID            string `db:"id" json:"id"`
+	AccountNumber string `db:"account_number" json:"account_number"`
+}
+
+func (q *Queries) ListBankAccountsToEncrypt(ctx context.Context, arg ListBankAccountsToEncryptParams) ([]ListBankAccountsToEncryptRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBankAccountsToEncrypt, arg.AfterID, arg.KeyPrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBankAccountsToEncryptRow
+	for rows.Next() {
+		var i ListBankAccountsToEncryptRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountNumber,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateBankAccountNumberEncryption = `-- name: UpdateBankAccountNumberEncryption :execrows
+UPDATE bank_accounts
+SET account_number = $1, account_number_hash = $2
+WHERE id = $3 AND account_number = $4
+`
+
+type UpdateBankAccountNumberEncryptionParams struct {
+	AccountNumber        string         `db:"account_number" json:"account_number"`
+	AccountNumberHash    sql.NullString `db:"account_number_hash" json:"account_number_hash"`
+	ID                   string         `db:"id" json:"id"`
+	CurrentAccountNumber string         `db:"current_account_number" json:"current_account_number"`
+}
+
+func (q *Queries) UpdateBankAccountNumberEncryption(ctx context.Context, arg UpdateBankAccountNumberEncryptionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateBankAccountNumberEncryption,
+		arg.AccountNumber,
+		arg.AccountNumberHash,
+		arg.ID,
+		arg.CurrentAccountNumber,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
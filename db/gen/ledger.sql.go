@@ -47,6 +47,80 @@ func (q *Queries) CreateExternalSystemCreditEntry(ctx context.Context, arg Creat
 	return i, err
 }
 
+const createExternalSystemDebitEntry = `-- name: CreateExternalSystemDebitEntry :one
+INSERT INTO ledger_entries (id, wallet_id, transaction_id, amount, currency, account_type, balance_before, balance_after)
+VALUES (gen_random_uuid()::text, NULL, $1, $2, $3, 'external_wallet', $4, $5)
+RETURNING id, wallet_id, transaction_id, amount, currency, account_type, balance_before, balance_after, created_at
+`
+
+type CreateExternalSystemDebitEntryParams struct {
+	TransactionID string `db:"transaction_id" json:"transaction_id"`
+	Amount        int64  `db:"amount" json:"amount"`
+	Currency      string `db:"currency" json:"currency"`
+	BalanceBefore int64  `db:"balance_before" json:"balance_before"`
+	BalanceAfter  int64  `db:"balance_after" json:"balance_after"`
+}
+
+func (q *Queries) CreateExternalSystemDebitEntry(ctx context.Context, arg CreateExternalSystemDebitEntryParams) (LedgerEntry, error) {
+	row := q.db.QueryRowContext(ctx, createExternalSystemDebitEntry,
+		arg.TransactionID,
+		arg.Amount,
+		arg.Currency,
+		arg.BalanceBefore,
+		arg.BalanceAfter,
+	)
+	var i LedgerEntry
+	err := row.Scan(
+		&i.ID,
+		&i.WalletID,
+		&i.TransactionID,
+		&i.Amount,
+		&i.Currency,
+		&i.AccountType,
+		&i.BalanceBefore,
+		&i.BalanceAfter,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createFeeRevenueEntry = `-- name: CreateFeeRevenueEntry :one
+INSERT INTO ledger_entries (id, wallet_id, transaction_id, amount, currency, account_type, balance_before, balance_after)
+VALUES (gen_random_uuid()::text, NULL, $1, $2, $3, 'fee_revenue', $4, $5)
+RETURNING id, wallet_id, transaction_id, amount, currency, account_type, balance_before, balance_after, created_at
+`
+
+type CreateFeeRevenueEntryParams struct {
+	TransactionID string `db:"transaction_id" json:"transaction_id"`
+	Amount        int64  `db:"amount" json:"amount"`
+	Currency      string `db:"currency" json:"currency"`
+	BalanceBefore int64  `db:"balance_before" json:"balance_before"`
+	BalanceAfter  int64  `db:"balance_after" json:"balance_after"`
+}
+
+func (q *Queries) CreateFeeRevenueEntry(ctx context.Context, arg CreateFeeRevenueEntryParams) (LedgerEntry, error) {
+	row := q.db.QueryRowContext(ctx, createFeeRevenueEntry,
+		arg.TransactionID,
+		arg.Amount,
+		arg.Currency,
+		arg.BalanceBefore,
+		arg.BalanceAfter,
+	)
+	var i LedgerEntry
+	err := row.Scan(
+		&i.ID,
+		&i.WalletID,
+		&i.TransactionID,
+		&i.Amount,
+		&i.Currency,
+		&i.AccountType,
+		&i.BalanceBefore,
+		&i.BalanceAfter,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const createLedgerEntry = `-- name: CreateLedgerEntry :one
 INSERT INTO ledger_entries (id, wallet_id, transaction_id, amount, currency, account_type, balance_before, balance_after)
 VALUES (gen_random_uuid()::text, $1, $2, $3, $4, 'user_wallet', $5, $6)
@@ -86,6 +160,19 @@ func (q *Queries) CreateLedgerEntry(ctx context.Context, arg CreateLedgerEntryPa
 	return i, err
 }
 
+const getFeeRevenueBalance = `-- name: GetFeeRevenueBalance :one
+SELECT COALESCE(SUM(amount), 0)::BIGINT as balance
+FROM ledger_entries
+WHERE account_type = 'fee_revenue' AND currency = $1
+`
+
+func (q *Queries) GetFeeRevenueBalance(ctx context.Context, currency string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getFeeRevenueBalance, currency)
+	var balance int64
+	err := row.Scan(&balance)
+	return balance, err
+}
+
 const getWalletBalance = `-- name: GetWalletBalance :one
 SELECT COALESCE(SUM(amount), 0)::BIGINT as balance
 FROM ledger_entries
@@ -103,3 +190,14 @@ func (q *Queries) GetWalletBalance(ctx context.Context, arg GetWalletBalancePara
 	err := row.Scan(&balance)
 	return balance, err
 }
+
+const lockFeeRevenueAccount = `-- name: LockFeeRevenueAccount :exec
+SELECT pg_advisory_xact_lock(hashtext('fee_revenue:' || $1::text))
+`
+
+// The fee revenue account has no wallet row to lock, so postings to it are
+// serialized per currency with a transaction-scoped advisory lock.
+func (q *Queries) LockFeeRevenueAccount(ctx context.Context, currency string) error {
+	_, err := q.db.ExecContext(ctx, lockFeeRevenueAccount, currency)
+	return err
+}
@@ -10,17 +10,111 @@ import (
 	"time"
 )
 
+type AuditChain struct {
+	Sequence int64  `db:"sequence" json:"sequence"`
+	EventID  string `db:"event_id" json:"event_id"`
+	PrevHash string `db:"prev_hash" json:"prev_hash"`
+	Hash     string `db:"hash" json:"hash"`
+}
+
+type AuditChainHead struct {
+	ID       int32  `db:"id" json:"id"`
+	Sequence int64  `db:"sequence" json:"sequence"`
+	Hash     string `db:"hash" json:"hash"`
+}
+
+type AuditEvent struct {
+	ID         string          `db:"id" json:"id"`
+	ActorID    string          `db:"actor_id" json:"actor_id"`
+	ActorType  string          `db:"actor_type" json:"actor_type"`
+	Action     string          `db:"action" json:"action"`
+	EntityType string          `db:"entity_type" json:"entity_type"`
+	EntityID   string          `db:"entity_id" json:"entity_id"`
+	Before     json.RawMessage `db:"before" json:"before"`
+	After      json.RawMessage `db:"after" json:"after"`
+	IpAddress  sql.NullString  `db:"ip_address" json:"ip_address"`
+	TraceID    sql.NullString  `db:"trace_id" json:"trace_id"`
+	Digest     string          `db:"digest" json:"digest"`
+	CreatedAt  time.Time       `db:"created_at" json:"created_at"`
+}
+
+type AuditLog struct {
+	Sequence   int64           `db:"sequence" json:"sequence"`
+	ID         string          `db:"id" json:"id"`
+	ActorID    string          `db:"actor_id" json:"actor_id"`
+	ActorType  string          `db:"actor_type" json:"actor_type"`
+	Action     string          `db:"action" json:"action"`
+	EntityType string          `db:"entity_type" json:"entity_type"`
+	EntityID   string          `db:"entity_id" json:"entity_id"`
+	Before     json.RawMessage `db:"before" json:"before"`
+	After      json.RawMessage `db:"after" json:"after"`
+	IpAddress  sql.NullString  `db:"ip_address" json:"ip_address"`
+	TraceID    sql.NullString  `db:"trace_id" json:"trace_id"`
+	Digest     string          `db:"digest" json:"digest"`
+	PrevHash   string          `db:"prev_hash" json:"prev_hash"`
+	Hash       string          `db:"hash" json:"hash"`
+	CreatedAt  time.Time       `db:"created_at" json:"created_at"`
+}
+
+type Bank struct {
+	Code              string         `db:"code" json:"code"`
+	Name              string         `db:"name" json:"name"`
+	Country           string         `db:"country" json:"country"`
+	Currencies        []string       `db:"currencies" json:"currencies"`
+	PreferredProvider sql.NullString `db:"preferred_provider" json:"preferred_provider"`
+	Active            bool           `db:"active" json:"active"`
+	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time      `db:"updated_at" json:"updated_at"`
+}
+
 type BankAccount struct {
-	ID            string         `db:"id" json:"id"`
-	UserID        string         `db:"user_id" json:"user_id"`
-	BankName      string         `db:"bank_name" json:"bank_name"`
-	BankCode      string         `db:"bank_code" json:"bank_code"`
-	AccountNumber string         `db:"account_number" json:"account_number"`
-	AccountName   sql.NullString `db:"account_name" json:"account_name"`
-	Currency      string         `db:"currency" json:"currency"`
-	Provider      string         `db:"provider" json:"provider"`
-	CreatedAt     time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt     time.Time      `db:"updated_at" json:"updated_at"`
+	ID                string         `db:"id" json:"id"`
+	UserID            string         `db:"user_id" json:"user_id"`
+	BankName          string         `db:"bank_name" json:"bank_name"`
+	BankCode          string         `db:"bank_code" json:"bank_code"`
+	AccountNumber     string         `db:"account_number" json:"account_number"`
+	AccountName       sql.NullString `db:"account_name" json:"account_name"`
+	Currency          string         `db:"currency" json:"currency"`
+	Provider          string         `db:"provider" json:"provider"`
+	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time      `db:"updated_at" json:"updated_at"`
+	AccountNumberHash sql.NullString `db:"account_number_hash" json:"account_number_hash"`
+}
+
+type Beneficiary struct {
+	ID                string         `db:"id" json:"id"`
+	UserID            string         `db:"user_id" json:"user_id"`
+	Nickname          sql.NullString `db:"nickname" json:"nickname"`
+	AccountNumber     string         `db:"account_number" json:"account_number"`
+	AccountNumberHash sql.NullString `db:"account_number_hash" json:"account_number_hash"`
+	BankCode          string         `db:"bank_code" json:"bank_code"`
+	AccountName       string         `db:"account_name" json:"account_name"`
+	Currency          string         `db:"currency" json:"currency"`
+	Country           sql.NullString `db:"country" json:"country"`
+	IsInternal        bool           `db:"is_internal" json:"is_internal"`
+	VerifiedAt        time.Time      `db:"verified_at" json:"verified_at"`
+	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type FeeRule struct {
+	ID                  string          `db:"id" json:"id"`
+	Name                string          `db:"name" json:"name"`
+	TransactionType     string          `db:"transaction_type" json:"transaction_type"`
+	SourceCurrency      sql.NullString  `db:"source_currency" json:"source_currency"`
+	DestinationCurrency sql.NullString  `db:"destination_currency" json:"destination_currency"`
+	MinAmount           int64           `db:"min_amount" json:"min_amount"`
+	MaxAmount           sql.NullInt64   `db:"max_amount" json:"max_amount"`
+	FeeType             string          `db:"fee_type" json:"fee_type"`
+	FlatAmount          int64           `db:"flat_amount" json:"flat_amount"`
+	PercentageBps       int32           `db:"percentage_bps" json:"percentage_bps"`
+	Tiers               json.RawMessage `db:"tiers" json:"tiers"`
+	MinFee              sql.NullInt64   `db:"min_fee" json:"min_fee"`
+	MaxFee              sql.NullInt64   `db:"max_fee" json:"max_fee"`
+	Priority            int32           `db:"priority" json:"priority"`
+	Active              bool            `db:"active" json:"active"`
+	CreatedAt           time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt           time.Time       `db:"updated_at" json:"updated_at"`
 }
 
 type IdempotencyKey struct {
@@ -29,6 +123,21 @@ type IdempotencyKey struct {
 	CreatedAt     time.Time `db:"created_at" json:"created_at"`
 }
 
+type KycDocument struct {
+	ID             string         `db:"id" json:"id"`
+	UserID         string         `db:"user_id" json:"user_id"`
+	Category       string         `db:"category" json:"category"`
+	DocumentType   string         `db:"document_type" json:"document_type"`
+	DocumentNumber string         `db:"document_number" json:"document_number"`
+	IssuingCountry string         `db:"issuing_country" json:"issuing_country"`
+	Status         string         `db:"status" json:"status"`
+	ReviewerID     sql.NullString `db:"reviewer_id" json:"reviewer_id"`
+	ReviewNote     sql.NullString `db:"review_note" json:"review_note"`
+	ReviewedAt     sql.NullTime   `db:"reviewed_at" json:"reviewed_at"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
+}
+
 type LedgerEntry struct {
 	ID            string         `db:"id" json:"id"`
 	WalletID      sql.NullString `db:"wallet_id" json:"wallet_id"`
@@ -51,46 +160,206 @@ type Outbox struct {
 	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
 }
 
+type PaymentRequest struct {
+	ID            string         `db:"id" json:"id"`
+	RequesterID   string         `db:"requester_id" json:"requester_id"`
+	WalletID      string         `db:"wallet_id" json:"wallet_id"`
+	Amount        int64          `db:"amount" json:"amount"`
+	Currency      string         `db:"currency" json:"currency"`
+	Memo          sql.NullString `db:"memo" json:"memo"`
+	Token         string         `db:"token" json:"token"`
+	Status        string         `db:"status" json:"status"`
+	ExpiresAt     time.Time      `db:"expires_at" json:"expires_at"`
+	TransactionID sql.NullString `db:"transaction_id" json:"transaction_id"`
+	PaidBy        sql.NullString `db:"paid_by" json:"paid_by"`
+	PaidAt        sql.NullTime   `db:"paid_at" json:"paid_at"`
+	CreatedAt     time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type PinAttempt struct {
+	UserID         string       `db:"user_id" json:"user_id"`
+	FailedAttempts int32        `db:"failed_attempts" json:"failed_attempts"`
+	LockoutCount   int32        `db:"lockout_count" json:"lockout_count"`
+	LockedUntil    sql.NullTime `db:"locked_until" json:"locked_until"`
+	UpdatedAt      time.Time    `db:"updated_at" json:"updated_at"`
+}
+
+type PinHistory struct {
+	ID        string    `db:"id" json:"id"`
+	UserID    string    `db:"user_id" json:"user_id"`
+	PinHash   string    `db:"pin_hash" json:"pin_hash"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type PinResetToken struct {
+	ID        string       `db:"id" json:"id"`
+	UserID    string       `db:"user_id" json:"user_id"`
+	TokenHash string       `db:"token_hash" json:"token_hash"`
+	CreatedBy string       `db:"created_by" json:"created_by"`
+	ExpiresAt time.Time    `db:"expires_at" json:"expires_at"`
+	UsedAt    sql.NullTime `db:"used_at" json:"used_at"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
+}
+
 type ProcessedJob struct {
 	JobID       string    `db:"job_id" json:"job_id"`
 	ProcessedAt time.Time `db:"processed_at" json:"processed_at"`
 	ExpiresAt   time.Time `db:"expires_at" json:"expires_at"`
 }
 
+type RateLimitBucket struct {
+	Key       string    `db:"key" json:"key"`
+	Tokens    float64   `db:"tokens" json:"tokens"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type Refund struct {
+	ID                  string    `db:"id" json:"id"`
+	TransactionID       string    `db:"transaction_id" json:"transaction_id"`
+	RefundTransactionID string    `db:"refund_transaction_id" json:"refund_transaction_id"`
+	Amount              int64     `db:"amount" json:"amount"`
+	Currency            string    `db:"currency" json:"currency"`
+	CreditedAmount      int64     `db:"credited_amount" json:"credited_amount"`
+	CreditedCurrency    string    `db:"credited_currency" json:"credited_currency"`
+	Reason              string    `db:"reason" json:"reason"`
+	IdempotencyKey      string    `db:"idempotency_key" json:"idempotency_key"`
+	CreatedBy           string    `db:"created_by" json:"created_by"`
+	CreatedAt           time.Time `db:"created_at" json:"created_at"`
+}
+
+type RiskAssessment struct {
+	ID             string          `db:"id" json:"id"`
+	TransactionID  string          `db:"transaction_id" json:"transaction_id"`
+	UserID         string          `db:"user_id" json:"user_id"`
+	Recipient      string          `db:"recipient" json:"recipient"`
+	Decision       string          `db:"decision" json:"decision"`
+	Score          int32           `db:"score" json:"score"`
+	Reasons        json.RawMessage `db:"reasons" json:"reasons"`
+	Features       json.RawMessage `db:"features" json:"features"`
+	ReviewDecision sql.NullString  `db:"review_decision" json:"review_decision"`
+	ReviewedBy     sql.NullString  `db:"reviewed_by" json:"reviewed_by"`
+	ReviewNote     sql.NullString  `db:"review_note" json:"review_note"`
+	ReviewedAt     sql.NullTime    `db:"reviewed_at" json:"reviewed_at"`
+	CreatedAt      time.Time       `db:"created_at" json:"created_at"`
+}
+
+type TotpRecoveryCode struct {
+	ID        string       `db:"id" json:"id"`
+	UserID    string       `db:"user_id" json:"user_id"`
+	CodeHash  string       `db:"code_hash" json:"code_hash"`
+	UsedAt    sql.NullTime `db:"used_at" json:"used_at"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
+}
+
 type Transaction struct {
-	ID                string         `db:"id" json:"id"`
-	IdempotencyKey    string         `db:"idempotency_key" json:"idempotency_key"`
-	TraceID           sql.NullString `db:"trace_id" json:"trace_id"`
-	FromWalletID      sql.NullString `db:"from_wallet_id" json:"from_wallet_id"`
-	ToWalletID        sql.NullString `db:"to_wallet_id" json:"to_wallet_id"`
-	Type              string         `db:"type" json:"type"`
-	Amount            int64          `db:"amount" json:"amount"`
-	Currency          string         `db:"currency" json:"currency"`
-	Status            string         `db:"status" json:"status"`
-	ProviderName      sql.NullString `db:"provider_name" json:"provider_name"`
-	ProviderReference sql.NullString `db:"provider_reference" json:"provider_reference"`
-	ExchangeRate      sql.NullString `db:"exchange_rate" json:"exchange_rate"`
-	FailureReason     sql.NullString `db:"failure_reason" json:"failure_reason"`
-	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt         time.Time      `db:"updated_at" json:"updated_at"`
+	ID                   string         `db:"id" json:"id"`
+	IdempotencyKey       string         `db:"idempotency_key" json:"idempotency_key"`
+	TraceID              sql.NullString `db:"trace_id" json:"trace_id"`
+	FromWalletID         sql.NullString `db:"from_wallet_id" json:"from_wallet_id"`
+	ToWalletID           sql.NullString `db:"to_wallet_id" json:"to_wallet_id"`
+	Type                 string         `db:"type" json:"type"`
+	Amount               int64          `db:"amount" json:"amount"`
+	Currency             string         `db:"currency" json:"currency"`
+	Status               string         `db:"status" json:"status"`
+	ProviderName         sql.NullString `db:"provider_name" json:"provider_name"`
+	ProviderReference    sql.NullString `db:"provider_reference" json:"provider_reference"`
+	ExchangeRate         sql.NullString `db:"exchange_rate" json:"exchange_rate"`
+	FailureReason        sql.NullString `db:"failure_reason" json:"failure_reason"`
+	CreatedAt            time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt            time.Time      `db:"updated_at" json:"updated_at"`
+	FeeAmount            int64          `db:"fee_amount" json:"fee_amount"`
+	FeeCurrency          sql.NullString `db:"fee_currency" json:"fee_currency"`
+	ParentTransactionID  sql.NullString `db:"parent_transaction_id" json:"parent_transaction_id"`
+	ScreeningResult      sql.NullString `db:"screening_result" json:"screening_result"`
+	ScreeningListVersion sql.NullString `db:"screening_list_version" json:"screening_list_version"`
+	ScreenedName         sql.NullString `db:"screened_name" json:"screened_name"`
+	BeneficiaryID        sql.NullString `db:"beneficiary_id" json:"beneficiary_id"`
+	PaymentRequestID     sql.NullString `db:"payment_request_id" json:"payment_request_id"`
 }
 
-type User struct {
-	UserID    string         `db:"user_id" json:"user_id"`
-	Name      sql.NullString `db:"name" json:"name"`
-	PinHash   sql.NullString `db:"pin_hash" json:"pin_hash"`
-	CreatedAt time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+type TransactionStatusHistory struct {
+	ID            string         `db:"id" json:"id"`
+	TransactionID string         `db:"transaction_id" json:"transaction_id"`
+	FromStatus    string         `db:"from_status" json:"from_status"`
+	ToStatus      string         `db:"to_status" json:"to_status"`
+	Reason        sql.NullString `db:"reason" json:"reason"`
+	CreatedAt     time.Time      `db:"created_at" json:"created_at"`
 }
 
-type Wallet struct {
+type TransferLimit struct {
+	ID                string        `db:"id" json:"id"`
+	Tier              string        `db:"tier" json:"tier"`
+	Currency          string        `db:"currency" json:"currency"`
+	TransactionType   string        `db:"transaction_type" json:"transaction_type"`
+	PerTransactionMax sql.NullInt64 `db:"per_transaction_max" json:"per_transaction_max"`
+	DailyAmountMax    sql.NullInt64 `db:"daily_amount_max" json:"daily_amount_max"`
+	DailyCountMax     sql.NullInt32 `db:"daily_count_max" json:"daily_count_max"`
+	MonthlyAmountMax  sql.NullInt64 `db:"monthly_amount_max" json:"monthly_amount_max"`
+	MonthlyCountMax   sql.NullInt32 `db:"monthly_count_max" json:"monthly_count_max"`
+	Active            bool          `db:"active" json:"active"`
+	CreatedAt         time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+type TransferRecipient struct {
 	ID            string         `db:"id" json:"id"`
-	UserID        string         `db:"user_id" json:"user_id"`
-	BankAccountID sql.NullString `db:"bank_account_id" json:"bank_account_id"`
+	TransactionID string         `db:"transaction_id" json:"transaction_id"`
+	AccountNumber string         `db:"account_number" json:"account_number"`
+	BankCode      string         `db:"bank_code" json:"bank_code"`
+	AccountName   sql.NullString `db:"account_name" json:"account_name"`
+	Country       sql.NullString `db:"country" json:"country"`
 	Currency      string         `db:"currency" json:"currency"`
-	Balance       int64          `db:"balance" json:"balance"`
 	CreatedAt     time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt     time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type User struct {
+	UserID      string         `db:"user_id" json:"user_id"`
+	Name        sql.NullString `db:"name" json:"name"`
+	PinHash     sql.NullString `db:"pin_hash" json:"pin_hash"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+	Tier        string         `db:"tier" json:"tier"`
+	Email       sql.NullString `db:"email" json:"email"`
+	Phone       sql.NullString `db:"phone" json:"phone"`
+	Country     sql.NullString `db:"country" json:"country"`
+	DateOfBirth sql.NullTime   `db:"date_of_birth" json:"date_of_birth"`
+	KycTier     int16          `db:"kyc_tier" json:"kyc_tier"`
+}
+
+type UserTotp struct {
+	UserID       string       `db:"user_id" json:"user_id"`
+	Secret       string       `db:"secret" json:"secret"`
+	ConfirmedAt  sql.NullTime `db:"confirmed_at" json:"confirmed_at"`
+	LastUsedStep int64        `db:"last_used_step" json:"last_used_step"`
+	CreatedAt    time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time    `db:"updated_at" json:"updated_at"`
+}
+
+type Wallet struct {
+	ID              string         `db:"id" json:"id"`
+	UserID          string         `db:"user_id" json:"user_id"`
+	BankAccountID   sql.NullString `db:"bank_account_id" json:"bank_account_id"`
+	Currency        string         `db:"currency" json:"currency"`
+	Balance         int64          `db:"balance" json:"balance"`
+	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
+	HeldBalance     int64          `db:"held_balance" json:"held_balance"`
+	Status          string         `db:"status" json:"status"`
+	FrozenDirection sql.NullString `db:"frozen_direction" json:"frozen_direction"`
+	StatusReason    sql.NullString `db:"status_reason" json:"status_reason"`
+	ClosedAt        sql.NullTime   `db:"closed_at" json:"closed_at"`
+}
+
+type WalletHold struct {
+	ID            string       `db:"id" json:"id"`
+	WalletID      string       `db:"wallet_id" json:"wallet_id"`
+	TransactionID string       `db:"transaction_id" json:"transaction_id"`
+	Amount        int64        `db:"amount" json:"amount"`
+	Currency      string       `db:"currency" json:"currency"`
+	Status        string       `db:"status" json:"status"`
+	CreatedAt     time.Time    `db:"created_at" json:"created_at"`
+	ReleasedAt    sql.NullTime `db:"released_at" json:"released_at"`
 }
 
 type WebhookEvent struct {
@@ -7,23 +7,93 @@ package gen
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 type Querier interface {
+	AdjustWalletHeldBalance(ctx context.Context, arg AdjustWalletHeldBalanceParams) error
+	// Counts an attempt before the PIN or code is checked, so parallel requests
+	// cannot all get past the lockout check. Returns no row while a lockout is in
+	// force or once max_attempts attempts are already counted. Attempts that
+	// reached max_attempts without a lockout were never resolved; once the last
+	// was claimed before stale_before they are dropped and counting starts over.
+	ClaimPINAttempt(ctx context.Context, arg ClaimPINAttemptParams) (PinAttempt, error)
 	CleanupExpiredJobs(ctx context.Context) error
+	// Resets the counters after a correct PIN or code, unless a lockout started
+	// while it was being checked.
+	ClearPINAttempts(ctx context.Context, userID string) (int64, error)
+	ConfirmUserTOTP(ctx context.Context, arg ConfirmUserTOTPParams) (int64, error)
+	ConsumePINResetToken(ctx context.Context, tokenHash string) (PinResetToken, error)
+	CountRecipientTransfers(ctx context.Context, arg CountRecipientTransfersParams) (int64, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) error
+	CreateBankAccount(ctx context.Context, arg CreateBankAccountParams) (BankAccount, error)
+	CreateBeneficiary(ctx context.Context, arg CreateBeneficiaryParams) (Beneficiary, error)
+	// A deposit is written in its final state: completed when the wallet is
+	// credited, or failed when the wallet cannot accept it.
+	CreateDepositTransaction(ctx context.Context, arg CreateDepositTransactionParams) (Transaction, error)
 	CreateExternalSystemCreditEntry(ctx context.Context, arg CreateExternalSystemCreditEntryParams) (LedgerEntry, error)
+	CreateExternalSystemDebitEntry(ctx context.Context, arg CreateExternalSystemDebitEntryParams) (LedgerEntry, error)
+	CreateFeeRevenueEntry(ctx context.Context, arg CreateFeeRevenueEntryParams) (LedgerEntry, error)
 	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	CreateKYCDocument(ctx context.Context, arg CreateKYCDocumentParams) (KycDocument, error)
 	CreateLedgerEntry(ctx context.Context, arg CreateLedgerEntryParams) (LedgerEntry, error)
 	CreateOutboxEntry(ctx context.Context, arg CreateOutboxEntryParams) (Outbox, error)
+	CreatePINHistory(ctx context.Context, arg CreatePINHistoryParams) error
+	CreatePINResetToken(ctx context.Context, arg CreatePINResetTokenParams) (PinResetToken, error)
+	CreatePaymentRequest(ctx context.Context, arg CreatePaymentRequestParams) (PaymentRequest, error)
+	CreateRefund(ctx context.Context, arg CreateRefundParams) (Refund, error)
+	CreateRiskAssessment(ctx context.Context, arg CreateRiskAssessmentParams) (RiskAssessment, error)
+	CreateTOTPRecoveryCode(ctx context.Context, arg CreateTOTPRecoveryCodeParams) error
 	CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error)
+	CreateTransactionStatusHistory(ctx context.Context, arg CreateTransactionStatusHistoryParams) error
+	CreateTransferRecipient(ctx context.Context, arg CreateTransferRecipientParams) (TransferRecipient, error)
+	// New users start at KYC tier 0 on the default transfer limit tier.
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateWallet(ctx context.Context, arg CreateWalletParams) (Wallet, error)
+	CreateWalletHold(ctx context.Context, arg CreateWalletHoldParams) (WalletHold, error)
 	CreateWebhookEvent(ctx context.Context, arg CreateWebhookEventParams) (WebhookEvent, error)
+	// Banks missing from the latest import are kept for history but no longer
+	// accepted.
+	DeactivateBanksNotIn(ctx context.Context, codes []string) (int64, error)
+	DeleteBeneficiary(ctx context.Context, arg DeleteBeneficiaryParams) (int64, error)
+	DeleteStaleRateLimitBuckets(ctx context.Context, updatedAt time.Time) error
+	DeleteTOTPRecoveryCodes(ctx context.Context, userID string) error
+	DeleteUserTOTP(ctx context.Context, userID string) error
+	ExpirePaymentRequests(ctx context.Context, batchSize int32) ([]ExpirePaymentRequestsRow, error)
+	GetAuditChainHead(ctx context.Context) (AuditChainHead, error)
+	GetBank(ctx context.Context, code string) (Bank, error)
+	// Rows written before encryption was enabled have no blind index yet and are
+	// matched on the plaintext number until the re-encryption worker reaches them.
 	GetBankAccountByAccountAndBankCode(ctx context.Context, arg GetBankAccountByAccountAndBankCodeParams) (BankAccount, error)
 	GetBankAccountByID(ctx context.Context, id string) (BankAccount, error)
+	// Matches a user's saved account the same way as
+	// GetBankAccountByAccountAndBankCode, falling back to the plaintext number for
+	// rows without a blind index.
+	GetBeneficiaryByAccount(ctx context.Context, arg GetBeneficiaryByAccountParams) (Beneficiary, error)
+	GetBeneficiaryForUser(ctx context.Context, arg GetBeneficiaryForUserParams) (Beneficiary, error)
+	// Used by risk checks, which look the beneficiary up by ID alone because the
+	// transaction already ties it to the sender.
+	GetBeneficiaryVerifiedAt(ctx context.Context, id string) (time.Time, error)
+	GetDepositByProviderReference(ctx context.Context, arg GetDepositByProviderReferenceParams) (Transaction, error)
+	GetFeeRevenueBalance(ctx context.Context, currency string) (int64, error)
+	GetKYCDocumentByIDForUpdate(ctx context.Context, id string) (KycDocument, error)
+	GetLatestRiskAssessment(ctx context.Context, transactionID string) (RiskAssessment, error)
+	GetPINAttempts(ctx context.Context, userID string) (PinAttempt, error)
+	GetPaymentRequestByIDForUpdate(ctx context.Context, id string) (PaymentRequest, error)
+	GetPaymentRequestByToken(ctx context.Context, token string) (PaymentRequest, error)
+	GetRateLimitTokens(ctx context.Context, arg GetRateLimitTokensParams) (float64, error)
+	GetRefundByIdempotencyKey(ctx context.Context, idempotencyKey string) (Refund, error)
+	GetRefundTotals(ctx context.Context, transactionID string) (GetRefundTotalsRow, error)
+	GetRiskTransferHistory(ctx context.Context, arg GetRiskTransferHistoryParams) (GetRiskTransferHistoryRow, error)
 	GetTransactionByID(ctx context.Context, id string) (Transaction, error)
+	GetTransactionByIDForUpdate(ctx context.Context, id string) (Transaction, error)
 	GetTransactionByIdempotencyKey(ctx context.Context, idempotencyKey string) (Transaction, error)
+	GetTransferLimit(ctx context.Context, arg GetTransferLimitParams) (TransferLimit, error)
+	GetTransferRecipientByTransactionID(ctx context.Context, transactionID string) (TransferRecipient, error)
+	GetTransferUsage(ctx context.Context, arg GetTransferUsageParams) (GetTransferUsageRow, error)
 	GetUnprocessedOutboxEntries(ctx context.Context, limit int32) ([]Outbox, error)
 	GetUserByID(ctx context.Context, userID string) (User, error)
+	GetUserTOTP(ctx context.Context, userID string) (UserTotp, error)
 	GetUserWalletsWithBankAccounts(ctx context.Context, userID string) ([]GetUserWalletsWithBankAccountsRow, error)
 	GetWalletBalance(ctx context.Context, arg GetWalletBalanceParams) (int64, error)
 	GetWalletByBankAccount(ctx context.Context, bankAccountID sql.NullString) (Wallet, error)
@@ -33,13 +103,87 @@ type Querier interface {
 	GetWalletByUserAndCurrencyForUpdate(ctx context.Context, arg GetWalletByUserAndCurrencyForUpdateParams) (Wallet, error)
 	IncrementOutboxRetryCount(ctx context.Context, id string) error
 	IsJobProcessed(ctx context.Context, jobID string) (bool, error)
+	ListActiveFeeRules(ctx context.Context, transactionType string) ([]FeeRule, error)
+	ListActiveTransferLimits(ctx context.Context, tier string) ([]TransferLimit, error)
+	ListApprovedKYCCategories(ctx context.Context, userID string) ([]string, error)
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditLog, error)
+	ListAuditEventsFromSequence(ctx context.Context, arg ListAuditEventsFromSequenceParams) ([]AuditLog, error)
+	ListBankAccountsToEncrypt(ctx context.Context, arg ListBankAccountsToEncryptParams) ([]ListBankAccountsToEncryptRow, error)
+	// search matches the bank code exactly or any part of the name, ignoring case.
+	ListBanks(ctx context.Context, arg ListBanksParams) ([]Bank, error)
+	ListBeneficiariesByUser(ctx context.Context, userID string) ([]Beneficiary, error)
+	ListBeneficiariesToEncrypt(ctx context.Context, arg ListBeneficiariesToEncryptParams) ([]ListBeneficiariesToEncryptRow, error)
+	// The review queue, oldest first.
+	ListKYCDocumentsByStatus(ctx context.Context, arg ListKYCDocumentsByStatusParams) ([]KycDocument, error)
+	ListKYCDocumentsByUser(ctx context.Context, userID string) ([]KycDocument, error)
+	ListKYCDocumentsToEncrypt(ctx context.Context, arg ListKYCDocumentsToEncryptParams) ([]ListKYCDocumentsToEncryptRow, error)
+	ListPaymentRequestsByRequester(ctx context.Context, arg ListPaymentRequestsByRequesterParams) ([]PaymentRequest, error)
+	ListPendingRiskReviews(ctx context.Context, limit int32) ([]RiskAssessment, error)
+	ListRecentPINHashes(ctx context.Context, arg ListRecentPINHashesParams) ([]string, error)
+	ListRefundsByTransaction(ctx context.Context, transactionID string) ([]Refund, error)
+	// Bank account recipients still holding the account number rather than its
+	// 64-character blind index.
+	ListRiskRecipientsToIndex(ctx context.Context, arg ListRiskRecipientsToIndexParams) ([]ListRiskRecipientsToIndexRow, error)
+	ListStaleInitiatedTransactionIDs(ctx context.Context, arg ListStaleInitiatedTransactionIDsParams) ([]string, error)
+	// Deposits into the user's wallets are included; they have no source wallet.
 	ListTransactionsByUser(ctx context.Context, arg ListTransactionsByUserParams) ([]Transaction, error)
+	// External transfers keep the recipient details in provider_reference until
+	// the payout is submitted. Plaintext details are JSON objects; a provider's
+	// own reference is neither JSON nor encrypted and is left alone.
+	ListTransactionsToEncrypt(ctx context.Context, arg ListTransactionsToEncryptParams) ([]ListTransactionsToEncryptRow, error)
+	ListTransferRecipientsByTransactionIDs(ctx context.Context, transactionIds []string) ([]TransferRecipient, error)
+	ListTransferRecipientsToEncrypt(ctx context.Context, arg ListTransferRecipientsToEncryptParams) ([]ListTransferRecipientsToEncryptRow, error)
+	// Encrypted payloads are stored as a JSON string holding the envelope;
+	// plaintext payloads are the provider's original JSON object.
+	ListWebhookEventsToEncrypt(ctx context.Context, arg ListWebhookEventsToEncryptParams) ([]ListWebhookEventsToEncryptRow, error)
+	// The fee revenue account has no wallet row to lock, so postings to it are
+	// serialized per currency with a transaction-scoped advisory lock.
+	LockFeeRevenueAccount(ctx context.Context, currency string) error
+	LockPIN(ctx context.Context, arg LockPINParams) error
 	MarkJobProcessed(ctx context.Context, arg MarkJobProcessedParams) (ProcessedJob, error)
 	MarkOutboxEntryProcessed(ctx context.Context, id string) error
+	// Only a pending request that has not run out can be paid, so a request is
+	// never paid twice.
+	MarkPaymentRequestPaid(ctx context.Context, arg MarkPaymentRequestPaidParams) (int64, error)
+	NextVirtualAccountNumber(ctx context.Context) (int64, error)
+	RecordRiskReview(ctx context.Context, arg RecordRiskReviewParams) (int64, error)
+	ReleaseWalletHold(ctx context.Context, arg ReleaseWalletHoldParams) (WalletHold, error)
+	ResetPINAttempts(ctx context.Context, userID string) error
+	ReviewKYCDocument(ctx context.Context, arg ReviewKYCDocumentParams) (KycDocument, error)
+	RevokePINResetTokens(ctx context.Context, userID string) error
+	SetInitialUserPIN(ctx context.Context, arg SetInitialUserPINParams) (int64, error)
+	// Refills the bucket for the time since its last update and takes one token.
+	// Returns no row when less than one token is available.
+	TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (float64, error)
+	TransitionPaymentRequestStatus(ctx context.Context, arg TransitionPaymentRequestStatusParams) (int64, error)
+	TransitionTransactionStatus(ctx context.Context, arg TransitionTransactionStatusParams) (int64, error)
+	UpdateBankAccountNumberEncryption(ctx context.Context, arg UpdateBankAccountNumberEncryptionParams) (int64, error)
+	// verified_at is the caller's to set: it only moves when the account details
+	// changed and were verified again.
+	UpdateBeneficiary(ctx context.Context, arg UpdateBeneficiaryParams) (Beneficiary, error)
+	UpdateBeneficiaryAccountNumberEncryption(ctx context.Context, arg UpdateBeneficiaryAccountNumberEncryptionParams) (int64, error)
+	UpdateKYCDocumentNumberEncryption(ctx context.Context, arg UpdateKYCDocumentNumberEncryptionParams) (int64, error)
+	UpdateRiskAssessmentRecipient(ctx context.Context, arg UpdateRiskAssessmentRecipientParams) (int64, error)
 	UpdateTransactionFailure(ctx context.Context, arg UpdateTransactionFailureParams) error
+	UpdateTransactionProviderReferenceEncryption(ctx context.Context, arg UpdateTransactionProviderReferenceEncryptionParams) (int64, error)
+	UpdateTransactionScreening(ctx context.Context, arg UpdateTransactionScreeningParams) error
 	UpdateTransactionStatus(ctx context.Context, arg UpdateTransactionStatusParams) error
 	UpdateTransactionWithProvider(ctx context.Context, arg UpdateTransactionWithProviderParams) error
+	UpdateTransferRecipientAccountNumberEncryption(ctx context.Context, arg UpdateTransferRecipientAccountNumberEncryptionParams) (int64, error)
+	// Only ever raises the KYC tier, so a late approval cannot undo a higher tier
+	// granted in the meantime.
+	UpdateUserKYCTier(ctx context.Context, arg UpdateUserKYCTierParams) (int64, error)
+	UpdateUserPIN(ctx context.Context, arg UpdateUserPINParams) error
+	// Fields left null keep their current value.
+	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
 	UpdateWalletBalance(ctx context.Context, arg UpdateWalletBalanceParams) error
+	// closed_at is set when the wallet is closed and never cleared.
+	UpdateWalletStatus(ctx context.Context, arg UpdateWalletStatusParams) (Wallet, error)
+	UpdateWebhookEventPayloadEncryption(ctx context.Context, arg UpdateWebhookEventPayloadEncryptionParams) (int64, error)
+	UpsertBank(ctx context.Context, arg UpsertBankParams) error
+	UpsertPendingUserTOTP(ctx context.Context, arg UpsertPendingUserTOTPParams) (UserTotp, error)
+	UseTOTPRecoveryCode(ctx context.Context, arg UseTOTPRecoveryCodeParams) (int64, error)
+	UseTOTPStep(ctx context.Context, arg UseTOTPStepParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -11,24 +11,94 @@ import (
 	"time"
 )
 
+const createDepositTransaction = `-- name: CreateDepositTransaction :one
+INSERT INTO transactions (
+    id, idempotency_key, trace_id, to_wallet_id, type, amount, currency, status,
+    provider_name, provider_reference, failure_reason
+)
+VALUES (gen_random_uuid()::text, $1, $2, $3, 'external', $4, $5, $6, $7, $8, $9)
+RETURNING id, idempotency_key, trace_id, from_wallet_id, to_wallet_id, type, amount, currency, status, provider_name, provider_reference, exchange_rate, failure_reason, created_at, updated_at, fee_amount, fee_currency, parent_transaction_id, screening_result, screening_list_version, screened_name, beneficiary_id, payment_request_id
+`
+
+type CreateDepositTransactionParams struct {
+	IdempotencyKey    string         `db:"idempotency_key" json:"idempotency_key"`
+	TraceID           sql.NullString `db:"trace_id" json:"trace_id"`
+	ToWalletID        sql.NullString `db:"to_wallet_id" json:"to_wallet_id"`
+	Amount            int64          `db:"amount" json:"amount"`
+	Currency          string         `db:"currency" json:"currency"`
+	Status            string         `db:"status" json:"status"`
+	ProviderName      sql.NullString `db:"provider_name" json:"provider_name"`
+	ProviderReference sql.NullString `db:"provider_reference" json:"provider_reference"`
+	FailureReason     sql.NullString `db:"failure_reason" json:"failure_reason"`
+}
+
+// A deposit is written in its final state: completed when the wallet is
+// credited, or failed when the wallet cannot accept it.
+func (q *Queries) CreateDepositTransaction(ctx context.Context, arg CreateDepositTransactionParams) (Transaction, error) {
+	row := q.db.QueryRowContext(ctx, createDepositTransaction,
+		arg.IdempotencyKey,
+		arg.TraceID,
+		arg.ToWalletID,
+		arg.Amount,
+		arg.Currency,
+		arg.Status,
+		arg.ProviderName,
+		arg.ProviderReference,
+		arg.FailureReason,
+	)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.IdempotencyKey,
+		&i.TraceID,
+		&i.FromWalletID,
+		&i.ToWalletID,
+		&i.Type,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.ProviderName,
+		&i.ProviderReference,
+		&i.ExchangeRate,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FeeAmount,
+		&i.FeeCurrency,
+		&i.ParentTransactionID,
+		&i.ScreeningResult,
+		&i.ScreeningListVersion,
+		&i.ScreenedName,
+		&i.BeneficiaryID,
+		&i.PaymentRequestID,
+	)
+	return i, err
+}
+
 const createTransaction = `-- name: CreateTransaction :one
 INSERT INTO transactions (
-    id, idempotency_key, trace_id, from_wallet_id, to_wallet_id, type, amount, currency, status, exchange_rate
+    id, idempotency_key, trace_id, from_wallet_id, to_wallet_id, type, amount, currency, status, exchange_rate,
+    fee_amount, fee_currency, parent_transaction_id, beneficiary_id, payment_request_id
 )
-VALUES (gen_random_uuid()::text, $1, $2, $3, $4, $5, $6, $7, $8, $9)
-RETURNING id, idempotency_key, trace_id, from_wallet_id, to_wallet_id, type, amount, currency, status, provider_name, provider_reference, exchange_rate, failure_reason, created_at, updated_at
+VALUES (gen_random_uuid()::text, $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+RETURNING id, idempotency_key, trace_id, from_wallet_id, to_wallet_id, type, amount, currency, status, provider_name, provider_reference, exchange_rate, failure_reason, created_at, updated_at, fee_amount, fee_currency, parent_transaction_id, screening_result, screening_list_version, screened_name, beneficiary_id, payment_request_id
 `
 
 type CreateTransactionParams struct {
-	IdempotencyKey string         `db:"idempotency_key" json:"idempotency_key"`
-	TraceID        sql.NullString `db:"trace_id" json:"trace_id"`
-	FromWalletID   sql.NullString `db:"from_wallet_id" json:"from_wallet_id"`
-	ToWalletID     sql.NullString `db:"to_wallet_id" json:"to_wallet_id"`
-	Type           string         `db:"type" json:"type"`
-	Amount         int64          `db:"amount" json:"amount"`
-	Currency       string         `db:"currency" json:"currency"`
-	Status         string         `db:"status" json:"status"`
-	ExchangeRate   sql.NullString `db:"exchange_rate" json:"exchange_rate"`
+	IdempotencyKey      string         `db:"idempotency_key" json:"idempotency_key"`
+	TraceID             sql.NullString `db:"trace_id" json:"trace_id"`
+	FromWalletID        sql.NullString `db:"from_wallet_id" json:"from_wallet_id"`
+	ToWalletID          sql.NullString `db:"to_wallet_id" json:"to_wallet_id"`
+	Type                string         `db:"type" json:"type"`
+	Amount              int64          `db:"amount" json:"amount"`
+	Currency            string         `db:"currency" json:"currency"`
+	Status              string         `db:"status" json:"status"`
+	ExchangeRate        sql.NullString `db:"exchange_rate" json:"exchange_rate"`
+	FeeAmount           int64          `db:"fee_amount" json:"fee_amount"`
+	FeeCurrency         sql.NullString `db:"fee_currency" json:"fee_currency"`
+	ParentTransactionID sql.NullString `db:"parent_transaction_id" json:"parent_transaction_id"`
+	BeneficiaryID       sql.NullString `db:"beneficiary_id" json:"beneficiary_id"`
+	PaymentRequestID    sql.NullString `db:"payment_request_id" json:"payment_request_id"`
 }
 
 func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error) {
@@ -42,7 +112,59 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		arg.Currency,
 		arg.Status,
 		arg.ExchangeRate,
+		arg.FeeAmount,
+		arg.FeeCurrency,
+		arg.ParentTransactionID,
+		arg.BeneficiaryID,
+		arg.PaymentRequestID,
+	)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.IdempotencyKey,
+		&i.TraceID,
+		&i.FromWalletID,
+		&i.ToWalletID,
+		&i.Type,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.ProviderName,
+		&i.ProviderReference,
+		&i.ExchangeRate,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FeeAmount,
+		&i.FeeCurrency,
+		&i.ParentTransactionID,
+		&i.ScreeningResult,
+		&i.ScreeningListVersion,
+		&i.ScreenedName,
+		&i.BeneficiaryID,
+		&i.PaymentRequestID,
 	)
+	return i, err
+}
+
+const getDepositByProviderReference = `-- name: GetDepositByProviderReference :one
+SELECT id, idempotency_key, trace_id, from_wallet_id, to_wallet_id, type, amount, currency,
+       status, provider_name, provider_reference, exchange_rate, failure_reason,
+       created_at, updated_at, fee_amount, fee_currency, parent_transaction_id,
+       screening_result, screening_list_version, screened_name, beneficiary_id,
+       payment_request_id
+FROM transactions
+WHERE provider_name = $1 AND provider_reference = $2
+  AND type = 'external' AND from_wallet_id IS NULL
+`
+
+type GetDepositByProviderReferenceParams struct {
+	ProviderName      sql.NullString `db:"provider_name" json:"provider_name"`
+	ProviderReference sql.NullString `db:"provider_reference" json:"provider_reference"`
+}
+
+func (q *Queries) GetDepositByProviderReference(ctx context.Context, arg GetDepositByProviderReferenceParams) (Transaction, error) {
+	row := q.db.QueryRowContext(ctx, getDepositByProviderReference, arg.ProviderName, arg.ProviderReference)
 	var i Transaction
 	err := row.Scan(
 		&i.ID,
@@ -60,6 +182,14 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		&i.FailureReason,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FeeAmount,
+		&i.FeeCurrency,
+		&i.ParentTransactionID,
+		&i.ScreeningResult,
+		&i.ScreeningListVersion,
+		&i.ScreenedName,
+		&i.BeneficiaryID,
+		&i.PaymentRequestID,
 	)
 	return i, err
 }
@@ -67,7 +197,9 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 const getTransactionByID = `-- name: GetTransactionByID :one
 SELECT id, idempotency_key, trace_id, from_wallet_id, to_wallet_id, type, amount, currency,
        status, provider_name, provider_reference, exchange_rate, failure_reason,
-       created_at, updated_at
+       created_at, updated_at, fee_amount, fee_currency, parent_transaction_id,
+       screening_result, screening_list_version, screened_name, beneficiary_id,
+       payment_request_id
 FROM transactions
 WHERE id = $1
 `
@@ -91,6 +223,56 @@ func (q *Queries) GetTransactionByID(ctx context.Context, id string) (Transactio
 		&i.FailureReason,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FeeAmount,
+		&i.FeeCurrency,
+		&i.ParentTransactionID,
+		&i.ScreeningResult,
+		&i.ScreeningListVersion,
+		&i.ScreenedName,
+		&i.BeneficiaryID,
+		&i.PaymentRequestID,
+	)
+	return i, err
+}
+
+const getTransactionByIDForUpdate = `-- name: GetTransactionByIDForUpdate :one
+SELECT id, idempotency_key, trace_id, from_wallet_id, to_wallet_id, type, amount, currency,
+       status, provider_name, provider_reference, exchange_rate, failure_reason,
+       created_at, updated_at, fee_amount, fee_currency, parent_transaction_id,
+       screening_result, screening_list_version, screened_name, beneficiary_id,
+       payment_request_id
+FROM transactions
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetTransactionByIDForUpdate(ctx context.Context, id string) (Transaction, error) {
+	row := q.db.QueryRowContext(ctx, getTransactionByIDForUpdate, id)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.IdempotencyKey,
+		&i.TraceID,
+		&i.FromWalletID,
+		&i.ToWalletID,
+		&i.Type,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.ProviderName,
+		&i.ProviderReference,
+		&i.ExchangeRate,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FeeAmount,
+		&i.FeeCurrency,
+		&i.ParentTransactionID,
+		&i.ScreeningResult,
+		&i.ScreeningListVersion,
+		&i.ScreenedName,
+		&i.BeneficiaryID,
+		&i.PaymentRequestID,
 	)
 	return i, err
 }
@@ -98,7 +280,9 @@ func (q *Queries) GetTransactionByID(ctx context.Context, id string) (Transactio
 const getTransactionByIdempotencyKey = `-- name: GetTransactionByIdempotencyKey :one
 SELECT id, idempotency_key, trace_id, from_wallet_id, to_wallet_id, type, amount, currency,
        status, provider_name, provider_reference, exchange_rate, failure_reason,
-       created_at, updated_at
+       created_at, updated_at, fee_amount, fee_currency, parent_transaction_id,
+       screening_result, screening_list_version, screened_name, beneficiary_id,
+       payment_request_id
 FROM transactions
 WHERE idempotency_key = $1
 `
@@ -122,17 +306,65 @@ func (q *Queries) GetTransactionByIdempotencyKey(ctx context.Context, idempotenc
 		&i.FailureReason,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FeeAmount,
+		&i.FeeCurrency,
+		&i.ParentTransactionID,
+		&i.ScreeningResult,
+		&i.ScreeningListVersion,
+		&i.ScreenedName,
+		&i.BeneficiaryID,
+		&i.PaymentRequestID,
 	)
 	return i, err
 }
 
+const listStaleInitiatedTransactionIDs = `-- name: ListStaleInitiatedTransactionIDs :many
+SELECT id
+FROM transactions
+WHERE status = 'initiated' AND created_at < $1
+ORDER BY created_at ASC
+LIMIT $2
+`
+
+type ListStaleInitiatedTransactionIDsParams struct {
+	CreatedBefore time.Time `db:"created_before" json:"created_before"`
+	BatchSize     int32     `db:"batch_size" json:"batch_size"`
+}
+
+func (q *Queries) ListStaleInitiatedTransactionIDs(ctx context.Context, arg ListStaleInitiatedTransactionIDsParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listStaleInitiatedTransactionIDs, arg.CreatedBefore, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTransactionsByUser = `-- name: ListTransactionsByUser :many
 SELECT t.id, t.idempotency_key, t.trace_id, t.from_wallet_id, t.to_wallet_id, t.type, t.amount, t.currency,
        t.status, t.provider_name, t.provider_reference, t.exchange_rate, t.failure_reason,
-       t.created_at, t.updated_at
+       t.created_at, t.updated_at, t.fee_amount, t.fee_currency, t.parent_transaction_id,
+       t.screening_result, t.screening_list_version, t.screened_name, t.beneficiary_id,
+       t.payment_request_id
 FROM transactions t
-WHERE t.from_wallet_id IN (
-    SELECT id FROM wallets WHERE user_id = $1
+WHERE (
+    t.from_wallet_id IN (SELECT id FROM wallets WHERE user_id = $1)
+    OR (t.type = 'external' AND t.from_wallet_id IS NULL
+        AND t.to_wallet_id IN (SELECT id FROM wallets WHERE user_id = $1))
 )
 AND (
     $2::timestamp = '1970-01-01 00:00:00+00'::timestamp OR 
@@ -149,6 +381,7 @@ type ListTransactionsByUserParams struct {
 	Limit   int32     `db:"limit" json:"limit"`
 }
 
+// Deposits into the user's wallets are included; they have no source wallet.
 func (q *Queries) ListTransactionsByUser(ctx context.Context, arg ListTransactionsByUserParams) ([]Transaction, error) {
 	rows, err := q.db.QueryContext(ctx, listTransactionsByUser,
 		arg.UserID,
@@ -179,6 +412,14 @@ func (q *Queries) ListTransactionsByUser(ctx context.Context, arg ListTransactio
 			&i.FailureReason,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.FeeAmount,
+			&i.FeeCurrency,
+			&i.ParentTransactionID,
+			&i.ScreeningResult,
+			&i.ScreeningListVersion,
+			&i.ScreenedName,
+			&i.BeneficiaryID,
+			&i.PaymentRequestID,
 		); err != nil {
 			return nil, err
 		}
@@ -193,6 +434,76 @@ func (q *Queries) ListTransactionsByUser(ctx context.Context, arg ListTransactio
 	return items, nil
 }
 
+const listTransactionsToEncrypt = `-- name: ListTransactionsToEncrypt :many
+SELECT id, provider_reference
+FROM transactions
+WHERE id > $1
+  AND (provider_reference LIKE '{%'
+       OR (provider_reference LIKE 'enc:%' AND NOT starts_with(provider_reference, $2::text)))
+ORDER BY id
+LIMIT $3
+`
+
+type ListTransactionsToEncryptParams struct {
+	AfterID   string `db:"after_id" json:"after_id"`
+	KeyPrefix string `db:"key_prefix" json:"key_prefix"`
+	RowLimit  int32  `db:"row_limit" json:"row_limit"`
+}
+
+type ListTransactionsToEncryptRow struct {
+	ID                string         `db:"id" json:"id"`
+	ProviderReference sql.NullString `db:"provider_reference" json:"provider_reference"`
+}
+
+// External transfers keep the recipient details in provider_reference until
+// the payout is submitted. Plaintext details are JSON objects; a provider's
+// own reference is neither JSON nor encrypted and is left alone.
+func (q *Queries) ListTransactionsToEncrypt(ctx context.Context, arg ListTransactionsToEncryptParams) ([]ListTransactionsToEncryptRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTransactionsToEncrypt, arg.AfterID, arg.KeyPrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTransactionsToEncryptRow
+	for rows.Next() {
+		var i ListTransactionsToEncryptRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProviderReference,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const transitionTransactionStatus = `-- name: TransitionTransactionStatus :execrows
+UPDATE transactions
+SET status = $1, updated_at = NOW()
+WHERE id = $2 AND status = $3
+`
+
+type TransitionTransactionStatusParams struct {
+	NewStatus     string `db:"new_status" json:"new_status"`
+	ID            string `db:"id" json:"id"`
+	CurrentStatus string `db:"current_status" json:"current_status"`
+}
+
+func (q *Queries) TransitionTransactionStatus(ctx context.Context, arg TransitionTransactionStatusParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, transitionTransactionStatus, arg.NewStatus, arg.ID, arg.CurrentStatus)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateTransactionFailure = `-- name: UpdateTransactionFailure :exec
 UPDATE transactions
 SET status = $1, failure_reason = $2, updated_at = NOW()
@@ -210,6 +521,49 @@ func (q *Queries) UpdateTransactionFailure(ctx context.Context, arg UpdateTransa
 	return err
 }
 
+const updateTransactionProviderReferenceEncryption = `-- name: UpdateTransactionProviderReferenceEncryption :execrows
+UPDATE transactions
+SET provider_reference = $1
+WHERE id = $2 AND provider_reference = $3
+`
+
+type UpdateTransactionProviderReferenceEncryptionParams struct {
+	ProviderReference        sql.NullString `db:"provider_reference" json:"provider_reference"`
+	ID                       string         `db:"id" json:"id"`
+	CurrentProviderReference sql.NullString `db:"current_provider_reference" json:"current_provider_reference"`
+}
+
+func (q *Queries) UpdateTransactionProviderReferenceEncryption(ctx context.Context, arg UpdateTransactionProviderReferenceEncryptionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateTransactionProviderReferenceEncryption, arg.ProviderReference, arg.ID, arg.CurrentProviderReference)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateTransactionScreening = `-- name: UpdateTransactionScreening :exec
+UPDATE transactions
+SET screening_result = $1, screening_list_version = $2, screened_name = $3, updated_at = NOW()
+WHERE id = $4
+`
+
+type UpdateTransactionScreeningParams struct {
+	ScreeningResult      sql.NullString `db:"screening_result" json:"screening_result"`
+	ScreeningListVersion sql.NullString `db:"screening_list_version" json:"screening_list_version"`
+	ScreenedName         sql.NullString `db:"screened_name" json:"screened_name"`
+	ID                   string         `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateTransactionScreening(ctx context.Context, arg UpdateTransactionScreeningParams) error {
+	_, err := q.db.ExecContext(ctx, updateTransactionScreening,
+		arg.ScreeningResult,
+		arg.ScreeningListVersion,
+		arg.ScreenedName,
+		arg.ID,
+	)
+	return err
+}
+
 const updateTransactionStatus = `-- name: UpdateTransactionStatus :exec
 UPDATE transactions
 SET status = $1, updated_at = NOW()
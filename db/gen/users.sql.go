@@ -7,10 +7,11 @@ package gen
 
 import (
 	"context"
+	"database/sql"
 )
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT user_id, name, pin_hash, created_at, updated_at
+SELECT user_id, name, pin_hash, created_at, updated_at, tier, email, phone, country, date_of_birth, kyc_tier
 FROM users
 WHERE user_id = $1
 `
@@ -24,6 +25,159 @@ func (q *Queries) GetUserByID(ctx context.Context, userID string) (User, error)
 		&i.PinHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Tier,
+		&i.Email,
+		&i.Phone,
+		&i.Country,
+		&i.DateOfBirth,
+		&i.KycTier,
 	)
 	return i, err
 }
+
+const createUser = `-- name: CreateUser :one
+INSERT INTO users (user_id, name, email, phone, country, date_of_birth, kyc_tier)
+VALUES ($1, $2, lower($3), $4, $5, $6, 0)
+RETURNING user_id, name, pin_hash, created_at, updated_at, tier, email, phone, country, date_of_birth, kyc_tier
+`
+
+type CreateUserParams struct {
+	UserID      string         `db:"user_id" json:"user_id"`
+	Name        sql.NullString `db:"name" json:"name"`
+	Email       sql.NullString `db:"email" json:"email"`
+	Phone       sql.NullString `db:"phone" json:"phone"`
+	Country     sql.NullString `db:"country" json:"country"`
+	DateOfBirth sql.NullTime   `db:"date_of_birth" json:"date_of_birth"`
+}
+
+// New users start at KYC tier 0 on the default transfer limit tier.
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, createUser,
+		arg.UserID,
+		arg.Name,
+		arg.Email,
+		arg.Phone,
+		arg.Country,
+		arg.DateOfBirth,
+	)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Name,
+		&i.PinHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Tier,
+		&i.Email,
+		&i.Phone,
+		&i.Country,
+		&i.DateOfBirth,
+		&i.KycTier,
+	)
+	return i, err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+SET name = COALESCE($1, name),
+    email = COALESCE(lower($2), email),
+    phone = COALESCE($3, phone),
+    country = COALESCE($4, country),
+    date_of_birth = COALESCE($5, date_of_birth),
+    updated_at = NOW()
+WHERE user_id = $6
+RETURNING user_id, name, pin_hash, created_at, updated_at, tier, email, phone, country, date_of_birth, kyc_tier
+`
+
+type UpdateUserProfileParams struct {
+	Name        sql.NullString `db:"name" json:"name"`
+	Email       sql.NullString `db:"email" json:"email"`
+	Phone       sql.NullString `db:"phone" json:"phone"`
+	Country     sql.NullString `db:"country" json:"country"`
+	DateOfBirth sql.NullTime   `db:"date_of_birth" json:"date_of_birth"`
+	UserID      string         `db:"user_id" json:"user_id"`
+}
+
+// Fields left null keep their current value.
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserProfile,
+		arg.Name,
+		arg.Email,
+		arg.Phone,
+		arg.Country,
+		arg.DateOfBirth,
+		arg.UserID,
+	)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Name,
+		&i.PinHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Tier,
+		&i.Email,
+		&i.Phone,
+		&i.Country,
+		&i.DateOfBirth,
+		&i.KycTier,
+	)
+	return i, err
+}
+
+const updateUserKYCTier = `-- name: UpdateUserKYCTier :execrows
+UPDATE users
+SET kyc_tier = $1, tier = $2, updated_at = NOW()
+WHERE user_id = $3 AND kyc_tier < $1
+`
+
+type UpdateUserKYCTierParams struct {
+	KycTier int16  `db:"kyc_tier" json:"kyc_tier"`
+	Tier    string `db:"tier" json:"tier"`
+	UserID  string `db:"user_id" json:"user_id"`
+}
+
+// Only ever raises the KYC tier, so a late approval cannot undo a higher tier
+// granted in the meantime.
+func (q *Queries) UpdateUserKYCTier(ctx context.Context, arg UpdateUserKYCTierParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUserKYCTier, arg.KycTier, arg.Tier, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setInitialUserPIN = `-- name: SetInitialUserPIN :execrows
+UPDATE users
+SET pin_hash = $1, updated_at = NOW()
+WHERE user_id = $2 AND pin_hash IS NULL
+`
+
+type SetInitialUserPINParams struct {
+	PinHash sql.NullString `db:"pin_hash" json:"pin_hash"`
+	UserID  string         `db:"user_id" json:"user_id"`
+}
+
+func (q *Queries) SetInitialUserPIN(ctx context.Context, arg SetInitialUserPINParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setInitialUserPIN, arg.PinHash, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateUserPIN = `-- name: UpdateUserPIN :exec
+UPDATE users
+SET pin_hash = $1, updated_at = NOW()
+WHERE user_id = $2
+`
+
+type UpdateUserPINParams struct {
+	PinHash sql.NullString `db:"pin_hash" json:"pin_hash"`
+	UserID  string         `db:"user_id" json:"user_id"`
+}
+
+func (q *Queries) UpdateUserPIN(ctx context.Context, arg UpdateUserPINParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserPIN, arg.PinHash, arg.UserID)
+	return err
+}
@@ -11,10 +11,26 @@ import (
 	"time"
 )
 
+const adjustWalletHeldBalance = `-- name: AdjustWalletHeldBalance :exec
+UPDATE wallets
+SET held_balance = held_balance + $1, updated_at = NOW()
+WHERE id = $2
+`
+
+type AdjustWalletHeldBalanceParams struct {
+	Delta int64  `db:"delta" json:"delta"`
+	ID    string `db:"id" json:"id"`
+}
+
+func (q *Queries) AdjustWalletHeldBalance(ctx context.Context, arg AdjustWalletHeldBalanceParams) error {
+	_, err := q.db.ExecContext(ctx, adjustWalletHeldBalance, arg.Delta, arg.ID)
+	return err
+}
+
 const createWallet = `-- name: CreateWallet :one
 INSERT INTO wallets (id, user_id, bank_account_id, currency, balance)
 VALUES (gen_random_uuid()::text, $1, $2, $3, $4)
-RETURNING id, user_id, bank_account_id, currency, balance, created_at, updated_at
+RETURNING id, user_id, bank_account_id, currency, balance, created_at, updated_at, held_balance, status, frozen_direction, status_reason, closed_at
 `
 
 type CreateWalletParams struct {
@@ -40,6 +56,11 @@ func (q *Queries) CreateWallet(ctx context.Context, arg CreateWalletParams) (Wal
 		&i.Balance,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HeldBalance,
+		&i.Status,
+		&i.FrozenDirection,
+		&i.StatusReason,
+		&i.ClosedAt,
 	)
 	return i, err
 }
@@ -53,6 +74,9 @@ SELECT
     w.balance,
     w.created_at,
     w.updated_at,
+    w.held_balance,
+    w.status,
+    w.frozen_direction,
     ba.account_number,
     ba.bank_name,
     ba.bank_code,
@@ -65,18 +89,21 @@ ORDER BY w.currency, w.created_at
 `
 
 type GetUserWalletsWithBankAccountsRow struct {
-	ID            string         `db:"id" json:"id"`
-	UserID        string         `db:"user_id" json:"user_id"`
-	BankAccountID sql.NullString `db:"bank_account_id" json:"bank_account_id"`
-	Currency      string         `db:"currency" json:"currency"`
-	Balance       int64          `db:"balance" json:"balance"`
-	CreatedAt     time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt     time.Time      `db:"updated_at" json:"updated_at"`
-	AccountNumber sql.NullString `db:"account_number" json:"account_number"`
-	BankName      sql.NullString `db:"bank_name" json:"bank_name"`
-	BankCode      sql.NullString `db:"bank_code" json:"bank_code"`
-	AccountName   sql.NullString `db:"account_name" json:"account_name"`
-	Provider      sql.NullString `db:"provider" json:"provider"`
+	ID              string         `db:"id" json:"id"`
+	UserID          string         `db:"user_id" json:"user_id"`
+	BankAccountID   sql.NullString `db:"bank_account_id" json:"bank_account_id"`
+	Currency        string         `db:"currency" json:"currency"`
+	Balance         int64          `db:"balance" json:"balance"`
+	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
+	HeldBalance     int64          `db:"held_balance" json:"held_balance"`
+	Status          string         `db:"status" json:"status"`
+	FrozenDirection sql.NullString `db:"frozen_direction" json:"frozen_direction"`
+	AccountNumber   sql.NullString `db:"account_number" json:"account_number"`
+	BankName        sql.NullString `db:"bank_name" json:"bank_name"`
+	BankCode        sql.NullString `db:"bank_code" json:"bank_code"`
+	AccountName     sql.NullString `db:"account_name" json:"account_name"`
+	Provider        sql.NullString `db:"provider" json:"provider"`
 }
 
 func (q *Queries) GetUserWalletsWithBankAccounts(ctx context.Context, userID string) ([]GetUserWalletsWithBankAccountsRow, error) {
@@ -96,6 +123,9 @@ func (q *Queries) GetUserWalletsWithBankAccounts(ctx context.Context, userID str
 			&i.Balance,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.HeldBalance,
+			&i.Status,
+			&i.FrozenDirection,
 			&i.AccountNumber,
 			&i.BankName,
 			&i.BankCode,
@@ -116,7 +146,7 @@ func (q *Queries) GetUserWalletsWithBankAccounts(ctx context.Context, userID str
 }
 
 const getWalletByBankAccount = `-- name: GetWalletByBankAccount :one
-SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at
+SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at, held_balance, status, frozen_direction, status_reason, closed_at
 FROM wallets
 WHERE bank_account_id = $1 AND bank_account_id IS NOT NULL
 `
@@ -132,12 +162,17 @@ func (q *Queries) GetWalletByBankAccount(ctx context.Context, bankAccountID sql.
 		&i.Balance,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HeldBalance,
+		&i.Status,
+		&i.FrozenDirection,
+		&i.StatusReason,
+		&i.ClosedAt,
 	)
 	return i, err
 }
 
 const getWalletByID = `-- name: GetWalletByID :one
-SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at
+SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at, held_balance, status, frozen_direction, status_reason, closed_at
 FROM wallets
 WHERE id = $1
 `
@@ -153,12 +188,17 @@ func (q *Queries) GetWalletByID(ctx context.Context, id string) (Wallet, error)
 		&i.Balance,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HeldBalance,
+		&i.Status,
+		&i.FrozenDirection,
+		&i.StatusReason,
+		&i.ClosedAt,
 	)
 	return i, err
 }
 
 const getWalletByIDForUpdate = `-- name: GetWalletByIDForUpdate :one
-SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at
+SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at, held_balance, status, frozen_direction, status_reason, closed_at
 FROM wallets
 WHERE id = $1
 FOR UPDATE
@@ -175,14 +215,19 @@ func (q *Queries) GetWalletByIDForUpdate(ctx context.Context, id string) (Wallet
 		&i.Balance,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HeldBalance,
+		&i.Status,
+		&i.FrozenDirection,
+		&i.StatusReason,
+		&i.ClosedAt,
 	)
 	return i, err
 }
 
 const getWalletByUserAndCurrency = `-- name: GetWalletByUserAndCurrency :one
-SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at
+SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at, held_balance, status, frozen_direction, status_reason, closed_at
 FROM wallets
-WHERE user_id = $1 AND currency = $2
+WHERE user_id = $1 AND currency = $2 AND status <> 'closed'
 `
 
 type GetWalletByUserAndCurrencyParams struct {
@@ -201,14 +246,19 @@ func (q *Queries) GetWalletByUserAndCurrency(ctx context.Context, arg GetWalletB
 		&i.Balance,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HeldBalance,
+		&i.Status,
+		&i.FrozenDirection,
+		&i.StatusReason,
+		&i.ClosedAt,
 	)
 	return i, err
 }
 
 const getWalletByUserAndCurrencyForUpdate = `-- name: GetWalletByUserAndCurrencyForUpdate :one
-SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at
+SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at, held_balance, status, frozen_direction, status_reason, closed_at
 FROM wallets
-WHERE user_id = $1 AND currency = $2
+WHERE user_id = $1 AND currency = $2 AND status <> 'closed'
 FOR UPDATE
 `
 
@@ -228,10 +278,26 @@ func (q *Queries) GetWalletByUserAndCurrencyForUpdate(ctx context.Context, arg G
 		&i.Balance,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HeldBalance,
+		&i.Status,
+		&i.FrozenDirection,
+		&i.StatusReason,
+		&i.ClosedAt,
 	)
 	return i, err
 }
 
+const nextVirtualAccountNumber = `-- name: NextVirtualAccountNumber :one
+SELECT nextval('virtual_account_number_seq')::bigint
+`
+
+func (q *Queries) NextVirtualAccountNumber(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, nextVirtualAccountNumber)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
 const updateWalletBalance = `-- name: UpdateWalletBalance :exec
 UPDATE wallets
 SET balance = $1, updated_at = NOW()
@@ -247,3 +313,47 @@ func (q *Queries) UpdateWalletBalance(ctx context.Context, arg UpdateWalletBalan
 	_, err := q.db.ExecContext(ctx, updateWalletBalance, arg.Balance, arg.ID)
 	return err
 }
+
+const updateWalletStatus = `-- name: UpdateWalletStatus :one
+UPDATE wallets
+SET status = $1,
+    frozen_direction = $2,
+    status_reason = $3,
+    closed_at = CASE WHEN $1 = 'closed' THEN NOW() ELSE closed_at END,
+    updated_at = NOW()
+WHERE id = $4
+RETURNING id, user_id, bank_account_id, currency, balance, created_at, updated_at, held_balance, status, frozen_direction, status_reason, closed_at
+`
+
+type UpdateWalletStatusParams struct {
+	Status          string         `db:"status" json:"status"`
+	FrozenDirection sql.NullString `db:"frozen_direction" json:"frozen_direction"`
+	StatusReason    sql.NullString `db:"status_reason" json:"status_reason"`
+	ID              string         `db:"id" json:"id"`
+}
+
+// closed_at is set when the wallet is closed and never cleared.
+func (q *Queries) UpdateWalletStatus(ctx context.Context, arg UpdateWalletStatusParams) (Wallet, error) {
+	row := q.db.QueryRowContext(ctx, updateWalletStatus,
+		arg.Status,
+		arg.FrozenDirection,
+		arg.StatusReason,
+		arg.ID,
+	)
+	var i Wallet
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.BankAccountID,
+		&i.Currency,
+		&i.Balance,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HeldBalance,
+		&i.Status,
+		&i.FrozenDirection,
+		&i.StatusReason,
+		&i.ClosedAt,
+	)
+	return i, err
+}
@@ -47,3 +47,71 @@ func (q *Queries) CreateWebhookEvent(ctx context.Context, arg CreateWebhookEvent
 	)
 	return i, err
 }
+
+const listWebhookEventsToEncrypt = `-- name: ListWebhookEventsToEncrypt :many
+SELECT id, payload
+FROM webhook_events
+WHERE id > $1
+  AND (jsonb_typeof(payload) <> 'string' OR NOT starts_with(payload #>> '{}', $2::text))
+ORDER BY id
+LIMIT $3
+`
+
+type ListWebhookEventsToEncryptParams struct {
+	AfterID   string `db:"after_id" json:"after_id"`
+	KeyPrefix string `db:"key_prefix" json:"key_prefix"`
+	RowLimit  int32  `db:"row_limit" json:"row_limit"`
+}
+
+type ListWebhookEventsToEncryptRow struct {
+	ID      string          `db:"id" json:"id"`
+	Payload json.RawMessage `db:"payload" json:"payload"`
+}
+
+// Encrypted payloads are stored as a JSON string holding the envelope;
+// plaintext payloads are the provider's original JSON object.
+func (q *Queries) ListWebhookEventsToEncrypt(ctx context.Context, arg ListWebhookEventsToEncryptParams) ([]ListWebhookEventsToEncryptRow, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookEventsToEncrypt, arg.AfterID, arg.KeyPrefix, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWebhookEventsToEncryptRow
+	for rows.Next() {
+		var i ListWebhookEventsToEncryptRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Payload,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateWebhookEventPayloadEncryption = `-- name: UpdateWebhookEventPayloadEncryption :execrows
+UPDATE webhook_events
+SET payload = $1
+WHERE id = $2 AND payload = $3
+`
+
+type UpdateWebhookEventPayloadEncryptionParams struct {
+	Payload        json.RawMessage `db:"payload" json:"payload"`
+	ID             string          `db:"id" json:"id"`
+	CurrentPayload json.RawMessage `db:"current_payload" json:"current_payload"`
+}
+
+func (q *Queries) UpdateWebhookEventPayloadEncryption(ctx context.Context, arg UpdateWebhookEventPayloadEncryptionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateWebhookEventPayloadEncryption, arg.Payload, arg.ID, arg.CurrentPayload)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,34 @@
+-- name: CreateAuditEvent :exec
+INSERT INTO audit_events (
+    actor_id, actor_type, action, entity_type, entity_id,
+    before, after, ip_address, trace_id, digest, created_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+);
+
+-- name: GetAuditChainHead :one
+SELECT id, sequence, hash
+FROM audit_chain_head
+WHERE id = 1;
+
+-- name: ListAuditEvents :many
+SELECT sequence, id, actor_id, actor_type, action, entity_type, entity_id,
+       before, after, ip_address, trace_id, digest, prev_hash, hash, created_at
+FROM audit_log
+WHERE (sqlc.narg(entity_type)::text IS NULL OR entity_type = sqlc.narg(entity_type))
+  AND (sqlc.narg(entity_id)::text IS NULL OR entity_id = sqlc.narg(entity_id))
+  AND (sqlc.narg(actor_id)::text IS NULL OR actor_id = sqlc.narg(actor_id))
+  AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action))
+  AND (sqlc.narg(created_from)::timestamptz IS NULL OR created_at >= sqlc.narg(created_from))
+  AND (sqlc.narg(created_to)::timestamptz IS NULL OR created_at < sqlc.narg(created_to))
+  AND (sqlc.narg(before_sequence)::bigint IS NULL OR sequence < sqlc.narg(before_sequence))
+ORDER BY sequence DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListAuditEventsFromSequence :many
+SELECT sequence, id, actor_id, actor_type, action, entity_type, entity_id,
+       before, after, ip_address, trace_id, digest, prev_hash, hash, created_at
+FROM audit_log
+WHERE sequence >= sqlc.arg(from_sequence)
+ORDER BY sequence ASC
+LIMIT sqlc.arg(row_limit);
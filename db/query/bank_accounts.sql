@@ -1,9 +1,31 @@
 -- name: GetBankAccountByID :one
-SELECT id, user_id, bank_name, bank_code, account_number, account_name, currency, provider, created_at, updated_at
+SELECT id, user_id, bank_name, bank_code, account_number, account_name, currency, provider, created_at, updated_at, account_number_hash
 FROM bank_accounts
 WHERE id = $1;
 
 -- name: GetBankAccountByAccountAndBankCode :one
-SELECT id, user_id, bank_name, bank_code, account_number, account_name, currency, provider, created_at, updated_at
+-- Rows written before encryption was enabled have no blind index yet and are
+-- matched on the plaintext number until the re-encryption worker reaches them.
+SELECT id, user_id, bank_name, bank_code, account_number, account_name, currency, provider, created_at, updated_at, account_number_hash
 FROM bank_accounts
-WHERE account_number = $1 AND bank_code = $2;
+WHERE bank_code = sqlc.arg(bank_code)
+  AND (account_number_hash = sqlc.narg(account_number_hash)
+       OR (account_number_hash IS NULL AND account_number = sqlc.arg(account_number)));
+
+-- name: ListBankAccountsToEncrypt :many
+SELECT id, account_number
+FROM bank_accounts
+WHERE id > sqlc.arg(after_id)
+  AND (NOT starts_with(account_number, sqlc.arg(key_prefix)::text) OR account_number_hash IS NULL)
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: UpdateBankAccountNumberEncryption :execrows
+UPDATE bank_accounts
+SET account_number = sqlc.arg(account_number), account_number_hash = sqlc.arg(account_number_hash)
+WHERE id = sqlc.arg(id) AND account_number = sqlc.arg(current_account_number);
+
+-- name: CreateBankAccount :one
+INSERT INTO bank_accounts (id, user_id, bank_name, bank_code, account_number, account_name, currency, provider, account_number_hash)
+VALUES (gen_random_uuid()::text, $1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
@@ -0,0 +1,34 @@
+-- name: GetBank :one
+SELECT code, name, country, currencies, preferred_provider, active, created_at, updated_at
+FROM banks
+WHERE code = $1;
+
+-- name: ListBanks :many
+-- search matches the bank code exactly or any part of the name, ignoring case.
+SELECT code, name, country, currencies, preferred_provider, active, created_at, updated_at
+FROM banks
+WHERE active
+  AND (sqlc.narg(search)::text IS NULL
+       OR code = sqlc.narg(search)
+       OR strpos(lower(name), lower(sqlc.narg(search))) > 0)
+  AND (sqlc.narg(country)::text IS NULL OR country = sqlc.narg(country))
+ORDER BY name, code;
+
+-- name: UpsertBank :exec
+INSERT INTO banks (code, name, country, currencies, preferred_provider)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (code) DO UPDATE
+SET name = EXCLUDED.name,
+    country = EXCLUDED.country,
+    currencies = EXCLUDED.currencies,
+    preferred_provider = EXCLUDED.preferred_provider,
+    active = TRUE,
+    updated_at = NOW();
+
+-- name: DeactivateBanksNotIn :execrows
+-- Banks missing from the latest import are kept for history but no longer
+-- accepted.
+UPDATE banks
+SET active = FALSE,
+    updated_at = NOW()
+WHERE active AND NOT (code = ANY(sqlc.arg(codes)::text[]));
@@ -0,0 +1,70 @@
+-- name: CreateBeneficiary :one
+INSERT INTO beneficiaries (
+    id, user_id, nickname, account_number, account_number_hash, bank_code,
+    account_name, currency, country, is_internal
+)
+VALUES (gen_random_uuid()::text, $1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, user_id, nickname, account_number, account_number_hash, bank_code, account_name, currency, country, is_internal, verified_at, created_at, updated_at;
+
+-- name: GetBeneficiaryForUser :one
+SELECT id, user_id, nickname, account_number, account_number_hash, bank_code, account_name, currency, country, is_internal, verified_at, created_at, updated_at
+FROM beneficiaries
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetBeneficiaryByAccount :one
+-- Matches a user's saved account the same way as
+-- GetBankAccountByAccountAndBankCode, falling back to the plaintext number for
+-- rows without a blind index.
+SELECT id, user_id, nickname, account_number, account_number_hash, bank_code, account_name, currency, country, is_internal, verified_at, created_at, updated_at
+FROM beneficiaries
+WHERE user_id = sqlc.arg(user_id)
+  AND bank_code = sqlc.arg(bank_code)
+  AND (account_number_hash = sqlc.narg(account_number_hash)
+       OR (account_number_hash IS NULL AND account_number = sqlc.arg(account_number)));
+
+-- name: ListBeneficiariesByUser :many
+SELECT id, user_id, nickname, account_number, account_number_hash, bank_code, account_name, currency, country, is_internal, verified_at, created_at, updated_at
+FROM beneficiaries
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: UpdateBeneficiary :one
+-- verified_at is the caller's to set: it only moves when the account details
+-- changed and were verified again.
+UPDATE beneficiaries
+SET nickname = sqlc.narg(nickname),
+    account_number = sqlc.arg(account_number),
+    account_number_hash = sqlc.narg(account_number_hash),
+    bank_code = sqlc.arg(bank_code),
+    account_name = sqlc.arg(account_name),
+    currency = sqlc.arg(currency),
+    country = sqlc.narg(country),
+    is_internal = sqlc.arg(is_internal),
+    verified_at = sqlc.arg(verified_at),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id)
+RETURNING id, user_id, nickname, account_number, account_number_hash, bank_code, account_name, currency, country, is_internal, verified_at, created_at, updated_at;
+
+-- name: DeleteBeneficiary :execrows
+DELETE FROM beneficiaries
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetBeneficiaryVerifiedAt :one
+-- Used by risk checks, which look the beneficiary up by ID alone because the
+-- transaction already ties it to the sender.
+SELECT verified_at
+FROM beneficiaries
+WHERE id = $1;
+
+-- name: ListBeneficiariesToEncrypt :many
+SELECT id, account_number
+FROM beneficiaries
+WHERE id > sqlc.arg(after_id)
+  AND (NOT starts_with(account_number, sqlc.arg(key_prefix)::text) OR account_number_hash IS NULL)
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: UpdateBeneficiaryAccountNumberEncryption :execrows
+UPDATE beneficiaries
+SET account_number = sqlc.arg(account_number), account_number_hash = sqlc.arg(account_number_hash)
+WHERE id = sqlc.arg(id) AND account_number = sqlc.arg(current_account_number);
@@ -0,0 +1,7 @@
+-- name: ListActiveFeeRules :many
+SELECT id, name, transaction_type, source_currency, destination_currency, min_amount, max_amount,
+       fee_type, flat_amount, percentage_bps, tiers, min_fee, max_fee, priority, active,
+       created_at, updated_at
+FROM fee_rules
+WHERE transaction_type = $1 AND active = TRUE
+ORDER BY priority DESC, created_at ASC;
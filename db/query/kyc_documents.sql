@@ -0,0 +1,52 @@
+-- name: CreateKYCDocument :one
+INSERT INTO kyc_documents (id, user_id, category, document_type, document_number, issuing_country)
+VALUES (gen_random_uuid()::text, $1, $2, $3, $4, $5)
+RETURNING id, user_id, category, document_type, document_number, issuing_country, status, reviewer_id, review_note, reviewed_at, created_at, updated_at;
+
+-- name: GetKYCDocumentByIDForUpdate :one
+SELECT id, user_id, category, document_type, document_number, issuing_country, status, reviewer_id, review_note, reviewed_at, created_at, updated_at
+FROM kyc_documents
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListKYCDocumentsByUser :many
+SELECT id, user_id, category, document_type, document_number, issuing_country, status, reviewer_id, review_note, reviewed_at, created_at, updated_at
+FROM kyc_documents
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: ListKYCDocumentsByStatus :many
+-- The review queue, oldest first.
+SELECT id, user_id, category, document_type, document_number, issuing_country, status, reviewer_id, review_note, reviewed_at, created_at, updated_at
+FROM kyc_documents
+WHERE status = sqlc.arg(status)
+ORDER BY created_at, id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListApprovedKYCCategories :many
+SELECT DISTINCT category
+FROM kyc_documents
+WHERE user_id = $1 AND status = 'approved';
+
+-- name: ReviewKYCDocument :one
+UPDATE kyc_documents
+SET status = sqlc.arg(status),
+    reviewer_id = sqlc.arg(reviewer_id),
+    review_note = sqlc.narg(review_note),
+    reviewed_at = NOW(),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING id, user_id, category, document_type, document_number, issuing_country, status, reviewer_id, review_note, reviewed_at, created_at, updated_at;
+
+-- name: ListKYCDocumentsToEncrypt :many
+SELECT id, document_number
+FROM kyc_documents
+WHERE id > sqlc.arg(after_id)
+  AND NOT starts_with(document_number, sqlc.arg(key_prefix)::text)
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: UpdateKYCDocumentNumberEncryption :execrows
+UPDATE kyc_documents
+SET document_number = sqlc.arg(document_number)
+WHERE id = sqlc.arg(id) AND document_number = sqlc.arg(current_document_number);
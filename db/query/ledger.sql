@@ -8,7 +8,27 @@ INSERT INTO ledger_entries (id, wallet_id, transaction_id, amount, currency, acc
 VALUES (gen_random_uuid()::text, NULL, $1, $2, $3, 'external_wallet', $4, $5)
 RETURNING id, wallet_id, transaction_id, amount, currency, account_type, balance_before, balance_after, created_at;
 
+-- name: CreateExternalSystemDebitEntry :one
+INSERT INTO ledger_entries (id, wallet_id, transaction_id, amount, currency, account_type, balance_before, balance_after)
+VALUES (gen_random_uuid()::text, NULL, $1, $2, $3, 'external_wallet', $4, $5)
+RETURNING id, wallet_id, transaction_id, amount, currency, account_type, balance_before, balance_after, created_at;
+
 -- name: GetWalletBalance :one
 SELECT COALESCE(SUM(amount), 0)::BIGINT as balance
 FROM ledger_entries
 WHERE wallet_id = $1 AND currency = $2 AND account_type = 'user_wallet';
+
+-- name: CreateFeeRevenueEntry :one
+INSERT INTO ledger_entries (id, wallet_id, transaction_id, amount, currency, account_type, balance_before, balance_after)
+VALUES (gen_random_uuid()::text, NULL, $1, $2, $3, 'fee_revenue', $4, $5)
+RETURNING id, wallet_id, transaction_id, amount, currency, account_type, balance_before, balance_after, created_at;
+
+-- name: GetFeeRevenueBalance :one
+SELECT COALESCE(SUM(amount), 0)::BIGINT as balance
+FROM ledger_entries
+WHERE account_type = 'fee_revenue' AND currency = $1;
+
+-- name: LockFeeRevenueAccount :exec
+-- The fee revenue account has no wallet row to lock, so postings to it are
+-- serialized per currency with a transaction-scoped advisory lock.
+SELECT pg_advisory_xact_lock(hashtext('fee_revenue:' || $1::text));
@@ -0,0 +1,47 @@
+-- name: CreatePaymentRequest :one
+INSERT INTO payment_requests (id, requester_id, wallet_id, amount, currency, memo, token, expires_at)
+VALUES (gen_random_uuid()::text, $1, $2, $3, $4, $5, $6, $7)
+RETURNING id, requester_id, wallet_id, amount, currency, memo, token, status, expires_at, transaction_id, paid_by, paid_at, created_at, updated_at;
+
+-- name: GetPaymentRequestByToken :one
+SELECT id, requester_id, wallet_id, amount, currency, memo, token, status, expires_at, transaction_id, paid_by, paid_at, created_at, updated_at
+FROM payment_requests
+WHERE token = $1;
+
+-- name: GetPaymentRequestByIDForUpdate :one
+SELECT id, requester_id, wallet_id, amount, currency, memo, token, status, expires_at, transaction_id, paid_by, paid_at, created_at, updated_at
+FROM payment_requests
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListPaymentRequestsByRequester :many
+SELECT id, requester_id, wallet_id, amount, currency, memo, token, status, expires_at, transaction_id, paid_by, paid_at, created_at, updated_at
+FROM payment_requests
+WHERE requester_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2;
+
+-- name: TransitionPaymentRequestStatus :execrows
+UPDATE payment_requests
+SET status = sqlc.arg(new_status), updated_at = NOW()
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(current_status);
+
+-- name: MarkPaymentRequestPaid :execrows
+-- Only a pending request that has not run out can be paid, so a request is
+-- never paid twice.
+UPDATE payment_requests
+SET status = 'paid', transaction_id = sqlc.arg(transaction_id), paid_by = sqlc.arg(paid_by),
+    paid_at = NOW(), updated_at = NOW()
+WHERE id = sqlc.arg(id) AND status = 'pending' AND expires_at > NOW();
+
+-- name: ExpirePaymentRequests :many
+UPDATE payment_requests
+SET status = 'expired', updated_at = NOW()
+WHERE id IN (
+    SELECT id FROM payment_requests
+    WHERE status = 'pending' AND expires_at <= NOW()
+    ORDER BY expires_at ASC
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, requester_id;
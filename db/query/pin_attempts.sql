@@ -0,0 +1,39 @@
+-- name: ClaimPINAttempt :one
+-- Counts an attempt before the PIN or code is checked, so parallel requests
+-- cannot all get past the lockout check. Returns no row while a lockout is in
+-- force or once max_attempts attempts are already counted. Attempts that
+-- reached max_attempts without a lockout were never resolved; once the last
+-- was claimed before stale_before they are dropped and counting starts over.
+INSERT INTO pin_attempts (user_id, failed_attempts, updated_at)
+VALUES (sqlc.arg(user_id), 1, NOW())
+ON CONFLICT (user_id) DO UPDATE
+SET failed_attempts = CASE
+        WHEN pin_attempts.failed_attempts >= sqlc.arg(max_attempts) THEN 1
+        ELSE pin_attempts.failed_attempts + 1
+    END,
+    updated_at = NOW()
+WHERE (pin_attempts.locked_until IS NULL OR pin_attempts.locked_until <= NOW())
+  AND (pin_attempts.failed_attempts < sqlc.arg(max_attempts) OR pin_attempts.updated_at < sqlc.arg(stale_before))
+RETURNING user_id, failed_attempts, lockout_count, locked_until, updated_at;
+
+-- name: ClearPINAttempts :execrows
+-- Resets the counters after a correct PIN or code, unless a lockout started
+-- while it was being checked.
+UPDATE pin_attempts
+SET failed_attempts = 0, lockout_count = 0, locked_until = NULL, updated_at = NOW()
+WHERE user_id = $1 AND (locked_until IS NULL OR locked_until <= NOW());
+
+-- name: GetPINAttempts :one
+SELECT user_id, failed_attempts, lockout_count, locked_until, updated_at
+FROM pin_attempts
+WHERE user_id = $1;
+
+-- name: LockPIN :exec
+UPDATE pin_attempts
+SET failed_attempts = 0, lockout_count = lockout_count + 1, locked_until = sqlc.arg(locked_until), updated_at = NOW()
+WHERE user_id = sqlc.arg(user_id);
+
+-- name: ResetPINAttempts :exec
+UPDATE pin_attempts
+SET failed_attempts = 0, lockout_count = 0, locked_until = NULL, updated_at = NOW()
+WHERE user_id = $1;
@@ -0,0 +1,10 @@
+-- name: CreatePINHistory :exec
+INSERT INTO pin_history (id, user_id, pin_hash)
+VALUES (gen_random_uuid()::text, $1, $2);
+
+-- name: ListRecentPINHashes :many
+SELECT pin_hash
+FROM pin_history
+WHERE user_id = sqlc.arg(user_id)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(history_size);
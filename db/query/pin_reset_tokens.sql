@@ -0,0 +1,15 @@
+-- name: CreatePINResetToken :one
+INSERT INTO pin_reset_tokens (id, user_id, token_hash, created_by, expires_at)
+VALUES (gen_random_uuid()::text, $1, $2, $3, $4)
+RETURNING id, user_id, token_hash, created_by, expires_at, used_at, created_at;
+
+-- name: RevokePINResetTokens :exec
+UPDATE pin_reset_tokens
+SET used_at = NOW()
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: ConsumePINResetToken :one
+UPDATE pin_reset_tokens
+SET used_at = NOW()
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING id, user_id, token_hash, created_by, expires_at, used_at, created_at;
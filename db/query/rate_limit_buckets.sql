@@ -0,0 +1,19 @@
+-- name: TakeRateLimitToken :one
+-- Refills the bucket for the time since its last update and takes one token.
+-- Returns no row when less than one token is available.
+INSERT INTO rate_limit_buckets (key, tokens, updated_at)
+VALUES (sqlc.arg(key), sqlc.arg(burst)::double precision - 1, NOW())
+ON CONFLICT (key) DO UPDATE
+SET tokens = LEAST(sqlc.arg(burst)::double precision, rate_limit_buckets.tokens + EXTRACT(EPOCH FROM NOW() - rate_limit_buckets.updated_at) * sqlc.arg(rate)::double precision) - 1,
+    updated_at = NOW()
+WHERE LEAST(sqlc.arg(burst)::double precision, rate_limit_buckets.tokens + EXTRACT(EPOCH FROM NOW() - rate_limit_buckets.updated_at) * sqlc.arg(rate)::double precision) >= 1
+RETURNING tokens;
+
+-- name: GetRateLimitTokens :one
+SELECT LEAST(sqlc.arg(burst)::double precision, tokens + EXTRACT(EPOCH FROM NOW() - updated_at) * sqlc.arg(rate)::double precision)::double precision AS tokens
+FROM rate_limit_buckets
+WHERE key = sqlc.arg(key);
+
+-- name: DeleteStaleRateLimitBuckets :exec
+DELETE FROM rate_limit_buckets
+WHERE updated_at < $1;
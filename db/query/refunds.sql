@@ -0,0 +1,26 @@
+-- name: CreateRefund :one
+INSERT INTO refunds (
+    id, transaction_id, refund_transaction_id, amount, currency, credited_amount, credited_currency,
+    reason, idempotency_key, created_by
+)
+VALUES (gen_random_uuid()::text, $1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetRefundByIdempotencyKey :one
+SELECT id, transaction_id, refund_transaction_id, amount, currency, credited_amount, credited_currency,
+       reason, idempotency_key, created_by, created_at
+FROM refunds
+WHERE idempotency_key = $1;
+
+-- name: ListRefundsByTransaction :many
+SELECT id, transaction_id, refund_transaction_id, amount, currency, credited_amount, credited_currency,
+       reason, idempotency_key, created_by, created_at
+FROM refunds
+WHERE transaction_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: GetRefundTotals :one
+SELECT COALESCE(SUM(amount), 0)::BIGINT AS refunded_amount,
+       COALESCE(SUM(credited_amount), 0)::BIGINT AS credited_amount
+FROM refunds
+WHERE transaction_id = $1;
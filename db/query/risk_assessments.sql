@@ -0,0 +1,65 @@
+-- name: CreateRiskAssessment :one
+INSERT INTO risk_assessments (id, transaction_id, user_id, recipient, decision, score, reasons, features)
+VALUES (gen_random_uuid()::text, $1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetLatestRiskAssessment :one
+SELECT id, transaction_id, user_id, recipient, decision, score, reasons, features,
+       review_decision, reviewed_by, review_note, reviewed_at, created_at
+FROM risk_assessments
+WHERE transaction_id = $1
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: ListPendingRiskReviews :many
+SELECT r.id, r.transaction_id, r.user_id, r.recipient, r.decision, r.score, r.reasons, r.features,
+       r.review_decision, r.reviewed_by, r.review_note, r.reviewed_at, r.created_at
+FROM risk_assessments r
+JOIN transactions t ON t.id = r.transaction_id
+WHERE r.decision = 'review' AND r.reviewed_at IS NULL AND t.status = 'on_hold'
+ORDER BY r.created_at ASC
+LIMIT $1;
+
+-- name: RecordRiskReview :execrows
+UPDATE risk_assessments
+SET review_decision = sqlc.arg(review_decision), reviewed_by = sqlc.arg(reviewed_by),
+    review_note = sqlc.arg(review_note), reviewed_at = NOW()
+WHERE id = sqlc.arg(id) AND reviewed_at IS NULL;
+
+-- name: CountRecipientTransfers :one
+SELECT COUNT(DISTINCT r.transaction_id)
+FROM risk_assessments r
+JOIN transactions t ON t.id = r.transaction_id
+WHERE r.user_id = $1 AND r.recipient = $2 AND t.status IN ('pending', 'completed');
+
+-- name: GetRiskTransferHistory :one
+SELECT COUNT(*) FILTER (WHERE t.created_at >= sqlc.arg(since)) AS recent_count,
+       COUNT(*) FILTER (WHERE t.status IN ('pending', 'completed')) AS total_count,
+       COUNT(*) FILTER (
+           WHERE t.status IN ('pending', 'completed')
+             AND w.currency = sqlc.arg(source_currency)
+             AND t.currency = sqlc.arg(destination_currency)
+             AND t.type = sqlc.arg(type)
+       ) AS corridor_count
+FROM transactions t
+JOIN wallets w ON w.id = t.from_wallet_id
+WHERE w.user_id = sqlc.arg(user_id)
+  AND t.type IN ('internal', 'external')
+  AND t.status IN ('pending', 'completed', 'on_hold')
+  AND (t.to_wallet_id IS NULL OR t.to_wallet_id NOT IN (SELECT id FROM wallets WHERE user_id = sqlc.arg(user_id)));
+
+-- name: ListRiskRecipientsToIndex :many
+-- Bank account recipients still holding the account number rather than its
+-- 64-character blind index.
+SELECT id, recipient
+FROM risk_assessments
+WHERE id > sqlc.arg(after_id)
+  AND starts_with(recipient, 'account:')
+  AND recipient !~ ':[0-9a-f]{64}$'
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: UpdateRiskAssessmentRecipient :execrows
+UPDATE risk_assessments
+SET recipient = sqlc.arg(recipient)
+WHERE id = sqlc.arg(id) AND recipient = sqlc.arg(current_recipient);
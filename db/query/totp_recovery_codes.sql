@@ -0,0 +1,12 @@
+-- name: CreateTOTPRecoveryCode :exec
+INSERT INTO totp_recovery_codes (id, user_id, code_hash)
+VALUES (gen_random_uuid()::text, $1, $2);
+
+-- name: UseTOTPRecoveryCode :execrows
+UPDATE totp_recovery_codes
+SET used_at = NOW()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: DeleteTOTPRecoveryCodes :exec
+DELETE FROM totp_recovery_codes
+WHERE user_id = $1;
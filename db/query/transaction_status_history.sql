@@ -0,0 +1,3 @@
+-- name: CreateTransactionStatusHistory :exec
+INSERT INTO transaction_status_history (id, transaction_id, from_status, to_status, reason)
+VALUES (gen_random_uuid()::text, $1, $2, $3, $4);
@@ -1,22 +1,57 @@
 -- name: GetTransactionByID :one
 SELECT id, idempotency_key, trace_id, from_wallet_id, to_wallet_id, type, amount, currency,
        status, provider_name, provider_reference, exchange_rate, failure_reason,
-       created_at, updated_at
+       created_at, updated_at, fee_amount, fee_currency, parent_transaction_id,
+       screening_result, screening_list_version, screened_name, beneficiary_id,
+       payment_request_id
 FROM transactions
 WHERE id = $1;
 
+-- name: GetTransactionByIDForUpdate :one
+SELECT id, idempotency_key, trace_id, from_wallet_id, to_wallet_id, type, amount, currency,
+       status, provider_name, provider_reference, exchange_rate, failure_reason,
+       created_at, updated_at, fee_amount, fee_currency, parent_transaction_id,
+       screening_result, screening_list_version, screened_name, beneficiary_id,
+       payment_request_id
+FROM transactions
+WHERE id = $1
+FOR UPDATE;
+
 -- name: GetTransactionByIdempotencyKey :one
 SELECT id, idempotency_key, trace_id, from_wallet_id, to_wallet_id, type, amount, currency,
        status, provider_name, provider_reference, exchange_rate, failure_reason,
-       created_at, updated_at
+       created_at, updated_at, fee_amount, fee_currency, parent_transaction_id,
+       screening_result, screening_list_version, screened_name, beneficiary_id,
+       payment_request_id
 FROM transactions
 WHERE idempotency_key = $1;
 
 -- name: CreateTransaction :one
 INSERT INTO transactions (
-    id, idempotency_key, trace_id, from_wallet_id, to_wallet_id, type, amount, currency, status, exchange_rate
+    id, idempotency_key, trace_id, from_wallet_id, to_wallet_id, type, amount, currency, status, exchange_rate,
+    fee_amount, fee_currency, parent_transaction_id, beneficiary_id, payment_request_id
+)
+VALUES (gen_random_uuid()::text, $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+RETURNING *;
+
+-- name: GetDepositByProviderReference :one
+SELECT id, idempotency_key, trace_id, from_wallet_id, to_wallet_id, type, amount, currency,
+       status, provider_name, provider_reference, exchange_rate, failure_reason,
+       created_at, updated_at, fee_amount, fee_currency, parent_transaction_id,
+       screening_result, screening_list_version, screened_name, beneficiary_id,
+       payment_request_id
+FROM transactions
+WHERE provider_name = $1 AND provider_reference = $2
+  AND type = 'external' AND from_wallet_id IS NULL;
+
+-- name: CreateDepositTransaction :one
+-- A deposit is written in its final state: completed when the wallet is
+-- credited, or failed when the wallet cannot accept it.
+INSERT INTO transactions (
+    id, idempotency_key, trace_id, to_wallet_id, type, amount, currency, status,
+    provider_name, provider_reference, failure_reason
 )
-VALUES (gen_random_uuid()::text, $1, $2, $3, $4, $5, $6, $7, $8, $9)
+VALUES (gen_random_uuid()::text, $1, $2, $3, 'external', $4, $5, $6, $7, $8, $9)
 RETURNING *;
 
 -- name: UpdateTransactionStatus :exec
@@ -24,23 +59,45 @@ UPDATE transactions
 SET status = $1, updated_at = NOW()
 WHERE id = $2;
 
+-- name: TransitionTransactionStatus :execrows
+UPDATE transactions
+SET status = sqlc.arg(new_status), updated_at = NOW()
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(current_status);
+
+-- name: ListStaleInitiatedTransactionIDs :many
+SELECT id
+FROM transactions
+WHERE status = 'initiated' AND created_at < sqlc.arg(created_before)
+ORDER BY created_at ASC
+LIMIT sqlc.arg(batch_size);
+
 -- name: UpdateTransactionWithProvider :exec
 UPDATE transactions
 SET provider_name = $1, provider_reference = $2, status = $3, updated_at = NOW()
 WHERE id = $4;
 
+-- name: UpdateTransactionScreening :exec
+UPDATE transactions
+SET screening_result = $1, screening_list_version = $2, screened_name = $3, updated_at = NOW()
+WHERE id = $4;
+
 -- name: UpdateTransactionFailure :exec
 UPDATE transactions
 SET status = $1, failure_reason = $2, updated_at = NOW()
 WHERE id = $3;
 
 -- name: ListTransactionsByUser :many
+-- Deposits into the user's wallets are included; they have no source wallet.
 SELECT t.id, t.idempotency_key, t.trace_id, t.from_wallet_id, t.to_wallet_id, t.type, t.amount, t.currency,
        t.status, t.provider_name, t.provider_reference, t.exchange_rate, t.failure_reason,
-       t.created_at, t.updated_at
+       t.created_at, t.updated_at, t.fee_amount, t.fee_currency, t.parent_transaction_id,
+       t.screening_result, t.screening_list_version, t.screened_name, t.beneficiary_id,
+       t.payment_request_id
 FROM transactions t
-WHERE t.from_wallet_id IN (
-    SELECT id FROM wallets WHERE user_id = $1
+WHERE (
+    t.from_wallet_id IN (SELECT id FROM wallets WHERE user_id = $1)
+    OR (t.type = 'external' AND t.from_wallet_id IS NULL
+        AND t.to_wallet_id IN (SELECT id FROM wallets WHERE user_id = $1))
 )
 AND (
     $2::timestamp = '1970-01-01 00:00:00+00'::timestamp OR 
@@ -48,3 +105,20 @@ AND (
 )
 ORDER BY t.created_at DESC, t.id DESC
 LIMIT $4;
+
+-- name: ListTransactionsToEncrypt :many
+-- External transfers keep the recipient details in provider_reference until
+-- the payout is submitted. Plaintext details are JSON objects; a provider's
+-- own reference is neither JSON nor encrypted and is left alone.
+SELECT id, provider_reference
+FROM transactions
+WHERE id > sqlc.arg(after_id)
+  AND (provider_reference LIKE '{%'
+       OR (provider_reference LIKE 'enc:%' AND NOT starts_with(provider_reference, sqlc.arg(key_prefix)::text)))
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: UpdateTransactionProviderReferenceEncryption :execrows
+UPDATE transactions
+SET provider_reference = sqlc.arg(provider_reference)
+WHERE id = sqlc.arg(id) AND provider_reference = sqlc.arg(current_provider_reference);
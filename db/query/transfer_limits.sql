@@ -0,0 +1,26 @@
+-- name: GetTransferLimit :one
+SELECT id, tier, currency, transaction_type, per_transaction_max, daily_amount_max, daily_count_max,
+       monthly_amount_max, monthly_count_max, active, created_at, updated_at
+FROM transfer_limits
+WHERE tier = $1 AND currency = $2 AND transaction_type = $3 AND active = TRUE;
+
+-- name: ListActiveTransferLimits :many
+SELECT id, tier, currency, transaction_type, per_transaction_max, daily_amount_max, daily_count_max,
+       monthly_amount_max, monthly_count_max, active, created_at, updated_at
+FROM transfer_limits
+WHERE tier = $1 AND active = TRUE
+ORDER BY currency ASC, transaction_type ASC;
+
+-- name: GetTransferUsage :one
+SELECT COUNT(*) FILTER (WHERE t.created_at >= sqlc.arg(day_start)) AS daily_count,
+       COALESCE(SUM(FLOOR(t.amount / COALESCE(t.exchange_rate, 1))) FILTER (WHERE t.created_at >= sqlc.arg(day_start)), 0)::bigint AS daily_amount,
+       COUNT(*) AS monthly_count,
+       COALESCE(SUM(FLOOR(t.amount / COALESCE(t.exchange_rate, 1))), 0)::bigint AS monthly_amount
+FROM transactions t
+JOIN wallets w ON w.id = t.from_wallet_id
+WHERE w.user_id = sqlc.arg(user_id)
+  AND w.currency = sqlc.arg(currency)
+  AND t.type = sqlc.arg(type)
+  AND t.status IN ('pending', 'completed', 'on_hold')
+  AND t.created_at >= sqlc.arg(month_start)
+  AND (t.to_wallet_id IS NULL OR t.to_wallet_id NOT IN (SELECT id FROM wallets WHERE user_id = sqlc.arg(user_id)));
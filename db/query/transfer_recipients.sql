@@ -0,0 +1,27 @@
+-- name: CreateTransferRecipient :one
+INSERT INTO transfer_recipients (id, transaction_id, account_number, bank_code, account_name, country, currency)
+VALUES (gen_random_uuid()::text, $1, $2, $3, $4, $5, $6)
+RETURNING id, transaction_id, account_number, bank_code, account_name, country, currency, created_at;
+
+-- name: GetTransferRecipientByTransactionID :one
+SELECT id, transaction_id, account_number, bank_code, account_name, country, currency, created_at
+FROM transfer_recipients
+WHERE transaction_id = $1;
+
+-- name: ListTransferRecipientsByTransactionIDs :many
+SELECT id, transaction_id, account_number, bank_code, account_name, country, currency, created_at
+FROM transfer_recipients
+WHERE transaction_id = ANY(sqlc.arg(transaction_ids)::text[]);
+
+-- name: ListTransferRecipientsToEncrypt :many
+SELECT id, account_number
+FROM transfer_recipients
+WHERE id > sqlc.arg(after_id)
+  AND NOT starts_with(account_number, sqlc.arg(key_prefix)::text)
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: UpdateTransferRecipientAccountNumberEncryption :execrows
+UPDATE transfer_recipients
+SET account_number = sqlc.arg(account_number)
+WHERE id = sqlc.arg(id) AND account_number = sqlc.arg(current_account_number);
@@ -1,4 +1,39 @@
 -- name: GetUserByID :one
-SELECT user_id, name, pin_hash, created_at, updated_at
+SELECT user_id, name, pin_hash, created_at, updated_at, tier, email, phone, country, date_of_birth, kyc_tier
 FROM users
 WHERE user_id = $1;
+
+-- name: CreateUser :one
+-- New users start at KYC tier 0 on the default transfer limit tier.
+INSERT INTO users (user_id, name, email, phone, country, date_of_birth, kyc_tier)
+VALUES ($1, $2, lower(sqlc.narg(email)), sqlc.narg(phone), sqlc.narg(country), sqlc.narg(date_of_birth), 0)
+RETURNING user_id, name, pin_hash, created_at, updated_at, tier, email, phone, country, date_of_birth, kyc_tier;
+
+-- name: UpdateUserProfile :one
+-- Fields left null keep their current value.
+UPDATE users
+SET name = COALESCE(sqlc.narg(name), name),
+    email = COALESCE(lower(sqlc.narg(email)), email),
+    phone = COALESCE(sqlc.narg(phone), phone),
+    country = COALESCE(sqlc.narg(country), country),
+    date_of_birth = COALESCE(sqlc.narg(date_of_birth), date_of_birth),
+    updated_at = NOW()
+WHERE user_id = sqlc.arg(user_id)
+RETURNING user_id, name, pin_hash, created_at, updated_at, tier, email, phone, country, date_of_birth, kyc_tier;
+
+-- name: UpdateUserKYCTier :execrows
+-- Only ever raises the KYC tier, so a late approval cannot undo a higher tier
+-- granted in the meantime.
+UPDATE users
+SET kyc_tier = sqlc.arg(kyc_tier), tier = sqlc.arg(tier), updated_at = NOW()
+WHERE user_id = sqlc.arg(user_id) AND kyc_tier < sqlc.arg(kyc_tier);
+
+-- name: SetInitialUserPIN :execrows
+UPDATE users
+SET pin_hash = sqlc.arg(pin_hash), updated_at = NOW()
+WHERE user_id = sqlc.arg(user_id) AND pin_hash IS NULL;
+
+-- name: UpdateUserPIN :exec
+UPDATE users
+SET pin_hash = sqlc.arg(pin_hash), updated_at = NOW()
+WHERE user_id = sqlc.arg(user_id);
@@ -0,0 +1,10 @@
+-- name: CreateWalletHold :one
+INSERT INTO wallet_holds (id, wallet_id, transaction_id, amount, currency, status)
+VALUES (gen_random_uuid()::text, $1, $2, $3, $4, 'active')
+RETURNING id, wallet_id, transaction_id, amount, currency, status, created_at, released_at;
+
+-- name: ReleaseWalletHold :one
+UPDATE wallet_holds
+SET status = sqlc.arg(status), released_at = NOW()
+WHERE transaction_id = sqlc.arg(transaction_id) AND status = 'active'
+RETURNING id, wallet_id, transaction_id, amount, currency, status, created_at, released_at;
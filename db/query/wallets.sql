@@ -1,27 +1,27 @@
 -- name: GetWalletByID :one
-SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at
+SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at, held_balance, status, frozen_direction, status_reason, closed_at
 FROM wallets
 WHERE id = $1;
 
 -- name: GetWalletByUserAndCurrency :one
-SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at
+SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at, held_balance, status, frozen_direction, status_reason, closed_at
 FROM wallets
-WHERE user_id = $1 AND currency = $2;
+WHERE user_id = $1 AND currency = $2 AND status <> 'closed';
 
 -- name: GetWalletByUserAndCurrencyForUpdate :one
-SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at
+SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at, held_balance, status, frozen_direction, status_reason, closed_at
 FROM wallets
-WHERE user_id = $1 AND currency = $2
+WHERE user_id = $1 AND currency = $2 AND status <> 'closed'
 FOR UPDATE;
 
 -- name: GetWalletByIDForUpdate :one
-SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at
+SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at, held_balance, status, frozen_direction, status_reason, closed_at
 FROM wallets
 WHERE id = $1
 FOR UPDATE;
 
 -- name: GetWalletByBankAccount :one
-SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at
+SELECT id, user_id, bank_account_id, currency, balance, created_at, updated_at, held_balance, status, frozen_direction, status_reason, closed_at
 FROM wallets
 WHERE bank_account_id = $1 AND bank_account_id IS NOT NULL;
 
@@ -35,6 +35,11 @@ UPDATE wallets
 SET balance = $1, updated_at = NOW()
 WHERE id = $2;
 
+-- name: AdjustWalletHeldBalance :exec
+UPDATE wallets
+SET held_balance = held_balance + sqlc.arg(delta), updated_at = NOW()
+WHERE id = sqlc.arg(id);
+
 -- name: GetUserWalletsWithBankAccounts :many
 SELECT 
     w.id,
@@ -44,6 +49,9 @@ SELECT
     w.balance,
     w.created_at,
     w.updated_at,
+    w.held_balance,
+    w.status,
+    w.frozen_direction,
     ba.account_number,
     ba.bank_name,
     ba.bank_code,
@@ -53,3 +61,17 @@ FROM wallets w
 LEFT JOIN bank_accounts ba ON w.bank_account_id = ba.id
 WHERE w.user_id = $1
 ORDER BY w.currency, w.created_at;
+
+-- name: UpdateWalletStatus :one
+-- closed_at is set when the wallet is closed and never cleared.
+UPDATE wallets
+SET status = sqlc.arg(status),
+    frozen_direction = sqlc.narg(frozen_direction),
+    status_reason = sqlc.narg(status_reason),
+    closed_at = CASE WHEN sqlc.arg(status) = 'closed' THEN NOW() ELSE closed_at END,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: NextVirtualAccountNumber :one
+SELECT nextval('virtual_account_number_seq')::bigint;
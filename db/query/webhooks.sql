@@ -3,3 +3,18 @@ INSERT INTO webhook_events (id, provider_name, event_type, provider_reference, t
 VALUES (gen_random_uuid()::text, $1, $2, $3, $4, $5)
 RETURNING *;
 
+
+-- name: ListWebhookEventsToEncrypt :many
+-- Encrypted payloads are stored as a JSON string holding the envelope;
+-- plaintext payloads are the provider's original JSON object.
+SELECT id, payload
+FROM webhook_events
+WHERE id > sqlc.arg(after_id)
+  AND (jsonb_typeof(payload) <> 'string' OR NOT starts_with(payload #>> '{}', sqlc.arg(key_prefix)::text))
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: UpdateWebhookEventPayloadEncryption :execrows
+UPDATE webhook_events
+SET payload = sqlc.arg(payload)
+WHERE id = sqlc.arg(id) AND payload = sqlc.arg(current_payload);
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	service "github.com/IfedayoAwe/payment-processing-service/services"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type AuditHandler interface {
+	ListEvents(c echo.Context) error
+	VerifyChain(c echo.Context) error
+}
+
+type auditHandler struct {
+	auditService service.AuditService
+}
+
+func newAuditHandler(auditService service.AuditService) AuditHandler {
+	return &auditHandler{
+		auditService: auditService,
+	}
+}
+
+func (ah *auditHandler) ListEvents(c echo.Context) error {
+	filter := models.AuditEventFilter{
+		EntityType: c.QueryParam("entity_type"),
+		EntityID:   c.QueryParam("entity_id"),
+		ActorID:    c.QueryParam("actor_id"),
+		Action:     c.QueryParam("action"),
+		Cursor:     c.QueryParam("cursor"),
+	}
+
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsed, err := strconv.ParseInt(limitStr, 10, 32)
+		if err != nil || parsed <= 0 {
+			return utils.BadRequest(c, "invalid limit parameter")
+		}
+		filter.Limit = int32(parsed)
+	}
+
+	from, err := parseTimeParam(c, "from")
+	if err != nil {
+		return utils.BadRequest(c, "invalid from parameter; use RFC 3339")
+	}
+	filter.From = from
+
+	to, err := parseTimeParam(c, "to")
+	if err != nil {
+		return utils.BadRequest(c, "invalid to parameter; use RFC 3339")
+	}
+	filter.To = to
+
+	page, err := ah.auditService.ListEvents(c.Request().Context(), filter)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	events := make([]*models.AuditEventResponse, 0, len(page.Events))
+	for _, event := range page.Events {
+		events = append(events, models.AuditEventToResponse(event))
+	}
+
+	response := &models.AuditEventsResponseDTO{
+		Events:     events,
+		NextCursor: page.NextCursor,
+	}
+
+	return utils.Success(c, response, "audit events retrieved successfully")
+}
+
+func (ah *auditHandler) VerifyChain(c echo.Context) error {
+	var fromSequence int64
+	if fromStr := c.QueryParam("from_sequence"); fromStr != "" {
+		parsed, err := strconv.ParseInt(fromStr, 10, 64)
+		if err != nil || parsed <= 0 {
+			return utils.BadRequest(c, "invalid from_sequence parameter")
+		}
+		fromSequence = parsed
+	}
+
+	var limit int32
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsed, err := strconv.ParseInt(limitStr, 10, 32)
+		if err != nil || parsed <= 0 {
+			return utils.BadRequest(c, "invalid limit parameter")
+		}
+		limit = int32(parsed)
+	}
+
+	result, err := ah.auditService.VerifyChain(c.Request().Context(), fromSequence, limit)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, models.AuditChainVerificationToResponse(result), "audit chain verified")
+}
+
+// parseTimeParam reads an optional RFC 3339 query parameter.
+func parseTimeParam(c echo.Context, name string) (*time.Time, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
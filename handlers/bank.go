@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	service "github.com/IfedayoAwe/payment-processing-service/services"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type BankHandler interface {
+	ListBanks(c echo.Context) error
+}
+
+type bankHandler struct {
+	bankService service.BankService
+}
+
+func newBankHandler(bankService service.BankService) BankHandler {
+	return &bankHandler{
+		bankService: bankService,
+	}
+}
+
+func (bh *bankHandler) ListBanks(c echo.Context) error {
+	search := strings.TrimSpace(c.QueryParam("search"))
+	country := strings.ToUpper(strings.TrimSpace(c.QueryParam("country")))
+	if country != "" && len(country) != 2 {
+		return utils.BadRequest(c, "country must be a 2-letter ISO 3166-1 code")
+	}
+
+	banks, err := bh.bankService.ListBanks(c.Request().Context(), search, country)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	responses := make([]*models.BankResponse, 0, len(banks))
+	for _, bank := range banks {
+		responses = append(responses, models.BankToResponse(bank))
+	}
+
+	return utils.Success(c, responses, "banks retrieved successfully")
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"github.com/IfedayoAwe/payment-processing-service/handlers/requests"
+	"github.com/IfedayoAwe/payment-processing-service/middleware"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	service "github.com/IfedayoAwe/payment-processing-service/services"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type BeneficiaryHandler interface {
+	CreateBeneficiary(c echo.Context) error
+	ListBeneficiaries(c echo.Context) error
+	GetBeneficiary(c echo.Context) error
+	UpdateBeneficiary(c echo.Context) error
+	DeleteBeneficiary(c echo.Context) error
+}
+
+type beneficiaryHandler struct {
+	beneficiaryService service.BeneficiaryService
+}
+
+func newBeneficiaryHandler(beneficiaryService service.BeneficiaryService) BeneficiaryHandler {
+	return &beneficiaryHandler{
+		beneficiaryService: beneficiaryService,
+	}
+}
+
+func (bh *beneficiaryHandler) CreateBeneficiary(c echo.Context) error {
+	var req requests.CreateBeneficiaryRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	userID := middleware.GetUserID(c)
+
+	beneficiary, err := bh.beneficiaryService.CreateBeneficiary(c.Request().Context(), userID, req.Nickname, req.AccountNumber, req.BankCode)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Created(c, models.BeneficiaryToResponse(beneficiary), "beneficiary saved successfully")
+}
+
+func (bh *beneficiaryHandler) ListBeneficiaries(c echo.Context) error {
+	userID := middleware.GetUserID(c)
+
+	beneficiaries, err := bh.beneficiaryService.ListBeneficiaries(c.Request().Context(), userID)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	responses := make([]*models.BeneficiaryResponse, 0, len(beneficiaries))
+	for _, beneficiary := range beneficiaries {
+		responses = append(responses, models.BeneficiaryToResponse(beneficiary))
+	}
+
+	return utils.Success(c, responses, "beneficiaries retrieved successfully")
+}
+
+func (bh *beneficiaryHandler) GetBeneficiary(c echo.Context) error {
+	beneficiaryID := c.Param("id")
+	if beneficiaryID == "" {
+		return utils.BadRequest(c, "beneficiary ID is required")
+	}
+
+	userID := middleware.GetUserID(c)
+
+	beneficiary, err := bh.beneficiaryService.GetBeneficiary(c.Request().Context(), userID, beneficiaryID)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, models.BeneficiaryToResponse(beneficiary), "beneficiary retrieved successfully")
+}
+
+func (bh *beneficiaryHandler) UpdateBeneficiary(c echo.Context) error {
+	beneficiaryID := c.Param("id")
+	if beneficiaryID == "" {
+		return utils.BadRequest(c, "beneficiary ID is required")
+	}
+
+	var req requests.UpdateBeneficiaryRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	userID := middleware.GetUserID(c)
+
+	beneficiary, err := bh.beneficiaryService.UpdateBeneficiary(c.Request().Context(), userID, beneficiaryID, models.BeneficiaryUpdate{
+		Nickname:      req.Nickname,
+		AccountNumber: req.AccountNumber,
+		BankCode:      req.BankCode,
+	})
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, models.BeneficiaryToResponse(beneficiary), "beneficiary updated successfully")
+}
+
+func (bh *beneficiaryHandler) DeleteBeneficiary(c echo.Context) error {
+	beneficiaryID := c.Param("id")
+	if beneficiaryID == "" {
+		return utils.BadRequest(c, "beneficiary ID is required")
+	}
+
+	userID := middleware.GetUserID(c)
+
+	if err := bh.beneficiaryService.DeleteBeneficiary(c.Request().Context(), userID, beneficiaryID); err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, map[string]string{"id": beneficiaryID}, "beneficiary deleted successfully")
+}
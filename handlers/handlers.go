@@ -5,19 +5,52 @@ import (
 )
 
 type Handlers struct {
-	Payment     PaymentHandler
-	NameEnquiry NameEnquiryHandler
-	Webhook     WebhookHandler
+	Payment        PaymentHandler
+	Wallet         WalletHandler
+	Refund         RefundHandler
+	PIN            PINHandler
+	TOTP           TOTPHandler
+	Limit          LimitHandler
+	Risk           RiskHandler
+	Audit          AuditHandler
+	NameEnquiry    NameEnquiryHandler
+	Beneficiary    BeneficiaryHandler
+	Bank           BankHandler
+	User           UserHandler
+	PaymentRequest PaymentRequestHandler
+	Webhook        WebhookHandler
 }
 
 func NewHandlers(services *service.Services) *Handlers {
-	paymentHandler := newPaymentHandler(services.Payment, services.Wallet, services.Queries)
+	paymentHandler := newPaymentHandler(services.Payment, services.Wallet, services.Refund, services.Queries)
+	walletHandler := newWalletHandler(services.Wallet)
+	refundHandler := newRefundHandler(services.Refund)
+	pinHandler := newPINHandler(services.PIN)
+	totpHandler := newTOTPHandler(services.TOTP)
+	limitHandler := newLimitHandler(services.Limit)
+	riskHandler := newRiskHandler(services.Risk, services.Payment)
+	auditHandler := newAuditHandler(services.Audit)
 	nameEnquiryHandler := newNameEnquiryHandler(services.NameEnquiry)
+	beneficiaryHandler := newBeneficiaryHandler(services.Beneficiary)
+	bankHandler := newBankHandler(services.Bank)
+	userHandler := newUserHandler(services.User)
+	paymentRequestHandler := newPaymentRequestHandler(services.PaymentRequest)
 	webhookHandler := newWebhookHandler(services.Queue)
 
 	return &Handlers{
-		Payment:     paymentHandler,
-		NameEnquiry: nameEnquiryHandler,
-		Webhook:     webhookHandler,
+		Payment:        paymentHandler,
+		Wallet:         walletHandler,
+		Refund:         refundHandler,
+		PIN:            pinHandler,
+		TOTP:           totpHandler,
+		Limit:          limitHandler,
+		Risk:           riskHandler,
+		Audit:          auditHandler,
+		NameEnquiry:    nameEnquiryHandler,
+		Beneficiary:    beneficiaryHandler,
+		Bank:           bankHandler,
+		User:           userHandler,
+		PaymentRequest: paymentRequestHandler,
+		Webhook:        webhookHandler,
 	}
 }
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"github.com/IfedayoAwe/payment-processing-service/middleware"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	service "github.com/IfedayoAwe/payment-processing-service/services"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type LimitHandler interface {
+	GetLimits(c echo.Context) error
+}
+
+type limitHandler struct {
+	limitService service.LimitService
+}
+
+func newLimitHandler(limitService service.LimitService) LimitHandler {
+	return &limitHandler{
+		limitService: limitService,
+	}
+}
+
+func (lh *limitHandler) GetLimits(c echo.Context) error {
+	userID := middleware.GetUserID(c)
+
+	limits, err := lh.limitService.GetUserLimits(c.Request().Context(), userID)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, models.UserLimitsToResponse(limits), "limits retrieved successfully")
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"math"
+
 	"github.com/IfedayoAwe/payment-processing-service/handlers/requests"
 	"github.com/IfedayoAwe/payment-processing-service/models"
 	service "github.com/IfedayoAwe/payment-processing-service/services"
@@ -37,9 +39,19 @@ func (neh *nameEnquiryHandler) EnquireAccountName(c echo.Context) error {
 		return utils.HandleError(c, err)
 	}
 
-	return utils.Success(c, models.NameEnquiryResponse{
+	response := models.NameEnquiryResponse{
 		AccountName: result.AccountName,
 		IsInternal:  result.IsInternal,
 		Currency:    result.Currency.String(),
-	}, "account name retrieved successfully")
+		Country:     result.Country,
+	}
+	if req.AccountName != "" {
+		match := neh.nameEnquiryService.MatchAccountName(req.AccountName, result.AccountName)
+		response.NameMatch = &models.NameMatchResponse{
+			Score:   math.Round(match.Score*100) / 100,
+			Verdict: string(match.Verdict),
+		}
+	}
+
+	return utils.Success(c, response, "account name retrieved successfully")
 }
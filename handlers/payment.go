@@ -17,6 +17,8 @@ type PaymentHandler interface {
 	CreateInternalTransfer(c echo.Context) error
 	CreateExternalTransfer(c echo.Context) error
 	ConfirmTransaction(c echo.Context) error
+	CancelTransaction(c echo.Context) error
+	ReverseTransaction(c echo.Context) error
 	GetTransaction(c echo.Context) error
 	GetTransactionHistory(c echo.Context) error
 	GetExchangeRate(c echo.Context) error
@@ -27,13 +29,15 @@ type PaymentHandler interface {
 type paymentHandler struct {
 	paymentService service.PaymentService
 	walletService  service.WalletService
+	refundService  service.RefundService
 	queries        *gen.Queries
 }
 
-func newPaymentHandler(paymentService service.PaymentService, walletService service.WalletService, queries *gen.Queries) PaymentHandler {
+func newPaymentHandler(paymentService service.PaymentService, walletService service.WalletService, refundService service.RefundService, queries *gen.Queries) PaymentHandler {
 	return &paymentHandler{
 		paymentService: paymentService,
 		walletService:  walletService,
+		refundService:  refundService,
 		queries:        queries,
 	}
 }
@@ -65,7 +69,7 @@ func (ph *paymentHandler) CreateInternalTransfer(c echo.Context) error {
 		return utils.BadRequest(c, "Idempotency-Key header is required")
 	}
 
-	transaction, err := ph.paymentService.CreateInternalTransfer(c.Request().Context(), fromUserID, req.ToAccountNumber, req.ToBankCode, fromCurrency, toAmount, idempotencyKey)
+	transaction, err := ph.paymentService.CreateInternalTransfer(c.Request().Context(), fromUserID, req.ToAccountNumber, req.ToBankCode, req.ToAccountName, req.BeneficiaryID, fromCurrency, toAmount, idempotencyKey)
 	if err != nil {
 		return utils.HandleError(c, err)
 	}
@@ -99,7 +103,7 @@ func (ph *paymentHandler) ConfirmTransaction(c echo.Context) error {
 
 	userID := middleware.GetUserID(c)
 
-	transaction, err := ph.paymentService.ConfirmTransaction(c.Request().Context(), transactionID, userID, req.PIN)
+	transaction, err := ph.paymentService.ConfirmTransaction(c.Request().Context(), transactionID, userID, req.PIN, req.TOTPCode)
 	if err != nil {
 		return utils.HandleError(c, err)
 	}
@@ -109,21 +113,81 @@ func (ph *paymentHandler) ConfirmTransaction(c echo.Context) error {
 		return utils.Success(c, response, "transaction confirmed and completed successfully")
 	}
 
+	if transaction.Status == models.TransactionStatusOnHold {
+		return utils.Success(c, response, "transaction held for review")
+	}
+
 	return utils.Success(c, response, "transaction confirmed and queued for processing")
 }
 
+func (ph *paymentHandler) CancelTransaction(c echo.Context) error {
+	transactionID := c.Param("id")
+	if transactionID == "" {
+		return utils.BadRequest(c, "transaction ID is required")
+	}
+
+	userID := middleware.GetUserID(c)
+
+	transaction, err := ph.paymentService.CancelTransaction(c.Request().Context(), transactionID, userID)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	response := models.TransactionToResponse(transaction)
+	return utils.Success(c, response, "transaction cancelled successfully")
+}
+
+func (ph *paymentHandler) ReverseTransaction(c echo.Context) error {
+	transactionID := c.Param("id")
+	if transactionID == "" {
+		return utils.BadRequest(c, "transaction ID is required")
+	}
+
+	var req requests.ReverseTransactionRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	idempotencyKey := c.Request().Header.Get("Idempotency-Key")
+	if idempotencyKey == "" {
+		return utils.BadRequest(c, "Idempotency-Key header is required")
+	}
+
+	adminUserID := middleware.GetUserID(c)
+
+	reversal, err := ph.paymentService.ReverseTransaction(c.Request().Context(), transactionID, adminUserID, req.Reason, idempotencyKey)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	response := models.TransactionToResponse(reversal)
+	return utils.Created(c, response, "transaction reversed successfully")
+}
+
 func (ph *paymentHandler) GetTransaction(c echo.Context) error {
 	transactionID := c.Param("id")
 	if transactionID == "" {
 		return utils.BadRequest(c, "transaction ID is required")
 	}
 
-	transaction, err := ph.paymentService.GetTransactionByID(c.Request().Context(), transactionID)
+	transaction, err := ph.paymentService.GetTransactionByID(c.Request().Context(), transactionID, middleware.GetUserID(c), middleware.IsAdmin(c))
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	refunds, err := ph.refundService.ListRefunds(c.Request().Context(), transaction.ID)
 	if err != nil {
 		return utils.HandleError(c, err)
 	}
 
 	response := models.TransactionToResponse(transaction)
+	for _, refund := range refunds {
+		response.Refunds = append(response.Refunds, models.RefundToResponse(refund))
+	}
 	return utils.Success(c, response, "transaction retrieved successfully")
 }
 
@@ -137,6 +201,10 @@ func (ph *paymentHandler) CreateExternalTransfer(c echo.Context) error {
 		return utils.ValidationError(c, utils.FormatValidationErrors(err))
 	}
 
+	if err := req.Validate(); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
 	userID := middleware.GetUserID(c)
 
 	fromCurrency, err := money.ParseCurrency(req.FromCurrency)
@@ -154,7 +222,7 @@ func (ph *paymentHandler) CreateExternalTransfer(c echo.Context) error {
 		return utils.BadRequest(c, "Idempotency-Key header is required")
 	}
 
-	transaction, err := ph.paymentService.CreateExternalTransfer(c.Request().Context(), userID, req.ToAccountNumber, req.ToBankCode, fromCurrency, toAmount, idempotencyKey)
+	transaction, err := ph.paymentService.CreateExternalTransfer(c.Request().Context(), userID, req.ToAccountNumber, req.ToBankCode, req.ToAccountName, req.BeneficiaryID, fromCurrency, toAmount, idempotencyKey)
 	if err != nil {
 		return utils.HandleError(c, err)
 	}
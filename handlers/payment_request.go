@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/IfedayoAwe/payment-processing-service/handlers/requests"
+	"github.com/IfedayoAwe/payment-processing-service/middleware"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	service "github.com/IfedayoAwe/payment-processing-service/services"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type PaymentRequestHandler interface {
+	CreatePaymentRequest(c echo.Context) error
+	ListPaymentRequests(c echo.Context) error
+	CancelPaymentRequest(c echo.Context) error
+	GetPaymentLink(c echo.Context) error
+	PayPaymentLink(c echo.Context) error
+}
+
+type paymentRequestHandler struct {
+	paymentRequestService service.PaymentRequestService
+}
+
+func newPaymentRequestHandler(paymentRequestService service.PaymentRequestService) PaymentRequestHandler {
+	return &paymentRequestHandler{
+		paymentRequestService: paymentRequestService,
+	}
+}
+
+func (prh *paymentRequestHandler) CreatePaymentRequest(c echo.Context) error {
+	var req requests.CreatePaymentRequestRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	amount, err := req.Amount.ToMoney()
+	if err != nil {
+		return utils.BadRequest(c, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+
+	request, err := prh.paymentRequestService.CreatePaymentRequest(c.Request().Context(), userID, models.NewPaymentRequest{
+		Amount:    amount,
+		Memo:      req.Memo,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Created(c, models.PaymentRequestToResponse(request, userID), "payment request created successfully")
+}
+
+func (prh *paymentRequestHandler) ListPaymentRequests(c echo.Context) error {
+	limit := int32(20)
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsed, err := strconv.ParseInt(limitStr, 10, 32)
+		if err != nil {
+			return utils.BadRequest(c, "invalid limit parameter")
+		}
+		limit = int32(parsed)
+	}
+
+	userID := middleware.GetUserID(c)
+
+	paymentRequests, err := prh.paymentRequestService.ListPaymentRequests(c.Request().Context(), userID, limit)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	response := make([]*models.PaymentRequestResponse, 0, len(paymentRequests))
+	for _, request := range paymentRequests {
+		response = append(response, models.PaymentRequestToResponse(request, userID))
+	}
+
+	return utils.Success(c, response, "payment requests retrieved successfully")
+}
+
+func (prh *paymentRequestHandler) CancelPaymentRequest(c echo.Context) error {
+	requestID := c.Param("id")
+	if requestID == "" {
+		return utils.BadRequest(c, "payment request ID is required")
+	}
+
+	userID := middleware.GetUserID(c)
+
+	request, err := prh.paymentRequestService.CancelPaymentRequest(c.Request().Context(), requestID, userID)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, models.PaymentRequestToResponse(request, userID), "payment request cancelled successfully")
+}
+
+func (prh *paymentRequestHandler) GetPaymentLink(c echo.Context) error {
+	token := c.Param("token")
+	if token == "" {
+		return utils.BadRequest(c, "payment link token is required")
+	}
+
+	request, err := prh.paymentRequestService.GetPaymentRequestByToken(c.Request().Context(), token)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	userID := middleware.GetUserID(c)
+
+	return utils.Success(c, models.PaymentRequestToResponse(request, userID), "payment request retrieved successfully")
+}
+
+func (prh *paymentRequestHandler) PayPaymentLink(c echo.Context) error {
+	token := c.Param("token")
+	if token == "" {
+		return utils.BadRequest(c, "payment link token is required")
+	}
+
+	var req requests.PayPaymentRequestRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	fromCurrency, err := money.ParseCurrency(req.FromCurrency)
+	if err != nil {
+		return utils.BadRequest(c, "invalid from currency")
+	}
+
+	idempotencyKey := c.Request().Header.Get("Idempotency-Key")
+	if idempotencyKey == "" {
+		return utils.BadRequest(c, "Idempotency-Key header is required")
+	}
+
+	payerID := middleware.GetUserID(c)
+
+	transaction, err := prh.paymentRequestService.PayPaymentRequest(c.Request().Context(), token, payerID, fromCurrency, idempotencyKey)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Created(c, models.TransactionToResponse(transaction), "transfer initiated, please confirm with PIN")
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"github.com/IfedayoAwe/payment-processing-service/handlers/requests"
+	"github.com/IfedayoAwe/payment-processing-service/middleware"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	service "github.com/IfedayoAwe/payment-processing-service/services"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type PINHandler interface {
+	SetPIN(c echo.Context) error
+	ChangePIN(c echo.Context) error
+	ResetPIN(c echo.Context) error
+	UnlockPIN(c echo.Context) error
+	CreatePINResetToken(c echo.Context) error
+}
+
+type pinHandler struct {
+	pinService service.PINService
+}
+
+func newPINHandler(pinService service.PINService) PINHandler {
+	return &pinHandler{
+		pinService: pinService,
+	}
+}
+
+func (ph *pinHandler) SetPIN(c echo.Context) error {
+	var req requests.SetPINRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	userID := middleware.GetUserID(c)
+
+	if err := ph.pinService.SetPIN(c.Request().Context(), userID, req.PIN); err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Created(c, map[string]string{"user_id": userID}, "PIN set successfully")
+}
+
+func (ph *pinHandler) ChangePIN(c echo.Context) error {
+	var req requests.ChangePINRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	userID := middleware.GetUserID(c)
+
+	if err := ph.pinService.ChangePIN(c.Request().Context(), userID, req.OldPIN, req.NewPIN); err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, map[string]string{"user_id": userID}, "PIN changed successfully")
+}
+
+func (ph *pinHandler) ResetPIN(c echo.Context) error {
+	var req requests.ResetPINRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	userID := middleware.GetUserID(c)
+
+	if err := ph.pinService.ResetPIN(c.Request().Context(), userID, req.Token, req.NewPIN); err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, map[string]string{"user_id": userID}, "PIN reset successfully")
+}
+
+func (ph *pinHandler) CreatePINResetToken(c echo.Context) error {
+	userID := c.Param("id")
+	if userID == "" {
+		return utils.BadRequest(c, "user ID is required")
+	}
+
+	adminUserID := middleware.GetUserID(c)
+
+	token, err := ph.pinService.CreatePINResetToken(c.Request().Context(), userID, adminUserID)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Created(c, models.PINResetTokenToResponse(token), "PIN reset token created successfully")
+}
+
+func (ph *pinHandler) UnlockPIN(c echo.Context) error {
+	userID := c.Param("id")
+	if userID == "" {
+		return utils.BadRequest(c, "user ID is required")
+	}
+
+	adminUserID := middleware.GetUserID(c)
+
+	if err := ph.pinService.UnlockPIN(c.Request().Context(), userID, adminUserID); err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, map[string]string{"user_id": userID}, "PIN unlocked successfully")
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"github.com/IfedayoAwe/payment-processing-service/handlers/requests"
+	"github.com/IfedayoAwe/payment-processing-service/middleware"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	service "github.com/IfedayoAwe/payment-processing-service/services"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type RefundHandler interface {
+	CreateRefund(c echo.Context) error
+}
+
+type refundHandler struct {
+	refundService service.RefundService
+}
+
+func newRefundHandler(refundService service.RefundService) RefundHandler {
+	return &refundHandler{
+		refundService: refundService,
+	}
+}
+
+func (rh *refundHandler) CreateRefund(c echo.Context) error {
+	transactionID := c.Param("id")
+	if transactionID == "" {
+		return utils.BadRequest(c, "transaction ID is required")
+	}
+
+	var req requests.CreateRefundRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	amount, err := req.Amount.ToMoney()
+	if err != nil {
+		return utils.BadRequest(c, err.Error())
+	}
+
+	idempotencyKey := c.Request().Header.Get("Idempotency-Key")
+	if idempotencyKey == "" {
+		return utils.BadRequest(c, "Idempotency-Key header is required")
+	}
+
+	adminUserID := middleware.GetUserID(c)
+
+	refund, err := rh.refundService.CreateRefund(c.Request().Context(), transactionID, adminUserID, amount, req.Reason, idempotencyKey)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Created(c, models.RefundToResponse(refund), "refund created successfully")
+}
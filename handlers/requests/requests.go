@@ -2,7 +2,9 @@ package requests
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/IfedayoAwe/payment-processing-service/pkg/bankaccount"
 	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
 	"github.com/IfedayoAwe/payment-processing-service/utils"
 )
@@ -21,31 +23,156 @@ func (a *AmountRequest) ToMoney() (money.Money, error) {
 	return money.FromMajorUnits(a.Amount, currency), nil
 }
 
+// A transfer names its recipient either by account number and bank code or
+// by a saved beneficiary_id. An optional to_account_name is checked against
+// the name on the account.
 type CreateInternalTransferRequest struct {
 	FromCurrency    string        `json:"from_currency" validate:"required,oneof=USD EUR GBP"`
-	ToAccountNumber string        `json:"to_account_number" validate:"required"`
-	ToBankCode      string        `json:"to_bank_code" validate:"required"`
+	ToAccountNumber string        `json:"to_account_number" validate:"required_without=BeneficiaryID"`
+	ToBankCode      string        `json:"to_bank_code" validate:"required_without=BeneficiaryID"`
+	ToAccountName   string        `json:"to_account_name" validate:"max=200"`
+	BeneficiaryID   string        `json:"beneficiary_id"`
 	Amount          AmountRequest `json:"amount" validate:"required"`
 }
 
 type CreateExternalTransferRequest struct {
-	ToAccountNumber string        `json:"to_account_number" validate:"required"`
-	ToBankCode      string        `json:"to_bank_code" validate:"required"`
+	ToAccountNumber string        `json:"to_account_number" validate:"required_without=BeneficiaryID"`
+	ToBankCode      string        `json:"to_bank_code" validate:"required_without=BeneficiaryID"`
+	ToAccountName   string        `json:"to_account_name" validate:"max=200"`
+	BeneficiaryID   string        `json:"beneficiary_id"`
 	FromCurrency    string        `json:"from_currency" validate:"required,oneof=USD EUR GBP"`
 	Amount          AmountRequest `json:"amount" validate:"required"`
 }
 
+// Validate checks to_account_number against the account format of the
+// corridor the amount is paid out in. See bankaccount.Validate.
+func (r *CreateExternalTransferRequest) Validate() error {
+	if r.ToAccountNumber == "" {
+		return nil
+	}
+	currency, err := money.ParseCurrency(r.Amount.Currency)
+	if err != nil {
+		// The amount's currency tag reports this.
+		return nil
+	}
+	if err := bankaccount.Validate(currency, r.ToAccountNumber); err != nil {
+		return &utils.FieldError{Field: "ToAccountNumber", Err: err}
+	}
+	return nil
+}
+
+type CreateBeneficiaryRequest struct {
+	Nickname      string `json:"nickname" validate:"max=64"`
+	AccountNumber string `json:"account_number" validate:"required"`
+	BankCode      string `json:"bank_code" validate:"required"`
+}
+
+// UpdateBeneficiaryRequest changes only the fields present in the body.
+type UpdateBeneficiaryRequest struct {
+	Nickname      *string `json:"nickname" validate:"omitempty,max=64"`
+	AccountNumber *string `json:"account_number"`
+	BankCode      *string `json:"bank_code"`
+}
+
+// CreatePaymentRequestRequest asks for money. ExpiresAt is RFC 3339; without
+// it the request runs for the configured default.
+type CreatePaymentRequestRequest struct {
+	Amount    AmountRequest `json:"amount" validate:"required"`
+	Memo      *string       `json:"memo" validate:"omitempty,max=140"`
+	ExpiresAt *time.Time    `json:"expires_at"`
+}
+
+type PayPaymentRequestRequest struct {
+	FromCurrency string `json:"from_currency" validate:"required,oneof=USD EUR GBP"`
+}
+
 type NameEnquiryRequest struct {
 	AccountNumber string `json:"account_number" validate:"required"`
 	BankCode      string `json:"bank_code" validate:"required"`
+	AccountName   string `json:"account_name" validate:"max=200"`
 }
 
 type ConfirmTransactionRequest struct {
+	PIN      string `json:"pin" validate:"required_without=TOTPCode"`
+	TOTPCode string `json:"totp_code"`
+}
+
+type ReverseTransactionRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+type OpenWalletRequest struct {
+	Currency string `json:"currency" validate:"required,oneof=USD EUR GBP"`
+}
+
+type FreezeWalletRequest struct {
+	Direction string `json:"direction" validate:"required,oneof=debits credits both"`
+	Reason    string `json:"reason" validate:"required,max=255"`
+}
+
+// WalletStatusRequest carries the reason for unfreezing or closing a wallet.
+type WalletStatusRequest struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+type RegisterUserRequest struct {
+	Name        string `json:"name" validate:"required,max=200"`
+	Email       string `json:"email" validate:"omitempty,email,max=254"`
+	Phone       string `json:"phone" validate:"omitempty,e164"`
+	Country     string `json:"country" validate:"omitempty,iso3166_1_alpha2"`
+	DateOfBirth string `json:"date_of_birth" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// UpdateUserProfileRequest changes only the fields present in the body.
+type UpdateUserProfileRequest struct {
+	Name        *string `json:"name" validate:"omitempty,min=1,max=200"`
+	Email       *string `json:"email" validate:"omitempty,email,max=254"`
+	Phone       *string `json:"phone" validate:"omitempty,e164"`
+	Country     *string `json:"country" validate:"omitempty,iso3166_1_alpha2"`
+	DateOfBirth *string `json:"date_of_birth" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type SubmitKYCDocumentRequest struct {
+	DocumentType   string `json:"document_type" validate:"required,oneof=passport national_id drivers_license utility_bill bank_statement"`
+	DocumentNumber string `json:"document_number" validate:"required,max=64"`
+	IssuingCountry string `json:"issuing_country" validate:"required,iso3166_1_alpha2"`
+}
+
+type ReviewKYCDocumentRequest struct {
+	Decision string `json:"decision" validate:"required,oneof=approved rejected"`
+	Note     string `json:"note" validate:"max=255"`
+}
+
+type ReviewTransactionRequest struct {
+	Decision string `json:"decision" validate:"required,oneof=approved rejected"`
+	Note     string `json:"note" validate:"max=255"`
+}
+
+type CreateRefundRequest struct {
+	Amount AmountRequest `json:"amount" validate:"required"`
+	Reason string        `json:"reason" validate:"required,max=255"`
+}
+
+type SetPINRequest struct {
 	PIN string `json:"pin" validate:"required"`
 }
 
+type ChangePINRequest struct {
+	OldPIN string `json:"old_pin" validate:"required"`
+	NewPIN string `json:"new_pin" validate:"required"`
+}
+
+type TOTPCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type ResetPINRequest struct {
+	Token  string `json:"token" validate:"required"`
+	NewPIN string `json:"new_pin" validate:"required"`
+}
+
 func (c *ConfirmTransactionRequest) Validate() error {
-	if !utils.IsValidPIN(c.PIN) {
+	if c.PIN != "" && !utils.IsValidPIN(c.PIN) {
 		return fmt.Errorf("PIN must be exactly 5 numeric digits")
 	}
 	return nil
@@ -1,8 +1,13 @@
 package requests
 
+// Credit notices (event_type credit.received) also name the virtual account
+// that was paid into and the amount received.
 type WebhookRequest struct {
-	EventType     string  `json:"event_type" validate:"required"`
-	Reference     string  `json:"reference"`
-	TransactionID *string `json:"transaction_id"`
-	Status        string  `json:"status"`
+	EventType     string         `json:"event_type" validate:"required"`
+	Reference     string         `json:"reference"`
+	TransactionID *string        `json:"transaction_id"`
+	Status        string         `json:"status"`
+	AccountNumber string         `json:"account_number" validate:"required_if=EventType credit.received"`
+	BankCode      string         `json:"bank_code" validate:"required_if=EventType credit.received"`
+	Amount        *AmountRequest `json:"amount" validate:"required_if=EventType credit.received"`
 }
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/IfedayoAwe/payment-processing-service/handlers/requests"
+	"github.com/IfedayoAwe/payment-processing-service/middleware"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	service "github.com/IfedayoAwe/payment-processing-service/services"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type RiskHandler interface {
+	ListPendingReviews(c echo.Context) error
+	ReviewTransaction(c echo.Context) error
+}
+
+type riskHandler struct {
+	riskService    service.RiskService
+	paymentService service.PaymentService
+}
+
+func newRiskHandler(riskService service.RiskService, paymentService service.PaymentService) RiskHandler {
+	return &riskHandler{
+		riskService:    riskService,
+		paymentService: paymentService,
+	}
+}
+
+func (rh *riskHandler) ListPendingReviews(c echo.Context) error {
+	limit := int32(20)
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsed, err := strconv.ParseInt(limitStr, 10, 32)
+		if err != nil {
+			return utils.BadRequest(c, "invalid limit parameter")
+		}
+		limit = int32(parsed)
+	}
+
+	assessments, err := rh.riskService.ListPendingReviews(c.Request().Context(), limit)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	response := make([]*models.RiskAssessmentResponse, 0, len(assessments))
+	for _, assessment := range assessments {
+		response = append(response, models.RiskAssessmentToResponse(assessment))
+	}
+
+	return utils.Success(c, response, "pending reviews retrieved successfully")
+}
+
+func (rh *riskHandler) ReviewTransaction(c echo.Context) error {
+	transactionID := c.Param("id")
+	if transactionID == "" {
+		return utils.BadRequest(c, "transaction ID is required")
+	}
+
+	var req requests.ReviewTransactionRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	adminUserID := middleware.GetUserID(c)
+
+	transaction, err := rh.paymentService.ReviewTransaction(c.Request().Context(), transactionID, adminUserID, models.RiskReviewDecision(req.Decision), req.Note)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, models.TransactionToResponse(transaction), "transaction review recorded successfully")
+}
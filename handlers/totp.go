@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"github.com/IfedayoAwe/payment-processing-service/handlers/requests"
+	"github.com/IfedayoAwe/payment-processing-service/middleware"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	service "github.com/IfedayoAwe/payment-processing-service/services"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type TOTPHandler interface {
+	Enroll(c echo.Context) error
+	Activate(c echo.Context) error
+	Disable(c echo.Context) error
+}
+
+type totpHandler struct {
+	totpService service.TOTPService
+}
+
+func newTOTPHandler(totpService service.TOTPService) TOTPHandler {
+	return &totpHandler{
+		totpService: totpService,
+	}
+}
+
+func (th *totpHandler) Enroll(c echo.Context) error {
+	userID := middleware.GetUserID(c)
+
+	enrollment, err := th.totpService.Enroll(c.Request().Context(), userID)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Created(c, models.TOTPEnrollmentToResponse(enrollment), "scan the provisioning URI and verify a code to enable TOTP")
+}
+
+func (th *totpHandler) Activate(c echo.Context) error {
+	var req requests.TOTPCodeRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	userID := middleware.GetUserID(c)
+
+	codes, err := th.totpService.Activate(c.Request().Context(), userID, req.Code)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, map[string]interface{}{
+		"user_id":        userID,
+		"recovery_codes": codes,
+	}, "TOTP enabled successfully; store the recovery codes somewhere safe")
+}
+
+func (th *totpHandler) Disable(c echo.Context) error {
+	var req requests.TOTPCodeRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	userID := middleware.GetUserID(c)
+
+	if err := th.totpService.Disable(c.Request().Context(), userID, req.Code); err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, map[string]string{"user_id": userID}, "TOTP disabled successfully")
+}
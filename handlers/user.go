@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/handlers/requests"
+	"github.com/IfedayoAwe/payment-processing-service/middleware"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	service "github.com/IfedayoAwe/payment-processing-service/services"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type UserHandler interface {
+	RegisterUser(c echo.Context) error
+	GetProfile(c echo.Context) error
+	UpdateProfile(c echo.Context) error
+	SubmitKYCDocument(c echo.Context) error
+	ListKYCDocuments(c echo.Context) error
+	ReviewKYCDocument(c echo.Context) error
+}
+
+type userHandler struct {
+	userService service.UserService
+}
+
+func newUserHandler(userService service.UserService) UserHandler {
+	return &userHandler{
+		userService: userService,
+	}
+}
+
+func (uh *userHandler) RegisterUser(c echo.Context) error {
+	var req requests.RegisterUserRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	profile, err := profileUpdate(&req.Name, optional(req.Email), optional(req.Phone), optional(req.Country), optional(req.DateOfBirth))
+	if err != nil {
+		return utils.BadRequest(c, "invalid date_of_birth")
+	}
+
+	userID := middleware.GetUserID(c)
+
+	user, err := uh.userService.RegisterUser(c.Request().Context(), userID, profile)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Created(c, models.UserProfileToResponse(user), "user registered successfully")
+}
+
+func (uh *userHandler) GetProfile(c echo.Context) error {
+	userID := middleware.GetUserID(c)
+
+	user, err := uh.userService.GetProfile(c.Request().Context(), userID)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, models.UserProfileToResponse(user), "profile retrieved successfully")
+}
+
+func (uh *userHandler) UpdateProfile(c echo.Context) error {
+	var req requests.UpdateUserProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	update, err := profileUpdate(req.Name, req.Email, req.Phone, req.Country, req.DateOfBirth)
+	if err != nil {
+		return utils.BadRequest(c, "invalid date_of_birth")
+	}
+
+	userID := middleware.GetUserID(c)
+
+	user, err := uh.userService.UpdateProfile(c.Request().Context(), userID, update)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, models.UserProfileToResponse(user), "profile updated successfully")
+}
+
+func (uh *userHandler) SubmitKYCDocument(c echo.Context) error {
+	var req requests.SubmitKYCDocumentRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	userID := middleware.GetUserID(c)
+
+	document, err := uh.userService.SubmitKYCDocument(c.Request().Context(), userID, models.KYCSubmission{
+		DocumentType:   req.DocumentType,
+		DocumentNumber: req.DocumentNumber,
+		IssuingCountry: req.IssuingCountry,
+	})
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Created(c, models.KYCDocumentToResponse(document), "document submitted for review")
+}
+
+func (uh *userHandler) ListKYCDocuments(c echo.Context) error {
+	status := models.KYCDocumentPending
+	if statusStr := c.QueryParam("status"); statusStr != "" {
+		status = models.KYCDocumentStatus(statusStr)
+		if status != models.KYCDocumentPending && status != models.KYCDocumentApproved && status != models.KYCDocumentRejected {
+			return utils.BadRequest(c, "invalid status parameter")
+		}
+	}
+
+	limit := int32(50)
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsed, err := strconv.ParseInt(limitStr, 10, 32)
+		if err != nil {
+			return utils.BadRequest(c, "invalid limit parameter")
+		}
+		limit = int32(parsed)
+	}
+
+	documents, err := uh.userService.ListKYCDocuments(c.Request().Context(), status, limit)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	response := make([]*models.KYCDocumentResponse, 0, len(documents))
+	for _, document := range documents {
+		response = append(response, models.KYCDocumentToResponse(document))
+	}
+
+	return utils.Success(c, response, "kyc documents retrieved successfully")
+}
+
+func (uh *userHandler) ReviewKYCDocument(c echo.Context) error {
+	documentID := c.Param("id")
+	if documentID == "" {
+		return utils.BadRequest(c, "document ID is required")
+	}
+
+	var req requests.ReviewKYCDocumentRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	adminUserID := middleware.GetUserID(c)
+
+	document, err := uh.userService.ReviewKYCDocument(c.Request().Context(), documentID, adminUserID, models.KYCDocumentStatus(req.Decision), req.Note)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, models.KYCDocumentToResponse(document), "kyc review recorded successfully")
+}
+
+// profileUpdate builds the service's view of a profile request. The date of
+// birth has already passed validation, so a parse error is not expected.
+func profileUpdate(name, email, phone, country, dateOfBirth *string) (models.UserProfileUpdate, error) {
+	update := models.UserProfileUpdate{
+		Name:    name,
+		Email:   email,
+		Phone:   phone,
+		Country: country,
+	}
+	if dateOfBirth != nil {
+		parsed, err := time.Parse(time.DateOnly, *dateOfBirth)
+		if err != nil {
+			return models.UserProfileUpdate{}, err
+		}
+		update.DateOfBirth = &parsed
+	}
+	return update, nil
+}
+
+// optional treats an empty string from a create request as not supplied.
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"github.com/IfedayoAwe/payment-processing-service/handlers/requests"
+	"github.com/IfedayoAwe/payment-processing-service/middleware"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	service "github.com/IfedayoAwe/payment-processing-service/services"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type WalletHandler interface {
+	OpenWallet(c echo.Context) error
+	FreezeWallet(c echo.Context) error
+	UnfreezeWallet(c echo.Context) error
+	CloseWallet(c echo.Context) error
+}
+
+type walletHandler struct {
+	walletService service.WalletService
+}
+
+func newWalletHandler(walletService service.WalletService) WalletHandler {
+	return &walletHandler{
+		walletService: walletService,
+	}
+}
+
+func (wh *walletHandler) OpenWallet(c echo.Context) error {
+	var req requests.OpenWalletRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	currency, err := money.ParseCurrency(req.Currency)
+	if err != nil {
+		return utils.BadRequest(c, "invalid currency")
+	}
+
+	userID := middleware.GetUserID(c)
+
+	wallet, err := wh.walletService.OpenWallet(c.Request().Context(), userID, currency)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Created(c, models.WalletWithBankAccountToResponse(wallet), "wallet opened successfully")
+}
+
+func (wh *walletHandler) FreezeWallet(c echo.Context) error {
+	walletID := c.Param("id")
+	if walletID == "" {
+		return utils.BadRequest(c, "wallet ID is required")
+	}
+
+	var req requests.FreezeWalletRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	wallet, err := wh.walletService.FreezeWallet(c.Request().Context(), walletID, models.FreezeDirection(req.Direction), req.Reason)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, models.WalletToResponse(wallet), "wallet frozen successfully")
+}
+
+func (wh *walletHandler) UnfreezeWallet(c echo.Context) error {
+	walletID := c.Param("id")
+	if walletID == "" {
+		return utils.BadRequest(c, "wallet ID is required")
+	}
+
+	var req requests.WalletStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	wallet, err := wh.walletService.UnfreezeWallet(c.Request().Context(), walletID, req.Reason)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, models.WalletToResponse(wallet), "wallet unfrozen successfully")
+}
+
+func (wh *walletHandler) CloseWallet(c echo.Context) error {
+	walletID := c.Param("id")
+	if walletID == "" {
+		return utils.BadRequest(c, "wallet ID is required")
+	}
+
+	var req requests.WalletStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return utils.ValidationError(c, utils.FormatValidationErrors(err))
+	}
+
+	wallet, err := wh.walletService.CloseWallet(c.Request().Context(), walletID, req.Reason)
+	if err != nil {
+		return utils.HandleError(c, err)
+	}
+
+	return utils.Success(c, models.WalletToResponse(wallet), "wallet closed successfully")
+}
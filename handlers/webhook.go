@@ -5,6 +5,7 @@ import (
 	"io"
 
 	"github.com/IfedayoAwe/payment-processing-service/handlers/requests"
+	"github.com/IfedayoAwe/payment-processing-service/middleware"
 	"github.com/IfedayoAwe/payment-processing-service/queue"
 	"github.com/IfedayoAwe/payment-processing-service/utils"
 	"github.com/labstack/echo/v4"
@@ -25,10 +26,9 @@ func newWebhookHandler(queue queue.Queue) WebhookHandler {
 }
 
 func (wh *webhookHandler) ReceiveWebhook(c echo.Context) error {
-	providerName := c.Param("provider")
-	if providerName == "" {
-		return utils.BadRequest(c, "provider name is required")
-	}
+	// The provider comes from the caller's credentials, checked by
+	// RequireWebhookProvider, so one provider cannot post events as another.
+	providerName := middleware.GetWebhookProvider(c)
 
 	body, err := io.ReadAll(c.Request().Body)
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"github.com/IfedayoAwe/payment-processing-service/config"
 	"github.com/IfedayoAwe/payment-processing-service/db"
 	"github.com/IfedayoAwe/payment-processing-service/handlers"
+	"github.com/IfedayoAwe/payment-processing-service/middleware"
 	"github.com/IfedayoAwe/payment-processing-service/routes"
 	service "github.com/IfedayoAwe/payment-processing-service/services"
 	"github.com/IfedayoAwe/payment-processing-service/utils"
@@ -57,8 +58,18 @@ func run() error {
 	validate := utils.InitValidator()
 	e.Validator = &CustomValidator{validator: validate}
 	e.HTTPErrorHandler = utils.HTTPErrorHandler
+	// Only trust X-Forwarded-For from proxies on private networks so clients
+	// cannot pick their own rate limit bucket.
+	e.IPExtractor = echo.ExtractIPFromXFFHeader()
 
-	routes.Register(e, &cfg, newHandlers)
+	rateLimitStore, err := middleware.NewRateLimitStore(&cfg, queries)
+	if err != nil {
+		return err
+	}
+
+	if err := routes.Register(e, &cfg, newHandlers, rateLimitStore); err != nil {
+		return err
+	}
 
 	port := cfg.Port
 	if port == "" {
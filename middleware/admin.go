@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/labstack/echo/v4"
+)
+
+const IsAdminKey = "is_admin"
+
+// AdminRole marks callers listed in adminUserIDs or holding the admin scope as
+// admins so handlers can check IsAdmin, and records them as admin actors in
+// the audit log. It must run after Authenticate.
+func AdminRole(adminUserIDs []string) echo.MiddlewareFunc {
+	isAdmin := adminChecker(adminUserIDs)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			admin := isAdmin(c)
+			c.Set(IsAdminKey, admin)
+			if admin {
+				ctx := utils.WithActor(c.Request().Context(), utils.Actor{ID: GetUserID(c), Type: utils.ActorTypeAdmin})
+				c.SetRequest(c.Request().WithContext(ctx))
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireAdmin restricts a route to the configured admin user IDs or callers
+// holding the admin scope. It must run after Authenticate.
+func RequireAdmin(adminUserIDs []string) echo.MiddlewareFunc {
+	isAdmin := adminChecker(adminUserIDs)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !isAdmin(c) {
+				return echo.NewHTTPError(http.StatusForbidden, "admin access required")
+			}
+			return next(c)
+		}
+	}
+}
+
+func IsAdmin(c echo.Context) bool {
+	isAdmin, _ := c.Get(IsAdminKey).(bool)
+	return isAdmin
+}
+
+func adminChecker(adminUserIDs []string) func(c echo.Context) bool {
+	admins := make(map[string]struct{}, len(adminUserIDs))
+	for _, id := range adminUserIDs {
+		admins[id] = struct{}{}
+	}
+
+	return func(c echo.Context) bool {
+		if _, ok := admins[GetUserID(c)]; ok {
+			return true
+		}
+		principal := GetPrincipal(c)
+		return principal != nil && principal.HasScope(AdminScope)
+	}
+}
@@ -0,0 +1,257 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/IfedayoAwe/payment-processing-service/config"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/jwt"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	PrincipalKey = "principal"
+
+	AuthMethodJWT    = "jwt"
+	AuthMethodAPIKey = "api_key"
+	AuthMethodHeader = "header"
+
+	APIKeyHeader = "X-API-Key"
+
+	// AdminScope grants admin access in addition to the ADMIN_USER_IDS list.
+	AdminScope = "admin"
+)
+
+// ErrNoCredentials is returned by an Authenticator when the request carries
+// none of the credentials it understands, so the next one can be tried.
+var ErrNoCredentials = errors.New("no credentials")
+
+// Principal is the verified caller of a request.
+type Principal struct {
+	Subject string
+	Method  string
+	Scopes  []string
+	Claims  map[string]interface{}
+}
+
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// JWTAuthenticator verifies "Authorization: Bearer" tokens.
+type JWTAuthenticator struct {
+	verifier *jwt.Verifier
+}
+
+func NewJWTAuthenticator(verifier *jwt.Verifier) *JWTAuthenticator {
+	return &JWTAuthenticator{verifier: verifier}
+}
+
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	header := r.Header.Get(echo.HeaderAuthorization)
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, ErrNoCredentials
+	}
+
+	claims, err := a.verifier.Verify(strings.TrimSpace(token))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Principal{
+		Subject: claims.Subject(),
+		Method:  AuthMethodJWT,
+		Scopes:  claims.Scopes(),
+		Claims:  claims,
+	}, nil
+}
+
+// APIKeyAuthenticator matches the X-API-Key header against configured keys.
+type APIKeyAuthenticator struct {
+	keys []config.APIKey
+}
+
+func NewAPIKeyAuthenticator(keys []config.APIKey) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{keys: keys}
+}
+
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	presented := r.Header.Get(APIKeyHeader)
+	if presented == "" {
+		return nil, ErrNoCredentials
+	}
+
+	// Compare digests so every comparison takes the same time regardless of
+	// key length.
+	digest := sha256.Sum256([]byte(presented))
+	var match *config.APIKey
+	for i := range a.keys {
+		expected := sha256.Sum256([]byte(a.keys[i].Key))
+		if subtle.ConstantTimeCompare(digest[:], expected[:]) == 1 && match == nil {
+			match = &a.keys[i]
+		}
+	}
+	if match == nil {
+		return nil, errors.New("unknown api key")
+	}
+
+	return &Principal{
+		Subject: match.Subject,
+		Method:  AuthMethodAPIKey,
+		Scopes:  match.Scopes,
+	}, nil
+}
+
+// HeaderAuthenticator trusts the X-User-ID header. It is only meant for local
+// development and is enabled with AUTH_ALLOW_USER_ID_HEADER.
+type HeaderAuthenticator struct{}
+
+func (HeaderAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		return nil, ErrNoCredentials
+	}
+	return &Principal{Subject: userID, Method: AuthMethodHeader}, nil
+}
+
+// Authenticate tries each authenticator in order and stores the first
+// verified principal on the context. A credential that is present but
+// rejected fails the request instead of falling through.
+func Authenticate(authenticators ...Authenticator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			for _, authenticator := range authenticators {
+				principal, err := authenticator.Authenticate(c.Request())
+				if errors.Is(err, ErrNoCredentials) {
+					continue
+				}
+				if err != nil {
+					utils.Logger.Warn().
+						Err(err).
+						Str("trace_id", utils.TraceIDFromContext(c.Request().Context())).
+						Msg("authentication failed")
+					return echo.NewHTTPError(http.StatusUnauthorized, "invalid credentials")
+				}
+
+				setPrincipal(c, principal)
+				return next(c)
+			}
+
+			return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+		}
+	}
+}
+
+// setPrincipal stores the verified caller on the echo context, and as the
+// audit actor on the request context so services can record who acted.
+func setPrincipal(c echo.Context, principal *Principal) {
+	c.Set(PrincipalKey, principal)
+	c.Set(UserIDKey, principal.Subject)
+
+	actorType := utils.ActorTypeUser
+	if principal.Method == AuthMethodAPIKey {
+		actorType = utils.ActorTypeService
+	}
+	ctx := utils.WithActor(c.Request().Context(), utils.Actor{ID: principal.Subject, Type: actorType})
+	c.SetRequest(c.Request().WithContext(ctx))
+}
+
+// NewAuthMiddleware builds the authentication chain from config. JWTs are
+// checked first, then API keys, then the development-only X-User-ID header.
+func NewAuthMiddleware(cfg *config.Config) (echo.MiddlewareFunc, error) {
+	var authenticators []Authenticator
+
+	opts := jwt.Options{
+		HMACSecret: []byte(cfg.JWTHMACSecret),
+		Issuer:     cfg.JWTIssuer,
+		Audience:   cfg.JWTAudience,
+	}
+	if cfg.JWTPublicKeyFile != "" {
+		data, err := os.ReadFile(cfg.JWTPublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read jwt public key: %w", err)
+		}
+		opts.RSAPublicKeyPEM = data
+	}
+	if cfg.JWKSFile != "" {
+		data, err := os.ReadFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("read jwks: %w", err)
+		}
+		opts.JWKS = data
+	}
+	if len(opts.HMACSecret) > 0 || len(opts.RSAPublicKeyPEM) > 0 || len(opts.JWKS) > 0 {
+		verifier, err := jwt.NewVerifier(opts)
+		if err != nil {
+			return nil, fmt.Errorf("configure jwt verifier: %w", err)
+		}
+		authenticators = append(authenticators, NewJWTAuthenticator(verifier))
+	}
+
+	if len(cfg.APIKeys) > 0 {
+		authenticators = append(authenticators, NewAPIKeyAuthenticator(cfg.APIKeys))
+	}
+
+	if cfg.AllowUserIDHeader {
+		utils.Logger.Warn().Msg("X-User-ID header authentication is enabled; do not use in production")
+		authenticators = append(authenticators, HeaderAuthenticator{})
+	}
+
+	if len(authenticators) == 0 {
+		return nil, errors.New("no authentication method configured")
+	}
+
+	return Authenticate(authenticators...), nil
+}
+
+// RequireScope rejects callers whose principal lacks the given scope. It must
+// run after Authenticate.
+func RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal := GetPrincipal(c)
+			if principal == nil || !principal.HasScope(scope) {
+				return echo.NewHTTPError(http.StatusForbidden, "insufficient scope")
+			}
+			return next(c)
+		}
+	}
+}
+
+func GetPrincipal(c echo.Context) *Principal {
+	principal, ok := c.Get(PrincipalKey).(*Principal)
+	if !ok {
+		return nil
+	}
+	return principal
+}
+
+func GetScopes(c echo.Context) []string {
+	if principal := GetPrincipal(c); principal != nil {
+		return principal.Scopes
+	}
+	return nil
+}
+
+func GetClaims(c echo.Context) map[string]interface{} {
+	if principal := GetPrincipal(c); principal != nil {
+		return principal.Claims
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/config"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signTestToken(t *testing.T, secret string, claims map[string]interface{}) string {
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func runAuth(t *testing.T, mw echo.MiddlewareFunc, setup func(r *http.Request)) (*Principal, error) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	setup(req)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var principal *Principal
+	err := mw(func(c echo.Context) error {
+		principal = GetPrincipal(c)
+		assert.Equal(t, principal.Subject, GetUserID(c))
+		return c.String(http.StatusOK, "OK")
+	})(c)
+	return principal, err
+}
+
+func assertStatus(t *testing.T, err error, code int) {
+	he, ok := err.(*echo.HTTPError)
+	require.True(t, ok)
+	assert.Equal(t, code, he.Code)
+}
+
+func TestNewAuthMiddleware(t *testing.T) {
+	cfg := &config.Config{
+		JWTHMACSecret: "test-secret",
+		APIKeys: []config.APIKey{
+			{Subject: "settlement-svc", Key: "sk_test_1", Scopes: []string{"payments:read"}},
+		},
+	}
+	mw, err := NewAuthMiddleware(cfg)
+	require.NoError(t, err)
+
+	t.Run("valid jwt", func(t *testing.T) {
+		token := signTestToken(t, "test-secret", map[string]interface{}{
+			"sub":   "user_1",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"scope": "payments:write",
+		})
+
+		principal, err := runAuth(t, mw, func(r *http.Request) {
+			r.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "user_1", principal.Subject)
+		assert.Equal(t, AuthMethodJWT, principal.Method)
+		assert.Equal(t, []string{"payments:write"}, principal.Scopes)
+		assert.Equal(t, "user_1", principal.Claims["sub"])
+	})
+
+	t.Run("invalid jwt does not fall through", func(t *testing.T) {
+		token := signTestToken(t, "wrong-secret", map[string]interface{}{
+			"sub": "user_1",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		})
+
+		_, err := runAuth(t, mw, func(r *http.Request) {
+			r.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+			r.Header.Set(APIKeyHeader, "sk_test_1")
+		})
+
+		assertStatus(t, err, http.StatusUnauthorized)
+	})
+
+	t.Run("valid api key", func(t *testing.T) {
+		principal, err := runAuth(t, mw, func(r *http.Request) {
+			r.Header.Set(APIKeyHeader, "sk_test_1")
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "settlement-svc", principal.Subject)
+		assert.Equal(t, AuthMethodAPIKey, principal.Method)
+	})
+
+	t.Run("unknown api key", func(t *testing.T) {
+		_, err := runAuth(t, mw, func(r *http.Request) {
+			r.Header.Set(APIKeyHeader, "sk_test_2")
+		})
+
+		assertStatus(t, err, http.StatusUnauthorized)
+	})
+
+	t.Run("user id header ignored unless enabled", func(t *testing.T) {
+		_, err := runAuth(t, mw, func(r *http.Request) {
+			r.Header.Set("X-User-ID", "user_1")
+		})
+
+		assertStatus(t, err, http.StatusUnauthorized)
+	})
+}
+
+func TestNewAuthMiddleware_UserIDHeader(t *testing.T) {
+	mw, err := NewAuthMiddleware(&config.Config{AllowUserIDHeader: true})
+	require.NoError(t, err)
+
+	principal, err := runAuth(t, mw, func(r *http.Request) {
+		r.Header.Set("X-User-ID", "user_1")
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "user_1", principal.Subject)
+	assert.Equal(t, AuthMethodHeader, principal.Method)
+}
+
+func TestNewAuthMiddleware_NothingConfigured(t *testing.T) {
+	_, err := NewAuthMiddleware(&config.Config{})
+	assert.Error(t, err)
+}
+
+func TestRequireScope(t *testing.T) {
+	e := echo.New()
+	handler := RequireScope("payments:write")(func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+
+	t.Run("scope present", func(t *testing.T) {
+		c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+		c.Set(PrincipalKey, &Principal{Subject: "user_1", Scopes: []string{"payments:write"}})
+
+		assert.NoError(t, handler(c))
+	})
+
+	t.Run("scope missing", func(t *testing.T) {
+		c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+		c.Set(PrincipalKey, &Principal{Subject: "user_1", Scopes: []string{"payments:read"}})
+
+		assertStatus(t, handler(c), http.StatusForbidden)
+	})
+}
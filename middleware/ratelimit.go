@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/config"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+)
+
+// RateLimitResult is the outcome of taking a token from a bucket.
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next token, zero when allowed.
+	RetryAfter time.Duration
+}
+
+// RateLimitStore holds token buckets. Implementations must make Take atomic
+// for a key so concurrent requests cannot spend the same token.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, limit config.RateLimit) (RateLimitResult, error)
+}
+
+// RateLimit limits requests with a token bucket per caller. Buckets are scoped
+// by name, so a route with its own limit does not share tokens with the group
+// default. Callers are identified by authenticated subject, API key subject or,
+// for unauthenticated routes, client IP. A nil store disables the limit.
+func RateLimit(name string, limit config.RateLimit, store RateLimitStore) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if store == nil || limit.Requests <= 0 || limit.Period <= 0 {
+			return next
+		}
+
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+
+			result, err := store.Take(ctx, name+":"+rateLimitIdentity(c), limit)
+			if err != nil {
+				// Failing open keeps the API available when the store is down.
+				utils.Logger.Error().
+					Err(err).
+					Str("limit", name).
+					Str("trace_id", utils.TraceIDFromContext(ctx)).
+					Msg("rate limit store error")
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set(HeaderRateLimitLimit, strconv.Itoa(limit.Requests))
+			header.Set(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+			header.Set(HeaderRateLimitReset, strconv.Itoa(ceilSeconds(result.Reset)))
+
+			if !result.Allowed {
+				header.Set(echo.HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+				return echo.NewHTTPError(http.StatusTooManyRequests, "rate limit exceeded")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func rateLimitIdentity(c echo.Context) string {
+	if principal := GetPrincipal(c); principal != nil {
+		if principal.Method == AuthMethodAPIKey {
+			return "key:" + principal.Subject
+		}
+		return "user:" + principal.Subject
+	}
+	return "ip:" + c.RealIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
+
+// bucketState computes a RateLimitResult from the tokens left after a take,
+// or the tokens available when the take was refused.
+func bucketState(tokens float64, allowed bool, limit config.RateLimit) RateLimitResult {
+	rate := float64(limit.Requests) / limit.Period.Seconds()
+	result := RateLimitResult{
+		Allowed:   allowed,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     time.Duration((float64(limit.Requests) - tokens) / rate * float64(time.Second)),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) / rate * float64(time.Second))
+	}
+	return result
+}
@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/config"
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+)
+
+const (
+	RateLimitStoreMemory   = "memory"
+	RateLimitStorePostgres = "postgres"
+
+	rateLimitCleanupInterval = 5 * time.Minute
+	// rateLimitBucketTTL is how long an idle Postgres bucket is kept. It must
+	// be longer than the longest configured period.
+	rateLimitBucketTTL = 24 * time.Hour
+)
+
+// NewRateLimitStore returns the store selected by RATE_LIMIT_STORE, or nil
+// when rate limiting is disabled.
+func NewRateLimitStore(cfg *config.Config, queries gen.Querier) (RateLimitStore, error) {
+	if !cfg.RateLimitEnabled {
+		return nil, nil
+	}
+
+	switch cfg.RateLimitStore {
+	case RateLimitStoreMemory, "":
+		return NewMemoryRateLimitStore(), nil
+	case RateLimitStorePostgres:
+		return NewPostgresRateLimitStore(queries), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", cfg.RateLimitStore)
+	}
+}
+
+type memoryBucket struct {
+	tokens  float64
+	updated time.Time
+	limit   config.RateLimit
+}
+
+// MemoryRateLimitStore keeps buckets in process memory. Limits are per
+// instance, so use the Postgres store when running more than one.
+type MemoryRateLimitStore struct {
+	mu          sync.Mutex
+	buckets     map[string]*memoryBucket
+	lastCleanup time.Time
+	now         func() time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: make(map[string]*memoryBucket),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, limit config.RateLimit) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.cleanup(now)
+
+	burst := float64(limit.Requests)
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: burst}
+		s.buckets[key] = bucket
+	} else {
+		bucket.tokens = refill(bucket.tokens, now.Sub(bucket.updated), limit)
+	}
+	bucket.updated = now
+	bucket.limit = limit
+
+	if bucket.tokens < 1 {
+		return bucketState(bucket.tokens, false, limit), nil
+	}
+
+	bucket.tokens--
+	return bucketState(bucket.tokens, true, limit), nil
+}
+
+// cleanup drops buckets that have refilled completely, since a missing bucket
+// behaves the same as a full one.
+func (s *MemoryRateLimitStore) cleanup(now time.Time) {
+	if now.Sub(s.lastCleanup) < rateLimitCleanupInterval {
+		return
+	}
+	s.lastCleanup = now
+
+	for key, bucket := range s.buckets {
+		if refill(bucket.tokens, now.Sub(bucket.updated), bucket.limit) >= float64(bucket.limit.Requests) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func refill(tokens float64, elapsed time.Duration, limit config.RateLimit) float64 {
+	rate := float64(limit.Requests) / limit.Period.Seconds()
+	return math.Min(float64(limit.Requests), tokens+elapsed.Seconds()*rate)
+}
+
+// PostgresRateLimitStore keeps buckets in the rate_limit_buckets table so
+// every instance shares the same limits. Each take is a single upsert, and the
+// database clock is used for refills.
+type PostgresRateLimitStore struct {
+	queries     gen.Querier
+	mu          sync.Mutex
+	lastCleanup time.Time
+}
+
+func NewPostgresRateLimitStore(queries gen.Querier) *PostgresRateLimitStore {
+	return &PostgresRateLimitStore{queries: queries}
+}
+
+func (s *PostgresRateLimitStore) Take(ctx context.Context, key string, limit config.RateLimit) (RateLimitResult, error) {
+	s.cleanup(ctx)
+
+	burst := float64(limit.Requests)
+	rate := burst / limit.Period.Seconds()
+
+	tokens, err := s.queries.TakeRateLimitToken(ctx, gen.TakeRateLimitTokenParams{
+		Key:   key,
+		Burst: burst,
+		Rate:  rate,
+	})
+	if err == nil {
+		return bucketState(tokens, true, limit), nil
+	}
+	if err != sql.ErrNoRows {
+		return RateLimitResult{}, fmt.Errorf("take rate limit token: %w", err)
+	}
+
+	tokens, err = s.queries.GetRateLimitTokens(ctx, gen.GetRateLimitTokensParams{
+		Burst: burst,
+		Rate:  rate,
+		Key:   key,
+	})
+	if err != nil {
+		return RateLimitResult{}, fmt.Errorf("get rate limit tokens: %w", err)
+	}
+	return bucketState(tokens, false, limit), nil
+}
+
+func (s *PostgresRateLimitStore) cleanup(ctx context.Context) {
+	s.mu.Lock()
+	if time.Since(s.lastCleanup) < rateLimitCleanupInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastCleanup = time.Now()
+	s.mu.Unlock()
+
+	// Best effort: a failed cleanup only leaves idle rows behind.
+	_ = s.queries.DeleteStaleRateLimitBuckets(ctx, time.Now().Add(-rateLimitBucketTTL))
+}
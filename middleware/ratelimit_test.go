@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/config"
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(context.Context, string, config.RateLimit) (RateLimitResult, error) {
+	return RateLimitResult{}, errors.New("store unavailable")
+}
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	store := NewMemoryRateLimitStore()
+	store.now = func() time.Time { return now }
+	limit := config.RateLimit{Requests: 3, Period: 3 * time.Second}
+
+	for i := 2; i >= 0; i-- {
+		result, err := store.Take(context.Background(), "k", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, i, result.Remaining)
+	}
+
+	result, err := store.Take(context.Background(), "k", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.Reset)
+
+	other, err := store.Take(context.Background(), "other", limit)
+	require.NoError(t, err)
+	assert.True(t, other.Allowed)
+
+	now = now.Add(time.Second)
+	result, err = store.Take(context.Background(), "k", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	now = now.Add(rateLimitCleanupInterval)
+	_, err = store.Take(context.Background(), "k", limit)
+	require.NoError(t, err)
+	assert.NotContains(t, store.buckets, "other")
+}
+
+func TestPostgresRateLimitStore(t *testing.T) {
+	limit := config.RateLimit{Requests: 10, Period: 10 * time.Second}
+
+	t.Run("allowed", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		store := NewPostgresRateLimitStore(mockQueries)
+
+		mockQueries.On("DeleteStaleRateLimitBuckets", mock.Anything, mock.Anything).Return(nil)
+		mockQueries.On("TakeRateLimitToken", mock.Anything, gen.TakeRateLimitTokenParams{Key: "k", Burst: 10, Rate: 1}).Return(4.5, nil)
+
+		result, err := store.Take(context.Background(), "k", limit)
+
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 4, result.Remaining)
+		assert.Equal(t, 5500*time.Millisecond, result.Reset)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		store := NewPostgresRateLimitStore(mockQueries)
+
+		mockQueries.On("DeleteStaleRateLimitBuckets", mock.Anything, mock.Anything).Return(nil)
+		mockQueries.On("TakeRateLimitToken", mock.Anything, mock.Anything).Return(0.0, sql.ErrNoRows)
+		mockQueries.On("GetRateLimitTokens", mock.Anything, gen.GetRateLimitTokensParams{Burst: 10, Rate: 1, Key: "k"}).Return(0.25, nil)
+
+		result, err := store.Take(context.Background(), "k", limit)
+
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 750*time.Millisecond, result.RetryAfter)
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	e := echo.New()
+	limit := config.RateLimit{Requests: 1, Period: time.Minute}
+	ok := func(c echo.Context) error { return c.String(http.StatusOK, "OK") }
+
+	newContext := func(principal *Principal) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.RemoteAddr = "203.0.113.7:4000"
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if principal != nil {
+			c.Set(PrincipalKey, principal)
+		}
+		return c, rec
+	}
+
+	t.Run("limits per user", func(t *testing.T) {
+		handler := RateLimit("transfers", limit, NewMemoryRateLimitStore())(ok)
+
+		c, rec := newContext(&Principal{Subject: "user_1", Method: AuthMethodJWT})
+		require.NoError(t, handler(c))
+		assert.Equal(t, "1", rec.Header().Get(HeaderRateLimitLimit))
+		assert.Equal(t, "0", rec.Header().Get(HeaderRateLimitRemaining))
+		assert.Equal(t, "60", rec.Header().Get(HeaderRateLimitReset))
+
+		c, rec = newContext(&Principal{Subject: "user_1", Method: AuthMethodJWT})
+		err := handler(c)
+		assertStatus(t, err, http.StatusTooManyRequests)
+		assert.Equal(t, "60", rec.Header().Get(echo.HeaderRetryAfter))
+
+		c, _ = newContext(&Principal{Subject: "user_2", Method: AuthMethodJWT})
+		assert.NoError(t, handler(c))
+
+		c, _ = newContext(nil)
+		assert.NoError(t, handler(c))
+	})
+
+	t.Run("limits are scoped by name", func(t *testing.T) {
+		store := NewMemoryRateLimitStore()
+		principal := &Principal{Subject: "user_1", Method: AuthMethodJWT}
+
+		c, _ := newContext(principal)
+		require.NoError(t, RateLimit("default", limit, store)(ok)(c))
+
+		c, _ = newContext(principal)
+		assert.NoError(t, RateLimit("name_enquiry", limit, store)(ok)(c))
+	})
+
+	t.Run("nil store disables limiting", func(t *testing.T) {
+		handler := RateLimit("default", limit, nil)(ok)
+		for i := 0; i < 3; i++ {
+			c, _ := newContext(nil)
+			assert.NoError(t, handler(c))
+		}
+	})
+
+	t.Run("store errors fail open", func(t *testing.T) {
+		c, _ := newContext(nil)
+		assert.NoError(t, RateLimit("default", limit, failingRateLimitStore{})(ok)(c))
+	})
+}
+
+func TestRateLimitIdentity(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "203.0.113.7:4000"
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	assert.Equal(t, "ip:203.0.113.7", rateLimitIdentity(c))
+
+	c.Set(PrincipalKey, &Principal{Subject: "settlement-svc", Method: AuthMethodAPIKey})
+	assert.Equal(t, "key:settlement-svc", rateLimitIdentity(c))
+
+	c.Set(PrincipalKey, &Principal{Subject: "user_1", Method: AuthMethodJWT})
+	assert.Equal(t, "user:user_1", rateLimitIdentity(c))
+}
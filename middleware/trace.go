@@ -16,6 +16,7 @@ func TraceIDMiddleware() echo.MiddlewareFunc {
 			}
 
 			ctx = utils.WithTraceID(ctx, traceID)
+			ctx = utils.WithClientIP(ctx, c.RealIP())
 			c.SetRequest(c.Request().WithContext(ctx))
 
 			c.Response().Header().Set(utils.TraceIDHeader, traceID)
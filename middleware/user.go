@@ -8,20 +8,24 @@ import (
 
 const UserIDKey = "user_id"
 
+// UserIDMiddleware authenticates with the X-User-ID header alone. It trusts
+// the caller and is kept for tests and local tooling; routes use
+// NewAuthMiddleware.
 func UserIDMiddleware() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			userID := c.Request().Header.Get("X-User-ID")
-			if userID == "" {
+			principal, err := HeaderAuthenticator{}.Authenticate(c.Request())
+			if err != nil {
 				return echo.NewHTTPError(http.StatusUnauthorized, "X-User-ID header is required")
 			}
 
-			c.Set(UserIDKey, userID)
+			setPrincipal(c, principal)
 			return next(c)
 		}
 	}
 }
 
+// GetUserID returns the verified subject of the authenticated caller.
 func GetUserID(c echo.Context) string {
 	userID, ok := c.Get(UserIDKey).(string)
 	if !ok {
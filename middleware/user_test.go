@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/IfedayoAwe/payment-processing-service/utils"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 )
@@ -21,6 +22,7 @@ func TestUserIDMiddleware(t *testing.T) {
 	handler := UserIDMiddleware()(func(c echo.Context) error {
 		userID := GetUserID(c)
 		assert.Equal(t, "user_1", userID)
+		assert.Equal(t, utils.Actor{ID: "user_1", Type: utils.ActorTypeUser}, utils.ActorFromContext(c.Request().Context()))
 		return c.String(http.StatusOK, "OK")
 	})
 
@@ -62,3 +64,73 @@ func TestGetUserID(t *testing.T) {
 	c2 := e.NewContext(req, rec)
 	assert.Equal(t, "", GetUserID(c2))
 }
+
+func TestRequireAdmin(t *testing.T) {
+	e := echo.New()
+	handler := RequireAdmin([]string{"admin_1"})(func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+
+	t.Run("admin allowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set(UserIDKey, "admin_1")
+
+		err := handler(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("non admin forbidden", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set(UserIDKey, "user_1")
+
+		err := handler(c)
+		assert.Error(t, err)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusForbidden, he.Code)
+	})
+
+	t.Run("admin scope allowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set(UserIDKey, "ops-svc")
+		c.Set(PrincipalKey, &Principal{Subject: "ops-svc", Scopes: []string{AdminScope}})
+
+		err := handler(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestAdminRole_AuditActor(t *testing.T) {
+	e := echo.New()
+
+	var actor utils.Actor
+	handler := AdminRole([]string{"admin_1"})(func(c echo.Context) error {
+		actor = utils.ActorFromContext(c.Request().Context())
+		return c.String(http.StatusOK, "OK")
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+		setPrincipal(c, &Principal{Subject: "admin_1", Method: AuthMethodJWT})
+
+		assert.NoError(t, handler(c))
+		assert.Equal(t, utils.Actor{ID: "admin_1", Type: utils.ActorTypeAdmin}, actor)
+	})
+
+	t.Run("service", func(t *testing.T) {
+		c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+		setPrincipal(c, &Principal{Subject: "settlement-svc", Method: AuthMethodAPIKey})
+
+		assert.NoError(t, handler(c))
+		assert.Equal(t, utils.Actor{ID: "settlement-svc", Type: utils.ActorTypeService}, actor)
+	})
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	WebhookProviderKey = "webhook_provider"
+
+	// WebhookScopePrefix followed by a provider name is the scope an API key
+	// needs to deliver that provider's webhooks.
+	WebhookScopePrefix = "webhooks:"
+)
+
+// RequireWebhookProvider restricts a route to API key callers holding the
+// webhooks:<provider> scope for the :provider path parameter, and stores the
+// provider for GetWebhookProvider. User credentials are rejected even if they
+// carry the scope, since webhooks can credit wallets. It must run after
+// Authenticate.
+func RequireWebhookProvider() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal := GetPrincipal(c)
+			provider := c.Param("provider")
+			if principal == nil || principal.Method != AuthMethodAPIKey || provider == "" || !principal.HasScope(WebhookScopePrefix+provider) {
+				return echo.NewHTTPError(http.StatusForbidden, "webhook access required")
+			}
+
+			c.Set(WebhookProviderKey, provider)
+			return next(c)
+		}
+	}
+}
+
+// GetWebhookProvider returns the provider the caller was authorized to send
+// webhooks for, or "" outside RequireWebhookProvider.
+func GetWebhookProvider(c echo.Context) string {
+	provider, _ := c.Get(WebhookProviderKey).(string)
+	return provider
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/config"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireWebhookProvider(t *testing.T) {
+	authenticate, err := NewAuthMiddleware(&config.Config{
+		JWTHMACSecret: "test-secret",
+		APIKeys: []config.APIKey{
+			{Subject: "currencycloud", Key: "sk_cc", Scopes: []string{"webhooks:currencycloud"}},
+			{Subject: "settlement-svc", Key: "sk_settle", Scopes: []string{"payments:read"}},
+		},
+	})
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.POST("/webhooks/:provider", func(c echo.Context) error {
+		return c.String(http.StatusOK, GetWebhookProvider(c))
+	}, authenticate, RequireWebhookProvider())
+
+	post := func(provider string, setup func(r *http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/"+provider, nil)
+		setup(req)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("provider api key", func(t *testing.T) {
+		rec := post("currencycloud", func(r *http.Request) { r.Header.Set(APIKeyHeader, "sk_cc") })
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "currencycloud", rec.Body.String())
+	})
+
+	t.Run("user jwt is rejected even with the scope", func(t *testing.T) {
+		token := signTestToken(t, "test-secret", map[string]interface{}{
+			"sub":   "user_1",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"scope": "webhooks:currencycloud",
+		})
+
+		rec := post("currencycloud", func(r *http.Request) { r.Header.Set(echo.HeaderAuthorization, "Bearer "+token) })
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("another provider's name in the url", func(t *testing.T) {
+		rec := post("dlocal", func(r *http.Request) { r.Header.Set(APIKeyHeader, "sk_cc") })
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("api key without a webhook scope", func(t *testing.T) {
+		rec := post("currencycloud", func(r *http.Request) { r.Header.Set(APIKeyHeader, "sk_settle") })
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
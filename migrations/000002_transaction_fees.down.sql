@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_ledger_entries_account_type_currency;
+
+DELETE FROM ledger_entries WHERE account_type = 'fee_revenue';
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_account_type_check;
+ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_account_type_check
+    CHECK (account_type IN ('user_wallet', 'external_wallet'));
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS fee_currency;
+ALTER TABLE transactions DROP COLUMN IF EXISTS fee_amount;
+
+DROP INDEX IF EXISTS idx_fee_rules_type_active;
+DROP TABLE IF EXISTS fee_rules;
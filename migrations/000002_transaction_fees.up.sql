@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS fee_rules (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    transaction_type TEXT NOT NULL CHECK (transaction_type IN ('internal', 'external')),
+    source_currency TEXT CHECK (source_currency IN ('USD', 'EUR', 'GBP')),
+    destination_currency TEXT CHECK (destination_currency IN ('USD', 'EUR', 'GBP')),
+    min_amount BIGINT NOT NULL DEFAULT 0 CHECK (min_amount >= 0),
+    max_amount BIGINT CHECK (max_amount IS NULL OR max_amount >= min_amount),
+    fee_type TEXT NOT NULL CHECK (fee_type IN ('flat', 'percentage', 'tiered')),
+    flat_amount BIGINT NOT NULL DEFAULT 0 CHECK (flat_amount >= 0),
+    percentage_bps INT NOT NULL DEFAULT 0 CHECK (percentage_bps >= 0),
+    tiers JSONB NOT NULL DEFAULT '[]'::jsonb,
+    min_fee BIGINT CHECK (min_fee IS NULL OR min_fee >= 0),
+    max_fee BIGINT CHECK (max_fee IS NULL OR max_fee >= 0),
+    priority INT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (fee_type <> 'tiered' OR jsonb_array_length(tiers) > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fee_rules_type_active ON fee_rules(transaction_type, active);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS fee_amount BIGINT NOT NULL DEFAULT 0 CHECK (fee_amount >= 0);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS fee_currency TEXT CHECK (fee_currency IN ('USD', 'EUR', 'GBP'));
+
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_account_type_check;
+ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_account_type_check
+    CHECK (account_type IN ('user_wallet', 'external_wallet', 'fee_revenue'));
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account_type_currency ON ledger_entries(account_type, currency);
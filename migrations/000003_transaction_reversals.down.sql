@@ -0,0 +1,22 @@
+DELETE FROM ledger_entries WHERE transaction_id IN (SELECT id FROM transactions WHERE type = 'reversal');
+DELETE FROM idempotency_keys WHERE transaction_id IN (SELECT id FROM transactions WHERE type = 'reversal');
+DELETE FROM transactions WHERE type = 'reversal';
+UPDATE transactions SET status = 'completed' WHERE status = 'reversed';
+UPDATE transactions SET status = 'failed' WHERE status = 'cancelled';
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_check CHECK (
+    (type = 'internal' AND from_wallet_id IS NOT NULL AND to_wallet_id IS NOT NULL) OR
+    (type = 'external' AND (from_wallet_id IS NOT NULL OR to_wallet_id IS NOT NULL))
+);
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_status_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_status_check
+    CHECK (status IN ('initiated', 'pending', 'completed', 'failed'));
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_type_check
+    CHECK (type IN ('internal', 'external'));
+
+DROP INDEX IF EXISTS idx_transactions_parent_transaction_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS parent_transaction_id;
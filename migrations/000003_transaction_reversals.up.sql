@@ -0,0 +1,18 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS parent_transaction_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_parent_transaction_id ON transactions(parent_transaction_id);
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_type_check
+    CHECK (type IN ('internal', 'external', 'reversal'));
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_status_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_status_check
+    CHECK (status IN ('initiated', 'pending', 'completed', 'failed', 'cancelled', 'reversed'));
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_check CHECK (
+    (type = 'internal' AND from_wallet_id IS NOT NULL AND to_wallet_id IS NOT NULL) OR
+    (type = 'external' AND (from_wallet_id IS NOT NULL OR to_wallet_id IS NOT NULL)) OR
+    (type = 'reversal' AND from_wallet_id IS NOT NULL AND to_wallet_id IS NOT NULL AND parent_transaction_id IS NOT NULL)
+);
@@ -0,0 +1,21 @@
+DELETE FROM ledger_entries WHERE transaction_id IN (SELECT id FROM transactions WHERE type = 'refund');
+DELETE FROM idempotency_keys WHERE transaction_id IN (SELECT id FROM transactions WHERE type = 'refund');
+DELETE FROM transactions WHERE type = 'refund';
+UPDATE transactions SET status = 'completed' WHERE status = 'refunded';
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_check CHECK (
+    (type = 'internal' AND from_wallet_id IS NOT NULL AND to_wallet_id IS NOT NULL) OR
+    (type = 'external' AND (from_wallet_id IS NOT NULL OR to_wallet_id IS NOT NULL)) OR
+    (type = 'reversal' AND from_wallet_id IS NOT NULL AND to_wallet_id IS NOT NULL AND parent_transaction_id IS NOT NULL)
+);
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_status_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_status_check
+    CHECK (status IN ('initiated', 'pending', 'completed', 'failed', 'cancelled', 'reversed'));
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_type_check
+    CHECK (type IN ('internal', 'external', 'reversal'));
+
+DROP TABLE IF EXISTS refunds;
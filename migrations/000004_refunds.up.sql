@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS refunds (
+    id TEXT PRIMARY KEY,
+    transaction_id TEXT NOT NULL,
+    refund_transaction_id TEXT NOT NULL UNIQUE,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency TEXT NOT NULL CHECK (currency IN ('USD', 'EUR', 'GBP')),
+    credited_amount BIGINT NOT NULL CHECK (credited_amount > 0),
+    credited_currency TEXT NOT NULL CHECK (credited_currency IN ('USD', 'EUR', 'GBP')),
+    reason TEXT NOT NULL,
+    idempotency_key TEXT UNIQUE NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_transaction_id ON refunds(transaction_id, created_at);
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_type_check
+    CHECK (type IN ('internal', 'external', 'reversal', 'refund'));
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_status_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_status_check
+    CHECK (status IN ('initiated', 'pending', 'completed', 'failed', 'cancelled', 'reversed', 'refunded'));
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_check CHECK (
+    (type = 'internal' AND from_wallet_id IS NOT NULL AND to_wallet_id IS NOT NULL) OR
+    (type = 'external' AND (from_wallet_id IS NOT NULL OR to_wallet_id IS NOT NULL)) OR
+    (type = 'reversal' AND from_wallet_id IS NOT NULL AND to_wallet_id IS NOT NULL AND parent_transaction_id IS NOT NULL) OR
+    (type = 'refund' AND to_wallet_id IS NOT NULL AND parent_transaction_id IS NOT NULL)
+);
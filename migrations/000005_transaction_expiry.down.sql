@@ -0,0 +1,8 @@
+UPDATE transactions SET status = 'cancelled' WHERE status = 'expired';
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_status_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_status_check
+    CHECK (status IN ('initiated', 'pending', 'completed', 'failed', 'cancelled', 'reversed', 'refunded'));
+
+DROP INDEX IF EXISTS idx_transactions_status_created_at;
+DROP TABLE IF EXISTS transaction_status_history;
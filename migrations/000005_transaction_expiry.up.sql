@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS transaction_status_history (
+    id TEXT PRIMARY KEY,
+    transaction_id TEXT NOT NULL,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_status_history_transaction_id ON transaction_status_history(transaction_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_status_created_at ON transactions(status, created_at);
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_status_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_status_check
+    CHECK (status IN ('initiated', 'pending', 'completed', 'failed', 'cancelled', 'reversed', 'refunded', 'expired'));
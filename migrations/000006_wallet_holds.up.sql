@@ -0,0 +1,15 @@
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS held_balance BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE wallets ADD CONSTRAINT wallets_held_balance_check CHECK (held_balance >= 0);
+
+CREATE TABLE IF NOT EXISTS wallet_holds (
+    id TEXT PRIMARY KEY,
+    wallet_id TEXT NOT NULL,
+    transaction_id TEXT NOT NULL UNIQUE,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency TEXT NOT NULL CHECK (currency IN ('USD', 'EUR', 'GBP')),
+    status TEXT NOT NULL CHECK (status IN ('active', 'captured', 'released')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    released_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_wallet_holds_wallet_id_status ON wallet_holds(wallet_id, status);
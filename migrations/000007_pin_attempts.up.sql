@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS pin_attempts (
+    user_id TEXT PRIMARY KEY,
+    failed_attempts INTEGER NOT NULL DEFAULT 0 CHECK (failed_attempts >= 0),
+    lockout_count INTEGER NOT NULL DEFAULT 0 CHECK (lockout_count >= 0),
+    locked_until TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
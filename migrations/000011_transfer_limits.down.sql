@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_transactions_from_wallet_created_at;
+DROP TABLE IF EXISTS transfer_limits;
+ALTER TABLE users DROP COLUMN IF EXISTS tier;
@@ -0,0 +1,19 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tier TEXT NOT NULL DEFAULT 'standard';
+
+CREATE TABLE IF NOT EXISTS transfer_limits (
+    id TEXT PRIMARY KEY,
+    tier TEXT NOT NULL,
+    currency TEXT NOT NULL CHECK (currency IN ('USD', 'EUR', 'GBP')),
+    transaction_type TEXT NOT NULL CHECK (transaction_type IN ('internal', 'external')),
+    per_transaction_max BIGINT CHECK (per_transaction_max IS NULL OR per_transaction_max > 0),
+    daily_amount_max BIGINT CHECK (daily_amount_max IS NULL OR daily_amount_max >= 0),
+    daily_count_max INT CHECK (daily_count_max IS NULL OR daily_count_max >= 0),
+    monthly_amount_max BIGINT CHECK (monthly_amount_max IS NULL OR monthly_amount_max >= 0),
+    monthly_count_max INT CHECK (monthly_count_max IS NULL OR monthly_count_max >= 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE(tier, currency, transaction_type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_from_wallet_created_at ON transactions(from_wallet_id, created_at);
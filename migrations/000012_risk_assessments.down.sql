@@ -0,0 +1,19 @@
+UPDATE wallets w
+SET held_balance = w.held_balance - h.amount, updated_at = NOW()
+FROM wallet_holds h
+JOIN transactions t ON t.id = h.transaction_id
+WHERE h.wallet_id = w.id AND h.status = 'active' AND t.status = 'on_hold';
+
+UPDATE wallet_holds SET status = 'released', released_at = NOW()
+WHERE status = 'active' AND transaction_id IN (SELECT id FROM transactions WHERE status = 'on_hold');
+
+UPDATE transactions SET status = 'failed' WHERE status = 'on_hold';
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_status_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_status_check
+    CHECK (status IN ('initiated', 'pending', 'completed', 'failed', 'cancelled', 'reversed', 'refunded', 'expired'));
+
+DROP INDEX IF EXISTS idx_risk_assessments_pending_review;
+DROP INDEX IF EXISTS idx_risk_assessments_user_recipient;
+DROP INDEX IF EXISTS idx_risk_assessments_transaction_id;
+DROP TABLE IF EXISTS risk_assessments;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS risk_assessments (
+    id TEXT PRIMARY KEY,
+    transaction_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    recipient TEXT NOT NULL,
+    decision TEXT NOT NULL CHECK (decision IN ('allow', 'review', 'deny')),
+    score INT NOT NULL,
+    reasons JSONB NOT NULL DEFAULT '[]'::jsonb,
+    features JSONB NOT NULL DEFAULT '{}'::jsonb,
+    review_decision TEXT CHECK (review_decision IN ('approved', 'rejected')),
+    reviewed_by TEXT,
+    review_note TEXT,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_assessments_transaction_id ON risk_assessments(transaction_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_risk_assessments_user_recipient ON risk_assessments(user_id, recipient);
+CREATE INDEX IF NOT EXISTS idx_risk_assessments_pending_review ON risk_assessments(created_at) WHERE decision = 'review' AND reviewed_at IS NULL;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_status_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_status_check
+    CHECK (status IN ('initiated', 'pending', 'completed', 'failed', 'cancelled', 'reversed', 'refunded', 'expired', 'on_hold'));
@@ -0,0 +1,3 @@
+ALTER TABLE transactions DROP COLUMN IF EXISTS screened_name;
+ALTER TABLE transactions DROP COLUMN IF EXISTS screening_list_version;
+ALTER TABLE transactions DROP COLUMN IF EXISTS screening_result;
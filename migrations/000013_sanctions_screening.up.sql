@@ -0,0 +1,3 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS screening_result TEXT CHECK (screening_result IN ('clear', 'match'));
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS screening_list_version TEXT;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS screened_name TEXT;
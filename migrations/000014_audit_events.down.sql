@@ -0,0 +1,19 @@
+DROP VIEW IF EXISTS audit_log;
+
+DROP TRIGGER IF EXISTS audit_chain_head_no_delete ON audit_chain_head;
+DROP TRIGGER IF EXISTS audit_chain_no_truncate ON audit_chain;
+DROP TRIGGER IF EXISTS audit_chain_no_modify ON audit_chain;
+DROP TRIGGER IF EXISTS audit_events_no_truncate ON audit_events;
+DROP TRIGGER IF EXISTS audit_events_no_modify ON audit_events;
+DROP TRIGGER IF EXISTS audit_events_chain ON audit_events;
+DROP FUNCTION IF EXISTS audit_events_append_only();
+DROP FUNCTION IF EXISTS audit_chain_append();
+
+DROP TABLE IF EXISTS audit_chain_head;
+DROP TABLE IF EXISTS audit_chain;
+
+DROP INDEX IF EXISTS idx_audit_events_created_at;
+DROP INDEX IF EXISTS idx_audit_events_action;
+DROP INDEX IF EXISTS idx_audit_events_actor;
+DROP INDEX IF EXISTS idx_audit_events_entity;
+DROP TABLE IF EXISTS audit_events;
@@ -0,0 +1,105 @@
+-- digest is the SHA-256 of the canonical event payload, computed by the
+-- service when the event is written.
+CREATE TABLE IF NOT EXISTS audit_events (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    actor_id TEXT NOT NULL,
+    actor_type TEXT NOT NULL CHECK (actor_type IN ('user', 'service', 'admin', 'system')),
+    action TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    before JSONB NOT NULL DEFAULT '{}'::jsonb,
+    after JSONB NOT NULL DEFAULT '{}'::jsonb,
+    ip_address TEXT,
+    trace_id TEXT,
+    digest TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_entity ON audit_events(entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events(actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_action ON audit_events(action);
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events(created_at);
+
+-- audit_chain links every committed event to the one before it:
+-- hash = sha256(prev_hash || digest).
+CREATE TABLE IF NOT EXISTS audit_chain (
+    sequence BIGINT PRIMARY KEY,
+    event_id TEXT NOT NULL UNIQUE,
+    prev_hash TEXT NOT NULL,
+    hash TEXT NOT NULL
+);
+
+-- The chain head holds the latest sequence and hash. Writers queue on this
+-- row, so the chain never forks.
+CREATE TABLE IF NOT EXISTS audit_chain_head (
+    id INT PRIMARY KEY CHECK (id = 1),
+    sequence BIGINT NOT NULL,
+    hash TEXT NOT NULL
+);
+
+INSERT INTO audit_chain_head (id, sequence, hash)
+VALUES (1, 0, repeat('0', 64))
+ON CONFLICT (id) DO NOTHING;
+
+-- Events are chained when their transaction commits rather than when they are
+-- inserted. The head row is then the last lock a transaction takes, so it
+-- cannot deadlock with wallet or transaction row locks, and chain order is
+-- commit order.
+CREATE OR REPLACE FUNCTION audit_chain_append() RETURNS trigger AS $$
+DECLARE
+    head audit_chain_head%ROWTYPE;
+    next_hash TEXT;
+BEGIN
+    SELECT * INTO head FROM audit_chain_head WHERE id = 1 FOR UPDATE;
+    next_hash := encode(sha256(convert_to(head.hash || NEW.digest, 'UTF8')), 'hex');
+
+    INSERT INTO audit_chain (sequence, event_id, prev_hash, hash)
+    VALUES (head.sequence + 1, NEW.id, head.hash, next_hash);
+
+    UPDATE audit_chain_head SET sequence = head.sequence + 1, hash = next_hash WHERE id = 1;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_chain ON audit_events;
+CREATE CONSTRAINT TRIGGER audit_events_chain
+    AFTER INSERT ON audit_events
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION audit_chain_append();
+
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION '% is append-only', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_no_modify ON audit_events;
+CREATE TRIGGER audit_events_no_modify
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+DROP TRIGGER IF EXISTS audit_events_no_truncate ON audit_events;
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
+
+DROP TRIGGER IF EXISTS audit_chain_no_modify ON audit_chain;
+CREATE TRIGGER audit_chain_no_modify
+    BEFORE UPDATE OR DELETE ON audit_chain
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+DROP TRIGGER IF EXISTS audit_chain_no_truncate ON audit_chain;
+CREATE TRIGGER audit_chain_no_truncate
+    BEFORE TRUNCATE ON audit_chain
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
+
+DROP TRIGGER IF EXISTS audit_chain_head_no_delete ON audit_chain_head;
+CREATE TRIGGER audit_chain_head_no_delete
+    BEFORE DELETE ON audit_chain_head
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+CREATE OR REPLACE VIEW audit_log AS
+SELECT c.sequence, e.id, e.actor_id, e.actor_type, e.action, e.entity_type, e.entity_id,
+       e.before, e.after, e.ip_address, e.trace_id, e.digest, c.prev_hash, c.hash, e.created_at
+FROM audit_events e
+JOIN audit_chain c ON c.event_id = e.id;
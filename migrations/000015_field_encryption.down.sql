@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_bank_accounts_account_hash_bank;
+ALTER TABLE bank_accounts DROP COLUMN IF EXISTS account_number_hash;
@@ -0,0 +1,15 @@
+-- bank_accounts.account_number, the recipient details stored in
+-- transactions.provider_reference and webhook_events.payload are encrypted by
+-- the service before they are written. Each value carries the ID of the key
+-- that wrapped it (enc:v1:<key id>:...), and rows written before encryption
+-- was enabled are encrypted in place by the re-encryption worker.
+
+-- account_number_hash is a keyed hash of the plaintext account number, so an
+-- account can still be found by number without decrypting every row.
+ALTER TABLE bank_accounts ADD COLUMN IF NOT EXISTS account_number_hash TEXT;
+
+-- Ciphertext is randomised, so the original UNIQUE(account_number, bank_code)
+-- no longer catches duplicates once a row is encrypted; the blind index does.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bank_accounts_account_hash_bank
+    ON bank_accounts(account_number_hash, bank_code)
+    WHERE account_number_hash IS NOT NULL;
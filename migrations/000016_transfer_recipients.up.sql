@@ -0,0 +1,34 @@
+-- The account an external transfer pays out to. Until now it was JSON encoded
+-- into transactions.provider_reference, which the payout worker then
+-- overwrote with the provider's reference. account_number is encrypted by the
+-- service like bank_accounts.account_number.
+CREATE TABLE IF NOT EXISTS transfer_recipients (
+    id TEXT PRIMARY KEY,
+    transaction_id TEXT NOT NULL UNIQUE,
+    account_number TEXT NOT NULL,
+    bank_code TEXT NOT NULL,
+    account_name TEXT,
+    country TEXT,
+    currency TEXT NOT NULL CHECK (currency IN ('USD', 'EUR', 'GBP')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Move recipient details still held in plaintext out of provider_reference.
+-- Encrypted details cannot be read here; they are still read from
+-- provider_reference until the transfer is paid out.
+INSERT INTO transfer_recipients (id, transaction_id, account_number, bank_code, currency, created_at)
+SELECT gen_random_uuid()::text,
+       id,
+       provider_reference::jsonb ->> 'account_number',
+       provider_reference::jsonb ->> 'bank_code',
+       currency,
+       created_at
+FROM transactions
+WHERE type = 'external' AND provider_reference LIKE '{%'
+ON CONFLICT (transaction_id) DO NOTHING;
+
+UPDATE transactions
+SET provider_reference = NULL
+WHERE type = 'external'
+  AND provider_reference LIKE '{%'
+  AND id IN (SELECT transaction_id FROM transfer_recipients);
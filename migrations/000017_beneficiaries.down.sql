@@ -0,0 +1,3 @@
+ALTER TABLE transactions DROP COLUMN IF EXISTS beneficiary_id;
+
+DROP TABLE IF EXISTS beneficiaries;
@@ -0,0 +1,28 @@
+-- Accounts a user has saved to pay again. account_number is encrypted by the
+-- service and account_number_hash is its blind index, as in bank_accounts.
+-- verified_at is when name enquiry last confirmed the account, which the
+-- new-beneficiary cool-down is measured from.
+CREATE TABLE IF NOT EXISTS beneficiaries (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    nickname TEXT,
+    account_number TEXT NOT NULL,
+    account_number_hash TEXT,
+    bank_code TEXT NOT NULL,
+    account_name TEXT NOT NULL,
+    currency TEXT NOT NULL CHECK (currency IN ('USD', 'EUR', 'GBP')),
+    country TEXT,
+    is_internal BOOLEAN NOT NULL,
+    verified_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_beneficiaries_user_id ON beneficiaries(user_id, created_at);
+
+-- A user saves an account once. Rows written without a keyring have no blind
+-- index and fall back to the plaintext number.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_beneficiaries_user_account
+    ON beneficiaries(user_id, bank_code, COALESCE(account_number_hash, account_number));
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS beneficiary_id TEXT;
@@ -0,0 +1,15 @@
+-- The bank directory, imported from BANK_DIRECTORY_FILE at startup. Transfers
+-- and name enquiries only accept active bank codes. preferred_provider names
+-- the provider payouts and name enquiries for the bank are sent to first.
+CREATE TABLE IF NOT EXISTS banks (
+    code TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    country TEXT NOT NULL CHECK (country ~ '^[A-Z]{2}$'),
+    currencies TEXT[] NOT NULL CHECK (cardinality(currencies) > 0),
+    preferred_provider TEXT,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_banks_active_name ON banks(name) WHERE active;
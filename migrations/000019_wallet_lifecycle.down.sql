@@ -0,0 +1,9 @@
+DROP SEQUENCE IF EXISTS virtual_account_number_seq;
+
+DROP INDEX IF EXISTS idx_wallets_user_currency_open;
+
+ALTER TABLE wallets DROP CONSTRAINT IF EXISTS wallets_frozen_direction_check;
+ALTER TABLE wallets DROP COLUMN IF EXISTS closed_at;
+ALTER TABLE wallets DROP COLUMN IF EXISTS status_reason;
+ALTER TABLE wallets DROP COLUMN IF EXISTS frozen_direction;
+ALTER TABLE wallets DROP COLUMN IF EXISTS status;
@@ -0,0 +1,18 @@
+-- Wallet lifecycle. A frozen wallet blocks debits, credits or both, as named
+-- by frozen_direction; a closed wallet blocks both for good and no longer
+-- counts as the user's wallet for its currency, so a new one can be opened.
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'frozen', 'closed'));
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS frozen_direction TEXT
+    CHECK (frozen_direction IN ('debits', 'credits', 'both'));
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS status_reason TEXT;
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE wallets ADD CONSTRAINT wallets_frozen_direction_check
+    CHECK ((status = 'frozen') = (frozen_direction IS NOT NULL));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wallets_user_currency_open
+    ON wallets(user_id, currency) WHERE status <> 'closed';
+
+-- Virtual account numbers for new wallets are VIRTUAL_ACCOUNT_BIN followed by
+-- the next value of this sequence and a Luhn check digit.
+CREATE SEQUENCE IF NOT EXISTS virtual_account_number_seq START 1;
@@ -0,0 +1,38 @@
+-- User onboarding. kyc_tier is the verification level: 0 for a registered but
+-- unverified user, 1 once an identity document is approved, and 2 once a proof
+-- of address is approved as well. Users that existed before onboarding were
+-- set up by hand, so they keep full access: the column is added with a
+-- default of 2 to backfill them, and new registrations start at 0.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS country TEXT CHECK (country ~ '^[A-Z]{2}$');
+ALTER TABLE users ADD COLUMN IF NOT EXISTS date_of_birth DATE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS kyc_tier SMALLINT NOT NULL DEFAULT 2
+    CHECK (kyc_tier BETWEEN 0 AND 2);
+ALTER TABLE users ALTER COLUMN kyc_tier SET DEFAULT 0;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users(lower(email)) WHERE email IS NOT NULL;
+
+-- Documents submitted for KYC review. document_number is encrypted like other
+-- account identifiers. A user has at most one pending document per category;
+-- a rejected document can be replaced by submitting another.
+CREATE TABLE IF NOT EXISTS kyc_documents (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    category TEXT NOT NULL CHECK (category IN ('identity', 'address')),
+    document_type TEXT NOT NULL,
+    document_number TEXT NOT NULL,
+    issuing_country TEXT NOT NULL CHECK (issuing_country ~ '^[A-Z]{2}$'),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    reviewer_id TEXT,
+    review_note TEXT,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK ((status = 'pending') = (reviewed_at IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_kyc_documents_user_id ON kyc_documents(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_kyc_documents_status ON kyc_documents(status, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_kyc_documents_pending
+    ON kyc_documents(user_id, category) WHERE status = 'pending';
@@ -0,0 +1,6 @@
+-- Deposits are external transactions paid into a wallet, so they have no
+-- source wallet. A provider may notify the same credit more than once; the
+-- provider's reference identifies it.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_deposit_reference
+    ON transactions(provider_name, provider_reference)
+    WHERE type = 'external' AND from_wallet_id IS NULL;
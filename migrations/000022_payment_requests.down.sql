@@ -0,0 +1,11 @@
+DELETE FROM outbox WHERE job_type = 'notification';
+
+ALTER TABLE outbox DROP CONSTRAINT IF EXISTS outbox_job_type_check;
+ALTER TABLE outbox ADD CONSTRAINT outbox_job_type_check
+    CHECK (job_type IN ('payout', 'webhook'));
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS payment_request_id;
+
+DROP INDEX IF EXISTS idx_payment_requests_pending_expiry;
+DROP INDEX IF EXISTS idx_payment_requests_requester_id;
+DROP TABLE IF EXISTS payment_requests;
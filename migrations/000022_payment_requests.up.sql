@@ -0,0 +1,29 @@
+-- A request for money from whoever holds the token. wallet_id is the
+-- requester's wallet in the requested currency, fixed when the request is
+-- made. transaction_id is the transfer that paid it.
+CREATE TABLE IF NOT EXISTS payment_requests (
+    id TEXT PRIMARY KEY,
+    requester_id TEXT NOT NULL,
+    wallet_id TEXT NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency TEXT NOT NULL CHECK (currency IN ('USD', 'EUR', 'GBP')),
+    memo TEXT,
+    token TEXT NOT NULL UNIQUE,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'paid', 'expired', 'cancelled')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    transaction_id TEXT,
+    paid_by TEXT,
+    paid_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK ((status = 'paid') = (transaction_id IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_requests_requester_id ON payment_requests(requester_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_payment_requests_pending_expiry ON payment_requests(expires_at) WHERE status = 'pending';
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS payment_request_id TEXT;
+
+ALTER TABLE outbox DROP CONSTRAINT IF EXISTS outbox_job_type_check;
+ALTER TABLE outbox ADD CONSTRAINT outbox_job_type_check
+    CHECK (job_type IN ('payout', 'webhook', 'notification'));
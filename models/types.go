@@ -1,6 +1,7 @@
 package models
 
 import (
+	"slices"
 	"time"
 
 	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
@@ -11,6 +12,8 @@ type TransactionType string
 const (
 	TransactionTypeInternal TransactionType = "internal"
 	TransactionTypeExternal TransactionType = "external"
+	TransactionTypeReversal TransactionType = "reversal"
+	TransactionTypeRefund   TransactionType = "refund"
 )
 
 type TransactionStatus string
@@ -20,41 +23,442 @@ const (
 	TransactionStatusPending   TransactionStatus = "pending"
 	TransactionStatusCompleted TransactionStatus = "completed"
 	TransactionStatusFailed    TransactionStatus = "failed"
+	TransactionStatusCancelled TransactionStatus = "cancelled"
+	TransactionStatusReversed  TransactionStatus = "reversed"
+	TransactionStatusRefunded  TransactionStatus = "refunded"
+	TransactionStatusExpired   TransactionStatus = "expired"
+	TransactionStatusOnHold    TransactionStatus = "on_hold"
+)
+
+type RiskDecision string
+
+const (
+	RiskDecisionAllow  RiskDecision = "allow"
+	RiskDecisionReview RiskDecision = "review"
+	RiskDecisionDeny   RiskDecision = "deny"
+)
+
+type RiskReviewDecision string
+
+const (
+	RiskReviewApproved RiskReviewDecision = "approved"
+	RiskReviewRejected RiskReviewDecision = "rejected"
+)
+
+// ScreeningResult is the outcome of sanctions screening an external payout's
+// beneficiary. Transactions that were not screened have no result.
+type ScreeningResult string
+
+const (
+	ScreeningResultClear ScreeningResult = "clear"
+	ScreeningResultMatch ScreeningResult = "match"
+)
+
+type WalletHoldStatus string
+
+const (
+	WalletHoldStatusActive   WalletHoldStatus = "active"
+	WalletHoldStatusCaptured WalletHoldStatus = "captured"
+	WalletHoldStatusReleased WalletHoldStatus = "released"
+)
+
+type WalletStatus string
+
+const (
+	WalletStatusActive WalletStatus = "active"
+	WalletStatusFrozen WalletStatus = "frozen"
+	WalletStatusClosed WalletStatus = "closed"
+)
+
+// FreezeDirection names the movements a frozen wallet blocks.
+type FreezeDirection string
+
+const (
+	FreezeDebits  FreezeDirection = "debits"
+	FreezeCredits FreezeDirection = "credits"
+	FreezeBoth    FreezeDirection = "both"
+)
+
+// KYCTier is how far a user's identity has been verified. It decides which
+// currencies the user may hold and which transfer limit tier applies.
+type KYCTier int
+
+const (
+	KYCTierUnverified KYCTier = 0
+	KYCTierIdentity   KYCTier = 1
+	KYCTierFull       KYCTier = 2
+)
+
+// KYCCategory is what a KYC document proves: who the user is, or where they
+// live.
+type KYCCategory string
+
+const (
+	KYCCategoryIdentity KYCCategory = "identity"
+	KYCCategoryAddress  KYCCategory = "address"
+)
+
+type KYCDocumentStatus string
+
+const (
+	KYCDocumentPending  KYCDocumentStatus = "pending"
+	KYCDocumentApproved KYCDocumentStatus = "approved"
+	KYCDocumentRejected KYCDocumentStatus = "rejected"
 )
 
 type User struct {
-	ID        string
-	Name      *string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID          string
+	Name        *string
+	Email       *string
+	Phone       *string
+	Country     *string
+	DateOfBirth *time.Time
+	KYCTier     KYCTier
+	LimitTier   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// UserProfileUpdate lists the profile fields to change. Nil fields are left as
+// they are.
+type UserProfileUpdate struct {
+	Name        *string
+	Email       *string
+	Phone       *string
+	Country     *string
+	DateOfBirth *time.Time
+}
+
+// UserProfile is a user with what their KYC tier allows and the documents
+// they have submitted, newest first.
+type UserProfile struct {
+	User
+	AllowedCurrencies []string
+	Documents         []*KYCDocument
+}
+
+type KYCDocument struct {
+	ID             string
+	UserID         string
+	Category       KYCCategory
+	DocumentType   string
+	DocumentNumber string
+	IssuingCountry string
+	Status         KYCDocumentStatus
+	ReviewerID     *string
+	ReviewNote     *string
+	ReviewedAt     *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// KYCSubmission is a document a user submits for review.
+type KYCSubmission struct {
+	DocumentType   string
+	DocumentNumber string
+	IssuingCountry string
 }
 
 type Wallet struct {
+	ID              string
+	UserID          string
+	BankAccountID   *string
+	Currency        string
+	Balance         int64
+	HeldBalance     int64
+	Status          WalletStatus
+	FrozenDirection *FreezeDirection
+	StatusReason    *string
+	ClosedAt        *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// AvailableBalance is the part of the balance not reserved by holds for
+// initiated transfers.
+func (w *Wallet) AvailableBalance() int64 {
+	return w.Balance - w.HeldBalance
+}
+
+// AllowsDebits reports whether money may leave the wallet.
+func (w *Wallet) AllowsDebits() bool {
+	return w.allows(FreezeDebits)
+}
+
+// AllowsCredits reports whether money may enter the wallet.
+func (w *Wallet) AllowsCredits() bool {
+	return w.allows(FreezeCredits)
+}
+
+func (w *Wallet) allows(direction FreezeDirection) bool {
+	switch w.Status {
+	case WalletStatusClosed:
+		return false
+	case WalletStatusFrozen:
+		return w.FrozenDirection != nil && *w.FrozenDirection != direction && *w.FrozenDirection != FreezeBoth
+	default:
+		return true
+	}
+}
+
+type Transaction struct {
+	ID                  string
+	IdempotencyKey      string
+	TraceID             *string
+	FromWalletID        *string
+	ToWalletID          *string
+	Type                TransactionType
+	Amount              int64
+	Currency            string
+	Status              TransactionStatus
+	ProviderName        *string
+	ProviderReference   *string
+	ExchangeRate        *float64
+	FailureReason       *string
+	FeeAmount           int64
+	FeeCurrency         *string
+	ParentTransactionID *string
+	// ScreeningResult, ScreeningListVersion and ScreenedName are set once an
+	// external payout's beneficiary has been sanctions screened.
+	ScreeningResult      *ScreeningResult
+	ScreeningListVersion *string
+	ScreenedName         *string
+	BeneficiaryID        *string
+	PaymentRequestID     *string
+	Recipient            *TransferRecipient
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// TransferRecipient is the beneficiary of an external transfer. AccountName
+// and Country are as resolved by name enquiry when the transfer was created.
+type TransferRecipient struct {
+	AccountNumber string  `json:"account_number"`
+	BankCode      string  `json:"bank_code"`
+	AccountName   *string `json:"account_name,omitempty"`
+	Country       *string `json:"country,omitempty"`
+	Currency      string  `json:"currency"`
+}
+
+// Bank is a bank directory entry. PreferredProvider is the provider payouts
+// and name enquiries for the bank go to first, when it has one.
+type Bank struct {
+	Code              string
+	Name              string
+	Country           string
+	Currencies        []string
+	PreferredProvider *string
+}
+
+func (b *Bank) SupportsCurrency(currency string) bool {
+	return slices.Contains(b.Currencies, currency)
+}
+
+// Beneficiary is an account a user has saved to pay again. AccountName,
+// Currency and IsInternal are as resolved by name enquiry at VerifiedAt.
+type Beneficiary struct {
 	ID            string
 	UserID        string
-	BankAccountID *string
+	Nickname      *string
+	AccountNumber string
+	BankCode      string
+	AccountName   string
 	Currency      string
-	Balance       int64
+	Country       *string
+	IsInternal    bool
+	VerifiedAt    time.Time
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
 
-type Transaction struct {
-	ID                string
-	IdempotencyKey    string
-	TraceID           *string
-	FromWalletID      *string
-	ToWalletID        *string
-	Type              TransactionType
-	Amount            int64
+// BeneficiaryUpdate lists the fields of a beneficiary to change. Nil fields are
+// left as they are, and an empty Nickname clears it.
+type BeneficiaryUpdate struct {
+	Nickname      *string
+	AccountNumber *string
+	BankCode      *string
+}
+
+type Refund struct {
+	ID                  string
+	TransactionID       string
+	RefundTransactionID string
+	Amount              int64
+	Currency            string
+	CreditedAmount      int64
+	CreditedCurrency    string
+	Reason              string
+	IdempotencyKey      string
+	CreatedBy           string
+	CreatedAt           time.Time
+}
+
+// WebhookEventCreditReceived is the webhook event a provider sends when money
+// is paid into one of our virtual accounts.
+const WebhookEventCreditReceived = "credit.received"
+
+// Deposit is a provider's notice of money paid into a virtual account. The
+// provider name and reference identify it, so a repeated notice is not
+// credited twice.
+type Deposit struct {
+	ProviderName      string
+	ProviderReference string
+	AccountNumber     string
+	BankCode          string
+	Amount            money.Money
+}
+
+type PaymentRequestStatus string
+
+const (
+	PaymentRequestPending   PaymentRequestStatus = "pending"
+	PaymentRequestPaid      PaymentRequestStatus = "paid"
+	PaymentRequestExpired   PaymentRequestStatus = "expired"
+	PaymentRequestCancelled PaymentRequestStatus = "cancelled"
+)
+
+// PaymentRequest asks for money from whoever holds Token. WalletID is the
+// requester's wallet in Currency, which the paying transfer credits.
+type PaymentRequest struct {
+	ID            string
+	RequesterID   string
+	WalletID      string
+	Amount        int64
+	Currency      string
+	Memo          *string
+	Token         string
+	Status        PaymentRequestStatus
+	ExpiresAt     time.Time
+	TransactionID *string
+	PaidBy        *string
+	PaidAt        *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// NewPaymentRequest is a request a user makes. A nil ExpiresAt takes the
+// configured default.
+type NewPaymentRequest struct {
+	Amount    money.Money
+	Memo      *string
+	ExpiresAt *time.Time
+}
+
+// PINResetToken is an admin-issued, single-use token that lets a user set a new
+// PIN without the old one. Token is only available when the token is created.
+type PINResetToken struct {
+	UserID    string
+	Token     string
+	ExpiresAt time.Time
+}
+
+// TOTPEnrollment is a pending authenticator secret. It only becomes active once
+// the user proves their app produces matching codes.
+type TOTPEnrollment struct {
+	UserID          string
+	Secret          string
+	ProvisioningURI string
+}
+
+// TransferLimitUsage is a user's allowance for one source currency and
+// transaction type. Amounts are in minor units of that currency, and a nil
+// limit means the dimension is not capped.
+type TransferLimitUsage struct {
 	Currency          string
-	Status            TransactionStatus
-	ProviderName      *string
-	ProviderReference *string
-	ExchangeRate      *float64
-	FailureReason     *string
-	CreatedAt         time.Time
-	UpdatedAt         time.Time
+	TransactionType   TransactionType
+	PerTransactionMax *int64
+	Daily             LimitWindow
+	Monthly           LimitWindow
+}
+
+type LimitWindow struct {
+	AmountLimit *int64
+	AmountUsed  int64
+	CountLimit  *int32
+	CountUsed   int32
+	ResetsAt    time.Time
+}
+
+type UserLimits struct {
+	UserID string
+	Tier   string
+	Limits []*TransferLimitUsage
+}
+
+// RiskReason is one screening rule that fired, with the score it added.
+type RiskReason struct {
+	Rule   string `json:"rule"`
+	Score  int    `json:"score"`
+	Detail string `json:"detail"`
+}
+
+// RiskAssessment is the outcome of screening a transaction at confirmation.
+// Features holds the inputs the rules saw, kept so decisions can be replayed
+// or used as training data.
+type RiskAssessment struct {
+	ID             string
+	TransactionID  string
+	UserID         string
+	Recipient      string
+	Decision       RiskDecision
+	Score          int
+	Reasons        []RiskReason
+	Features       map[string]any
+	ReviewDecision *RiskReviewDecision
+	ReviewedBy     *string
+	ReviewNote     *string
+	ReviewedAt     *time.Time
+	CreatedAt      time.Time
+}
+
+// AuditEvent is one entry in the audit log. Hash chains it to the entry
+// before it, so any edit or removal breaks verification from that point on.
+type AuditEvent struct {
+	Sequence   int64
+	ID         string
+	ActorID    string
+	ActorType  string
+	Action     string
+	EntityType string
+	EntityID   string
+	Before     map[string]any
+	After      map[string]any
+	IPAddress  *string
+	TraceID    *string
+	PrevHash   string
+	Hash       string
+	CreatedAt  time.Time
+}
+
+// AuditEventFilter narrows an audit log query. Empty fields match everything.
+type AuditEventFilter struct {
+	EntityType string
+	EntityID   string
+	ActorID    string
+	Action     string
+	From       *time.Time
+	To         *time.Time
+	Cursor     string
+	Limit      int32
+}
+
+type AuditEventPage struct {
+	Events     []*AuditEvent
+	NextCursor *string
+}
+
+// AuditChainVerification is the result of checking a range of the audit
+// chain. BrokenAt and Problem are set when Valid is false. Complete is true
+// when the range reached the newest event.
+type AuditChainVerification struct {
+	FromSequence int64
+	Checked      int
+	Valid        bool
+	Complete     bool
+	BrokenAt     *int64
+	Problem      string
+	LastSequence int64
+	LastHash     string
 }
 
 type LedgerEntry struct {
@@ -100,22 +504,34 @@ type WebhookEvent struct {
 }
 
 type NameEnquiryResponse struct {
-	AccountName string `json:"account_name"`
-	IsInternal  bool   `json:"is_internal"`
-	Currency    string `json:"currency"`
+	AccountName string             `json:"account_name"`
+	IsInternal  bool               `json:"is_internal"`
+	Currency    string             `json:"currency"`
+	Country     string             `json:"country,omitempty"`
+	NameMatch   *NameMatchResponse `json:"name_match,omitempty"`
+}
+
+// NameMatchResponse scores the account name a caller supplied against the
+// resolved account name. Verdict is match, partial or mismatch.
+type NameMatchResponse struct {
+	Score   float64 `json:"score"`
+	Verdict string  `json:"verdict"`
 }
 
 type WalletWithBankAccount struct {
-	ID            string    `json:"id"`
-	Currency      string    `json:"currency"`
-	Balance       int64     `json:"balance"`
-	AccountNumber *string   `json:"account_number,omitempty"`
-	BankName      *string   `json:"bank_name,omitempty"`
-	BankCode      *string   `json:"bank_code,omitempty"`
-	AccountName   *string   `json:"account_name,omitempty"`
-	Provider      *string   `json:"provider,omitempty"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID               string           `json:"id"`
+	Currency         string           `json:"currency"`
+	Balance          int64            `json:"balance"`
+	AvailableBalance int64            `json:"available_balance"`
+	Status           WalletStatus     `json:"status"`
+	FrozenDirection  *FreezeDirection `json:"frozen_direction,omitempty"`
+	AccountNumber    *string          `json:"account_number,omitempty"`
+	BankName         *string          `json:"bank_name,omitempty"`
+	BankCode         *string          `json:"bank_code,omitempty"`
+	AccountName      *string          `json:"account_name,omitempty"`
+	Provider         *string          `json:"provider,omitempty"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
 }
 
 type TransactionHistoryResponse struct {
@@ -136,68 +552,507 @@ type TestUsersResponse struct {
 
 // Response DTOs with amounts in major units (dollars/euros/pounds)
 type TransactionResponse struct {
-	ID                string    `json:"id"`
-	IdempotencyKey    string    `json:"idempotency_key"`
-	FromWalletID      *string   `json:"from_wallet_id,omitempty"`
-	ToWalletID        *string   `json:"to_wallet_id,omitempty"`
-	Type              string    `json:"type"`
-	Amount            float64   `json:"amount"`
-	Currency          string    `json:"currency"`
-	Status            string    `json:"status"`
-	ProviderName      *string   `json:"provider_name,omitempty"`
-	ProviderReference *string   `json:"provider_reference,omitempty"`
-	ExchangeRate      *float64  `json:"exchange_rate,omitempty"`
-	FailureReason     *string   `json:"failure_reason,omitempty"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID                   string             `json:"id"`
+	IdempotencyKey       string             `json:"idempotency_key"`
+	FromWalletID         *string            `json:"from_wallet_id,omitempty"`
+	ToWalletID           *string            `json:"to_wallet_id,omitempty"`
+	Type                 string             `json:"type"`
+	Amount               float64            `json:"amount"`
+	Currency             string             `json:"currency"`
+	Status               string             `json:"status"`
+	ProviderName         *string            `json:"provider_name,omitempty"`
+	ProviderReference    *string            `json:"provider_reference,omitempty"`
+	ExchangeRate         *float64           `json:"exchange_rate,omitempty"`
+	FailureReason        *string            `json:"failure_reason,omitempty"`
+	FeeAmount            float64            `json:"fee_amount"`
+	FeeCurrency          *string            `json:"fee_currency,omitempty"`
+	ParentTransactionID  *string            `json:"parent_transaction_id,omitempty"`
+	ScreeningResult      *string            `json:"screening_result,omitempty"`
+	ScreeningListVersion *string            `json:"screening_list_version,omitempty"`
+	BeneficiaryID        *string            `json:"beneficiary_id,omitempty"`
+	PaymentRequestID     *string            `json:"payment_request_id,omitempty"`
+	Recipient            *TransferRecipient `json:"recipient,omitempty"`
+	Refunds              []*RefundResponse  `json:"refunds,omitempty"`
+	CreatedAt            time.Time          `json:"created_at"`
+	UpdatedAt            time.Time          `json:"updated_at"`
 }
 
 func TransactionToResponse(tx *Transaction) *TransactionResponse {
 	amount := money.ToMajorUnits(tx.Amount)
 	return &TransactionResponse{
-		ID:                tx.ID,
-		IdempotencyKey:    tx.IdempotencyKey,
-		FromWalletID:      tx.FromWalletID,
-		ToWalletID:        tx.ToWalletID,
-		Type:              string(tx.Type),
-		Amount:            amount,
-		Currency:          tx.Currency,
-		Status:            string(tx.Status),
-		ProviderName:      tx.ProviderName,
-		ProviderReference: tx.ProviderReference,
-		ExchangeRate:      tx.ExchangeRate,
-		FailureReason:     tx.FailureReason,
-		CreatedAt:         tx.CreatedAt,
-		UpdatedAt:         tx.UpdatedAt,
+		ID:                   tx.ID,
+		IdempotencyKey:       tx.IdempotencyKey,
+		FromWalletID:         tx.FromWalletID,
+		ToWalletID:           tx.ToWalletID,
+		Type:                 string(tx.Type),
+		Amount:               amount,
+		Currency:             tx.Currency,
+		Status:               string(tx.Status),
+		ProviderName:         tx.ProviderName,
+		ProviderReference:    tx.ProviderReference,
+		ExchangeRate:         tx.ExchangeRate,
+		FailureReason:        tx.FailureReason,
+		FeeAmount:            money.ToMajorUnits(tx.FeeAmount),
+		FeeCurrency:          tx.FeeCurrency,
+		ParentTransactionID:  tx.ParentTransactionID,
+		ScreeningResult:      (*string)(tx.ScreeningResult),
+		ScreeningListVersion: tx.ScreeningListVersion,
+		BeneficiaryID:        tx.BeneficiaryID,
+		PaymentRequestID:     tx.PaymentRequestID,
+		Recipient:            tx.Recipient,
+		CreatedAt:            tx.CreatedAt,
+		UpdatedAt:            tx.UpdatedAt,
 	}
 }
 
-type WalletWithBankAccountResponse struct {
+// BankResponse leaves out the preferred provider, which is routing detail.
+type BankResponse struct {
+	Code       string   `json:"code"`
+	Name       string   `json:"name"`
+	Country    string   `json:"country"`
+	Currencies []string `json:"currencies"`
+}
+
+func BankToResponse(b *Bank) *BankResponse {
+	return &BankResponse{
+		Code:       b.Code,
+		Name:       b.Name,
+		Country:    b.Country,
+		Currencies: b.Currencies,
+	}
+}
+
+type BeneficiaryResponse struct {
 	ID            string    `json:"id"`
+	Nickname      *string   `json:"nickname,omitempty"`
+	AccountNumber string    `json:"account_number"`
+	BankCode      string    `json:"bank_code"`
+	AccountName   string    `json:"account_name"`
 	Currency      string    `json:"currency"`
-	Balance       float64   `json:"balance"`
-	AccountNumber *string   `json:"account_number,omitempty"`
-	BankName      *string   `json:"bank_name,omitempty"`
-	BankCode      *string   `json:"bank_code,omitempty"`
-	AccountName   *string   `json:"account_name,omitempty"`
-	Provider      *string   `json:"provider,omitempty"`
+	Country       *string   `json:"country,omitempty"`
+	IsInternal    bool      `json:"is_internal"`
+	VerifiedAt    time.Time `json:"verified_at"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 }
 
+func BeneficiaryToResponse(b *Beneficiary) *BeneficiaryResponse {
+	return &BeneficiaryResponse{
+		ID:            b.ID,
+		Nickname:      b.Nickname,
+		AccountNumber: b.AccountNumber,
+		BankCode:      b.BankCode,
+		AccountName:   b.AccountName,
+		Currency:      b.Currency,
+		Country:       b.Country,
+		IsInternal:    b.IsInternal,
+		VerifiedAt:    b.VerifiedAt,
+		CreatedAt:     b.CreatedAt,
+		UpdatedAt:     b.UpdatedAt,
+	}
+}
+
+type UserProfileResponse struct {
+	ID                string                 `json:"id"`
+	Name              *string                `json:"name,omitempty"`
+	Email             *string                `json:"email,omitempty"`
+	Phone             *string                `json:"phone,omitempty"`
+	Country           *string                `json:"country,omitempty"`
+	DateOfBirth       *string                `json:"date_of_birth,omitempty"`
+	KYCTier           KYCTier                `json:"kyc_tier"`
+	LimitTier         string                 `json:"limit_tier"`
+	AllowedCurrencies []string               `json:"allowed_currencies"`
+	Documents         []*KYCDocumentResponse `json:"documents"`
+	CreatedAt         time.Time              `json:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+}
+
+func UserProfileToResponse(p *UserProfile) *UserProfileResponse {
+	response := &UserProfileResponse{
+		ID:                p.ID,
+		Name:              p.Name,
+		Email:             p.Email,
+		Phone:             p.Phone,
+		Country:           p.Country,
+		KYCTier:           p.KYCTier,
+		LimitTier:         p.LimitTier,
+		AllowedCurrencies: p.AllowedCurrencies,
+		Documents:         make([]*KYCDocumentResponse, 0, len(p.Documents)),
+		CreatedAt:         p.CreatedAt,
+		UpdatedAt:         p.UpdatedAt,
+	}
+	if response.AllowedCurrencies == nil {
+		response.AllowedCurrencies = []string{}
+	}
+	if p.DateOfBirth != nil {
+		dateOfBirth := p.DateOfBirth.Format(time.DateOnly)
+		response.DateOfBirth = &dateOfBirth
+	}
+	for _, document := range p.Documents {
+		response.Documents = append(response.Documents, KYCDocumentToResponse(document))
+	}
+	return response
+}
+
+type KYCDocumentResponse struct {
+	ID             string            `json:"id"`
+	UserID         string            `json:"user_id"`
+	Category       KYCCategory       `json:"category"`
+	DocumentType   string            `json:"document_type"`
+	DocumentNumber string            `json:"document_number"`
+	IssuingCountry string            `json:"issuing_country"`
+	Status         KYCDocumentStatus `json:"status"`
+	ReviewerID     *string           `json:"reviewer_id,omitempty"`
+	ReviewNote     *string           `json:"review_note,omitempty"`
+	ReviewedAt     *time.Time        `json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+func KYCDocumentToResponse(d *KYCDocument) *KYCDocumentResponse {
+	return &KYCDocumentResponse{
+		ID:             d.ID,
+		UserID:         d.UserID,
+		Category:       d.Category,
+		DocumentType:   d.DocumentType,
+		DocumentNumber: d.DocumentNumber,
+		IssuingCountry: d.IssuingCountry,
+		Status:         d.Status,
+		ReviewerID:     d.ReviewerID,
+		ReviewNote:     d.ReviewNote,
+		ReviewedAt:     d.ReviewedAt,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
+
+// PaymentRequestResponse is what anyone holding the token sees. The paying
+// transfer and payer are only shown to the requester.
+type PaymentRequestResponse struct {
+	ID            string               `json:"id"`
+	Amount        float64              `json:"amount"`
+	Currency      string               `json:"currency"`
+	Memo          *string              `json:"memo,omitempty"`
+	Token         string               `json:"token"`
+	Status        PaymentRequestStatus `json:"status"`
+	ExpiresAt     time.Time            `json:"expires_at"`
+	TransactionID *string              `json:"transaction_id,omitempty"`
+	PaidBy        *string              `json:"paid_by,omitempty"`
+	PaidAt        *time.Time           `json:"paid_at,omitempty"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+func PaymentRequestToResponse(r *PaymentRequest, viewerID string) *PaymentRequestResponse {
+	response := &PaymentRequestResponse{
+		ID:        r.ID,
+		Amount:    money.ToMajorUnits(r.Amount),
+		Currency:  r.Currency,
+		Memo:      r.Memo,
+		Token:     r.Token,
+		Status:    r.Status,
+		ExpiresAt: r.ExpiresAt,
+		PaidAt:    r.PaidAt,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+	if viewerID == r.RequesterID {
+		response.TransactionID = r.TransactionID
+		response.PaidBy = r.PaidBy
+	}
+	return response
+}
+
+type RefundResponse struct {
+	ID                  string    `json:"id"`
+	TransactionID       string    `json:"transaction_id"`
+	RefundTransactionID string    `json:"refund_transaction_id"`
+	Amount              float64   `json:"amount"`
+	Currency            string    `json:"currency"`
+	CreditedAmount      float64   `json:"credited_amount"`
+	CreditedCurrency    string    `json:"credited_currency"`
+	Reason              string    `json:"reason"`
+	CreatedBy           string    `json:"created_by"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+func RefundToResponse(r *Refund) *RefundResponse {
+	return &RefundResponse{
+		ID:                  r.ID,
+		TransactionID:       r.TransactionID,
+		RefundTransactionID: r.RefundTransactionID,
+		Amount:              money.ToMajorUnits(r.Amount),
+		Currency:            r.Currency,
+		CreditedAmount:      money.ToMajorUnits(r.CreditedAmount),
+		CreditedCurrency:    r.CreditedCurrency,
+		Reason:              r.Reason,
+		CreatedBy:           r.CreatedBy,
+		CreatedAt:           r.CreatedAt,
+	}
+}
+
+type PINResetTokenResponse struct {
+	UserID    string    `json:"user_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func PINResetTokenToResponse(t *PINResetToken) *PINResetTokenResponse {
+	return &PINResetTokenResponse{
+		UserID:    t.UserID,
+		Token:     t.Token,
+		ExpiresAt: t.ExpiresAt,
+	}
+}
+
+type TOTPEnrollmentResponse struct {
+	UserID          string `json:"user_id"`
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+func TOTPEnrollmentToResponse(e *TOTPEnrollment) *TOTPEnrollmentResponse {
+	return &TOTPEnrollmentResponse{
+		UserID:          e.UserID,
+		Secret:          e.Secret,
+		ProvisioningURI: e.ProvisioningURI,
+	}
+}
+
+type LimitWindowResponse struct {
+	AmountLimit     *float64  `json:"amount_limit"`
+	AmountUsed      float64   `json:"amount_used"`
+	AmountRemaining *float64  `json:"amount_remaining"`
+	CountLimit      *int32    `json:"count_limit"`
+	CountUsed       int32     `json:"count_used"`
+	CountRemaining  *int32    `json:"count_remaining"`
+	ResetsAt        time.Time `json:"resets_at"`
+}
+
+func LimitWindowToResponse(w LimitWindow) LimitWindowResponse {
+	resp := LimitWindowResponse{
+		AmountUsed: money.ToMajorUnits(w.AmountUsed),
+		CountUsed:  w.CountUsed,
+		CountLimit: w.CountLimit,
+		ResetsAt:   w.ResetsAt,
+	}
+	if w.AmountLimit != nil {
+		limit := money.ToMajorUnits(*w.AmountLimit)
+		remaining := money.ToMajorUnits(max(*w.AmountLimit-w.AmountUsed, 0))
+		resp.AmountLimit = &limit
+		resp.AmountRemaining = &remaining
+	}
+	if w.CountLimit != nil {
+		remaining := max(*w.CountLimit-w.CountUsed, 0)
+		resp.CountRemaining = &remaining
+	}
+	return resp
+}
+
+type TransferLimitResponse struct {
+	Currency          string              `json:"currency"`
+	TransactionType   string              `json:"transaction_type"`
+	PerTransactionMax *float64            `json:"per_transaction_max"`
+	Daily             LimitWindowResponse `json:"daily"`
+	Monthly           LimitWindowResponse `json:"monthly"`
+}
+
+type UserLimitsResponse struct {
+	UserID string                   `json:"user_id"`
+	Tier   string                   `json:"tier"`
+	Limits []*TransferLimitResponse `json:"limits"`
+}
+
+func UserLimitsToResponse(l *UserLimits) *UserLimitsResponse {
+	limits := make([]*TransferLimitResponse, 0, len(l.Limits))
+	for _, limit := range l.Limits {
+		resp := &TransferLimitResponse{
+			Currency:        limit.Currency,
+			TransactionType: string(limit.TransactionType),
+			Daily:           LimitWindowToResponse(limit.Daily),
+			Monthly:         LimitWindowToResponse(limit.Monthly),
+		}
+		if limit.PerTransactionMax != nil {
+			perTransaction := money.ToMajorUnits(*limit.PerTransactionMax)
+			resp.PerTransactionMax = &perTransaction
+		}
+		limits = append(limits, resp)
+	}
+	return &UserLimitsResponse{
+		UserID: l.UserID,
+		Tier:   l.Tier,
+		Limits: limits,
+	}
+}
+
+type RiskAssessmentResponse struct {
+	ID             string         `json:"id"`
+	TransactionID  string         `json:"transaction_id"`
+	UserID         string         `json:"user_id"`
+	Recipient      string         `json:"recipient"`
+	Decision       string         `json:"decision"`
+	Score          int            `json:"score"`
+	Reasons        []RiskReason   `json:"reasons"`
+	Features       map[string]any `json:"features"`
+	ReviewDecision *string        `json:"review_decision,omitempty"`
+	ReviewedBy     *string        `json:"reviewed_by,omitempty"`
+	ReviewNote     *string        `json:"review_note,omitempty"`
+	ReviewedAt     *time.Time     `json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+func RiskAssessmentToResponse(a *RiskAssessment) *RiskAssessmentResponse {
+	resp := &RiskAssessmentResponse{
+		ID:            a.ID,
+		TransactionID: a.TransactionID,
+		UserID:        a.UserID,
+		Recipient:     a.Recipient,
+		Decision:      string(a.Decision),
+		Score:         a.Score,
+		Reasons:       a.Reasons,
+		Features:      a.Features,
+		ReviewedBy:    a.ReviewedBy,
+		ReviewNote:    a.ReviewNote,
+		ReviewedAt:    a.ReviewedAt,
+		CreatedAt:     a.CreatedAt,
+	}
+	if resp.Reasons == nil {
+		resp.Reasons = []RiskReason{}
+	}
+	if a.ReviewDecision != nil {
+		decision := string(*a.ReviewDecision)
+		resp.ReviewDecision = &decision
+	}
+	return resp
+}
+
+type AuditEventResponse struct {
+	Sequence   int64          `json:"sequence"`
+	ID         string         `json:"id"`
+	ActorID    string         `json:"actor_id"`
+	ActorType  string         `json:"actor_type"`
+	Action     string         `json:"action"`
+	EntityType string         `json:"entity_type"`
+	EntityID   string         `json:"entity_id"`
+	Before     map[string]any `json:"before"`
+	After      map[string]any `json:"after"`
+	IPAddress  *string        `json:"ip_address,omitempty"`
+	TraceID    *string        `json:"trace_id,omitempty"`
+	PrevHash   string         `json:"prev_hash"`
+	Hash       string         `json:"hash"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+func AuditEventToResponse(e *AuditEvent) *AuditEventResponse {
+	return &AuditEventResponse{
+		Sequence:   e.Sequence,
+		ID:         e.ID,
+		ActorID:    e.ActorID,
+		ActorType:  e.ActorType,
+		Action:     e.Action,
+		EntityType: e.EntityType,
+		EntityID:   e.EntityID,
+		Before:     e.Before,
+		After:      e.After,
+		IPAddress:  e.IPAddress,
+		TraceID:    e.TraceID,
+		PrevHash:   e.PrevHash,
+		Hash:       e.Hash,
+		CreatedAt:  e.CreatedAt,
+	}
+}
+
+type AuditEventsResponseDTO struct {
+	Events     []*AuditEventResponse `json:"events"`
+	NextCursor *string               `json:"next_cursor,omitempty"`
+}
+
+type AuditChainVerificationResponse struct {
+	FromSequence int64  `json:"from_sequence"`
+	Checked      int    `json:"checked"`
+	Valid        bool   `json:"valid"`
+	Complete     bool   `json:"complete"`
+	BrokenAt     *int64 `json:"broken_at,omitempty"`
+	Problem      string `json:"problem,omitempty"`
+	LastSequence int64  `json:"last_sequence"`
+	LastHash     string `json:"last_hash"`
+}
+
+func AuditChainVerificationToResponse(v *AuditChainVerification) *AuditChainVerificationResponse {
+	return &AuditChainVerificationResponse{
+		FromSequence: v.FromSequence,
+		Checked:      v.Checked,
+		Valid:        v.Valid,
+		Complete:     v.Complete,
+		BrokenAt:     v.BrokenAt,
+		Problem:      v.Problem,
+		LastSequence: v.LastSequence,
+		LastHash:     v.LastHash,
+	}
+}
+
+type WalletResponse struct {
+	ID               string           `json:"id"`
+	UserID           string           `json:"user_id"`
+	Currency         string           `json:"currency"`
+	Balance          float64          `json:"balance"`
+	AvailableBalance float64          `json:"available_balance"`
+	Status           WalletStatus     `json:"status"`
+	FrozenDirection  *FreezeDirection `json:"frozen_direction,omitempty"`
+	StatusReason     *string          `json:"status_reason,omitempty"`
+	ClosedAt         *time.Time       `json:"closed_at,omitempty"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+}
+
+func WalletToResponse(w *Wallet) *WalletResponse {
+	return &WalletResponse{
+		ID:               w.ID,
+		UserID:           w.UserID,
+		Currency:         w.Currency,
+		Balance:          money.ToMajorUnits(w.Balance),
+		AvailableBalance: money.ToMajorUnits(w.AvailableBalance()),
+		Status:           w.Status,
+		FrozenDirection:  w.FrozenDirection,
+		StatusReason:     w.StatusReason,
+		ClosedAt:         w.ClosedAt,
+		CreatedAt:        w.CreatedAt,
+		UpdatedAt:        w.UpdatedAt,
+	}
+}
+
+type WalletWithBankAccountResponse struct {
+	ID               string           `json:"id"`
+	Currency         string           `json:"currency"`
+	Balance          float64          `json:"balance"`
+	AvailableBalance float64          `json:"available_balance"`
+	Status           WalletStatus     `json:"status"`
+	FrozenDirection  *FreezeDirection `json:"frozen_direction,omitempty"`
+	AccountNumber    *string          `json:"account_number,omitempty"`
+	BankName         *string          `json:"bank_name,omitempty"`
+	BankCode         *string          `json:"bank_code,omitempty"`
+	AccountName      *string          `json:"account_name,omitempty"`
+	Provider         *string          `json:"provider,omitempty"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+}
+
 func WalletWithBankAccountToResponse(w *WalletWithBankAccount) *WalletWithBankAccountResponse {
 	balance := money.ToMajorUnits(w.Balance)
 	return &WalletWithBankAccountResponse{
-		ID:            w.ID,
-		Currency:      w.Currency,
-		Balance:       balance,
-		AccountNumber: w.AccountNumber,
-		BankName:      w.BankName,
-		BankCode:      w.BankCode,
-		AccountName:   w.AccountName,
-		Provider:      w.Provider,
-		CreatedAt:     w.CreatedAt,
-		UpdatedAt:     w.UpdatedAt,
+		ID:               w.ID,
+		Currency:         w.Currency,
+		Balance:          balance,
+		AvailableBalance: money.ToMajorUnits(w.AvailableBalance),
+		Status:           w.Status,
+		FrozenDirection:  w.FrozenDirection,
+		AccountNumber:    w.AccountNumber,
+		BankName:         w.BankName,
+		BankCode:         w.BankCode,
+		AccountName:      w.AccountName,
+		Provider:         w.Provider,
+		CreatedAt:        w.CreatedAt,
+		UpdatedAt:        w.UpdatedAt,
 	}
 }
 
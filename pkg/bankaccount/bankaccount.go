@@ -0,0 +1,218 @@
+// Package bankaccount validates the account identifiers used in each payment
+// corridor: IBANs for EUR, UK sort code and account numbers for GBP, and US
+// ABA routing and account numbers for USD.
+package bankaccount
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+)
+
+// Part names the piece of an account identifier that failed validation.
+type Part string
+
+const (
+	PartIBAN          Part = "iban"
+	PartSortCode      Part = "sort_code"
+	PartAccountNumber Part = "account_number"
+	PartRoutingNumber Part = "routing_number"
+)
+
+// Code classifies a validation failure.
+type Code string
+
+const (
+	CodeFormat   Code = "invalid_format"
+	CodeLength   Code = "invalid_length"
+	CodeCountry  Code = "unsupported_country"
+	CodeChecksum Code = "invalid_checksum"
+)
+
+// Error is a validation failure of one part of an account identifier.
+type Error struct {
+	Part    Part
+	Code    Code
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func newError(part Part, code Code, format string, args ...any) *Error {
+	return &Error{Part: part, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// ibanLengths is the IBAN length of each country in the IBAN registry.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22,
+	"BH": 22, "BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22,
+	"DK": 18, "DO": 28, "EE": 20, "EG": 29, "ES": 24, "FI": 18, "FO": 18, "FR": 27,
+	"GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28, "HR": 21, "HU": 28,
+	"IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30, "KW": 30, "KZ": 20,
+	"LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "MC": 27, "MD": 24,
+	"ME": 22, "MK": 19, "MR": 27, "MT": 31, "MU": 30, "NL": 18, "NO": 15, "PK": 24,
+	"PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "SA": 24, "SC": 31,
+	"SE": 24, "SI": 19, "SK": 24, "SM": 27, "ST": 25, "SV": 28, "TL": 23, "TN": 24,
+	"TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20,
+}
+
+// Validate checks account against the identifier format of the corridor that
+// pays out in currency. EUR accounts are IBANs. GBP accounts are a GB IBAN,
+// or a 6-digit sort code followed by an 8-digit account number. USD accounts
+// are a 9-digit ABA routing number followed by a 4 to 17 digit account
+// number. Spaces and hyphens are ignored. Other currencies are not checked.
+func Validate(currency money.Currency, account string) error {
+	switch currency {
+	case money.EUR:
+		return ValidateIBAN(account)
+	case money.GBP:
+		if isIBAN(account) {
+			if err := ValidateIBAN(account); err != nil {
+				return err
+			}
+			if country := strings.ToUpper(compact(account)[:2]); country != "GB" {
+				return newError(PartIBAN, CodeCountry, "GBP accounts must be GB IBANs, not %s", country)
+			}
+			return nil
+		}
+		digits := compact(account)
+		if len(digits) != 14 {
+			return newError(PartAccountNumber, CodeLength, "GBP accounts must be a GB IBAN or a 6-digit sort code and 8-digit account number")
+		}
+		if err := ValidateSortCode(digits[:6]); err != nil {
+			return err
+		}
+		return ValidateUKAccountNumber(digits[6:])
+	case money.USD:
+		digits := compact(account)
+		if len(digits) < 9+4 || len(digits) > 9+17 {
+			return newError(PartAccountNumber, CodeLength, "USD accounts must be a 9-digit routing number and a 4 to 17 digit account number")
+		}
+		if err := ValidateRoutingNumber(digits[:9]); err != nil {
+			return err
+		}
+		return ValidateUSAccountNumber(digits[9:])
+	}
+	return nil
+}
+
+// ValidateIBAN checks an IBAN's characters, its length for the country, and
+// its mod-97 check digits.
+func ValidateIBAN(iban string) error {
+	iban = strings.ToUpper(compact(iban))
+	if len(iban) < 4 || !isLetters(iban[:2]) || !isDigits(iban[2:4]) || !isAlphanumeric(iban[4:]) {
+		return newError(PartIBAN, CodeFormat, "IBAN must be a country code, 2 check digits and the account identifier")
+	}
+
+	country := iban[:2]
+	length, ok := ibanLengths[country]
+	if !ok {
+		return newError(PartIBAN, CodeCountry, "IBANs are not issued in %s", country)
+	}
+	if len(iban) != length {
+		return newError(PartIBAN, CodeLength, "%s IBANs must be %d characters", country, length)
+	}
+
+	if mod97(iban[4:]+iban[:4]) != 1 {
+		return newError(PartIBAN, CodeChecksum, "IBAN check digits are invalid")
+	}
+	return nil
+}
+
+// ValidateSortCode checks a UK sort code, written with or without hyphens.
+func ValidateSortCode(sortCode string) error {
+	if digits := compact(sortCode); len(digits) != 6 || !isDigits(digits) {
+		return newError(PartSortCode, CodeFormat, "sort code must be 6 digits")
+	}
+	return nil
+}
+
+// ValidateUKAccountNumber checks an 8-digit UK account number.
+func ValidateUKAccountNumber(accountNumber string) error {
+	if digits := compact(accountNumber); len(digits) != 8 || !isDigits(digits) {
+		return newError(PartAccountNumber, CodeFormat, "UK account number must be 8 digits")
+	}
+	return nil
+}
+
+// ValidateRoutingNumber checks a 9-digit ABA routing number and its check
+// digit, weighted 3, 7, 1 across the digits.
+func ValidateRoutingNumber(routingNumber string) error {
+	digits := compact(routingNumber)
+	if len(digits) != 9 || !isDigits(digits) {
+		return newError(PartRoutingNumber, CodeFormat, "routing number must be 9 digits")
+	}
+
+	weights := [3]int{3, 7, 1}
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(digits[i]-'0') * weights[i%3]
+	}
+	if sum%10 != 0 {
+		return newError(PartRoutingNumber, CodeChecksum, "routing number check digit is invalid")
+	}
+	return nil
+}
+
+// ValidateUSAccountNumber checks a US account number of 4 to 17 digits.
+func ValidateUSAccountNumber(accountNumber string) error {
+	digits := compact(accountNumber)
+	if len(digits) < 4 || len(digits) > 17 || !isDigits(digits) {
+		return newError(PartAccountNumber, CodeFormat, "US account number must be 4 to 17 digits")
+	}
+	return nil
+}
+
+// mod97 returns the remainder of s divided by 97, reading each letter as two
+// digits (A is 10, Z is 35). s holds only digits and upper-case letters.
+func mod97(s string) int {
+	remainder := 0
+	for _, c := range s {
+		if c >= 'A' && c <= 'Z' {
+			remainder = (remainder*100 + int(c-'A'+10)) % 97
+		} else {
+			remainder = (remainder*10 + int(c-'0')) % 97
+		}
+	}
+	return remainder
+}
+
+func isIBAN(account string) bool {
+	account = compact(account)
+	return len(account) >= 2 && isLetters(strings.ToUpper(account[:2]))
+}
+
+// compact removes the spaces and hyphens used to group digits.
+func compact(s string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(s))
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isLetters(s string) bool {
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	for _, c := range s {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package bankaccount
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateIBAN(t *testing.T) {
+	for _, iban := range []string{
+		"GB82WEST12345698765432",
+		"DE89 3704 0044 0532 0130 00",
+		"fr1420041010050500013m02606",
+		"NL91ABNA0417164300",
+	} {
+		assert.NoError(t, ValidateIBAN(iban), iban)
+	}
+
+	tests := []struct {
+		name string
+		iban string
+		code Code
+	}{
+		{name: "bad check digits", iban: "GB83WEST12345698765432", code: CodeChecksum},
+		{name: "wrong length", iban: "DE8937040044053201300", code: CodeLength},
+		{name: "unknown country", iban: "ZZ8937040044053201300", code: CodeCountry},
+		{name: "no check digits", iban: "DEXX37040044053201300", code: CodeFormat},
+		{name: "punctuation", iban: "DE89.3704.0044.0532.0130.00", code: CodeFormat},
+		{name: "empty", iban: "", code: CodeFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertCode(t, ValidateIBAN(tt.iban), PartIBAN, tt.code)
+		})
+	}
+}
+
+func TestValidateRoutingNumber(t *testing.T) {
+	assert.NoError(t, ValidateRoutingNumber("021000021"))
+	assert.NoError(t, ValidateRoutingNumber("011000015"))
+	assertCode(t, ValidateRoutingNumber("021000022"), PartRoutingNumber, CodeChecksum)
+	assertCode(t, ValidateRoutingNumber("02100002"), PartRoutingNumber, CodeFormat)
+}
+
+func TestValidateSortCodeAndUKAccountNumber(t *testing.T) {
+	assert.NoError(t, ValidateSortCode("40-47-84"))
+	assertCode(t, ValidateSortCode("40-47-8"), PartSortCode, CodeFormat)
+	assert.NoError(t, ValidateUKAccountNumber("70872490"))
+	assertCode(t, ValidateUKAccountNumber("7087249"), PartAccountNumber, CodeFormat)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency money.Currency
+		account  string
+		part     Part
+		code     Code
+	}{
+		{name: "EUR IBAN", currency: money.EUR, account: "DE89370400440532013000"},
+		{name: "EUR plain number", currency: money.EUR, account: "9999999999", part: PartIBAN, code: CodeFormat},
+		{name: "GBP sort code and account", currency: money.GBP, account: "40-47-84 70872490"},
+		{name: "GBP IBAN", currency: money.GBP, account: "GB82 WEST 1234 5698 7654 32"},
+		{name: "GBP non-UK IBAN", currency: money.GBP, account: "DE89370400440532013000", part: PartIBAN, code: CodeCountry},
+		{name: "GBP short account", currency: money.GBP, account: "404784 7087249", part: PartAccountNumber, code: CodeLength},
+		{name: "USD routing and account", currency: money.USD, account: "021000021 9999999999"},
+		{name: "USD bad routing number", currency: money.USD, account: "021000022 9999999999", part: PartRoutingNumber, code: CodeChecksum},
+		{name: "USD account only", currency: money.USD, account: "9999999999", part: PartAccountNumber, code: CodeLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.currency, tt.account)
+			if tt.code == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assertCode(t, err, tt.part, tt.code)
+		})
+	}
+}
+
+func assertCode(t *testing.T, err error, part Part, code Code) {
+	t.Helper()
+	var validationErr *Error
+	if assert.True(t, errors.As(err, &validationErr), "expected *Error, got %v", err) {
+		assert.Equal(t, part, validationErr.Part)
+		assert.Equal(t, code, validationErr.Code)
+	}
+}
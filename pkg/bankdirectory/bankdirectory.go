@@ -0,0 +1,121 @@
+// Package bankdirectory parses the bank directory file: the banks transfers
+// may be sent to, with the currencies they accept and the provider to route
+// them through.
+package bankdirectory
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+)
+
+// Bank is one directory entry. PreferredProvider is empty when any provider
+// may be used.
+type Bank struct {
+	Code              string
+	Name              string
+	Country           string
+	Currencies        []string
+	PreferredProvider string
+}
+
+var countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// LoadFile reads a CSV directory from path.
+func LoadFile(path string) ([]Bank, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read bank directory: %w", err)
+	}
+	return LoadCSV(data)
+}
+
+// LoadCSV parses a CSV directory. The header must contain code, name, country
+// and currencies columns, with currencies separated by ';'. An optional
+// preferred_provider column names the provider to route the bank through.
+// Country codes are ISO 3166-1 alpha-2 and are upper-cased.
+func LoadCSV(data []byte) ([]Bank, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read bank directory header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, required := range []string{"code", "name", "country", "currencies"} {
+		if _, ok := columns[required]; !ok {
+			return nil, errors.New("bank directory header must include code, name, country and currencies")
+		}
+	}
+	providerCol, hasProvider := columns["preferred_provider"]
+
+	var banks []Bank
+	seen := make(map[string]bool)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read bank directory: %w", err)
+		}
+
+		bank := Bank{
+			Code:    strings.TrimSpace(record[columns["code"]]),
+			Name:    strings.TrimSpace(record[columns["name"]]),
+			Country: strings.ToUpper(strings.TrimSpace(record[columns["country"]])),
+		}
+		if hasProvider && providerCol < len(record) {
+			bank.PreferredProvider = strings.TrimSpace(record[providerCol])
+		}
+		for _, currency := range strings.Split(record[columns["currencies"]], ";") {
+			if currency = strings.ToUpper(strings.TrimSpace(currency)); currency != "" {
+				bank.Currencies = append(bank.Currencies, currency)
+			}
+		}
+
+		if err := validate(bank); err != nil {
+			return nil, fmt.Errorf("bank directory line %d: %w", line, err)
+		}
+		if seen[bank.Code] {
+			return nil, fmt.Errorf("bank directory line %d: duplicate bank code %s", line, bank.Code)
+		}
+		seen[bank.Code] = true
+		banks = append(banks, bank)
+	}
+
+	if len(banks) == 0 {
+		return nil, errors.New("bank directory has no banks")
+	}
+	return banks, nil
+}
+
+func validate(bank Bank) error {
+	if bank.Code == "" || bank.Name == "" {
+		return errors.New("missing a code or name")
+	}
+	if !countryPattern.MatchString(bank.Country) {
+		return fmt.Errorf("invalid country %q", bank.Country)
+	}
+	if len(bank.Currencies) == 0 {
+		return errors.New("no currencies")
+	}
+	for _, currency := range bank.Currencies {
+		if _, err := money.ParseCurrency(currency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package bankdirectory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCSV = `code,name,country,currencies,preferred_provider
+044,Test Bank,us,USD;EUR;GBP,currencycloud
+058,Harbor Savings,GB, gbp ,
+`
+
+func TestLoadCSV(t *testing.T) {
+	banks, err := LoadCSV([]byte(testCSV))
+	require.NoError(t, err)
+
+	require.Len(t, banks, 2)
+	assert.Equal(t, Bank{
+		Code:              "044",
+		Name:              "Test Bank",
+		Country:           "US",
+		Currencies:        []string{"USD", "EUR", "GBP"},
+		PreferredProvider: "currencycloud",
+	}, banks[0])
+	assert.Equal(t, []string{"GBP"}, banks[1].Currencies)
+	assert.Empty(t, banks[1].PreferredProvider)
+}
+
+func TestLoadCSV_WithoutProviderColumn(t *testing.T) {
+	banks, err := LoadCSV([]byte("code,name,country,currencies\n044,Test Bank,US,USD\n"))
+	require.NoError(t, err)
+	require.Len(t, banks, 1)
+	assert.Empty(t, banks[0].PreferredProvider)
+}
+
+func TestLoadCSV_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{name: "missing column", csv: "code,name,currencies\n044,Test Bank,USD\n"},
+		{name: "missing name", csv: "code,name,country,currencies\n044,,US,USD\n"},
+		{name: "bad country", csv: "code,name,country,currencies\n044,Test Bank,USA,USD\n"},
+		{name: "no currencies", csv: "code,name,country,currencies\n044,Test Bank,US,\n"},
+		{name: "unsupported currency", csv: "code,name,country,currencies\n044,Test Bank,US,USD;JPY\n"},
+		{name: "duplicate code", csv: "code,name,country,currencies\n044,Test Bank,US,USD\n044,Other Bank,US,USD\n"},
+		{name: "no banks", csv: "code,name,country,currencies\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadCSV([]byte(tt.csv))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "banks.csv")
+	require.NoError(t, os.WriteFile(path, []byte(testCSV), 0o600))
+
+	banks, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Len(t, banks, 2)
+
+	_, err = LoadFile(filepath.Join(t.TempDir(), "missing.csv"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,321 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+var (
+	ErrMalformed            = errors.New("malformed token")
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrUnknownKey           = errors.New("no key matches token")
+	ErrInvalidSignature     = errors.New("invalid signature")
+	ErrExpired              = errors.New("token is expired")
+	ErrNotYetValid          = errors.New("token is not valid yet")
+	ErrInvalidIssuer        = errors.New("invalid issuer")
+	ErrInvalidAudience      = errors.New("invalid audience")
+	ErrMissingSubject       = errors.New("token has no subject")
+)
+
+// Claims holds the decoded token payload.
+type Claims map[string]interface{}
+
+func (c Claims) Subject() string {
+	sub, _ := c["sub"].(string)
+	return sub
+}
+
+// Scopes reads the space-separated "scope" claim, or the "scp"/"scopes"
+// array claims used by some issuers.
+func (c Claims) Scopes() []string {
+	if scope, ok := c["scope"].(string); ok {
+		return strings.Fields(scope)
+	}
+	for _, key := range []string{"scp", "scopes"} {
+		if values, ok := c[key].([]interface{}); ok {
+			scopes := make([]string, 0, len(values))
+			for _, v := range values {
+				if s, ok := v.(string); ok {
+					scopes = append(scopes, s)
+				}
+			}
+			return scopes
+		}
+	}
+	return nil
+}
+
+func (c Claims) hasAudience(audience string) bool {
+	switch aud := c["aud"].(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, v := range aud {
+			if s, ok := v.(string); ok && s == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c Claims) time(key string) (time.Time, bool) {
+	v, ok := c[key].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(v), 0), true
+}
+
+type Options struct {
+	// HMACSecret verifies HS256 tokens that carry no key ID.
+	HMACSecret []byte
+	// RSAPublicKeyPEM verifies RS256 tokens that carry no key ID.
+	RSAPublicKeyPEM []byte
+	// JWKS is a JSON Web Key Set document. RSA keys verify RS256 tokens and
+	// oct keys verify HS256 tokens, matched by "kid".
+	JWKS []byte
+
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+}
+
+// Verifier validates compact JWS tokens signed with HS256 or RS256.
+type Verifier struct {
+	hmacKeys map[string][]byte
+	rsaKeys  map[string]*rsa.PublicKey
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+func NewVerifier(opts Options) (*Verifier, error) {
+	v := &Verifier{
+		hmacKeys: make(map[string][]byte),
+		rsaKeys:  make(map[string]*rsa.PublicKey),
+		issuer:   opts.Issuer,
+		audience: opts.Audience,
+		leeway:   opts.Leeway,
+		now:      time.Now,
+	}
+
+	if len(opts.HMACSecret) > 0 {
+		v.hmacKeys[""] = opts.HMACSecret
+	}
+
+	if len(opts.RSAPublicKeyPEM) > 0 {
+		key, err := ParseRSAPublicKeyPEM(opts.RSAPublicKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+		v.rsaKeys[""] = key
+	}
+
+	if len(opts.JWKS) > 0 {
+		if err := v.addJWKS(opts.JWKS); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(v.hmacKeys) == 0 && len(v.rsaKeys) == 0 {
+		return nil, errors.New("jwt: no verification keys configured")
+	}
+
+	return v, nil
+}
+
+// Verify checks the signature and registered claims of a token and returns its
+// claims. A subject is required.
+func (v *Verifier) Verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrMalformed
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	signingInput := []byte(parts[0] + "." + parts[1])
+	switch header.Alg {
+	case AlgHS256:
+		key, ok := v.hmacKeys[header.Kid]
+		if !ok {
+			return nil, ErrUnknownKey
+		}
+		mac := hmac.New(sha256.New, key)
+		mac.Write(signingInput)
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return nil, ErrInvalidSignature
+		}
+	case AlgRS256:
+		key, ok := v.rsaKeys[header.Kid]
+		if !ok {
+			return nil, ErrUnknownKey
+		}
+		digest := sha256.Sum256(signingInput)
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return nil, ErrInvalidSignature
+		}
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrMalformed
+	}
+
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (v *Verifier) validateClaims(claims Claims) error {
+	now := v.now()
+
+	exp, ok := claims.time("exp")
+	if !ok || now.After(exp.Add(v.leeway)) {
+		return ErrExpired
+	}
+
+	if nbf, ok := claims.time("nbf"); ok && now.Add(v.leeway).Before(nbf) {
+		return ErrNotYetValid
+	}
+
+	if v.issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.issuer {
+			return ErrInvalidIssuer
+		}
+	}
+
+	if v.audience != "" && !claims.hasAudience(v.audience) {
+		return ErrInvalidAudience
+	}
+
+	if claims.Subject() == "" {
+		return ErrMissingSubject
+	}
+
+	return nil
+}
+
+func (v *Verifier) addJWKS(data []byte) error {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			K   string `json:"k"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("jwt: parse jwks: %w", err)
+	}
+
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch k.Kty {
+		case "RSA":
+			key, err := rsaKeyFromJWK(k.N, k.E)
+			if err != nil {
+				return fmt.Errorf("jwt: jwk %q: %w", k.Kid, err)
+			}
+			v.rsaKeys[k.Kid] = key
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(k.K)
+			if err != nil {
+				return fmt.Errorf("jwt: jwk %q: invalid k: %w", k.Kid, err)
+			}
+			v.hmacKeys[k.Kid] = secret
+		}
+	}
+
+	return nil
+}
+
+func rsaKeyFromJWK(n string, e string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+
+	exponent := new(big.Int).SetBytes(eBytes)
+	if !exponent.IsInt64() || exponent.Int64() < 3 {
+		return nil, errors.New("invalid exponent")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(exponent.Int64()),
+	}, nil
+}
+
+// ParseRSAPublicKeyPEM accepts a PKIX "PUBLIC KEY" or PKCS#1 "RSA PUBLIC KEY"
+// block.
+func ParseRSAPublicKeyPEM(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("jwt: no PEM block found")
+	}
+
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: parse public key: %w", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("jwt: public key is not RSA")
+	}
+	return rsaKey, nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
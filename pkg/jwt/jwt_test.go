@@ -0,0 +1,198 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signHS256(t *testing.T, secret []byte, kid string, claims map[string]interface{}) string {
+	header := map[string]string{"alg": AlgHS256, "typ": "JWT"}
+	if kid != "" {
+		header["kid"] = kid
+	}
+	input := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	header := map[string]string{"alg": AlgRS256, "typ": "JWT"}
+	if kid != "" {
+		header["kid"] = kid
+	}
+	input := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(input))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   "user_1",
+		"iss":   "https://auth.example.com",
+		"aud":   "payments",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "payments:read payments:write",
+	}
+}
+
+func TestVerifier_HS256(t *testing.T) {
+	secret := []byte("test-secret")
+	v, err := NewVerifier(Options{HMACSecret: secret, Issuer: "https://auth.example.com", Audience: "payments"})
+	require.NoError(t, err)
+
+	t.Run("valid token", func(t *testing.T) {
+		claims, err := v.Verify(signHS256(t, secret, "", validClaims()))
+		require.NoError(t, err)
+		assert.Equal(t, "user_1", claims.Subject())
+		assert.Equal(t, []string{"payments:read", "payments:write"}, claims.Scopes())
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		_, err := v.Verify(signHS256(t, []byte("other"), "", validClaims()))
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		token := signHS256(t, secret, "", validClaims())
+		claims := validClaims()
+		claims["sub"] = "user_2"
+		parts := strings.Split(token, ".")
+		forged := parts[0] + "." + encodeSegment(t, claims) + "." + parts[2]
+		_, err := v.Verify(forged)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		claims := validClaims()
+		claims["exp"] = time.Now().Add(-time.Minute).Unix()
+		_, err := v.Verify(signHS256(t, secret, "", claims))
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("missing exp", func(t *testing.T) {
+		claims := validClaims()
+		delete(claims, "exp")
+		_, err := v.Verify(signHS256(t, secret, "", claims))
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("not yet valid", func(t *testing.T) {
+		claims := validClaims()
+		claims["nbf"] = time.Now().Add(time.Hour).Unix()
+		_, err := v.Verify(signHS256(t, secret, "", claims))
+		assert.ErrorIs(t, err, ErrNotYetValid)
+	})
+
+	t.Run("wrong issuer", func(t *testing.T) {
+		claims := validClaims()
+		claims["iss"] = "https://evil.example.com"
+		_, err := v.Verify(signHS256(t, secret, "", claims))
+		assert.ErrorIs(t, err, ErrInvalidIssuer)
+	})
+
+	t.Run("audience list", func(t *testing.T) {
+		claims := validClaims()
+		claims["aud"] = []string{"other", "payments"}
+		_, err := v.Verify(signHS256(t, secret, "", claims))
+		assert.NoError(t, err)
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		claims := validClaims()
+		claims["aud"] = "other"
+		_, err := v.Verify(signHS256(t, secret, "", claims))
+		assert.ErrorIs(t, err, ErrInvalidAudience)
+	})
+
+	t.Run("missing subject", func(t *testing.T) {
+		claims := validClaims()
+		delete(claims, "sub")
+		_, err := v.Verify(signHS256(t, secret, "", claims))
+		assert.ErrorIs(t, err, ErrMissingSubject)
+	})
+
+	t.Run("alg none rejected", func(t *testing.T) {
+		token := encodeSegment(t, map[string]string{"alg": "none"}) + "." + encodeSegment(t, validClaims()) + "."
+		_, err := v.Verify(token)
+		assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := v.Verify("not-a-token")
+		assert.ErrorIs(t, err, ErrMalformed)
+	})
+}
+
+func TestVerifier_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	t.Run("static public key", func(t *testing.T) {
+		v, err := NewVerifier(Options{RSAPublicKeyPEM: pemBytes})
+		require.NoError(t, err)
+
+		claims, err := v.Verify(signRS256(t, key, "", validClaims()))
+		require.NoError(t, err)
+		assert.Equal(t, "user_1", claims.Subject())
+	})
+
+	t.Run("jwks by kid", func(t *testing.T) {
+		jwks := fmt.Sprintf(`{"keys":[{"kty":"RSA","kid":"key-1","use":"sig","n":%q,"e":%q},{"kty":"oct","kid":"hmac-1","k":%q}]}`,
+			base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			base64.RawURLEncoding.EncodeToString([]byte("jwks-secret")),
+		)
+		v, err := NewVerifier(Options{JWKS: []byte(jwks)})
+		require.NoError(t, err)
+
+		_, err = v.Verify(signRS256(t, key, "key-1", validClaims()))
+		assert.NoError(t, err)
+
+		_, err = v.Verify(signHS256(t, []byte("jwks-secret"), "hmac-1", validClaims()))
+		assert.NoError(t, err)
+
+		_, err = v.Verify(signRS256(t, key, "key-2", validClaims()))
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+
+	t.Run("rs256 token against hmac-only verifier", func(t *testing.T) {
+		v, err := NewVerifier(Options{HMACSecret: []byte("secret")})
+		require.NoError(t, err)
+
+		_, err = v.Verify(signRS256(t, key, "", validClaims()))
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+}
+
+func TestNewVerifier_NoKeys(t *testing.T) {
+	_, err := NewVerifier(Options{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,289 @@
+// Package keyring loads key-encryption keys from a local file and uses them
+// for envelope encryption of individual column values.
+//
+// Every value gets its own random data key. The value is sealed with the data
+// key, and the data key is sealed with the keyring's active key. Both are
+// stored together with the key ID in a single string:
+//
+//	enc:v1:<key id>:<wrapped data key>:<ciphertext>
+//
+// so a value can be decrypted with whichever key wrapped it, and rotating the
+// active key only affects newly written values until they are re-encrypted.
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// KeySize is the length in bytes of every key in the keyring (AES-256).
+const KeySize = 32
+
+const (
+	envelopePrefix  = "enc:"
+	envelopeVersion = "v1"
+)
+
+var (
+	ErrUnknownKey      = errors.New("unknown encryption key")
+	ErrInvalidEnvelope = errors.New("invalid encrypted value")
+	ErrDecrypt         = errors.New("decryption failed")
+)
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+var encoding = base64.RawURLEncoding
+
+// Keyring holds the key-encryption keys by ID and the key used for blind
+// indexes. The index key is separate from the encryption keys and is not
+// rotated with them, because every stored index would have to be recomputed.
+type Keyring struct {
+	activeKeyID string
+	keys        map[string][]byte
+	indexKey    []byte
+}
+
+type fileFormat struct {
+	ActiveKey string `json:"active_key"`
+	Keys      []struct {
+		ID  string `json:"id"`
+		Key string `json:"key"`
+	} `json:"keys"`
+	IndexKey string `json:"index_key"`
+}
+
+// LoadFile reads a keyring from a JSON file.
+func LoadFile(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read keyring: %w", err)
+	}
+	return Load(data)
+}
+
+// Load parses a JSON keyring:
+//
+//	{
+//	  "active_key": "2025-01",
+//	  "keys": [{"id": "2025-01", "key": "<base64, 32 bytes>"}],
+//	  "index_key": "<base64, 32 bytes>"
+//	}
+//
+// Retired keys stay in the list so values they wrapped can still be read
+// until they have been re-encrypted.
+func Load(data []byte) (*Keyring, error) {
+	var file fileFormat
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse keyring: %w", err)
+	}
+
+	kr := &Keyring{
+		activeKeyID: file.ActiveKey,
+		keys:        make(map[string][]byte, len(file.Keys)),
+	}
+
+	for _, k := range file.Keys {
+		if !keyIDPattern.MatchString(k.ID) {
+			return nil, fmt.Errorf("invalid key id %q", k.ID)
+		}
+		if _, ok := kr.keys[k.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", k.ID)
+		}
+		key, err := decodeKey(k.Key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.ID, err)
+		}
+		kr.keys[k.ID] = key
+	}
+
+	if _, ok := kr.keys[kr.activeKeyID]; !ok {
+		return nil, fmt.Errorf("active key %q is not in the keyring", kr.activeKeyID)
+	}
+
+	indexKey, err := decodeKey(file.IndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("index key: %w", err)
+	}
+	kr.indexKey = indexKey
+
+	return kr, nil
+}
+
+func decodeKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("decode key: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(key))
+	}
+	return key, nil
+}
+
+// GenerateKey returns a random base64 key suitable for a keyring file.
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// ActiveKeyID is the ID of the key new values are encrypted with.
+func (kr *Keyring) ActiveKeyID() string {
+	return kr.activeKeyID
+}
+
+// ActivePrefix is the prefix shared by every value encrypted with the active
+// key. Stored values without it still need to be encrypted or re-encrypted.
+func (kr *Keyring) ActivePrefix() string {
+	return Prefix(kr.activeKeyID)
+}
+
+// Prefix is the prefix of values encrypted with keyID.
+func Prefix(keyID string) string {
+	return envelopePrefix + envelopeVersion + ":" + keyID + ":"
+}
+
+// IsEncrypted reports whether value looks like an envelope produced by
+// Encrypt, as opposed to a plaintext value written before encryption was
+// enabled.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, envelopePrefix)
+}
+
+// KeyID returns the ID of the key that wrapped an encrypted value.
+func KeyID(value string) (string, error) {
+	env, err := parseEnvelope(value)
+	if err != nil {
+		return "", err
+	}
+	return env.keyID, nil
+}
+
+// Encrypt seals plaintext under a new data key wrapped with the active key.
+// aad is authenticated but not stored; the same aad must be passed to
+// Decrypt, which stops a value being copied into a different column.
+func (kr *Keyring) Encrypt(plaintext, aad []byte) (string, error) {
+	dataKey := make([]byte, KeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("generate data key: %w", err)
+	}
+
+	ciphertext, err := seal(dataKey, plaintext, aad)
+	if err != nil {
+		return "", err
+	}
+
+	wrappedKey, err := seal(kr.keys[kr.activeKeyID], dataKey, []byte(kr.activeKeyID))
+	if err != nil {
+		return "", err
+	}
+
+	return kr.ActivePrefix() + encoding.EncodeToString(wrappedKey) + ":" + encoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt opens a value produced by Encrypt with any key in the keyring.
+func (kr *Keyring) Decrypt(value string, aad []byte) ([]byte, error) {
+	env, err := parseEnvelope(value)
+	if err != nil {
+		return nil, err
+	}
+
+	kek, ok := kr.keys[env.keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, env.keyID)
+	}
+
+	dataKey, err := open(kek, env.wrappedKey, []byte(env.keyID))
+	if err != nil {
+		return nil, err
+	}
+	return open(dataKey, env.ciphertext, aad)
+}
+
+// BlindIndex returns a keyed hash of value for equality lookups on an
+// encrypted column. domain separates indexes for different columns, so the
+// same value does not produce the same hash in two places.
+func (kr *Keyring) BlindIndex(domain, value string) string {
+	mac := hmac.New(sha256.New, kr.indexKey)
+	mac.Write([]byte(domain))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type envelope struct {
+	keyID      string
+	wrappedKey []byte
+	ciphertext []byte
+}
+
+func parseEnvelope(value string) (envelope, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 5 || parts[0]+":" != envelopePrefix || parts[1] != envelopeVersion {
+		return envelope{}, ErrInvalidEnvelope
+	}
+
+	wrappedKey, err := encoding.DecodeString(parts[3])
+	if err != nil {
+		return envelope{}, ErrInvalidEnvelope
+	}
+	ciphertext, err := encoding.DecodeString(parts[4])
+	if err != nil {
+		return envelope{}, ErrInvalidEnvelope
+	}
+
+	return envelope{keyID: parts[2], wrappedKey: wrappedKey, ciphertext: ciphertext}, nil
+}
+
+// seal encrypts with AES-GCM and prepends the random nonce.
+func seal(key, plaintext, aad []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(key, sealed, aad []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrInvalidEnvelope
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
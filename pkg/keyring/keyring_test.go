@@ -0,0 +1,162 @@
+package keyring
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testKeyA     = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("a", KeySize)))
+	testKeyB     = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("b", KeySize)))
+	testIndexKey = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("i", KeySize)))
+)
+
+func testKeyring(t *testing.T, active string) *Keyring {
+	t.Helper()
+	kr, err := Load([]byte(fmt.Sprintf(`{
+		"active_key": %q,
+		"keys": [{"id": "k1", "key": %q}, {"id": "k2", "key": %q}],
+		"index_key": %q
+	}`, active, testKeyA, testKeyB, testIndexKey)))
+	require.NoError(t, err)
+	return kr
+}
+
+func TestLoad(t *testing.T) {
+	kr := testKeyring(t, "k2")
+	assert.Equal(t, "k2", kr.ActiveKeyID())
+	assert.Equal(t, "enc:v1:k2:", kr.ActivePrefix())
+
+	_, err := Load([]byte(fmt.Sprintf(`{"active_key": "k3", "keys": [{"id": "k1", "key": %q}], "index_key": %q}`, testKeyA, testIndexKey)))
+	assert.ErrorContains(t, err, "active key")
+
+	_, err = Load([]byte(fmt.Sprintf(`{"active_key": "k1", "keys": [{"id": "k1", "key": "c2hvcnQ="}], "index_key": %q}`, testIndexKey)))
+	assert.ErrorContains(t, err, "32 bytes")
+
+	_, err = Load([]byte(fmt.Sprintf(`{"active_key": "k:1", "keys": [{"id": "k:1", "key": %q}], "index_key": %q}`, testKeyA, testIndexKey)))
+	assert.ErrorContains(t, err, "invalid key id")
+
+	_, err = Load([]byte(fmt.Sprintf(`{"active_key": "k1", "keys": [{"id": "k1", "key": %q}, {"id": "k1", "key": %q}], "index_key": %q}`, testKeyA, testKeyB, testIndexKey)))
+	assert.ErrorContains(t, err, "duplicate key id")
+
+	_, err = Load([]byte(fmt.Sprintf(`{"active_key": "k1", "keys": [{"id": "k1", "key": %q}]}`, testKeyA)))
+	assert.ErrorContains(t, err, "index key")
+}
+
+func TestLoadFile(t *testing.T) {
+	key, err := GenerateKey()
+	require.NoError(t, err)
+	indexKey, err := GenerateKey()
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf(`{"active_key": "k1", "keys": [{"id": "k1", "key": %q}], "index_key": %q}`, key, indexKey)), 0o600))
+
+	kr, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "k1", kr.ActiveKeyID())
+
+	_, err = LoadFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	kr := testKeyring(t, "k1")
+	aad := []byte("bank_accounts.account_number")
+
+	value, err := kr.Encrypt([]byte("0123456789"), aad)
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(value))
+	assert.True(t, strings.HasPrefix(value, kr.ActivePrefix()))
+	assert.NotContains(t, value, "0123456789")
+
+	keyID, err := KeyID(value)
+	require.NoError(t, err)
+	assert.Equal(t, "k1", keyID)
+
+	plaintext, err := kr.Decrypt(value, aad)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(plaintext))
+
+	again, err := kr.Encrypt([]byte("0123456789"), aad)
+	require.NoError(t, err)
+	assert.NotEqual(t, value, again, "every value gets a fresh data key and nonce")
+}
+
+func TestDecrypt_Failures(t *testing.T) {
+	kr := testKeyring(t, "k1")
+	aad := []byte("bank_accounts.account_number")
+
+	value, err := kr.Encrypt([]byte("0123456789"), aad)
+	require.NoError(t, err)
+
+	t.Run("wrong aad", func(t *testing.T) {
+		_, err := kr.Decrypt(value, []byte("webhook_events.payload"))
+		assert.ErrorIs(t, err, ErrDecrypt)
+	})
+
+	t.Run("tampered ciphertext", func(t *testing.T) {
+		tampered := value[:len(value)-2] + "AA"
+		if tampered == value {
+			tampered = value[:len(value)-2] + "BB"
+		}
+		_, err := kr.Decrypt(tampered, aad)
+		assert.Error(t, err)
+	})
+
+	t.Run("key id swapped", func(t *testing.T) {
+		swapped := strings.Replace(value, "enc:v1:k1:", "enc:v1:k2:", 1)
+		_, err := kr.Decrypt(swapped, aad)
+		assert.ErrorIs(t, err, ErrDecrypt)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		unknown := strings.Replace(value, "enc:v1:k1:", "enc:v1:k9:", 1)
+		_, err := kr.Decrypt(unknown, aad)
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+
+	t.Run("not an envelope", func(t *testing.T) {
+		_, err := kr.Decrypt("0123456789", aad)
+		assert.ErrorIs(t, err, ErrInvalidEnvelope)
+		assert.False(t, IsEncrypted("0123456789"))
+	})
+}
+
+func TestRotation(t *testing.T) {
+	old := testKeyring(t, "k1")
+	aad := []byte("transactions.provider_reference")
+
+	value, err := old.Encrypt([]byte(`{"account_number":"0123456789"}`), aad)
+	require.NoError(t, err)
+
+	rotated := testKeyring(t, "k2")
+	assert.False(t, strings.HasPrefix(value, rotated.ActivePrefix()))
+
+	plaintext, err := rotated.Decrypt(value, aad)
+	require.NoError(t, err, "retired keys still decrypt")
+
+	reencrypted, err := rotated.Encrypt(plaintext, aad)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(reencrypted, "enc:v1:k2:"))
+}
+
+func TestBlindIndex(t *testing.T) {
+	kr := testKeyring(t, "k1")
+
+	index := kr.BlindIndex("bank_accounts.account_number", "0123456789")
+	assert.Len(t, index, 64)
+	assert.Equal(t, index, kr.BlindIndex("bank_accounts.account_number", "0123456789"))
+	assert.NotEqual(t, index, kr.BlindIndex("bank_accounts.account_number", "0123456788"))
+	assert.NotEqual(t, index, kr.BlindIndex("beneficiaries.account_number", "0123456789"))
+
+	rotated := testKeyring(t, "k2")
+	assert.Equal(t, index, rotated.BlindIndex("bank_accounts.account_number", "0123456789"), "index does not change when the active key rotates")
+}
@@ -0,0 +1,136 @@
+// Package namematch scores how well an account name supplied by a user
+// matches the name a bank returned for the account.
+package namematch
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/IfedayoAwe/payment-processing-service/pkg/namenorm"
+)
+
+// Verdict classifies a score against a set of thresholds.
+type Verdict string
+
+const (
+	VerdictMatch    Verdict = "match"
+	VerdictPartial  Verdict = "partial"
+	VerdictMismatch Verdict = "mismatch"
+)
+
+// initialScore is the similarity of an initial to a word starting with the
+// same letter, so "J. Doe" is close to, but not the same as, "John Doe".
+const initialScore = 0.85
+
+// honorifics are dropped before comparison.
+var honorifics = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "miss": true, "dr": true, "prof": true,
+}
+
+// Thresholds are the lowest scores for a match and a partial match.
+type Thresholds struct {
+	Match   float64
+	Partial float64
+}
+
+// Result is the score of one comparison and its verdict.
+type Result struct {
+	Score   float64
+	Verdict Verdict
+}
+
+// Compare scores supplied against resolved and classifies the score.
+func Compare(supplied, resolved string, thresholds Thresholds) Result {
+	score := Score(supplied, resolved)
+
+	verdict := VerdictMismatch
+	switch {
+	case score >= thresholds.Match:
+		verdict = VerdictMatch
+	case score >= thresholds.Partial:
+		verdict = VerdictPartial
+	}
+	return Result{Score: score, Verdict: verdict}
+}
+
+// Score returns how well two names match, from 0 to 1. Names are normalized
+// and split into words. Each word of the shorter name is paired with the most
+// similar unused word of the longer one, and the pair similarities are
+// averaged weighted by word length. Words of the longer name left unpaired
+// count as similarity 0, so extra words on either side lower the score and a
+// name is never a match for itself with more words added. Word order does not
+// matter.
+func Score(a, b string) float64 {
+	short, long := tokens(a), tokens(b)
+	if len(short) == 0 || len(long) == 0 {
+		return 0
+	}
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+
+	// Longer words pick first, so an initial cannot take the word a full
+	// name would have matched.
+	sort.SliceStable(short, func(i, j int) bool {
+		return utf8.RuneCountInString(short[i]) > utf8.RuneCountInString(short[j])
+	})
+
+	// Every word of the shorter name takes a word of the longer one, even
+	// one it shares nothing with, so a wrong word is not counted twice.
+	used := make([]bool, len(long))
+	var total, weight float64
+	for _, word := range short {
+		best, bestIndex := 0.0, -1
+		for j, other := range long {
+			if used[j] {
+				continue
+			}
+			if similarity := wordSimilarity(word, other); bestIndex < 0 || similarity > best {
+				best, bestIndex = similarity, j
+			}
+		}
+		used[bestIndex] = true
+
+		length := float64(utf8.RuneCountInString(word))
+		total += best * length
+		weight += length
+	}
+
+	for j, other := range long {
+		if !used[j] {
+			weight += float64(utf8.RuneCountInString(other))
+		}
+	}
+
+	return total / weight
+}
+
+func tokens(name string) []string {
+	var words []string
+	for _, word := range strings.Fields(namenorm.Normalize(name)) {
+		if !honorifics[word] {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+func wordSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	if isInitial(a) || isInitial(b) {
+		first, _ := utf8.DecodeRuneInString(a)
+		other, _ := utf8.DecodeRuneInString(b)
+		if first == other {
+			return initialScore
+		}
+		return 0
+	}
+	return namenorm.Similarity(a, b)
+}
+
+func isInitial(word string) bool {
+	return utf8.RuneCountInString(word) == 1
+}
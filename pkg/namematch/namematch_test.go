@@ -0,0 +1,47 @@
+package namematch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testThresholds = Thresholds{Match: 0.9, Partial: 0.75}
+
+func TestScore(t *testing.T) {
+	assert.Equal(t, 1.0, Score("John Doe", "DOE, John"))
+	assert.Equal(t, 1.0, Score("Mr John Doe", "John Doe"))
+	assert.Equal(t, 1.0, Score("José Álvarez", "Jose Alvarez"))
+	assert.Equal(t, 0.5, Score("John Doe", "John Michael Doe"), "a word on one side only lowers the score")
+	assert.Equal(t, Score("John Doe Fraudster", "John Doe"), Score("John Doe", "John Doe Fraudster"), "extra words count the same on either side")
+	assert.InDelta(t, 0.9625, Score("J. Doe", "John Doe"), 0.0001, "an initial scores below the full word")
+	assert.Equal(t, 0.0, Score("", "John Doe"))
+	assert.Equal(t, 0.0, Score("John Doe", " .,- "))
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		supplied string
+		resolved string
+		verdict  Verdict
+	}{
+		{name: "same name", supplied: "John Doe", resolved: "John Doe", verdict: VerdictMatch},
+		{name: "typo", supplied: "Jon Doe", resolved: "John Doe", verdict: VerdictMatch},
+		{name: "initial", supplied: "J. Doe", resolved: "John Doe", verdict: VerdictMatch},
+		{name: "first name only", supplied: "John", resolved: "John Doe", verdict: VerdictMismatch},
+		{name: "extra supplied word", supplied: "John Doe Fraudster", resolved: "John Doe", verdict: VerdictMismatch},
+		{name: "extra resolved word", supplied: "John Doe", resolved: "John Doe Fraudster", verdict: VerdictMismatch},
+		{name: "one letter off in a longer name", supplied: "Jon Michael Doe", resolved: "John Michael Doe", verdict: VerdictMatch},
+		{name: "different surname", supplied: "John Smith", resolved: "John Doe", verdict: VerdictMismatch},
+		{name: "different person", supplied: "Jane Smith", resolved: "Viktor Petrovsky", verdict: VerdictMismatch},
+		{name: "wrong initial", supplied: "K. Doe", resolved: "John Doe", verdict: VerdictPartial},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Compare(tt.supplied, tt.resolved, testThresholds)
+			assert.Equal(t, tt.verdict, result.Verdict, "score %.3f", result.Score)
+		})
+	}
+}
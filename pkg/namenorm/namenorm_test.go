@@ -0,0 +1,22 @@
+package namenorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, Normalize("John Doe"), Normalize("DOE, John"))
+	assert.Equal(t, "doe john", Normalize("John  Doe"))
+	assert.Equal(t, "alvarez jose nunez", Normalize("José Álvarez-Núñez"))
+	assert.Equal(t, "", Normalize(" .,- "))
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, Similarity("martha", "martha"))
+	assert.InDelta(t, 0.961, Similarity("martha", "marhta"), 0.001)
+	assert.InDelta(t, 0.840, Similarity("dwayne", "duane"), 0.001)
+	assert.Equal(t, 0.0, Similarity("abc", ""))
+	assert.Less(t, Similarity("john smith", "viktor petrovsky"), 0.7)
+}
@@ -0,0 +1,222 @@
+// Package sanctions loads a sanctions or watchlist file and screens names
+// against it with fuzzy matching.
+package sanctions
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/IfedayoAwe/payment-processing-service/pkg/namenorm"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported sanctions list format")
+
+// Entry is one listed party. Aliases are alternative spellings or names the
+// party is known by, and are matched the same way as Name.
+type Entry struct {
+	ID      string
+	Name    string
+	Aliases []string
+}
+
+// List is a loaded sanctions list. Version identifies the list contents so a
+// screening result can be traced back to the exact list it was made against.
+type List struct {
+	Version string
+	Entries []Entry
+
+	names []indexedName
+}
+
+type indexedName struct {
+	entry      int
+	name       string
+	normalized string
+}
+
+// Match is a listed name that scored at or above the threshold.
+type Match struct {
+	Entry       Entry
+	MatchedName string
+	Score       float64
+}
+
+// LoadFile reads a list from path, choosing the format from the extension
+// (.csv or .xml).
+func LoadFile(path string) (*List, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read sanctions list: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return LoadCSV(data)
+	case ".xml":
+		return LoadXML(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// LoadCSV parses a CSV list. The header must contain id and name columns; an
+// optional aliases column holds alternative names separated by ';'. The
+// version is a hash of the file contents.
+func LoadCSV(data []byte) (*List, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read sanctions list header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	idCol, hasID := columns["id"]
+	nameCol, hasName := columns["name"]
+	aliasCol, hasAliases := columns["aliases"]
+	if !hasID || !hasName {
+		return nil, errors.New("sanctions list header must include id and name")
+	}
+
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read sanctions list: %w", err)
+		}
+
+		entry := Entry{
+			ID:   strings.TrimSpace(record[idCol]),
+			Name: strings.TrimSpace(record[nameCol]),
+		}
+		if hasAliases && aliasCol < len(record) {
+			for _, alias := range strings.Split(record[aliasCol], ";") {
+				if alias = strings.TrimSpace(alias); alias != "" {
+					entry.Aliases = append(entry.Aliases, alias)
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return newList(contentVersion(data), entries)
+}
+
+type xmlList struct {
+	XMLName xml.Name   `xml:"sanctionsList"`
+	Version string     `xml:"version,attr"`
+	Entries []xmlEntry `xml:"entry"`
+}
+
+type xmlEntry struct {
+	ID      string   `xml:"id,attr"`
+	Name    string   `xml:"name"`
+	Aliases []string `xml:"alias"`
+}
+
+// LoadXML parses an XML list of the form
+//
+//	<sanctionsList version="2025-01-15">
+//	  <entry id="SL-1"><name>...</name><alias>...</alias></entry>
+//	</sanctionsList>
+//
+// The version attribute is used when present, otherwise a hash of the file
+// contents.
+func LoadXML(data []byte) (*List, error) {
+	var doc xmlList
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse sanctions list: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(doc.Entries))
+	for _, e := range doc.Entries {
+		entry := Entry{ID: strings.TrimSpace(e.ID), Name: strings.TrimSpace(e.Name)}
+		for _, alias := range e.Aliases {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				entry.Aliases = append(entry.Aliases, alias)
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	version := strings.TrimSpace(doc.Version)
+	if version == "" {
+		version = contentVersion(data)
+	}
+
+	return newList(version, entries)
+}
+
+func newList(version string, entries []Entry) (*List, error) {
+	list := &List{Version: version, Entries: entries}
+	for i, entry := range entries {
+		if entry.ID == "" || entry.Name == "" {
+			return nil, fmt.Errorf("sanctions list entry %d is missing an id or name", i+1)
+		}
+		for _, name := range append([]string{entry.Name}, entry.Aliases...) {
+			if normalized := namenorm.Normalize(name); normalized != "" {
+				list.names = append(list.names, indexedName{entry: i, name: name, normalized: normalized})
+			}
+		}
+	}
+	return list, nil
+}
+
+func contentVersion(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])[:12]
+}
+
+// Match returns every entry with a name or alias scoring at least threshold
+// against name, best first. Each entry appears at most once, under its best
+// scoring name.
+func (l *List) Match(name string, threshold float64) []Match {
+	normalized := namenorm.Normalize(name)
+	if normalized == "" {
+		return nil
+	}
+
+	best := make(map[int]Match)
+	for _, candidate := range l.names {
+		score := namenorm.Similarity(normalized, candidate.normalized)
+		if score < threshold {
+			continue
+		}
+		if current, ok := best[candidate.entry]; !ok || score > current.Score {
+			best[candidate.entry] = Match{
+				Entry:       l.Entries[candidate.entry],
+				MatchedName: candidate.name,
+				Score:       score,
+			}
+		}
+	}
+
+	matches := make([]Match, 0, len(best))
+	for _, match := range best {
+		matches = append(matches, match)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Entry.ID < matches[j].Entry.ID
+	})
+	return matches
+}
@@ -0,0 +1,124 @@
+package sanctions
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCSV = `id,name,aliases
+SL-1,Viktor Petrovsky,Viktor Petrovski;V. Petrovsky
+SL-2,Aurora Trading LLC,
+SL-3,José Álvarez Núñez,
+`
+
+const testXML = `<?xml version="1.0" encoding="UTF-8"?>
+<sanctionsList version="2025-01-15">
+  <entry id="SL-1">
+    <name>Viktor Petrovsky</name>
+    <alias>Viktor Petrovski</alias>
+  </entry>
+  <entry id="SL-2">
+    <name>Aurora Trading LLC</name>
+  </entry>
+</sanctionsList>
+`
+
+func TestLoadCSV(t *testing.T) {
+	list, err := LoadCSV([]byte(testCSV))
+	require.NoError(t, err)
+
+	require.Len(t, list.Entries, 3)
+	assert.Equal(t, "SL-1", list.Entries[0].ID)
+	assert.Equal(t, []string{"Viktor Petrovski", "V. Petrovsky"}, list.Entries[0].Aliases)
+	assert.Empty(t, list.Entries[1].Aliases)
+	assert.True(t, strings.HasPrefix(list.Version, "sha256:"))
+
+	other, err := LoadCSV([]byte(testCSV + "SL-4,Someone Else,\n"))
+	require.NoError(t, err)
+	assert.NotEqual(t, list.Version, other.Version)
+
+	_, err = LoadCSV([]byte("name\nViktor Petrovsky\n"))
+	assert.Error(t, err)
+
+	_, err = LoadCSV([]byte("id,name\nSL-1,\n"))
+	assert.Error(t, err)
+}
+
+func TestLoadXML(t *testing.T) {
+	list, err := LoadXML([]byte(testXML))
+	require.NoError(t, err)
+
+	assert.Equal(t, "2025-01-15", list.Version)
+	require.Len(t, list.Entries, 2)
+	assert.Equal(t, []string{"Viktor Petrovski"}, list.Entries[0].Aliases)
+
+	_, err = LoadXML([]byte("<sanctionsList><entry"))
+	assert.Error(t, err)
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	csvPath := filepath.Join(dir, "list.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte(testCSV), 0o600))
+	list, err := LoadFile(csvPath)
+	require.NoError(t, err)
+	assert.Len(t, list.Entries, 3)
+
+	xmlPath := filepath.Join(dir, "list.XML")
+	require.NoError(t, os.WriteFile(xmlPath, []byte(testXML), 0o600))
+	list, err = LoadFile(xmlPath)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01-15", list.Version)
+
+	jsonPath := filepath.Join(dir, "list.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte("{}"), 0o600))
+	_, err = LoadFile(jsonPath)
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	_, err = LoadFile(filepath.Join(dir, "missing.csv"))
+	assert.Error(t, err)
+}
+
+func TestList_Match(t *testing.T) {
+	list, err := LoadCSV([]byte(testCSV))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		input   string
+		matchID string
+	}{
+		{name: "exact", input: "Viktor Petrovsky", matchID: "SL-1"},
+		{name: "reordered and punctuated", input: "PETROVSKY, Viktor", matchID: "SL-1"},
+		{name: "misspelled", input: "Victor Petrovsky", matchID: "SL-1"},
+		{name: "accents dropped", input: "Jose Alvarez Nunez", matchID: "SL-3"},
+		{name: "unrelated", input: "Mock Account Holder 7890"},
+		{name: "empty", input: "  "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := list.Match(tt.input, 0.9)
+			if tt.matchID == "" {
+				assert.Empty(t, matches)
+				return
+			}
+			require.NotEmpty(t, matches)
+			assert.Equal(t, tt.matchID, matches[0].Entry.ID)
+			assert.GreaterOrEqual(t, matches[0].Score, 0.9)
+		})
+	}
+
+	t.Run("entry reported once under its best name", func(t *testing.T) {
+		matches := list.Match("Viktor Petrovski", 0.8)
+		require.Len(t, matches, 1)
+		assert.Equal(t, "Viktor Petrovski", matches[0].MatchedName)
+		assert.Equal(t, 1.0, matches[0].Score)
+	})
+}
@@ -0,0 +1,108 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 seed from RFC 6238 appendix B.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	// RFC 6238 lists 8-digit codes; these are their last 6 digits.
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := Code(rfcSecret, time.Unix(tt.unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, tt.code, code, "time %d", tt.unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	t.Run("current step", func(t *testing.T) {
+		step, ok, err := Validate(rfcSecret, "050471", now, 1)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, Step(now), step)
+	})
+
+	t.Run("previous step within skew", func(t *testing.T) {
+		previous, err := Code(rfcSecret, now.Add(-Period))
+		require.NoError(t, err)
+
+		step, ok, err := Validate(rfcSecret, previous, now, 1)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, Step(now)-1, step)
+	})
+
+	t.Run("outside skew", func(t *testing.T) {
+		old, err := Code(rfcSecret, now.Add(-3*Period))
+		require.NoError(t, err)
+
+		_, ok, err := Validate(rfcSecret, old, now, 1)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("wrong code", func(t *testing.T) {
+		_, ok, err := Validate(rfcSecret, "000000", now, 1)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("wrong length", func(t *testing.T) {
+		_, ok, err := Validate(rfcSecret, "50471", now, 1)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("invalid secret", func(t *testing.T) {
+		_, _, err := Validate("not base32!", "050471", now, 1)
+		assert.ErrorIs(t, err, ErrInvalidSecret)
+	})
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+
+	key, err := decodeSecret(secret)
+	require.NoError(t, err)
+	assert.Len(t, key, SecretSize)
+
+	other, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Payment Service", "user_1", "JBSWY3DPEHPK3PXP")
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/Payment Service:user_1", parsed.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", parsed.Query().Get("secret"))
+	assert.Equal(t, "Payment Service", parsed.Query().Get("issuer"))
+	assert.Equal(t, "6", parsed.Query().Get("digits"))
+}
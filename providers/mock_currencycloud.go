@@ -47,6 +47,7 @@ func (p *CurrencyCloudProvider) NameEnquiry(ctx context.Context, req NameEnquiry
 		return &NameEnquiryResponse{
 			AccountName: "Mock Account Holder",
 			Currency:    money.USD,
+			Country:     "US",
 		}, nil
 	}
 
@@ -54,6 +55,7 @@ func (p *CurrencyCloudProvider) NameEnquiry(ctx context.Context, req NameEnquiry
 	return &NameEnquiryResponse{
 		AccountName: fmt.Sprintf("Mock Account Holder %s", lastFour),
 		Currency:    money.USD,
+		Country:     "US",
 	}, nil
 }
 
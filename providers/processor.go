@@ -2,15 +2,24 @@ package providers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
 )
 
+// BankRouter names the provider a bank's payouts and name enquiries should
+// go to first, or returns "" when the bank has no preference.
+type BankRouter interface {
+	PreferredProvider(ctx context.Context, bankCode string) string
+}
+
 type Processor struct {
 	payoutProviders       []PayoutProvider
 	nameEnquiryProviders  []NameEnquiryProvider
 	exchangeRateProviders []ExchangeRateProvider
+	router                BankRouter
 }
 
 func NewProcessor() *Processor {
@@ -33,6 +42,19 @@ func (p *Processor) RegisterExchangeRateProvider(provider ExchangeRateProvider)
 	p.exchangeRateProviders = append(p.exchangeRateProviders, provider)
 }
 
+// SetBankRouter makes payouts and name enquiries consult router for the
+// destination bank's preferred provider.
+func (p *Processor) SetBankRouter(router BankRouter) {
+	p.router = router
+}
+
+func (p *Processor) preferredProvider(ctx context.Context, bankCode string) string {
+	if p.router == nil || bankCode == "" {
+		return ""
+	}
+	return p.router.PreferredProvider(ctx, bankCode)
+}
+
 func (p *Processor) SelectProvider(currency money.Currency) (PayoutProvider, error) {
 	for _, provider := range p.payoutProviders {
 		if provider.SupportsCurrency(currency) {
@@ -42,8 +64,22 @@ func (p *Processor) SelectProvider(currency money.Currency) (PayoutProvider, err
 	return nil, fmt.Errorf("no provider available for currency: %s", currency)
 }
 
+// SelectPayoutProvider picks the destination bank's preferred provider when
+// it is registered and supports the currency, and otherwise the first
+// provider that supports the currency.
+func (p *Processor) SelectPayoutProvider(ctx context.Context, bankCode string, currency money.Currency) (PayoutProvider, error) {
+	if preferred := p.preferredProvider(ctx, bankCode); preferred != "" {
+		for _, provider := range p.payoutProviders {
+			if strings.EqualFold(provider.Name(), preferred) && provider.SupportsCurrency(currency) {
+				return provider, nil
+			}
+		}
+	}
+	return p.SelectProvider(currency)
+}
+
 func (p *Processor) SendPayout(ctx context.Context, req PayoutRequest) (*PayoutResponse, error) {
-	provider, err := p.SelectProvider(req.Amount.Currency)
+	provider, err := p.SelectPayoutProvider(ctx, req.Destination.BankCode, req.Amount.Currency)
 	if err != nil {
 		return nil, err
 	}
@@ -57,12 +93,38 @@ func (p *Processor) SendPayout(ctx context.Context, req PayoutRequest) (*PayoutR
 	return resp, nil
 }
 
+// NameEnquiry asks the bank's preferred provider first and then the other
+// name enquiry providers in registration order, and returns the first
+// answer. The error lists every provider's failure.
 func (p *Processor) NameEnquiry(ctx context.Context, req NameEnquiryRequest) (*NameEnquiryResponse, error) {
 	if len(p.nameEnquiryProviders) == 0 {
 		return nil, fmt.Errorf("no name enquiry providers available")
 	}
 
-	return p.nameEnquiryProviders[0].NameEnquiry(ctx, req)
+	providers := p.nameEnquiryProviders
+	if preferred := p.preferredProvider(ctx, req.BankCode); preferred != "" {
+		providers = make([]NameEnquiryProvider, 0, len(p.nameEnquiryProviders))
+		for _, provider := range p.nameEnquiryProviders {
+			if strings.EqualFold(provider.Name(), preferred) {
+				providers = append(providers, provider)
+			}
+		}
+		for _, provider := range p.nameEnquiryProviders {
+			if !strings.EqualFold(provider.Name(), preferred) {
+				providers = append(providers, provider)
+			}
+		}
+	}
+
+	var errs []error
+	for _, provider := range providers {
+		resp, err := provider.NameEnquiry(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+	}
+	return nil, errors.Join(errs...)
 }
 
 func (p *Processor) GetExchangeRate(ctx context.Context, req ExchangeRateRequest) (*ExchangeRateResponse, error) {
@@ -37,6 +37,9 @@ type NameEnquiryRequest struct {
 type NameEnquiryResponse struct {
 	AccountName string
 	Currency    money.Currency
+	// Country is the ISO 3166-1 alpha-2 code of the account's bank, when the
+	// provider reports it.
+	Country string
 }
 
 type Provider interface {
@@ -104,7 +104,7 @@ func (q *RabbitMQQueue) setupQueues() error {
 		return fmt.Errorf("declare exchange: %w", err)
 	}
 
-	queues := []string{"payout", "webhook"}
+	queues := []string{"payout", "webhook", "notification"}
 	for _, queueName := range queues {
 		queue, err := ch.QueueDeclare(
 			queueName,
@@ -9,8 +9,9 @@ import (
 type JobType string
 
 const (
-	JobTypePayout  JobType = "payout"
-	JobTypeWebhook JobType = "webhook"
+	JobTypePayout       JobType = "payout"
+	JobTypeWebhook      JobType = "webhook"
+	JobTypeNotification JobType = "notification"
 )
 
 const (
@@ -40,13 +41,14 @@ type Queue interface {
 	Close() error
 }
 
+// PayoutJobPayload identifies the transfer to pay out. The worker reads the
+// recipient from transfer_recipients, so account details never sit in the
+// queue.
 type PayoutJobPayload struct {
 	TransactionID string `json:"transaction_id"`
 	TraceID       string `json:"trace_id"`
 	Amount        int64  `json:"amount"`
 	Currency      string `json:"currency"`
-	AccountNumber string `json:"account_number"`
-	BankCode      string `json:"bank_code"`
 }
 
 type WebhookJobPayload struct {
@@ -56,3 +58,15 @@ type WebhookJobPayload struct {
 	TransactionID     *string `json:"transaction_id,omitempty"`
 	Payload           []byte  `json:"payload"`
 }
+
+// NotificationJobPayload tells a user about something that happened to their
+// account. Event names what happened; the IDs that apply to it are set.
+type NotificationJobPayload struct {
+	Event            string `json:"event"`
+	UserID           string `json:"user_id"`
+	PaymentRequestID string `json:"payment_request_id,omitempty"`
+	TransactionID    string `json:"transaction_id,omitempty"`
+	Amount           int64  `json:"amount"`
+	Currency         string `json:"currency"`
+	TraceID          string `json:"trace_id"`
+}
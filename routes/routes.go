@@ -10,7 +10,12 @@ import (
 	emw "github.com/labstack/echo/v4/middleware"
 )
 
-func Register(e *echo.Echo, cfg *config.Config, handlers *handlers.Handlers) {
+func Register(e *echo.Echo, cfg *config.Config, handlers *handlers.Handlers, rateLimitStore middleware.RateLimitStore) error {
+	authenticate, err := middleware.NewAuthMiddleware(cfg)
+	if err != nil {
+		return err
+	}
+
 	e.GET("/health", func(c echo.Context) error {
 		return c.String(http.StatusOK, "Ok")
 	})
@@ -41,29 +46,86 @@ func Register(e *echo.Echo, cfg *config.Config, handlers *handlers.Handlers) {
 	api := e.Group("/api")
 	api.Use(emw.Logger(), emw.Recover())
 	api.Use(middleware.TraceIDMiddleware())
-	api.Use(middleware.UserIDMiddleware())
+	api.Use(authenticate)
+	api.Use(middleware.AdminRole(cfg.AdminUserIDs))
+	api.Use(middleware.RateLimit("default", cfg.RateLimitDefault, rateLimitStore))
 
-	RegisterPaymentRoutes(api, handlers)
+	RegisterPaymentRoutes(api, handlers, middleware.RequireAdmin(cfg.AdminUserIDs), RouteRateLimits{
+		Transfers:   middleware.RateLimit("transfers", cfg.RateLimitTransfers, rateLimitStore),
+		NameEnquiry: middleware.RateLimit("name_enquiry", cfg.RateLimitNameEnquiry, rateLimitStore),
+	})
 
 	// Test endpoint (no authentication required)
 	testApi := e.Group("/api/test")
 	testApi.Use(emw.Logger(), emw.Recover())
 	testApi.Use(middleware.TraceIDMiddleware())
+	testApi.Use(middleware.RateLimit("default", cfg.RateLimitDefault, rateLimitStore))
 	RegisterTestRoutes(testApi, handlers)
+
+	return nil
 }
 
-func RegisterPaymentRoutes(api *echo.Group, handlers *handlers.Handlers) {
+// RouteRateLimits are stricter limits applied to individual routes on top of
+// the group default.
+type RouteRateLimits struct {
+	Transfers   echo.MiddlewareFunc
+	NameEnquiry echo.MiddlewareFunc
+}
+
+func RegisterPaymentRoutes(api *echo.Group, handlers *handlers.Handlers, requireAdmin echo.MiddlewareFunc, limits RouteRateLimits) {
 	api.GET("/exchange-rate", handlers.Payment.GetExchangeRate)
 	api.GET("/wallets", handlers.Payment.GetUserWallets)
+	api.POST("/wallets", handlers.Wallet.OpenWallet)
 	api.GET("/transactions", handlers.Payment.GetTransactionHistory)
-	api.POST("/payments/internal", handlers.Payment.CreateInternalTransfer)
-	api.POST("/payments/external", handlers.Payment.CreateExternalTransfer)
+	api.GET("/limits", handlers.Limit.GetLimits)
+	api.POST("/payments/internal", handlers.Payment.CreateInternalTransfer, limits.Transfers)
+	api.POST("/payments/external", handlers.Payment.CreateExternalTransfer, limits.Transfers)
 	api.POST("/payments/:id/confirm", handlers.Payment.ConfirmTransaction)
+	api.POST("/payments/:id/cancel", handlers.Payment.CancelTransaction)
+	api.POST("/payments/:id/reverse", handlers.Payment.ReverseTransaction, requireAdmin)
+	api.POST("/payments/:id/refunds", handlers.Refund.CreateRefund, requireAdmin)
 	api.GET("/payments/:id", handlers.Payment.GetTransaction)
 
-	api.POST("/webhooks/:provider", handlers.Webhook.ReceiveWebhook)
+	api.POST("/users/me", handlers.User.RegisterUser)
+	api.GET("/users/me", handlers.User.GetProfile)
+	api.PATCH("/users/me", handlers.User.UpdateProfile)
+	api.POST("/users/me/kyc/documents", handlers.User.SubmitKYCDocument)
+	api.POST("/users/me/pin", handlers.PIN.SetPIN)
+	api.PUT("/users/me/pin", handlers.PIN.ChangePIN)
+	api.POST("/users/me/pin/reset", handlers.PIN.ResetPIN)
+	api.POST("/users/me/totp", handlers.TOTP.Enroll)
+	api.POST("/users/me/totp/verify", handlers.TOTP.Activate)
+	api.DELETE("/users/me/totp", handlers.TOTP.Disable)
+
+	api.POST("/admin/users/:id/pin/unlock", handlers.PIN.UnlockPIN, requireAdmin)
+	api.POST("/admin/users/:id/pin/reset-token", handlers.PIN.CreatePINResetToken, requireAdmin)
+	api.GET("/admin/reviews", handlers.Risk.ListPendingReviews, requireAdmin)
+	api.POST("/admin/payments/:id/review", handlers.Risk.ReviewTransaction, requireAdmin)
+	api.GET("/admin/audit-events", handlers.Audit.ListEvents, requireAdmin)
+	api.GET("/admin/audit-events/verify", handlers.Audit.VerifyChain, requireAdmin)
+	api.POST("/admin/wallets/:id/freeze", handlers.Wallet.FreezeWallet, requireAdmin)
+	api.POST("/admin/wallets/:id/unfreeze", handlers.Wallet.UnfreezeWallet, requireAdmin)
+	api.POST("/admin/wallets/:id/close", handlers.Wallet.CloseWallet, requireAdmin)
+	api.GET("/admin/kyc/documents", handlers.User.ListKYCDocuments, requireAdmin)
+	api.POST("/admin/kyc/documents/:id/review", handlers.User.ReviewKYCDocument, requireAdmin)
+
+	api.POST("/payment-requests", handlers.PaymentRequest.CreatePaymentRequest)
+	api.GET("/payment-requests", handlers.PaymentRequest.ListPaymentRequests)
+	api.POST("/payment-requests/:id/cancel", handlers.PaymentRequest.CancelPaymentRequest)
+	api.GET("/payment-links/:token", handlers.PaymentRequest.GetPaymentLink)
+	api.POST("/payment-links/:token/pay", handlers.PaymentRequest.PayPaymentLink, limits.Transfers)
 
-	api.POST("/name-enquiry", handlers.NameEnquiry.EnquireAccountName)
+	api.POST("/webhooks/:provider", handlers.Webhook.ReceiveWebhook, middleware.RequireWebhookProvider())
+
+	api.POST("/name-enquiry", handlers.NameEnquiry.EnquireAccountName, limits.NameEnquiry)
+	api.GET("/banks", handlers.Bank.ListBanks)
+
+	// Saving or changing a beneficiary runs name enquiry, so it shares that limit.
+	api.POST("/beneficiaries", handlers.Beneficiary.CreateBeneficiary, limits.NameEnquiry)
+	api.GET("/beneficiaries", handlers.Beneficiary.ListBeneficiaries)
+	api.GET("/beneficiaries/:id", handlers.Beneficiary.GetBeneficiary)
+	api.PATCH("/beneficiaries/:id", handlers.Beneficiary.UpdateBeneficiary, limits.NameEnquiry)
+	api.DELETE("/beneficiaries/:id", handlers.Beneficiary.DeleteBeneficiary)
 }
 
 func RegisterTestRoutes(testApi *echo.Group, handlers *handlers.Handlers) {
@@ -85,25 +147,66 @@ func getOpenAPISpec() map[string]interface{} {
 			},
 		},
 		"paths": map[string]interface{}{
-			"/health":                    getHealthEndpoint(),
-			"/api/test/users":            getTestUsersEndpoint(),
-			"/api/name-enquiry":          getNameEnquiryEndpoint(),
-			"/api/exchange-rate":         getExchangeRateEndpoint(),
-			"/api/wallets":               getWalletsEndpoint(),
-			"/api/payments/internal":     getCreateInternalTransferEndpoint(),
-			"/api/payments/external":     getCreateExternalTransferEndpoint(),
-			"/api/payments/{id}/confirm": getConfirmTransactionEndpoint(),
-			"/api/payments/{id}":         getGetTransactionEndpoint(),
-			"/api/transactions":          getTransactionHistoryEndpoint(),
-			"/api/webhooks/{provider}":   getWebhookEndpoint(),
+			"/health":                           getHealthEndpoint(),
+			"/api/test/users":                   getTestUsersEndpoint(),
+			"/api/name-enquiry":                 getNameEnquiryEndpoint(),
+			"/api/banks":                        getBanksEndpoint(),
+			"/api/exchange-rate":                getExchangeRateEndpoint(),
+			"/api/wallets":                      getWalletsEndpoint(),
+			"/api/payments/internal":            getCreateInternalTransferEndpoint(),
+			"/api/payments/external":            getCreateExternalTransferEndpoint(),
+			"/api/payments/{id}/confirm":        getConfirmTransactionEndpoint(),
+			"/api/payments/{id}/cancel":         getCancelTransactionEndpoint(),
+			"/api/payments/{id}/reverse":        getReverseTransactionEndpoint(),
+			"/api/payments/{id}/refunds":        getCreateRefundEndpoint(),
+			"/api/payments/{id}":                getGetTransactionEndpoint(),
+			"/api/transactions":                 getTransactionHistoryEndpoint(),
+			"/api/limits":                       getLimitsEndpoint(),
+			"/api/webhooks/{provider}":          getWebhookEndpoint(),
+			"/api/beneficiaries":                getBeneficiariesEndpoint(),
+			"/api/beneficiaries/{id}":           getBeneficiaryEndpoint(),
+			"/api/payment-requests":             getPaymentRequestsEndpoint(),
+			"/api/payment-requests/{id}/cancel": getCancelPaymentRequestEndpoint(),
+			"/api/payment-links/{token}":        getPaymentLinkEndpoint(),
+			"/api/payment-links/{token}/pay":    getPayPaymentLinkEndpoint(),
+
+			"/api/users/me":                         getUserProfileEndpoint(),
+			"/api/users/me/kyc/documents":           getSubmitKYCDocumentEndpoint(),
+			"/api/users/me/pin":                     getPINEndpoint(),
+			"/api/users/me/pin/reset":               getResetPINEndpoint(),
+			"/api/users/me/totp":                    getTOTPEndpoint(),
+			"/api/users/me/totp/verify":             getVerifyTOTPEndpoint(),
+			"/api/admin/users/{id}/pin/unlock":      getUnlockPINEndpoint(),
+			"/api/admin/users/{id}/pin/reset-token": getCreatePINResetTokenEndpoint(),
+			"/api/admin/reviews":                    getPendingReviewsEndpoint(),
+			"/api/admin/payments/{id}/review":       getReviewTransactionEndpoint(),
+			"/api/admin/audit-events":               getAuditEventsEndpoint(),
+			"/api/admin/audit-events/verify":        getVerifyAuditChainEndpoint(),
+			"/api/admin/wallets/{id}/freeze":        getFreezeWalletEndpoint(),
+			"/api/admin/wallets/{id}/unfreeze":      getUnfreezeWalletEndpoint(),
+			"/api/admin/wallets/{id}/close":         getCloseWalletEndpoint(),
+			"/api/admin/kyc/documents":              getKYCDocumentsEndpoint(),
+			"/api/admin/kyc/documents/{id}/review":  getReviewKYCDocumentEndpoint(),
 		},
 		"components": map[string]interface{}{
 			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{
+					"type":         "http",
+					"scheme":       "bearer",
+					"bearerFormat": "JWT",
+					"description":  "HS256 or RS256 JWT. The sub claim is the user ID; scopes come from the scope claim.",
+				},
+				"apiKey": map[string]interface{}{
+					"type":        "apiKey",
+					"in":          "header",
+					"name":        "X-API-Key",
+					"description": "API key for server-to-server callers, configured with AUTH_API_KEYS",
+				},
 				"X-User-ID": map[string]interface{}{
 					"type":        "apiKey",
 					"in":          "header",
 					"name":        "X-User-ID",
-					"description": "User ID for authentication (e.g., user_1)",
+					"description": "Unverified user ID (e.g., user_1). Local development only, enabled with AUTH_ALLOW_USER_ID_HEADER.",
 				},
 			},
 			"schemas": getSchemas(),
@@ -111,6 +214,16 @@ func getOpenAPISpec() map[string]interface{} {
 	}
 }
 
+// getSecurityRequirements lists the alternative credentials accepted on
+// authenticated endpoints.
+func getSecurityRequirements() []map[string]interface{} {
+	return []map[string]interface{}{
+		{"bearerAuth": []string{}},
+		{"apiKey": []string{}},
+		{"X-User-ID": []string{}},
+	}
+}
+
 func getHealthEndpoint() map[string]interface{} {
 	return map[string]interface{}{
 		"get": map[string]interface{}{
@@ -186,12 +299,10 @@ func getNameEnquiryEndpoint() map[string]interface{} {
 	return map[string]interface{}{
 		"post": map[string]interface{}{
 			"summary":     "Name enquiry",
-			"description": "Check if an account number and bank code belong to an internal user or external account. Returns account name, whether it's internal, and currency.",
+			"description": "Check if an account number and bank code belong to an internal user or external account. Returns account name, whether it's internal, and currency. When account_name is sent, also scores it against the resolved name and returns name_match with a verdict of match, partial or mismatch. External results are cached briefly.",
 			"operationId": "nameEnquiry",
 			"tags":        []string{"Payments"},
-			"security": []map[string]interface{}{
-				{"X-User-ID": []string{}},
-			},
+			"security":    getSecurityRequirements(),
 			"requestBody": map[string]interface{}{
 				"required": true,
 				"content": map[string]interface{}{
@@ -202,6 +313,7 @@ func getNameEnquiryEndpoint() map[string]interface{} {
 						"example": map[string]interface{}{
 							"account_number": "1000000001",
 							"bank_code":      "044",
+							"account_name":   "John Doe",
 						},
 					},
 				},
@@ -219,393 +331,526 @@ func getNameEnquiryEndpoint() map[string]interface{} {
 									"account_name": "John Doe",
 									"is_internal":  true,
 									"currency":     "USD",
+									"name_match": map[string]interface{}{
+										"score":   1.0,
+										"verdict": "match",
+									},
 								},
 								"message": "account name retrieved successfully",
 							},
 						},
 					},
 				},
-				"400": getErrorResponse("Bad request - invalid parameters"),
+				"400": getErrorResponse("Bad request - invalid parameters or unknown bank code"),
+				"429": getRateLimitedResponse(),
 				"500": getErrorResponse("Internal server error"),
 			},
 		},
 	}
 }
 
-func getExchangeRateEndpoint() map[string]interface{} {
+func getBanksEndpoint() map[string]interface{} {
 	return map[string]interface{}{
 		"get": map[string]interface{}{
-			"summary":     "Get exchange rate",
-			"description": "Get current exchange rate between two currencies. Rates are locked at transaction initiation time.",
-			"operationId": "getExchangeRate",
+			"summary":     "List banks",
+			"description": "List the banks in the bank directory, by name. Transfers, name enquiries and beneficiaries only accept bank codes from this list, and transfers only in a currency the bank accepts.",
+			"operationId": "listBanks",
 			"tags":        []string{"Payments"},
-			"security": []map[string]interface{}{
-				{"X-User-ID": []string{}},
-			},
+			"security":    getSecurityRequirements(),
 			"parameters": []map[string]interface{}{
 				{
-					"name":        "from",
+					"name":        "search",
 					"in":          "query",
-					"required":    true,
-					"description": "Source currency",
+					"required":    false,
+					"description": "Match a bank code exactly or any part of a bank name, ignoring case",
 					"schema": map[string]interface{}{
 						"type":    "string",
-						"enum":    []string{"USD", "EUR", "GBP"},
-						"example": "USD",
+						"example": "test",
 					},
 				},
 				{
-					"name":        "to",
+					"name":        "country",
 					"in":          "query",
-					"required":    true,
-					"description": "Destination currency",
+					"required":    false,
+					"description": "ISO 3166-1 alpha-2 country code",
 					"schema": map[string]interface{}{
 						"type":    "string",
-						"enum":    []string{"USD", "EUR", "GBP"},
-						"example": "EUR",
+						"example": "US",
 					},
 				},
 			},
 			"responses": map[string]interface{}{
 				"200": map[string]interface{}{
-					"description": "Exchange rate retrieved successfully",
+					"description": "Banks retrieved",
 					"content": map[string]interface{}{
 						"application/json": map[string]interface{}{
 							"schema": map[string]interface{}{
-								"$ref": "#/components/schemas/SuccessResponse",
-							},
-							"example": map[string]interface{}{
-								"data": map[string]interface{}{
-									"from_currency": "USD",
-									"to_currency":   "EUR",
-									"rate":          0.85,
+								"type": "object",
+								"properties": map[string]interface{}{
+									"data": map[string]interface{}{
+										"type": "array",
+										"items": map[string]interface{}{
+											"$ref": "#/components/schemas/BankResponse",
+										},
+									},
+									"message": map[string]interface{}{
+										"type":    "string",
+										"example": "banks retrieved successfully",
+									},
 								},
-								"message": "exchange rate retrieved successfully",
 							},
 						},
 					},
 				},
-				"400": getErrorResponse("Bad request - invalid currency"),
+				"400": getErrorResponse("Bad request - invalid country parameter"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
 				"500": getErrorResponse("Internal server error"),
 			},
 		},
 	}
 }
 
-func getWalletsEndpoint() map[string]interface{} {
+func getBeneficiariesEndpoint() map[string]interface{} {
 	return map[string]interface{}{
-		"get": map[string]interface{}{
-			"summary":     "Get user wallets",
-			"description": "Get all wallets for the authenticated user with bank account details and cached balances (optimized, not ledger sum).",
-			"operationId": "getUserWallets",
-			"tags":        []string{"Wallets"},
-			"security": []map[string]interface{}{
-				{"X-User-ID": []string{}},
+		"post": map[string]interface{}{
+			"summary":     "Save beneficiary",
+			"description": "Save an account to pay again. The account is verified by name enquiry, and its account name, currency and country are taken from the enquiry. Counts towards the name enquiry rate limit.",
+			"operationId": "createBeneficiary",
+			"tags":        []string{"Beneficiaries"},
+			"security":    getSecurityRequirements(),
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"$ref": "#/components/schemas/CreateBeneficiaryRequest",
+						},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"201": getBeneficiaryResponse("Beneficiary saved", "beneficiary saved successfully"),
+				"400": getErrorResponse("Bad request - validation error or unknown bank code"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"409": getErrorResponse("Conflict - account already saved as a beneficiary"),
+				"429": getRateLimitedResponse(),
+				"500": getErrorResponse("Internal server error"),
 			},
+		},
+		"get": map[string]interface{}{
+			"summary":     "List beneficiaries",
+			"description": "List the authenticated user's saved beneficiaries, newest first.",
+			"operationId": "listBeneficiaries",
+			"tags":        []string{"Beneficiaries"},
+			"security":    getSecurityRequirements(),
 			"responses": map[string]interface{}{
 				"200": map[string]interface{}{
-					"description": "Wallets retrieved successfully",
+					"description": "Beneficiaries retrieved",
 					"content": map[string]interface{}{
 						"application/json": map[string]interface{}{
 							"schema": map[string]interface{}{
-								"$ref": "#/components/schemas/SuccessResponse",
-							},
-							"example": map[string]interface{}{
-								"data": []map[string]interface{}{
-									{
-										"id":             "wallet_user1_usd",
-										"currency":       "USD",
-										"balance":        100.50,
-										"account_number": "1000000001",
-										"bank_name":      "Test Bank",
-										"bank_code":      "044",
-										"account_name":   "John Doe",
-										"provider":       "currencycloud",
-										"created_at":     "2026-01-11T00:00:00Z",
-										"updated_at":     "2026-01-11T00:00:00Z",
+								"type": "object",
+								"properties": map[string]interface{}{
+									"data": map[string]interface{}{
+										"type": "array",
+										"items": map[string]interface{}{
+											"$ref": "#/components/schemas/BeneficiaryResponse",
+										},
+									},
+									"message": map[string]interface{}{
+										"type":    "string",
+										"example": "beneficiaries retrieved successfully",
 									},
 								},
-								"message": "wallets retrieved successfully",
 							},
 						},
 					},
 				},
-				"401": getErrorResponse("Unauthorized - missing or invalid X-User-ID"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
 				"500": getErrorResponse("Internal server error"),
 			},
 		},
 	}
 }
 
-func getCreateInternalTransferEndpoint() map[string]interface{} {
-	return map[string]interface{}{
-		"post": map[string]interface{}{
-			"summary":     "Create internal transfer",
-			"description": "Initiate an internal transfer between users. Uses account number and bank code to identify recipient. Same user transfers (different currency) process immediately. Different user transfers require PIN confirmation.",
-			"operationId": "createInternalTransfer",
-			"tags":        []string{"Payments"},
-			"security": []map[string]interface{}{
-				{"X-User-ID": []string{}},
+func getBeneficiaryEndpoint() map[string]interface{} {
+	idParameter := []map[string]interface{}{
+		{
+			"name":     "id",
+			"in":       "path",
+			"required": true,
+			"schema": map[string]interface{}{
+				"type": "string",
 			},
-			"parameters": []map[string]interface{}{
-				{
-					"name":        "Idempotency-Key",
-					"in":          "header",
-					"required":    true,
-					"description": "Unique key for idempotency",
-					"schema": map[string]interface{}{
-						"type":    "string",
-						"example": "unique-key-123",
-					},
-				},
+		},
+	}
+
+	return map[string]interface{}{
+		"get": map[string]interface{}{
+			"summary":     "Get beneficiary",
+			"operationId": "getBeneficiary",
+			"tags":        []string{"Beneficiaries"},
+			"security":    getSecurityRequirements(),
+			"parameters":  idParameter,
+			"responses": map[string]interface{}{
+				"200": getBeneficiaryResponse("Beneficiary retrieved", "beneficiary retrieved successfully"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"404": getErrorResponse("Not found - beneficiary not found"),
+				"500": getErrorResponse("Internal server error"),
 			},
+		},
+		"patch": map[string]interface{}{
+			"summary":     "Update beneficiary",
+			"description": "Change a beneficiary's nickname or account. A new account number or bank code is verified by name enquiry again and restarts the new-beneficiary cool-down.",
+			"operationId": "updateBeneficiary",
+			"tags":        []string{"Beneficiaries"},
+			"security":    getSecurityRequirements(),
+			"parameters":  idParameter,
 			"requestBody": map[string]interface{}{
 				"required": true,
 				"content": map[string]interface{}{
 					"application/json": map[string]interface{}{
 						"schema": map[string]interface{}{
-							"$ref": "#/components/schemas/CreateInternalTransferRequest",
-						},
-						"example": map[string]interface{}{
-							"from_currency":     "USD",
-							"to_account_number": "2000000001",
-							"to_bank_code":      "044",
-							"amount": map[string]interface{}{
-								"amount":   100.50,
-								"currency": "EUR",
-							},
+							"$ref": "#/components/schemas/UpdateBeneficiaryRequest",
 						},
 					},
 				},
 			},
 			"responses": map[string]interface{}{
-				"201": map[string]interface{}{
-					"description": "Transfer initiated or completed",
+				"200": getBeneficiaryResponse("Beneficiary updated", "beneficiary updated successfully"),
+				"400": getErrorResponse("Bad request - validation error or unknown bank code"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"404": getErrorResponse("Not found - beneficiary not found"),
+				"409": getErrorResponse("Conflict - account already saved as another beneficiary"),
+				"429": getRateLimitedResponse(),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+		"delete": map[string]interface{}{
+			"summary":     "Delete beneficiary",
+			"description": "Remove a saved beneficiary. Transfers already made to it keep their beneficiary_id.",
+			"operationId": "deleteBeneficiary",
+			"tags":        []string{"Beneficiaries"},
+			"security":    getSecurityRequirements(),
+			"parameters":  idParameter,
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Beneficiary deleted",
 					"content": map[string]interface{}{
 						"application/json": map[string]interface{}{
 							"schema": map[string]interface{}{
 								"$ref": "#/components/schemas/SuccessResponse",
 							},
-							"examples": map[string]interface{}{
-								"completed": map[string]interface{}{
-									"summary": "Immediate completion (same user)",
-									"value": map[string]interface{}{
-										"data": map[string]interface{}{
-											"id":            "tx-id",
-											"status":        "completed",
-											"amount":        100.50,
-											"currency":      "EUR",
-											"exchange_rate": 0.85,
-										},
-										"message": "transfer completed successfully",
-									},
-								},
-								"initiated": map[string]interface{}{
-									"summary": "Requires confirmation (different user)",
-									"value": map[string]interface{}{
-										"data": map[string]interface{}{
-											"id":            "tx-id",
-											"status":        "initiated",
-											"amount":        100.50,
-											"currency":      "EUR",
-											"exchange_rate": 0.85,
-										},
-										"message": "transfer initiated, please confirm with PIN",
-									},
+							"example": map[string]interface{}{
+								"data": map[string]interface{}{
+									"id": "beneficiary-id",
 								},
+								"message": "beneficiary deleted successfully",
 							},
 						},
 					},
 				},
-				"400": getErrorResponse("Bad request - validation error, insufficient funds, or invalid parameters"),
-				"401": getErrorResponse("Unauthorized - missing or invalid X-User-ID"),
-				"404": getErrorResponse("Not found - sender wallet or recipient account not found"),
-				"409": getErrorResponse("Conflict - duplicate idempotency key"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"404": getErrorResponse("Not found - beneficiary not found"),
 				"500": getErrorResponse("Internal server error"),
 			},
 		},
 	}
 }
 
-func getCreateExternalTransferEndpoint() map[string]interface{} {
+func getBeneficiaryResponse(description string, message string) map[string]interface{} {
 	return map[string]interface{}{
-		"post": map[string]interface{}{
-			"summary":     "Create external transfer",
-			"description": "Initiate an external transfer to a bank account outside the system. Always requires PIN confirmation. Exchange rate is locked at initiation time.",
-			"operationId": "createExternalTransfer",
-			"tags":        []string{"Payments"},
-			"security": []map[string]interface{}{
-				{"X-User-ID": []string{}},
-			},
-			"parameters": []map[string]interface{}{
-				{
-					"name":        "Idempotency-Key",
-					"in":          "header",
-					"required":    true,
-					"description": "Unique key for idempotency",
-					"schema": map[string]interface{}{
-						"type":    "string",
-						"example": "unique-key-123",
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"data": map[string]interface{}{
+							"$ref": "#/components/schemas/BeneficiaryResponse",
+						},
+						"message": map[string]interface{}{
+							"type":    "string",
+							"example": message,
+						},
 					},
 				},
 			},
+		},
+	}
+}
+
+func getPaymentRequestsEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     "Create payment request",
+			"description": "Ask to be paid an amount into the authenticated user's wallet in that currency. The response carries a token for the shareable payment link. expires_at defaults to PAYMENT_REQUEST_DEFAULT_TTL from now and may not exceed PAYMENT_REQUEST_MAX_TTL.",
+			"operationId": "createPaymentRequest",
+			"tags":        []string{"Payment Requests"},
+			"security":    getSecurityRequirements(),
 			"requestBody": map[string]interface{}{
 				"required": true,
 				"content": map[string]interface{}{
 					"application/json": map[string]interface{}{
 						"schema": map[string]interface{}{
-							"$ref": "#/components/schemas/CreateExternalTransferRequest",
+							"$ref": "#/components/schemas/CreatePaymentRequestRequest",
 						},
 						"example": map[string]interface{}{
-							"from_currency":     "USD",
-							"to_account_number": "9999999999",
-							"to_bank_code":      "044",
 							"amount": map[string]interface{}{
-								"amount":   50.00,
-								"currency": "GBP",
+								"amount":   25.00,
+								"currency": "USD",
 							},
+							"memo": "Dinner on Friday",
 						},
 					},
 				},
 			},
 			"responses": map[string]interface{}{
-				"201": map[string]interface{}{
-					"description": "External transfer initiated",
+				"201": getPaymentRequestResponse("Payment request created", "payment request created successfully"),
+				"400": getErrorResponse("Bad request - validation error, memo too long, or expiry in the past or beyond the maximum"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"404": getErrorResponse("Not found - no wallet in the requested currency"),
+				"423": getWalletLockedResponse(),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+		"get": map[string]interface{}{
+			"summary":     "List payment requests",
+			"description": "List the payment requests the authenticated user has created, newest first.",
+			"operationId": "listPaymentRequests",
+			"tags":        []string{"Payment Requests"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				{
+					"name":        "limit",
+					"in":          "query",
+					"required":    false,
+					"description": "Maximum number of payment requests to return (default 20, max 100)",
+					"schema": map[string]interface{}{
+						"type":    "integer",
+						"example": 20,
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Payment requests retrieved",
 					"content": map[string]interface{}{
 						"application/json": map[string]interface{}{
 							"schema": map[string]interface{}{
-								"$ref": "#/components/schemas/SuccessResponse",
-							},
-							"example": map[string]interface{}{
-								"data": map[string]interface{}{
-									"id":            "tx-id",
-									"status":        "initiated",
-									"amount":        50.00,
-									"currency":      "GBP",
-									"exchange_rate": 0.75,
+								"type": "object",
+								"properties": map[string]interface{}{
+									"data": map[string]interface{}{
+										"type": "array",
+										"items": map[string]interface{}{
+											"$ref": "#/components/schemas/PaymentRequestResponse",
+										},
+									},
+									"message": map[string]interface{}{
+										"type":    "string",
+										"example": "payment requests retrieved successfully",
+									},
 								},
-								"message": "external transfer initiated, please confirm with PIN",
 							},
 						},
 					},
 				},
-				"400": getErrorResponse("Bad request - validation error, insufficient funds, or invalid parameters"),
-				"401": getErrorResponse("Unauthorized - missing or invalid X-User-ID"),
-				"404": getErrorResponse("Not found - sender wallet not found"),
-				"409": getErrorResponse("Conflict - duplicate idempotency key"),
+				"400": getErrorResponse("Bad request - invalid limit parameter"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
 				"500": getErrorResponse("Internal server error"),
 			},
 		},
 	}
 }
 
-func getConfirmTransactionEndpoint() map[string]interface{} {
+func getCancelPaymentRequestEndpoint() map[string]interface{} {
 	return map[string]interface{}{
 		"post": map[string]interface{}{
-			"summary":     "Confirm transaction",
-			"description": "Confirm an initiated transaction with PIN. Transaction must be in 'initiated' status and not expired (10 minutes from creation). Internal transfers complete immediately. External transfers are queued for asynchronous processing.",
-			"operationId": "confirmTransaction",
-			"tags":        []string{"Payments"},
-			"security": []map[string]interface{}{
-				{"X-User-ID": []string{}},
-			},
+			"summary":     "Cancel payment request",
+			"description": "Cancel a pending payment request. Only the requester can cancel. A transfer already initiated against the link fails when it is confirmed.",
+			"operationId": "cancelPaymentRequest",
+			"tags":        []string{"Payment Requests"},
+			"security":    getSecurityRequirements(),
 			"parameters": []map[string]interface{}{
 				{
 					"name":        "id",
 					"in":          "path",
 					"required":    true,
-					"description": "Transaction ID",
+					"description": "Payment request ID",
 					"schema": map[string]interface{}{
-						"type":    "string",
-						"example": "tx-id-123",
+						"type": "string",
 					},
 				},
 			},
+			"responses": map[string]interface{}{
+				"200": getPaymentRequestResponse("Payment request cancelled", "payment request cancelled successfully"),
+				"400": getErrorResponse("Bad request - payment request is no longer pending"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"404": getErrorResponse("Not found - payment request not found"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getPaymentLinkEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"get": map[string]interface{}{
+			"summary":     "Get payment link",
+			"description": "Look up the payment request behind a shareable link. transaction_id and paid_by are only shown to the requester.",
+			"operationId": "getPaymentLink",
+			"tags":        []string{"Payment Requests"},
+			"security":    getSecurityRequirements(),
+			"parameters":  getPaymentLinkTokenParameter(),
+			"responses": map[string]interface{}{
+				"200": getPaymentRequestResponse("Payment request retrieved", "payment request retrieved successfully"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"404": getErrorResponse("Not found - payment link not found"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getPayPaymentLinkEndpoint() map[string]interface{} {
+	parameters := append(getPaymentLinkTokenParameter(), map[string]interface{}{
+		"name":        "Idempotency-Key",
+		"in":          "header",
+		"required":    true,
+		"description": "Unique key for idempotency",
+		"schema": map[string]interface{}{
+			"type":    "string",
+			"example": "unique-key-123",
+		},
+	})
+
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     "Pay payment link",
+			"description": "Initiate an internal transfer of the requested amount to the requester, debited from the payer's wallet in from_currency. The transfer is confirmed with PIN like any other, and the request is marked paid when it is confirmed. Counts towards the transfer rate limit.",
+			"operationId": "payPaymentLink",
+			"tags":        []string{"Payment Requests"},
+			"security":    getSecurityRequirements(),
+			"parameters":  parameters,
 			"requestBody": map[string]interface{}{
 				"required": true,
 				"content": map[string]interface{}{
 					"application/json": map[string]interface{}{
 						"schema": map[string]interface{}{
-							"$ref": "#/components/schemas/ConfirmTransactionRequest",
+							"$ref": "#/components/schemas/PayPaymentRequestRequest",
 						},
 						"example": map[string]interface{}{
-							"pin": "12345",
+							"from_currency": "USD",
 						},
 					},
 				},
 			},
 			"responses": map[string]interface{}{
-				"200": map[string]interface{}{
-					"description": "Transaction confirmed",
+				"201": map[string]interface{}{
+					"description": "Transfer initiated",
 					"content": map[string]interface{}{
 						"application/json": map[string]interface{}{
 							"schema": map[string]interface{}{
 								"$ref": "#/components/schemas/SuccessResponse",
 							},
-							"examples": map[string]interface{}{
-								"internal": map[string]interface{}{
-									"summary": "Internal transfer completed",
-									"value": map[string]interface{}{
-										"data": map[string]interface{}{
-											"id":       "tx-id",
-											"status":   "completed",
-											"amount":   100.50,
-											"currency": "EUR",
-										},
-										"message": "transaction confirmed and completed successfully",
-									},
-								},
-								"external": map[string]interface{}{
-									"summary": "External transfer queued",
-									"value": map[string]interface{}{
-										"data": map[string]interface{}{
-											"id":                 "tx-id",
-											"status":             "pending",
-											"amount":             50.00,
-											"currency":           "GBP",
-											"provider_reference": "{\"account_number\":\"9999999999\",\"bank_code\":\"044\"}",
-										},
-										"message": "transaction confirmed and queued for processing",
-									},
+							"example": map[string]interface{}{
+								"data": map[string]interface{}{
+									"id":                 "tx-id",
+									"status":             "initiated",
+									"amount":             25.00,
+									"currency":           "USD",
+									"payment_request_id": "payment-request-id",
 								},
+								"message": "transfer initiated, please confirm with PIN",
 							},
 						},
 					},
 				},
-				"400": getErrorResponse("Bad request - invalid PIN, expired transaction, or insufficient funds"),
-				"401": getErrorResponse("Unauthorized - missing or invalid X-User-ID"),
-				"404": getErrorResponse("Not found - transaction not found"),
+				"400": getErrorResponse("Bad request - validation error, insufficient funds, own payment request, or payment request no longer pending"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"404": getErrorResponse("Not found - payment link or payer wallet not found"),
+				"409": getErrorResponse("Conflict - duplicate idempotency key"),
+				"422": getCreateTransferUnprocessableResponse(),
+				"403": getKYCRequiredResponse(),
+				"423": getWalletLockedResponse(),
+				"429": getRateLimitedResponse(),
 				"500": getErrorResponse("Internal server error"),
 			},
 		},
 	}
 }
 
-func getGetTransactionEndpoint() map[string]interface{} {
+func getPaymentLinkTokenParameter() []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"name":        "token",
+			"in":          "path",
+			"required":    true,
+			"description": "Payment link token",
+			"schema": map[string]interface{}{
+				"type": "string",
+			},
+		},
+	}
+}
+
+func getPaymentRequestResponse(description string, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"data": map[string]interface{}{
+							"$ref": "#/components/schemas/PaymentRequestResponse",
+						},
+						"message": map[string]interface{}{
+							"type":    "string",
+							"example": message,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getExchangeRateEndpoint() map[string]interface{} {
 	return map[string]interface{}{
 		"get": map[string]interface{}{
-			"summary":     "Get transaction",
-			"description": "Get transaction details by ID",
-			"operationId": "getTransaction",
+			"summary":     "Get exchange rate",
+			"description": "Get current exchange rate between two currencies. Rates are locked at transaction initiation time.",
+			"operationId": "getExchangeRate",
 			"tags":        []string{"Payments"},
-			"security": []map[string]interface{}{
-				{"X-User-ID": []string{}},
-			},
+			"security":    getSecurityRequirements(),
 			"parameters": []map[string]interface{}{
 				{
-					"name":        "id",
-					"in":          "path",
+					"name":        "from",
+					"in":          "query",
 					"required":    true,
-					"description": "Transaction ID",
+					"description": "Source currency",
 					"schema": map[string]interface{}{
 						"type":    "string",
-						"example": "tx-id-123",
+						"enum":    []string{"USD", "EUR", "GBP"},
+						"example": "USD",
+					},
+				},
+				{
+					"name":        "to",
+					"in":          "query",
+					"required":    true,
+					"description": "Destination currency",
+					"schema": map[string]interface{}{
+						"type":    "string",
+						"enum":    []string{"USD", "EUR", "GBP"},
+						"example": "EUR",
 					},
 				},
 			},
 			"responses": map[string]interface{}{
 				"200": map[string]interface{}{
-					"description": "Transaction retrieved successfully",
+					"description": "Exchange rate retrieved successfully",
 					"content": map[string]interface{}{
 						"application/json": map[string]interface{}{
 							"schema": map[string]interface{}{
@@ -613,69 +858,83 @@ func getGetTransactionEndpoint() map[string]interface{} {
 							},
 							"example": map[string]interface{}{
 								"data": map[string]interface{}{
-									"id":              "tx-id",
-									"idempotency_key": "key-123",
-									"from_wallet_id":  "wallet_user1_usd",
-									"to_wallet_id":    "wallet_user2_eur",
-									"type":            "internal",
-									"amount":          100.50,
-									"currency":        "EUR",
-									"status":          "completed",
-									"exchange_rate":   0.85,
-									"created_at":      "2026-01-11T00:00:00Z",
-									"updated_at":      "2026-01-11T00:00:00Z",
+									"from_currency": "USD",
+									"to_currency":   "EUR",
+									"rate":          0.85,
 								},
-								"message": "transaction retrieved successfully",
+								"message": "exchange rate retrieved successfully",
 							},
 						},
 					},
 				},
-				"401": getErrorResponse("Unauthorized - missing or invalid X-User-ID"),
-				"404": getErrorResponse("Not found - transaction not found"),
+				"400": getErrorResponse("Bad request - invalid currency"),
 				"500": getErrorResponse("Internal server error"),
 			},
 		},
 	}
 }
 
-func getTransactionHistoryEndpoint() map[string]interface{} {
+func getWalletsEndpoint() map[string]interface{} {
 	return map[string]interface{}{
 		"get": map[string]interface{}{
-			"summary":     "Get transaction history",
-			"description": "Get paginated transaction history using cursor-based pagination. Returns transactions in descending order (newest first).",
-			"operationId": "getTransactionHistory",
-			"tags":        []string{"Payments"},
-			"security": []map[string]interface{}{
-				{"X-User-ID": []string{}},
-			},
-			"parameters": []map[string]interface{}{
-				{
-					"name":        "cursor",
-					"in":          "query",
-					"required":    false,
-					"description": "Base64-encoded cursor from previous response for pagination",
-					"schema": map[string]interface{}{
-						"type":    "string",
-						"example": "base64-encoded-cursor",
+			"summary":     "Get user wallets",
+			"description": "Get all wallets for the authenticated user with bank account details and cached balances (optimized, not ledger sum).",
+			"operationId": "getUserWallets",
+			"tags":        []string{"Wallets"},
+			"security":    getSecurityRequirements(),
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Wallets retrieved successfully",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"example": map[string]interface{}{
+								"data": []map[string]interface{}{
+									{
+										"id":                "wallet_user1_usd",
+										"currency":          "USD",
+										"balance":           100.50,
+										"available_balance": 75.50,
+										"status":            "active",
+										"account_number":    "1000000001",
+										"bank_name":         "Test Bank",
+										"bank_code":         "044",
+										"account_name":      "John Doe",
+										"provider":          "currencycloud",
+										"created_at":        "2026-01-11T00:00:00Z",
+										"updated_at":        "2026-01-11T00:00:00Z",
+									},
+								},
+								"message": "wallets retrieved successfully",
+							},
+						},
 					},
 				},
-				{
-					"name":        "limit",
-					"in":          "query",
-					"required":    false,
-					"description": "Number of transactions per page (default: 20, max: 100)",
-					"schema": map[string]interface{}{
-						"type":    "integer",
-						"minimum": 1,
-						"maximum": 100,
-						"default": 20,
-						"example": 20,
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+		"post": map[string]interface{}{
+			"summary":     "Open wallet",
+			"description": "Open a wallet in a supported currency for the authenticated user. The wallet gets a new virtual bank account in the user's name, numbered from the configured BIN. A user has one open wallet per currency, in the currencies their KYC tier allows.",
+			"operationId": "openWallet",
+			"tags":        []string{"Wallets"},
+			"security":    getSecurityRequirements(),
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"$ref": "#/components/schemas/OpenWalletRequest",
+						},
 					},
 				},
 			},
 			"responses": map[string]interface{}{
-				"200": map[string]interface{}{
-					"description": "Transaction history retrieved successfully",
+				"201": map[string]interface{}{
+					"description": "Wallet opened",
 					"content": map[string]interface{}{
 						"application/json": map[string]interface{}{
 							"schema": map[string]interface{}{
@@ -683,68 +942,120 @@ func getTransactionHistoryEndpoint() map[string]interface{} {
 							},
 							"example": map[string]interface{}{
 								"data": map[string]interface{}{
-									"transactions": []map[string]interface{}{
-										{
-											"id":         "tx-id-1",
-											"type":       "internal",
-											"amount":     100.50,
-											"currency":   "EUR",
-											"status":     "completed",
-											"created_at": "2026-01-11T00:00:00Z",
-										},
-										{
-											"id":         "tx-id-2",
-											"type":       "external",
-											"amount":     50.00,
-											"currency":   "GBP",
-											"status":     "completed",
-											"created_at": "2026-01-10T00:00:00Z",
-										},
-									},
-									"next_cursor": "base64-encoded-cursor",
+									"id":                "wallet-id",
+									"currency":          "USD",
+									"balance":           0,
+									"available_balance": 0,
+									"status":            "active",
+									"account_number":    "3000000012",
+									"bank_name":         "Test Bank",
+									"bank_code":         "044",
+									"account_name":      "John Doe",
+									"provider":          "currencycloud",
+									"created_at":        "2026-01-11T00:00:00Z",
+									"updated_at":        "2026-01-11T00:00:00Z",
 								},
-								"message": "transaction history retrieved successfully",
+								"message": "wallet opened successfully",
 							},
 						},
 					},
 				},
-				"400": getErrorResponse("Bad request - invalid limit parameter"),
-				"401": getErrorResponse("Unauthorized - missing or invalid X-User-ID"),
+				"400": getErrorResponse("Bad request - validation error or currency not available"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"403": getKYCRequiredResponse(),
+				"404": getErrorResponse("Not found - user not found"),
+				"409": getErrorResponse("Conflict - the user already has an open wallet in this currency"),
 				"500": getErrorResponse("Internal server error"),
 			},
 		},
 	}
 }
 
-func getWebhookEndpoint() map[string]interface{} {
+func getFreezeWalletEndpoint() map[string]interface{} {
+	return getWalletStatusEndpoint(
+		"Freeze wallet (admin)",
+		"Block debits, credits or both on a wallet. Transfers, reversals and refunds that would move money in a blocked direction are rejected with 423 WALLET_LOCKED. Freezing a frozen wallet replaces its direction and reason. Restricted to admin users.",
+		"freezeWallet",
+		"FreezeWalletRequest",
+		"wallet frozen successfully",
+		"Bad request - validation error or wallet is closed",
+		map[string]interface{}{
+			"id":                "wallet_user1_usd",
+			"user_id":           "user_1",
+			"currency":          "USD",
+			"balance":           100.50,
+			"available_balance": 75.50,
+			"status":            "frozen",
+			"frozen_direction":  "debits",
+			"status_reason":     "Suspected account takeover",
+			"created_at":        "2026-01-11T00:00:00Z",
+			"updated_at":        "2026-01-11T00:00:00Z",
+		},
+	)
+}
+
+func getUnfreezeWalletEndpoint() map[string]interface{} {
+	return getWalletStatusEndpoint(
+		"Unfreeze wallet (admin)",
+		"Return a frozen wallet to active. Restricted to admin users.",
+		"unfreezeWallet",
+		"WalletStatusRequest",
+		"wallet unfrozen successfully",
+		"Bad request - wallet is not frozen",
+		map[string]interface{}{
+			"id":                "wallet_user1_usd",
+			"user_id":           "user_1",
+			"currency":          "USD",
+			"balance":           100.50,
+			"available_balance": 75.50,
+			"status":            "active",
+			"status_reason":     "Customer identity confirmed",
+			"created_at":        "2026-01-11T00:00:00Z",
+			"updated_at":        "2026-01-11T00:00:00Z",
+		},
+	)
+}
+
+func getCloseWalletEndpoint() map[string]interface{} {
+	return getWalletStatusEndpoint(
+		"Close wallet (admin)",
+		"Close a wallet whose balance and held balance are both zero. A closed wallet accepts no transfers and cannot be reopened; the user can open a new wallet in the same currency. Restricted to admin users.",
+		"closeWallet",
+		"WalletStatusRequest",
+		"wallet closed successfully",
+		"Bad request - wallet is already closed or its balance is not zero",
+		map[string]interface{}{
+			"id":                "wallet_user1_usd",
+			"user_id":           "user_1",
+			"currency":          "USD",
+			"balance":           0,
+			"available_balance": 0,
+			"status":            "closed",
+			"status_reason":     "Customer request",
+			"closed_at":         "2026-01-11T00:00:00Z",
+			"created_at":        "2026-01-11T00:00:00Z",
+			"updated_at":        "2026-01-11T00:00:00Z",
+		},
+	)
+}
+
+func getWalletStatusEndpoint(summary, description, operationID, requestSchema, message, badRequest string, example map[string]interface{}) map[string]interface{} {
 	return map[string]interface{}{
 		"post": map[string]interface{}{
-			"summary":     "Receive webhook",
-			"description": "Receive webhook events from payment providers. Events are queued for asynchronous processing.",
-			"operationId": "receiveWebhook",
-			"tags":        []string{"Webhooks"},
-			"security": []map[string]interface{}{
-				{"X-User-ID": []string{}},
-			},
+			"summary":     summary,
+			"description": description,
+			"operationId": operationID,
+			"tags":        []string{"Admin"},
+			"security":    getSecurityRequirements(),
 			"parameters": []map[string]interface{}{
 				{
-					"name":        "provider",
+					"name":        "id",
 					"in":          "path",
 					"required":    true,
-					"description": "Provider name (e.g., currencycloud, dlocal)",
-					"schema": map[string]interface{}{
-						"type":    "string",
-						"example": "currencycloud",
-					},
-				},
-				{
-					"name":        "reference",
-					"in":          "query",
-					"required":    false,
-					"description": "Provider reference ID (optional, can be in body)",
+					"description": "Wallet ID",
 					"schema": map[string]interface{}{
 						"type":    "string",
-						"example": "TXN-12345",
+						"example": "wallet_user1_usd",
 					},
 				},
 			},
@@ -753,69 +1064,1856 @@ func getWebhookEndpoint() map[string]interface{} {
 				"content": map[string]interface{}{
 					"application/json": map[string]interface{}{
 						"schema": map[string]interface{}{
-							"$ref": "#/components/schemas/WebhookRequest",
-						},
-						"example": map[string]interface{}{
-							"event_type":     "payout.completed",
-							"reference":      "TXN-12345",
-							"transaction_id": "tx-id",
-							"status":         "completed",
+							"$ref": "#/components/schemas/" + requestSchema,
 						},
 					},
 				},
 			},
 			"responses": map[string]interface{}{
 				"200": map[string]interface{}{
-					"description": "Webhook received successfully",
+					"description": "Wallet status updated",
 					"content": map[string]interface{}{
 						"application/json": map[string]interface{}{
 							"schema": map[string]interface{}{
 								"$ref": "#/components/schemas/SuccessResponse",
 							},
 							"example": map[string]interface{}{
-								"data": map[string]interface{}{
-									"status": "received",
-								},
-								"message": "webhook received successfully",
+								"data":    example,
+								"message": message,
 							},
 						},
 					},
 				},
-				"400": getErrorResponse("Bad request - invalid webhook payload or missing reference"),
-				"401": getErrorResponse("Unauthorized - missing or invalid X-User-ID"),
+				"400": getErrorResponse(badRequest),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"403": getErrorResponse("Forbidden - admin access required"),
+				"404": getErrorResponse("Not found - wallet not found"),
 				"500": getErrorResponse("Internal server error"),
 			},
 		},
 	}
 }
 
-func getErrorResponse(description string) map[string]interface{} {
+func getUserProfileEndpoint() map[string]interface{} {
 	return map[string]interface{}{
-		"description": description,
-		"content": map[string]interface{}{
-			"application/json": map[string]interface{}{
-				"schema": map[string]interface{}{
-					"$ref": "#/components/schemas/ErrorResponse",
+		"post": map[string]interface{}{
+			"summary":     "Register user",
+			"description": "Create the profile for the authenticated user ID. New users start at KYC tier 0, which allows no currencies until an identity document is approved.",
+			"operationId": "registerUser",
+			"tags":        []string{"Users"},
+			"security":    getSecurityRequirements(),
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"$ref": "#/components/schemas/RegisterUserRequest",
+						},
+					},
 				},
-				"example": map[string]interface{}{
-					"error":   "error_code",
-					"message": description,
+			},
+			"responses": map[string]interface{}{
+				"201": getUserProfileResponse("User registered", "user registered successfully"),
+				"400": getErrorResponse("Bad request - validation error or date of birth not in the past"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"409": getErrorResponse("Conflict - user already registered, or email already registered"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+		"get": map[string]interface{}{
+			"summary":     "Get profile",
+			"description": "Get the authenticated user's profile, KYC tier, the currencies the tier allows and the KYC documents submitted, newest first.",
+			"operationId": "getProfile",
+			"tags":        []string{"Users"},
+			"security":    getSecurityRequirements(),
+			"responses": map[string]interface{}{
+				"200": getUserProfileResponse("Profile retrieved", "profile retrieved successfully"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"404": getErrorResponse("Not found - user not registered"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+		"patch": map[string]interface{}{
+			"summary":     "Update profile",
+			"description": "Change the fields present in the body. Name, date of birth and country cannot change once the user reaches KYC tier 1; email and phone can always change.",
+			"operationId": "updateProfile",
+			"tags":        []string{"Users"},
+			"security":    getSecurityRequirements(),
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"$ref": "#/components/schemas/UpdateUserProfileRequest",
+						},
+					},
 				},
 			},
+			"responses": map[string]interface{}{
+				"200": getUserProfileResponse("Profile updated", "profile updated successfully"),
+				"400": getErrorResponse("Bad request - validation error, no fields to update, or a verified field changed"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"404": getErrorResponse("Not found - user not registered"),
+				"409": getErrorResponse("Conflict - email already registered"),
+				"500": getErrorResponse("Internal server error"),
+			},
 		},
 	}
 }
 
-func getSchemas() map[string]interface{} {
+func getSubmitKYCDocumentEndpoint() map[string]interface{} {
 	return map[string]interface{}{
-		"AmountRequest": map[string]interface{}{
-			"type":     "object",
-			"required": []string{"amount", "currency"},
-			"properties": map[string]interface{}{
-				"amount": map[string]interface{}{
-					"type":        "number",
-					"format":      "float",
-					"minimum":     0,
+		"post": map[string]interface{}{
+			"summary":     "Submit KYC document",
+			"description": "Submit an identity or proof-of-address document for review. The category follows from the document type. A category that is already verified, or that has a document pending review, cannot be submitted again.",
+			"operationId": "submitKYCDocument",
+			"tags":        []string{"Users"},
+			"security":    getSecurityRequirements(),
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"$ref": "#/components/schemas/SubmitKYCDocumentRequest",
+						},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"201": getKYCDocumentResponse("Document submitted", "document submitted for review"),
+				"400": getErrorResponse("Bad request - validation error or category already verified"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"404": getErrorResponse("Not found - user not registered"),
+				"409": getErrorResponse("Conflict - a document in this category is already pending review"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getKYCDocumentsEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"get": map[string]interface{}{
+			"summary":     "List KYC documents (admin)",
+			"description": "List KYC documents in a status, oldest first. Defaults to the pending review queue. Restricted to admin users.",
+			"operationId": "listKYCDocuments",
+			"tags":        []string{"Admin"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				{
+					"name":        "status",
+					"in":          "query",
+					"required":    false,
+					"description": "Document status to list (default: pending)",
+					"schema": map[string]interface{}{
+						"type":    "string",
+						"enum":    []string{"pending", "approved", "rejected"},
+						"default": "pending",
+					},
+				},
+				{
+					"name":        "limit",
+					"in":          "query",
+					"required":    false,
+					"description": "Number of documents to return (default: 50)",
+					"schema": map[string]interface{}{
+						"type":    "integer",
+						"minimum": 1,
+						"default": 50,
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "KYC documents retrieved",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"type": "object",
+								"properties": map[string]interface{}{
+									"data": map[string]interface{}{
+										"type": "array",
+										"items": map[string]interface{}{
+											"$ref": "#/components/schemas/KYCDocumentResponse",
+										},
+									},
+									"message": map[string]interface{}{
+										"type":    "string",
+										"example": "kyc documents retrieved successfully",
+									},
+								},
+							},
+						},
+					},
+				},
+				"400": getErrorResponse("Bad request - invalid status or limit parameter"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"403": getErrorResponse("Forbidden - admin access required"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getReviewKYCDocumentEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     "Review KYC document (admin)",
+			"description": "Approve or reject a pending KYC document. An approved identity document raises the user to KYC tier 1, and identity with proof of address to tier 2, moving them to the transfer limit tier configured for it. Tiers are never lowered. Restricted to admin users.",
+			"operationId": "reviewKYCDocument",
+			"tags":        []string{"Admin"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				{
+					"name":        "id",
+					"in":          "path",
+					"required":    true,
+					"description": "ID of the pending document",
+					"schema": map[string]interface{}{
+						"type": "string",
+					},
+				},
+			},
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"$ref": "#/components/schemas/ReviewKYCDocumentRequest",
+						},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": getKYCDocumentResponse("Review recorded", "kyc review recorded successfully"),
+				"400": getErrorResponse("Bad request - validation error or document already reviewed"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"403": getErrorResponse("Forbidden - admin access required"),
+				"404": getErrorResponse("Not found - document not found"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getUserProfileResponse(description string, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"data": map[string]interface{}{
+							"$ref": "#/components/schemas/UserProfileResponse",
+						},
+						"message": map[string]interface{}{
+							"type":    "string",
+							"example": message,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getKYCDocumentResponse(description string, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"data": map[string]interface{}{
+							"$ref": "#/components/schemas/KYCDocumentResponse",
+						},
+						"message": map[string]interface{}{
+							"type":    "string",
+							"example": message,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getLimitsEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"get": map[string]interface{}{
+			"summary":     "Get transfer limits",
+			"description": "Get the authenticated user's transfer limits for their tier, with usage in the current UTC day and month. Amounts are in the source (debited) currency and exclude fees. A null limit is not enforced. Transfers between the user's own wallets are not limited.",
+			"operationId": "getLimits",
+			"tags":        []string{"Limits"},
+			"security":    getSecurityRequirements(),
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Limits retrieved successfully",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"example": map[string]interface{}{
+								"data": map[string]interface{}{
+									"user_id": "user_1",
+									"tier":    "standard",
+									"limits": []map[string]interface{}{
+										{
+											"currency":            "USD",
+											"transaction_type":    "external",
+											"per_transaction_max": 5000.00,
+											"daily": map[string]interface{}{
+												"amount_limit":     10000.00,
+												"amount_used":      1000.00,
+												"amount_remaining": 9000.00,
+												"count_limit":      10,
+												"count_used":       1,
+												"count_remaining":  9,
+												"resets_at":        "2026-01-12T00:00:00Z",
+											},
+											"monthly": map[string]interface{}{
+												"amount_limit":     50000.00,
+												"amount_used":      1000.00,
+												"amount_remaining": 49000.00,
+												"count_limit":      nil,
+												"count_used":       1,
+												"count_remaining":  nil,
+												"resets_at":        "2026-02-01T00:00:00Z",
+											},
+										},
+									},
+								},
+								"message": "limits retrieved successfully",
+							},
+						},
+					},
+				},
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getCreateInternalTransferEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     "Create internal transfer",
+			"description": "Initiate an internal transfer between users. Uses account number and bank code to identify recipient. Same user transfers (different currency) process immediately. Different user transfers require PIN confirmation.",
+			"operationId": "createInternalTransfer",
+			"tags":        []string{"Payments"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				{
+					"name":        "Idempotency-Key",
+					"in":          "header",
+					"required":    true,
+					"description": "Unique key for idempotency",
+					"schema": map[string]interface{}{
+						"type":    "string",
+						"example": "unique-key-123",
+					},
+				},
+			},
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"$ref": "#/components/schemas/CreateInternalTransferRequest",
+						},
+						"example": map[string]interface{}{
+							"from_currency":     "USD",
+							"to_account_number": "2000000001",
+							"to_bank_code":      "044",
+							"amount": map[string]interface{}{
+								"amount":   100.50,
+								"currency": "EUR",
+							},
+						},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"201": map[string]interface{}{
+					"description": "Transfer initiated or completed",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"examples": map[string]interface{}{
+								"completed": map[string]interface{}{
+									"summary": "Immediate completion (same user)",
+									"value": map[string]interface{}{
+										"data": map[string]interface{}{
+											"id":            "tx-id",
+											"status":        "completed",
+											"amount":        100.50,
+											"currency":      "EUR",
+											"exchange_rate": 0.85,
+										},
+										"message": "transfer completed successfully",
+									},
+								},
+								"initiated": map[string]interface{}{
+									"summary": "Requires confirmation (different user)",
+									"value": map[string]interface{}{
+										"data": map[string]interface{}{
+											"id":            "tx-id",
+											"status":        "initiated",
+											"amount":        100.50,
+											"currency":      "EUR",
+											"exchange_rate": 0.85,
+											"fee_amount":    0,
+											"fee_currency":  "USD",
+										},
+										"message": "transfer initiated, please confirm with PIN",
+									},
+								},
+							},
+						},
+					},
+				},
+				"400": getErrorResponse("Bad request - validation error, insufficient funds, unknown bank code, currency the bank does not accept, or invalid parameters"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"404": getErrorResponse("Not found - sender wallet, recipient account or beneficiary not found"),
+				"409": getErrorResponse("Conflict - duplicate idempotency key"),
+				"422": getCreateTransferUnprocessableResponse(),
+				"403": getKYCRequiredResponse(),
+				"423": getWalletLockedResponse(),
+				"429": getRateLimitedResponse(),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getCreateExternalTransferEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     "Create external transfer",
+			"description": "Initiate an external transfer to a bank account outside the system. Always requires PIN confirmation. Exchange rate is locked at initiation time.",
+			"operationId": "createExternalTransfer",
+			"tags":        []string{"Payments"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				{
+					"name":        "Idempotency-Key",
+					"in":          "header",
+					"required":    true,
+					"description": "Unique key for idempotency",
+					"schema": map[string]interface{}{
+						"type":    "string",
+						"example": "unique-key-123",
+					},
+				},
+			},
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"$ref": "#/components/schemas/CreateExternalTransferRequest",
+						},
+						"example": map[string]interface{}{
+							"from_currency":     "USD",
+							"to_account_number": "40478470872490",
+							"to_bank_code":      "044",
+							"amount": map[string]interface{}{
+								"amount":   50.00,
+								"currency": "GBP",
+							},
+						},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"201": map[string]interface{}{
+					"description": "External transfer initiated",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"example": map[string]interface{}{
+								"data": map[string]interface{}{
+									"id":            "tx-id",
+									"status":        "initiated",
+									"amount":        50.00,
+									"currency":      "GBP",
+									"exchange_rate": 0.75,
+									"fee_amount":    1.00,
+									"fee_currency":  "USD",
+								},
+								"message": "external transfer initiated, please confirm with PIN",
+							},
+						},
+					},
+				},
+				"400": getErrorResponse("Bad request - validation error, insufficient funds, unknown bank code, currency the bank does not accept, or invalid parameters"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"404": getErrorResponse("Not found - sender wallet or beneficiary not found"),
+				"409": getErrorResponse("Conflict - duplicate idempotency key"),
+				"422": getCreateTransferUnprocessableResponse(),
+				"403": getKYCRequiredResponse(),
+				"423": getWalletLockedResponse(),
+				"429": getRateLimitedResponse(),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getConfirmTransactionEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     "Confirm transaction",
+			"description": "Confirm an initiated transaction with PIN. Transaction must be in 'initiated' status and not expired (10 minutes from creation). Amounts at or above the STEP_UP_THRESHOLDS entry for the transaction currency also need a TOTP or recovery code in totp_code; with STEP_UP_MODE=replace the code is accepted instead of the PIN. The transfer is then risk screened: flagged transfers move to 'on_hold' until an admin reviews them, and denied transfers fail. Internal transfers complete immediately. External transfers are queued for asynchronous processing.",
+			"operationId": "confirmTransaction",
+			"tags":        []string{"Payments"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				{
+					"name":        "id",
+					"in":          "path",
+					"required":    true,
+					"description": "Transaction ID",
+					"schema": map[string]interface{}{
+						"type":    "string",
+						"example": "tx-id-123",
+					},
+				},
+			},
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"$ref": "#/components/schemas/ConfirmTransactionRequest",
+						},
+						"example": map[string]interface{}{
+							"pin": "12345",
+						},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Transaction confirmed",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"examples": map[string]interface{}{
+								"internal": map[string]interface{}{
+									"summary": "Internal transfer completed",
+									"value": map[string]interface{}{
+										"data": map[string]interface{}{
+											"id":       "tx-id",
+											"status":   "completed",
+											"amount":   100.50,
+											"currency": "EUR",
+										},
+										"message": "transaction confirmed and completed successfully",
+									},
+								},
+								"external": map[string]interface{}{
+									"summary": "External transfer queued",
+									"value": map[string]interface{}{
+										"data": map[string]interface{}{
+											"id":       "tx-id",
+											"status":   "pending",
+											"amount":   50.00,
+											"currency": "GBP",
+											"recipient": map[string]interface{}{
+												"account_number": "40478470872490",
+												"bank_code":      "044",
+												"account_name":   "Mock Account Holder 2490",
+												"country":        "US",
+												"currency":       "GBP",
+											},
+										},
+										"message": "transaction confirmed and queued for processing",
+									},
+								},
+								"on_hold": map[string]interface{}{
+									"summary": "Transfer held for risk review",
+									"value": map[string]interface{}{
+										"data": map[string]interface{}{
+											"id":       "tx-id",
+											"status":   "on_hold",
+											"amount":   1500.00,
+											"currency": "EUR",
+										},
+										"message": "transaction held for review",
+									},
+								},
+								"sanctions_hold": map[string]interface{}{
+									"summary": "External transfer held after a sanctions match",
+									"value": map[string]interface{}{
+										"data": map[string]interface{}{
+											"id":                     "tx-id",
+											"status":                 "on_hold",
+											"amount":                 50.00,
+											"currency":               "GBP",
+											"screening_result":       "match",
+											"screening_list_version": "2025-01-15",
+										},
+										"message": "transaction held for review",
+									},
+								},
+							},
+						},
+					},
+				},
+				"400": getErrorResponse("Bad request - invalid PIN or TOTP code, expired transaction, or insufficient funds"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"403": getConfirmForbiddenResponse(),
+				"404": getErrorResponse("Not found - transaction not found"),
+				"422": getConfirmUnprocessableResponse(),
+				"423": getConfirmLockedResponse(),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getCancelTransactionEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     "Cancel transaction",
+			"description": "Cancel an initiated transaction before it is confirmed. Only the sender can cancel, and no money is moved.",
+			"operationId": "cancelTransaction",
+			"tags":        []string{"Payments"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				{
+					"name":        "id",
+					"in":          "path",
+					"required":    true,
+					"description": "Transaction ID",
+					"schema": map[string]interface{}{
+						"type":    "string",
+						"example": "tx-id-123",
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Transaction cancelled",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"example": map[string]interface{}{
+								"data": map[string]interface{}{
+									"id":       "tx-id",
+									"status":   "cancelled",
+									"amount":   100.50,
+									"currency": "EUR",
+								},
+								"message": "transaction cancelled successfully",
+							},
+						},
+					},
+				},
+				"400": getErrorResponse("Bad request - transaction is not initiated or does not belong to user"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"404": getErrorResponse("Not found - transaction not found"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getReverseTransactionEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     "Reverse transaction (admin)",
+			"description": "Reverse a completed internal transfer. Creates a linked reversal transaction that debits the recipient and credits the sender at the original exchange rate, and marks the original as 'reversed'. Fails if the recipient no longer holds enough funds. The original fee is not refunded. Restricted to admin users.",
+			"operationId": "reverseTransaction",
+			"tags":        []string{"Admin"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				{
+					"name":        "id",
+					"in":          "path",
+					"required":    true,
+					"description": "ID of the transaction to reverse",
+					"schema": map[string]interface{}{
+						"type":    "string",
+						"example": "tx-id-123",
+					},
+				},
+				{
+					"name":        "Idempotency-Key",
+					"in":          "header",
+					"required":    true,
+					"description": "Unique key to ensure idempotency",
+					"schema": map[string]interface{}{
+						"type":    "string",
+						"example": "reversal-key-123",
+					},
+				},
+			},
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"$ref": "#/components/schemas/ReverseTransactionRequest",
+						},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"201": map[string]interface{}{
+					"description": "Reversal transaction created",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"example": map[string]interface{}{
+								"data": map[string]interface{}{
+									"id":                    "reversal-tx-id",
+									"type":                  "reversal",
+									"status":                "completed",
+									"amount":                118.24,
+									"currency":              "USD",
+									"parent_transaction_id": "tx-id",
+								},
+								"message": "transaction reversed successfully",
+							},
+						},
+					},
+				},
+				"400": getErrorResponse("Bad request - transaction is not a completed internal transfer, or recipient has insufficient balance"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"403": getErrorResponse("Forbidden - admin access required"),
+				"404": getErrorResponse("Not found - transaction not found"),
+				"409": getErrorResponse("Conflict - idempotency key already used"),
+				"423": getWalletLockedResponse(),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getCreateRefundEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     "Refund transaction (admin)",
+			"description": "Refund part or all of a completed internal or external transfer. The amount is given in the original transaction currency and the sender is credited at the original exchange rate. Total refunds can never exceed the original amount. When the total reaches it the original moves to 'refunded'. Fees are not refunded. Restricted to admin users.",
+			"operationId": "createRefund",
+			"tags":        []string{"Admin"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				{
+					"name":        "id",
+					"in":          "path",
+					"required":    true,
+					"description": "ID of the transaction to refund",
+					"schema": map[string]interface{}{
+						"type":    "string",
+						"example": "tx-id-123",
+					},
+				},
+				{
+					"name":        "Idempotency-Key",
+					"in":          "header",
+					"required":    true,
+					"description": "Unique key to ensure idempotency",
+					"schema": map[string]interface{}{
+						"type":    "string",
+						"example": "refund-key-123",
+					},
+				},
+			},
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"$ref": "#/components/schemas/CreateRefundRequest",
+						},
+						"example": map[string]interface{}{
+							"amount": map[string]interface{}{
+								"amount":   25.00,
+								"currency": "EUR",
+							},
+							"reason": "Disputed portion of the order",
+						},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"201": map[string]interface{}{
+					"description": "Refund created",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+						},
+					},
+				},
+				"400": getErrorResponse("Bad request - transaction not completed, currency mismatch, amount exceeds remaining refundable amount, or recipient has insufficient balance"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"403": getErrorResponse("Forbidden - admin access required"),
+				"404": getErrorResponse("Not found - transaction not found"),
+				"409": getErrorResponse("Conflict - idempotency key already used"),
+				"423": getWalletLockedResponse(),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getPINSuccessResponse(description string, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"$ref": "#/components/schemas/SuccessResponse",
+				},
+				"example": map[string]interface{}{
+					"data": map[string]interface{}{
+						"user_id": "user_1",
+					},
+					"message": message,
+				},
+			},
+		},
+	}
+}
+
+func getPINEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     "Set PIN",
+			"description": "Set the first transaction PIN for the authenticated user. Fails if a PIN is already set. Trivially weak PINs such as 12345 or 11111 are rejected.",
+			"operationId": "setPIN",
+			"tags":        []string{"Users"},
+			"security":    getSecurityRequirements(),
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"$ref": "#/components/schemas/SetPINRequest",
+						},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"201": getPINSuccessResponse("PIN set", "PIN set successfully"),
+				"400": getErrorResponse("Bad request - invalid or weak PIN, or PIN already set"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"404": getErrorResponse("Not found - user not found"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+		"put": map[string]interface{}{
+			"summary":     "Change PIN",
+			"description": "Change the authenticated user's PIN. The current PIN is required and wrong attempts count towards the PIN lockout. The new PIN must not be weak or match one of the user's recent PINs.",
+			"operationId": "changePIN",
+			"tags":        []string{"Users"},
+			"security":    getSecurityRequirements(),
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"$ref": "#/components/schemas/ChangePINRequest",
+						},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": getPINSuccessResponse("PIN changed", "PIN changed successfully"),
+				"400": getErrorResponse("Bad request - wrong current PIN, weak PIN, or recently used PIN"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"423": getPINLockedResponse(),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getResetPINEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     "Reset PIN with token",
+			"description": "Set a new PIN using a single-use reset token issued by an admin. The token must have been issued to the authenticated user and not be expired. A successful reset also clears any PIN lockout.",
+			"operationId": "resetPIN",
+			"tags":        []string{"Users"},
+			"security":    getSecurityRequirements(),
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"$ref": "#/components/schemas/ResetPINRequest",
+						},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": getPINSuccessResponse("PIN reset", "PIN reset successfully"),
+				"400": getErrorResponse("Bad request - invalid or expired token, weak PIN, or recently used PIN"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getCreatePINResetTokenEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     "Issue PIN reset token (admin)",
+			"description": "Issue a single-use token the user can exchange for a new PIN. Any earlier unused token for the user is revoked. The token is only returned once. Restricted to admin users.",
+			"operationId": "createPINResetToken",
+			"tags":        []string{"Admin"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				{
+					"name":        "id",
+					"in":          "path",
+					"required":    true,
+					"description": "ID of the user whose PIN to reset",
+					"schema": map[string]interface{}{
+						"type":    "string",
+						"example": "user_1",
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"201": map[string]interface{}{
+					"description": "Reset token issued",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"example": map[string]interface{}{
+								"data": map[string]interface{}{
+									"user_id":    "user_1",
+									"token":      "q3Zr8bT0x0mK1cWl8c0Jd2p3J1mPpQ6n2nWc7kUuY5E",
+									"expires_at": "2026-01-11T00:15:00Z",
+								},
+								"message": "PIN reset token created successfully",
+							},
+						},
+					},
+				},
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"403": getErrorResponse("Forbidden - admin access required"),
+				"404": getErrorResponse("Not found - user not found"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getPendingReviewsEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"get": map[string]interface{}{
+			"summary":     "List pending risk reviews (admin)",
+			"description": "List transactions held by risk screening that are waiting for an analyst decision, oldest first. Restricted to admin users.",
+			"operationId": "listPendingReviews",
+			"tags":        []string{"Admin"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				{
+					"name":        "limit",
+					"in":          "query",
+					"required":    false,
+					"description": "Number of reviews to return (default: 20, max: 100)",
+					"schema": map[string]interface{}{
+						"type":    "integer",
+						"minimum": 1,
+						"maximum": 100,
+						"default": 20,
+						"example": 20,
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Pending reviews retrieved",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"example": map[string]interface{}{
+								"data": []map[string]interface{}{
+									{
+										"id":             "assessment-id",
+										"transaction_id": "tx-id",
+										"user_id":        "user_1",
+										"recipient":      "wallet:wallet_user2_eur",
+										"decision":       "review",
+										"score":          50,
+										"reasons": []map[string]interface{}{
+											{
+												"rule":   "new_beneficiary_large_amount",
+												"score":  50,
+												"detail": "large first transfer to this recipient",
+											},
+										},
+										"features": map[string]interface{}{
+											"source_amount":       150000,
+											"source_currency":     "USD",
+											"recipient_transfers": 0,
+										},
+										"created_at": "2026-01-11T00:00:00Z",
+									},
+								},
+								"message": "pending reviews retrieved successfully",
+							},
+						},
+					},
+				},
+				"400": getErrorResponse("Bad request - invalid limit parameter"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"403": getErrorResponse("Forbidden - admin access required"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getReviewTransactionEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     "Review held transaction (admin)",
+			"description": "Record an analyst decision on a transaction held by risk screening. Approving completes the confirmation the user already authorised; rejecting fails the transaction and releases the held funds. Restricted to admin users.",
+			"operationId": "reviewTransaction",
+			"tags":        []string{"Admin"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				{
+					"name":        "id",
+					"in":          "path",
+					"required":    true,
+					"description": "ID of the on_hold transaction",
+					"schema": map[string]interface{}{
+						"type":    "string",
+						"example": "tx-id-123",
+					},
+				},
+			},
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"$ref": "#/components/schemas/ReviewTransactionRequest",
+						},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Review recorded",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"example": map[string]interface{}{
+								"data": map[string]interface{}{
+									"id":       "tx-id",
+									"status":   "completed",
+									"amount":   1500.00,
+									"currency": "EUR",
+								},
+								"message": "transaction review recorded successfully",
+							},
+						},
+					},
+				},
+				"400": getErrorResponse("Bad request - transaction is not on hold, already reviewed, or insufficient funds"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"403": getErrorResponse("Forbidden - admin access required"),
+				"404": getErrorResponse("Not found - transaction not found"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getTOTPCodeRequestBody() map[string]interface{} {
+	return map[string]interface{}{
+		"required": true,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"$ref": "#/components/schemas/TOTPCodeRequest",
+				},
+			},
+		},
+	}
+}
+
+func getTOTPEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     "Start TOTP enrollment",
+			"description": "Generate an authenticator secret for the authenticated user. Add it to an authenticator app using the provisioning URI, then call the verify endpoint with a code to enable it. Calling this again before verification replaces the pending secret.",
+			"operationId": "enrollTOTP",
+			"tags":        []string{"Users"},
+			"security":    getSecurityRequirements(),
+			"responses": map[string]interface{}{
+				"201": map[string]interface{}{
+					"description": "Enrollment started",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"example": map[string]interface{}{
+								"data": map[string]interface{}{
+									"user_id":          "user_1",
+									"secret":           "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP",
+									"provisioning_uri": "otpauth://totp/Payment%20Processing%20Service:user_1?algorithm=SHA1&digits=6&issuer=Payment+Processing+Service&period=30&secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP",
+								},
+								"message": "scan the provisioning URI and verify a code to enable TOTP",
+							},
+						},
+					},
+				},
+				"400": getErrorResponse("Bad request - TOTP already enabled"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"404": getErrorResponse("Not found - user not found"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+		"delete": map[string]interface{}{
+			"summary":     "Disable TOTP",
+			"description": "Remove the authenticator and its recovery codes. Requires a current TOTP code or an unused recovery code; wrong codes count towards the PIN lockout.",
+			"operationId": "disableTOTP",
+			"tags":        []string{"Users"},
+			"security":    getSecurityRequirements(),
+			"requestBody": getTOTPCodeRequestBody(),
+			"responses": map[string]interface{}{
+				"200": getPINSuccessResponse("TOTP disabled", "TOTP disabled successfully"),
+				"400": getErrorResponse("Bad request - invalid TOTP code"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"403": getTOTPRequiredResponse(),
+				"423": getPINLockedResponse(),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getVerifyTOTPEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     "Verify TOTP enrollment",
+			"description": "Enable a pending authenticator by submitting a code it generated. Returns 10 single-use recovery codes. They are stored hashed and are only shown in this response.",
+			"operationId": "verifyTOTP",
+			"tags":        []string{"Users"},
+			"security":    getSecurityRequirements(),
+			"requestBody": getTOTPCodeRequestBody(),
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "TOTP enabled",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"example": map[string]interface{}{
+								"data": map[string]interface{}{
+									"user_id":        "user_1",
+									"recovery_codes": []string{"k3vq-7rmd-2xha-pe5n", "..."},
+								},
+								"message": "TOTP enabled successfully; store the recovery codes somewhere safe",
+							},
+						},
+					},
+				},
+				"400": getErrorResponse("Bad request - invalid TOTP code, no pending enrollment, or TOTP already enabled"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getUnlockPINEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     "Unlock PIN (admin)",
+			"description": "Clear a PIN lockout and the failed-attempt counters for a user. Restricted to admin users.",
+			"operationId": "unlockPIN",
+			"tags":        []string{"Admin"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				{
+					"name":        "id",
+					"in":          "path",
+					"required":    true,
+					"description": "ID of the user whose PIN to unlock",
+					"schema": map[string]interface{}{
+						"type":    "string",
+						"example": "user_1",
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "PIN unlocked",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"example": map[string]interface{}{
+								"data": map[string]interface{}{
+									"user_id": "user_1",
+								},
+								"message": "PIN unlocked successfully",
+							},
+						},
+					},
+				},
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"403": getErrorResponse("Forbidden - admin access required"),
+				"404": getErrorResponse("Not found - user not found"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getGetTransactionEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"get": map[string]interface{}{
+			"summary":     "Get transaction",
+			"description": "Get transaction details by ID, including any refunds issued against it. Only the sender, the recipient or an admin can read a transaction; anyone else gets 404.",
+			"operationId": "getTransaction",
+			"tags":        []string{"Payments"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				{
+					"name":        "id",
+					"in":          "path",
+					"required":    true,
+					"description": "Transaction ID",
+					"schema": map[string]interface{}{
+						"type":    "string",
+						"example": "tx-id-123",
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Transaction retrieved successfully",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"example": map[string]interface{}{
+								"data": map[string]interface{}{
+									"id":              "tx-id",
+									"idempotency_key": "key-123",
+									"from_wallet_id":  "wallet_user1_usd",
+									"to_wallet_id":    "wallet_user2_eur",
+									"type":            "internal",
+									"amount":          100.50,
+									"currency":        "EUR",
+									"status":          "completed",
+									"exchange_rate":   0.85,
+									"created_at":      "2026-01-11T00:00:00Z",
+									"updated_at":      "2026-01-11T00:00:00Z",
+								},
+								"message": "transaction retrieved successfully",
+							},
+						},
+					},
+				},
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"404": getErrorResponse("Not found - transaction not found or not visible to the caller"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getAuditEventsEndpoint() map[string]interface{} {
+	queryParam := func(name string, description string, example string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":        name,
+			"in":          "query",
+			"required":    false,
+			"description": description,
+			"schema": map[string]interface{}{
+				"type":    "string",
+				"example": example,
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"get": map[string]interface{}{
+			"summary":     "List audit events (admin)",
+			"description": "Query the append-only audit log, newest first. Every money movement, PIN or TOTP change, admin action and transaction status change is recorded in the same database transaction as the change itself. Filters can be combined. Restricted to admin users.",
+			"operationId": "listAuditEvents",
+			"tags":        []string{"Admin"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				queryParam("entity_type", "Entity type: transaction, wallet, user or refund", "transaction"),
+				queryParam("entity_id", "ID of the entity", "tx-id"),
+				queryParam("actor_id", "Who performed the action: a user ID, service name, or worker name", "user_1"),
+				queryParam("action", "Action name, e.g. transaction.status_changed or pin.changed", "transaction.status_changed"),
+				queryParam("from", "Only events at or after this time (RFC 3339)", "2026-01-01T00:00:00Z"),
+				queryParam("to", "Only events before this time (RFC 3339)", "2026-02-01T00:00:00Z"),
+				queryParam("cursor", "next_cursor from the previous page", "1042"),
+				{
+					"name":        "limit",
+					"in":          "query",
+					"required":    false,
+					"description": "Number of events to return (default: 20, max: 100)",
+					"schema": map[string]interface{}{
+						"type":    "integer",
+						"minimum": 1,
+						"maximum": 100,
+						"default": 20,
+						"example": 20,
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Audit events retrieved",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"example": map[string]interface{}{
+								"data": map[string]interface{}{
+									"events": []map[string]interface{}{
+										{
+											"sequence":    1043,
+											"id":          "event-id",
+											"actor_id":    "admin_1",
+											"actor_type":  "admin",
+											"action":      "transaction.status_changed",
+											"entity_type": "transaction",
+											"entity_id":   "tx-id",
+											"before":      map[string]interface{}{"status": "completed"},
+											"after":       map[string]interface{}{"status": "reversed", "reason": "duplicate payment"},
+											"ip_address":  "203.0.113.7",
+											"trace_id":    "trace-id",
+											"prev_hash":   "9f2c…",
+											"hash":        "41ab…",
+											"created_at":  "2026-01-11T00:00:00Z",
+										},
+									},
+									"next_cursor": "1043",
+								},
+								"message": "audit events retrieved successfully",
+							},
+						},
+					},
+				},
+				"400": getErrorResponse("Bad request - invalid limit, cursor, from or to parameter"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"403": getErrorResponse("Forbidden - admin access required"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getVerifyAuditChainEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"get": map[string]interface{}{
+			"summary":     "Verify audit chain (admin)",
+			"description": "Recompute the digest and hash of a range of audit events and check each links to the one before it. When the range reaches the newest event it is also checked against the chain head, which catches events removed from the end. Verify a long log in ranges by passing last_sequence + 1 as from_sequence. Restricted to admin users.",
+			"operationId": "verifyAuditChain",
+			"tags":        []string{"Admin"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				{
+					"name":        "from_sequence",
+					"in":          "query",
+					"required":    false,
+					"description": "First sequence to check (default: 1)",
+					"schema": map[string]interface{}{
+						"type":    "integer",
+						"minimum": 1,
+						"default": 1,
+						"example": 1,
+					},
+				},
+				{
+					"name":        "limit",
+					"in":          "query",
+					"required":    false,
+					"description": "Number of events to check (default: 1000, max: 10000)",
+					"schema": map[string]interface{}{
+						"type":    "integer",
+						"minimum": 1,
+						"maximum": 10000,
+						"default": 1000,
+						"example": 1000,
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Verification result. A broken chain is reported with valid=false, not as an error.",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"examples": map[string]interface{}{
+								"valid": map[string]interface{}{
+									"value": map[string]interface{}{
+										"data": map[string]interface{}{
+											"from_sequence": 1,
+											"checked":       1043,
+											"valid":         true,
+											"complete":      true,
+											"last_sequence": 1043,
+											"last_hash":     "41ab…",
+										},
+										"message": "audit chain verified",
+									},
+								},
+								"broken": map[string]interface{}{
+									"value": map[string]interface{}{
+										"data": map[string]interface{}{
+											"from_sequence": 1,
+											"checked":       511,
+											"valid":         false,
+											"complete":      false,
+											"broken_at":     512,
+											"problem":       "event content does not match its digest",
+											"last_sequence": 511,
+											"last_hash":     "77d0…",
+										},
+										"message": "audit chain verified",
+									},
+								},
+							},
+						},
+					},
+				},
+				"400": getErrorResponse("Bad request - invalid from_sequence or limit parameter"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"403": getErrorResponse("Forbidden - admin access required"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getTransactionHistoryEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"get": map[string]interface{}{
+			"summary":     "Get transaction history",
+			"description": "Get paginated transaction history using cursor-based pagination. Returns transactions in descending order (newest first).",
+			"operationId": "getTransactionHistory",
+			"tags":        []string{"Payments"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				{
+					"name":        "cursor",
+					"in":          "query",
+					"required":    false,
+					"description": "Base64-encoded cursor from previous response for pagination",
+					"schema": map[string]interface{}{
+						"type":    "string",
+						"example": "base64-encoded-cursor",
+					},
+				},
+				{
+					"name":        "limit",
+					"in":          "query",
+					"required":    false,
+					"description": "Number of transactions per page (default: 20, max: 100)",
+					"schema": map[string]interface{}{
+						"type":    "integer",
+						"minimum": 1,
+						"maximum": 100,
+						"default": 20,
+						"example": 20,
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Transaction history retrieved successfully",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"example": map[string]interface{}{
+								"data": map[string]interface{}{
+									"transactions": []map[string]interface{}{
+										{
+											"id":         "tx-id-1",
+											"type":       "internal",
+											"amount":     100.50,
+											"currency":   "EUR",
+											"status":     "completed",
+											"created_at": "2026-01-11T00:00:00Z",
+										},
+										{
+											"id":         "tx-id-2",
+											"type":       "external",
+											"amount":     50.00,
+											"currency":   "GBP",
+											"status":     "completed",
+											"created_at": "2026-01-10T00:00:00Z",
+										},
+									},
+									"next_cursor": "base64-encoded-cursor",
+								},
+								"message": "transaction history retrieved successfully",
+							},
+						},
+					},
+				},
+				"400": getErrorResponse("Bad request - invalid limit parameter"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getWebhookEndpoint() map[string]interface{} {
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     "Receive webhook",
+			"description": "Receive webhook events from payment providers. Events are queued for asynchronous processing. A credit.received event credits the wallet that owns the named virtual account, once per provider reference.",
+			"operationId": "receiveWebhook",
+			"tags":        []string{"Webhooks"},
+			"security":    getSecurityRequirements(),
+			"parameters": []map[string]interface{}{
+				{
+					"name":        "provider",
+					"in":          "path",
+					"required":    true,
+					"description": "Provider name (e.g., currencycloud, dlocal)",
+					"schema": map[string]interface{}{
+						"type":    "string",
+						"example": "currencycloud",
+					},
+				},
+				{
+					"name":        "reference",
+					"in":          "query",
+					"required":    false,
+					"description": "Provider reference ID (optional, can be in body)",
+					"schema": map[string]interface{}{
+						"type":    "string",
+						"example": "TXN-12345",
+					},
+				},
+			},
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"$ref": "#/components/schemas/WebhookRequest",
+						},
+						"example": map[string]interface{}{
+							"event_type":     "payout.completed",
+							"reference":      "TXN-12345",
+							"transaction_id": "tx-id",
+							"status":         "completed",
+						},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Webhook received successfully",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"$ref": "#/components/schemas/SuccessResponse",
+							},
+							"example": map[string]interface{}{
+								"data": map[string]interface{}{
+									"status": "received",
+								},
+								"message": "webhook received successfully",
+							},
+						},
+					},
+				},
+				"400": getErrorResponse("Bad request - invalid webhook payload or missing reference"),
+				"401": getErrorResponse("Unauthorized - missing or invalid credentials"),
+				"403": getErrorResponse("Forbidden - caller is not an API key with the webhooks:{provider} scope"),
+				"500": getErrorResponse("Internal server error"),
+			},
+		},
+	}
+}
+
+func getPINLockedResponse() map[string]interface{} {
+	return map[string]interface{}{
+		"description": "Locked - too many failed PIN attempts; the PIN is temporarily locked",
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"$ref": "#/components/schemas/ErrorResponse",
+				},
+				"example": map[string]interface{}{
+					"message": "PIN is locked until 2026-01-11T00:30:00Z",
+					"code":    "PIN_LOCKED",
+				},
+			},
+		},
+	}
+}
+
+func getWalletLockedResponse() map[string]interface{} {
+	return map[string]interface{}{
+		"description": "Locked - a wallet on either side is frozen in that direction or closed",
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"$ref": "#/components/schemas/ErrorResponse",
+				},
+				"example": map[string]interface{}{
+					"message": "sender wallet is frozen",
+					"code":    "WALLET_LOCKED",
+				},
+			},
+		},
+	}
+}
+
+func getConfirmLockedResponse() map[string]interface{} {
+	return map[string]interface{}{
+		"description": "Locked - the PIN is temporarily locked, or a wallet on either side has been frozen or closed since the transfer was created",
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"$ref": "#/components/schemas/ErrorResponse",
+				},
+				"examples": map[string]interface{}{
+					"pin_locked": map[string]interface{}{
+						"value": map[string]interface{}{
+							"message": "PIN is locked until 2026-01-11T00:30:00Z",
+							"code":    "PIN_LOCKED",
+						},
+					},
+					"wallet_locked": map[string]interface{}{
+						"value": map[string]interface{}{
+							"message": "recipient wallet is frozen",
+							"code":    "WALLET_LOCKED",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getKYCRequiredResponse() map[string]interface{} {
+	return map[string]interface{}{
+		"description": "Forbidden - the user's KYC tier does not allow this currency",
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"$ref": "#/components/schemas/ErrorResponse",
+				},
+				"example": map[string]interface{}{
+					"message": "EUR is not available at KYC tier 1",
+					"code":    "KYC_REQUIRED",
+				},
+			},
+		},
+	}
+}
+
+// getConfirmForbiddenResponse covers both 403 outcomes of confirming a
+// transfer, which share a status code but carry different error codes.
+func getConfirmForbiddenResponse() map[string]interface{} {
+	return map[string]interface{}{
+		"description": "Forbidden - this amount needs a TOTP code, the user has not enrolled an authenticator, or the user's KYC tier does not allow the currency",
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"$ref": "#/components/schemas/ErrorResponse",
+				},
+				"examples": map[string]interface{}{
+					"totp_required": map[string]interface{}{
+						"value": map[string]interface{}{
+							"message": "TOTP code required for transfers of this amount",
+							"code":    "TOTP_REQUIRED",
+						},
+					},
+					"kyc_required": map[string]interface{}{
+						"value": map[string]interface{}{
+							"message": "EUR is not available at KYC tier 1",
+							"code":    "KYC_REQUIRED",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getTOTPRequiredResponse() map[string]interface{} {
+	return map[string]interface{}{
+		"description": "Forbidden - this amount needs a TOTP code, or the user has not enrolled an authenticator",
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"$ref": "#/components/schemas/ErrorResponse",
+				},
+				"example": map[string]interface{}{
+					"message": "TOTP code required for transfers of this amount",
+					"code":    "TOTP_REQUIRED",
+				},
+			},
+		},
+	}
+}
+
+// getCreateTransferUnprocessableResponse covers both 422 outcomes of creating
+// a transfer, which share a status code but carry different error codes.
+func getCreateTransferUnprocessableResponse() map[string]interface{} {
+	return map[string]interface{}{
+		"description": "Unprocessable - the transfer exceeds a per-transaction, daily or monthly limit, or to_account_name does not match the recipient account",
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"$ref": "#/components/schemas/ErrorResponse",
+				},
+				"examples": map[string]interface{}{
+					"limit_exceeded": map[string]interface{}{
+						"value": map[string]interface{}{
+							"message": "amount exceeds the remaining daily limit of USD 250.00",
+							"code":    "LIMIT_EXCEEDED",
+						},
+					},
+					"name_mismatch": map[string]interface{}{
+						"value": map[string]interface{}{
+							"message": "account name does not match the recipient account",
+							"code":    "NAME_MISMATCH",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getConfirmUnprocessableResponse covers both 422 outcomes of a confirmation,
+// which share a status code but carry different error codes.
+func getConfirmUnprocessableResponse() map[string]interface{} {
+	return map[string]interface{}{
+		"description": "Unprocessable - the transfer exceeds a limit, or was declined by risk screening and has failed",
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"$ref": "#/components/schemas/ErrorResponse",
+				},
+				"examples": map[string]interface{}{
+					"limit_exceeded": map[string]interface{}{
+						"value": map[string]interface{}{
+							"message": "amount exceeds the remaining daily limit of USD 250.00",
+							"code":    "LIMIT_EXCEEDED",
+						},
+					},
+					"declined": map[string]interface{}{
+						"value": map[string]interface{}{
+							"message": "transaction declined",
+							"code":    "TRANSACTION_DECLINED",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getRateLimitedResponse() map[string]interface{} {
+	integerHeader := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"description": description,
+			"schema": map[string]interface{}{
+				"type": "integer",
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"description": "Too many requests - rate limit exceeded",
+		"headers": map[string]interface{}{
+			"Retry-After":         integerHeader("Seconds until the next request will be accepted"),
+			"RateLimit-Limit":     integerHeader("Requests allowed per period"),
+			"RateLimit-Remaining": integerHeader("Requests left in the current window"),
+			"RateLimit-Reset":     integerHeader("Seconds until the limit is fully restored"),
+		},
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"$ref": "#/components/schemas/ErrorResponse",
+				},
+				"example": map[string]interface{}{
+					"message": "rate limit exceeded",
+				},
+			},
+		},
+	}
+}
+
+func getErrorResponse(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"$ref": "#/components/schemas/ErrorResponse",
+				},
+				"example": map[string]interface{}{
+					"error":   "error_code",
+					"message": description,
+				},
+			},
+		},
+	}
+}
+
+func getSchemas() map[string]interface{} {
+	return map[string]interface{}{
+		"AmountRequest": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"amount", "currency"},
+			"properties": map[string]interface{}{
+				"amount": map[string]interface{}{
+					"type":        "number",
+					"format":      "float",
+					"minimum":     0,
 					"example":     100.50,
 					"description": "Amount in major units (dollars, euros, pounds)",
 				},
@@ -826,73 +2924,593 @@ func getSchemas() map[string]interface{} {
 				},
 			},
 		},
-		"NameEnquiryRequest": map[string]interface{}{
+		"NameEnquiryRequest": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"account_number", "bank_code"},
+			"properties": map[string]interface{}{
+				"account_number": map[string]interface{}{
+					"type":    "string",
+					"example": "1000000001",
+				},
+				"bank_code": map[string]interface{}{
+					"type":    "string",
+					"example": "044",
+				},
+				"account_name": map[string]interface{}{
+					"type":        "string",
+					"example":     "John Doe",
+					"description": "Name the caller expects on the account. When set, the response includes name_match",
+				},
+			},
+		},
+		"CreateInternalTransferRequest": map[string]interface{}{
+			"type":        "object",
+			"required":    []string{"from_currency", "amount"},
+			"description": "Name the recipient with either to_account_number and to_bank_code or beneficiary_id",
+			"properties": map[string]interface{}{
+				"from_currency": map[string]interface{}{
+					"type":    "string",
+					"enum":    []string{"USD", "EUR", "GBP"},
+					"example": "USD",
+				},
+				"to_account_number": map[string]interface{}{
+					"type":    "string",
+					"example": "2000000001",
+				},
+				"to_bank_code": map[string]interface{}{
+					"type":    "string",
+					"example": "044",
+				},
+				"to_account_name": map[string]interface{}{
+					"type":        "string",
+					"example":     "John Doe",
+					"description": "Name the sender expects on the account. A mismatch rejects the transfer with NAME_MISMATCH; partial matches are allowed",
+				},
+				"beneficiary_id": map[string]interface{}{
+					"type":        "string",
+					"example":     "beneficiary-id",
+					"description": "A saved beneficiary of the sender, in place of to_account_number and to_bank_code",
+				},
+				"amount": map[string]interface{}{
+					"$ref": "#/components/schemas/AmountRequest",
+				},
+			},
+		},
+		"CreateExternalTransferRequest": map[string]interface{}{
+			"type":        "object",
+			"required":    []string{"from_currency", "amount"},
+			"description": "Name the recipient with either to_account_number and to_bank_code or beneficiary_id",
+			"properties": map[string]interface{}{
+				"from_currency": map[string]interface{}{
+					"type":    "string",
+					"enum":    []string{"USD", "EUR", "GBP"},
+					"example": "USD",
+				},
+				"to_account_number": map[string]interface{}{
+					"type":        "string",
+					"example":     "40478470872490",
+					"description": "Format depends on amount.currency: an IBAN for EUR; a GB IBAN or 6-digit sort code and 8-digit account number for GBP; a 9-digit ABA routing number and 4 to 17 digit account number for USD. Spaces and hyphens are ignored",
+				},
+				"to_bank_code": map[string]interface{}{
+					"type":    "string",
+					"example": "044",
+				},
+				"to_account_name": map[string]interface{}{
+					"type":        "string",
+					"example":     "John Doe",
+					"description": "Name the sender expects on the account. A mismatch rejects the transfer with NAME_MISMATCH; partial matches are allowed",
+				},
+				"beneficiary_id": map[string]interface{}{
+					"type":        "string",
+					"example":     "beneficiary-id",
+					"description": "A saved beneficiary of the sender, in place of to_account_number and to_bank_code",
+				},
+				"amount": map[string]interface{}{
+					"$ref": "#/components/schemas/AmountRequest",
+				},
+			},
+		},
+		"ConfirmTransactionRequest": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"pin": map[string]interface{}{
+					"type":        "string",
+					"pattern":     "^[0-9]{5}$",
+					"example":     "12345",
+					"description": "5-digit numeric PIN. Required unless step-up replaces the PIN for this amount",
+				},
+				"totp_code": map[string]interface{}{
+					"type":        "string",
+					"example":     "492039",
+					"description": "6-digit authenticator code or an unused recovery code. Required at or above the step-up threshold",
+				},
+			},
+		},
+		"TOTPCodeRequest": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"code"},
+			"properties": map[string]interface{}{
+				"code": map[string]interface{}{
+					"type":        "string",
+					"example":     "492039",
+					"description": "6-digit authenticator code, or a recovery code when disabling",
+				},
+			},
+		},
+		"ReverseTransactionRequest": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"reason"},
+			"properties": map[string]interface{}{
+				"reason": map[string]interface{}{
+					"type":        "string",
+					"maxLength":   255,
+					"example":     "Sent to the wrong recipient",
+					"description": "Why the transfer is being reversed",
+				},
+			},
+		},
+		"OpenWalletRequest": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"currency"},
+			"properties": map[string]interface{}{
+				"currency": map[string]interface{}{
+					"type":    "string",
+					"enum":    []string{"USD", "EUR", "GBP"},
+					"example": "USD",
+				},
+			},
+		},
+		"FreezeWalletRequest": map[string]interface{}{
 			"type":     "object",
-			"required": []string{"account_number", "bank_code"},
+			"required": []string{"direction", "reason"},
 			"properties": map[string]interface{}{
-				"account_number": map[string]interface{}{
+				"direction": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"debits", "credits", "both"},
+					"example":     "debits",
+					"description": "Movements to block: money leaving the wallet, entering it, or both",
+				},
+				"reason": map[string]interface{}{
+					"type":      "string",
+					"maxLength": 255,
+					"example":   "Suspected account takeover",
+				},
+			},
+		},
+		"WalletStatusRequest": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"reason": map[string]interface{}{
+					"type":      "string",
+					"maxLength": 255,
+					"example":   "Customer request",
+				},
+			},
+		},
+		"RegisterUserRequest": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"name"},
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":      "string",
+					"maxLength": 200,
+					"example":   "Ada Lovelace",
+				},
+				"email": map[string]interface{}{
 					"type":    "string",
-					"example": "1000000001",
+					"format":  "email",
+					"example": "ada@example.com",
 				},
-				"bank_code": map[string]interface{}{
+				"phone": map[string]interface{}{
+					"type":        "string",
+					"example":     "+447700900123",
+					"description": "E.164 phone number",
+				},
+				"country": map[string]interface{}{
+					"type":        "string",
+					"example":     "GB",
+					"description": "ISO 3166-1 alpha-2 country of residence",
+				},
+				"date_of_birth": map[string]interface{}{
 					"type":    "string",
-					"example": "044",
+					"format":  "date",
+					"example": "1990-12-10",
 				},
 			},
 		},
-		"CreateInternalTransferRequest": map[string]interface{}{
-			"type":     "object",
-			"required": []string{"from_currency", "to_account_number", "to_bank_code", "amount"},
+		"UpdateUserProfileRequest": map[string]interface{}{
+			"type":        "object",
+			"description": "Only the fields present are changed. name, country and date_of_birth are rejected once the user reaches KYC tier 1.",
 			"properties": map[string]interface{}{
-				"from_currency": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":      "string",
+					"maxLength": 200,
+					"example":   "Ada Lovelace",
+				},
+				"email": map[string]interface{}{
 					"type":    "string",
-					"enum":    []string{"USD", "EUR", "GBP"},
-					"example": "USD",
+					"format":  "email",
+					"example": "ada@example.com",
 				},
-				"to_account_number": map[string]interface{}{
+				"phone": map[string]interface{}{
 					"type":    "string",
-					"example": "2000000001",
+					"example": "+447700900123",
 				},
-				"to_bank_code": map[string]interface{}{
+				"country": map[string]interface{}{
 					"type":    "string",
-					"example": "044",
+					"example": "GB",
 				},
-				"amount": map[string]interface{}{
-					"$ref": "#/components/schemas/AmountRequest",
+				"date_of_birth": map[string]interface{}{
+					"type":    "string",
+					"format":  "date",
+					"example": "1990-12-10",
 				},
 			},
 		},
-		"CreateExternalTransferRequest": map[string]interface{}{
+		"SubmitKYCDocumentRequest": map[string]interface{}{
 			"type":     "object",
-			"required": []string{"from_currency", "to_account_number", "to_bank_code", "amount"},
+			"required": []string{"document_type", "document_number", "issuing_country"},
 			"properties": map[string]interface{}{
-				"from_currency": map[string]interface{}{
+				"document_type": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"passport", "national_id", "drivers_license", "utility_bill", "bank_statement"},
+					"example":     "passport",
+					"description": "passport, national_id and drivers_license prove identity; utility_bill and bank_statement prove address",
+				},
+				"document_number": map[string]interface{}{
+					"type":      "string",
+					"maxLength": 64,
+					"example":   "123456789",
+				},
+				"issuing_country": map[string]interface{}{
 					"type":    "string",
-					"enum":    []string{"USD", "EUR", "GBP"},
-					"example": "USD",
+					"example": "GB",
 				},
-				"to_account_number": map[string]interface{}{
+			},
+		},
+		"ReviewKYCDocumentRequest": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"decision"},
+			"properties": map[string]interface{}{
+				"decision": map[string]interface{}{
 					"type":    "string",
-					"example": "9999999999",
+					"enum":    []string{"approved", "rejected"},
+					"example": "approved",
 				},
-				"to_bank_code": map[string]interface{}{
+				"note": map[string]interface{}{
+					"type":        "string",
+					"maxLength":   255,
+					"example":     "Checked against issuing registry",
+					"description": "Reviewer note stored with the decision and shown to the user",
+				},
+			},
+		},
+		"UserProfileResponse": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id": map[string]interface{}{
 					"type":    "string",
-					"example": "044",
+					"example": "user_3",
+				},
+				"name": map[string]interface{}{
+					"type":    "string",
+					"example": "Ada Lovelace",
+				},
+				"email": map[string]interface{}{
+					"type":    "string",
+					"example": "ada@example.com",
+				},
+				"phone": map[string]interface{}{
+					"type":    "string",
+					"example": "+447700900123",
+				},
+				"country": map[string]interface{}{
+					"type":    "string",
+					"example": "GB",
+				},
+				"date_of_birth": map[string]interface{}{
+					"type":    "string",
+					"format":  "date",
+					"example": "1990-12-10",
+				},
+				"kyc_tier": map[string]interface{}{
+					"type":        "integer",
+					"enum":        []int{0, 1, 2},
+					"example":     1,
+					"description": "0 unverified, 1 identity verified, 2 identity and address verified",
+				},
+				"limit_tier": map[string]interface{}{
+					"type":        "string",
+					"example":     "standard",
+					"description": "Transfer limit tier; see GET /api/limits",
+				},
+				"allowed_currencies": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+					"example":     []string{"USD"},
+					"description": "Currencies the KYC tier allows wallets and transfers in",
+				},
+				"documents": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"$ref": "#/components/schemas/KYCDocumentResponse",
+					},
+				},
+				"created_at": map[string]interface{}{
+					"type":    "string",
+					"format":  "date-time",
+					"example": "2026-01-11T00:00:00Z",
+				},
+				"updated_at": map[string]interface{}{
+					"type":    "string",
+					"format":  "date-time",
+					"example": "2026-01-11T00:00:00Z",
+				},
+			},
+		},
+		"KYCDocumentResponse": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id": map[string]interface{}{
+					"type":    "string",
+					"example": "kyc-document-id",
+				},
+				"user_id": map[string]interface{}{
+					"type":    "string",
+					"example": "user_3",
+				},
+				"category": map[string]interface{}{
+					"type":    "string",
+					"enum":    []string{"identity", "address"},
+					"example": "identity",
+				},
+				"document_type": map[string]interface{}{
+					"type":    "string",
+					"example": "passport",
+				},
+				"document_number": map[string]interface{}{
+					"type":    "string",
+					"example": "123456789",
+				},
+				"issuing_country": map[string]interface{}{
+					"type":    "string",
+					"example": "GB",
+				},
+				"status": map[string]interface{}{
+					"type":    "string",
+					"enum":    []string{"pending", "approved", "rejected"},
+					"example": "pending",
+				},
+				"reviewer_id": map[string]interface{}{
+					"type":    "string",
+					"example": "admin_1",
+				},
+				"review_note": map[string]interface{}{
+					"type":    "string",
+					"example": "Checked against issuing registry",
+				},
+				"reviewed_at": map[string]interface{}{
+					"type":    "string",
+					"format":  "date-time",
+					"example": "2026-01-11T00:00:00Z",
+				},
+				"created_at": map[string]interface{}{
+					"type":    "string",
+					"format":  "date-time",
+					"example": "2026-01-11T00:00:00Z",
+				},
+				"updated_at": map[string]interface{}{
+					"type":    "string",
+					"format":  "date-time",
+					"example": "2026-01-11T00:00:00Z",
+				},
+			},
+		},
+		"ReviewTransactionRequest": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"decision"},
+			"properties": map[string]interface{}{
+				"decision": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"approved", "rejected"},
+					"example":     "approved",
+					"description": "approved completes the transfer; rejected fails it and releases the held funds",
+				},
+				"note": map[string]interface{}{
+					"type":        "string",
+					"maxLength":   255,
+					"example":     "Recipient confirmed by phone",
+					"description": "Analyst note stored with the decision",
 				},
+			},
+		},
+		"CreateRefundRequest": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"amount", "reason"},
+			"properties": map[string]interface{}{
 				"amount": map[string]interface{}{
-					"$ref": "#/components/schemas/AmountRequest",
+					"$ref":        "#/components/schemas/AmountRequest",
+					"description": "Amount to refund, in the original transaction currency",
+				},
+				"reason": map[string]interface{}{
+					"type":      "string",
+					"maxLength": 255,
+					"example":   "Disputed portion of the order",
 				},
 			},
 		},
-		"ConfirmTransactionRequest": map[string]interface{}{
+		"SetPINRequest": map[string]interface{}{
 			"type":     "object",
 			"required": []string{"pin"},
 			"properties": map[string]interface{}{
 				"pin": map[string]interface{}{
+					"type":    "string",
+					"pattern": "^[0-9]{5}$",
+					"example": "27491",
+				},
+			},
+		},
+		"ChangePINRequest": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"old_pin", "new_pin"},
+			"properties": map[string]interface{}{
+				"old_pin": map[string]interface{}{
+					"type":    "string",
+					"pattern": "^[0-9]{5}$",
+					"example": "27491",
+				},
+				"new_pin": map[string]interface{}{
+					"type":    "string",
+					"pattern": "^[0-9]{5}$",
+					"example": "90817",
+				},
+			},
+		},
+		"ResetPINRequest": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"token", "new_pin"},
+			"properties": map[string]interface{}{
+				"token": map[string]interface{}{
+					"type":    "string",
+					"example": "q3Zr8bT0x0mK1cWl8c0Jd2p3J1mPpQ6n2nWc7kUuY5E",
+				},
+				"new_pin": map[string]interface{}{
+					"type":    "string",
+					"pattern": "^[0-9]{5}$",
+					"example": "90817",
+				},
+			},
+		},
+		"RefundResponse": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id": map[string]interface{}{
+					"type":    "string",
+					"example": "refund-id",
+				},
+				"transaction_id": map[string]interface{}{
+					"type":    "string",
+					"example": "tx-id",
+				},
+				"refund_transaction_id": map[string]interface{}{
+					"type":    "string",
+					"example": "refund-tx-id",
+				},
+				"amount": map[string]interface{}{
+					"type":        "number",
+					"format":      "float",
+					"example":     25.00,
+					"description": "Refunded amount in the original transaction currency",
+				},
+				"currency": map[string]interface{}{
+					"type":    "string",
+					"enum":    []string{"USD", "EUR", "GBP"},
+					"example": "EUR",
+				},
+				"credited_amount": map[string]interface{}{
+					"type":        "number",
+					"format":      "float",
+					"example":     29.41,
+					"description": "Amount credited back to the sender at the original exchange rate",
+				},
+				"credited_currency": map[string]interface{}{
+					"type":    "string",
+					"enum":    []string{"USD", "EUR", "GBP"},
+					"example": "USD",
+				},
+				"reason": map[string]interface{}{
+					"type":    "string",
+					"example": "Disputed portion of the order",
+				},
+				"created_by": map[string]interface{}{
+					"type":    "string",
+					"example": "admin_1",
+				},
+				"created_at": map[string]interface{}{
+					"type":    "string",
+					"format":  "date-time",
+					"example": "2026-01-11T00:00:00Z",
+				},
+			},
+		},
+		"RiskAssessmentResponse": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id": map[string]interface{}{
+					"type":    "string",
+					"example": "assessment-id",
+				},
+				"transaction_id": map[string]interface{}{
+					"type":    "string",
+					"example": "tx-id",
+				},
+				"user_id": map[string]interface{}{
+					"type":    "string",
+					"example": "user_1",
+				},
+				"recipient": map[string]interface{}{
 					"type":        "string",
-					"pattern":     "^[0-9]{5}$",
-					"example":     "12345",
-					"description": "5-digit numeric PIN",
+					"example":     "wallet:wallet_user2_eur",
+					"description": "wallet:<id> for internal transfers, account:<bank_code>:<account_number> for external ones",
+				},
+				"decision": map[string]interface{}{
+					"type":    "string",
+					"enum":    []string{"allow", "review", "deny"},
+					"example": "review",
+				},
+				"score": map[string]interface{}{
+					"type":    "integer",
+					"example": 50,
+				},
+				"reasons": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"rule": map[string]interface{}{
+								"type":    "string",
+								"enum":    []string{"velocity", "new_beneficiary_large_amount", "unusual_corridor", "near_limit", "new_beneficiary_cooldown", "sanctions_match"},
+								"example": "new_beneficiary_large_amount",
+							},
+							"score": map[string]interface{}{
+								"type":    "integer",
+								"example": 50,
+							},
+							"detail": map[string]interface{}{
+								"type":    "string",
+								"example": "large first transfer to this recipient",
+							},
+						},
+					},
+				},
+				"features": map[string]interface{}{
+					"type":        "object",
+					"description": "Inputs the rules were evaluated against",
+				},
+				"review_decision": map[string]interface{}{
+					"type":    "string",
+					"enum":    []string{"approved", "rejected"},
+					"example": "approved",
+				},
+				"reviewed_by": map[string]interface{}{
+					"type":    "string",
+					"example": "admin_1",
+				},
+				"review_note": map[string]interface{}{
+					"type":    "string",
+					"example": "Recipient confirmed by phone",
+				},
+				"reviewed_at": map[string]interface{}{
+					"type":    "string",
+					"format":  "date-time",
+					"example": "2026-01-11T00:10:00Z",
+				},
+				"created_at": map[string]interface{}{
+					"type":    "string",
+					"format":  "date-time",
+					"example": "2026-01-11T00:00:00Z",
 				},
 			},
 		},
@@ -916,6 +3534,19 @@ func getSchemas() map[string]interface{} {
 					"type":    "string",
 					"example": "completed",
 				},
+				"account_number": map[string]interface{}{
+					"type":        "string",
+					"example":     "3000000012",
+					"description": "Virtual account paid into; required for credit.received",
+				},
+				"bank_code": map[string]interface{}{
+					"type":        "string",
+					"example":     "044",
+					"description": "Bank holding the virtual account; required for credit.received",
+				},
+				"amount": map[string]interface{}{
+					"$ref": "#/components/schemas/AmountRequest",
+				},
 			},
 		},
 		"TransactionResponse": map[string]interface{}{
@@ -939,7 +3570,7 @@ func getSchemas() map[string]interface{} {
 				},
 				"type": map[string]interface{}{
 					"type":    "string",
-					"enum":    []string{"internal", "external"},
+					"enum":    []string{"internal", "external", "reversal", "refund"},
 					"example": "internal",
 				},
 				"amount": map[string]interface{}{
@@ -954,7 +3585,7 @@ func getSchemas() map[string]interface{} {
 				},
 				"status": map[string]interface{}{
 					"type":    "string",
-					"enum":    []string{"initiated", "pending", "completed", "failed"},
+					"enum":    []string{"initiated", "pending", "completed", "failed", "cancelled", "reversed", "refunded", "expired", "on_hold"},
 					"example": "completed",
 				},
 				"provider_name": map[string]interface{}{
@@ -974,6 +3605,297 @@ func getSchemas() map[string]interface{} {
 					"type":    "string",
 					"example": "Insufficient funds",
 				},
+				"fee_amount": map[string]interface{}{
+					"type":        "number",
+					"format":      "float",
+					"example":     1.00,
+					"description": "Fee charged on top of the transfer amount, in major units of fee_currency. Quoted at creation, before PIN confirmation.",
+				},
+				"fee_currency": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"USD", "EUR", "GBP"},
+					"example":     "USD",
+					"description": "Currency of the debited wallet in which the fee is charged",
+				},
+				"parent_transaction_id": map[string]interface{}{
+					"type":        "string",
+					"example":     "tx-id",
+					"description": "For reversal and refund transactions, the ID of the original transaction",
+				},
+				"screening_result": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"clear", "match"},
+					"example":     "clear",
+					"description": "Sanctions screening of the beneficiary name, set when an external transfer is confirmed. A match holds the transfer for review.",
+				},
+				"screening_list_version": map[string]interface{}{
+					"type":        "string",
+					"example":     "2025-01-15",
+					"description": "Version of the sanctions list the beneficiary was screened against",
+				},
+				"beneficiary_id": map[string]interface{}{
+					"type":        "string",
+					"example":     "beneficiary-id",
+					"description": "Saved beneficiary the transfer was made to, if any",
+				},
+				"payment_request_id": map[string]interface{}{
+					"type":        "string",
+					"example":     "payment-request-id",
+					"description": "Payment request the transfer pays, if it was made from a payment link",
+				},
+				"recipient": map[string]interface{}{
+					"$ref": "#/components/schemas/TransferRecipient",
+				},
+				"refunds": map[string]interface{}{
+					"type":        "array",
+					"description": "Refunds issued against this transaction (only on GET /api/payments/{id})",
+					"items": map[string]interface{}{
+						"$ref": "#/components/schemas/RefundResponse",
+					},
+				},
+				"created_at": map[string]interface{}{
+					"type":    "string",
+					"format":  "date-time",
+					"example": "2026-01-11T00:00:00Z",
+				},
+				"updated_at": map[string]interface{}{
+					"type":    "string",
+					"format":  "date-time",
+					"example": "2026-01-11T00:00:00Z",
+				},
+			},
+		},
+		"TransferRecipient": map[string]interface{}{
+			"type":        "object",
+			"description": "Bank account an external transfer pays out to, set on external transfers",
+			"properties": map[string]interface{}{
+				"account_number": map[string]interface{}{
+					"type":    "string",
+					"example": "9999999999",
+				},
+				"bank_code": map[string]interface{}{
+					"type":    "string",
+					"example": "044",
+				},
+				"account_name": map[string]interface{}{
+					"type":        "string",
+					"example":     "Mock Account Holder 9999",
+					"description": "Account name resolved by name enquiry when the transfer was created",
+				},
+				"country": map[string]interface{}{
+					"type":        "string",
+					"example":     "US",
+					"description": "ISO 3166-1 alpha-2 country of the recipient's bank, when the provider reports it",
+				},
+				"currency": map[string]interface{}{
+					"type":    "string",
+					"enum":    []string{"USD", "EUR", "GBP"},
+					"example": "GBP",
+				},
+			},
+		},
+		"CreatePaymentRequestRequest": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"amount"},
+			"properties": map[string]interface{}{
+				"amount": map[string]interface{}{
+					"$ref": "#/components/schemas/AmountRequest",
+				},
+				"memo": map[string]interface{}{
+					"type":      "string",
+					"maxLength": 140,
+					"example":   "Dinner on Friday",
+				},
+				"expires_at": map[string]interface{}{
+					"type":        "string",
+					"format":      "date-time",
+					"example":     "2026-01-18T00:00:00Z",
+					"description": "Defaults to PAYMENT_REQUEST_DEFAULT_TTL from now; may not exceed PAYMENT_REQUEST_MAX_TTL",
+				},
+			},
+		},
+		"PayPaymentRequestRequest": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"from_currency"},
+			"properties": map[string]interface{}{
+				"from_currency": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"USD", "EUR", "GBP"},
+					"example":     "USD",
+					"description": "Payer wallet to debit; converted at the current rate when it differs from the requested currency",
+				},
+			},
+		},
+		"PaymentRequestResponse": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id": map[string]interface{}{
+					"type":    "string",
+					"example": "payment-request-id",
+				},
+				"amount": map[string]interface{}{
+					"type":    "number",
+					"format":  "float",
+					"example": 25.00,
+				},
+				"currency": map[string]interface{}{
+					"type":    "string",
+					"enum":    []string{"USD", "EUR", "GBP"},
+					"example": "USD",
+				},
+				"memo": map[string]interface{}{
+					"type":    "string",
+					"example": "Dinner on Friday",
+				},
+				"token": map[string]interface{}{
+					"type":        "string",
+					"example":     "c2hhcmVhYmxlLXRva2VuLTE",
+					"description": "Token for the shareable link, GET /api/payment-links/{token}",
+				},
+				"status": map[string]interface{}{
+					"type":    "string",
+					"enum":    []string{"pending", "paid", "expired", "cancelled"},
+					"example": "pending",
+				},
+				"expires_at": map[string]interface{}{
+					"type":    "string",
+					"format":  "date-time",
+					"example": "2026-01-18T00:00:00Z",
+				},
+				"transaction_id": map[string]interface{}{
+					"type":        "string",
+					"example":     "tx-id",
+					"description": "Transfer that paid the request; only shown to the requester",
+				},
+				"paid_by": map[string]interface{}{
+					"type":        "string",
+					"example":     "user_1",
+					"description": "User who paid the request; only shown to the requester",
+				},
+				"paid_at": map[string]interface{}{
+					"type":    "string",
+					"format":  "date-time",
+					"example": "2026-01-12T00:00:00Z",
+				},
+				"created_at": map[string]interface{}{
+					"type":    "string",
+					"format":  "date-time",
+					"example": "2026-01-11T00:00:00Z",
+				},
+				"updated_at": map[string]interface{}{
+					"type":    "string",
+					"format":  "date-time",
+					"example": "2026-01-11T00:00:00Z",
+				},
+			},
+		},
+		"CreateBeneficiaryRequest": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"account_number", "bank_code"},
+			"properties": map[string]interface{}{
+				"nickname": map[string]interface{}{
+					"type":      "string",
+					"maxLength": 64,
+					"example":   "Landlord",
+				},
+				"account_number": map[string]interface{}{
+					"type":    "string",
+					"example": "9999999999",
+				},
+				"bank_code": map[string]interface{}{
+					"type":    "string",
+					"example": "044",
+				},
+			},
+		},
+		"UpdateBeneficiaryRequest": map[string]interface{}{
+			"type":        "object",
+			"description": "Only the fields present are changed. An empty nickname clears it.",
+			"properties": map[string]interface{}{
+				"nickname": map[string]interface{}{
+					"type":      "string",
+					"maxLength": 64,
+					"example":   "Rent",
+				},
+				"account_number": map[string]interface{}{
+					"type":    "string",
+					"example": "9999999998",
+				},
+				"bank_code": map[string]interface{}{
+					"type":    "string",
+					"example": "044",
+				},
+			},
+		},
+		"BankResponse": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"code": map[string]interface{}{
+					"type":    "string",
+					"example": "044",
+				},
+				"name": map[string]interface{}{
+					"type":    "string",
+					"example": "Test Bank",
+				},
+				"country": map[string]interface{}{
+					"type":    "string",
+					"example": "US",
+				},
+				"currencies": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"type": "string",
+						"enum": []string{"USD", "EUR", "GBP"},
+					},
+					"example": []string{"USD", "EUR", "GBP"},
+				},
+			},
+		},
+		"BeneficiaryResponse": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id": map[string]interface{}{
+					"type":    "string",
+					"example": "beneficiary-id",
+				},
+				"nickname": map[string]interface{}{
+					"type":    "string",
+					"example": "Landlord",
+				},
+				"account_number": map[string]interface{}{
+					"type":    "string",
+					"example": "9999999999",
+				},
+				"bank_code": map[string]interface{}{
+					"type":    "string",
+					"example": "044",
+				},
+				"account_name": map[string]interface{}{
+					"type":        "string",
+					"example":     "Mock Account Holder 9999",
+					"description": "Account name resolved by name enquiry when the beneficiary was last verified",
+				},
+				"currency": map[string]interface{}{
+					"type":    "string",
+					"enum":    []string{"USD", "EUR", "GBP"},
+					"example": "USD",
+				},
+				"country": map[string]interface{}{
+					"type":    "string",
+					"example": "US",
+				},
+				"is_internal": map[string]interface{}{
+					"type":        "boolean",
+					"example":     false,
+					"description": "Whether the account belongs to a user of this service; pay internal beneficiaries with an internal transfer",
+				},
+				"verified_at": map[string]interface{}{
+					"type":        "string",
+					"format":      "date-time",
+					"example":     "2026-01-11T00:00:00Z",
+					"description": "When name enquiry last confirmed the account. Transfers within RISK_BENEFICIARY_COOLDOWN of it add to the risk score.",
+				},
 				"created_at": map[string]interface{}{
 					"type":    "string",
 					"format":  "date-time",
@@ -1003,6 +3925,22 @@ func getSchemas() map[string]interface{} {
 					"format":  "float",
 					"example": 100.50,
 				},
+				"available_balance": map[string]interface{}{
+					"type":        "number",
+					"format":      "float",
+					"description": "Balance minus funds held for initiated transfers",
+					"example":     75.50,
+				},
+				"status": map[string]interface{}{
+					"type":    "string",
+					"enum":    []string{"active", "frozen", "closed"},
+					"example": "active",
+				},
+				"frozen_direction": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"debits", "credits", "both"},
+					"description": "Movements blocked while the wallet is frozen",
+				},
 				"account_number": map[string]interface{}{
 					"type":    "string",
 					"example": "1000000001",
@@ -1035,6 +3973,62 @@ func getSchemas() map[string]interface{} {
 				},
 			},
 		},
+		"WalletResponse": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id": map[string]interface{}{
+					"type":    "string",
+					"example": "wallet_user1_usd",
+				},
+				"user_id": map[string]interface{}{
+					"type":    "string",
+					"example": "user_1",
+				},
+				"currency": map[string]interface{}{
+					"type":    "string",
+					"enum":    []string{"USD", "EUR", "GBP"},
+					"example": "USD",
+				},
+				"balance": map[string]interface{}{
+					"type":    "number",
+					"format":  "float",
+					"example": 100.50,
+				},
+				"available_balance": map[string]interface{}{
+					"type":    "number",
+					"format":  "float",
+					"example": 75.50,
+				},
+				"status": map[string]interface{}{
+					"type":    "string",
+					"enum":    []string{"active", "frozen", "closed"},
+					"example": "frozen",
+				},
+				"frozen_direction": map[string]interface{}{
+					"type":    "string",
+					"enum":    []string{"debits", "credits", "both"},
+					"example": "debits",
+				},
+				"status_reason": map[string]interface{}{
+					"type":    "string",
+					"example": "Suspected account takeover",
+				},
+				"closed_at": map[string]interface{}{
+					"type":   "string",
+					"format": "date-time",
+				},
+				"created_at": map[string]interface{}{
+					"type":    "string",
+					"format":  "date-time",
+					"example": "2026-01-11T00:00:00Z",
+				},
+				"updated_at": map[string]interface{}{
+					"type":    "string",
+					"format":  "date-time",
+					"example": "2026-01-11T00:00:00Z",
+				},
+			},
+		},
 		"TestUserDataResponse": map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -1092,6 +4086,11 @@ func getSchemas() map[string]interface{} {
 					"type":    "string",
 					"example": "Human-readable error message",
 				},
+				"code": map[string]interface{}{
+					"type":        "string",
+					"description": "Machine-readable code for errors clients handle specially, e.g. PIN_LOCKED",
+					"example":     "PIN_LOCKED",
+				},
 			},
 		},
 	}
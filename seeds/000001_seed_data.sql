@@ -13,10 +13,12 @@ SET timezone = 'UTC';
 -- ============================================
 -- USERS
 -- ============================================
-INSERT INTO users (user_id, name, pin_hash, created_at, updated_at)
+-- Both users are fully verified (KYC tier 2) so they can hold and move every
+-- seeded currency without going through document review first.
+INSERT INTO users (user_id, name, pin_hash, kyc_tier, created_at, updated_at)
 VALUES 
-    ('user_1', 'John Doe', '$2a$10$z1vJ04atupmQEzE6En2J5O7KtbhR3LgnbL7o2LizR9AhPE7cmA3wC', NOW(), NOW()),
-    ('user_2', 'Jane Doe', '$2a$10$z1vJ04atupmQEzE6En2J5O7KtbhR3LgnbL7o2LizR9AhPE7cmA3wC', NOW(), NOW())
+    ('user_1', 'John Doe', '$2a$10$z1vJ04atupmQEzE6En2J5O7KtbhR3LgnbL7o2LizR9AhPE7cmA3wC', 2, NOW(), NOW()),
+    ('user_2', 'Jane Doe', '$2a$10$z1vJ04atupmQEzE6En2J5O7KtbhR3LgnbL7o2LizR9AhPE7cmA3wC', 2, NOW(), NOW())
 ON CONFLICT (user_id) DO NOTHING;
 
 -- ============================================
@@ -173,3 +175,35 @@ SET balance = (
 ),
 updated_at = NOW()
 WHERE id IN ('wallet_user1_usd', 'wallet_user1_eur', 'wallet_user1_gbp', 'wallet_user2_usd', 'wallet_user2_eur', 'wallet_user2_gbp');
+
+-- ============================================
+-- FEE RULES
+-- ============================================
+-- Amounts are in minor units of the source (debited) currency.
+-- Internal transfers have no rule and are therefore free.
+INSERT INTO fee_rules (id, name, transaction_type, source_currency, destination_currency, min_amount, max_amount, fee_type, flat_amount, percentage_bps, tiers, min_fee, max_fee, priority, created_at, updated_at)
+VALUES
+    ('fee_external_default', 'External payout 1%', 'external', NULL, NULL, 0, NULL, 'percentage', 0, 100, '[]', 100, 2500, 0, NOW(), NOW()),
+    ('fee_external_gbp_tiered', 'External payout from GBP (tiered)', 'external', 'GBP', NULL, 0, NULL, 'tiered', 0, 0, '[{"up_to": 100000, "percentage_bps": 150}, {"up_to": null, "percentage_bps": 75}]', 100, 5000, 0, NOW(), NOW())
+ON CONFLICT (id) DO NOTHING;
+
+-- ============================================
+-- TRANSFER LIMITS
+-- ============================================
+-- Amounts are in minor units of the source (debited) currency. NULL means the
+-- dimension is not limited. Users are on the 'standard' tier unless changed.
+INSERT INTO transfer_limits (id, tier, currency, transaction_type, per_transaction_max, daily_amount_max, daily_count_max, monthly_amount_max, monthly_count_max, created_at, updated_at)
+VALUES
+    ('limit_standard_usd_internal', 'standard', 'USD', 'internal', 1000000, 2000000, 20, 10000000, NULL, NOW(), NOW()),
+    ('limit_standard_eur_internal', 'standard', 'EUR', 'internal', 1000000, 2000000, 20, 10000000, NULL, NOW(), NOW()),
+    ('limit_standard_gbp_internal', 'standard', 'GBP', 'internal', 1000000, 2000000, 20, 10000000, NULL, NOW(), NOW()),
+    ('limit_standard_usd_external', 'standard', 'USD', 'external', 500000, 1000000, 10, 5000000, 100, NOW(), NOW()),
+    ('limit_standard_eur_external', 'standard', 'EUR', 'external', 500000, 1000000, 10, 5000000, 100, NOW(), NOW()),
+    ('limit_standard_gbp_external', 'standard', 'GBP', 'external', 500000, 1000000, 10, 5000000, 100, NOW(), NOW()),
+    ('limit_premium_usd_internal', 'premium', 'USD', 'internal', 5000000, 10000000, NULL, 50000000, NULL, NOW(), NOW()),
+    ('limit_premium_eur_internal', 'premium', 'EUR', 'internal', 5000000, 10000000, NULL, 50000000, NULL, NOW(), NOW()),
+    ('limit_premium_gbp_internal', 'premium', 'GBP', 'internal', 5000000, 10000000, NULL, 50000000, NULL, NOW(), NOW()),
+    ('limit_premium_usd_external', 'premium', 'USD', 'external', 2500000, 5000000, 50, 25000000, 500, NOW(), NOW()),
+    ('limit_premium_eur_external', 'premium', 'EUR', 'external', 2500000, 5000000, 50, 25000000, 500, NOW(), NOW()),
+    ('limit_premium_gbp_external', 'premium', 'GBP', 'external', 2500000, 5000000, 50, 25000000, 500, NOW(), NOW())
+ON CONFLICT (id) DO NOTHING;
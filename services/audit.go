@@ -0,0 +1,379 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+)
+
+const (
+	auditEntityTransaction = "transaction"
+	auditEntityWallet      = "wallet"
+	auditEntityUser        = "user"
+	auditEntityRefund      = "refund"
+	auditEntityBeneficiary = "beneficiary"
+	auditEntityKYCDocument = "kyc_document"
+
+	auditEntityPaymentRequest = "payment_request"
+
+	defaultAuditVerifyLimit = 1000
+	maxAuditVerifyLimit     = 10000
+)
+
+// auditGenesisHash is the prev_hash of the first event in the chain.
+var auditGenesisHash = strings.Repeat("0", 64)
+
+type AuditService interface {
+	ListEvents(ctx context.Context, filter models.AuditEventFilter) (*models.AuditEventPage, error)
+	VerifyChain(ctx context.Context, fromSequence int64, limit int32) (*models.AuditChainVerification, error)
+}
+
+type auditService struct {
+	queries gen.Querier
+}
+
+func newAuditService(queries gen.Querier) AuditService {
+	return &auditService{
+		queries: queries,
+	}
+}
+
+// auditEvent describes one change to record. Before and After are snapshots
+// of the fields that changed and are marshalled to JSON; nil is stored as {}.
+type auditEvent struct {
+	Action     string
+	EntityType string
+	EntityID   string
+	Before     any
+	After      any
+}
+
+// auditPayload is what an event's digest is computed over. Field order is
+// part of the hash, so fields must never be reordered or removed.
+type auditPayload struct {
+	ActorID    string          `json:"actor_id"`
+	ActorType  string          `json:"actor_type"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	IPAddress  string          `json:"ip_address"`
+	TraceID    string          `json:"trace_id"`
+	CreatedAt  string          `json:"created_at"`
+}
+
+// recordAuditEvent appends an event to the audit log using queries, which
+// should be bound to the transaction making the change so the event commits
+// or rolls back with it. The actor, client IP and trace ID come from ctx.
+func recordAuditEvent(ctx context.Context, queries gen.Querier, event auditEvent) error {
+	before, err := auditSnapshot(event.Before)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("marshal audit before snapshot: %w", err))
+	}
+	after, err := auditSnapshot(event.After)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("marshal audit after snapshot: %w", err))
+	}
+
+	actor := utils.ActorFromContext(ctx)
+	ipAddress := utils.ClientIPFromContext(ctx)
+	traceID := utils.TraceIDFromContext(ctx)
+	// Postgres stores microseconds; truncating here keeps the digest
+	// reproducible from the stored row.
+	createdAt := time.Now().UTC().Truncate(time.Microsecond)
+
+	digest, err := auditDigest(auditPayload{
+		ActorID:    actor.ID,
+		ActorType:  actor.Type,
+		Action:     event.Action,
+		EntityType: event.EntityType,
+		EntityID:   event.EntityID,
+		Before:     before,
+		After:      after,
+		IPAddress:  ipAddress,
+		TraceID:    traceID,
+		CreatedAt:  createdAt.Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return utils.ServerErr(err)
+	}
+
+	if err := queries.CreateAuditEvent(ctx, gen.CreateAuditEventParams{
+		ActorID:    actor.ID,
+		ActorType:  actor.Type,
+		Action:     event.Action,
+		EntityType: event.EntityType,
+		EntityID:   event.EntityID,
+		Before:     before,
+		After:      after,
+		IpAddress:  sql.NullString{String: ipAddress, Valid: ipAddress != ""},
+		TraceID:    sql.NullString{String: traceID, Valid: traceID != ""},
+		Digest:     digest,
+		CreatedAt:  createdAt,
+	}); err != nil {
+		return utils.ServerErr(fmt.Errorf("record audit event %s: %w", event.Action, err))
+	}
+	return nil
+}
+
+func auditSnapshot(v any) (json.RawMessage, error) {
+	if v == nil {
+		return json.RawMessage(`{}`), nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return canonicalJSON(raw)
+}
+
+// canonicalJSON re-encodes raw with sorted keys and no insignificant
+// whitespace. Postgres JSONB does not keep the original formatting, so both
+// the write and the verify side hash the canonical form.
+func canonicalJSON(raw json.RawMessage) (json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func auditDigest(payload auditPayload) (string, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("marshal audit payload: %w", err)
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// auditChainHash mirrors audit_chain_append in the database.
+func auditChainHash(prevHash string, digest string) string {
+	sum := sha256.Sum256([]byte(prevHash + digest))
+	return hex.EncodeToString(sum[:])
+}
+
+// rowDigest recomputes the digest of a stored event from its columns.
+func rowDigest(row gen.AuditLog) (string, error) {
+	before, err := canonicalJSON(row.Before)
+	if err != nil {
+		return "", fmt.Errorf("decode before snapshot: %w", err)
+	}
+	after, err := canonicalJSON(row.After)
+	if err != nil {
+		return "", fmt.Errorf("decode after snapshot: %w", err)
+	}
+
+	return auditDigest(auditPayload{
+		ActorID:    row.ActorID,
+		ActorType:  row.ActorType,
+		Action:     row.Action,
+		EntityType: row.EntityType,
+		EntityID:   row.EntityID,
+		Before:     before,
+		After:      after,
+		IPAddress:  row.IpAddress.String,
+		TraceID:    row.TraceID.String,
+		CreatedAt:  row.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// ListEvents returns audit events newest first. The cursor is the sequence of
+// the last event on the previous page.
+func (as *auditService) ListEvents(ctx context.Context, filter models.AuditEventFilter) (*models.AuditEventPage, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	params := gen.ListAuditEventsParams{
+		EntityType: sql.NullString{String: filter.EntityType, Valid: filter.EntityType != ""},
+		EntityID:   sql.NullString{String: filter.EntityID, Valid: filter.EntityID != ""},
+		ActorID:    sql.NullString{String: filter.ActorID, Valid: filter.ActorID != ""},
+		Action:     sql.NullString{String: filter.Action, Valid: filter.Action != ""},
+		RowLimit:   limit + 1,
+	}
+	if filter.From != nil {
+		params.CreatedFrom = sql.NullTime{Time: *filter.From, Valid: true}
+	}
+	if filter.To != nil {
+		params.CreatedTo = sql.NullTime{Time: *filter.To, Valid: true}
+	}
+	if filter.Cursor != "" {
+		sequence, err := strconv.ParseInt(filter.Cursor, 10, 64)
+		if err != nil || sequence <= 0 {
+			return nil, utils.BadRequestErr("invalid cursor")
+		}
+		params.BeforeSequence = sql.NullInt64{Int64: sequence, Valid: true}
+	}
+
+	rows, err := as.queries.ListAuditEvents(ctx, params)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("list audit events: %w", err))
+	}
+
+	hasMore := len(rows) > int(limit)
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	events := make([]*models.AuditEvent, 0, len(rows))
+	for _, row := range rows {
+		event, err := mapAuditEvent(row)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	var nextCursor *string
+	if hasMore && len(rows) > 0 {
+		cursor := strconv.FormatInt(rows[len(rows)-1].Sequence, 10)
+		nextCursor = &cursor
+	}
+
+	return &models.AuditEventPage{
+		Events:     events,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// VerifyChain recomputes the digest and hash of up to limit events starting
+// at fromSequence and checks each links to the one before. When the range
+// reaches the newest event it is also compared with the chain head, which
+// catches events removed from the end. A later range can be checked by
+// passing the previous result's LastSequence + 1.
+func (as *auditService) VerifyChain(ctx context.Context, fromSequence int64, limit int32) (*models.AuditChainVerification, error) {
+	if fromSequence <= 0 {
+		fromSequence = 1
+	}
+	if limit <= 0 {
+		limit = defaultAuditVerifyLimit
+	}
+	if limit > maxAuditVerifyLimit {
+		limit = maxAuditVerifyLimit
+	}
+
+	// Read one event before the range so its hash can anchor the first link.
+	anchorSequence := fromSequence - 1
+	queryFrom := max(anchorSequence, 1)
+	rowLimit := limit
+	if anchorSequence > 0 {
+		rowLimit++
+	}
+
+	rows, err := as.queries.ListAuditEventsFromSequence(ctx, gen.ListAuditEventsFromSequenceParams{
+		FromSequence: queryFrom,
+		RowLimit:     rowLimit,
+	})
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("list audit events: %w", err))
+	}
+
+	result := &models.AuditChainVerification{
+		FromSequence: fromSequence,
+		Valid:        true,
+		LastSequence: anchorSequence,
+		LastHash:     auditGenesisHash,
+	}
+	broken := func(sequence int64, problem string) *models.AuditChainVerification {
+		result.Valid = false
+		result.BrokenAt = &sequence
+		result.Problem = problem
+		return result
+	}
+
+	if anchorSequence > 0 {
+		if len(rows) == 0 || rows[0].Sequence != anchorSequence {
+			return broken(anchorSequence, "event is missing"), nil
+		}
+		result.LastHash = rows[0].Hash
+		rows = rows[1:]
+	}
+
+	for _, row := range rows {
+		expected := result.LastSequence + 1
+		if row.Sequence != expected {
+			return broken(expected, "event is missing"), nil
+		}
+		if row.PrevHash != result.LastHash {
+			return broken(row.Sequence, "prev_hash does not match the previous event"), nil
+		}
+
+		digest, err := rowDigest(row)
+		if err != nil {
+			return nil, utils.ServerErr(fmt.Errorf("audit event %d: %w", row.Sequence, err))
+		}
+		if digest != row.Digest {
+			return broken(row.Sequence, "event content does not match its digest"), nil
+		}
+		if auditChainHash(row.PrevHash, row.Digest) != row.Hash {
+			return broken(row.Sequence, "hash does not match prev_hash and digest"), nil
+		}
+
+		result.Checked++
+		result.LastSequence = row.Sequence
+		result.LastHash = row.Hash
+	}
+
+	if len(rows) < int(limit) {
+		head, err := as.queries.GetAuditChainHead(ctx)
+		if err != nil {
+			return nil, utils.ServerErr(fmt.Errorf("get audit chain head: %w", err))
+		}
+		result.Complete = true
+		if head.Sequence != result.LastSequence || head.Hash != result.LastHash {
+			return broken(result.LastSequence+1, "chain head does not match the last event"), nil
+		}
+	}
+
+	return result, nil
+}
+
+func mapAuditEvent(row gen.AuditLog) (*models.AuditEvent, error) {
+	event := &models.AuditEvent{
+		Sequence:   row.Sequence,
+		ID:         row.ID,
+		ActorID:    row.ActorID,
+		ActorType:  row.ActorType,
+		Action:     row.Action,
+		EntityType: row.EntityType,
+		EntityID:   row.EntityID,
+		PrevHash:   row.PrevHash,
+		Hash:       row.Hash,
+		CreatedAt:  row.CreatedAt,
+	}
+
+	if err := json.Unmarshal(row.Before, &event.Before); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("unmarshal audit before snapshot: %w", err))
+	}
+	if err := json.Unmarshal(row.After, &event.After); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("unmarshal audit after snapshot: %w", err))
+	}
+	if row.IpAddress.Valid {
+		event.IPAddress = &row.IpAddress.String
+	}
+	if row.TraceID.Valid {
+		event.TraceID = &row.TraceID.String
+	}
+
+	return event, nil
+}
@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// expectAuditEvent expects an audit event with action on entityID.
+func expectAuditEvent(m *mocks.MockQuerier, action string, entityID string) *mock.Call {
+	return m.On("CreateAuditEvent", mock.Anything, mock.MatchedBy(func(arg gen.CreateAuditEventParams) bool {
+		return arg.Action == action && arg.EntityID == entityID
+	})).Return(nil)
+}
+
+// auditRow builds a stored event the way the database would chain it.
+func auditRow(t *testing.T, sequence int64, prevHash string, entityID string) gen.AuditLog {
+	t.Helper()
+	row := gen.AuditLog{
+		Sequence:   sequence,
+		ID:         entityID + "_event",
+		ActorID:    "user_1",
+		ActorType:  utils.ActorTypeUser,
+		Action:     "transaction.status_changed",
+		EntityType: auditEntityTransaction,
+		EntityID:   entityID,
+		Before:     json.RawMessage(`{"status": "initiated"}`),
+		After:      json.RawMessage(`{"status": "completed", "reason": ""}`),
+		IpAddress:  sql.NullString{String: "203.0.113.7", Valid: true},
+		PrevHash:   prevHash,
+		CreatedAt:  time.Date(2026, 1, 11, 9, 30, 0, 123456000, time.UTC),
+	}
+	digest, err := rowDigest(row)
+	require.NoError(t, err)
+	row.Digest = digest
+	row.Hash = auditChainHash(prevHash, digest)
+	return row
+}
+
+func auditChain(t *testing.T, n int) []gen.AuditLog {
+	t.Helper()
+	rows := make([]gen.AuditLog, 0, n)
+	prevHash := auditGenesisHash
+	for i := 1; i <= n; i++ {
+		row := auditRow(t, int64(i), prevHash, fmt.Sprintf("tx_%d", i))
+		rows = append(rows, row)
+		prevHash = row.Hash
+	}
+	return rows
+}
+
+func TestRecordAuditEvent(t *testing.T) {
+	mockQueries := new(mocks.MockQuerier)
+
+	var stored gen.CreateAuditEventParams
+	mockQueries.On("CreateAuditEvent", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(gen.CreateAuditEventParams) }).
+		Return(nil)
+
+	ctx := utils.WithActor(context.Background(), utils.Actor{ID: "admin_1", Type: utils.ActorTypeAdmin})
+	ctx = utils.WithClientIP(ctx, "203.0.113.7")
+	ctx = utils.WithTraceID(ctx, "trace_1")
+
+	err := recordAuditEvent(ctx, mockQueries, auditEvent{
+		Action:     "transaction.status_changed",
+		EntityType: auditEntityTransaction,
+		EntityID:   "tx_1",
+		Before:     map[string]any{"status": models.TransactionStatusCompleted},
+		After:      map[string]any{"status": models.TransactionStatusReversed, "reason": "duplicate"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "admin_1", stored.ActorID)
+	assert.Equal(t, utils.ActorTypeAdmin, stored.ActorType)
+	assert.Equal(t, sql.NullString{String: "203.0.113.7", Valid: true}, stored.IpAddress)
+	assert.Equal(t, sql.NullString{String: "trace_1", Valid: true}, stored.TraceID)
+	assert.JSONEq(t, `{"status":"completed"}`, string(stored.Before))
+	assert.Equal(t, stored.CreatedAt, stored.CreatedAt.Truncate(time.Microsecond))
+
+	// JSONB hands the snapshots back reformatted; the digest must survive that.
+	row := gen.AuditLog{
+		ActorID:    stored.ActorID,
+		ActorType:  stored.ActorType,
+		Action:     stored.Action,
+		EntityType: stored.EntityType,
+		EntityID:   stored.EntityID,
+		Before:     json.RawMessage(`{"status": "completed"}`),
+		After:      json.RawMessage(`{"reason": "duplicate", "status": "reversed"}`),
+		IpAddress:  stored.IpAddress,
+		TraceID:    stored.TraceID,
+		CreatedAt:  stored.CreatedAt.In(time.FixedZone("WAT", 3600)),
+	}
+	digest, err := rowDigest(row)
+	require.NoError(t, err)
+	assert.Equal(t, stored.Digest, digest)
+
+	t.Run("nil snapshots and no request context", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("CreateAuditEvent", mock.Anything, mock.MatchedBy(func(arg gen.CreateAuditEventParams) bool {
+			return string(arg.Before) == "{}" && string(arg.After) == "{}" &&
+				arg.ActorType == utils.ActorTypeSystem && !arg.IpAddress.Valid && !arg.TraceID.Valid
+		})).Return(nil)
+
+		err := recordAuditEvent(context.Background(), mockQueries, auditEvent{Action: "totp.disabled", EntityType: auditEntityUser, EntityID: "user_1"})
+
+		require.NoError(t, err)
+		mockQueries.AssertExpectations(t)
+	})
+}
+
+func TestAuditService_VerifyChain(t *testing.T) {
+	t.Run("intact chain", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		as := &auditService{queries: mockQueries}
+		rows := auditChain(t, 3)
+
+		mockQueries.On("ListAuditEventsFromSequence", mock.Anything, gen.ListAuditEventsFromSequenceParams{FromSequence: 1, RowLimit: 1000}).Return(rows, nil)
+		mockQueries.On("GetAuditChainHead", mock.Anything).Return(gen.AuditChainHead{ID: 1, Sequence: 3, Hash: rows[2].Hash}, nil)
+
+		result, err := as.VerifyChain(context.Background(), 0, 0)
+
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.True(t, result.Complete)
+		assert.Equal(t, 3, result.Checked)
+		assert.Equal(t, int64(3), result.LastSequence)
+		assert.Equal(t, rows[2].Hash, result.LastHash)
+	})
+
+	t.Run("empty chain", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		as := &auditService{queries: mockQueries}
+
+		mockQueries.On("ListAuditEventsFromSequence", mock.Anything, mock.Anything).Return([]gen.AuditLog{}, nil)
+		mockQueries.On("GetAuditChainHead", mock.Anything).Return(gen.AuditChainHead{ID: 1, Sequence: 0, Hash: auditGenesisHash}, nil)
+
+		result, err := as.VerifyChain(context.Background(), 1, 10)
+
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, 0, result.Checked)
+	})
+
+	t.Run("edited event", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		as := &auditService{queries: mockQueries}
+		rows := auditChain(t, 3)
+		rows[1].After = json.RawMessage(`{"status": "failed", "reason": ""}`)
+
+		mockQueries.On("ListAuditEventsFromSequence", mock.Anything, mock.Anything).Return(rows, nil)
+
+		result, err := as.VerifyChain(context.Background(), 1, 10)
+
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		require.NotNil(t, result.BrokenAt)
+		assert.Equal(t, int64(2), *result.BrokenAt)
+		assert.Contains(t, result.Problem, "digest")
+		assert.Equal(t, 1, result.Checked)
+	})
+
+	t.Run("deleted event", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		as := &auditService{queries: mockQueries}
+		rows := auditChain(t, 3)
+
+		mockQueries.On("ListAuditEventsFromSequence", mock.Anything, mock.Anything).Return([]gen.AuditLog{rows[0], rows[2]}, nil)
+
+		result, err := as.VerifyChain(context.Background(), 1, 10)
+
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, int64(2), *result.BrokenAt)
+		assert.Contains(t, result.Problem, "missing")
+	})
+
+	t.Run("rehashed event breaks the next link", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		as := &auditService{queries: mockQueries}
+		rows := auditChain(t, 3)
+		rows[1] = auditRow(t, 2, rows[0].Hash, "tx_forged")
+
+		mockQueries.On("ListAuditEventsFromSequence", mock.Anything, mock.Anything).Return(rows, nil)
+
+		result, err := as.VerifyChain(context.Background(), 1, 10)
+
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, int64(3), *result.BrokenAt)
+		assert.Contains(t, result.Problem, "prev_hash")
+	})
+
+	t.Run("truncated tail", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		as := &auditService{queries: mockQueries}
+		rows := auditChain(t, 3)
+
+		mockQueries.On("ListAuditEventsFromSequence", mock.Anything, mock.Anything).Return(rows[:2], nil)
+		mockQueries.On("GetAuditChainHead", mock.Anything).Return(gen.AuditChainHead{ID: 1, Sequence: 3, Hash: rows[2].Hash}, nil)
+
+		result, err := as.VerifyChain(context.Background(), 1, 10)
+
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, int64(3), *result.BrokenAt)
+		assert.Contains(t, result.Problem, "chain head")
+	})
+
+	t.Run("continues from a later sequence", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		as := &auditService{queries: mockQueries}
+		rows := auditChain(t, 3)
+
+		mockQueries.On("ListAuditEventsFromSequence", mock.Anything, gen.ListAuditEventsFromSequenceParams{FromSequence: 1, RowLimit: 3}).Return(rows[:3], nil)
+
+		result, err := as.VerifyChain(context.Background(), 2, 2)
+
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.False(t, result.Complete)
+		assert.Equal(t, 2, result.Checked)
+		assert.Equal(t, int64(3), result.LastSequence)
+		mockQueries.AssertNotCalled(t, "GetAuditChainHead", mock.Anything)
+	})
+}
+
+func TestAuditService_ListEvents(t *testing.T) {
+	t.Run("pages newest first", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		as := &auditService{queries: mockQueries}
+		rows := auditChain(t, 3)
+		from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		mockQueries.On("ListAuditEvents", mock.Anything, gen.ListAuditEventsParams{
+			EntityType:     sql.NullString{String: "transaction", Valid: true},
+			CreatedFrom:    sql.NullTime{Time: from, Valid: true},
+			BeforeSequence: sql.NullInt64{Int64: 10, Valid: true},
+			RowLimit:       3,
+		}).Return([]gen.AuditLog{rows[2], rows[1], rows[0]}, nil)
+
+		page, err := as.ListEvents(context.Background(), models.AuditEventFilter{
+			EntityType: "transaction",
+			From:       &from,
+			Cursor:     "10",
+			Limit:      2,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, page.Events, 2)
+		assert.Equal(t, int64(3), page.Events[0].Sequence)
+		assert.Equal(t, "completed", page.Events[0].After["status"])
+		assert.Equal(t, "203.0.113.7", *page.Events[0].IPAddress)
+		require.NotNil(t, page.NextCursor)
+		assert.Equal(t, "2", *page.NextCursor)
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		as := &auditService{queries: mockQueries}
+
+		mockQueries.On("ListAuditEvents", mock.Anything, mock.MatchedBy(func(arg gen.ListAuditEventsParams) bool {
+			return arg.RowLimit == 21 && !arg.BeforeSequence.Valid
+		})).Return(auditChain(t, 1), nil)
+
+		page, err := as.ListEvents(context.Background(), models.AuditEventFilter{})
+
+		require.NoError(t, err)
+		assert.Len(t, page.Events, 1)
+		assert.Nil(t, page.NextCursor)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		as := &auditService{queries: new(mocks.MockQuerier)}
+
+		_, err := as.ListEvents(context.Background(), models.AuditEventFilter{Cursor: "abc"})
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+	})
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/bankdirectory"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+)
+
+type BankService interface {
+	ListBanks(ctx context.Context, search string, country string) ([]*models.Bank, error)
+	ValidateBank(ctx context.Context, bankCode string, currency money.Currency) error
+	PreferredProvider(ctx context.Context, bankCode string) string
+}
+
+type bankService struct {
+	queries gen.Querier
+}
+
+func newBankService(queries gen.Querier) BankService {
+	return &bankService{
+		queries: queries,
+	}
+}
+
+// ListBanks returns the active banks in the directory by name. search matches
+// a bank code exactly or any part of a name, and country narrows the list to
+// one ISO 3166-1 alpha-2 code. Both are optional.
+func (bs *bankService) ListBanks(ctx context.Context, search string, country string) ([]*models.Bank, error) {
+	rows, err := bs.queries.ListBanks(ctx, gen.ListBanksParams{
+		Search:  sql.NullString{String: search, Valid: search != ""},
+		Country: sql.NullString{String: country, Valid: country != ""},
+	})
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("list banks: %w", err))
+	}
+
+	banks := make([]*models.Bank, 0, len(rows))
+	for _, row := range rows {
+		banks = append(banks, mapBank(row))
+	}
+	return banks, nil
+}
+
+// ValidateBank rejects a bank code that is not an active directory entry, and
+// a bank that does not accept currency. An empty currency is not checked.
+func (bs *bankService) ValidateBank(ctx context.Context, bankCode string, currency money.Currency) error {
+	row, err := bs.queries.GetBank(ctx, bankCode)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return utils.BadRequestErr("unknown bank_code")
+		}
+		return utils.ServerErr(fmt.Errorf("get bank: %w", err))
+	}
+	if !row.Active {
+		return utils.BadRequestErr("unknown bank_code")
+	}
+
+	bank := mapBank(row)
+	if currency != "" && !bank.SupportsCurrency(currency.String()) {
+		return utils.BadRequestErr(fmt.Sprintf("bank %s does not accept %s", bank.Code, currency))
+	}
+	return nil
+}
+
+// PreferredProvider implements providers.BankRouter. A bank that cannot be
+// looked up has no preference, so routing falls back to the currency.
+func (bs *bankService) PreferredProvider(ctx context.Context, bankCode string) string {
+	row, err := bs.queries.GetBank(ctx, bankCode)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			utils.Logger.Warn().Err(err).Str("bank_code", bankCode).Msg("failed to look up bank for routing")
+		}
+		return ""
+	}
+	if !row.Active || !row.PreferredProvider.Valid {
+		return ""
+	}
+	return row.PreferredProvider.String
+}
+
+// importBankDirectory makes the banks table match banks: every bank is
+// inserted or updated and marked active, and active banks missing from the
+// list are deactivated. It returns the number deactivated.
+func importBankDirectory(ctx context.Context, queries gen.Querier, banks []bankdirectory.Bank) (int64, error) {
+	codes := make([]string, 0, len(banks))
+	for _, bank := range banks {
+		if err := queries.UpsertBank(ctx, gen.UpsertBankParams{
+			Code:              bank.Code,
+			Name:              bank.Name,
+			Country:           bank.Country,
+			Currencies:        bank.Currencies,
+			PreferredProvider: sql.NullString{String: bank.PreferredProvider, Valid: bank.PreferredProvider != ""},
+		}); err != nil {
+			return 0, fmt.Errorf("upsert bank %s: %w", bank.Code, err)
+		}
+		codes = append(codes, bank.Code)
+	}
+
+	deactivated, err := queries.DeactivateBanksNotIn(ctx, codes)
+	if err != nil {
+		return 0, fmt.Errorf("deactivate banks: %w", err)
+	}
+	return deactivated, nil
+}
+
+func mapBank(row gen.Bank) *models.Bank {
+	bank := &models.Bank{
+		Code:       row.Code,
+		Name:       row.Name,
+		Country:    row.Country,
+		Currencies: row.Currencies,
+	}
+	if row.PreferredProvider.Valid {
+		bank.PreferredProvider = &row.PreferredProvider.String
+	}
+	return bank
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/bankdirectory"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// knownBanks accepts every bank code and currency, for tests that are not
+// about the bank directory.
+func knownBanks() *mocks.MockBankService {
+	banks := new(mocks.MockBankService)
+	banks.On("ValidateBank", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	banks.On("PreferredProvider", mock.Anything, mock.Anything).Return("")
+	return banks
+}
+
+func testBank(code string, active bool) gen.Bank {
+	return gen.Bank{
+		Code:              code,
+		Name:              "Test Bank",
+		Country:           "US",
+		Currencies:        []string{"USD", "EUR"},
+		PreferredProvider: sql.NullString{String: "dlocal", Valid: true},
+		Active:            active,
+	}
+}
+
+func TestBankService_ValidateBank(t *testing.T) {
+	mockQueries := new(mocks.MockQuerier)
+	mockQueries.On("GetBank", mock.Anything, "044").Return(testBank("044", true), nil)
+	mockQueries.On("GetBank", mock.Anything, "070").Return(testBank("070", false), nil)
+	mockQueries.On("GetBank", mock.Anything, "999").Return(gen.Bank{}, sql.ErrNoRows)
+
+	bs := &bankService{queries: mockQueries}
+	ctx := context.Background()
+
+	assert.NoError(t, bs.ValidateBank(ctx, "044", money.USD))
+	assert.NoError(t, bs.ValidateBank(ctx, "044", ""), "an empty currency is not checked")
+
+	err := bs.ValidateBank(ctx, "044", money.GBP)
+	assert.True(t, errors.Is(err, utils.ErrBadRequest))
+	assert.Contains(t, err.Error(), "does not accept GBP")
+
+	for _, code := range []string{"070", "999"} {
+		err := bs.ValidateBank(ctx, code, money.USD)
+		assert.True(t, errors.Is(err, utils.ErrBadRequest), code)
+		assert.Contains(t, err.Error(), "unknown bank_code")
+	}
+}
+
+func TestBankService_PreferredProvider(t *testing.T) {
+	mockQueries := new(mocks.MockQuerier)
+	mockQueries.On("GetBank", mock.Anything, "044").Return(testBank("044", true), nil)
+	mockQueries.On("GetBank", mock.Anything, "070").Return(testBank("070", false), nil)
+	mockQueries.On("GetBank", mock.Anything, "999").Return(gen.Bank{}, sql.ErrNoRows)
+	mockQueries.On("GetBank", mock.Anything, "500").Return(gen.Bank{}, errors.New("connection refused"))
+
+	bs := &bankService{queries: mockQueries}
+	ctx := context.Background()
+
+	assert.Equal(t, "dlocal", bs.PreferredProvider(ctx, "044"))
+	assert.Empty(t, bs.PreferredProvider(ctx, "070"), "an inactive bank has no preference")
+	assert.Empty(t, bs.PreferredProvider(ctx, "999"))
+	assert.Empty(t, bs.PreferredProvider(ctx, "500"))
+}
+
+func TestBankService_ListBanks(t *testing.T) {
+	mockQueries := new(mocks.MockQuerier)
+	mockQueries.On("ListBanks", mock.Anything, gen.ListBanksParams{
+		Search: sql.NullString{String: "test", Valid: true},
+	}).Return([]gen.Bank{testBank("044", true)}, nil)
+
+	bs := &bankService{queries: mockQueries}
+
+	banks, err := bs.ListBanks(context.Background(), "test", "")
+	require.NoError(t, err)
+	require.Len(t, banks, 1)
+	assert.Equal(t, "044", banks[0].Code)
+	assert.Equal(t, []string{"USD", "EUR"}, banks[0].Currencies)
+	require.NotNil(t, banks[0].PreferredProvider)
+	assert.Equal(t, "dlocal", *banks[0].PreferredProvider)
+}
+
+func TestImportBankDirectory(t *testing.T) {
+	mockQueries := new(mocks.MockQuerier)
+	mockQueries.On("UpsertBank", mock.Anything, gen.UpsertBankParams{
+		Code:              "044",
+		Name:              "Test Bank",
+		Country:           "US",
+		Currencies:        []string{"USD"},
+		PreferredProvider: sql.NullString{String: "currencycloud", Valid: true},
+	}).Return(nil)
+	mockQueries.On("UpsertBank", mock.Anything, gen.UpsertBankParams{
+		Code:       "058",
+		Name:       "Harbor Savings",
+		Country:    "GB",
+		Currencies: []string{"GBP"},
+	}).Return(nil)
+	mockQueries.On("DeactivateBanksNotIn", mock.Anything, []string{"044", "058"}).Return(int64(2), nil)
+
+	deactivated, err := importBankDirectory(context.Background(), mockQueries, []bankdirectory.Bank{
+		{Code: "044", Name: "Test Bank", Country: "US", Currencies: []string{"USD"}, PreferredProvider: "currencycloud"},
+		{Code: "058", Name: "Harbor Savings", Country: "GB", Currencies: []string{"GBP"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deactivated)
+	mockQueries.AssertExpectations(t)
+}
@@ -0,0 +1,351 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/lib/pq"
+)
+
+type BeneficiaryService interface {
+	CreateBeneficiary(ctx context.Context, userID string, nickname string, accountNumber string, bankCode string) (*models.Beneficiary, error)
+	ListBeneficiaries(ctx context.Context, userID string) ([]*models.Beneficiary, error)
+	GetBeneficiary(ctx context.Context, userID string, beneficiaryID string) (*models.Beneficiary, error)
+	UpdateBeneficiary(ctx context.Context, userID string, beneficiaryID string, update models.BeneficiaryUpdate) (*models.Beneficiary, error)
+	DeleteBeneficiary(ctx context.Context, userID string, beneficiaryID string) error
+}
+
+type beneficiaryService struct {
+	queries     gen.Querier
+	db          *sql.DB
+	nameEnquiry NameEnquiryService
+	fields      fieldCipher
+}
+
+func newBeneficiaryService(queries gen.Querier, db *sql.DB, nameEnquiry NameEnquiryService, fields fieldCipher) BeneficiaryService {
+	return &beneficiaryService{
+		queries:     queries,
+		db:          db,
+		nameEnquiry: nameEnquiry,
+		fields:      fields,
+	}
+}
+
+// CreateBeneficiary saves an account for the user after name enquiry confirms
+// it exists. The account name, currency and country are taken from the
+// enquiry rather than from the caller.
+func (bs *beneficiaryService) CreateBeneficiary(ctx context.Context, userID string, nickname string, accountNumber string, bankCode string) (*models.Beneficiary, error) {
+	if err := bs.checkNotSaved(ctx, userID, accountNumber, bankCode, ""); err != nil {
+		return nil, err
+	}
+
+	enquiry, err := bs.nameEnquiry.EnquireAccountName(ctx, accountNumber, bankCode)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := bs.fields.sealBeneficiaryAccountNumber(accountNumber)
+	if err != nil {
+		return nil, utils.ServerErr(err)
+	}
+
+	tx, err := bs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := bs.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = bs.queries
+	}
+
+	row, err := queries.CreateBeneficiary(ctx, gen.CreateBeneficiaryParams{
+		UserID:            userID,
+		Nickname:          sql.NullString{String: nickname, Valid: nickname != ""},
+		AccountNumber:     sealed,
+		AccountNumberHash: bs.fields.beneficiaryAccountIndex(accountNumber),
+		BankCode:          bankCode,
+		AccountName:       enquiry.AccountName,
+		Currency:          enquiry.Currency.String(),
+		Country:           sql.NullString{String: enquiry.Country, Valid: enquiry.Country != ""},
+		IsInternal:        enquiry.IsInternal,
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, utils.DuplicateKeyErr("beneficiary already saved")
+		}
+		return nil, utils.ServerErr(fmt.Errorf("create beneficiary: %w", err))
+	}
+
+	if err := recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "beneficiary.created",
+		EntityType: auditEntityBeneficiary,
+		EntityID:   row.ID,
+		After:      beneficiarySnapshot(row),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	return bs.mapBeneficiary(row)
+}
+
+func (bs *beneficiaryService) ListBeneficiaries(ctx context.Context, userID string) ([]*models.Beneficiary, error) {
+	rows, err := bs.queries.ListBeneficiariesByUser(ctx, userID)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("list beneficiaries: %w", err))
+	}
+
+	beneficiaries := make([]*models.Beneficiary, 0, len(rows))
+	for _, row := range rows {
+		beneficiary, err := bs.mapBeneficiary(row)
+		if err != nil {
+			return nil, err
+		}
+		beneficiaries = append(beneficiaries, beneficiary)
+	}
+	return beneficiaries, nil
+}
+
+// GetBeneficiary returns one of the user's saved accounts. Another user's
+// beneficiary is reported as not found.
+func (bs *beneficiaryService) GetBeneficiary(ctx context.Context, userID string, beneficiaryID string) (*models.Beneficiary, error) {
+	row, err := bs.getBeneficiaryRow(ctx, bs.queries, userID, beneficiaryID)
+	if err != nil {
+		return nil, err
+	}
+	return bs.mapBeneficiary(row)
+}
+
+// UpdateBeneficiary changes a saved account. A new account number or bank
+// code is verified by name enquiry again and restarts the new-beneficiary
+// cool-down; a nickname change alone does not.
+func (bs *beneficiaryService) UpdateBeneficiary(ctx context.Context, userID string, beneficiaryID string, update models.BeneficiaryUpdate) (*models.Beneficiary, error) {
+	existing, err := bs.getBeneficiaryRow(ctx, bs.queries, userID, beneficiaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentAccountNumber, err := bs.fields.openBeneficiaryAccountNumber(existing.AccountNumber)
+	if err != nil {
+		return nil, utils.ServerErr(err)
+	}
+
+	params := gen.UpdateBeneficiaryParams{
+		Nickname:          existing.Nickname,
+		AccountNumber:     existing.AccountNumber,
+		AccountNumberHash: existing.AccountNumberHash,
+		BankCode:          existing.BankCode,
+		AccountName:       existing.AccountName,
+		Currency:          existing.Currency,
+		Country:           existing.Country,
+		IsInternal:        existing.IsInternal,
+		VerifiedAt:        existing.VerifiedAt,
+		ID:                existing.ID,
+		UserID:            userID,
+	}
+	if update.Nickname != nil {
+		params.Nickname = sql.NullString{String: *update.Nickname, Valid: *update.Nickname != ""}
+	}
+
+	accountNumber, bankCode := currentAccountNumber, existing.BankCode
+	if update.AccountNumber != nil {
+		accountNumber = *update.AccountNumber
+	}
+	if update.BankCode != nil {
+		bankCode = *update.BankCode
+	}
+
+	if accountNumber != currentAccountNumber || bankCode != existing.BankCode {
+		if err := bs.checkNotSaved(ctx, userID, accountNumber, bankCode, existing.ID); err != nil {
+			return nil, err
+		}
+
+		enquiry, err := bs.nameEnquiry.EnquireAccountName(ctx, accountNumber, bankCode)
+		if err != nil {
+			return nil, err
+		}
+
+		sealed, err := bs.fields.sealBeneficiaryAccountNumber(accountNumber)
+		if err != nil {
+			return nil, utils.ServerErr(err)
+		}
+
+		params.AccountNumber = sealed
+		params.AccountNumberHash = bs.fields.beneficiaryAccountIndex(accountNumber)
+		params.BankCode = bankCode
+		params.AccountName = enquiry.AccountName
+		params.Currency = enquiry.Currency.String()
+		params.Country = sql.NullString{String: enquiry.Country, Valid: enquiry.Country != ""}
+		params.IsInternal = enquiry.IsInternal
+		params.VerifiedAt = time.Now()
+	}
+
+	tx, err := bs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := bs.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = bs.queries
+	}
+
+	row, err := queries.UpdateBeneficiary(ctx, params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.NotFoundErr("beneficiary not found")
+		}
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, utils.DuplicateKeyErr("beneficiary already saved")
+		}
+		return nil, utils.ServerErr(fmt.Errorf("update beneficiary: %w", err))
+	}
+
+	if err := recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "beneficiary.updated",
+		EntityType: auditEntityBeneficiary,
+		EntityID:   row.ID,
+		Before:     beneficiarySnapshot(existing),
+		After:      beneficiarySnapshot(row),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	return bs.mapBeneficiary(row)
+}
+
+// DeleteBeneficiary removes a saved account. Transfers already made to it keep
+// their beneficiary_id.
+func (bs *beneficiaryService) DeleteBeneficiary(ctx context.Context, userID string, beneficiaryID string) error {
+	tx, err := bs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := bs.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = bs.queries
+	}
+
+	existing, err := bs.getBeneficiaryRow(ctx, queries, userID, beneficiaryID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := queries.DeleteBeneficiary(ctx, gen.DeleteBeneficiaryParams{
+		ID:     existing.ID,
+		UserID: userID,
+	})
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("delete beneficiary: %w", err))
+	}
+	if deleted == 0 {
+		return utils.NotFoundErr("beneficiary not found")
+	}
+
+	if err := recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "beneficiary.deleted",
+		EntityType: auditEntityBeneficiary,
+		EntityID:   existing.ID,
+		Before:     beneficiarySnapshot(existing),
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+	return nil
+}
+
+func (bs *beneficiaryService) getBeneficiaryRow(ctx context.Context, queries gen.Querier, userID string, beneficiaryID string) (gen.Beneficiary, error) {
+	row, err := queries.GetBeneficiaryForUser(ctx, gen.GetBeneficiaryForUserParams{
+		ID:     beneficiaryID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return gen.Beneficiary{}, utils.NotFoundErr("beneficiary not found")
+		}
+		return gen.Beneficiary{}, utils.ServerErr(fmt.Errorf("get beneficiary: %w", err))
+	}
+	return row, nil
+}
+
+// checkNotSaved rejects an account the user has already saved, other than as
+// exceptID. The unique index still catches a concurrent save.
+func (bs *beneficiaryService) checkNotSaved(ctx context.Context, userID string, accountNumber string, bankCode string, exceptID string) error {
+	existing, err := bs.queries.GetBeneficiaryByAccount(ctx, bs.fields.beneficiaryLookup(userID, accountNumber, bankCode))
+	if err == nil && existing.ID != exceptID {
+		return utils.DuplicateKeyErr("beneficiary already saved")
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return utils.ServerErr(fmt.Errorf("check beneficiary: %w", err))
+	}
+	return nil
+}
+
+func (bs *beneficiaryService) mapBeneficiary(row gen.Beneficiary) (*models.Beneficiary, error) {
+	accountNumber, err := bs.fields.openBeneficiaryAccountNumber(row.AccountNumber)
+	if err != nil {
+		return nil, utils.ServerErr(err)
+	}
+
+	beneficiary := &models.Beneficiary{
+		ID:            row.ID,
+		UserID:        row.UserID,
+		AccountNumber: accountNumber,
+		BankCode:      row.BankCode,
+		AccountName:   row.AccountName,
+		Currency:      row.Currency,
+		IsInternal:    row.IsInternal,
+		VerifiedAt:    row.VerifiedAt,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+	}
+	if row.Nickname.Valid {
+		beneficiary.Nickname = &row.Nickname.String
+	}
+	if row.Country.Valid {
+		beneficiary.Country = &row.Country.String
+	}
+	return beneficiary, nil
+}
+
+// beneficiarySnapshot is what the audit log records about a beneficiary. The
+// account number is left out so the log holds no plaintext copy of it.
+func beneficiarySnapshot(row gen.Beneficiary) map[string]any {
+	return map[string]any{
+		"nickname":     row.Nickname.String,
+		"bank_code":    row.BankCode,
+		"account_name": row.AccountName,
+		"currency":     row.Currency,
+		"is_internal":  row.IsInternal,
+		"verified_at":  row.VerifiedAt,
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testBeneficiary(t *testing.T, fields fieldCipher) gen.Beneficiary {
+	t.Helper()
+	sealed, err := fields.sealBeneficiaryAccountNumber("9999999999")
+	require.NoError(t, err)
+	now := time.Now()
+	return gen.Beneficiary{
+		ID:                "ben_1",
+		UserID:            "user_1",
+		Nickname:          sql.NullString{String: "Landlord", Valid: true},
+		AccountNumber:     sealed,
+		AccountNumberHash: fields.beneficiaryAccountIndex("9999999999"),
+		BankCode:          "044",
+		AccountName:       "Mock Account Holder 9999",
+		Currency:          "USD",
+		Country:           sql.NullString{String: "US", Valid: true},
+		VerifiedAt:        now,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+}
+
+func TestBeneficiaryService_CreateBeneficiary(t *testing.T) {
+	fields := testFieldCipher(t, "k1")
+
+	t.Run("rejects an account already saved", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		bs := &beneficiaryService{queries: mockQueries, nameEnquiry: stubNameEnquiry{err: errors.New("unexpected name enquiry")}, fields: fields}
+
+		mockQueries.On("GetBeneficiaryByAccount", mock.Anything, fields.beneficiaryLookup("user_1", "9999999999", "044")).Return(testBeneficiary(t, fields), nil)
+
+		_, err := bs.CreateBeneficiary(context.Background(), "user_1", "", "9999999999", "044")
+
+		assert.ErrorIs(t, err, utils.ErrDuplicatedKey)
+		mockQueries.AssertNotCalled(t, "CreateBeneficiary", mock.Anything, mock.Anything)
+	})
+
+	t.Run("is not saved when name enquiry fails", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		bs := &beneficiaryService{queries: mockQueries, nameEnquiry: stubNameEnquiry{err: utils.BadRequestErr("account not found")}, fields: fields}
+
+		mockQueries.On("GetBeneficiaryByAccount", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+
+		_, err := bs.CreateBeneficiary(context.Background(), "user_1", "", "0000000000", "044")
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		mockQueries.AssertNotCalled(t, "CreateBeneficiary", mock.Anything, mock.Anything)
+	})
+}
+
+func TestBeneficiaryService_GetBeneficiary(t *testing.T) {
+	fields := testFieldCipher(t, "k1")
+
+	t.Run("decrypts the account number", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		bs := &beneficiaryService{queries: mockQueries, fields: fields}
+
+		mockQueries.On("GetBeneficiaryForUser", mock.Anything, gen.GetBeneficiaryForUserParams{ID: "ben_1", UserID: "user_1"}).Return(testBeneficiary(t, fields), nil)
+
+		beneficiary, err := bs.GetBeneficiary(context.Background(), "user_1", "ben_1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "9999999999", beneficiary.AccountNumber)
+		assert.Equal(t, "Mock Account Holder 9999", beneficiary.AccountName)
+		require.NotNil(t, beneficiary.Nickname)
+		assert.Equal(t, "Landlord", *beneficiary.Nickname)
+		require.NotNil(t, beneficiary.Country)
+		assert.Equal(t, "US", *beneficiary.Country)
+	})
+
+	t.Run("another user's beneficiary is not found", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		bs := &beneficiaryService{queries: mockQueries, fields: fields}
+
+		mockQueries.On("GetBeneficiaryForUser", mock.Anything, gen.GetBeneficiaryForUserParams{ID: "ben_1", UserID: "user_2"}).Return(nil, sql.ErrNoRows)
+
+		_, err := bs.GetBeneficiary(context.Background(), "user_2", "ben_1")
+
+		assert.ErrorIs(t, err, utils.ErrNotFound)
+	})
+}
+
+func TestBeneficiaryService_ListBeneficiaries(t *testing.T) {
+	fields := testFieldCipher(t, "k1")
+	mockQueries := new(mocks.MockQuerier)
+	bs := &beneficiaryService{queries: mockQueries, fields: fields}
+
+	mockQueries.On("ListBeneficiariesByUser", mock.Anything, "user_1").Return([]gen.Beneficiary{testBeneficiary(t, fields)}, nil)
+
+	beneficiaries, err := bs.ListBeneficiaries(context.Background(), "user_1")
+
+	require.NoError(t, err)
+	require.Len(t, beneficiaries, 1)
+	assert.Equal(t, "9999999999", beneficiaries[0].AccountNumber)
+}
+
+func TestBeneficiaryService_UpdateBeneficiary(t *testing.T) {
+	fields := testFieldCipher(t, "k1")
+
+	t.Run("rejects an account saved as another beneficiary", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		bs := &beneficiaryService{queries: mockQueries, nameEnquiry: stubNameEnquiry{err: errors.New("unexpected name enquiry")}, fields: fields}
+
+		other := testBeneficiary(t, fields)
+		other.ID = "ben_2"
+		mockQueries.On("GetBeneficiaryForUser", mock.Anything, gen.GetBeneficiaryForUserParams{ID: "ben_1", UserID: "user_1"}).Return(testBeneficiary(t, fields), nil)
+		mockQueries.On("GetBeneficiaryByAccount", mock.Anything, fields.beneficiaryLookup("user_1", "8888888888", "044")).Return(other, nil)
+
+		accountNumber := "8888888888"
+		_, err := bs.UpdateBeneficiary(context.Background(), "user_1", "ben_1", models.BeneficiaryUpdate{AccountNumber: &accountNumber})
+
+		assert.ErrorIs(t, err, utils.ErrDuplicatedKey)
+		mockQueries.AssertNotCalled(t, "UpdateBeneficiary", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown beneficiary", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		bs := &beneficiaryService{queries: mockQueries, fields: fields}
+
+		mockQueries.On("GetBeneficiaryForUser", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+
+		nickname := "Rent"
+		_, err := bs.UpdateBeneficiary(context.Background(), "user_1", "ben_missing", models.BeneficiaryUpdate{Nickname: &nickname})
+
+		assert.ErrorIs(t, err, utils.ErrNotFound)
+	})
+}
+
+func TestPaymentService_TransferToBeneficiary(t *testing.T) {
+	fields := testFieldCipher(t, "k1")
+	amount := money.NewMoney(10000, money.USD)
+
+	t.Run("beneficiary and account together are rejected", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &paymentService{queries: mockQueries, beneficiaries: &beneficiaryService{queries: mockQueries, fields: fields}}
+
+		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "key_1").Return(nil, sql.ErrNoRows)
+
+		_, err := ps.CreateInternalTransfer(context.Background(), "user_1", "9999999999", "044", "", "ben_1", money.USD, amount, "key_1")
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		mockQueries.AssertNotCalled(t, "GetBeneficiaryForUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("internal transfer to an external beneficiary", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &paymentService{queries: mockQueries, beneficiaries: &beneficiaryService{queries: mockQueries, fields: fields}}
+
+		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "key_1").Return(nil, sql.ErrNoRows)
+		mockQueries.On("GetBeneficiaryForUser", mock.Anything, gen.GetBeneficiaryForUserParams{ID: "ben_1", UserID: "user_1"}).Return(testBeneficiary(t, fields), nil)
+
+		_, err := ps.CreateInternalTransfer(context.Background(), "user_1", "", "", "", "ben_1", money.USD, amount, "key_1")
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+	})
+
+	t.Run("unknown beneficiary", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &paymentService{queries: mockQueries, beneficiaries: &beneficiaryService{queries: mockQueries, fields: fields}}
+
+		mockQueries.On("GetBeneficiaryForUser", mock.Anything, gen.GetBeneficiaryForUserParams{ID: "ben_missing", UserID: "user_1"}).Return(nil, sql.ErrNoRows)
+
+		_, err := ps.CreateExternalTransfer(context.Background(), "user_1", "", "", "", "ben_missing", money.USD, amount, "key_1")
+
+		assert.ErrorIs(t, err, utils.ErrNotFound)
+	})
+}
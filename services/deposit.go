@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/lib/pq"
+)
+
+type DepositService interface {
+	CreditDeposit(ctx context.Context, deposit models.Deposit) (*models.Transaction, error)
+}
+
+type depositService struct {
+	queries gen.Querier
+	db      *sql.DB
+	wallet  WalletService
+	ledger  LedgerService
+	fields  fieldCipher
+}
+
+func newDepositService(queries gen.Querier, db *sql.DB, wallet WalletService, ledger LedgerService, fields fieldCipher) DepositService {
+	return &depositService{
+		queries: queries,
+		db:      db,
+		wallet:  wallet,
+		ledger:  ledger,
+		fields:  fields,
+	}
+}
+
+// CreditDeposit records money paid into a wallet's virtual account as an
+// external transaction with no source wallet. A notice already recorded under
+// the same provider reference returns the existing transaction. A wallet that
+// is closed, frozen for credits or in another currency is not credited; the
+// deposit is recorded as failed so it can be returned to the sender.
+func (ds *depositService) CreditDeposit(ctx context.Context, deposit models.Deposit) (*models.Transaction, error) {
+	if deposit.ProviderName == "" || deposit.ProviderReference == "" {
+		return nil, utils.BadRequestErr("deposit provider and reference are required")
+	}
+	if !deposit.Amount.IsPositive() {
+		return nil, utils.BadRequestErr("deposit amount must be positive")
+	}
+
+	existing, err := ds.getDeposit(ctx, ds.queries, deposit)
+	if err == nil {
+		return existing, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	bankAccount, err := ds.queries.GetBankAccountByAccountAndBankCode(ctx, ds.fields.bankAccountLookup(deposit.AccountNumber, deposit.BankCode))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.NotFoundErr("deposit account not found")
+		}
+		return nil, utils.ServerErr(fmt.Errorf("get deposit bank account: %w", err))
+	}
+
+	if bankAccount.Provider != deposit.ProviderName {
+		return nil, utils.BadRequestErr("deposit account is not held with this provider")
+	}
+
+	wallet, err := ds.wallet.GetWalletByBankAccount(ctx, bankAccount.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := ds.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := ds.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ds.queries
+	}
+
+	lockedWallet, err := ds.wallet.LockWalletForUpdate(ctx, tx, wallet.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := models.TransactionStatusCompleted
+	var failureReason sql.NullString
+	if err := checkDeposit(lockedWallet, deposit.Amount.Currency); err != nil {
+		status = models.TransactionStatusFailed
+		failureReason = sql.NullString{String: err.Error(), Valid: true}
+	}
+
+	traceID := utils.TraceIDFromContext(ctx)
+	transaction, err := queries.CreateDepositTransaction(ctx, gen.CreateDepositTransactionParams{
+		IdempotencyKey:    depositIdempotencyKey(deposit),
+		TraceID:           sql.NullString{String: traceID, Valid: traceID != ""},
+		ToWalletID:        sql.NullString{String: lockedWallet.ID, Valid: true},
+		Amount:            deposit.Amount.Amount,
+		Currency:          deposit.Amount.Currency.String(),
+		Status:            string(status),
+		ProviderName:      sql.NullString{String: deposit.ProviderName, Valid: true},
+		ProviderReference: sql.NullString{String: deposit.ProviderReference, Valid: true},
+		FailureReason:     failureReason,
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			// Another worker recorded the same notice first.
+			_ = tx.Rollback()
+			return ds.getDeposit(ctx, ds.queries, deposit)
+		}
+		return nil, utils.ServerErr(fmt.Errorf("create deposit transaction: %w", err))
+	}
+
+	if err := recordTransactionCreated(ctx, queries, transaction); err != nil {
+		return nil, err
+	}
+
+	if status == models.TransactionStatusCompleted {
+		if err := ds.ledger.CreateExternalSystemDebitEntry(ctx, tx, transaction.ID, -deposit.Amount.Amount, deposit.Amount.Currency); err != nil {
+			return nil, err
+		}
+
+		if err := ds.ledger.CreateCreditEntry(ctx, tx, lockedWallet.ID, transaction.ID, deposit.Amount.Amount, deposit.Amount.Currency); err != nil {
+			return nil, err
+		}
+
+		newBalance, err := money.NewMoney(lockedWallet.Balance, deposit.Amount.Currency).Add(deposit.Amount)
+		if err != nil {
+			return nil, utils.ServerErr(fmt.Errorf("calculate new wallet balance: %w", err))
+		}
+
+		if err := queries.UpdateWalletBalance(ctx, gen.UpdateWalletBalanceParams{
+			Balance: newBalance.Amount,
+			ID:      lockedWallet.ID,
+		}); err != nil {
+			return nil, utils.ServerErr(fmt.Errorf("update wallet balance: %w", err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	utils.Logger.Info().
+		Str("transaction_id", transaction.ID).
+		Str("wallet_id", lockedWallet.ID).
+		Str("provider", deposit.ProviderName).
+		Str("provider_reference", deposit.ProviderReference).
+		Int64("amount", deposit.Amount.Amount).
+		Str("currency", deposit.Amount.Currency.String()).
+		Str("status", string(status)).
+		Str("trace_id", traceID).
+		Msg("deposit recorded")
+
+	return mapTransaction(transaction), nil
+}
+
+func (ds *depositService) getDeposit(ctx context.Context, queries gen.Querier, deposit models.Deposit) (*models.Transaction, error) {
+	transaction, err := queries.GetDepositByProviderReference(ctx, gen.GetDepositByProviderReferenceParams{
+		ProviderName:      sql.NullString{String: deposit.ProviderName, Valid: true},
+		ProviderReference: sql.NullString{String: deposit.ProviderReference, Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, utils.ServerErr(fmt.Errorf("get deposit: %w", err))
+	}
+	return mapTransaction(transaction), nil
+}
+
+// checkDeposit reports why a wallet cannot take a deposit, or nil if it can.
+func checkDeposit(wallet *models.Wallet, currency money.Currency) error {
+	if wallet.Currency != currency.String() {
+		return fmt.Errorf("deposit currency %s does not match %s wallet", currency, wallet.Currency)
+	}
+	if !wallet.AllowsCredits() {
+		return fmt.Errorf("wallet is %s", wallet.Status)
+	}
+	return nil
+}
+
+// depositIdempotencyKey fills the transaction's required idempotency key. The
+// provider reference is only unique per provider, so both are included.
+func depositIdempotencyKey(deposit models.Deposit) string {
+	return fmt.Sprintf("deposit:%s:%s", deposit.ProviderName, deposit.ProviderReference)
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/queue"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testDeposit() models.Deposit {
+	return models.Deposit{
+		ProviderName:      "currencycloud",
+		ProviderReference: "CC-CREDIT-1",
+		AccountNumber:     "3000000012",
+		BankCode:          "044",
+		Amount:            money.NewMoney(10000, money.USD),
+	}
+}
+
+func TestDepositService_CreditDeposit(t *testing.T) {
+	depositLookup := gen.GetDepositByProviderReferenceParams{
+		ProviderName:      sql.NullString{String: "currencycloud", Valid: true},
+		ProviderReference: sql.NullString{String: "CC-CREDIT-1", Valid: true},
+	}
+
+	t.Run("amount must be positive", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ds := &depositService{queries: mockQueries}
+
+		deposit := testDeposit()
+		deposit.Amount = money.NewMoney(0, money.USD)
+
+		_, err := ds.CreditDeposit(context.Background(), deposit)
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		mockQueries.AssertNotCalled(t, "GetDepositByProviderReference", mock.Anything, mock.Anything)
+	})
+
+	t.Run("repeated notice returns the recorded deposit", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ds := &depositService{queries: mockQueries}
+
+		mockQueries.On("GetDepositByProviderReference", mock.Anything, depositLookup).Return(gen.Transaction{
+			ID:         "txn_deposit",
+			ToWalletID: sql.NullString{String: "wallet_user1_usd", Valid: true},
+			Type:       "external",
+			Amount:     10000,
+			Currency:   "USD",
+			Status:     "completed",
+		}, nil)
+
+		transaction, err := ds.CreditDeposit(context.Background(), testDeposit())
+
+		require.NoError(t, err)
+		assert.Equal(t, "txn_deposit", transaction.ID)
+		assert.Equal(t, models.TransactionStatusCompleted, transaction.Status)
+		mockQueries.AssertNotCalled(t, "GetBankAccountByAccountAndBankCode", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ds := &depositService{queries: mockQueries}
+
+		mockQueries.On("GetDepositByProviderReference", mock.Anything, depositLookup).Return(nil, sql.ErrNoRows)
+		mockQueries.On("GetBankAccountByAccountAndBankCode", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+
+		_, err := ds.CreditDeposit(context.Background(), testDeposit())
+
+		assert.ErrorIs(t, err, utils.ErrNotFound)
+	})
+
+	t.Run("account held with another provider", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		wallets := new(mocks.MockWalletService)
+		ds := &depositService{queries: mockQueries, wallet: wallets}
+
+		mockQueries.On("GetDepositByProviderReference", mock.Anything, depositLookup).Return(nil, sql.ErrNoRows)
+		mockQueries.On("GetBankAccountByAccountAndBankCode", mock.Anything, mock.Anything).Return(gen.BankAccount{
+			ID:       "bank_acc_user1_usd_dl",
+			Currency: "USD",
+			Provider: "dlocal",
+		}, nil)
+
+		_, err := ds.CreditDeposit(context.Background(), testDeposit())
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		wallets.AssertNotCalled(t, "GetWalletByBankAccount", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCheckDeposit(t *testing.T) {
+	debits := models.FreezeDebits
+	credits := models.FreezeCredits
+
+	assert.NoError(t, checkDeposit(&models.Wallet{Currency: "USD", Status: models.WalletStatusActive}, money.USD))
+	assert.NoError(t, checkDeposit(&models.Wallet{Currency: "USD", Status: models.WalletStatusFrozen, FrozenDirection: &debits}, money.USD))
+
+	assert.ErrorContains(t, checkDeposit(&models.Wallet{Currency: "USD", Status: models.WalletStatusActive}, money.EUR), "does not match USD wallet")
+	assert.ErrorContains(t, checkDeposit(&models.Wallet{Currency: "USD", Status: models.WalletStatusFrozen, FrozenDirection: &credits}, money.USD), "wallet is frozen")
+	assert.ErrorContains(t, checkDeposit(&models.Wallet{Currency: "USD", Status: models.WalletStatusClosed}, money.USD), "wallet is closed")
+}
+
+func TestParseDeposit(t *testing.T) {
+	payload := queue.WebhookJobPayload{
+		ProviderName:      "currencycloud",
+		EventType:         models.WebhookEventCreditReceived,
+		ProviderReference: "CC-CREDIT-1",
+		Payload:           []byte(`{"event_type":"credit.received","account_number":"3000000012","bank_code":"044","amount":{"amount":100,"currency":"USD"}}`),
+	}
+
+	deposit, err := parseDeposit(payload)
+
+	require.NoError(t, err)
+	assert.Equal(t, testDeposit(), deposit)
+
+	payload.Payload = []byte(`{"account_number":"3000000012","bank_code":"044","amount":{"amount":100,"currency":"NGN"}}`)
+	_, err = parseDeposit(payload)
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+}
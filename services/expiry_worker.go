@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+)
+
+const (
+	defaultTransactionTTL    = 10 * time.Minute
+	defaultExpirySweepPeriod = time.Minute
+	expiryBatchSize          = int32(100)
+)
+
+type ExpiryWorker interface {
+	StartWorker(ctx context.Context) error
+}
+
+type expiryWorker struct {
+	queries  *gen.Queries
+	db       *sql.DB
+	ttl      time.Duration
+	interval time.Duration
+}
+
+func newExpiryWorker(queries *gen.Queries, db *sql.DB, ttl time.Duration, interval time.Duration) ExpiryWorker {
+	if ttl <= 0 {
+		ttl = defaultTransactionTTL
+	}
+	if interval <= 0 {
+		interval = defaultExpirySweepPeriod
+	}
+	return &expiryWorker{
+		queries:  queries,
+		db:       db,
+		ttl:      ttl,
+		interval: interval,
+	}
+}
+
+// transactionExpired reports whether an initiated transaction created at
+// createdAt is past its confirmation window.
+func transactionExpired(createdAt time.Time, ttl time.Duration) bool {
+	if ttl <= 0 {
+		ttl = defaultTransactionTTL
+	}
+	return time.Since(createdAt) > ttl
+}
+
+func (ew *expiryWorker) StartWorker(ctx context.Context) error {
+	utils.Logger.Info().Dur("ttl", ew.ttl).Msg("expiry worker started")
+	ticker := time.NewTicker(ew.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			utils.Logger.Info().Msg("expiry worker stopping")
+			return ctx.Err()
+		case <-ticker.C:
+			if err := ew.processExpiryBatch(ctx); err != nil {
+				traceID := utils.TraceIDFromContext(ctx)
+				utils.Logger.Error().Err(err).Str("trace_id", traceID).Msg("error expiring transactions")
+			}
+		}
+	}
+}
+
+// processExpiryBatch expires each stale transaction in its own database
+// transaction, so one row that cannot be expired is logged and skipped rather
+// than rolling back the rest of the batch. Payment requests are expired in a
+// separate transaction of their own.
+func (ew *expiryWorker) processExpiryBatch(ctx context.Context) error {
+	ctx = utils.WithActor(ctx, utils.SystemActor("expiry_worker"))
+
+	expired, err := expireStaleTransactions(ctx, ew.queries, time.Now().Add(-ew.ttl), ew.inTx)
+	if err != nil {
+		return err
+	}
+	if expired > 0 {
+		utils.Logger.Info().Int("count", expired).Msg("expired stale initiated transactions")
+	}
+
+	var expiredRequests int
+	err = ew.inTx(ctx, func(queries gen.Querier) error {
+		expiredRequests, err = expirePaymentRequests(ctx, queries)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if expiredRequests > 0 {
+		utils.Logger.Info().Int("count", expiredRequests).Msg("expired payment requests")
+	}
+	return nil
+}
+
+// inTx runs fn with queries bound to a new transaction.
+func (ew *expiryWorker) inTx(ctx context.Context, fn func(queries gen.Querier) error) error {
+	tx, err := ew.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(ew.queries.WithTx(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// expireStaleTransactions moves initiated transactions created before the
+// cutoff to expired and releases their holds, each inside its own inTx call.
+// The listing takes no locks. A row confirmed or cancelled after it was listed
+// fails the conditional transition and is left alone. A row that fails to
+// expire is logged and skipped, and only the rows that were expired are
+// counted.
+func expireStaleTransactions(ctx context.Context, queries gen.Querier, createdBefore time.Time, inTx func(ctx context.Context, fn func(queries gen.Querier) error) error) (int, error) {
+	ids, err := queries.ListStaleInitiatedTransactionIDs(ctx, gen.ListStaleInitiatedTransactionIDsParams{
+		CreatedBefore: createdBefore,
+		BatchSize:     expiryBatchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("list stale initiated transactions: %w", err)
+	}
+
+	expired := 0
+	for _, id := range ids {
+		err := inTx(ctx, func(queries gen.Querier) error {
+			return abandonInitiatedTransaction(ctx, queries, id, models.TransactionStatusExpired, "not confirmed before expiry")
+		})
+		if err != nil {
+			utils.Logger.Error().Err(err).Str("transaction_id", id).Msg("failed to expire transaction")
+			continue
+		}
+		expired++
+	}
+
+	return expired, nil
+}
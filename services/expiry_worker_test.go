@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionExpired(t *testing.T) {
+	assert.False(t, transactionExpired(time.Now().Add(-5*time.Minute), 0))
+	assert.True(t, transactionExpired(time.Now().Add(-11*time.Minute), 0))
+	assert.True(t, transactionExpired(time.Now().Add(-2*time.Minute), time.Minute))
+	assert.False(t, transactionExpired(time.Now().Add(-20*time.Minute), time.Hour))
+}
+
+func TestExpireStaleTransactions(t *testing.T) {
+	cutoff := time.Now().Add(-10 * time.Minute)
+
+	// inTx stands in for a database transaction per row and counts the calls.
+	newInTx := func(queries gen.Querier, calls *int) func(context.Context, func(gen.Querier) error) error {
+		return func(ctx context.Context, fn func(gen.Querier) error) error {
+			*calls++
+			return fn(queries)
+		}
+	}
+
+	t.Run("expires each stale transaction", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+
+		mockQueries.On("ListStaleInitiatedTransactionIDs", mock.Anything, gen.ListStaleInitiatedTransactionIDsParams{
+			CreatedBefore: cutoff,
+			BatchSize:     expiryBatchSize,
+		}).Return([]string{"tx_1", "tx_2"}, nil)
+		for _, id := range []string{"tx_1", "tx_2"} {
+			mockQueries.On("TransitionTransactionStatus", mock.Anything, gen.TransitionTransactionStatusParams{
+				NewStatus:     string(models.TransactionStatusExpired),
+				ID:            id,
+				CurrentStatus: string(models.TransactionStatusInitiated),
+			}).Return(int64(1), nil)
+			mockQueries.On("CreateTransactionStatusHistory", mock.Anything, mock.MatchedBy(func(arg gen.CreateTransactionStatusHistoryParams) bool {
+				return arg.TransactionID == id && arg.ToStatus == string(models.TransactionStatusExpired) && arg.Reason.Valid
+			})).Return(nil)
+			expectAuditEvent(mockQueries, "transaction.status_changed", id)
+			mockQueries.On("ReleaseWalletHold", mock.Anything, gen.ReleaseWalletHoldParams{
+				Status:        string(models.WalletHoldStatusReleased),
+				TransactionID: id,
+			}).Return(nil, sql.ErrNoRows)
+		}
+
+		var txCalls int
+		count, err := expireStaleTransactions(context.Background(), mockQueries, cutoff, newInTx(mockQueries, &txCalls))
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, 2, txCalls, "each transaction is expired in its own database transaction")
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("a row that fails to expire does not block the rest", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+
+		mockQueries.On("ListStaleInitiatedTransactionIDs", mock.Anything, mock.Anything).Return([]string{"tx_1", "tx_2"}, nil)
+		mockQueries.On("TransitionTransactionStatus", mock.Anything, mock.MatchedBy(func(arg gen.TransitionTransactionStatusParams) bool {
+			return arg.ID == "tx_1"
+		})).Return(int64(0), errors.New("db error"))
+		mockQueries.On("TransitionTransactionStatus", mock.Anything, mock.MatchedBy(func(arg gen.TransitionTransactionStatusParams) bool {
+			return arg.ID == "tx_2"
+		})).Return(int64(1), nil)
+		mockQueries.On("CreateTransactionStatusHistory", mock.Anything, mock.Anything).Return(nil)
+		expectAuditEvent(mockQueries, "transaction.status_changed", "tx_2")
+		mockQueries.On("ReleaseWalletHold", mock.Anything, gen.ReleaseWalletHoldParams{
+			Status:        string(models.WalletHoldStatusReleased),
+			TransactionID: "tx_2",
+		}).Return(nil, sql.ErrNoRows)
+
+		var txCalls int
+		count, err := expireStaleTransactions(context.Background(), mockQueries, cutoff, newInTx(mockQueries, &txCalls))
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, 2, txCalls)
+		mockQueries.AssertNotCalled(t, "ReleaseWalletHold", mock.Anything, gen.ReleaseWalletHoldParams{
+			Status:        string(models.WalletHoldStatusReleased),
+			TransactionID: "tx_1",
+		})
+	})
+
+	t.Run("nothing to expire", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("ListStaleInitiatedTransactionIDs", mock.Anything, mock.Anything).Return([]string{}, nil)
+
+		var txCalls int
+		count, err := expireStaleTransactions(context.Background(), mockQueries, cutoff, newInTx(mockQueries, &txCalls))
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+		assert.Zero(t, txCalls)
+		mockQueries.AssertNotCalled(t, "TransitionTransactionStatus", mock.Anything, mock.Anything)
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("ListStaleInitiatedTransactionIDs", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+
+		var txCalls int
+		_, err := expireStaleTransactions(context.Background(), mockQueries, cutoff, newInTx(mockQueries, &txCalls))
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "list stale initiated transactions")
+	})
+}
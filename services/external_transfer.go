@@ -11,48 +11,53 @@ import (
 	"github.com/IfedayoAwe/payment-processing-service/db/gen"
 	"github.com/IfedayoAwe/payment-processing-service/models"
 	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
-	"github.com/IfedayoAwe/payment-processing-service/providers"
 	"github.com/IfedayoAwe/payment-processing-service/queue"
 	"github.com/IfedayoAwe/payment-processing-service/utils"
 	"github.com/lib/pq"
 )
 
 type ExternalTransferService interface {
-	CreateExternalTransfer(ctx context.Context, userID string, toAccountNumber string, toBankCode string, fromCurrency money.Currency, toAmount money.Money, exchangeRate float64, idempotencyKey string) (*models.Transaction, error)
-	confirmExternalTransfer(ctx context.Context, transaction gen.Transaction) (*models.Transaction, error)
+	CreateExternalTransfer(ctx context.Context, userID string, toAccountNumber string, toBankCode string, toAccountName string, beneficiaryID string, fromCurrency money.Currency, toAmount money.Money, exchangeRate float64, idempotencyKey string) (*models.Transaction, error)
+	confirmExternalTransfer(ctx context.Context, transaction gen.Transaction, review *gen.RecordRiskReviewParams) (*models.Transaction, error)
 }
 
 type externalTransferService struct {
-	queries  gen.Querier
-	db       *sql.DB
-	wallet   WalletService
-	ledger   LedgerService
-	queue    queue.Queue
-	provider *providers.Processor
+	queries     gen.Querier
+	db          *sql.DB
+	wallet      WalletService
+	ledger      LedgerService
+	fee         FeeService
+	limits      LimitService
+	queue       queue.Queue
+	nameEnquiry NameEnquiryService
+	fields      fieldCipher
 }
 
-func newExternalTransferService(queries gen.Querier, db *sql.DB, wallet WalletService, ledger LedgerService, queue queue.Queue, provider *providers.Processor) ExternalTransferService {
+func newExternalTransferService(queries gen.Querier, db *sql.DB, wallet WalletService, ledger LedgerService, fee FeeService, limits LimitService, queue queue.Queue, nameEnquiry NameEnquiryService, fields fieldCipher) ExternalTransferService {
 	return &externalTransferService{
-		queries:  queries,
-		db:       db,
-		wallet:   wallet,
-		ledger:   ledger,
-		queue:    queue,
-		provider: provider,
+		queries:     queries,
+		db:          db,
+		wallet:      wallet,
+		ledger:      ledger,
+		fee:         fee,
+		limits:      limits,
+		queue:       queue,
+		nameEnquiry: nameEnquiry,
+		fields:      fields,
 	}
 }
 
-func (ets *externalTransferService) CreateExternalTransfer(ctx context.Context, userID string, toAccountNumber string, toBankCode string, fromCurrency money.Currency, toAmount money.Money, exchangeRate float64, idempotencyKey string) (*models.Transaction, error) {
+func (ets *externalTransferService) CreateExternalTransfer(ctx context.Context, userID string, toAccountNumber string, toBankCode string, toAccountName string, beneficiaryID string, fromCurrency money.Currency, toAmount money.Money, exchangeRate float64, idempotencyKey string) (*models.Transaction, error) {
 	if !toAmount.IsPositive() {
 		return nil, utils.BadRequestErr("amount must be positive")
 	}
 
-	existing, err := ets.getTransactionByIdempotencyKey(ctx, idempotencyKey)
+	existing, err := replayTransaction(ctx, ets.queries, idempotencyKey, userID)
 	if err == nil && existing != nil {
 		return existing, nil
 	}
 	if err != nil && err != sql.ErrNoRows {
-		return nil, utils.ServerErr(fmt.Errorf("check idempotency: %w", err))
+		return nil, err
 	}
 
 	fromWallet, err := ets.wallet.GetWalletByUserAndCurrency(ctx, userID, fromCurrency)
@@ -63,29 +68,81 @@ func (ets *externalTransferService) CreateExternalTransfer(ctx context.Context,
 		return nil, err
 	}
 
+	if err := checkWalletDebit(fromWallet, "sender"); err != nil {
+		return nil, err
+	}
+
 	fromAmount := int64(float64(toAmount.Amount) / exchangeRate)
 
-	if fromWallet.Balance < fromAmount {
+	if err := ets.limits.CheckTransfer(ctx, userID, models.TransactionTypeExternal, money.NewMoney(fromAmount, fromCurrency)); err != nil {
+		return nil, err
+	}
+
+	fee, err := ets.fee.CalculateFee(ctx, models.TransactionTypeExternal, money.NewMoney(fromAmount, fromCurrency), toAmount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromWallet.AvailableBalance() < fromAmount+fee.Amount {
+		return nil, utils.BadRequestErr("insufficient funds")
+	}
+
+	enquiry, err := ets.nameEnquiry.EnquireAccountName(ctx, toAccountNumber, toBankCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkAccountName(ets.nameEnquiry, toAccountName, enquiry.AccountName); err != nil {
+		return nil, err
+	}
+
+	tx, err := ets.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := ets.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ets.queries
+	}
+
+	lockedWallet, err := ets.wallet.LockWalletForUpdate(ctx, tx, fromWallet.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkWalletDebit(lockedWallet, "sender"); err != nil {
+		return nil, err
+	}
+
+	if lockedWallet.AvailableBalance() < fromAmount+fee.Amount {
 		return nil, utils.BadRequestErr("insufficient funds")
 	}
 
 	exchangeRateStr := strconv.FormatFloat(exchangeRate, 'f', 8, 64)
 	traceID := utils.TraceIDFromContext(ctx)
-	transaction, err := ets.queries.CreateTransaction(ctx, gen.CreateTransactionParams{
+	transaction, err := queries.CreateTransaction(ctx, gen.CreateTransactionParams{
 		IdempotencyKey: idempotencyKey,
 		TraceID:        sql.NullString{String: traceID, Valid: traceID != ""},
-		FromWalletID:   sql.NullString{String: fromWallet.ID, Valid: true},
+		FromWalletID:   sql.NullString{String: lockedWallet.ID, Valid: true},
 		ToWalletID:     sql.NullString{Valid: false},
 		Type:           string(models.TransactionTypeExternal),
 		Amount:         toAmount.Amount,
 		Currency:       toAmount.Currency.String(),
 		Status:         string(models.TransactionStatusInitiated),
 		ExchangeRate:   sql.NullString{String: exchangeRateStr, Valid: true},
+		FeeAmount:      fee.Amount,
+		FeeCurrency:    sql.NullString{String: fee.Currency.String(), Valid: true},
+		BeneficiaryID:  sql.NullString{String: beneficiaryID, Valid: beneficiaryID != ""},
 	})
 	if err != nil {
 		var pqErr *pq.Error
 		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
-			existing, err := ets.getTransactionByIdempotencyKey(ctx, idempotencyKey)
+			_ = tx.Rollback()
+			existing, err := replayTransaction(ctx, ets.queries, idempotencyKey, userID)
 			if err != nil {
 				return nil, utils.DuplicateKeyErr("transaction with this idempotency key already exists")
 			}
@@ -94,23 +151,32 @@ func (ets *externalTransferService) CreateExternalTransfer(ctx context.Context,
 		return nil, utils.ServerErr(fmt.Errorf("create transaction: %w", err))
 	}
 
-	recipientDetails := map[string]string{
-		"account_number": toAccountNumber,
-		"bank_code":      toBankCode,
+	if err := recordTransactionCreated(ctx, queries, transaction); err != nil {
+		return nil, err
 	}
-	recipientJSON, err := json.Marshal(recipientDetails)
-	if err != nil {
-		return nil, utils.ServerErr(fmt.Errorf("marshal recipient details: %w", err))
+
+	if err := createTransferRecipient(ctx, queries, ets.fields, transaction.ID, toAccountNumber, toBankCode, toAmount.Currency.String(), enquiry); err != nil {
+		return nil, utils.ServerErr(err)
 	}
 
-	err = ets.queries.UpdateTransactionWithProvider(ctx, gen.UpdateTransactionWithProviderParams{
-		ProviderName:      sql.NullString{Valid: false},
-		ProviderReference: sql.NullString{String: string(recipientJSON), Valid: true},
-		Status:            string(models.TransactionStatusInitiated),
-		ID:                transaction.ID,
-	})
-	if err != nil {
-		return nil, utils.ServerErr(fmt.Errorf("store recipient details: %w", err))
+	if err := placeWalletHold(ctx, queries, lockedWallet.ID, transaction.ID, money.NewMoney(fromAmount+fee.Amount, fromCurrency)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	recipient := &models.TransferRecipient{
+		AccountNumber: toAccountNumber,
+		BankCode:      toBankCode,
+		Currency:      toAmount.Currency.String(),
+	}
+	if enquiry.AccountName != "" {
+		recipient.AccountName = &enquiry.AccountName
+	}
+	if enquiry.Country != "" {
+		recipient.Country = &enquiry.Country
 	}
 
 	return &models.Transaction{
@@ -121,12 +187,16 @@ func (ets *externalTransferService) CreateExternalTransfer(ctx context.Context,
 		Amount:         transaction.Amount,
 		Currency:       transaction.Currency,
 		Status:         models.TransactionStatusInitiated,
+		FeeAmount:      transaction.FeeAmount,
+		FeeCurrency:    &transaction.FeeCurrency.String,
+		BeneficiaryID:  nullStringPtr(transaction.BeneficiaryID),
+		Recipient:      recipient,
 		CreatedAt:      transaction.CreatedAt,
 		UpdatedAt:      transaction.UpdatedAt,
 	}, nil
 }
 
-func (ets *externalTransferService) confirmExternalTransfer(ctx context.Context, transaction gen.Transaction) (*models.Transaction, error) {
+func (ets *externalTransferService) confirmExternalTransfer(ctx context.Context, transaction gen.Transaction, review *gen.RecordRiskReviewParams) (*models.Transaction, error) {
 	if !transaction.ExchangeRate.Valid {
 		return nil, utils.ServerErr(fmt.Errorf("exchange rate not found in transaction"))
 	}
@@ -152,6 +222,11 @@ func (ets *externalTransferService) confirmExternalTransfer(ctx context.Context,
 
 	fromAmount := int64(float64(transaction.Amount) / exchangeRate)
 
+	fee, err := transactionFee(transaction, fromCurrency)
+	if err != nil {
+		return nil, utils.ServerErr(err)
+	}
+
 	tx, err := ets.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
@@ -165,12 +240,29 @@ func (ets *externalTransferService) confirmExternalTransfer(ctx context.Context,
 		queries = ets.queries
 	}
 
+	if err := lockTransactionForConfirm(ctx, queries, transaction); err != nil {
+		return nil, err
+	}
+
 	lockedWallet, err := ets.wallet.LockWalletForUpdate(ctx, tx, fromWallet.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	if lockedWallet.Balance < fromAmount {
+	if err := checkWalletDebit(lockedWallet, "sender"); err != nil {
+		return nil, err
+	}
+
+	if err := recheckConfirmLimits(ctx, tx, ets.limits, transaction, lockedWallet); err != nil {
+		return nil, err
+	}
+
+	held, err := releaseWalletHold(ctx, queries, transaction.ID, models.WalletHoldStatusCaptured)
+	if err != nil {
+		return nil, err
+	}
+
+	if lockedWallet.AvailableBalance()+held < fromAmount+fee.Amount {
 		return nil, utils.BadRequestErr("insufficient funds")
 	}
 
@@ -187,8 +279,12 @@ func (ets *externalTransferService) confirmExternalTransfer(ctx context.Context,
 		return nil, err
 	}
 
+	if err := postFeeEntries(ctx, ets.ledger, tx, lockedWallet.ID, transaction.ID, fee); err != nil {
+		return nil, err
+	}
+
 	walletMoney := money.NewMoney(lockedWallet.Balance, fromCurrency)
-	fromAmountMoney := money.NewMoney(fromAmount, fromCurrency)
+	fromAmountMoney := money.NewMoney(fromAmount+fee.Amount, fromCurrency)
 	newBalanceMoney, err := walletMoney.Subtract(fromAmountMoney)
 	if err != nil {
 		return nil, utils.ServerErr(fmt.Errorf("calculate new wallet balance: %w", err))
@@ -201,11 +297,14 @@ func (ets *externalTransferService) confirmExternalTransfer(ctx context.Context,
 		return nil, utils.ServerErr(fmt.Errorf("update wallet balance: %w", err))
 	}
 
-	if err := queries.UpdateTransactionStatus(ctx, gen.UpdateTransactionStatusParams{
-		Status: string(models.TransactionStatusPending),
-		ID:     transaction.ID,
-	}); err != nil {
-		return nil, utils.ServerErr(fmt.Errorf("update transaction status: %w", err))
+	if err := transitionTransactionStatus(ctx, queries, transaction.ID, models.TransactionStatus(transaction.Status), models.TransactionStatusPending, ""); err != nil {
+		return nil, err
+	}
+
+	if review != nil {
+		if err := recordRiskReview(ctx, queries, transaction.ID, *review); err != nil {
+			return nil, err
+		}
 	}
 
 	_, err = queries.CreateIdempotencyKey(ctx, gen.CreateIdempotencyKeyParams{
@@ -216,23 +315,9 @@ func (ets *externalTransferService) confirmExternalTransfer(ctx context.Context,
 		return nil, utils.ServerErr(fmt.Errorf("create idempotency key: %w", err))
 	}
 
-	if !transaction.ProviderReference.Valid {
-		return nil, utils.ServerErr(fmt.Errorf("recipient details not found in transaction"))
-	}
-
-	var recipientDetails map[string]string
-	if err := json.Unmarshal([]byte(transaction.ProviderReference.String), &recipientDetails); err != nil {
-		return nil, utils.ServerErr(fmt.Errorf("unmarshal recipient details: %w", err))
-	}
-
-	accountNumber, ok := recipientDetails["account_number"]
-	if !ok {
-		return nil, utils.ServerErr(fmt.Errorf("account_number not found in recipient details"))
-	}
-
-	bankCode, ok := recipientDetails["bank_code"]
-	if !ok {
-		return nil, utils.ServerErr(fmt.Errorf("bank_code not found in recipient details"))
+	recipient, err := loadTransferRecipient(ctx, queries, ets.fields, transaction)
+	if err != nil {
+		return nil, utils.ServerErr(err)
 	}
 
 	traceID := utils.TraceIDFromContext(ctx)
@@ -245,8 +330,6 @@ func (ets *externalTransferService) confirmExternalTransfer(ctx context.Context,
 		TraceID:       traceID,
 		Amount:        transaction.Amount,
 		Currency:      transaction.Currency,
-		AccountNumber: accountNumber,
-		BankCode:      bankCode,
 	}
 
 	payloadJSON, err := json.Marshal(payload)
@@ -271,17 +354,7 @@ func (ets *externalTransferService) confirmExternalTransfer(ctx context.Context,
 		return nil, utils.ServerErr(fmt.Errorf("get updated transaction: %w", err))
 	}
 
-	return mapTransaction(updatedTransaction), nil
-}
-
-func (ets *externalTransferService) getTransactionByIdempotencyKey(ctx context.Context, idempotencyKey string) (*models.Transaction, error) {
-	transaction, err := ets.queries.GetTransactionByIdempotencyKey(ctx, idempotencyKey)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, sql.ErrNoRows
-		}
-		return nil, utils.ServerErr(fmt.Errorf("get transaction: %w", err))
-	}
-
-	return mapTransaction(transaction), nil
+	result := mapTransaction(updatedTransaction)
+	result.Recipient = recipient
+	return result, nil
 }
@@ -2,12 +2,17 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 
 	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
 	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
 	"github.com/IfedayoAwe/payment-processing-service/providers"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestExternalTransferService_CreateExternalTransfer_Validation(t *testing.T) {
@@ -18,21 +23,48 @@ func TestExternalTransferService_CreateExternalTransfer_Validation(t *testing.T)
 	processor.RegisterExchangeRateProvider(mockProvider)
 
 	ets := &externalTransferService{
-		queries:  &gen.Queries{},
-		provider: processor,
+		queries:     &gen.Queries{},
+		nameEnquiry: &nameEnquiryService{queries: &gen.Queries{}, banks: knownBanks(), provider: processor},
 	}
 
 	t.Run("zero amount should fail", func(t *testing.T) {
 		zeroAmount := money.NewMoney(0, money.USD)
-		_, err := ets.CreateExternalTransfer(context.Background(), "user_1", "1234567890", "044", money.USD, zeroAmount, 1.0, "key_1")
+		_, err := ets.CreateExternalTransfer(context.Background(), "user_1", "1234567890", "044", "", "", money.USD, zeroAmount, 1.0, "key_1")
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "amount must be positive")
 	})
 
 	t.Run("negative amount should fail", func(t *testing.T) {
 		negativeAmount := money.NewMoney(-100, money.USD)
-		_, err := ets.CreateExternalTransfer(context.Background(), "user_1", "1234567890", "044", money.USD, negativeAmount, 1.0, "key_1")
+		_, err := ets.CreateExternalTransfer(context.Background(), "user_1", "1234567890", "044", "", "", money.USD, negativeAmount, 1.0, "key_1")
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "amount must be positive")
 	})
 }
+
+func TestExternalTransferService_ConfirmLocksTransactionFirst(t *testing.T) {
+	mockQueries := new(mocks.MockQuerier)
+	mockWallet := new(mocks.MockWalletService)
+	ets := &externalTransferService{queries: mockQueries, db: newNopDB(), wallet: mockWallet, limits: noLimits()}
+
+	transaction := gen.Transaction{
+		ID:           "tx_1",
+		FromWalletID: sql.NullString{String: "wallet_1", Valid: true},
+		Type:         string(models.TransactionTypeExternal),
+		Status:       string(models.TransactionStatusInitiated),
+		Amount:       1000,
+		Currency:     "USD",
+		ExchangeRate: sql.NullString{String: "1", Valid: true},
+	}
+	cancelled := transaction
+	cancelled.Status = string(models.TransactionStatusCancelled)
+
+	mockWallet.On("GetWalletByID", mock.Anything, "wallet_1").Return(&models.Wallet{ID: "wallet_1", Currency: "USD"}, nil)
+	mockQueries.On("GetTransactionByIDForUpdate", mock.Anything, "tx_1").Return(cancelled, nil)
+
+	_, err := ets.confirmExternalTransfer(context.Background(), transaction, nil)
+
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+	mockWallet.AssertNotCalled(t, "LockWalletForUpdate", mock.Anything, mock.Anything, mock.Anything)
+	mockQueries.AssertNotCalled(t, "ReleaseWalletHold", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+)
+
+const (
+	feeTypeFlat       = "flat"
+	feeTypePercentage = "percentage"
+	feeTypeTiered     = "tiered"
+
+	basisPointsDivisor = 10000
+)
+
+type FeeService interface {
+	CalculateFee(ctx context.Context, transactionType models.TransactionType, sourceAmount money.Money, destinationCurrency money.Currency) (money.Money, error)
+}
+
+type feeService struct {
+	queries gen.Querier
+}
+
+func newFeeService(queries gen.Querier) FeeService {
+	return &feeService{
+		queries: queries,
+	}
+}
+
+// feeTier is one band of a graduated fee schedule. The portion of the amount
+// up to UpTo (exclusive of previous bands) is charged at PercentageBps. A nil
+// UpTo marks the open-ended final band.
+type feeTier struct {
+	UpTo          *int64 `json:"up_to"`
+	PercentageBps int64  `json:"percentage_bps"`
+}
+
+// CalculateFee prices a transfer in the source (debited) currency using the
+// highest priority active rule matching the transaction type, corridor and
+// amount band. No matching rule means the transfer is free.
+func (fs *feeService) CalculateFee(ctx context.Context, transactionType models.TransactionType, sourceAmount money.Money, destinationCurrency money.Currency) (money.Money, error) {
+	rules, err := fs.queries.ListActiveFeeRules(ctx, string(transactionType))
+	if err != nil {
+		return money.Money{}, utils.ServerErr(fmt.Errorf("list fee rules: %w", err))
+	}
+
+	rule := selectFeeRule(rules, sourceAmount, destinationCurrency)
+	if rule == nil {
+		return money.NewMoney(0, sourceAmount.Currency), nil
+	}
+
+	fee, err := calculateFee(*rule, sourceAmount.Amount)
+	if err != nil {
+		return money.Money{}, utils.ServerErr(fmt.Errorf("calculate fee for rule %s: %w", rule.ID, err))
+	}
+
+	return money.NewMoney(fee, sourceAmount.Currency), nil
+}
+
+// selectFeeRule expects rules ordered by priority. Among rules of equal
+// priority the one pinning the most corridor fields wins, so a specific
+// USD->EUR rule beats a catch-all rule for the same transaction type.
+func selectFeeRule(rules []gen.FeeRule, sourceAmount money.Money, destinationCurrency money.Currency) *gen.FeeRule {
+	var selected *gen.FeeRule
+	selectedSpecificity := -1
+
+	for i := range rules {
+		rule := &rules[i]
+		if !feeRuleMatches(rule, sourceAmount, destinationCurrency) {
+			continue
+		}
+
+		specificity := 0
+		if rule.SourceCurrency.Valid {
+			specificity++
+		}
+		if rule.DestinationCurrency.Valid {
+			specificity++
+		}
+
+		if selected == nil || rule.Priority > selected.Priority ||
+			(rule.Priority == selected.Priority && specificity > selectedSpecificity) {
+			selected = rule
+			selectedSpecificity = specificity
+		}
+	}
+
+	return selected
+}
+
+func feeRuleMatches(rule *gen.FeeRule, sourceAmount money.Money, destinationCurrency money.Currency) bool {
+	if !rule.Active {
+		return false
+	}
+	if rule.SourceCurrency.Valid && rule.SourceCurrency.String != sourceAmount.Currency.String() {
+		return false
+	}
+	if rule.DestinationCurrency.Valid && rule.DestinationCurrency.String != destinationCurrency.String() {
+		return false
+	}
+	if sourceAmount.Amount < rule.MinAmount {
+		return false
+	}
+	if rule.MaxAmount.Valid && sourceAmount.Amount > rule.MaxAmount.Int64 {
+		return false
+	}
+	return true
+}
+
+func calculateFee(rule gen.FeeRule, amount int64) (int64, error) {
+	var fee int64
+
+	switch rule.FeeType {
+	case feeTypeFlat:
+		fee = rule.FlatAmount
+	case feeTypePercentage:
+		fee = rule.FlatAmount + applyBasisPoints(amount, int64(rule.PercentageBps))
+	case feeTypeTiered:
+		var tiers []feeTier
+		if err := json.Unmarshal(rule.Tiers, &tiers); err != nil {
+			return 0, fmt.Errorf("unmarshal tiers: %w", err)
+		}
+		tieredFee, err := calculateTieredFee(tiers, amount)
+		if err != nil {
+			return 0, err
+		}
+		fee = rule.FlatAmount + tieredFee
+	default:
+		return 0, fmt.Errorf("unknown fee type: %s", rule.FeeType)
+	}
+
+	if rule.MinFee.Valid && fee < rule.MinFee.Int64 {
+		fee = rule.MinFee.Int64
+	}
+	if rule.MaxFee.Valid && fee > rule.MaxFee.Int64 {
+		fee = rule.MaxFee.Int64
+	}
+
+	return fee, nil
+}
+
+func calculateTieredFee(tiers []feeTier, amount int64) (int64, error) {
+	if len(tiers) == 0 {
+		return 0, fmt.Errorf("tiered fee rule has no tiers")
+	}
+
+	var fee, lowerBound int64
+	for _, tier := range tiers {
+		if amount <= lowerBound {
+			break
+		}
+
+		upperBound := amount
+		if tier.UpTo != nil {
+			if *tier.UpTo <= lowerBound {
+				return 0, fmt.Errorf("tier bounds must be increasing")
+			}
+			upperBound = min(*tier.UpTo, amount)
+		}
+
+		fee += applyBasisPoints(upperBound-lowerBound, tier.PercentageBps)
+		lowerBound = upperBound
+	}
+
+	// Amounts beyond the last bounded tier are charged at the last tier's rate.
+	if amount > lowerBound {
+		fee += applyBasisPoints(amount-lowerBound, tiers[len(tiers)-1].PercentageBps)
+	}
+
+	return fee, nil
+}
+
+// applyBasisPoints rounds half up to the nearest minor unit.
+func applyBasisPoints(amount int64, bps int64) int64 {
+	return (amount*bps + basisPointsDivisor/2) / basisPointsDivisor
+}
+
+// transactionFee returns the fee quoted when the transaction was created. Fees
+// are always charged in the currency of the debited wallet.
+func transactionFee(transaction gen.Transaction, fromCurrency money.Currency) (money.Money, error) {
+	if transaction.FeeCurrency.Valid && transaction.FeeCurrency.String != fromCurrency.String() {
+		return money.Money{}, fmt.Errorf("fee currency %s does not match wallet currency %s", transaction.FeeCurrency.String, fromCurrency)
+	}
+	return money.NewMoney(transaction.FeeAmount, fromCurrency), nil
+}
+
+// postFeeEntries debits the fee from the sender's wallet as its own ledger leg
+// and credits the fee revenue account. It must run inside the same DB
+// transaction as the principal postings.
+func postFeeEntries(ctx context.Context, ledger LedgerService, tx *sql.Tx, walletID string, transactionID string, fee money.Money) error {
+	if !fee.IsPositive() {
+		return nil
+	}
+
+	if err := ledger.CreateDebitEntry(ctx, tx, walletID, transactionID, -fee.Amount, fee.Currency); err != nil {
+		return err
+	}
+
+	return ledger.CreateFeeRevenueEntry(ctx, tx, transactionID, fee.Amount, fee.Currency)
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateFee(t *testing.T) {
+	tiers, _ := json.Marshal([]map[string]interface{}{
+		{"up_to": 100000, "percentage_bps": 200},
+		{"up_to": 500000, "percentage_bps": 100},
+		{"up_to": nil, "percentage_bps": 50},
+	})
+
+	tests := []struct {
+		name     string
+		rule     gen.FeeRule
+		amount   int64
+		expected int64
+	}{
+		{
+			name:     "flat",
+			rule:     gen.FeeRule{FeeType: feeTypeFlat, FlatAmount: 250},
+			amount:   100000,
+			expected: 250,
+		},
+		{
+			name:     "percentage",
+			rule:     gen.FeeRule{FeeType: feeTypePercentage, PercentageBps: 150},
+			amount:   100000,
+			expected: 1500,
+		},
+		{
+			name:     "percentage rounds half up",
+			rule:     gen.FeeRule{FeeType: feeTypePercentage, PercentageBps: 50},
+			amount:   101,
+			expected: 1,
+		},
+		{
+			name:     "percentage plus flat component",
+			rule:     gen.FeeRule{FeeType: feeTypePercentage, PercentageBps: 290, FlatAmount: 30},
+			amount:   10000,
+			expected: 320,
+		},
+		{
+			name:     "percentage capped by min fee",
+			rule:     gen.FeeRule{FeeType: feeTypePercentage, PercentageBps: 100, MinFee: sql.NullInt64{Int64: 100, Valid: true}},
+			amount:   1000,
+			expected: 100,
+		},
+		{
+			name:     "percentage capped by max fee",
+			rule:     gen.FeeRule{FeeType: feeTypePercentage, PercentageBps: 100, MaxFee: sql.NullInt64{Int64: 2500, Valid: true}},
+			amount:   1000000,
+			expected: 2500,
+		},
+		{
+			name:     "tiered within first band",
+			rule:     gen.FeeRule{FeeType: feeTypeTiered, Tiers: tiers},
+			amount:   50000,
+			expected: 1000,
+		},
+		{
+			name:     "tiered across all bands",
+			rule:     gen.FeeRule{FeeType: feeTypeTiered, Tiers: tiers},
+			amount:   700000,
+			expected: 2000 + 4000 + 1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee, err := calculateFee(tt.rule, tt.amount)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, fee)
+		})
+	}
+
+	t.Run("unknown fee type", func(t *testing.T) {
+		_, err := calculateFee(gen.FeeRule{FeeType: "bogus"}, 1000)
+		assert.Error(t, err)
+	})
+
+	t.Run("tiered without tiers", func(t *testing.T) {
+		_, err := calculateFee(gen.FeeRule{FeeType: feeTypeTiered, Tiers: json.RawMessage(`[]`)}, 1000)
+		assert.Error(t, err)
+	})
+}
+
+func TestSelectFeeRule(t *testing.T) {
+	usd := sql.NullString{String: "USD", Valid: true}
+	eur := sql.NullString{String: "EUR", Valid: true}
+
+	rules := []gen.FeeRule{
+		{ID: "catch_all", Active: true},
+		{ID: "usd_eur", Active: true, SourceCurrency: usd, DestinationCurrency: eur},
+		{ID: "usd_large", Active: true, SourceCurrency: usd, MinAmount: 1000000},
+		{ID: "inactive", Active: false, SourceCurrency: usd, DestinationCurrency: eur, Priority: 10},
+	}
+
+	tests := []struct {
+		name        string
+		amount      money.Money
+		destination money.Currency
+		expected    string
+	}{
+		{"corridor specific rule wins", money.NewMoney(5000, money.USD), money.EUR, "usd_eur"},
+		{"amount band applies", money.NewMoney(2000000, money.USD), money.GBP, "usd_large"},
+		{"falls back to catch all", money.NewMoney(5000, money.GBP), money.USD, "catch_all"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := selectFeeRule(rules, tt.amount, tt.destination)
+			require.NotNil(t, rule)
+			assert.Equal(t, tt.expected, rule.ID)
+		})
+	}
+
+	t.Run("higher priority wins over specificity", func(t *testing.T) {
+		prioritised := []gen.FeeRule{
+			{ID: "specific", Active: true, SourceCurrency: usd, DestinationCurrency: eur},
+			{ID: "promo", Active: true, Priority: 5},
+		}
+		rule := selectFeeRule(prioritised, money.NewMoney(5000, money.USD), money.EUR)
+		require.NotNil(t, rule)
+		assert.Equal(t, "promo", rule.ID)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		rule := selectFeeRule([]gen.FeeRule{{ID: "eur_only", Active: true, SourceCurrency: eur}}, money.NewMoney(5000, money.USD), money.EUR)
+		assert.Nil(t, rule)
+	})
+}
+
+func TestFeeService_CalculateFee(t *testing.T) {
+	t.Run("no rules means no fee", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		fs := &feeService{queries: mockQueries}
+
+		mockQueries.On("ListActiveFeeRules", mock.Anything, "internal").Return([]gen.FeeRule{}, nil)
+
+		fee, err := fs.CalculateFee(context.Background(), models.TransactionTypeInternal, money.NewMoney(10000, money.USD), money.EUR)
+
+		require.NoError(t, err)
+		assert.Equal(t, money.NewMoney(0, money.USD), fee)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("fee is charged in source currency", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		fs := &feeService{queries: mockQueries}
+
+		mockQueries.On("ListActiveFeeRules", mock.Anything, "external").Return([]gen.FeeRule{
+			{ID: "external", Active: true, FeeType: feeTypePercentage, PercentageBps: 100},
+		}, nil)
+
+		fee, err := fs.CalculateFee(context.Background(), models.TransactionTypeExternal, money.NewMoney(20000, money.GBP), money.USD)
+
+		require.NoError(t, err)
+		assert.Equal(t, money.NewMoney(200, money.GBP), fee)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		fs := &feeService{queries: mockQueries}
+
+		mockQueries.On("ListActiveFeeRules", mock.Anything, "external").Return(nil, errors.New("db error"))
+
+		_, err := fs.CalculateFee(context.Background(), models.TransactionTypeExternal, money.NewMoney(20000, money.GBP), money.USD)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "list fee rules")
+	})
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/keyring"
+)
+
+// Encrypted columns. The name is bound to each value as associated data, so a
+// ciphertext copied from one column into another does not decrypt.
+const (
+	fieldBankAccountNumber      = "bank_accounts.account_number"
+	fieldBeneficiaryAccount     = "beneficiaries.account_number"
+	fieldRecipientDetails       = "transactions.provider_reference"
+	fieldRecipientAccountNumber = "transfer_recipients.account_number"
+	fieldWebhookPayload         = "webhook_events.payload"
+	fieldKYCDocumentNumber      = "kyc_documents.document_number"
+)
+
+// fieldRiskRecipientAccount is the blind index domain for bank account numbers
+// in risk_assessments.recipient, which is keyed on the index rather than the
+// number.
+const fieldRiskRecipientAccount = "risk_assessments.recipient"
+
+var errKeyringNotConfigured = errors.New("value is encrypted but no keyring is configured")
+
+// fieldCipher encrypts sensitive column values before they are written and
+// decrypts them when they are read. Values written before encryption was
+// enabled are still read as plaintext. The zero value has no keyring and
+// stores plaintext, which is what runs when ENCRYPTION_KEYRING_FILE is unset.
+type fieldCipher struct {
+	keyring *keyring.Keyring
+}
+
+func (fc fieldCipher) enabled() bool {
+	return fc.keyring != nil
+}
+
+func (fc fieldCipher) seal(field string, plaintext []byte) (string, error) {
+	if !fc.enabled() {
+		return string(plaintext), nil
+	}
+	value, err := fc.keyring.Encrypt(plaintext, []byte(field))
+	if err != nil {
+		return "", fmt.Errorf("encrypt %s: %w", field, err)
+	}
+	return value, nil
+}
+
+func (fc fieldCipher) open(field string, value string) ([]byte, error) {
+	if !keyring.IsEncrypted(value) {
+		return []byte(value), nil
+	}
+	if !fc.enabled() {
+		return nil, fmt.Errorf("decrypt %s: %w", field, errKeyringNotConfigured)
+	}
+	plaintext, err := fc.keyring.Decrypt(value, []byte(field))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt %s: %w", field, err)
+	}
+	return plaintext, nil
+}
+
+// blindIndex is the value stored in an account_number_hash column. It is null
+// without a keyring, which makes lookups fall back to matching the plaintext
+// column.
+func (fc fieldCipher) blindIndex(field string, value string) sql.NullString {
+	if !fc.enabled() {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: fc.keyring.BlindIndex(field, value), Valid: true}
+}
+
+// accountNumberIndex is the blind index stored in
+// bank_accounts.account_number_hash.
+func (fc fieldCipher) accountNumberIndex(accountNumber string) sql.NullString {
+	return fc.blindIndex(fieldBankAccountNumber, accountNumber)
+}
+
+// accountRecipient is the risk_assessments.recipient value for a bank account.
+// The account number is replaced by its blind index, so repeat transfers to
+// the account still match. Without a keyring it is kept as is, like the other
+// protected columns.
+func (fc fieldCipher) accountRecipient(bankCode, accountNumber string) string {
+	if index := fc.blindIndex(fieldRiskRecipientAccount, accountNumber); index.Valid {
+		accountNumber = index.String
+	}
+	return "account:" + bankCode + ":" + accountNumber
+}
+
+func (fc fieldCipher) sealAccountNumber(accountNumber string) (string, error) {
+	return fc.seal(fieldBankAccountNumber, []byte(accountNumber))
+}
+
+func (fc fieldCipher) openAccountNumber(value string) (string, error) {
+	plaintext, err := fc.open(fieldBankAccountNumber, value)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// bankAccountLookup builds the parameters for finding an internal account by
+// number, matching on the blind index once the row has one.
+func (fc fieldCipher) bankAccountLookup(accountNumber, bankCode string) gen.GetBankAccountByAccountAndBankCodeParams {
+	return gen.GetBankAccountByAccountAndBankCodeParams{
+		BankCode:          bankCode,
+		AccountNumberHash: fc.accountNumberIndex(accountNumber),
+		AccountNumber:     accountNumber,
+	}
+}
+
+// beneficiaryAccountIndex is the blind index stored in
+// beneficiaries.account_number_hash.
+func (fc fieldCipher) beneficiaryAccountIndex(accountNumber string) sql.NullString {
+	return fc.blindIndex(fieldBeneficiaryAccount, accountNumber)
+}
+
+func (fc fieldCipher) sealBeneficiaryAccountNumber(accountNumber string) (string, error) {
+	return fc.seal(fieldBeneficiaryAccount, []byte(accountNumber))
+}
+
+func (fc fieldCipher) openBeneficiaryAccountNumber(value string) (string, error) {
+	plaintext, err := fc.open(fieldBeneficiaryAccount, value)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// beneficiaryLookup builds the parameters for finding one of a user's saved
+// accounts by number, matching on the blind index once the row has one.
+func (fc fieldCipher) beneficiaryLookup(userID, accountNumber, bankCode string) gen.GetBeneficiaryByAccountParams {
+	return gen.GetBeneficiaryByAccountParams{
+		UserID:            userID,
+		BankCode:          bankCode,
+		AccountNumberHash: fc.beneficiaryAccountIndex(accountNumber),
+		AccountNumber:     accountNumber,
+	}
+}
+
+func (fc fieldCipher) sealRecipientAccountNumber(accountNumber string) (string, error) {
+	return fc.seal(fieldRecipientAccountNumber, []byte(accountNumber))
+}
+
+func (fc fieldCipher) openRecipientAccountNumber(value string) (string, error) {
+	plaintext, err := fc.open(fieldRecipientAccountNumber, value)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func (fc fieldCipher) sealKYCDocumentNumber(documentNumber string) (string, error) {
+	return fc.seal(fieldKYCDocumentNumber, []byte(documentNumber))
+}
+
+func (fc fieldCipher) openKYCDocumentNumber(value string) (string, error) {
+	plaintext, err := fc.open(fieldKYCDocumentNumber, value)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// openRecipientDetails reads the account an external transfer initiated
+// before transfer_recipients existed pays out to. Those transfers kept it in
+// provider_reference until the payout was submitted.
+func (fc fieldCipher) openRecipientDetails(transaction gen.Transaction) (map[string]string, error) {
+	if !transaction.ProviderReference.Valid {
+		return nil, fmt.Errorf("recipient details not found in transaction")
+	}
+
+	recipientJSON, err := fc.open(fieldRecipientDetails, transaction.ProviderReference.String)
+	if err != nil {
+		return nil, err
+	}
+
+	var recipientDetails map[string]string
+	if err := json.Unmarshal(recipientJSON, &recipientDetails); err != nil {
+		return nil, fmt.Errorf("unmarshal recipient details: %w", err)
+	}
+	return recipientDetails, nil
+}
+
+// sealWebhookPayload returns the value stored in webhook_events.payload. The
+// column is JSONB, so an encrypted payload is stored as a JSON string.
+func (fc fieldCipher) sealWebhookPayload(payload []byte) (json.RawMessage, error) {
+	if !fc.enabled() {
+		return json.RawMessage(payload), nil
+	}
+	value, err := fc.seal(fieldWebhookPayload, payload)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+func (fc fieldCipher) openWebhookPayload(stored json.RawMessage) ([]byte, error) {
+	var value string
+	if err := json.Unmarshal(stored, &value); err != nil || !keyring.IsEncrypted(value) {
+		// A plaintext payload from before encryption was enabled.
+		return stored, nil
+	}
+	return fc.open(fieldWebhookPayload, value)
+}
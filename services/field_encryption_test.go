@@ -0,0 +1,186 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/keyring"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFieldCipher returns a cipher whose keyring holds keys k1 and k2 with
+// active as the active key.
+func testFieldCipher(t *testing.T, active string) fieldCipher {
+	t.Helper()
+	key := func(c string) string {
+		return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(c, keyring.KeySize)))
+	}
+	kr, err := keyring.Load([]byte(fmt.Sprintf(`{
+		"active_key": %q,
+		"keys": [{"id": "k1", "key": %q}, {"id": "k2", "key": %q}],
+		"index_key": %q
+	}`, active, key("a"), key("b"), key("i"))))
+	require.NoError(t, err)
+	return fieldCipher{keyring: kr}
+}
+
+// sealLegacyRecipientDetails encrypts recipient details the way external
+// transfers stored them in provider_reference before transfer_recipients.
+func sealLegacyRecipientDetails(t *testing.T, fields fieldCipher, accountNumber, bankCode string) string {
+	t.Helper()
+	recipientJSON, err := json.Marshal(map[string]string{"account_number": accountNumber, "bank_code": bankCode})
+	require.NoError(t, err)
+	sealed, err := fields.seal(fieldRecipientDetails, recipientJSON)
+	require.NoError(t, err)
+	return sealed
+}
+
+func TestFieldCipher_AccountNumber(t *testing.T) {
+	t.Run("plaintext without a keyring", func(t *testing.T) {
+		var fields fieldCipher
+
+		sealed, err := fields.sealAccountNumber("0123456789")
+		require.NoError(t, err)
+		assert.Equal(t, "0123456789", sealed)
+		assert.False(t, fields.accountNumberIndex("0123456789").Valid)
+
+		lookup := fields.bankAccountLookup("0123456789", "044")
+		assert.Equal(t, gen.GetBankAccountByAccountAndBankCodeParams{BankCode: "044", AccountNumber: "0123456789"}, lookup)
+	})
+
+	t.Run("encrypted with a keyring", func(t *testing.T) {
+		fields := testFieldCipher(t, "k1")
+
+		sealed, err := fields.sealAccountNumber("0123456789")
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(sealed, "enc:v1:k1:"))
+
+		accountNumber, err := fields.openAccountNumber(sealed)
+		require.NoError(t, err)
+		assert.Equal(t, "0123456789", accountNumber)
+
+		lookup := fields.bankAccountLookup("0123456789", "044")
+		assert.True(t, lookup.AccountNumberHash.Valid)
+		assert.Equal(t, fields.accountNumberIndex("0123456789"), lookup.AccountNumberHash)
+	})
+
+	t.Run("plaintext rows still read", func(t *testing.T) {
+		accountNumber, err := testFieldCipher(t, "k1").openAccountNumber("0123456789")
+		require.NoError(t, err)
+		assert.Equal(t, "0123456789", accountNumber)
+	})
+
+	t.Run("encrypted value without a keyring", func(t *testing.T) {
+		sealed, err := testFieldCipher(t, "k1").sealAccountNumber("0123456789")
+		require.NoError(t, err)
+
+		_, err = fieldCipher{}.openAccountNumber(sealed)
+		assert.ErrorIs(t, err, errKeyringNotConfigured)
+	})
+
+	t.Run("value from another column", func(t *testing.T) {
+		fields := testFieldCipher(t, "k1")
+		sealed, err := fields.seal(fieldWebhookPayload, []byte("0123456789"))
+		require.NoError(t, err)
+
+		_, err = fields.openAccountNumber(sealed)
+		assert.ErrorIs(t, err, keyring.ErrDecrypt)
+	})
+}
+
+func TestFieldCipher_RecipientDetails(t *testing.T) {
+	fields := testFieldCipher(t, "k1")
+
+	sealed := sealLegacyRecipientDetails(t, fields, "0123456789", "044")
+	assert.NotContains(t, sealed, "0123456789")
+
+	details, err := fields.openRecipientDetails(gen.Transaction{ProviderReference: sql.NullString{String: sealed, Valid: true}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"account_number": "0123456789", "bank_code": "044"}, details)
+
+	details, err = fields.openRecipientDetails(gen.Transaction{ProviderReference: sql.NullString{String: `{"account_number":"1","bank_code":"2"}`, Valid: true}})
+	require.NoError(t, err)
+	assert.Equal(t, "1", details["account_number"])
+
+	_, err = fields.openRecipientDetails(gen.Transaction{})
+	assert.Error(t, err)
+}
+
+func TestFieldCipher_RecipientAccountNumber(t *testing.T) {
+	fields := testFieldCipher(t, "k1")
+
+	sealed, err := fields.sealRecipientAccountNumber("0123456789")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sealed, "enc:v1:k1:"))
+
+	accountNumber, err := fields.openRecipientAccountNumber(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", accountNumber)
+
+	bankAccountNumber, err := fields.sealAccountNumber("0123456789")
+	require.NoError(t, err)
+	_, err = fields.openRecipientAccountNumber(bankAccountNumber)
+	assert.ErrorIs(t, err, keyring.ErrDecrypt)
+}
+
+func TestFieldCipher_BeneficiaryAccountNumber(t *testing.T) {
+	fields := testFieldCipher(t, "k1")
+
+	sealed, err := fields.sealBeneficiaryAccountNumber("0123456789")
+	require.NoError(t, err)
+	accountNumber, err := fields.openBeneficiaryAccountNumber(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", accountNumber)
+
+	// The blind index is keyed per column, so it cannot be joined against
+	// bank_accounts.
+	assert.True(t, fields.beneficiaryAccountIndex("0123456789").Valid)
+	assert.NotEqual(t, fields.accountNumberIndex("0123456789"), fields.beneficiaryAccountIndex("0123456789"))
+	assert.False(t, fieldCipher{}.beneficiaryAccountIndex("0123456789").Valid)
+}
+
+func TestFieldCipher_WebhookPayload(t *testing.T) {
+	payload := []byte(`{"event":"payout.completed","account_number":"0123456789"}`)
+
+	t.Run("plaintext without a keyring", func(t *testing.T) {
+		stored, err := fieldCipher{}.sealWebhookPayload(payload)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(payload), string(stored))
+	})
+
+	t.Run("encrypted payload is a JSON string", func(t *testing.T) {
+		fields := testFieldCipher(t, "k1")
+
+		stored, err := fields.sealWebhookPayload(payload)
+		require.NoError(t, err)
+
+		var value string
+		require.NoError(t, json.Unmarshal(stored, &value))
+		assert.True(t, strings.HasPrefix(value, "enc:v1:k1:"))
+
+		opened, err := fields.openWebhookPayload(stored)
+		require.NoError(t, err)
+		assert.Equal(t, payload, opened)
+
+		opened, err = fields.openWebhookPayload(json.RawMessage(payload))
+		require.NoError(t, err)
+		assert.Equal(t, payload, []byte(opened))
+	})
+}
+
+func TestMapTransaction_HidesEncryptedRecipientDetails(t *testing.T) {
+	sealed := sealLegacyRecipientDetails(t, testFieldCipher(t, "k1"), "0123456789", "044")
+
+	result := mapTransaction(gen.Transaction{
+		ID:                "tx_123",
+		Type:              "external",
+		ProviderReference: sql.NullString{String: sealed, Valid: true},
+	})
+	assert.Nil(t, result.ProviderReference)
+}
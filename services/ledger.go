@@ -14,6 +14,8 @@ type LedgerService interface {
 	CreateDebitEntry(ctx context.Context, tx *sql.Tx, walletID string, transactionID string, amount int64, currency money.Currency) error
 	CreateCreditEntry(ctx context.Context, tx *sql.Tx, walletID string, transactionID string, amount int64, currency money.Currency) error
 	CreateExternalSystemCreditEntry(ctx context.Context, tx *sql.Tx, transactionID string, amount int64, currency money.Currency) error
+	CreateExternalSystemDebitEntry(ctx context.Context, tx *sql.Tx, transactionID string, amount int64, currency money.Currency) error
+	CreateFeeRevenueEntry(ctx context.Context, tx *sql.Tx, transactionID string, amount int64, currency money.Currency) error
 	GetWalletBalance(ctx context.Context, tx *sql.Tx, walletID string, currency money.Currency) (int64, error)
 }
 
@@ -58,7 +60,7 @@ func (ls *ledgerService) CreateDebitEntry(ctx context.Context, tx *sql.Tx, walle
 		return utils.ServerErr(fmt.Errorf("create debit entry: %w", err))
 	}
 
-	return nil
+	return recordLedgerAuditEvent(ctx, queries, "wallet.debited", walletID, transactionID, amount, currency, balanceBefore, balanceAfter)
 }
 
 func (ls *ledgerService) CreateCreditEntry(ctx context.Context, tx *sql.Tx, walletID string, transactionID string, amount int64, currency money.Currency) error {
@@ -92,7 +94,7 @@ func (ls *ledgerService) CreateCreditEntry(ctx context.Context, tx *sql.Tx, wall
 		return utils.ServerErr(fmt.Errorf("create credit entry: %w", err))
 	}
 
-	return nil
+	return recordLedgerAuditEvent(ctx, queries, "wallet.credited", walletID, transactionID, amount, currency, balanceBefore, balanceAfter)
 }
 
 func (ls *ledgerService) CreateExternalSystemCreditEntry(ctx context.Context, tx *sql.Tx, transactionID string, amount int64, currency money.Currency) error {
@@ -124,6 +126,70 @@ func (ls *ledgerService) CreateExternalSystemCreditEntry(ctx context.Context, tx
 	return nil
 }
 
+func (ls *ledgerService) CreateExternalSystemDebitEntry(ctx context.Context, tx *sql.Tx, transactionID string, amount int64, currency money.Currency) error {
+	if amount >= 0 {
+		return utils.BadRequestErr("external system debit amount must be negative")
+	}
+
+	var queries gen.Querier
+	if q, ok := ls.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ls.queries
+	}
+
+	balanceBefore := int64(0)
+	balanceAfter := amount
+
+	_, err := queries.CreateExternalSystemDebitEntry(ctx, gen.CreateExternalSystemDebitEntryParams{
+		TransactionID: transactionID,
+		Amount:        amount,
+		Currency:      currency.String(),
+		BalanceBefore: balanceBefore,
+		BalanceAfter:  balanceAfter,
+	})
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("create external system debit entry: %w", err))
+	}
+
+	return nil
+}
+
+func (ls *ledgerService) CreateFeeRevenueEntry(ctx context.Context, tx *sql.Tx, transactionID string, amount int64, currency money.Currency) error {
+	if amount <= 0 {
+		return utils.BadRequestErr("fee revenue amount must be positive")
+	}
+
+	var queries gen.Querier
+	if q, ok := ls.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ls.queries
+	}
+
+	if err := queries.LockFeeRevenueAccount(ctx, currency.String()); err != nil {
+		return utils.ServerErr(fmt.Errorf("lock fee revenue account: %w", err))
+	}
+
+	balanceBefore, err := queries.GetFeeRevenueBalance(ctx, currency.String())
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("get fee revenue balance: %w", err))
+	}
+
+	_, err = queries.CreateFeeRevenueEntry(ctx, gen.CreateFeeRevenueEntryParams{
+		TransactionID: transactionID,
+		Amount:        amount,
+		Currency:      currency.String(),
+		BalanceBefore: balanceBefore,
+		BalanceAfter:  balanceBefore + amount,
+	})
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("create fee revenue entry: %w", err))
+	}
+
+	return nil
+}
+
 func (ls *ledgerService) GetWalletBalance(ctx context.Context, tx *sql.Tx, walletID string, currency money.Currency) (int64, error) {
 	var queries gen.Querier
 	if tx != nil {
@@ -146,3 +212,21 @@ func (ls *ledgerService) GetWalletBalance(ctx context.Context, tx *sql.Tx, walle
 
 	return balance, nil
 }
+
+// recordLedgerAuditEvent records a wallet balance change. Postings to the
+// external system and fee accounts are not audited separately; they are the
+// other side of a wallet posting or a transaction status change.
+func recordLedgerAuditEvent(ctx context.Context, queries gen.Querier, action string, walletID string, transactionID string, amount int64, currency money.Currency, balanceBefore int64, balanceAfter int64) error {
+	return recordAuditEvent(ctx, queries, auditEvent{
+		Action:     action,
+		EntityType: auditEntityWallet,
+		EntityID:   walletID,
+		Before:     map[string]any{"balance": balanceBefore},
+		After: map[string]any{
+			"balance":        balanceAfter,
+			"amount":         amount,
+			"currency":       currency.String(),
+			"transaction_id": transactionID,
+		},
+	})
+}
@@ -6,7 +6,10 @@ import (
 
 	"github.com/IfedayoAwe/payment-processing-service/db/gen"
 	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLedgerService_CreateDebitEntry_Validation(t *testing.T) {
@@ -42,3 +45,29 @@ func TestLedgerService_CreateCreditEntry_Validation(t *testing.T) {
 	})
 
 }
+
+func TestLedgerService_CreateFeeRevenueEntry(t *testing.T) {
+	mockQueries := new(mocks.MockQuerier)
+	ls := &ledgerService{queries: mockQueries}
+
+	var calls []string
+	mockQueries.On("LockFeeRevenueAccount", mock.Anything, "USD").
+		Run(func(mock.Arguments) { calls = append(calls, "lock") }).
+		Return(nil)
+	mockQueries.On("GetFeeRevenueBalance", mock.Anything, "USD").
+		Run(func(mock.Arguments) { calls = append(calls, "balance") }).
+		Return(int64(500), nil)
+	mockQueries.On("CreateFeeRevenueEntry", mock.Anything, gen.CreateFeeRevenueEntryParams{
+		TransactionID: "tx_1",
+		Amount:        150,
+		Currency:      "USD",
+		BalanceBefore: 500,
+		BalanceAfter:  650,
+	}).Return(gen.LedgerEntry{}, nil)
+
+	err := ls.CreateFeeRevenueEntry(context.Background(), nil, "tx_1", 150, money.USD)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lock", "balance"}, calls, "the account is locked before its balance is read")
+	mockQueries.AssertExpectations(t)
+}
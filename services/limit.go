@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+)
+
+// defaultUserTier is used for users without a row in the users table, and
+// matches the column default.
+const defaultUserTier = "standard"
+
+type LimitService interface {
+	CheckTransfer(ctx context.Context, userID string, transactionType models.TransactionType, amount money.Money) error
+	CheckTransferLocked(ctx context.Context, tx *sql.Tx, userID string, transactionType models.TransactionType, amount money.Money) error
+	GetUserLimits(ctx context.Context, userID string) (*models.UserLimits, error)
+	GetTransferLimit(ctx context.Context, userID string, transactionType models.TransactionType, currency money.Currency) (*models.TransferLimitUsage, error)
+}
+
+type limitService struct {
+	queries gen.Querier
+	kyc     KYCPolicy
+}
+
+func newLimitService(queries gen.Querier, kyc KYCPolicy) LimitService {
+	return &limitService{
+		queries: queries,
+		kyc:     kyc,
+	}
+}
+
+// CheckTransfer rejects a transfer that would take the user past the active
+// limit for their tier, the source currency and the transaction type. Amounts
+// are in the source (debited) currency and exclude fees. No matching rule
+// means the transfer is not limited.
+//
+// Usage counts pending, completed and on_hold transfers, so initiated
+// transfers do not reserve allowance. Confirmation checks again to catch several
+// initiated transfers that each fit on their own.
+//
+// A source currency the user's KYC tier does not allow is rejected before
+// any limit is looked at. Conversions between a user's own wallets are not
+// checked here; OpenWallet already gated which currencies they hold.
+func (ls *limitService) CheckTransfer(ctx context.Context, userID string, transactionType models.TransactionType, amount money.Money) error {
+	return ls.checkTransfer(ctx, ls.queries, userID, transactionType, amount)
+}
+
+// CheckTransferLocked is CheckTransfer run inside tx, for a confirmation that
+// holds the lock on the source wallet. Confirmations drawing on the same
+// allowance queue on that lock, so each one sees the usage committed before
+// it and the check cannot go stale before the debit.
+func (ls *limitService) CheckTransferLocked(ctx context.Context, tx *sql.Tx, userID string, transactionType models.TransactionType, amount money.Money) error {
+	var queries gen.Querier
+	if q, ok := ls.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ls.queries
+	}
+
+	return ls.checkTransfer(ctx, queries, userID, transactionType, amount)
+}
+
+func (ls *limitService) checkTransfer(ctx context.Context, queries gen.Querier, userID string, transactionType models.TransactionType, amount money.Money) error {
+	user, err := ls.user(ctx, queries, userID)
+	if err != nil {
+		return err
+	}
+	if err := ls.kyc.checkCurrency(user, amount.Currency); err != nil {
+		return err
+	}
+	tier := user.Tier
+
+	rule, err := queries.GetTransferLimit(ctx, gen.GetTransferLimitParams{
+		Tier:            tier,
+		Currency:        amount.Currency.String(),
+		TransactionType: string(transactionType),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return utils.ServerErr(fmt.Errorf("get transfer limit: %w", err))
+	}
+
+	usage, err := ls.usage(ctx, queries, userID, rule.Currency, transactionType, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := checkTransferLimit(rule, usage, amount); err != nil {
+		utils.Logger.Warn().
+			Str("event", "transfer_limit_exceeded").
+			Str("user_id", userID).
+			Str("tier", tier).
+			Str("transaction_type", string(transactionType)).
+			Str("amount", amount.String()).
+			Str("trace_id", utils.TraceIDFromContext(ctx)).
+			Msg("transfer rejected by limit")
+		return err
+	}
+
+	return nil
+}
+
+// GetUserLimits returns every active limit for the user's tier along with
+// what has been used in the current day and month (UTC).
+func (ls *limitService) GetUserLimits(ctx context.Context, userID string) (*models.UserLimits, error) {
+	tier, err := ls.userTier(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := ls.queries.ListActiveTransferLimits(ctx, tier)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("list transfer limits: %w", err))
+	}
+
+	now := time.Now()
+	limits := make([]*models.TransferLimitUsage, 0, len(rules))
+	for _, rule := range rules {
+		usage, err := ls.usage(ctx, ls.queries, userID, rule.Currency, models.TransactionType(rule.TransactionType), now)
+		if err != nil {
+			return nil, err
+		}
+		limits = append(limits, transferLimitUsage(rule, usage, now))
+	}
+
+	return &models.UserLimits{
+		UserID: userID,
+		Tier:   tier,
+		Limits: limits,
+	}, nil
+}
+
+// GetTransferLimit returns the user's limit and usage for one source currency
+// and transaction type, or nil when that combination is not limited.
+func (ls *limitService) GetTransferLimit(ctx context.Context, userID string, transactionType models.TransactionType, currency money.Currency) (*models.TransferLimitUsage, error) {
+	tier, err := ls.userTier(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := ls.queries.GetTransferLimit(ctx, gen.GetTransferLimitParams{
+		Tier:            tier,
+		Currency:        currency.String(),
+		TransactionType: string(transactionType),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, utils.ServerErr(fmt.Errorf("get transfer limit: %w", err))
+	}
+
+	now := time.Now()
+	usage, err := ls.usage(ctx, ls.queries, userID, rule.Currency, transactionType, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return transferLimitUsage(rule, usage, now), nil
+}
+
+func (ls *limitService) userTier(ctx context.Context, userID string) (string, error) {
+	user, err := ls.user(ctx, ls.queries, userID)
+	if err != nil {
+		return "", err
+	}
+	return user.Tier, nil
+}
+
+// user loads the user whose limits apply. A user without a row is treated as
+// unverified and on defaultUserTier.
+func (ls *limitService) user(ctx context.Context, queries gen.Querier, userID string) (gen.User, error) {
+	user, err := queries.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return gen.User{UserID: userID, Tier: defaultUserTier}, nil
+		}
+		return gen.User{}, utils.ServerErr(fmt.Errorf("get user: %w", err))
+	}
+	if user.Tier == "" {
+		user.Tier = defaultUserTier
+	}
+	return user, nil
+}
+
+func (ls *limitService) usage(ctx context.Context, queries gen.Querier, userID string, currency string, transactionType models.TransactionType, now time.Time) (gen.GetTransferUsageRow, error) {
+	dayStart, monthStart := limitWindows(now)
+	usage, err := queries.GetTransferUsage(ctx, gen.GetTransferUsageParams{
+		DayStart:   dayStart,
+		UserID:     userID,
+		Currency:   currency,
+		Type:       string(transactionType),
+		MonthStart: monthStart,
+	})
+	if err != nil {
+		return gen.GetTransferUsageRow{}, utils.ServerErr(fmt.Errorf("get transfer usage: %w", err))
+	}
+	return usage, nil
+}
+
+// limitWindows returns the start of the current UTC day and month.
+func limitWindows(now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return dayStart, monthStart
+}
+
+func transferLimitUsage(rule gen.TransferLimit, usage gen.GetTransferUsageRow, now time.Time) *models.TransferLimitUsage {
+	dayStart, monthStart := limitWindows(now)
+	return &models.TransferLimitUsage{
+		Currency:          rule.Currency,
+		TransactionType:   models.TransactionType(rule.TransactionType),
+		PerTransactionMax: nullInt64Ptr(rule.PerTransactionMax),
+		Daily: models.LimitWindow{
+			AmountLimit: nullInt64Ptr(rule.DailyAmountMax),
+			AmountUsed:  usage.DailyAmount,
+			CountLimit:  nullInt32Ptr(rule.DailyCountMax),
+			CountUsed:   int32(usage.DailyCount),
+			ResetsAt:    dayStart.AddDate(0, 0, 1),
+		},
+		Monthly: models.LimitWindow{
+			AmountLimit: nullInt64Ptr(rule.MonthlyAmountMax),
+			AmountUsed:  usage.MonthlyAmount,
+			CountLimit:  nullInt32Ptr(rule.MonthlyCountMax),
+			CountUsed:   int32(usage.MonthlyCount),
+			ResetsAt:    monthStart.AddDate(0, 1, 0),
+		},
+	}
+}
+
+func checkTransferLimit(rule gen.TransferLimit, usage gen.GetTransferUsageRow, amount money.Money) error {
+	if rule.PerTransactionMax.Valid && amount.Amount > rule.PerTransactionMax.Int64 {
+		return utils.LimitExceededErr(fmt.Sprintf("amount exceeds the per-transaction limit of %s", money.NewMoney(rule.PerTransactionMax.Int64, amount.Currency)))
+	}
+
+	if rule.DailyCountMax.Valid && usage.DailyCount >= int64(rule.DailyCountMax.Int32) {
+		return utils.LimitExceededErr("daily transfer count limit reached")
+	}
+
+	if rule.DailyAmountMax.Valid && usage.DailyAmount+amount.Amount > rule.DailyAmountMax.Int64 {
+		remaining := max(rule.DailyAmountMax.Int64-usage.DailyAmount, 0)
+		return utils.LimitExceededErr(fmt.Sprintf("amount exceeds the remaining daily limit of %s", money.NewMoney(remaining, amount.Currency)))
+	}
+
+	if rule.MonthlyCountMax.Valid && usage.MonthlyCount >= int64(rule.MonthlyCountMax.Int32) {
+		return utils.LimitExceededErr("monthly transfer count limit reached")
+	}
+
+	if rule.MonthlyAmountMax.Valid && usage.MonthlyAmount+amount.Amount > rule.MonthlyAmountMax.Int64 {
+		remaining := max(rule.MonthlyAmountMax.Int64-usage.MonthlyAmount, 0)
+		return utils.LimitExceededErr(fmt.Sprintf("amount exceeds the remaining monthly limit of %s", money.NewMoney(remaining, amount.Currency)))
+	}
+
+	return nil
+}
+
+func nullInt64Ptr(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int64
+}
+
+func nullInt32Ptr(v sql.NullInt32) *int32 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int32
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// noLimits returns a limit service that allows every transfer.
+func noLimits() *mocks.MockLimitService {
+	limits := new(mocks.MockLimitService)
+	limits.On("CheckTransfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	limits.On("CheckTransferLocked", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return limits
+}
+
+func TestCheckTransferLimit(t *testing.T) {
+	rule := gen.TransferLimit{
+		PerTransactionMax: sql.NullInt64{Int64: 100000, Valid: true},
+		DailyAmountMax:    sql.NullInt64{Int64: 200000, Valid: true},
+		DailyCountMax:     sql.NullInt32{Int32: 3, Valid: true},
+		MonthlyAmountMax:  sql.NullInt64{Int64: 500000, Valid: true},
+		MonthlyCountMax:   sql.NullInt32{Int32: 10, Valid: true},
+	}
+
+	tests := []struct {
+		name    string
+		usage   gen.GetTransferUsageRow
+		amount  int64
+		message string
+	}{
+		{name: "within limits", usage: gen.GetTransferUsageRow{DailyAmount: 50000, DailyCount: 1, MonthlyAmount: 50000, MonthlyCount: 1}, amount: 100000},
+		{name: "per transaction", amount: 100001, message: "per-transaction limit of USD 1000.00"},
+		{name: "daily count", usage: gen.GetTransferUsageRow{DailyCount: 3, MonthlyCount: 3}, amount: 100, message: "daily transfer count limit reached"},
+		{name: "daily amount", usage: gen.GetTransferUsageRow{DailyAmount: 150000, DailyCount: 1, MonthlyAmount: 150000, MonthlyCount: 1}, amount: 60000, message: "remaining daily limit of USD 500.00"},
+		{name: "monthly count", usage: gen.GetTransferUsageRow{MonthlyCount: 10}, amount: 100, message: "monthly transfer count limit reached"},
+		{name: "monthly amount", usage: gen.GetTransferUsageRow{MonthlyAmount: 450000, MonthlyCount: 5}, amount: 60000, message: "remaining monthly limit of USD 500.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkTransferLimit(rule, tt.usage, money.NewMoney(tt.amount, money.USD))
+			if tt.message == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, utils.ErrLimitExceeded)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+
+	t.Run("unset limits are not enforced", func(t *testing.T) {
+		usage := gen.GetTransferUsageRow{DailyAmount: 1 << 40, DailyCount: 1 << 20, MonthlyAmount: 1 << 40, MonthlyCount: 1 << 20}
+		assert.NoError(t, checkTransferLimit(gen.TransferLimit{}, usage, money.NewMoney(1<<40, money.USD)))
+	})
+}
+
+func TestLimitWindows(t *testing.T) {
+	now := time.Date(2024, time.March, 15, 23, 30, 0, 0, time.FixedZone("WAT", 3600))
+
+	dayStart, monthStart := limitWindows(now)
+
+	assert.Equal(t, time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC), dayStart)
+	assert.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), monthStart)
+}
+
+func TestLimitService_CheckTransfer(t *testing.T) {
+	t.Run("uses the user's tier", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ls := &limitService{queries: mockQueries}
+
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(gen.User{UserID: "user_1", Tier: "premium"}, nil)
+		mockQueries.On("GetTransferLimit", mock.Anything, gen.GetTransferLimitParams{
+			Tier:            "premium",
+			Currency:        "USD",
+			TransactionType: "external",
+		}).Return(gen.TransferLimit{Currency: "USD", DailyCountMax: sql.NullInt32{Int32: 2, Valid: true}}, nil)
+		mockQueries.On("GetTransferUsage", mock.Anything, mock.MatchedBy(func(arg gen.GetTransferUsageParams) bool {
+			return arg.UserID == "user_1" && arg.Currency == "USD" && arg.Type == "external" && !arg.MonthStart.After(arg.DayStart)
+		})).Return(gen.GetTransferUsageRow{DailyCount: 2, MonthlyCount: 2}, nil)
+
+		err := ls.CheckTransfer(context.Background(), "user_1", models.TransactionTypeExternal, money.NewMoney(100, money.USD))
+
+		assert.ErrorIs(t, err, utils.ErrLimitExceeded)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("no rule means no limit", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ls := &limitService{queries: mockQueries}
+
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(nil, sql.ErrNoRows)
+		mockQueries.On("GetTransferLimit", mock.Anything, gen.GetTransferLimitParams{
+			Tier:            defaultUserTier,
+			Currency:        "EUR",
+			TransactionType: "internal",
+		}).Return(nil, sql.ErrNoRows)
+
+		err := ls.CheckTransfer(context.Background(), "user_1", models.TransactionTypeInternal, money.NewMoney(100, money.EUR))
+
+		require.NoError(t, err)
+		mockQueries.AssertNotCalled(t, "GetTransferUsage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("locked check sees usage committed by earlier confirmations", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ls := &limitService{queries: mockQueries}
+
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(gen.User{UserID: "user_1", Tier: defaultUserTier}, nil)
+		mockQueries.On("GetTransferLimit", mock.Anything, mock.Anything).
+			Return(gen.TransferLimit{Currency: "USD", DailyAmountMax: sql.NullInt64{Int64: 100000, Valid: true}}, nil)
+		mockQueries.On("GetTransferUsage", mock.Anything, mock.Anything).
+			Return(gen.GetTransferUsageRow{DailyAmount: 90000, DailyCount: 1, MonthlyAmount: 90000, MonthlyCount: 1}, nil)
+
+		err := ls.CheckTransferLocked(context.Background(), &sql.Tx{}, "user_1", models.TransactionTypeExternal, money.NewMoney(20000, money.USD))
+
+		assert.ErrorIs(t, err, utils.ErrLimitExceeded)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("currency the user's KYC tier does not allow", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ls := &limitService{queries: mockQueries, kyc: KYCPolicy{Enabled: true}}
+
+		mockQueries.On("GetUserByID", mock.Anything, "user_3").Return(gen.User{UserID: "user_3", Tier: defaultUserTier}, nil)
+
+		err := ls.CheckTransfer(context.Background(), "user_3", models.TransactionTypeInternal, money.NewMoney(100, money.USD))
+
+		assert.ErrorIs(t, err, utils.ErrKYCRequired)
+		mockQueries.AssertNotCalled(t, "GetTransferLimit", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRecheckConfirmLimits(t *testing.T) {
+	wallet := &models.Wallet{ID: "wallet_1", UserID: "user_1", Currency: "USD"}
+	transaction := gen.Transaction{
+		ID:           "tx_1",
+		Type:         "external",
+		Status:       "initiated",
+		Amount:       8500,
+		Currency:     "EUR",
+		ExchangeRate: sql.NullString{String: "0.85", Valid: true},
+	}
+
+	t.Run("initiated transfer is checked under the wallet lock", func(t *testing.T) {
+		limits := new(mocks.MockLimitService)
+		tx := &sql.Tx{}
+		limits.On("CheckTransferLocked", mock.Anything, tx, "user_1", models.TransactionTypeExternal, money.NewMoney(10000, money.USD)).
+			Return(utils.LimitExceededErr("daily transfer amount limit exceeded"))
+
+		err := recheckConfirmLimits(context.Background(), tx, limits, transaction, wallet)
+
+		assert.ErrorIs(t, err, utils.ErrLimitExceeded)
+		limits.AssertExpectations(t)
+	})
+
+	t.Run("approved held transfer already counts towards usage", func(t *testing.T) {
+		limits := new(mocks.MockLimitService)
+		held := transaction
+		held.Status = "on_hold"
+
+		err := recheckConfirmLimits(context.Background(), &sql.Tx{}, limits, held, wallet)
+
+		require.NoError(t, err)
+		limits.AssertNotCalled(t, "CheckTransferLocked", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestLimitService_GetUserLimits(t *testing.T) {
+	mockQueries := new(mocks.MockQuerier)
+	ls := &limitService{queries: mockQueries}
+
+	mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(gen.User{UserID: "user_1", Tier: "standard"}, nil)
+	mockQueries.On("ListActiveTransferLimits", mock.Anything, "standard").Return([]gen.TransferLimit{{
+		Currency:         "USD",
+		TransactionType:  "external",
+		DailyAmountMax:   sql.NullInt64{Int64: 200000, Valid: true},
+		MonthlyCountMax:  sql.NullInt32{Int32: 10, Valid: true},
+		MonthlyAmountMax: sql.NullInt64{},
+	}}, nil)
+	mockQueries.On("GetTransferUsage", mock.Anything, mock.Anything).Return(gen.GetTransferUsageRow{
+		DailyAmount: 250000, DailyCount: 2, MonthlyAmount: 400000, MonthlyCount: 4,
+	}, nil)
+
+	limits, err := ls.GetUserLimits(context.Background(), "user_1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "standard", limits.Tier)
+	require.Len(t, limits.Limits, 1)
+	limit := limits.Limits[0]
+	assert.Nil(t, limit.PerTransactionMax)
+	assert.Equal(t, int64(200000), *limit.Daily.AmountLimit)
+	assert.Nil(t, limit.Monthly.AmountLimit)
+
+	resp := models.UserLimitsToResponse(limits)
+	assert.Equal(t, 0.0, *resp.Limits[0].Daily.AmountRemaining)
+	assert.Nil(t, resp.Limits[0].Daily.CountRemaining)
+	assert.Equal(t, int32(6), *resp.Limits[0].Monthly.CountRemaining)
+	assert.True(t, resp.Limits[0].Daily.ResetsAt.After(time.Now()))
+}
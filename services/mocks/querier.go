@@ -3,6 +3,7 @@ package mocks
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/IfedayoAwe/payment-processing-service/db/gen"
 	"github.com/stretchr/testify/mock"
@@ -208,3 +209,699 @@ func (m *MockQuerier) CleanupExpiredJobs(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
 }
+
+func (m *MockQuerier) ListActiveFeeRules(ctx context.Context, transactionType string) ([]gen.FeeRule, error) {
+	args := m.Called(ctx, transactionType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.FeeRule), args.Error(1)
+}
+
+func (m *MockQuerier) CreateFeeRevenueEntry(ctx context.Context, arg gen.CreateFeeRevenueEntryParams) (gen.LedgerEntry, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(gen.LedgerEntry), args.Error(1)
+}
+
+func (m *MockQuerier) GetFeeRevenueBalance(ctx context.Context, currency string) (int64, error) {
+	args := m.Called(ctx, currency)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) TransitionTransactionStatus(ctx context.Context, arg gen.TransitionTransactionStatusParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) CreateExternalSystemDebitEntry(ctx context.Context, arg gen.CreateExternalSystemDebitEntryParams) (gen.LedgerEntry, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.LedgerEntry{}, args.Error(1)
+	}
+	return args.Get(0).(gen.LedgerEntry), args.Error(1)
+}
+
+func (m *MockQuerier) GetTransactionByIDForUpdate(ctx context.Context, id string) (gen.Transaction, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return gen.Transaction{}, args.Error(1)
+	}
+	return args.Get(0).(gen.Transaction), args.Error(1)
+}
+
+func (m *MockQuerier) CreateRefund(ctx context.Context, arg gen.CreateRefundParams) (gen.Refund, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.Refund{}, args.Error(1)
+	}
+	return args.Get(0).(gen.Refund), args.Error(1)
+}
+
+func (m *MockQuerier) GetRefundByIdempotencyKey(ctx context.Context, idempotencyKey string) (gen.Refund, error) {
+	args := m.Called(ctx, idempotencyKey)
+	if args.Get(0) == nil {
+		return gen.Refund{}, args.Error(1)
+	}
+	return args.Get(0).(gen.Refund), args.Error(1)
+}
+
+func (m *MockQuerier) GetRefundTotals(ctx context.Context, transactionID string) (gen.GetRefundTotalsRow, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return gen.GetRefundTotalsRow{}, args.Error(1)
+	}
+	return args.Get(0).(gen.GetRefundTotalsRow), args.Error(1)
+}
+
+func (m *MockQuerier) ListRefundsByTransaction(ctx context.Context, transactionID string) ([]gen.Refund, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.Refund), args.Error(1)
+}
+
+func (m *MockQuerier) CreateTransactionStatusHistory(ctx context.Context, arg gen.CreateTransactionStatusHistoryParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) ListStaleInitiatedTransactionIDs(ctx context.Context, arg gen.ListStaleInitiatedTransactionIDsParams) ([]string, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockQuerier) AdjustWalletHeldBalance(ctx context.Context, arg gen.AdjustWalletHeldBalanceParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) CreateWalletHold(ctx context.Context, arg gen.CreateWalletHoldParams) (gen.WalletHold, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.WalletHold{}, args.Error(1)
+	}
+	return args.Get(0).(gen.WalletHold), args.Error(1)
+}
+
+func (m *MockQuerier) ReleaseWalletHold(ctx context.Context, arg gen.ReleaseWalletHoldParams) (gen.WalletHold, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.WalletHold{}, args.Error(1)
+	}
+	return args.Get(0).(gen.WalletHold), args.Error(1)
+}
+
+func (m *MockQuerier) GetPINAttempts(ctx context.Context, userID string) (gen.PinAttempt, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return gen.PinAttempt{}, args.Error(1)
+	}
+	return args.Get(0).(gen.PinAttempt), args.Error(1)
+}
+
+func (m *MockQuerier) LockPIN(ctx context.Context, arg gen.LockPINParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) ResetPINAttempts(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) SetInitialUserPIN(ctx context.Context, arg gen.SetInitialUserPINParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateUserPIN(ctx context.Context, arg gen.UpdateUserPINParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) CreatePINHistory(ctx context.Context, arg gen.CreatePINHistoryParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) ListRecentPINHashes(ctx context.Context, arg gen.ListRecentPINHashesParams) ([]string, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockQuerier) CreatePINResetToken(ctx context.Context, arg gen.CreatePINResetTokenParams) (gen.PinResetToken, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.PinResetToken{}, args.Error(1)
+	}
+	return args.Get(0).(gen.PinResetToken), args.Error(1)
+}
+
+func (m *MockQuerier) RevokePINResetTokens(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) ConsumePINResetToken(ctx context.Context, tokenHash string) (gen.PinResetToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return gen.PinResetToken{}, args.Error(1)
+	}
+	return args.Get(0).(gen.PinResetToken), args.Error(1)
+}
+
+func (m *MockQuerier) GetUserTOTP(ctx context.Context, userID string) (gen.UserTotp, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return gen.UserTotp{}, args.Error(1)
+	}
+	return args.Get(0).(gen.UserTotp), args.Error(1)
+}
+
+func (m *MockQuerier) UpsertPendingUserTOTP(ctx context.Context, arg gen.UpsertPendingUserTOTPParams) (gen.UserTotp, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.UserTotp{}, args.Error(1)
+	}
+	return args.Get(0).(gen.UserTotp), args.Error(1)
+}
+
+func (m *MockQuerier) ConfirmUserTOTP(ctx context.Context, arg gen.ConfirmUserTOTPParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) UseTOTPStep(ctx context.Context, arg gen.UseTOTPStepParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) DeleteUserTOTP(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) CreateTOTPRecoveryCode(ctx context.Context, arg gen.CreateTOTPRecoveryCodeParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) UseTOTPRecoveryCode(ctx context.Context, arg gen.UseTOTPRecoveryCodeParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) DeleteTOTPRecoveryCodes(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) TakeRateLimitToken(ctx context.Context, arg gen.TakeRateLimitTokenParams) (float64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockQuerier) GetRateLimitTokens(ctx context.Context, arg gen.GetRateLimitTokensParams) (float64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockQuerier) DeleteStaleRateLimitBuckets(ctx context.Context, updatedAt time.Time) error {
+	args := m.Called(ctx, updatedAt)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) GetTransferLimit(ctx context.Context, arg gen.GetTransferLimitParams) (gen.TransferLimit, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.TransferLimit{}, args.Error(1)
+	}
+	return args.Get(0).(gen.TransferLimit), args.Error(1)
+}
+
+func (m *MockQuerier) ListActiveTransferLimits(ctx context.Context, tier string) ([]gen.TransferLimit, error) {
+	args := m.Called(ctx, tier)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.TransferLimit), args.Error(1)
+}
+
+func (m *MockQuerier) GetTransferUsage(ctx context.Context, arg gen.GetTransferUsageParams) (gen.GetTransferUsageRow, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.GetTransferUsageRow{}, args.Error(1)
+	}
+	return args.Get(0).(gen.GetTransferUsageRow), args.Error(1)
+}
+
+func (m *MockQuerier) CreateRiskAssessment(ctx context.Context, arg gen.CreateRiskAssessmentParams) (gen.RiskAssessment, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.RiskAssessment{}, args.Error(1)
+	}
+	return args.Get(0).(gen.RiskAssessment), args.Error(1)
+}
+
+func (m *MockQuerier) GetLatestRiskAssessment(ctx context.Context, transactionID string) (gen.RiskAssessment, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return gen.RiskAssessment{}, args.Error(1)
+	}
+	return args.Get(0).(gen.RiskAssessment), args.Error(1)
+}
+
+func (m *MockQuerier) ListPendingRiskReviews(ctx context.Context, limit int32) ([]gen.RiskAssessment, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.RiskAssessment), args.Error(1)
+}
+
+func (m *MockQuerier) RecordRiskReview(ctx context.Context, arg gen.RecordRiskReviewParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) ListRiskRecipientsToIndex(ctx context.Context, arg gen.ListRiskRecipientsToIndexParams) ([]gen.ListRiskRecipientsToIndexRow, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.ListRiskRecipientsToIndexRow), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateRiskAssessmentRecipient(ctx context.Context, arg gen.UpdateRiskAssessmentRecipientParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) CountRecipientTransfers(ctx context.Context, arg gen.CountRecipientTransfersParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) GetRiskTransferHistory(ctx context.Context, arg gen.GetRiskTransferHistoryParams) (gen.GetRiskTransferHistoryRow, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.GetRiskTransferHistoryRow{}, args.Error(1)
+	}
+	return args.Get(0).(gen.GetRiskTransferHistoryRow), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateTransactionScreening(ctx context.Context, arg gen.UpdateTransactionScreeningParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) CreateAuditEvent(ctx context.Context, arg gen.CreateAuditEventParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) GetAuditChainHead(ctx context.Context) (gen.AuditChainHead, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return gen.AuditChainHead{}, args.Error(1)
+	}
+	return args.Get(0).(gen.AuditChainHead), args.Error(1)
+}
+
+func (m *MockQuerier) ListAuditEvents(ctx context.Context, arg gen.ListAuditEventsParams) ([]gen.AuditLog, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.AuditLog), args.Error(1)
+}
+
+func (m *MockQuerier) ListAuditEventsFromSequence(ctx context.Context, arg gen.ListAuditEventsFromSequenceParams) ([]gen.AuditLog, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.AuditLog), args.Error(1)
+}
+
+func (m *MockQuerier) ListBankAccountsToEncrypt(ctx context.Context, arg gen.ListBankAccountsToEncryptParams) ([]gen.ListBankAccountsToEncryptRow, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.ListBankAccountsToEncryptRow), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateBankAccountNumberEncryption(ctx context.Context, arg gen.UpdateBankAccountNumberEncryptionParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) ListTransactionsToEncrypt(ctx context.Context, arg gen.ListTransactionsToEncryptParams) ([]gen.ListTransactionsToEncryptRow, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.ListTransactionsToEncryptRow), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateTransactionProviderReferenceEncryption(ctx context.Context, arg gen.UpdateTransactionProviderReferenceEncryptionParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) ListWebhookEventsToEncrypt(ctx context.Context, arg gen.ListWebhookEventsToEncryptParams) ([]gen.ListWebhookEventsToEncryptRow, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.ListWebhookEventsToEncryptRow), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateWebhookEventPayloadEncryption(ctx context.Context, arg gen.UpdateWebhookEventPayloadEncryptionParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) CreateTransferRecipient(ctx context.Context, arg gen.CreateTransferRecipientParams) (gen.TransferRecipient, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.TransferRecipient{}, args.Error(1)
+	}
+	return args.Get(0).(gen.TransferRecipient), args.Error(1)
+}
+
+func (m *MockQuerier) GetTransferRecipientByTransactionID(ctx context.Context, transactionID string) (gen.TransferRecipient, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return gen.TransferRecipient{}, args.Error(1)
+	}
+	return args.Get(0).(gen.TransferRecipient), args.Error(1)
+}
+
+func (m *MockQuerier) ListTransferRecipientsByTransactionIDs(ctx context.Context, transactionIds []string) ([]gen.TransferRecipient, error) {
+	args := m.Called(ctx, transactionIds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.TransferRecipient), args.Error(1)
+}
+
+func (m *MockQuerier) ListTransferRecipientsToEncrypt(ctx context.Context, arg gen.ListTransferRecipientsToEncryptParams) ([]gen.ListTransferRecipientsToEncryptRow, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.ListTransferRecipientsToEncryptRow), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateTransferRecipientAccountNumberEncryption(ctx context.Context, arg gen.UpdateTransferRecipientAccountNumberEncryptionParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) CreateBeneficiary(ctx context.Context, arg gen.CreateBeneficiaryParams) (gen.Beneficiary, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.Beneficiary{}, args.Error(1)
+	}
+	return args.Get(0).(gen.Beneficiary), args.Error(1)
+}
+
+func (m *MockQuerier) GetBeneficiaryForUser(ctx context.Context, arg gen.GetBeneficiaryForUserParams) (gen.Beneficiary, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.Beneficiary{}, args.Error(1)
+	}
+	return args.Get(0).(gen.Beneficiary), args.Error(1)
+}
+
+func (m *MockQuerier) GetBeneficiaryByAccount(ctx context.Context, arg gen.GetBeneficiaryByAccountParams) (gen.Beneficiary, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.Beneficiary{}, args.Error(1)
+	}
+	return args.Get(0).(gen.Beneficiary), args.Error(1)
+}
+
+func (m *MockQuerier) ListBeneficiariesByUser(ctx context.Context, userID string) ([]gen.Beneficiary, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.Beneficiary), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateBeneficiary(ctx context.Context, arg gen.UpdateBeneficiaryParams) (gen.Beneficiary, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.Beneficiary{}, args.Error(1)
+	}
+	return args.Get(0).(gen.Beneficiary), args.Error(1)
+}
+
+func (m *MockQuerier) DeleteBeneficiary(ctx context.Context, arg gen.DeleteBeneficiaryParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) GetBeneficiaryVerifiedAt(ctx context.Context, id string) (time.Time, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return time.Time{}, args.Error(1)
+	}
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockQuerier) ListBeneficiariesToEncrypt(ctx context.Context, arg gen.ListBeneficiariesToEncryptParams) ([]gen.ListBeneficiariesToEncryptRow, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.ListBeneficiariesToEncryptRow), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateBeneficiaryAccountNumberEncryption(ctx context.Context, arg gen.UpdateBeneficiaryAccountNumberEncryptionParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) GetBank(ctx context.Context, code string) (gen.Bank, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return gen.Bank{}, args.Error(1)
+	}
+	return args.Get(0).(gen.Bank), args.Error(1)
+}
+
+func (m *MockQuerier) ListBanks(ctx context.Context, arg gen.ListBanksParams) ([]gen.Bank, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.Bank), args.Error(1)
+}
+
+func (m *MockQuerier) UpsertBank(ctx context.Context, arg gen.UpsertBankParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) DeactivateBanksNotIn(ctx context.Context, codes []string) (int64, error) {
+	args := m.Called(ctx, codes)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) CreateBankAccount(ctx context.Context, arg gen.CreateBankAccountParams) (gen.BankAccount, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.BankAccount{}, args.Error(1)
+	}
+	return args.Get(0).(gen.BankAccount), args.Error(1)
+}
+
+func (m *MockQuerier) NextVirtualAccountNumber(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateWalletStatus(ctx context.Context, arg gen.UpdateWalletStatusParams) (gen.Wallet, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.Wallet{}, args.Error(1)
+	}
+	return args.Get(0).(gen.Wallet), args.Error(1)
+}
+
+func (m *MockQuerier) CreateUser(ctx context.Context, arg gen.CreateUserParams) (gen.User, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.User{}, args.Error(1)
+	}
+	return args.Get(0).(gen.User), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateUserProfile(ctx context.Context, arg gen.UpdateUserProfileParams) (gen.User, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.User{}, args.Error(1)
+	}
+	return args.Get(0).(gen.User), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateUserKYCTier(ctx context.Context, arg gen.UpdateUserKYCTierParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) CreateKYCDocument(ctx context.Context, arg gen.CreateKYCDocumentParams) (gen.KycDocument, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.KycDocument{}, args.Error(1)
+	}
+	return args.Get(0).(gen.KycDocument), args.Error(1)
+}
+
+func (m *MockQuerier) GetKYCDocumentByIDForUpdate(ctx context.Context, id string) (gen.KycDocument, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return gen.KycDocument{}, args.Error(1)
+	}
+	return args.Get(0).(gen.KycDocument), args.Error(1)
+}
+
+func (m *MockQuerier) ListKYCDocumentsByUser(ctx context.Context, userID string) ([]gen.KycDocument, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.KycDocument), args.Error(1)
+}
+
+func (m *MockQuerier) ListKYCDocumentsByStatus(ctx context.Context, arg gen.ListKYCDocumentsByStatusParams) ([]gen.KycDocument, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.KycDocument), args.Error(1)
+}
+
+func (m *MockQuerier) ListApprovedKYCCategories(ctx context.Context, userID string) ([]string, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockQuerier) ReviewKYCDocument(ctx context.Context, arg gen.ReviewKYCDocumentParams) (gen.KycDocument, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.KycDocument{}, args.Error(1)
+	}
+	return args.Get(0).(gen.KycDocument), args.Error(1)
+}
+
+func (m *MockQuerier) ListKYCDocumentsToEncrypt(ctx context.Context, arg gen.ListKYCDocumentsToEncryptParams) ([]gen.ListKYCDocumentsToEncryptRow, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.ListKYCDocumentsToEncryptRow), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateKYCDocumentNumberEncryption(ctx context.Context, arg gen.UpdateKYCDocumentNumberEncryptionParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) GetDepositByProviderReference(ctx context.Context, arg gen.GetDepositByProviderReferenceParams) (gen.Transaction, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.Transaction{}, args.Error(1)
+	}
+	return args.Get(0).(gen.Transaction), args.Error(1)
+}
+
+func (m *MockQuerier) CreateDepositTransaction(ctx context.Context, arg gen.CreateDepositTransactionParams) (gen.Transaction, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.Transaction{}, args.Error(1)
+	}
+	return args.Get(0).(gen.Transaction), args.Error(1)
+}
+
+func (m *MockQuerier) CreatePaymentRequest(ctx context.Context, arg gen.CreatePaymentRequestParams) (gen.PaymentRequest, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.PaymentRequest{}, args.Error(1)
+	}
+	return args.Get(0).(gen.PaymentRequest), args.Error(1)
+}
+
+func (m *MockQuerier) GetPaymentRequestByToken(ctx context.Context, token string) (gen.PaymentRequest, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return gen.PaymentRequest{}, args.Error(1)
+	}
+	return args.Get(0).(gen.PaymentRequest), args.Error(1)
+}
+
+func (m *MockQuerier) GetPaymentRequestByIDForUpdate(ctx context.Context, id string) (gen.PaymentRequest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return gen.PaymentRequest{}, args.Error(1)
+	}
+	return args.Get(0).(gen.PaymentRequest), args.Error(1)
+}
+
+func (m *MockQuerier) ListPaymentRequestsByRequester(ctx context.Context, arg gen.ListPaymentRequestsByRequesterParams) ([]gen.PaymentRequest, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.PaymentRequest), args.Error(1)
+}
+
+func (m *MockQuerier) TransitionPaymentRequestStatus(ctx context.Context, arg gen.TransitionPaymentRequestStatusParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) MarkPaymentRequestPaid(ctx context.Context, arg gen.MarkPaymentRequestPaidParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) ExpirePaymentRequests(ctx context.Context, batchSize int32) ([]gen.ExpirePaymentRequestsRow, error) {
+	args := m.Called(ctx, batchSize)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]gen.ExpirePaymentRequestsRow), args.Error(1)
+}
+
+func (m *MockQuerier) LockFeeRevenueAccount(ctx context.Context, currency string) error {
+	args := m.Called(ctx, currency)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) ClaimPINAttempt(ctx context.Context, arg gen.ClaimPINAttemptParams) (gen.PinAttempt, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return gen.PinAttempt{}, args.Error(1)
+	}
+	return args.Get(0).(gen.PinAttempt), args.Error(1)
+}
+
+func (m *MockQuerier) ClearPINAttempts(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
 	"github.com/IfedayoAwe/payment-processing-service/models"
 	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
 	"github.com/stretchr/testify/mock"
@@ -61,6 +62,38 @@ func (m *MockWalletService) LockWalletByUserAndCurrency(ctx context.Context, tx
 	return args.Get(0).(*models.Wallet), args.Error(1)
 }
 
+func (m *MockWalletService) OpenWallet(ctx context.Context, userID string, currency money.Currency) (*models.WalletWithBankAccount, error) {
+	args := m.Called(ctx, userID, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WalletWithBankAccount), args.Error(1)
+}
+
+func (m *MockWalletService) FreezeWallet(ctx context.Context, walletID string, direction models.FreezeDirection, reason string) (*models.Wallet, error) {
+	args := m.Called(ctx, walletID, direction, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Wallet), args.Error(1)
+}
+
+func (m *MockWalletService) UnfreezeWallet(ctx context.Context, walletID string, reason string) (*models.Wallet, error) {
+	args := m.Called(ctx, walletID, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Wallet), args.Error(1)
+}
+
+func (m *MockWalletService) CloseWallet(ctx context.Context, walletID string, reason string) (*models.Wallet, error) {
+	args := m.Called(ctx, walletID, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Wallet), args.Error(1)
+}
+
 type MockLedgerService struct {
 	mock.Mock
 }
@@ -80,7 +113,89 @@ func (m *MockLedgerService) CreateExternalSystemCreditEntry(ctx context.Context,
 	return args.Error(0)
 }
 
+func (m *MockLedgerService) CreateExternalSystemDebitEntry(ctx context.Context, tx *sql.Tx, transactionID string, amount int64, currency money.Currency) error {
+	args := m.Called(ctx, tx, transactionID, amount, currency)
+	return args.Error(0)
+}
+
+func (m *MockLedgerService) CreateFeeRevenueEntry(ctx context.Context, tx *sql.Tx, transactionID string, amount int64, currency money.Currency) error {
+	args := m.Called(ctx, tx, transactionID, amount, currency)
+	return args.Error(0)
+}
+
 func (m *MockLedgerService) GetWalletBalance(ctx context.Context, tx *sql.Tx, walletID string, currency money.Currency) (int64, error) {
 	args := m.Called(ctx, tx, walletID, currency)
 	return args.Get(0).(int64), args.Error(1)
 }
+
+type MockLimitService struct {
+	mock.Mock
+}
+
+func (m *MockLimitService) CheckTransfer(ctx context.Context, userID string, transactionType models.TransactionType, amount money.Money) error {
+	args := m.Called(ctx, userID, transactionType, amount)
+	return args.Error(0)
+}
+
+func (m *MockLimitService) CheckTransferLocked(ctx context.Context, tx *sql.Tx, userID string, transactionType models.TransactionType, amount money.Money) error {
+	args := m.Called(ctx, tx, userID, transactionType, amount)
+	return args.Error(0)
+}
+
+func (m *MockLimitService) GetUserLimits(ctx context.Context, userID string) (*models.UserLimits, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserLimits), args.Error(1)
+}
+
+func (m *MockLimitService) GetTransferLimit(ctx context.Context, userID string, transactionType models.TransactionType, currency money.Currency) (*models.TransferLimitUsage, error) {
+	args := m.Called(ctx, userID, transactionType, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TransferLimitUsage), args.Error(1)
+}
+
+type MockRiskService struct {
+	mock.Mock
+}
+
+func (m *MockRiskService) Screen(ctx context.Context, transaction gen.Transaction, fromWallet *models.Wallet) (*models.RiskAssessment, error) {
+	args := m.Called(ctx, transaction, fromWallet)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RiskAssessment), args.Error(1)
+}
+
+func (m *MockRiskService) ListPendingReviews(ctx context.Context, limit int32) ([]*models.RiskAssessment, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.RiskAssessment), args.Error(1)
+}
+
+type MockBankService struct {
+	mock.Mock
+}
+
+func (m *MockBankService) ListBanks(ctx context.Context, search string, country string) ([]*models.Bank, error) {
+	args := m.Called(ctx, search, country)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Bank), args.Error(1)
+}
+
+func (m *MockBankService) ValidateBank(ctx context.Context, bankCode string, currency money.Currency) error {
+	args := m.Called(ctx, bankCode, currency)
+	return args.Error(0)
+}
+
+func (m *MockBankService) PreferredProvider(ctx context.Context, bankCode string) string {
+	args := m.Called(ctx, bankCode)
+	return args.String(0)
+}
@@ -4,32 +4,45 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/IfedayoAwe/payment-processing-service/db/gen"
 	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/namematch"
 	"github.com/IfedayoAwe/payment-processing-service/providers"
 	"github.com/IfedayoAwe/payment-processing-service/utils"
 )
 
 type NameEnquiryService interface {
 	EnquireAccountName(ctx context.Context, accountNumber, bankCode string) (*NameEnquiryResult, error)
+	MatchAccountName(supplied, resolved string) namematch.Result
 }
 
 type NameEnquiryResult struct {
 	AccountName string
 	IsInternal  bool
 	Currency    money.Currency
+	Country     string
 }
 
 type nameEnquiryService struct {
-	queries  *gen.Queries
-	provider *providers.Processor
+	queries    gen.Querier
+	banks      BankService
+	provider   *providers.Processor
+	fields     fieldCipher
+	cache      *nameEnquiryCache
+	thresholds namematch.Thresholds
 }
 
-func newNameEnquiryService(queries *gen.Queries, provider *providers.Processor) NameEnquiryService {
+func newNameEnquiryService(queries gen.Querier, banks BankService, provider *providers.Processor, fields fieldCipher, cacheTTL time.Duration, thresholds namematch.Thresholds) NameEnquiryService {
 	return &nameEnquiryService{
-		queries:  queries,
-		provider: provider,
+		queries:    queries,
+		banks:      banks,
+		provider:   provider,
+		fields:     fields,
+		cache:      newNameEnquiryCache(cacheTTL),
+		thresholds: thresholds,
 	}
 }
 
@@ -40,11 +53,11 @@ func (nes *nameEnquiryService) EnquireAccountName(ctx context.Context, accountNu
 	if bankCode == "" {
 		return nil, utils.BadRequestErr("bank_code is required")
 	}
+	if err := nes.banks.ValidateBank(ctx, bankCode, ""); err != nil {
+		return nil, err
+	}
 
-	bankAccount, err := nes.queries.GetBankAccountByAccountAndBankCode(ctx, gen.GetBankAccountByAccountAndBankCodeParams{
-		AccountNumber: accountNumber,
-		BankCode:      bankCode,
-	})
+	bankAccount, err := nes.queries.GetBankAccountByAccountAndBankCode(ctx, nes.fields.bankAccountLookup(accountNumber, bankCode))
 	if err == nil {
 		currency, parseErr := money.ParseCurrency(bankAccount.Currency)
 		if parseErr != nil {
@@ -67,6 +80,10 @@ func (nes *nameEnquiryService) EnquireAccountName(ctx context.Context, accountNu
 		return nil, utils.ServerErr(fmt.Errorf("check internal account: %w", err))
 	}
 
+	if cached, ok := nes.cache.get(accountNumber, bankCode); ok {
+		return &cached, nil
+	}
+
 	resp, err := nes.provider.NameEnquiry(ctx, providers.NameEnquiryRequest{
 		AccountNumber: accountNumber,
 		BankCode:      bankCode,
@@ -75,9 +92,111 @@ func (nes *nameEnquiryService) EnquireAccountName(ctx context.Context, accountNu
 		return nil, utils.ServerErr(fmt.Errorf("external name enquiry failed: %w", err))
 	}
 
-	return &NameEnquiryResult{
+	result := NameEnquiryResult{
 		AccountName: resp.AccountName,
 		IsInternal:  false,
 		Currency:    resp.Currency,
-	}, nil
+		Country:     resp.Country,
+	}
+	nes.cache.set(accountNumber, bankCode, result)
+	return &result, nil
+}
+
+// MatchAccountName scores an account name supplied by a user against the
+// name enquiry returned for the account.
+func (nes *nameEnquiryService) MatchAccountName(supplied, resolved string) namematch.Result {
+	return namematch.Compare(supplied, resolved, nes.thresholds)
+}
+
+// checkAccountName rejects a transfer whose supplied account name is a
+// mismatch for the resolved one. Partial matches are allowed. An empty
+// supplied name is not checked.
+func checkAccountName(nameEnquiry NameEnquiryService, supplied, resolved string) error {
+	if supplied == "" {
+		return nil
+	}
+	if nameEnquiry.MatchAccountName(supplied, resolved).Verdict == namematch.VerdictMismatch {
+		return utils.NameMismatchErr("account name does not match the recipient account")
+	}
+	return nil
+}
+
+// nameEnquiryCacheSize caps the number of cached results, so a caller
+// enquiring many accounts cannot grow the cache without bound.
+const nameEnquiryCacheSize = 10000
+
+// nameEnquiryCache keeps external name enquiry results for ttl, so repeat
+// lookups of the same account, such as creating and then confirming a
+// transfer, do not call the provider each time. Internal accounts are read
+// from the database and not cached. A nil cache stores nothing.
+type nameEnquiryCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[nameEnquiryCacheKey]nameEnquiryCacheEntry
+}
+
+type nameEnquiryCacheKey struct {
+	accountNumber string
+	bankCode      string
+}
+
+type nameEnquiryCacheEntry struct {
+	result    NameEnquiryResult
+	expiresAt time.Time
+}
+
+func newNameEnquiryCache(ttl time.Duration) *nameEnquiryCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &nameEnquiryCache{
+		ttl:     ttl,
+		entries: make(map[nameEnquiryCacheKey]nameEnquiryCacheEntry),
+	}
+}
+
+func (c *nameEnquiryCache) get(accountNumber, bankCode string) (NameEnquiryResult, bool) {
+	if c == nil {
+		return NameEnquiryResult{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := nameEnquiryCacheKey{accountNumber: accountNumber, bankCode: bankCode}
+	entry, ok := c.entries[key]
+	if !ok {
+		return NameEnquiryResult{}, false
+	}
+	if !time.Now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return NameEnquiryResult{}, false
+	}
+	return entry.result, true
+}
+
+func (c *nameEnquiryCache) set(accountNumber, bankCode string, result NameEnquiryResult) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= nameEnquiryCacheSize {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, key)
+			}
+		}
+		if len(c.entries) >= nameEnquiryCacheSize {
+			return
+		}
+	}
+
+	c.entries[nameEnquiryCacheKey{accountNumber: accountNumber, bankCode: bankCode}] = nameEnquiryCacheEntry{
+		result:    result,
+		expiresAt: now.Add(c.ttl),
+	}
 }
@@ -2,12 +2,20 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/IfedayoAwe/payment-processing-service/db/gen"
 	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/namematch"
 	"github.com/IfedayoAwe/payment-processing-service/providers"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNameEnquiryService_EnquireAccountName_Validation(t *testing.T) {
@@ -37,12 +45,150 @@ func TestNameEnquiryService_EnquireAccountName_Validation(t *testing.T) {
 	})
 }
 
+func TestNameEnquiryService_EnquireAccountName_Cache(t *testing.T) {
+	mockQueries := new(mocks.MockQuerier)
+	mockQueries.On("GetBankAccountByAccountAndBankCode", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+
+	countingProvider := &countingNameEnquiryProvider{accountName: "Mock External Account"}
+	processor := providers.NewProcessor()
+	processor.RegisterNameEnquiryProvider(countingProvider)
+
+	nes := &nameEnquiryService{queries: mockQueries, banks: knownBanks(), provider: processor, cache: newNameEnquiryCache(time.Minute)}
+
+	first, err := nes.EnquireAccountName(context.Background(), "9999999999", "044")
+	require.NoError(t, err)
+	second, err := nes.EnquireAccountName(context.Background(), "9999999999", "044")
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, countingProvider.calls)
+
+	_, err = nes.EnquireAccountName(context.Background(), "9999999999", "058")
+	require.NoError(t, err)
+	assert.Equal(t, 2, countingProvider.calls, "a different bank code is a different account")
+
+	t.Run("expired results are fetched again", func(t *testing.T) {
+		key := nameEnquiryCacheKey{accountNumber: "9999999999", bankCode: "044"}
+		entry := nes.cache.entries[key]
+		entry.expiresAt = time.Now().Add(-time.Second)
+		nes.cache.entries[key] = entry
+
+		_, err := nes.EnquireAccountName(context.Background(), "9999999999", "044")
+		require.NoError(t, err)
+		assert.Equal(t, 3, countingProvider.calls)
+	})
+
+	t.Run("failures are not cached", func(t *testing.T) {
+		countingProvider.err = errors.New("provider unavailable")
+		_, err := nes.EnquireAccountName(context.Background(), "1111111111", "044")
+		assert.Error(t, err)
+
+		countingProvider.err = nil
+		_, err = nes.EnquireAccountName(context.Background(), "1111111111", "044")
+		assert.NoError(t, err)
+		assert.Equal(t, 5, countingProvider.calls)
+	})
+}
+
+func TestNameEnquiryService_EnquireAccountName_ProviderFallback(t *testing.T) {
+	mockQueries := new(mocks.MockQuerier)
+	mockQueries.On("GetBankAccountByAccountAndBankCode", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+
+	failing := &countingNameEnquiryProvider{err: errors.New("timeout")}
+	working := &countingNameEnquiryProvider{accountName: "Mock External Account"}
+	processor := providers.NewProcessor()
+	processor.RegisterNameEnquiryProvider(failing)
+	processor.RegisterNameEnquiryProvider(working)
+
+	nes := &nameEnquiryService{queries: mockQueries, banks: knownBanks(), provider: processor}
+
+	result, err := nes.EnquireAccountName(context.Background(), "9999999999", "044")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Mock External Account", result.AccountName)
+	assert.Equal(t, 1, failing.calls)
+	assert.Equal(t, 1, working.calls)
+}
+
+func TestNameEnquiryService_EnquireAccountName_PreferredProvider(t *testing.T) {
+	mockQueries := new(mocks.MockQuerier)
+	mockQueries.On("GetBankAccountByAccountAndBankCode", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+
+	first := &countingNameEnquiryProvider{name: "currencycloud", accountName: "First Provider"}
+	preferred := &countingNameEnquiryProvider{name: "dLocal", accountName: "Preferred Provider"}
+	processor := providers.NewProcessor()
+	processor.RegisterNameEnquiryProvider(first)
+	processor.RegisterNameEnquiryProvider(preferred)
+
+	banks := new(mocks.MockBankService)
+	banks.On("ValidateBank", mock.Anything, "058", money.Currency("")).Return(nil)
+	banks.On("PreferredProvider", mock.Anything, "058").Return("dlocal")
+	processor.SetBankRouter(banks)
+
+	nes := &nameEnquiryService{queries: mockQueries, banks: banks, provider: processor}
+
+	result, err := nes.EnquireAccountName(context.Background(), "9999999999", "058")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Preferred Provider", result.AccountName)
+	assert.Equal(t, 0, first.calls)
+	assert.Equal(t, 1, preferred.calls)
+}
+
+func TestNameEnquiryService_EnquireAccountName_UnknownBank(t *testing.T) {
+	banks := new(mocks.MockBankService)
+	banks.On("ValidateBank", mock.Anything, "999", money.Currency("")).Return(utils.BadRequestErr("unknown bank_code"))
+
+	nes := &nameEnquiryService{queries: new(mocks.MockQuerier), banks: banks}
+
+	result, err := nes.EnquireAccountName(context.Background(), "9999999999", "999")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+}
+
+func TestCheckAccountName(t *testing.T) {
+	nes := &nameEnquiryService{thresholds: namematch.Thresholds{Match: 0.9, Partial: 0.75}}
+
+	assert.NoError(t, checkAccountName(nes, "", "John Doe"), "no supplied name is not checked")
+	assert.NoError(t, checkAccountName(nes, "DOE John", "John Doe"))
+	assert.NoError(t, checkAccountName(nes, "K. Doe", "John Doe"), "partial matches are allowed")
+	assert.ErrorIs(t, checkAccountName(nes, "John Doe Fraudster", "John Doe"), utils.ErrNameMismatch, "extra words are not ignored")
+	assert.ErrorIs(t, checkAccountName(nes, "Jane Smith", "John Doe"), utils.ErrNameMismatch)
+	assert.ErrorIs(t, checkAccountName(nes, "John Doe", ""), utils.ErrNameMismatch, "an account without a name cannot be matched")
+}
+
+// countingNameEnquiryProvider answers with accountName, or err when set, and
+// counts the calls it receives.
+type countingNameEnquiryProvider struct {
+	name        string
+	accountName string
+	err         error
+	calls       int
+}
+
+func (p *countingNameEnquiryProvider) NameEnquiry(ctx context.Context, req providers.NameEnquiryRequest) (*providers.NameEnquiryResponse, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &providers.NameEnquiryResponse{AccountName: p.accountName, Currency: money.USD, Country: "US"}, nil
+}
+
+func (p *countingNameEnquiryProvider) Name() string {
+	if p.name != "" {
+		return p.name
+	}
+	return "counting"
+}
+
 type mockCurrencyCloudProvider struct{}
 
 func (m *mockCurrencyCloudProvider) NameEnquiry(ctx context.Context, req providers.NameEnquiryRequest) (*providers.NameEnquiryResponse, error) {
 	return &providers.NameEnquiryResponse{
 		AccountName: "Mock External Account",
 		Currency:    money.USD,
+		Country:     "US",
 	}, nil
 }
 
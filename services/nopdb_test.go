@@ -0,0 +1,45 @@
+package services
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+)
+
+// nopDriver backs a *sql.DB whose transactions begin, commit and roll back
+// without doing anything. It lets tests drive code that opens a transaction
+// while its queries go to a MockQuerier, which is never bound to the
+// transaction.
+type nopDriver struct{}
+
+type nopConn struct{}
+
+type nopTx struct{}
+
+func init() {
+	sql.Register("services_nop", nopDriver{})
+}
+
+func (nopDriver) Open(string) (driver.Conn, error) { return nopConn{}, nil }
+
+func (nopConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("nop driver: queries must go through the mock querier")
+}
+
+func (nopConn) Close() error { return nil }
+
+func (nopConn) Begin() (driver.Tx, error) { return nopTx{}, nil }
+
+func (nopTx) Commit() error { return nil }
+
+func (nopTx) Rollback() error { return nil }
+
+// newNopDB returns a *sql.DB on nopDriver. Opening it never fails, since the
+// driver is registered in init and connections are never dialled.
+func newNopDB() *sql.DB {
+	db, err := sql.Open("services_nop", "")
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
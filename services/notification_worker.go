@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/queue"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+)
+
+type NotificationWorker interface {
+	ProcessNotificationJob(ctx context.Context, job *queue.Job) error
+	StartWorker(ctx context.Context) error
+}
+
+type notificationWorker struct {
+	queue queue.Queue
+}
+
+func newNotificationWorker(queue queue.Queue) NotificationWorker {
+	return &notificationWorker{
+		queue: queue,
+	}
+}
+
+// ProcessNotificationJob delivers a notification to the user it names. There
+// is no push or email channel yet, so delivery is a structured log line that
+// a downstream consumer can pick up.
+func (nw *notificationWorker) ProcessNotificationJob(ctx context.Context, job *queue.Job) error {
+	var payload queue.NotificationJobPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("unmarshal notification job payload: %w", err)
+	}
+
+	utils.Logger.Info().
+		Str("trace_id", payload.TraceID).
+		Str("event", payload.Event).
+		Str("user_id", payload.UserID).
+		Str("payment_request_id", payload.PaymentRequestID).
+		Str("transaction_id", payload.TransactionID).
+		Int64("amount", payload.Amount).
+		Str("currency", payload.Currency).
+		Msg("notification delivered")
+
+	return nil
+}
+
+func (nw *notificationWorker) StartWorker(ctx context.Context) error {
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := nw.queue.Process(ctx, queue.JobTypeNotification, nw.ProcessNotificationJob, 5*time.Second); err != nil {
+				traceID := utils.TraceIDFromContext(ctx)
+				utils.Logger.Error().Err(err).Str("trace_id", traceID).Str("job_type", string(queue.JobTypeNotification)).Msg("error processing notification job")
+			}
+		}
+	}
+}
@@ -152,6 +152,13 @@ func (ow *outboxWorker) processOutboxEntry(ctx context.Context, entry gen.Outbox
 		}
 		return ow.queue.Enqueue(ctx, queue.JobTypeWebhook, payload)
 
+	case queue.JobTypeNotification:
+		var payload queue.NotificationJobPayload
+		if err := json.Unmarshal(entry.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal notification payload: %w", err)
+		}
+		return ow.queue.Enqueue(ctx, queue.JobTypeNotification, payload)
+
 	default:
 		return fmt.Errorf("unknown job type: %s", entry.JobType)
 	}
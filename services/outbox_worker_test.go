@@ -61,8 +61,6 @@ func TestOutboxWorker_ProcessOutboxEntry_Payout(t *testing.T) {
 		TraceID:       "trace_123",
 		Amount:        10000,
 		Currency:      "USD",
-		AccountNumber: "1234567890",
-		BankCode:      "044",
 	}
 	payloadJSON, _ := json.Marshal(payload)
 
@@ -184,8 +182,6 @@ func TestOutboxWorker_ProcessOutboxEntry_EnqueueFailure(t *testing.T) {
 		TraceID:       "trace_123",
 		Amount:        10000,
 		Currency:      "USD",
-		AccountNumber: "1234567890",
-		BankCode:      "044",
 	}
 	payloadJSON, _ := json.Marshal(payload)
 
@@ -18,10 +18,14 @@ import (
 
 type PaymentService interface {
 	GetExchangeRate(ctx context.Context, fromCurrency, toCurrency money.Currency) (float64, error)
-	CreateInternalTransfer(ctx context.Context, fromUserID string, toAccountNumber string, toBankCode string, fromCurrency money.Currency, toAmount money.Money, idempotencyKey string) (*models.Transaction, error)
-	CreateExternalTransfer(ctx context.Context, userID string, toAccountNumber string, toBankCode string, fromCurrency money.Currency, toAmount money.Money, idempotencyKey string) (*models.Transaction, error)
-	ConfirmTransaction(ctx context.Context, transactionID string, userID string, pin string) (*models.Transaction, error)
-	GetTransactionByID(ctx context.Context, transactionID string) (*models.Transaction, error)
+	CreateInternalTransfer(ctx context.Context, fromUserID string, toAccountNumber string, toBankCode string, toAccountName string, beneficiaryID string, fromCurrency money.Currency, toAmount money.Money, idempotencyKey string) (*models.Transaction, error)
+	CreatePaymentRequestTransfer(ctx context.Context, fromUserID string, request *models.PaymentRequest, fromCurrency money.Currency, idempotencyKey string) (*models.Transaction, error)
+	CreateExternalTransfer(ctx context.Context, userID string, toAccountNumber string, toBankCode string, toAccountName string, beneficiaryID string, fromCurrency money.Currency, toAmount money.Money, idempotencyKey string) (*models.Transaction, error)
+	ConfirmTransaction(ctx context.Context, transactionID string, userID string, pin string, totpCode string) (*models.Transaction, error)
+	CancelTransaction(ctx context.Context, transactionID string, userID string) (*models.Transaction, error)
+	ReviewTransaction(ctx context.Context, transactionID string, adminUserID string, decision models.RiskReviewDecision, note string) (*models.Transaction, error)
+	ReverseTransaction(ctx context.Context, transactionID string, adminUserID string, reason string, idempotencyKey string) (*models.Transaction, error)
+	GetTransactionByID(ctx context.Context, transactionID string, userID string, isAdmin bool) (*models.Transaction, error)
 	GetTransactionByIdempotencyKey(ctx context.Context, idempotencyKey string) (*models.Transaction, error)
 	GetTransactionHistory(ctx context.Context, userID string, cursor string, limit int32) (*models.TransactionHistoryResponse, error)
 }
@@ -32,17 +36,41 @@ type paymentService struct {
 	wallet           WalletService
 	ledger           LedgerService
 	externalTransfer ExternalTransferService
+	fee              FeeService
+	pin              PINService
+	totp             TOTPService
+	stepUp           StepUpPolicy
+	limits           LimitService
+	risk             RiskService
+	sanctions        SanctionsService
+	beneficiaries    BeneficiaryService
+	nameEnquiry      NameEnquiryService
+	banks            BankService
 	provider         *providers.Processor
+	fields           fieldCipher
+	transactionTTL   time.Duration
 }
 
-func newPaymentService(queries gen.Querier, db *sql.DB, wallet WalletService, ledger LedgerService, externalTransfer ExternalTransferService, provider *providers.Processor) PaymentService {
+func newPaymentService(queries gen.Querier, db *sql.DB, wallet WalletService, ledger LedgerService, externalTransfer ExternalTransferService, fee FeeService, pin PINService, totp TOTPService, stepUp StepUpPolicy, limits LimitService, risk RiskService, sanctions SanctionsService, beneficiaries BeneficiaryService, nameEnquiry NameEnquiryService, banks BankService, provider *providers.Processor, fields fieldCipher, transactionTTL time.Duration) PaymentService {
 	return &paymentService{
 		queries:          queries,
 		db:               db,
 		wallet:           wallet,
 		ledger:           ledger,
 		externalTransfer: externalTransfer,
+		fee:              fee,
+		pin:              pin,
+		totp:             totp,
+		stepUp:           stepUp,
+		limits:           limits,
+		risk:             risk,
+		sanctions:        sanctions,
+		beneficiaries:    beneficiaries,
+		nameEnquiry:      nameEnquiry,
+		banks:            banks,
 		provider:         provider,
+		fields:           fields,
+		transactionTTL:   transactionTTL,
 	}
 }
 
@@ -57,17 +85,32 @@ func (ps *paymentService) GetExchangeRate(ctx context.Context, fromCurrency, toC
 	return rateResp.Rate, nil
 }
 
-func (ps *paymentService) CreateInternalTransfer(ctx context.Context, fromUserID string, toAccountNumber string, toBankCode string, fromCurrency money.Currency, toAmount money.Money, idempotencyKey string) (*models.Transaction, error) {
+func (ps *paymentService) CreateInternalTransfer(ctx context.Context, fromUserID string, toAccountNumber string, toBankCode string, toAccountName string, beneficiaryID string, fromCurrency money.Currency, toAmount money.Money, idempotencyKey string) (*models.Transaction, error) {
 	if !toAmount.IsPositive() {
 		return nil, utils.BadRequestErr("amount must be positive")
 	}
 
-	existing, err := ps.GetTransactionByIdempotencyKey(ctx, idempotencyKey)
+	existing, err := replayTransaction(ctx, ps.queries, idempotencyKey, fromUserID)
 	if err == nil && existing != nil {
 		return existing, nil
 	}
 	if err != nil && err != sql.ErrNoRows {
-		return nil, utils.ServerErr(fmt.Errorf("check idempotency: %w", err))
+		return nil, err
+	}
+
+	if beneficiaryID != "" {
+		beneficiary, err := ps.resolveBeneficiary(ctx, fromUserID, beneficiaryID, toAccountNumber, toBankCode)
+		if err != nil {
+			return nil, err
+		}
+		if !beneficiary.IsInternal {
+			return nil, utils.BadRequestErr("beneficiary is not an internal account")
+		}
+		toAccountNumber, toBankCode = beneficiary.AccountNumber, beneficiary.BankCode
+	}
+
+	if err := ps.banks.ValidateBank(ctx, toBankCode, toAmount.Currency); err != nil {
+		return nil, err
 	}
 
 	exchangeRate, err := ps.GetExchangeRate(ctx, fromCurrency, toAmount.Currency)
@@ -83,10 +126,7 @@ func (ps *paymentService) CreateInternalTransfer(ctx context.Context, fromUserID
 		return nil, err
 	}
 
-	bankAccount, err := ps.queries.GetBankAccountByAccountAndBankCode(ctx, gen.GetBankAccountByAccountAndBankCodeParams{
-		AccountNumber: toAccountNumber,
-		BankCode:      toBankCode,
-	})
+	bankAccount, err := ps.queries.GetBankAccountByAccountAndBankCode(ctx, ps.fields.bankAccountLookup(toAccountNumber, toBankCode))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, utils.NotFoundErr("recipient account not found")
@@ -98,6 +138,10 @@ func (ps *paymentService) CreateInternalTransfer(ctx context.Context, fromUserID
 		return nil, utils.BadRequestErr("recipient account currency mismatch")
 	}
 
+	if err := checkAccountName(ps.nameEnquiry, toAccountName, bankAccount.AccountName.String); err != nil {
+		return nil, err
+	}
+
 	toWallet, err := ps.wallet.GetWalletByBankAccount(ctx, bankAccount.ID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -110,14 +154,83 @@ func (ps *paymentService) CreateInternalTransfer(ctx context.Context, fromUserID
 		return nil, utils.BadRequestErr("cannot transfer to same wallet")
 	}
 
+	if err := checkWalletDebit(fromWallet, "sender"); err != nil {
+		return nil, err
+	}
+	if err := checkWalletCredit(toWallet, "recipient"); err != nil {
+		return nil, err
+	}
+
+	fromAmount := money.NewMoney(int64(float64(toAmount.Amount)/exchangeRate), fromCurrency)
+	fee, err := ps.fee.CalculateFee(ctx, models.TransactionTypeInternal, fromAmount, toAmount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
 	if fromWallet.UserID == toWallet.UserID {
-		return ps.processInternalTransferImmediate(ctx, fromWallet, toWallet, fromCurrency, toAmount, exchangeRate, idempotencyKey)
+		return ps.processInternalTransferImmediate(ctx, fromWallet, toWallet, fromCurrency, toAmount, exchangeRate, fee, beneficiaryID, idempotencyKey)
 	}
 
-	return ps.createInitiatedInternalTransfer(ctx, fromWallet, toWallet, toAmount, exchangeRate, idempotencyKey)
+	if err := ps.limits.CheckTransfer(ctx, fromUserID, models.TransactionTypeInternal, fromAmount); err != nil {
+		return nil, err
+	}
+
+	return ps.createInitiatedInternalTransfer(ctx, fromWallet, toWallet, toAmount, exchangeRate, fee, beneficiaryID, "", idempotencyKey)
 }
 
-func (ps *paymentService) processInternalTransferImmediate(ctx context.Context, fromWallet *models.Wallet, toWallet *models.Wallet, fromCurrency money.Currency, toAmount money.Money, exchangeRate float64, idempotencyKey string) (*models.Transaction, error) {
+// CreatePaymentRequestTransfer initiates the internal transfer that pays a
+// payment request. The recipient wallet, amount and currency come from the
+// request; the caller has already checked that it is still pending.
+func (ps *paymentService) CreatePaymentRequestTransfer(ctx context.Context, fromUserID string, request *models.PaymentRequest, fromCurrency money.Currency, idempotencyKey string) (*models.Transaction, error) {
+	toCurrency, err := money.ParseCurrency(request.Currency)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("parse payment request currency: %w", err))
+	}
+	toAmount := money.NewMoney(request.Amount, toCurrency)
+
+	exchangeRate, err := ps.GetExchangeRate(ctx, fromCurrency, toCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	fromWallet, err := ps.wallet.GetWalletByUserAndCurrency(ctx, fromUserID, fromCurrency)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.NotFoundErr("sender wallet not found")
+		}
+		return nil, err
+	}
+
+	toWallet, err := ps.wallet.GetWalletByID(ctx, request.WalletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromWallet.UserID == toWallet.UserID {
+		return nil, utils.BadRequestErr("cannot pay your own payment request")
+	}
+
+	if err := checkWalletDebit(fromWallet, "sender"); err != nil {
+		return nil, err
+	}
+	if err := checkWalletCredit(toWallet, "recipient"); err != nil {
+		return nil, err
+	}
+
+	fromAmount := money.NewMoney(int64(float64(toAmount.Amount)/exchangeRate), fromCurrency)
+	fee, err := ps.fee.CalculateFee(ctx, models.TransactionTypeInternal, fromAmount, toCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ps.limits.CheckTransfer(ctx, fromUserID, models.TransactionTypeInternal, fromAmount); err != nil {
+		return nil, err
+	}
+
+	return ps.createInitiatedInternalTransfer(ctx, fromWallet, toWallet, toAmount, exchangeRate, fee, "", request.ID, idempotencyKey)
+}
+
+func (ps *paymentService) processInternalTransferImmediate(ctx context.Context, fromWallet *models.Wallet, toWallet *models.Wallet, fromCurrency money.Currency, toAmount money.Money, exchangeRate float64, fee money.Money, beneficiaryID string, idempotencyKey string) (*models.Transaction, error) {
 	fromAmount := int64(float64(toAmount.Amount) / exchangeRate)
 
 	tx, err := ps.db.BeginTx(ctx, nil)
@@ -143,7 +256,14 @@ func (ps *paymentService) processInternalTransferImmediate(ctx context.Context,
 		return nil, err
 	}
 
-	if lockedFromWallet.Balance < fromAmount {
+	if err := checkWalletDebit(lockedFromWallet, "sender"); err != nil {
+		return nil, err
+	}
+	if err := checkWalletCredit(lockedToWallet, "recipient"); err != nil {
+		return nil, err
+	}
+
+	if lockedFromWallet.AvailableBalance() < fromAmount+fee.Amount {
 		return nil, utils.BadRequestErr("insufficient funds")
 	}
 
@@ -159,12 +279,15 @@ func (ps *paymentService) processInternalTransferImmediate(ctx context.Context,
 		Currency:       toAmount.Currency.String(),
 		Status:         string(models.TransactionStatusPending),
 		ExchangeRate:   sql.NullString{String: exchangeRateStr, Valid: true},
+		FeeAmount:      fee.Amount,
+		FeeCurrency:    sql.NullString{String: fee.Currency.String(), Valid: true},
+		BeneficiaryID:  sql.NullString{String: beneficiaryID, Valid: beneficiaryID != ""},
 	})
 	if err != nil {
 		var pqErr *pq.Error
 		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
 			_ = tx.Rollback()
-			existing, err := ps.GetTransactionByIdempotencyKey(ctx, idempotencyKey)
+			existing, err := replayTransaction(ctx, ps.queries, idempotencyKey, fromWallet.UserID)
 			if err != nil {
 				return nil, utils.DuplicateKeyErr("transaction with this idempotency key already exists")
 			}
@@ -173,6 +296,10 @@ func (ps *paymentService) processInternalTransferImmediate(ctx context.Context,
 		return nil, utils.ServerErr(fmt.Errorf("create transaction: %w", err))
 	}
 
+	if err := recordTransactionCreated(ctx, queries, transaction); err != nil {
+		return nil, err
+	}
+
 	if err := ps.ledger.CreateDebitEntry(ctx, tx, lockedFromWallet.ID, transaction.ID, -fromAmount, fromCurrency); err != nil {
 		return nil, err
 	}
@@ -181,15 +308,16 @@ func (ps *paymentService) processInternalTransferImmediate(ctx context.Context,
 		return nil, err
 	}
 
-	if err := queries.UpdateTransactionStatus(ctx, gen.UpdateTransactionStatusParams{
-		Status: string(models.TransactionStatusCompleted),
-		ID:     transaction.ID,
-	}); err != nil {
-		return nil, utils.ServerErr(fmt.Errorf("update transaction status: %w", err))
+	if err := postFeeEntries(ctx, ps.ledger, tx, lockedFromWallet.ID, transaction.ID, fee); err != nil {
+		return nil, err
+	}
+
+	if err := transitionTransactionStatus(ctx, queries, transaction.ID, models.TransactionStatusPending, models.TransactionStatusCompleted, ""); err != nil {
+		return nil, err
 	}
 
 	fromWalletMoney := money.NewMoney(lockedFromWallet.Balance, fromCurrency)
-	fromAmountMoney := money.NewMoney(fromAmount, fromCurrency)
+	fromAmountMoney := money.NewMoney(fromAmount+fee.Amount, fromCurrency)
 	newFromBalanceMoney, err := fromWalletMoney.Subtract(fromAmountMoney)
 	if err != nil {
 		return nil, utils.ServerErr(fmt.Errorf("calculate new from balance: %w", err))
@@ -236,34 +364,76 @@ func (ps *paymentService) processInternalTransferImmediate(ctx context.Context,
 		Amount:         transaction.Amount,
 		Currency:       transaction.Currency,
 		Status:         models.TransactionStatusCompleted,
+		FeeAmount:      transaction.FeeAmount,
+		FeeCurrency:    &transaction.FeeCurrency.String,
+		BeneficiaryID:  nullStringPtr(transaction.BeneficiaryID),
 		CreatedAt:      transaction.CreatedAt,
 		UpdatedAt:      transaction.UpdatedAt,
 	}, nil
 }
 
-func (ps *paymentService) createInitiatedInternalTransfer(ctx context.Context, fromWallet *models.Wallet, toWallet *models.Wallet, toAmount money.Money, exchangeRate float64, idempotencyKey string) (*models.Transaction, error) {
+// createInitiatedInternalTransfer records a transfer awaiting PIN confirmation
+// and places a hold for the debit and fee, so the same funds cannot back
+// several initiated transfers at once.
+func (ps *paymentService) createInitiatedInternalTransfer(ctx context.Context, fromWallet *models.Wallet, toWallet *models.Wallet, toAmount money.Money, exchangeRate float64, fee money.Money, beneficiaryID string, paymentRequestID string, idempotencyKey string) (*models.Transaction, error) {
 	fromAmount := int64(float64(toAmount.Amount) / exchangeRate)
-	if fromWallet.Balance < fromAmount {
+	if fromWallet.AvailableBalance() < fromAmount+fee.Amount {
+		return nil, utils.BadRequestErr("insufficient funds")
+	}
+
+	fromCurrency, err := money.ParseCurrency(fromWallet.Currency)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("parse from currency: %w", err))
+	}
+
+	tx, err := ps.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := ps.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ps.queries
+	}
+
+	lockedFromWallet, err := ps.wallet.LockWalletForUpdate(ctx, tx, fromWallet.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkWalletDebit(lockedFromWallet, "sender"); err != nil {
+		return nil, err
+	}
+
+	if lockedFromWallet.AvailableBalance() < fromAmount+fee.Amount {
 		return nil, utils.BadRequestErr("insufficient funds")
 	}
 
 	exchangeRateStr := strconv.FormatFloat(exchangeRate, 'f', 8, 64)
 	traceID := utils.TraceIDFromContext(ctx)
-	transaction, err := ps.queries.CreateTransaction(ctx, gen.CreateTransactionParams{
-		IdempotencyKey: idempotencyKey,
-		TraceID:        sql.NullString{String: traceID, Valid: traceID != ""},
-		FromWalletID:   sql.NullString{String: fromWallet.ID, Valid: true},
-		ToWalletID:     sql.NullString{String: toWallet.ID, Valid: true},
-		Type:           string(models.TransactionTypeInternal),
-		Amount:         toAmount.Amount,
-		Currency:       toAmount.Currency.String(),
-		Status:         string(models.TransactionStatusInitiated),
-		ExchangeRate:   sql.NullString{String: exchangeRateStr, Valid: true},
+	transaction, err := queries.CreateTransaction(ctx, gen.CreateTransactionParams{
+		IdempotencyKey:   idempotencyKey,
+		TraceID:          sql.NullString{String: traceID, Valid: traceID != ""},
+		FromWalletID:     sql.NullString{String: lockedFromWallet.ID, Valid: true},
+		ToWalletID:       sql.NullString{String: toWallet.ID, Valid: true},
+		Type:             string(models.TransactionTypeInternal),
+		Amount:           toAmount.Amount,
+		Currency:         toAmount.Currency.String(),
+		Status:           string(models.TransactionStatusInitiated),
+		ExchangeRate:     sql.NullString{String: exchangeRateStr, Valid: true},
+		FeeAmount:        fee.Amount,
+		FeeCurrency:      sql.NullString{String: fee.Currency.String(), Valid: true},
+		BeneficiaryID:    sql.NullString{String: beneficiaryID, Valid: beneficiaryID != ""},
+		PaymentRequestID: sql.NullString{String: paymentRequestID, Valid: paymentRequestID != ""},
 	})
 	if err != nil {
 		var pqErr *pq.Error
 		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
-			existing, err := ps.GetTransactionByIdempotencyKey(ctx, idempotencyKey)
+			_ = tx.Rollback()
+			existing, err := replayTransaction(ctx, ps.queries, idempotencyKey, fromWallet.UserID)
 			if err != nil {
 				return nil, utils.DuplicateKeyErr("transaction with this idempotency key already exists")
 			}
@@ -272,21 +442,76 @@ func (ps *paymentService) createInitiatedInternalTransfer(ctx context.Context, f
 		return nil, utils.ServerErr(fmt.Errorf("create transaction: %w", err))
 	}
 
+	if err := recordTransactionCreated(ctx, queries, transaction); err != nil {
+		return nil, err
+	}
+
+	if err := placeWalletHold(ctx, queries, lockedFromWallet.ID, transaction.ID, money.NewMoney(fromAmount+fee.Amount, fromCurrency)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
 	return &models.Transaction{
-		ID:             transaction.ID,
-		IdempotencyKey: transaction.IdempotencyKey,
-		FromWalletID:   &fromWallet.ID,
-		ToWalletID:     &toWallet.ID,
-		Type:           models.TransactionTypeInternal,
-		Amount:         transaction.Amount,
-		Currency:       transaction.Currency,
-		Status:         models.TransactionStatusInitiated,
-		CreatedAt:      transaction.CreatedAt,
-		UpdatedAt:      transaction.UpdatedAt,
+		ID:               transaction.ID,
+		IdempotencyKey:   transaction.IdempotencyKey,
+		FromWalletID:     &lockedFromWallet.ID,
+		ToWalletID:       &toWallet.ID,
+		Type:             models.TransactionTypeInternal,
+		Amount:           transaction.Amount,
+		Currency:         transaction.Currency,
+		Status:           models.TransactionStatusInitiated,
+		FeeAmount:        transaction.FeeAmount,
+		FeeCurrency:      &transaction.FeeCurrency.String,
+		BeneficiaryID:    nullStringPtr(transaction.BeneficiaryID),
+		PaymentRequestID: nullStringPtr(transaction.PaymentRequestID),
+		CreatedAt:        transaction.CreatedAt,
+		UpdatedAt:        transaction.UpdatedAt,
 	}, nil
 }
 
-func (ps *paymentService) GetTransactionByID(ctx context.Context, transactionID string) (*models.Transaction, error) {
+// GetTransactionByID returns a transaction to the owner of its source wallet,
+// the owner of its destination wallet, or an admin. Anyone else gets the same
+// not-found error as for an unknown ID so transaction IDs cannot be probed.
+func (ps *paymentService) GetTransactionByID(ctx context.Context, transactionID string, userID string, isAdmin bool) (*models.Transaction, error) {
+	transaction, err := ps.getTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if isAdmin {
+		return transaction, nil
+	}
+
+	allowed, err := ps.canViewTransaction(ctx, transaction, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !allowed {
+		utils.Logger.Warn().
+			Str("event", "transaction_access_denied").
+			Str("transaction_id", transaction.ID).
+			Str("user_id", userID).
+			Str("trace_id", utils.TraceIDFromContext(ctx)).
+			Msg("transaction access denied")
+		if err := recordAuditEvent(ctx, ps.queries, auditEvent{
+			Action:     "transaction.access_denied",
+			EntityType: auditEntityTransaction,
+			EntityID:   transaction.ID,
+			After:      map[string]any{"user_id": userID},
+		}); err != nil {
+			return nil, err
+		}
+		return nil, utils.NotFoundErr("transaction not found")
+	}
+
+	return transaction, nil
+}
+
+func (ps *paymentService) getTransaction(ctx context.Context, transactionID string) (*models.Transaction, error) {
 	transaction, err := ps.queries.GetTransactionByID(ctx, transactionID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -295,9 +520,40 @@ func (ps *paymentService) GetTransactionByID(ctx context.Context, transactionID
 		return nil, utils.ServerErr(fmt.Errorf("get transaction: %w", err))
 	}
 
-	return mapTransaction(transaction), nil
+	result := mapTransaction(transaction)
+	if err := attachTransferRecipients(ctx, ps.queries, ps.fields, []*models.Transaction{result}); err != nil {
+		return nil, utils.ServerErr(err)
+	}
+	return result, nil
 }
 
+// canViewTransaction reports whether userID owns either wallet on the
+// transaction.
+func (ps *paymentService) canViewTransaction(ctx context.Context, transaction *models.Transaction, userID string) (bool, error) {
+	for _, walletID := range []*string{transaction.FromWalletID, transaction.ToWalletID} {
+		if walletID == nil {
+			continue
+		}
+
+		wallet, err := ps.queries.GetWalletByID(ctx, *walletID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+			return false, utils.ServerErr(fmt.Errorf("get wallet: %w", err))
+		}
+
+		if wallet.UserID == userID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// GetTransactionByIdempotencyKey looks a key up without checking who owns the
+// transaction. It is for admin flows; user-facing replays go through
+// replayTransaction.
 func (ps *paymentService) GetTransactionByIdempotencyKey(ctx context.Context, idempotencyKey string) (*models.Transaction, error) {
 	transaction, err := ps.queries.GetTransactionByIdempotencyKey(ctx, idempotencyKey)
 	if err != nil {
@@ -310,15 +566,80 @@ func (ps *paymentService) GetTransactionByIdempotencyKey(ctx context.Context, id
 	return mapTransaction(transaction), nil
 }
 
-func (ps *paymentService) CreateExternalTransfer(ctx context.Context, userID string, toAccountNumber string, toBankCode string, fromCurrency money.Currency, toAmount money.Money, idempotencyKey string) (*models.Transaction, error) {
+// replayTransaction returns the transfer userID already created with
+// idempotencyKey, or sql.ErrNoRows if there is none. A key that belongs to
+// someone else's transfer is refused with a duplicate-key error and audited
+// like any other denied read, so a guessed key reveals nothing about it.
+func replayTransaction(ctx context.Context, queries gen.Querier, idempotencyKey string, userID string) (*models.Transaction, error) {
+	transaction, err := queries.GetTransactionByIdempotencyKey(ctx, idempotencyKey)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, utils.ServerErr(fmt.Errorf("check idempotency: %w", err))
+	}
+
+	owned := false
+	if transaction.FromWalletID.Valid {
+		wallet, err := queries.GetWalletByID(ctx, transaction.FromWalletID.String)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, utils.ServerErr(fmt.Errorf("get wallet: %w", err))
+		}
+		owned = err == nil && wallet.UserID == userID
+	}
+
+	if !owned {
+		utils.Logger.Warn().
+			Str("event", "transaction_access_denied").
+			Str("transaction_id", transaction.ID).
+			Str("user_id", userID).
+			Str("trace_id", utils.TraceIDFromContext(ctx)).
+			Msg("idempotency key belongs to another user's transaction")
+		if err := recordAuditEvent(ctx, queries, auditEvent{
+			Action:     "transaction.access_denied",
+			EntityType: auditEntityTransaction,
+			EntityID:   transaction.ID,
+			After:      map[string]any{"user_id": userID, "via": "idempotency_key"},
+		}); err != nil {
+			return nil, err
+		}
+		return nil, utils.DuplicateKeyErr("idempotency key already used for another transaction")
+	}
+
+	return mapTransaction(transaction), nil
+}
+
+func (ps *paymentService) CreateExternalTransfer(ctx context.Context, userID string, toAccountNumber string, toBankCode string, toAccountName string, beneficiaryID string, fromCurrency money.Currency, toAmount money.Money, idempotencyKey string) (*models.Transaction, error) {
+	if beneficiaryID != "" {
+		beneficiary, err := ps.resolveBeneficiary(ctx, userID, beneficiaryID, toAccountNumber, toBankCode)
+		if err != nil {
+			return nil, err
+		}
+		toAccountNumber, toBankCode = beneficiary.AccountNumber, beneficiary.BankCode
+	}
+
+	if err := ps.banks.ValidateBank(ctx, toBankCode, toAmount.Currency); err != nil {
+		return nil, err
+	}
+
 	exchangeRate, err := ps.GetExchangeRate(ctx, fromCurrency, toAmount.Currency)
 	if err != nil {
 		return nil, err
 	}
-	return ps.externalTransfer.CreateExternalTransfer(ctx, userID, toAccountNumber, toBankCode, fromCurrency, toAmount, exchangeRate, idempotencyKey)
+	return ps.externalTransfer.CreateExternalTransfer(ctx, userID, toAccountNumber, toBankCode, toAccountName, beneficiaryID, fromCurrency, toAmount, exchangeRate, idempotencyKey)
 }
 
-func (ps *paymentService) ConfirmTransaction(ctx context.Context, transactionID string, userID string, pin string) (*models.Transaction, error) {
+// resolveBeneficiary returns the saved account a transfer is paying. A
+// transfer names either a beneficiary or an account, never both, so a
+// beneficiary cannot be paired with a different account number.
+func (ps *paymentService) resolveBeneficiary(ctx context.Context, userID string, beneficiaryID string, toAccountNumber string, toBankCode string) (*models.Beneficiary, error) {
+	if toAccountNumber != "" || toBankCode != "" {
+		return nil, utils.BadRequestErr("provide either beneficiary_id or to_account_number and to_bank_code, not both")
+	}
+	return ps.beneficiaries.GetBeneficiary(ctx, userID, beneficiaryID)
+}
+
+func (ps *paymentService) ConfirmTransaction(ctx context.Context, transactionID string, userID string, pin string, totpCode string) (*models.Transaction, error) {
 	transaction, err := ps.queries.GetTransactionByID(ctx, transactionID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -327,11 +648,19 @@ func (ps *paymentService) ConfirmTransaction(ctx context.Context, transactionID
 		return nil, utils.ServerErr(fmt.Errorf("get transaction: %w", err))
 	}
 
+	if transaction.Status == string(models.TransactionStatusExpired) {
+		return nil, utils.BadRequestErr("transaction has expired")
+	}
+
+	if transaction.Status == string(models.TransactionStatusOnHold) {
+		return nil, utils.BadRequestErr("transaction is held for review")
+	}
+
 	if transaction.Status != string(models.TransactionStatusInitiated) {
 		return nil, utils.BadRequestErr("transaction is not in initiated status")
 	}
 
-	if time.Since(transaction.CreatedAt) > 10*time.Minute {
+	if transactionExpired(transaction.CreatedAt, ps.transactionTTL) {
 		return nil, utils.BadRequestErr("transaction has expired")
 	}
 
@@ -348,34 +677,146 @@ func (ps *paymentService) ConfirmTransaction(ctx context.Context, transactionID
 		return nil, utils.BadRequestErr("transaction does not belong to user")
 	}
 
-	user, err := ps.queries.GetUserByID(ctx, userID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, utils.NotFoundErr("user not found")
-		}
-		return nil, utils.ServerErr(fmt.Errorf("get user: %w", err))
+	if err := ps.checkConfirmLimits(ctx, userID, transaction, fromWallet); err != nil {
+		return nil, err
 	}
 
-	if !user.PinHash.Valid {
-		return nil, utils.BadRequestErr("PIN not set for user")
+	if err := ps.verifyConfirmation(ctx, userID, money.NewMoney(transaction.Amount, money.Currency(transaction.Currency)), pin, totpCode); err != nil {
+		return nil, err
 	}
 
-	if err := utils.VerifyPIN(user.PinHash.String, pin); err != nil {
-		return nil, utils.BadRequestErr("invalid PIN")
+	screening, err := ps.sanctions.ScreenPayout(ctx, transaction, fromWallet)
+	if err != nil {
+		return nil, err
+	}
+	if screening != nil && screening.Result == models.ScreeningResultMatch {
+		return ps.holdTransactionForReview(ctx, transaction.ID, "held for sanctions review")
 	}
 
+	assessment, err := ps.risk.Screen(ctx, transaction, fromWallet)
+	if err != nil {
+		return nil, err
+	}
+	if assessment != nil {
+		switch assessment.Decision {
+		case models.RiskDecisionDeny:
+			return nil, ps.declineTransaction(ctx, transaction.ID)
+		case models.RiskDecisionReview:
+			return ps.holdTransactionForReview(ctx, transaction.ID, "held for risk review")
+		}
+	}
+
+	return ps.completeConfirmation(ctx, transaction, nil)
+}
+
+// completeConfirmation moves the money for a transaction that has passed
+// verification and screening, from initiated or from on_hold after approval.
+// review is the approving risk review, recorded in the same database
+// transaction as the money movement; it is nil for a direct confirmation.
+func (ps *paymentService) completeConfirmation(ctx context.Context, transaction gen.Transaction, review *gen.RecordRiskReviewParams) (*models.Transaction, error) {
 	if transaction.Type == string(models.TransactionTypeInternal) {
-		return ps.confirmInternalTransfer(ctx, transaction)
+		return ps.confirmInternalTransfer(ctx, transaction, review)
 	}
 
 	if transaction.Type == string(models.TransactionTypeExternal) {
-		return ps.externalTransfer.confirmExternalTransfer(ctx, transaction)
+		return ps.externalTransfer.confirmExternalTransfer(ctx, transaction, review)
 	}
 
 	return nil, utils.BadRequestErr("invalid transaction type")
 }
 
-func (ps *paymentService) confirmInternalTransfer(ctx context.Context, transaction gen.Transaction) (*models.Transaction, error) {
+// checkConfirmLimits evaluates limits again at confirmation, since transfers
+// confirmed after this one was initiated count towards the same allowance.
+// It rejects early, before the PIN is checked; recheckConfirmLimits repeats it
+// under the sender wallet lock.
+func (ps *paymentService) checkConfirmLimits(ctx context.Context, userID string, transaction gen.Transaction, fromWallet *models.Wallet) error {
+	fromAmount, err := confirmLimitAmount(transaction, fromWallet)
+	if err != nil {
+		return err
+	}
+
+	return ps.limits.CheckTransfer(ctx, userID, models.TransactionType(transaction.Type), fromAmount)
+}
+
+// recheckConfirmLimits evaluates limits inside the confirming transaction,
+// after lockedFromWallet was locked, so parallel confirmations cannot each
+// pass the cap and together exceed it. An approved on_hold transfer is not
+// checked again: held transfers already count towards usage.
+func recheckConfirmLimits(ctx context.Context, tx *sql.Tx, limits LimitService, transaction gen.Transaction, lockedFromWallet *models.Wallet) error {
+	if transaction.Status != string(models.TransactionStatusInitiated) {
+		return nil
+	}
+
+	fromAmount, err := confirmLimitAmount(transaction, lockedFromWallet)
+	if err != nil {
+		return err
+	}
+
+	return limits.CheckTransferLocked(ctx, tx, lockedFromWallet.UserID, models.TransactionType(transaction.Type), fromAmount)
+}
+
+func confirmLimitAmount(transaction gen.Transaction, fromWallet *models.Wallet) (money.Money, error) {
+	fromCurrency, err := money.ParseCurrency(fromWallet.Currency)
+	if err != nil {
+		return money.Money{}, utils.ServerErr(fmt.Errorf("parse from currency: %w", err))
+	}
+
+	fromAmount, err := transactionSourceAmount(transaction, fromCurrency)
+	if err != nil {
+		return money.Money{}, utils.ServerErr(err)
+	}
+
+	return fromAmount, nil
+}
+
+// transactionSourceAmount converts the transaction amount back into the
+// currency debited from the source wallet, excluding the fee.
+func transactionSourceAmount(transaction gen.Transaction, fromCurrency money.Currency) (money.Money, error) {
+	exchangeRate := 1.0
+	if transaction.ExchangeRate.Valid {
+		var err error
+		exchangeRate, err = strconv.ParseFloat(transaction.ExchangeRate.String, 64)
+		if err != nil {
+			return money.Money{}, fmt.Errorf("invalid exchange rate: %w", err)
+		}
+	}
+
+	return money.NewMoney(int64(float64(transaction.Amount)/exchangeRate), fromCurrency), nil
+}
+
+// verifyConfirmation checks the factors the step-up policy asks for: the PIN
+// below the threshold, and a TOTP code with or instead of the PIN above it.
+func (ps *paymentService) verifyConfirmation(ctx context.Context, userID string, amount money.Money, pin string, totpCode string) error {
+	if ps.stepUp.Requires(amount) {
+		enabled, err := ps.totp.IsEnabled(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !enabled {
+			return utils.TOTPRequiredErr("transfers of this amount require an authenticator app; enroll one first")
+		}
+		if totpCode == "" {
+			return utils.TOTPRequiredErr("TOTP code required for transfers of this amount")
+		}
+	}
+
+	if ps.stepUp.requiresPIN(amount) {
+		if pin == "" {
+			return utils.BadRequestErr("PIN is required")
+		}
+		if err := ps.pin.VerifyPIN(ctx, userID, pin); err != nil {
+			return err
+		}
+	}
+
+	if ps.stepUp.Requires(amount) {
+		return ps.totp.Verify(ctx, userID, totpCode)
+	}
+
+	return nil
+}
+
+func (ps *paymentService) confirmInternalTransfer(ctx context.Context, transaction gen.Transaction, review *gen.RecordRiskReviewParams) (*models.Transaction, error) {
 	if !transaction.ExchangeRate.Valid {
 		return nil, utils.ServerErr(fmt.Errorf("exchange rate not found in transaction"))
 	}
@@ -416,6 +857,11 @@ func (ps *paymentService) confirmInternalTransfer(ctx context.Context, transacti
 
 	fromAmount := int64(float64(transaction.Amount) / exchangeRate)
 
+	fee, err := transactionFee(transaction, fromCurrency)
+	if err != nil {
+		return nil, utils.ServerErr(err)
+	}
+
 	tx, err := ps.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
@@ -429,6 +875,10 @@ func (ps *paymentService) confirmInternalTransfer(ctx context.Context, transacti
 		queries = ps.queries
 	}
 
+	if err := lockTransactionForConfirm(ctx, queries, transaction); err != nil {
+		return nil, err
+	}
+
 	lockedFromWallet, err := ps.wallet.LockWalletForUpdate(ctx, tx, fromWallet.ID)
 	if err != nil {
 		return nil, err
@@ -439,7 +889,25 @@ func (ps *paymentService) confirmInternalTransfer(ctx context.Context, transacti
 		return nil, err
 	}
 
-	if lockedFromWallet.Balance < fromAmount {
+	if err := checkWalletDebit(lockedFromWallet, "sender"); err != nil {
+		return nil, err
+	}
+	if err := checkWalletCredit(lockedToWallet, "recipient"); err != nil {
+		return nil, err
+	}
+
+	if err := recheckConfirmLimits(ctx, tx, ps.limits, transaction, lockedFromWallet); err != nil {
+		return nil, err
+	}
+
+	// The transfer's own hold is part of the locked held balance, so it is
+	// added back before checking what is available.
+	held, err := releaseWalletHold(ctx, queries, transaction.ID, models.WalletHoldStatusCaptured)
+	if err != nil {
+		return nil, err
+	}
+
+	if lockedFromWallet.AvailableBalance()+held < fromAmount+fee.Amount {
 		return nil, utils.BadRequestErr("insufficient funds")
 	}
 
@@ -451,15 +919,28 @@ func (ps *paymentService) confirmInternalTransfer(ctx context.Context, transacti
 		return nil, err
 	}
 
-	if err := queries.UpdateTransactionStatus(ctx, gen.UpdateTransactionStatusParams{
-		Status: string(models.TransactionStatusCompleted),
-		ID:     transaction.ID,
-	}); err != nil {
-		return nil, utils.ServerErr(fmt.Errorf("update transaction status: %w", err))
+	if err := postFeeEntries(ctx, ps.ledger, tx, lockedFromWallet.ID, transaction.ID, fee); err != nil {
+		return nil, err
+	}
+
+	if err := transitionTransactionStatus(ctx, queries, transaction.ID, models.TransactionStatus(transaction.Status), models.TransactionStatusCompleted, ""); err != nil {
+		return nil, err
+	}
+
+	if review != nil {
+		if err := recordRiskReview(ctx, queries, transaction.ID, *review); err != nil {
+			return nil, err
+		}
+	}
+
+	if transaction.PaymentRequestID.Valid {
+		if err := markPaymentRequestPaid(ctx, queries, transaction, lockedFromWallet.UserID); err != nil {
+			return nil, err
+		}
 	}
 
 	fromWalletMoney := money.NewMoney(lockedFromWallet.Balance, fromCurrency)
-	fromAmountMoney := money.NewMoney(fromAmount, fromCurrency)
+	fromAmountMoney := money.NewMoney(fromAmount+fee.Amount, fromCurrency)
 	newFromBalanceMoney, err := fromWalletMoney.Subtract(fromAmountMoney)
 	if err != nil {
 		return nil, utils.ServerErr(fmt.Errorf("calculate new from balance: %w", err))
@@ -547,6 +1028,10 @@ func (ps *paymentService) GetTransactionHistory(ctx context.Context, userID stri
 		result = append(result, mapTransaction(t))
 	}
 
+	if err := attachTransferRecipients(ctx, ps.queries, ps.fields, result); err != nil {
+		return nil, utils.ServerErr(err)
+	}
+
 	var nextCursor *string
 	if hasMore && len(result) > 0 {
 		lastTx := result[len(result)-1]
@@ -9,6 +9,7 @@ import (
 
 	"github.com/IfedayoAwe/payment-processing-service/db/gen"
 	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
 	"github.com/IfedayoAwe/payment-processing-service/providers"
 	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
 	"github.com/IfedayoAwe/payment-processing-service/utils"
@@ -48,19 +49,53 @@ func TestPaymentService_GetTransactionByID(t *testing.T) {
 		}
 
 		mockQueries.On("GetTransactionByID", mock.Anything, "tx_123").Return(genTx, nil)
+		mockQueries.On("GetWalletByID", mock.Anything, "wallet_1").Return(gen.Wallet{ID: "wallet_1", UserID: "user_1"}, nil)
+		mockQueries.On("GetWalletByID", mock.Anything, "wallet_2").Return(gen.Wallet{ID: "wallet_2", UserID: "user_2"}, nil)
 
-		result, err := ps.GetTransactionByID(context.Background(), "tx_123")
+		result, err := ps.GetTransactionByID(context.Background(), "tx_123", "user_1", false)
 
 		require.NoError(t, err)
 		assert.Equal(t, "tx_123", result.ID)
 		assert.Equal(t, "completed", string(result.Status))
+
+		t.Run("counterparty", func(t *testing.T) {
+			result, err := ps.GetTransactionByID(context.Background(), "tx_123", "user_2", false)
+
+			require.NoError(t, err)
+			assert.Equal(t, "tx_123", result.ID)
+		})
+
+		t.Run("admin", func(t *testing.T) {
+			result, err := ps.GetTransactionByID(context.Background(), "tx_123", "admin_1", true)
+
+			require.NoError(t, err)
+			assert.Equal(t, "tx_123", result.ID)
+		})
+
+		t.Run("unrelated user gets not found and the denial is audited", func(t *testing.T) {
+			mockQueries.On("CreateAuditEvent", mock.Anything, mock.MatchedBy(func(arg gen.CreateAuditEventParams) bool {
+				return arg.Action == "transaction.access_denied" &&
+					arg.EntityType == auditEntityTransaction &&
+					arg.EntityID == "tx_123" &&
+					arg.ActorID == "user_3" &&
+					string(arg.After) == `{"user_id":"user_3"}`
+			})).Return(nil).Once()
+			ctx := utils.WithActor(context.Background(), utils.Actor{ID: "user_3", Type: utils.ActorTypeUser})
+
+			result, err := ps.GetTransactionByID(ctx, "tx_123", "user_3", false)
+
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, utils.ErrNotFound)
+			assert.Contains(t, err.Error(), "transaction not found")
+		})
+
 		mockQueries.AssertExpectations(t)
 	})
 
 	t.Run("transaction not found", func(t *testing.T) {
 		mockQueries.On("GetTransactionByID", mock.Anything, "tx_notfound").Return(gen.Transaction{}, sql.ErrNoRows)
 
-		result, err := ps.GetTransactionByID(context.Background(), "tx_notfound")
+		result, err := ps.GetTransactionByID(context.Background(), "tx_notfound", "user_1", false)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -71,7 +106,7 @@ func TestPaymentService_GetTransactionByID(t *testing.T) {
 	t.Run("database error", func(t *testing.T) {
 		mockQueries.On("GetTransactionByID", mock.Anything, "tx_error").Return(gen.Transaction{}, errors.New("db connection error"))
 
-		result, err := ps.GetTransactionByID(context.Background(), "tx_error")
+		result, err := ps.GetTransactionByID(context.Background(), "tx_error", "user_1", false)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -138,16 +173,18 @@ func TestPaymentService_ConfirmTransaction_Validation(t *testing.T) {
 	processor.RegisterExchangeRateProvider(mockProvider)
 
 	ps := &paymentService{
+		limits:   noLimits(),
 		queries:  mockQueries,
 		provider: processor,
 		wallet:   mockWallet,
 		ledger:   &ledgerService{queries: mockQueries},
+		pin:      &pinService{queries: mockQueries},
 	}
 
 	t.Run("transaction not found", func(t *testing.T) {
 		mockQueries.On("GetTransactionByID", mock.Anything, "tx_notfound").Return(gen.Transaction{}, sql.ErrNoRows)
 
-		result, err := ps.ConfirmTransaction(context.Background(), "tx_notfound", "user_1", "1234")
+		result, err := ps.ConfirmTransaction(context.Background(), "tx_notfound", "user_1", "1234", "")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -166,7 +203,7 @@ func TestPaymentService_ConfirmTransaction_Validation(t *testing.T) {
 
 		mockQueries.On("GetTransactionByID", mock.Anything, "tx_123").Return(genTx, nil)
 
-		result, err := ps.ConfirmTransaction(context.Background(), "tx_123", "user_1", "1234")
+		result, err := ps.ConfirmTransaction(context.Background(), "tx_123", "user_1", "1234", "")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -181,10 +218,12 @@ func TestPaymentService_ConfirmTransaction_Validation(t *testing.T) {
 		processor.RegisterPayoutProvider(&mockCurrencyCloudProvider{})
 
 		ps := &paymentService{
+			limits:   noLimits(),
 			queries:  mockQueries,
 			provider: processor,
 			wallet:   mockWallet,
 			ledger:   &ledgerService{queries: mockQueries},
+			pin:      &pinService{queries: mockQueries},
 		}
 
 		oldTime := time.Now().Add(-11 * time.Minute)
@@ -197,7 +236,7 @@ func TestPaymentService_ConfirmTransaction_Validation(t *testing.T) {
 
 		mockQueries.On("GetTransactionByID", mock.Anything, "tx_123").Return(genTx, nil)
 
-		result, err := ps.ConfirmTransaction(context.Background(), "tx_123", "user_1", "1234")
+		result, err := ps.ConfirmTransaction(context.Background(), "tx_123", "user_1", "1234", "")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -212,10 +251,12 @@ func TestPaymentService_ConfirmTransaction_Validation(t *testing.T) {
 		processor.RegisterPayoutProvider(&mockCurrencyCloudProvider{})
 
 		ps := &paymentService{
+			limits:   noLimits(),
 			queries:  mockQueries,
 			provider: processor,
 			wallet:   mockWallet,
 			ledger:   &ledgerService{queries: mockQueries},
+			pin:      &pinService{queries: mockQueries},
 		}
 
 		now := time.Now()
@@ -234,7 +275,7 @@ func TestPaymentService_ConfirmTransaction_Validation(t *testing.T) {
 		mockQueries.On("GetTransactionByID", mock.Anything, "tx_123").Return(genTx, nil)
 		mockWallet.On("GetWalletByID", mock.Anything, "wallet_1").Return(wallet, nil)
 
-		result, err := ps.ConfirmTransaction(context.Background(), "tx_123", "user_1", "1234")
+		result, err := ps.ConfirmTransaction(context.Background(), "tx_123", "user_1", "1234", "")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -250,10 +291,12 @@ func TestPaymentService_ConfirmTransaction_Validation(t *testing.T) {
 		processor.RegisterPayoutProvider(&mockCurrencyCloudProvider{})
 
 		ps := &paymentService{
+			limits:   noLimits(),
 			queries:  mockQueries,
 			provider: processor,
 			wallet:   mockWallet,
 			ledger:   &ledgerService{queries: mockQueries},
+			pin:      &pinService{queries: mockQueries},
 		}
 
 		now := time.Now()
@@ -265,15 +308,16 @@ func TestPaymentService_ConfirmTransaction_Validation(t *testing.T) {
 		}
 
 		wallet := &models.Wallet{
-			ID:     "wallet_1",
-			UserID: "user_1",
+			ID:       "wallet_1",
+			UserID:   "user_1",
+			Currency: "USD",
 		}
 
 		mockQueries.On("GetTransactionByID", mock.Anything, "tx_123").Return(genTx, nil)
 		mockWallet.On("GetWalletByID", mock.Anything, "wallet_1").Return(wallet, nil)
 		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(gen.User{}, sql.ErrNoRows)
 
-		result, err := ps.ConfirmTransaction(context.Background(), "tx_123", "user_1", "1234")
+		result, err := ps.ConfirmTransaction(context.Background(), "tx_123", "user_1", "1234", "")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -289,10 +333,12 @@ func TestPaymentService_ConfirmTransaction_Validation(t *testing.T) {
 		processor.RegisterPayoutProvider(&mockCurrencyCloudProvider{})
 
 		ps := &paymentService{
+			limits:   noLimits(),
 			queries:  mockQueries,
 			provider: processor,
 			wallet:   mockWallet,
 			ledger:   &ledgerService{queries: mockQueries},
+			pin:      &pinService{queries: mockQueries},
 		}
 
 		now := time.Now()
@@ -304,8 +350,9 @@ func TestPaymentService_ConfirmTransaction_Validation(t *testing.T) {
 		}
 
 		wallet := &models.Wallet{
-			ID:     "wallet_1",
-			UserID: "user_1",
+			ID:       "wallet_1",
+			UserID:   "user_1",
+			Currency: "USD",
 		}
 
 		user := gen.User{
@@ -317,7 +364,7 @@ func TestPaymentService_ConfirmTransaction_Validation(t *testing.T) {
 		mockWallet.On("GetWalletByID", mock.Anything, "wallet_1").Return(wallet, nil)
 		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(user, nil)
 
-		result, err := ps.ConfirmTransaction(context.Background(), "tx_123", "user_1", "1234")
+		result, err := ps.ConfirmTransaction(context.Background(), "tx_123", "user_1", "1234", "")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -326,6 +373,223 @@ func TestPaymentService_ConfirmTransaction_Validation(t *testing.T) {
 		mockWallet.AssertExpectations(t)
 	})
 
+	t.Run("step-up requires enrollment", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockWallet := new(mocks.MockWalletService)
+		pin := &pinService{queries: mockQueries}
+
+		ps := &paymentService{
+			limits:  noLimits(),
+			queries: mockQueries,
+			wallet:  mockWallet,
+			pin:     pin,
+			totp:    &totpService{queries: mockQueries, pin: pin},
+			stepUp:  StepUpPolicy{Thresholds: map[money.Currency]float64{money.USD: 1000}, Mode: StepUpModeAdditional},
+		}
+
+		genTx := gen.Transaction{
+			ID:           "tx_123",
+			FromWalletID: sql.NullString{String: "wallet_1", Valid: true},
+			Amount:       250000,
+			Currency:     "USD",
+			Status:       "initiated",
+			CreatedAt:    time.Now(),
+		}
+
+		mockQueries.On("GetTransactionByID", mock.Anything, "tx_123").Return(genTx, nil)
+		mockWallet.On("GetWalletByID", mock.Anything, "wallet_1").Return(&models.Wallet{ID: "wallet_1", UserID: "user_1", Currency: "USD"}, nil)
+		mockQueries.On("GetUserTOTP", mock.Anything, "user_1").Return(nil, sql.ErrNoRows)
+
+		result, err := ps.ConfirmTransaction(context.Background(), "tx_123", "user_1", "12345", "")
+
+		assert.ErrorIs(t, err, utils.ErrTOTPRequired)
+		assert.Nil(t, result)
+		mockQueries.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("step-up requires TOTP code", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockWallet := new(mocks.MockWalletService)
+		pin := &pinService{queries: mockQueries}
+
+		ps := &paymentService{
+			limits:  noLimits(),
+			queries: mockQueries,
+			wallet:  mockWallet,
+			pin:     pin,
+			totp:    &totpService{queries: mockQueries, pin: pin},
+			stepUp:  StepUpPolicy{Thresholds: map[money.Currency]float64{money.USD: 1000}, Mode: StepUpModeReplace},
+		}
+
+		genTx := gen.Transaction{
+			ID:           "tx_123",
+			FromWalletID: sql.NullString{String: "wallet_1", Valid: true},
+			Amount:       100000,
+			Currency:     "USD",
+			Status:       "initiated",
+			CreatedAt:    time.Now(),
+		}
+
+		mockQueries.On("GetTransactionByID", mock.Anything, "tx_123").Return(genTx, nil)
+		mockWallet.On("GetWalletByID", mock.Anything, "wallet_1").Return(&models.Wallet{ID: "wallet_1", UserID: "user_1", Currency: "USD"}, nil)
+		mockQueries.On("GetUserTOTP", mock.Anything, "user_1").Return(gen.UserTotp{
+			UserID:      "user_1",
+			ConfirmedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}, nil)
+
+		result, err := ps.ConfirmTransaction(context.Background(), "tx_123", "user_1", "", "")
+
+		assert.ErrorIs(t, err, utils.ErrTOTPRequired)
+		assert.Contains(t, err.Error(), "TOTP code required")
+		assert.Nil(t, result)
+	})
+
+	t.Run("limit rechecked at confirmation", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockWallet := new(mocks.MockWalletService)
+		mockLimits := new(mocks.MockLimitService)
+
+		ps := &paymentService{
+			queries: mockQueries,
+			wallet:  mockWallet,
+			limits:  mockLimits,
+			pin:     &pinService{queries: mockQueries},
+		}
+
+		genTx := gen.Transaction{
+			ID:           "tx_123",
+			FromWalletID: sql.NullString{String: "wallet_1", Valid: true},
+			Type:         "internal",
+			Amount:       85000,
+			ExchangeRate: sql.NullString{String: "0.85000000", Valid: true},
+			Status:       "initiated",
+			CreatedAt:    time.Now(),
+		}
+
+		mockQueries.On("GetTransactionByID", mock.Anything, "tx_123").Return(genTx, nil)
+		mockWallet.On("GetWalletByID", mock.Anything, "wallet_1").Return(&models.Wallet{ID: "wallet_1", UserID: "user_1", Currency: "USD"}, nil)
+		mockLimits.On("CheckTransfer", mock.Anything, "user_1", models.TransactionTypeInternal, money.NewMoney(100000, money.USD)).
+			Return(utils.LimitExceededErr("daily transfer count limit reached"))
+
+		result, err := ps.ConfirmTransaction(context.Background(), "tx_123", "user_1", "12345", "")
+
+		assert.ErrorIs(t, err, utils.ErrLimitExceeded)
+		assert.Nil(t, result)
+		mockLimits.AssertExpectations(t)
+		mockQueries.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("risk review puts transaction on hold", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockWallet := new(mocks.MockWalletService)
+		mockRisk := new(mocks.MockRiskService)
+
+		ps := &paymentService{
+			limits:    noLimits(),
+			risk:      mockRisk,
+			sanctions: &sanctionsService{},
+			queries:   mockQueries,
+			wallet:    mockWallet,
+			pin:       &pinService{queries: mockQueries},
+		}
+
+		genTx := gen.Transaction{
+			ID:           "tx_123",
+			FromWalletID: sql.NullString{String: "wallet_1", Valid: true},
+			Type:         "internal",
+			Status:       "initiated",
+			CreatedAt:    time.Now(),
+		}
+		wallet := &models.Wallet{ID: "wallet_1", UserID: "user_1", Currency: "USD"}
+
+		hashedPIN, _ := utils.HashPIN("12345")
+		mockQueries.On("GetTransactionByID", mock.Anything, "tx_123").Return(genTx, nil).Once()
+		mockWallet.On("GetWalletByID", mock.Anything, "wallet_1").Return(wallet, nil)
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(gen.User{UserID: "user_1", PinHash: sql.NullString{String: hashedPIN, Valid: true}}, nil)
+		mockQueries.On("ClaimPINAttempt", mock.Anything, mock.Anything).Return(gen.PinAttempt{UserID: "user_1", FailedAttempts: 1}, nil)
+		mockQueries.On("ClearPINAttempts", mock.Anything, "user_1").Return(int64(1), nil)
+		mockRisk.On("Screen", mock.Anything, genTx, wallet).Return(&models.RiskAssessment{Decision: models.RiskDecisionReview}, nil)
+		mockQueries.On("TransitionTransactionStatus", mock.Anything, gen.TransitionTransactionStatusParams{
+			NewStatus:     "on_hold",
+			ID:            "tx_123",
+			CurrentStatus: "initiated",
+		}).Return(int64(1), nil)
+		mockQueries.On("CreateTransactionStatusHistory", mock.Anything, mock.Anything).Return(nil)
+		expectAuditEvent(mockQueries, "transaction.status_changed", "tx_123")
+		heldTx := genTx
+		heldTx.Status = "on_hold"
+		mockQueries.On("GetTransactionByID", mock.Anything, "tx_123").Return(heldTx, nil).Once()
+
+		result, err := ps.ConfirmTransaction(context.Background(), "tx_123", "user_1", "12345", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, models.TransactionStatusOnHold, result.Status)
+		mockQueries.AssertExpectations(t)
+		mockRisk.AssertExpectations(t)
+		mockQueries.AssertNotCalled(t, "ReleaseWalletHold", mock.Anything, mock.Anything)
+	})
+
+	t.Run("sanctions match puts external transfer on hold", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockWallet := new(mocks.MockWalletService)
+		mockRisk := new(mocks.MockRiskService)
+
+		ps := &paymentService{
+			limits:  noLimits(),
+			risk:    mockRisk,
+			queries: mockQueries,
+			wallet:  mockWallet,
+			pin:     &pinService{queries: mockQueries},
+			sanctions: &sanctionsService{
+				queries:     mockQueries,
+				nameEnquiry: stubNameEnquiry{accountName: "Viktor Petrovsky"},
+				list:        testSanctionsList(t),
+				threshold:   0.9,
+			},
+		}
+
+		genTx := externalTransaction()
+		genTx.CreatedAt = time.Now()
+		wallet := &models.Wallet{ID: "wallet_1", UserID: "user_1", Currency: "USD"}
+
+		hashedPIN, _ := utils.HashPIN("12345")
+		mockQueries.On("GetTransactionByID", mock.Anything, "tx_123").Return(genTx, nil).Once()
+		mockWallet.On("GetWalletByID", mock.Anything, "wallet_1").Return(wallet, nil)
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(gen.User{UserID: "user_1", PinHash: sql.NullString{String: hashedPIN, Valid: true}}, nil)
+		mockQueries.On("ClaimPINAttempt", mock.Anything, mock.Anything).Return(gen.PinAttempt{UserID: "user_1", FailedAttempts: 1}, nil)
+		mockQueries.On("ClearPINAttempts", mock.Anything, "user_1").Return(int64(1), nil)
+		mockQueries.On("GetTransferRecipientByTransactionID", mock.Anything, "tx_123").Return(testTransferRecipient("tx_123"), nil)
+		mockQueries.On("UpdateTransactionScreening", mock.Anything, mock.Anything).Return(nil)
+		mockQueries.On("CreateRiskAssessment", mock.Anything, mock.Anything).Return(gen.RiskAssessment{ID: "ra_1"}, nil)
+		mockQueries.On("TransitionTransactionStatus", mock.Anything, gen.TransitionTransactionStatusParams{
+			NewStatus:     "on_hold",
+			ID:            "tx_123",
+			CurrentStatus: "initiated",
+		}).Return(int64(1), nil)
+		mockQueries.On("CreateTransactionStatusHistory", mock.Anything, mock.MatchedBy(func(arg gen.CreateTransactionStatusHistoryParams) bool {
+			return arg.Reason.String == "held for sanctions review"
+		})).Return(nil)
+		expectAuditEvent(mockQueries, "transaction.status_changed", "tx_123")
+		heldTx := genTx
+		heldTx.Status = "on_hold"
+		heldTx.ScreeningResult = sql.NullString{String: "match", Valid: true}
+		heldTx.ScreeningListVersion = sql.NullString{String: "2025-01-15", Valid: true}
+		mockQueries.On("GetTransactionByID", mock.Anything, "tx_123").Return(heldTx, nil).Once()
+		mockQueries.On("ListTransferRecipientsByTransactionIDs", mock.Anything, []string{"tx_123"}).Return([]gen.TransferRecipient{testTransferRecipient("tx_123")}, nil)
+
+		result, err := ps.ConfirmTransaction(context.Background(), "tx_123", "user_1", "12345", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, models.TransactionStatusOnHold, result.Status)
+		require.NotNil(t, result.ScreeningResult)
+		assert.Equal(t, models.ScreeningResultMatch, *result.ScreeningResult)
+		assert.Equal(t, "2025-01-15", *result.ScreeningListVersion)
+		require.NotNil(t, result.Recipient)
+		assert.Equal(t, "9999999999", result.Recipient.AccountNumber)
+		mockQueries.AssertExpectations(t)
+		mockRisk.AssertNotCalled(t, "Screen", mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("invalid PIN", func(t *testing.T) {
 		mockQueries := new(mocks.MockQuerier)
 		mockWallet := new(mocks.MockWalletService)
@@ -333,10 +597,12 @@ func TestPaymentService_ConfirmTransaction_Validation(t *testing.T) {
 		processor.RegisterPayoutProvider(&mockCurrencyCloudProvider{})
 
 		ps := &paymentService{
+			limits:   noLimits(),
 			queries:  mockQueries,
 			provider: processor,
 			wallet:   mockWallet,
 			ledger:   &ledgerService{queries: mockQueries},
+			pin:      &pinService{queries: mockQueries},
 		}
 
 		now := time.Now()
@@ -348,8 +614,9 @@ func TestPaymentService_ConfirmTransaction_Validation(t *testing.T) {
 		}
 
 		wallet := &models.Wallet{
-			ID:     "wallet_1",
-			UserID: "user_1",
+			ID:       "wallet_1",
+			UserID:   "user_1",
+			Currency: "USD",
 		}
 
 		hashedPIN, _ := utils.HashPIN("5678")
@@ -361,8 +628,9 @@ func TestPaymentService_ConfirmTransaction_Validation(t *testing.T) {
 		mockQueries.On("GetTransactionByID", mock.Anything, "tx_123").Return(genTx, nil)
 		mockWallet.On("GetWalletByID", mock.Anything, "wallet_1").Return(wallet, nil)
 		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(user, nil)
+		mockQueries.On("ClaimPINAttempt", mock.Anything, mock.Anything).Return(gen.PinAttempt{UserID: "user_1", FailedAttempts: 1}, nil)
 
-		result, err := ps.ConfirmTransaction(context.Background(), "tx_123", "user_1", "1234")
+		result, err := ps.ConfirmTransaction(context.Background(), "tx_123", "user_1", "1234", "")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
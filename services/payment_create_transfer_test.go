@@ -12,6 +12,7 @@ import (
 	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
 	"github.com/IfedayoAwe/payment-processing-service/providers"
 	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -46,15 +47,39 @@ func TestPaymentService_CreateInternalTransfer_Comprehensive(t *testing.T) {
 		}
 
 		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "key_123").Return(existingTx, nil)
+		mockQueries.On("GetWalletByID", mock.Anything, "wallet_1").Return(gen.Wallet{ID: "wallet_1", UserID: "user_1"}, nil)
 
 		amount := money.NewMoney(10000, money.USD)
-		result, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", money.USD, amount, "key_123")
+		result, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", "", "", money.USD, amount, "key_123")
 
 		require.NoError(t, err)
 		assert.Equal(t, "tx_existing", result.ID)
 		mockQueries.AssertExpectations(t)
 	})
 
+	t.Run("idempotency key of another user's transfer is refused", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &paymentService{queries: mockQueries}
+
+		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "key_123").Return(gen.Transaction{
+			ID:           "tx_existing",
+			FromWalletID: sql.NullString{String: "wallet_1", Valid: true},
+			Type:         "internal",
+			Amount:       10000,
+			Currency:     "USD",
+		}, nil)
+		mockQueries.On("GetWalletByID", mock.Anything, "wallet_1").Return(gen.Wallet{ID: "wallet_1", UserID: "user_1"}, nil)
+		expectAuditEvent(mockQueries, "transaction.access_denied", "tx_existing")
+
+		amount := money.NewMoney(10000, money.USD)
+		result, err := ps.CreateInternalTransfer(context.Background(), "user_2", "1234567890", "044", "", "", money.USD, amount, "key_123")
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, utils.ErrDuplicatedKey)
+		assert.NotContains(t, err.Error(), "tx_existing")
+		mockQueries.AssertExpectations(t)
+	})
+
 	t.Run("sender wallet not found", func(t *testing.T) {
 		mockQueries := new(mocks.MockQuerier)
 		mockWallet := new(mocks.MockWalletService)
@@ -69,13 +94,14 @@ func TestPaymentService_CreateInternalTransfer_Comprehensive(t *testing.T) {
 			provider: processor,
 			wallet:   mockWallet,
 			ledger:   &ledgerService{queries: mockQueries},
+			banks:    knownBanks(),
 		}
 
 		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "key_new").Return(gen.Transaction{}, sql.ErrNoRows)
 		mockWallet.On("GetWalletByUserAndCurrency", mock.Anything, "user_1", money.USD).Return(nil, sql.ErrNoRows)
 
 		amount := money.NewMoney(10000, money.USD)
-		result, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", money.USD, amount, "key_new")
+		result, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", "", "", money.USD, amount, "key_new")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -84,6 +110,26 @@ func TestPaymentService_CreateInternalTransfer_Comprehensive(t *testing.T) {
 		mockWallet.AssertExpectations(t)
 	})
 
+	t.Run("unknown bank code", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockBanks := new(mocks.MockBankService)
+		ps := &paymentService{
+			queries: mockQueries,
+			banks:   mockBanks,
+		}
+
+		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "key_new").Return(gen.Transaction{}, sql.ErrNoRows)
+		mockBanks.On("ValidateBank", mock.Anything, "999", money.USD).Return(utils.BadRequestErr("unknown bank_code"))
+
+		amount := money.NewMoney(10000, money.USD)
+		result, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "999", "", "", money.USD, amount, "key_new")
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "unknown bank_code")
+		mockBanks.AssertExpectations(t)
+	})
+
 	t.Run("recipient account not found", func(t *testing.T) {
 		mockQueries := new(mocks.MockQuerier)
 		mockWallet := new(mocks.MockWalletService)
@@ -98,6 +144,7 @@ func TestPaymentService_CreateInternalTransfer_Comprehensive(t *testing.T) {
 			provider: processor,
 			wallet:   mockWallet,
 			ledger:   &ledgerService{queries: mockQueries},
+			banks:    knownBanks(),
 		}
 
 		fromWallet := &models.Wallet{
@@ -113,7 +160,7 @@ func TestPaymentService_CreateInternalTransfer_Comprehensive(t *testing.T) {
 		})).Return(gen.BankAccount{}, sql.ErrNoRows)
 
 		amount := money.NewMoney(10000, money.USD)
-		result, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", money.USD, amount, "key_new")
+		result, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", "", "", money.USD, amount, "key_new")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -136,6 +183,7 @@ func TestPaymentService_CreateInternalTransfer_Comprehensive(t *testing.T) {
 			provider: processor,
 			wallet:   mockWallet,
 			ledger:   &ledgerService{queries: mockQueries},
+			banks:    knownBanks(),
 		}
 
 		fromWallet := &models.Wallet{
@@ -156,7 +204,7 @@ func TestPaymentService_CreateInternalTransfer_Comprehensive(t *testing.T) {
 		mockQueries.On("GetBankAccountByAccountAndBankCode", mock.Anything, mock.Anything).Return(bankAccount, nil)
 
 		amount := money.NewMoney(10000, money.USD)
-		result, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", money.USD, amount, "key_new")
+		result, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", "", "", money.USD, amount, "key_new")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -165,6 +213,50 @@ func TestPaymentService_CreateInternalTransfer_Comprehensive(t *testing.T) {
 		mockWallet.AssertExpectations(t)
 	})
 
+	t.Run("account name mismatch", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockWallet := new(mocks.MockWalletService)
+		processor := providers.NewProcessor()
+		mockProvider := &mockCurrencyCloudProvider{}
+		processor.RegisterPayoutProvider(mockProvider)
+		processor.RegisterNameEnquiryProvider(mockProvider)
+		processor.RegisterExchangeRateProvider(mockProvider)
+
+		ps := &paymentService{
+			queries:     mockQueries,
+			provider:    processor,
+			wallet:      mockWallet,
+			ledger:      &ledgerService{queries: mockQueries},
+			nameEnquiry: stubNameEnquiry{},
+			banks:       knownBanks(),
+		}
+
+		fromWallet := &models.Wallet{
+			ID:       "wallet_1",
+			UserID:   "user_1",
+			Currency: "USD",
+		}
+
+		bankAccount := gen.BankAccount{
+			ID:            "acc_1",
+			AccountNumber: "1234567890",
+			BankCode:      "044",
+			AccountName:   sql.NullString{String: "John Doe", Valid: true},
+			Currency:      "USD",
+		}
+
+		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "key_new").Return(gen.Transaction{}, sql.ErrNoRows)
+		mockWallet.On("GetWalletByUserAndCurrency", mock.Anything, "user_1", money.USD).Return(fromWallet, nil)
+		mockQueries.On("GetBankAccountByAccountAndBankCode", mock.Anything, mock.Anything).Return(bankAccount, nil)
+
+		amount := money.NewMoney(10000, money.USD)
+		result, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", "Jane Smith", "", money.USD, amount, "key_new")
+
+		assert.ErrorIs(t, err, utils.ErrNameMismatch)
+		assert.Nil(t, result)
+		mockWallet.AssertNotCalled(t, "GetWalletByBankAccount", mock.Anything, mock.Anything)
+	})
+
 	t.Run("recipient wallet not found", func(t *testing.T) {
 		mockQueries := new(mocks.MockQuerier)
 		mockWallet := new(mocks.MockWalletService)
@@ -179,6 +271,7 @@ func TestPaymentService_CreateInternalTransfer_Comprehensive(t *testing.T) {
 			provider: processor,
 			wallet:   mockWallet,
 			ledger:   &ledgerService{queries: mockQueries},
+			banks:    knownBanks(),
 		}
 
 		fromWallet := &models.Wallet{
@@ -200,7 +293,7 @@ func TestPaymentService_CreateInternalTransfer_Comprehensive(t *testing.T) {
 		mockWallet.On("GetWalletByBankAccount", mock.Anything, "acc_1").Return(nil, sql.ErrNoRows)
 
 		amount := money.NewMoney(10000, money.USD)
-		result, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", money.USD, amount, "key_new")
+		result, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", "", "", money.USD, amount, "key_new")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -223,6 +316,7 @@ func TestPaymentService_CreateInternalTransfer_Comprehensive(t *testing.T) {
 			provider: processor,
 			wallet:   mockWallet,
 			ledger:   &ledgerService{queries: mockQueries},
+			banks:    knownBanks(),
 		}
 
 		fromWallet := &models.Wallet{
@@ -250,7 +344,7 @@ func TestPaymentService_CreateInternalTransfer_Comprehensive(t *testing.T) {
 		mockWallet.On("GetWalletByBankAccount", mock.Anything, "acc_1").Return(toWallet, nil)
 
 		amount := money.NewMoney(10000, money.USD)
-		result, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", money.USD, amount, "key_new")
+		result, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", "", "", money.USD, amount, "key_new")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -259,6 +353,60 @@ func TestPaymentService_CreateInternalTransfer_Comprehensive(t *testing.T) {
 		mockWallet.AssertExpectations(t)
 	})
 
+	t.Run("recipient wallet frozen for credits", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockWallet := new(mocks.MockWalletService)
+		processor := providers.NewProcessor()
+		mockProvider := &mockCurrencyCloudProvider{}
+		processor.RegisterPayoutProvider(mockProvider)
+		processor.RegisterNameEnquiryProvider(mockProvider)
+		processor.RegisterExchangeRateProvider(mockProvider)
+
+		ps := &paymentService{
+			queries:  mockQueries,
+			provider: processor,
+			wallet:   mockWallet,
+			ledger:   &ledgerService{queries: mockQueries},
+			banks:    knownBanks(),
+		}
+
+		fromWallet := &models.Wallet{
+			ID:       "wallet_1",
+			UserID:   "user_1",
+			Currency: "USD",
+			Status:   models.WalletStatusActive,
+		}
+
+		credits := models.FreezeCredits
+		toWallet := &models.Wallet{
+			ID:              "wallet_2",
+			UserID:          "user_2",
+			Currency:        "USD",
+			Status:          models.WalletStatusFrozen,
+			FrozenDirection: &credits,
+		}
+
+		bankAccount := gen.BankAccount{
+			ID:            "acc_2",
+			AccountNumber: "2000000001",
+			BankCode:      "044",
+			Currency:      "USD",
+		}
+
+		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "key_new").Return(gen.Transaction{}, sql.ErrNoRows)
+		mockWallet.On("GetWalletByUserAndCurrency", mock.Anything, "user_1", money.USD).Return(fromWallet, nil)
+		mockQueries.On("GetBankAccountByAccountAndBankCode", mock.Anything, mock.Anything).Return(bankAccount, nil)
+		mockWallet.On("GetWalletByBankAccount", mock.Anything, "acc_2").Return(toWallet, nil)
+
+		amount := money.NewMoney(10000, money.USD)
+		result, err := ps.CreateInternalTransfer(context.Background(), "user_1", "2000000001", "044", "", "", money.USD, amount, "key_new")
+
+		assert.ErrorIs(t, err, utils.ErrWalletLocked)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "recipient wallet is frozen")
+		mockQueries.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
+	})
+
 	t.Run("idempotency check database error", func(t *testing.T) {
 		mockQueries := new(mocks.MockQuerier)
 		processor := providers.NewProcessor()
@@ -277,7 +425,7 @@ func TestPaymentService_CreateInternalTransfer_Comprehensive(t *testing.T) {
 		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "key_error").Return(gen.Transaction{}, errors.New("db error"))
 
 		amount := money.NewMoney(10000, money.USD)
-		result, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", money.USD, amount, "key_error")
+		result, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", "", "", money.USD, amount, "key_error")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -286,6 +434,57 @@ func TestPaymentService_CreateInternalTransfer_Comprehensive(t *testing.T) {
 	})
 }
 
+func TestPaymentService_ProcessInternalTransferImmediate(t *testing.T) {
+	mockQueries := new(mocks.MockQuerier)
+	mockWallet := new(mocks.MockWalletService)
+	mockLedger := new(mocks.MockLedgerService)
+	ps := &paymentService{
+		queries: mockQueries,
+		db:      newNopDB(),
+		wallet:  mockWallet,
+		ledger:  mockLedger,
+	}
+
+	fromWallet := &models.Wallet{ID: "wallet_usd", UserID: "user_1", Currency: "USD", Balance: 10000, Status: models.WalletStatusActive}
+	toWallet := &models.Wallet{ID: "wallet_eur", UserID: "user_1", Currency: "EUR", Balance: 0, Status: models.WalletStatusActive}
+
+	mockWallet.On("LockWalletForUpdate", mock.Anything, mock.Anything, "wallet_usd").Return(fromWallet, nil)
+	mockWallet.On("LockWalletForUpdate", mock.Anything, mock.Anything, "wallet_eur").Return(toWallet, nil)
+	mockQueries.On("CreateTransaction", mock.Anything, mock.Anything).Return(gen.Transaction{
+		ID:             "tx_1",
+		IdempotencyKey: "key_1",
+		Type:           "internal",
+		Status:         "pending",
+		Amount:         850,
+		Currency:       "EUR",
+	}, nil)
+	expectAuditEvent(mockQueries, "transaction.created", "tx_1")
+	mockLedger.On("CreateDebitEntry", mock.Anything, mock.Anything, "wallet_usd", "tx_1", int64(-1000), money.USD).Return(nil)
+	mockLedger.On("CreateCreditEntry", mock.Anything, mock.Anything, "wallet_eur", "tx_1", int64(850), money.EUR).Return(nil)
+	mockQueries.On("TransitionTransactionStatus", mock.Anything, gen.TransitionTransactionStatusParams{
+		NewStatus:     "completed",
+		ID:            "tx_1",
+		CurrentStatus: "pending",
+	}).Return(int64(1), nil)
+	mockQueries.On("CreateTransactionStatusHistory", mock.Anything, gen.CreateTransactionStatusHistoryParams{
+		TransactionID: "tx_1",
+		FromStatus:    "pending",
+		ToStatus:      "completed",
+	}).Return(nil)
+	expectAuditEvent(mockQueries, "transaction.status_changed", "tx_1")
+	mockQueries.On("UpdateWalletBalance", mock.Anything, gen.UpdateWalletBalanceParams{Balance: 9000, ID: "wallet_usd"}).Return(nil)
+	mockQueries.On("UpdateWalletBalance", mock.Anything, gen.UpdateWalletBalanceParams{Balance: 850, ID: "wallet_eur"}).Return(nil)
+	mockQueries.On("CreateIdempotencyKey", mock.Anything, mock.Anything).Return(gen.IdempotencyKey{}, nil)
+
+	result, err := ps.processInternalTransferImmediate(context.Background(), fromWallet, toWallet, money.USD, money.NewMoney(850, money.EUR), 0.85, money.NewMoney(0, money.USD), "", "key_1")
+
+	require.NoError(t, err)
+	assert.Equal(t, models.TransactionStatusCompleted, result.Status)
+	mockQueries.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+	mockQueries.AssertNotCalled(t, "UpdateTransactionStatus", mock.Anything, mock.Anything)
+}
+
 func TestPaymentService_CreateExternalTransfer_Comprehensive(t *testing.T) {
 	t.Run("insufficient funds", func(t *testing.T) {
 		mockQueries := new(mocks.MockQuerier)
@@ -298,10 +497,11 @@ func TestPaymentService_CreateExternalTransfer_Comprehensive(t *testing.T) {
 		processor.RegisterExchangeRateProvider(mockProvider)
 
 		externalTransferSvc := &externalTransferService{
-			queries:  mockQueries,
-			wallet:   mockWallet,
-			ledger:   mockLedger,
-			provider: processor,
+			queries: mockQueries,
+			wallet:  mockWallet,
+			ledger:  mockLedger,
+			fee:     &feeService{queries: mockQueries},
+			limits:  noLimits(),
 		}
 
 		ps := &paymentService{
@@ -310,6 +510,7 @@ func TestPaymentService_CreateExternalTransfer_Comprehensive(t *testing.T) {
 			wallet:           mockWallet,
 			ledger:           mockLedger,
 			externalTransfer: externalTransferSvc,
+			banks:            knownBanks(),
 		}
 
 		fromWallet := &models.Wallet{
@@ -321,9 +522,60 @@ func TestPaymentService_CreateExternalTransfer_Comprehensive(t *testing.T) {
 
 		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "key_1").Return(gen.Transaction{}, sql.ErrNoRows)
 		mockWallet.On("GetWalletByUserAndCurrency", mock.Anything, "user_1", money.USD).Return(fromWallet, nil)
+		mockQueries.On("ListActiveFeeRules", mock.Anything, "external").Return([]gen.FeeRule{}, nil)
+
+		amount := money.NewMoney(10000, money.USD)
+		result, err := ps.CreateExternalTransfer(context.Background(), "user_1", "1234567890", "044", "", "", money.USD, amount, "key_1")
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "insufficient funds")
+		mockQueries.AssertExpectations(t)
+		mockWallet.AssertExpectations(t)
+		mockLedger.AssertExpectations(t)
+	})
+
+	t.Run("held funds are not available", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockWallet := new(mocks.MockWalletService)
+		mockLedger := new(mocks.MockLedgerService)
+		processor := providers.NewProcessor()
+		mockProvider := &mockCurrencyCloudProvider{}
+		processor.RegisterPayoutProvider(mockProvider)
+		processor.RegisterNameEnquiryProvider(mockProvider)
+		processor.RegisterExchangeRateProvider(mockProvider)
+
+		externalTransferSvc := &externalTransferService{
+			queries: mockQueries,
+			wallet:  mockWallet,
+			ledger:  mockLedger,
+			fee:     &feeService{queries: mockQueries},
+			limits:  noLimits(),
+		}
+
+		ps := &paymentService{
+			queries:          mockQueries,
+			provider:         processor,
+			wallet:           mockWallet,
+			ledger:           mockLedger,
+			externalTransfer: externalTransferSvc,
+			banks:            knownBanks(),
+		}
+
+		fromWallet := &models.Wallet{
+			ID:          "wallet_1",
+			UserID:      "user_1",
+			Currency:    "USD",
+			Balance:     20000,
+			HeldBalance: 15000,
+		}
+
+		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "key_1").Return(gen.Transaction{}, sql.ErrNoRows)
+		mockWallet.On("GetWalletByUserAndCurrency", mock.Anything, "user_1", money.USD).Return(fromWallet, nil)
+		mockQueries.On("ListActiveFeeRules", mock.Anything, "external").Return([]gen.FeeRule{}, nil)
 
 		amount := money.NewMoney(10000, money.USD)
-		result, err := ps.CreateExternalTransfer(context.Background(), "user_1", "1234567890", "044", money.USD, amount, "key_1")
+		result, err := ps.CreateExternalTransfer(context.Background(), "user_1", "1234567890", "044", "", "", money.USD, amount, "key_1")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
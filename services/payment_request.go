@@ -0,0 +1,435 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/queue"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+)
+
+const (
+	defaultPaymentRequestTTL = 7 * 24 * time.Hour
+	maxPaymentRequestMemo    = 140
+
+	// NotificationPaymentRequestPaid tells a requester their request was paid.
+	NotificationPaymentRequestPaid = "payment_request.paid"
+)
+
+type PaymentRequestService interface {
+	CreatePaymentRequest(ctx context.Context, userID string, request models.NewPaymentRequest) (*models.PaymentRequest, error)
+	ListPaymentRequests(ctx context.Context, userID string, limit int32) ([]*models.PaymentRequest, error)
+	GetPaymentRequestByToken(ctx context.Context, token string) (*models.PaymentRequest, error)
+	CancelPaymentRequest(ctx context.Context, requestID string, userID string) (*models.PaymentRequest, error)
+	PayPaymentRequest(ctx context.Context, token string, payerID string, fromCurrency money.Currency, idempotencyKey string) (*models.Transaction, error)
+}
+
+type paymentRequestService struct {
+	queries    gen.Querier
+	db         *sql.DB
+	wallet     WalletService
+	payments   PaymentService
+	defaultTTL time.Duration
+	maxTTL     time.Duration
+}
+
+func newPaymentRequestService(queries gen.Querier, db *sql.DB, wallet WalletService, payments PaymentService, defaultTTL time.Duration, maxTTL time.Duration) PaymentRequestService {
+	if defaultTTL <= 0 {
+		defaultTTL = defaultPaymentRequestTTL
+	}
+	if maxTTL < defaultTTL {
+		maxTTL = defaultTTL
+	}
+	return &paymentRequestService{
+		queries:    queries,
+		db:         db,
+		wallet:     wallet,
+		payments:   payments,
+		defaultTTL: defaultTTL,
+		maxTTL:     maxTTL,
+	}
+}
+
+// CreatePaymentRequest asks for money into the user's wallet in the requested
+// currency. The wallet must exist and accept credits now; it is checked again
+// when the request is paid.
+func (prs *paymentRequestService) CreatePaymentRequest(ctx context.Context, userID string, request models.NewPaymentRequest) (*models.PaymentRequest, error) {
+	if !request.Amount.IsPositive() {
+		return nil, utils.BadRequestErr("amount must be positive")
+	}
+
+	var memo sql.NullString
+	if request.Memo != nil {
+		trimmed := strings.TrimSpace(*request.Memo)
+		if len(trimmed) > maxPaymentRequestMemo {
+			return nil, utils.BadRequestErr(fmt.Sprintf("memo must be at most %d characters", maxPaymentRequestMemo))
+		}
+		memo = sql.NullString{String: trimmed, Valid: trimmed != ""}
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(prs.defaultTTL)
+	if request.ExpiresAt != nil {
+		expiresAt = *request.ExpiresAt
+		if !expiresAt.After(now) {
+			return nil, utils.BadRequestErr("expires_at must be in the future")
+		}
+		if expiresAt.After(now.Add(prs.maxTTL)) {
+			return nil, utils.BadRequestErr(fmt.Sprintf("expires_at must be within %s", prs.maxTTL))
+		}
+	}
+
+	wallet, err := prs.wallet.GetWalletByUserAndCurrency(ctx, userID, request.Amount.Currency)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.NotFoundErr(fmt.Sprintf("no %s wallet to receive the payment", request.Amount.Currency))
+		}
+		return nil, err
+	}
+
+	if err := checkWalletCredit(wallet, "receiving"); err != nil {
+		return nil, err
+	}
+
+	token, err := generatePaymentRequestToken()
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("generate payment request token: %w", err))
+	}
+
+	tx, err := prs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := prs.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = prs.queries
+	}
+
+	row, err := queries.CreatePaymentRequest(ctx, gen.CreatePaymentRequestParams{
+		RequesterID: userID,
+		WalletID:    wallet.ID,
+		Amount:      request.Amount.Amount,
+		Currency:    request.Amount.Currency.String(),
+		Memo:        memo,
+		Token:       token,
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("create payment request: %w", err))
+	}
+
+	if err := recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "payment_request.created",
+		EntityType: auditEntityPaymentRequest,
+		EntityID:   row.ID,
+		After: map[string]any{
+			"status":     row.Status,
+			"wallet_id":  row.WalletID,
+			"amount":     row.Amount,
+			"currency":   row.Currency,
+			"expires_at": row.ExpiresAt,
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	return mapPaymentRequest(row), nil
+}
+
+func (prs *paymentRequestService) ListPaymentRequests(ctx context.Context, userID string, limit int32) ([]*models.PaymentRequest, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	rows, err := prs.queries.ListPaymentRequestsByRequester(ctx, gen.ListPaymentRequestsByRequesterParams{
+		RequesterID: userID,
+		Limit:       limit,
+	})
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("list payment requests: %w", err))
+	}
+
+	requests := make([]*models.PaymentRequest, 0, len(rows))
+	for _, row := range rows {
+		requests = append(requests, mapPaymentRequest(row))
+	}
+	return requests, nil
+}
+
+// GetPaymentRequestByToken returns the request a shared link points to. The
+// token is the only thing a payer needs, so any authenticated user may look
+// one up.
+func (prs *paymentRequestService) GetPaymentRequestByToken(ctx context.Context, token string) (*models.PaymentRequest, error) {
+	row, err := prs.queries.GetPaymentRequestByToken(ctx, token)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.NotFoundErr("payment request not found")
+		}
+		return nil, utils.ServerErr(fmt.Errorf("get payment request: %w", err))
+	}
+
+	return mapPaymentRequest(row), nil
+}
+
+// CancelPaymentRequest withdraws a pending request. Another user's request is
+// reported as not found. A transfer already initiated against it can no
+// longer be confirmed.
+func (prs *paymentRequestService) CancelPaymentRequest(ctx context.Context, requestID string, userID string) (*models.PaymentRequest, error) {
+	tx, err := prs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := prs.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = prs.queries
+	}
+
+	row, err := queries.GetPaymentRequestByIDForUpdate(ctx, requestID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.NotFoundErr("payment request not found")
+		}
+		return nil, utils.ServerErr(fmt.Errorf("get payment request: %w", err))
+	}
+
+	if row.RequesterID != userID {
+		return nil, utils.NotFoundErr("payment request not found")
+	}
+
+	if err := checkPaymentRequestPayable(mapPaymentRequest(row)); err != nil {
+		return nil, err
+	}
+
+	if err := transitionPaymentRequestStatus(ctx, queries, row.ID, models.PaymentRequestPending, models.PaymentRequestCancelled); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	request := mapPaymentRequest(row)
+	request.Status = models.PaymentRequestCancelled
+	return request, nil
+}
+
+// PayPaymentRequest starts an internal transfer from the payer's wallet in
+// fromCurrency for the amount requested. Like any transfer between users it
+// is initiated and must be confirmed; the request is marked paid when it is.
+func (prs *paymentRequestService) PayPaymentRequest(ctx context.Context, token string, payerID string, fromCurrency money.Currency, idempotencyKey string) (*models.Transaction, error) {
+	existing, err := replayTransaction(ctx, prs.queries, idempotencyKey, payerID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	request, err := prs.GetPaymentRequestByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// A replay is only for the same link; the key cannot be reused to fetch
+	// a transfer made for another request.
+	if existing != nil {
+		if existing.PaymentRequestID == nil || *existing.PaymentRequestID != request.ID {
+			return nil, utils.DuplicateKeyErr("idempotency key already used for another transaction")
+		}
+		return existing, nil
+	}
+
+	if request.RequesterID == payerID {
+		return nil, utils.BadRequestErr("cannot pay your own payment request")
+	}
+
+	if err := checkPaymentRequestPayable(request); err != nil {
+		return nil, err
+	}
+
+	return prs.payments.CreatePaymentRequestTransfer(ctx, payerID, request, fromCurrency, idempotencyKey)
+}
+
+// checkPaymentRequestPayable rejects a request that is no longer pending.
+func checkPaymentRequestPayable(request *models.PaymentRequest) error {
+	if request.Status != models.PaymentRequestPending {
+		return utils.BadRequestErr(fmt.Sprintf("payment request is %s", request.Status))
+	}
+	return nil
+}
+
+// markPaymentRequestPaid records that the transfer paid its request and
+// queues a notification for the requester, inside the transaction that
+// completes the transfer. A request that was paid, cancelled or ran out in
+// the meantime fails the confirmation, so no money moves for it.
+func markPaymentRequestPaid(ctx context.Context, queries gen.Querier, transaction gen.Transaction, payerID string) error {
+	row, err := queries.GetPaymentRequestByIDForUpdate(ctx, transaction.PaymentRequestID.String)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("get payment request: %w", err))
+	}
+
+	if err := checkPaymentRequestPayable(mapPaymentRequest(row)); err != nil {
+		return err
+	}
+
+	if transaction.ToWalletID.String != row.WalletID || transaction.Amount != row.Amount || transaction.Currency != row.Currency {
+		return utils.ServerErr(fmt.Errorf("transaction %s does not match payment request %s", transaction.ID, row.ID))
+	}
+
+	rows, err := queries.MarkPaymentRequestPaid(ctx, gen.MarkPaymentRequestPaidParams{
+		TransactionID: sql.NullString{String: transaction.ID, Valid: true},
+		PaidBy:        sql.NullString{String: payerID, Valid: true},
+		ID:            row.ID,
+	})
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("mark payment request paid: %w", err))
+	}
+	if rows == 0 {
+		return utils.BadRequestErr("payment request is no longer pending")
+	}
+
+	if err := recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "payment_request.paid",
+		EntityType: auditEntityPaymentRequest,
+		EntityID:   row.ID,
+		Before:     map[string]any{"status": row.Status},
+		After: map[string]any{
+			"status":         models.PaymentRequestPaid,
+			"transaction_id": transaction.ID,
+			"paid_by":        payerID,
+		},
+	}); err != nil {
+		return err
+	}
+
+	traceID := utils.TraceIDFromContext(ctx)
+	if transaction.TraceID.Valid {
+		traceID = transaction.TraceID.String
+	}
+
+	payload, err := json.Marshal(queue.NotificationJobPayload{
+		Event:            NotificationPaymentRequestPaid,
+		UserID:           row.RequesterID,
+		PaymentRequestID: row.ID,
+		TransactionID:    transaction.ID,
+		Amount:           row.Amount,
+		Currency:         row.Currency,
+		TraceID:          traceID,
+	})
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("marshal notification payload: %w", err))
+	}
+
+	if _, err := queries.CreateOutboxEntry(ctx, gen.CreateOutboxEntryParams{
+		JobType: string(queue.JobTypeNotification),
+		Payload: payload,
+	}); err != nil {
+		return utils.ServerErr(fmt.Errorf("create outbox entry: %w", err))
+	}
+
+	return nil
+}
+
+func transitionPaymentRequestStatus(ctx context.Context, queries gen.Querier, requestID string, from models.PaymentRequestStatus, to models.PaymentRequestStatus) error {
+	rows, err := queries.TransitionPaymentRequestStatus(ctx, gen.TransitionPaymentRequestStatusParams{
+		NewStatus:     string(to),
+		ID:            requestID,
+		CurrentStatus: string(from),
+	})
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("update payment request status: %w", err))
+	}
+	if rows == 0 {
+		return utils.BadRequestErr(fmt.Sprintf("payment request is no longer %s", from))
+	}
+
+	return recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "payment_request." + string(to),
+		EntityType: auditEntityPaymentRequest,
+		EntityID:   requestID,
+		Before:     map[string]any{"status": from},
+		After:      map[string]any{"status": to},
+	})
+}
+
+// expirePaymentRequests moves pending requests past their expiry to expired.
+func expirePaymentRequests(ctx context.Context, queries gen.Querier) (int, error) {
+	rows, err := queries.ExpirePaymentRequests(ctx, expiryBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("expire payment requests: %w", err)
+	}
+
+	for _, row := range rows {
+		if err := recordAuditEvent(ctx, queries, auditEvent{
+			Action:     "payment_request.expired",
+			EntityType: auditEntityPaymentRequest,
+			EntityID:   row.ID,
+			Before:     map[string]any{"status": models.PaymentRequestPending},
+			After:      map[string]any{"status": models.PaymentRequestExpired},
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(rows), nil
+}
+
+func generatePaymentRequestToken() (string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// mapPaymentRequest reports a pending request past its expiry as expired,
+// since the expiry sweep may not have reached it yet.
+func mapPaymentRequest(r gen.PaymentRequest) *models.PaymentRequest {
+	status := models.PaymentRequestStatus(r.Status)
+	if status == models.PaymentRequestPending && !time.Now().Before(r.ExpiresAt) {
+		status = models.PaymentRequestExpired
+	}
+
+	var paidAt *time.Time
+	if r.PaidAt.Valid {
+		paidAt = &r.PaidAt.Time
+	}
+
+	return &models.PaymentRequest{
+		ID:            r.ID,
+		RequesterID:   r.RequesterID,
+		WalletID:      r.WalletID,
+		Amount:        r.Amount,
+		Currency:      r.Currency,
+		Memo:          nullStringPtr(r.Memo),
+		Token:         r.Token,
+		Status:        status,
+		ExpiresAt:     r.ExpiresAt,
+		TransactionID: nullStringPtr(r.TransactionID),
+		PaidBy:        nullStringPtr(r.PaidBy),
+		PaidAt:        paidAt,
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+	}
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/providers"
+	"github.com/IfedayoAwe/payment-processing-service/queue"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testPaymentRequestRow(status models.PaymentRequestStatus) gen.PaymentRequest {
+	return gen.PaymentRequest{
+		ID:          "preq_1",
+		RequesterID: "user_2",
+		WalletID:    "wallet_user2_usd",
+		Amount:      2500,
+		Currency:    "USD",
+		Token:       "tok_abc",
+		Status:      string(status),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+}
+
+func TestPaymentRequestService_CreatePaymentRequest(t *testing.T) {
+	newService := func() (*paymentRequestService, *mocks.MockQuerier, *mocks.MockWalletService) {
+		mockQueries := new(mocks.MockQuerier)
+		wallets := new(mocks.MockWalletService)
+		prs := newPaymentRequestService(mockQueries, nil, wallets, nil, time.Hour, 24*time.Hour).(*paymentRequestService)
+		return prs, mockQueries, wallets
+	}
+
+	t.Run("amount must be positive", func(t *testing.T) {
+		prs, _, wallets := newService()
+
+		_, err := prs.CreatePaymentRequest(context.Background(), "user_2", models.NewPaymentRequest{Amount: money.NewMoney(0, money.USD)})
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		wallets.AssertNotCalled(t, "GetWalletByUserAndCurrency", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("expiry in the past", func(t *testing.T) {
+		prs, _, _ := newService()
+		past := time.Now().Add(-time.Minute)
+
+		_, err := prs.CreatePaymentRequest(context.Background(), "user_2", models.NewPaymentRequest{Amount: money.NewMoney(2500, money.USD), ExpiresAt: &past})
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+	})
+
+	t.Run("expiry beyond the maximum", func(t *testing.T) {
+		prs, _, _ := newService()
+		later := time.Now().Add(48 * time.Hour)
+
+		_, err := prs.CreatePaymentRequest(context.Background(), "user_2", models.NewPaymentRequest{Amount: money.NewMoney(2500, money.USD), ExpiresAt: &later})
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+	})
+
+	t.Run("memo too long", func(t *testing.T) {
+		prs, _, _ := newService()
+		long := strings.Repeat("dinner ", 21)
+
+		_, err := prs.CreatePaymentRequest(context.Background(), "user_2", models.NewPaymentRequest{Amount: money.NewMoney(2500, money.USD), Memo: &long})
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+	})
+
+	t.Run("no wallet in the requested currency", func(t *testing.T) {
+		prs, _, wallets := newService()
+		wallets.On("GetWalletByUserAndCurrency", mock.Anything, "user_2", money.GBP).Return(nil, sql.ErrNoRows)
+
+		_, err := prs.CreatePaymentRequest(context.Background(), "user_2", models.NewPaymentRequest{Amount: money.NewMoney(2500, money.GBP)})
+
+		assert.ErrorIs(t, err, utils.ErrNotFound)
+	})
+
+	t.Run("wallet not accepting credits", func(t *testing.T) {
+		prs, mockQueries, wallets := newService()
+		credits := models.FreezeCredits
+		wallets.On("GetWalletByUserAndCurrency", mock.Anything, "user_2", money.USD).Return(&models.Wallet{
+			ID:              "wallet_user2_usd",
+			UserID:          "user_2",
+			Currency:        "USD",
+			Status:          models.WalletStatusFrozen,
+			FrozenDirection: &credits,
+		}, nil)
+
+		_, err := prs.CreatePaymentRequest(context.Background(), "user_2", models.NewPaymentRequest{Amount: money.NewMoney(2500, money.USD)})
+
+		assert.ErrorIs(t, err, utils.ErrWalletLocked)
+		mockQueries.AssertNotCalled(t, "CreatePaymentRequest", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPaymentRequestService_GetPaymentRequestByToken(t *testing.T) {
+	t.Run("unknown token", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		prs := &paymentRequestService{queries: mockQueries}
+		mockQueries.On("GetPaymentRequestByToken", mock.Anything, "tok_missing").Return(nil, sql.ErrNoRows)
+
+		_, err := prs.GetPaymentRequestByToken(context.Background(), "tok_missing")
+
+		assert.ErrorIs(t, err, utils.ErrNotFound)
+	})
+
+	t.Run("pending request past its expiry reads as expired", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		prs := &paymentRequestService{queries: mockQueries}
+		row := testPaymentRequestRow(models.PaymentRequestPending)
+		row.ExpiresAt = time.Now().Add(-time.Minute)
+		mockQueries.On("GetPaymentRequestByToken", mock.Anything, "tok_abc").Return(row, nil)
+
+		request, err := prs.GetPaymentRequestByToken(context.Background(), "tok_abc")
+
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentRequestExpired, request.Status)
+	})
+}
+
+func TestPaymentRequestService_PayPaymentRequest(t *testing.T) {
+	newService := func(row gen.PaymentRequest) (*paymentRequestService, *mocks.MockQuerier, *mocks.MockWalletService) {
+		mockQueries := new(mocks.MockQuerier)
+		wallets := new(mocks.MockWalletService)
+		payments := &paymentService{queries: mockQueries, wallet: wallets}
+		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "pay-key-1").Return(nil, sql.ErrNoRows)
+		mockQueries.On("GetPaymentRequestByToken", mock.Anything, "tok_abc").Return(row, nil)
+		return &paymentRequestService{queries: mockQueries, payments: payments}, mockQueries, wallets
+	}
+
+	t.Run("requester cannot pay their own request", func(t *testing.T) {
+		prs, _, wallets := newService(testPaymentRequestRow(models.PaymentRequestPending))
+
+		_, err := prs.PayPaymentRequest(context.Background(), "tok_abc", "user_2", money.USD, "pay-key-1")
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		wallets.AssertNotCalled(t, "GetWalletByUserAndCurrency", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("request no longer pending", func(t *testing.T) {
+		for _, status := range []models.PaymentRequestStatus{models.PaymentRequestPaid, models.PaymentRequestCancelled, models.PaymentRequestExpired} {
+			prs, _, wallets := newService(testPaymentRequestRow(status))
+
+			_, err := prs.PayPaymentRequest(context.Background(), "tok_abc", "user_1", money.USD, "pay-key-1")
+
+			assert.ErrorIs(t, err, utils.ErrBadRequest, status)
+			assert.Contains(t, err.Error(), "payment request is "+string(status))
+			wallets.AssertNotCalled(t, "GetWalletByUserAndCurrency", mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("creates an initiated transfer linked to the request", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		wallets := new(mocks.MockWalletService)
+		processor := providers.NewProcessor()
+		processor.RegisterExchangeRateProvider(&mockCurrencyCloudProvider{})
+		payments := &paymentService{
+			queries:  mockQueries,
+			db:       newNopDB(),
+			wallet:   wallets,
+			provider: processor,
+			fee:      &feeService{queries: mockQueries},
+			limits:   noLimits(),
+		}
+		prs := &paymentRequestService{queries: mockQueries, payments: payments}
+
+		payer := &models.Wallet{ID: "wallet_user1_usd", UserID: "user_1", Currency: "USD", Balance: 10000, Status: models.WalletStatusActive}
+		requester := &models.Wallet{ID: "wallet_user2_usd", UserID: "user_2", Currency: "USD", Status: models.WalletStatusActive}
+
+		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "pay-key-1").Return(nil, sql.ErrNoRows)
+		mockQueries.On("GetPaymentRequestByToken", mock.Anything, "tok_abc").Return(testPaymentRequestRow(models.PaymentRequestPending), nil)
+		wallets.On("GetWalletByUserAndCurrency", mock.Anything, "user_1", money.USD).Return(payer, nil)
+		wallets.On("GetWalletByID", mock.Anything, "wallet_user2_usd").Return(requester, nil)
+		mockQueries.On("ListActiveFeeRules", mock.Anything, "internal").Return([]gen.FeeRule{}, nil)
+		wallets.On("LockWalletForUpdate", mock.Anything, mock.Anything, "wallet_user1_usd").Return(payer, nil)
+		mockQueries.On("CreateTransaction", mock.Anything, mock.MatchedBy(func(arg gen.CreateTransactionParams) bool {
+			return arg.PaymentRequestID == sql.NullString{String: "preq_1", Valid: true}
+		})).Return(gen.Transaction{
+			ID:               "txn_pay",
+			IdempotencyKey:   "pay-key-1",
+			Type:             "internal",
+			Status:           "initiated",
+			Amount:           2500,
+			Currency:         "USD",
+			PaymentRequestID: sql.NullString{String: "preq_1", Valid: true},
+		}, nil)
+		expectAuditEvent(mockQueries, "transaction.created", "txn_pay")
+		mockQueries.On("CreateWalletHold", mock.Anything, mock.Anything).Return(gen.WalletHold{}, nil)
+		mockQueries.On("AdjustWalletHeldBalance", mock.Anything, mock.Anything).Return(nil)
+		expectAuditEvent(mockQueries, "wallet.hold_placed", "wallet_user1_usd")
+
+		transaction, err := prs.PayPaymentRequest(context.Background(), "tok_abc", "user_1", money.USD, "pay-key-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, models.TransactionStatusInitiated, transaction.Status)
+		require.NotNil(t, transaction.PaymentRequestID)
+		assert.Equal(t, "preq_1", *transaction.PaymentRequestID)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("repeated idempotency key returns the transfer", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		prs := &paymentRequestService{queries: mockQueries, payments: &paymentService{queries: mockQueries}}
+		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "pay-key-1").Return(gen.Transaction{
+			ID:               "txn_pay",
+			FromWalletID:     sql.NullString{String: "wallet_user1_usd", Valid: true},
+			Type:             "internal",
+			Status:           "initiated",
+			PaymentRequestID: sql.NullString{String: "preq_1", Valid: true},
+		}, nil)
+		mockQueries.On("GetWalletByID", mock.Anything, "wallet_user1_usd").Return(gen.Wallet{ID: "wallet_user1_usd", UserID: "user_1"}, nil)
+		mockQueries.On("GetPaymentRequestByToken", mock.Anything, "tok_abc").Return(testPaymentRequestRow(models.PaymentRequestPaid), nil)
+
+		transaction, err := prs.PayPaymentRequest(context.Background(), "tok_abc", "user_1", money.USD, "pay-key-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "txn_pay", transaction.ID)
+		require.NotNil(t, transaction.PaymentRequestID)
+		assert.Equal(t, "preq_1", *transaction.PaymentRequestID)
+	})
+
+	t.Run("idempotency key reused for another request", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		prs := &paymentRequestService{queries: mockQueries, payments: &paymentService{queries: mockQueries}}
+		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "pay-key-1").Return(gen.Transaction{
+			ID:               "txn_pay",
+			FromWalletID:     sql.NullString{String: "wallet_user1_usd", Valid: true},
+			Type:             "internal",
+			Status:           "initiated",
+			PaymentRequestID: sql.NullString{String: "preq_other", Valid: true},
+		}, nil)
+		mockQueries.On("GetWalletByID", mock.Anything, "wallet_user1_usd").Return(gen.Wallet{ID: "wallet_user1_usd", UserID: "user_1"}, nil)
+		mockQueries.On("GetPaymentRequestByToken", mock.Anything, "tok_abc").Return(testPaymentRequestRow(models.PaymentRequestPending), nil)
+
+		transaction, err := prs.PayPaymentRequest(context.Background(), "tok_abc", "user_1", money.USD, "pay-key-1")
+
+		assert.Nil(t, transaction)
+		assert.ErrorIs(t, err, utils.ErrDuplicatedKey)
+	})
+
+	t.Run("idempotency key of another payer's transfer", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		prs := &paymentRequestService{queries: mockQueries, payments: &paymentService{queries: mockQueries}}
+		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "pay-key-1").Return(gen.Transaction{
+			ID:               "txn_pay",
+			FromWalletID:     sql.NullString{String: "wallet_user3_usd", Valid: true},
+			PaymentRequestID: sql.NullString{String: "preq_1", Valid: true},
+		}, nil)
+		mockQueries.On("GetWalletByID", mock.Anything, "wallet_user3_usd").Return(gen.Wallet{ID: "wallet_user3_usd", UserID: "user_3"}, nil)
+		expectAuditEvent(mockQueries, "transaction.access_denied", "txn_pay")
+
+		transaction, err := prs.PayPaymentRequest(context.Background(), "tok_abc", "user_1", money.USD, "pay-key-1")
+
+		assert.Nil(t, transaction)
+		assert.ErrorIs(t, err, utils.ErrDuplicatedKey)
+		mockQueries.AssertNotCalled(t, "GetPaymentRequestByToken", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMarkPaymentRequestPaid(t *testing.T) {
+	transaction := gen.Transaction{
+		ID:               "txn_pay",
+		TraceID:          sql.NullString{String: "trace-1", Valid: true},
+		ToWalletID:       sql.NullString{String: "wallet_user2_usd", Valid: true},
+		Amount:           2500,
+		Currency:         "USD",
+		PaymentRequestID: sql.NullString{String: "preq_1", Valid: true},
+	}
+
+	t.Run("marks the request paid and notifies the requester", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("GetPaymentRequestByIDForUpdate", mock.Anything, "preq_1").Return(testPaymentRequestRow(models.PaymentRequestPending), nil)
+		mockQueries.On("MarkPaymentRequestPaid", mock.Anything, gen.MarkPaymentRequestPaidParams{
+			TransactionID: sql.NullString{String: "txn_pay", Valid: true},
+			PaidBy:        sql.NullString{String: "user_1", Valid: true},
+			ID:            "preq_1",
+		}).Return(int64(1), nil)
+		expectAuditEvent(mockQueries, "payment_request.paid", "preq_1")
+
+		var entry gen.CreateOutboxEntryParams
+		mockQueries.On("CreateOutboxEntry", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { entry = args.Get(1).(gen.CreateOutboxEntryParams) }).
+			Return(gen.Outbox{}, nil)
+
+		err := markPaymentRequestPaid(context.Background(), mockQueries, transaction, "user_1")
+
+		require.NoError(t, err)
+		assert.Equal(t, string(queue.JobTypeNotification), entry.JobType)
+
+		var payload queue.NotificationJobPayload
+		require.NoError(t, json.Unmarshal(entry.Payload, &payload))
+		assert.Equal(t, queue.NotificationJobPayload{
+			Event:            NotificationPaymentRequestPaid,
+			UserID:           "user_2",
+			PaymentRequestID: "preq_1",
+			TransactionID:    "txn_pay",
+			Amount:           2500,
+			Currency:         "USD",
+			TraceID:          "trace-1",
+		}, payload)
+	})
+
+	t.Run("cancelled request fails the confirmation", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("GetPaymentRequestByIDForUpdate", mock.Anything, "preq_1").Return(testPaymentRequestRow(models.PaymentRequestCancelled), nil)
+
+		err := markPaymentRequestPaid(context.Background(), mockQueries, transaction, "user_1")
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		mockQueries.AssertNotCalled(t, "MarkPaymentRequestPaid", mock.Anything, mock.Anything)
+		mockQueries.AssertNotCalled(t, "CreateOutboxEntry", mock.Anything, mock.Anything)
+	})
+
+	t.Run("request paid concurrently", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("GetPaymentRequestByIDForUpdate", mock.Anything, "preq_1").Return(testPaymentRequestRow(models.PaymentRequestPending), nil)
+		mockQueries.On("MarkPaymentRequestPaid", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+		err := markPaymentRequestPaid(context.Background(), mockQueries, transaction, "user_1")
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		mockQueries.AssertNotCalled(t, "CreateOutboxEntry", mock.Anything, mock.Anything)
+	})
+}
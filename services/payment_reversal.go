@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/lib/pq"
+)
+
+// CancelTransaction abandons an initiated transfer before it is confirmed. No
+// money has moved yet, so only the status changes and the hold is released.
+func (ps *paymentService) CancelTransaction(ctx context.Context, transactionID string, userID string) (*models.Transaction, error) {
+	transaction, err := ps.queries.GetTransactionByID(ctx, transactionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.NotFoundErr("transaction not found")
+		}
+		return nil, utils.ServerErr(fmt.Errorf("get transaction: %w", err))
+	}
+
+	if !transaction.FromWalletID.Valid {
+		return nil, utils.BadRequestErr("transaction missing from wallet")
+	}
+
+	fromWallet, err := ps.wallet.GetWalletByID(ctx, transaction.FromWalletID.String)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromWallet.UserID != userID {
+		return nil, utils.BadRequestErr("transaction does not belong to user")
+	}
+
+	if transaction.Status != string(models.TransactionStatusInitiated) {
+		return nil, utils.BadRequestErr("only initiated transactions can be cancelled")
+	}
+
+	tx, err := ps.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := ps.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ps.queries
+	}
+
+	if err := abandonInitiatedTransaction(ctx, queries, transaction.ID, models.TransactionStatusCancelled, "cancelled by user"); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	return ps.getTransaction(ctx, transaction.ID)
+}
+
+// ReverseTransaction undoes a completed internal transfer by posting a linked
+// reversal transaction that moves the principal back from the recipient to the
+// sender at the original exchange rate. The fee charged on the original
+// transfer is not refunded.
+func (ps *paymentService) ReverseTransaction(ctx context.Context, transactionID string, adminUserID string, reason string, idempotencyKey string) (*models.Transaction, error) {
+	existing, err := ps.GetTransactionByIdempotencyKey(ctx, idempotencyKey)
+	if err == nil && existing != nil {
+		if existing.ParentTransactionID == nil || *existing.ParentTransactionID != transactionID {
+			return nil, utils.DuplicateKeyErr("idempotency key already used for another transaction")
+		}
+		return existing, nil
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return nil, utils.ServerErr(fmt.Errorf("check idempotency: %w", err))
+	}
+
+	original, err := ps.queries.GetTransactionByID(ctx, transactionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.NotFoundErr("transaction not found")
+		}
+		return nil, utils.ServerErr(fmt.Errorf("get transaction: %w", err))
+	}
+
+	if original.Type != string(models.TransactionTypeInternal) {
+		return nil, utils.BadRequestErr("only internal transfers can be reversed")
+	}
+
+	if original.Status != string(models.TransactionStatusCompleted) {
+		return nil, utils.BadRequestErr("only completed transactions can be reversed")
+	}
+
+	if !original.FromWalletID.Valid || !original.ToWalletID.Valid {
+		return nil, utils.ServerErr(fmt.Errorf("transaction %s is missing wallets", original.ID))
+	}
+
+	if !original.ExchangeRate.Valid {
+		return nil, utils.ServerErr(fmt.Errorf("exchange rate not found in transaction"))
+	}
+
+	exchangeRate, err := strconv.ParseFloat(original.ExchangeRate.String, 64)
+	if err != nil || exchangeRate <= 0 {
+		return nil, utils.ServerErr(fmt.Errorf("invalid exchange rate: %s", original.ExchangeRate.String))
+	}
+
+	recipientCurrency, err := money.ParseCurrency(original.Currency)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("parse to currency: %w", err))
+	}
+
+	tx, err := ps.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := ps.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ps.queries
+	}
+
+	lockedSenderWallet, err := ps.wallet.LockWalletForUpdate(ctx, tx, original.FromWalletID.String)
+	if err != nil {
+		return nil, err
+	}
+
+	lockedRecipientWallet, err := ps.wallet.LockWalletForUpdate(ctx, tx, original.ToWalletID.String)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkWalletCredit(lockedSenderWallet, "sender"); err != nil {
+		return nil, err
+	}
+	if err := checkWalletDebit(lockedRecipientWallet, "recipient"); err != nil {
+		return nil, err
+	}
+
+	senderCurrency, err := money.ParseCurrency(lockedSenderWallet.Currency)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("parse from currency: %w", err))
+	}
+
+	recipientAmount := money.NewMoney(original.Amount, recipientCurrency)
+	senderAmount := money.NewMoney(int64(float64(original.Amount)/exchangeRate), senderCurrency)
+
+	if lockedRecipientWallet.AvailableBalance() < recipientAmount.Amount {
+		return nil, utils.BadRequestErr("recipient has insufficient balance for reversal")
+	}
+
+	if err := transitionTransactionStatus(ctx, queries, original.ID, models.TransactionStatusCompleted, models.TransactionStatusReversed, reason); err != nil {
+		return nil, err
+	}
+
+	// The status update above holds the row lock, so no refund can slip in
+	// between this check and the commit.
+	refundTotals, err := queries.GetRefundTotals(ctx, original.ID)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("get refund totals: %w", err))
+	}
+	if refundTotals.RefundedAmount > 0 {
+		return nil, utils.BadRequestErr("transaction has been partially refunded; refund the remaining amount instead")
+	}
+
+	reverseRateStr := strconv.FormatFloat(1/exchangeRate, 'f', 8, 64)
+	traceID := utils.TraceIDFromContext(ctx)
+	reversal, err := queries.CreateTransaction(ctx, gen.CreateTransactionParams{
+		IdempotencyKey:      idempotencyKey,
+		TraceID:             sql.NullString{String: traceID, Valid: traceID != ""},
+		FromWalletID:        sql.NullString{String: lockedRecipientWallet.ID, Valid: true},
+		ToWalletID:          sql.NullString{String: lockedSenderWallet.ID, Valid: true},
+		Type:                string(models.TransactionTypeReversal),
+		Amount:              senderAmount.Amount,
+		Currency:            senderAmount.Currency.String(),
+		Status:              string(models.TransactionStatusCompleted),
+		ExchangeRate:        sql.NullString{String: reverseRateStr, Valid: true},
+		ParentTransactionID: sql.NullString{String: original.ID, Valid: true},
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, utils.DuplicateKeyErr("transaction with this idempotency key already exists")
+		}
+		return nil, utils.ServerErr(fmt.Errorf("create reversal transaction: %w", err))
+	}
+
+	if err := recordTransactionCreated(ctx, queries, reversal); err != nil {
+		return nil, err
+	}
+
+	if err := ps.ledger.CreateDebitEntry(ctx, tx, lockedRecipientWallet.ID, reversal.ID, -recipientAmount.Amount, recipientAmount.Currency); err != nil {
+		return nil, err
+	}
+
+	if err := ps.ledger.CreateCreditEntry(ctx, tx, lockedSenderWallet.ID, reversal.ID, senderAmount.Amount, senderAmount.Currency); err != nil {
+		return nil, err
+	}
+
+	newRecipientBalance, err := money.NewMoney(lockedRecipientWallet.Balance, recipientCurrency).Subtract(recipientAmount)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("calculate new recipient balance: %w", err))
+	}
+
+	newSenderBalance, err := money.NewMoney(lockedSenderWallet.Balance, senderCurrency).Add(senderAmount)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("calculate new sender balance: %w", err))
+	}
+
+	if err := queries.UpdateWalletBalance(ctx, gen.UpdateWalletBalanceParams{
+		Balance: newRecipientBalance.Amount,
+		ID:      lockedRecipientWallet.ID,
+	}); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("update recipient wallet balance: %w", err))
+	}
+
+	if err := queries.UpdateWalletBalance(ctx, gen.UpdateWalletBalanceParams{
+		Balance: newSenderBalance.Amount,
+		ID:      lockedSenderWallet.ID,
+	}); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("update sender wallet balance: %w", err))
+	}
+
+	_, err = queries.CreateIdempotencyKey(ctx, gen.CreateIdempotencyKeyParams{
+		Key:           idempotencyKey,
+		TransactionID: reversal.ID,
+	})
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("create idempotency key: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	utils.Logger.Info().
+		Str("transaction_id", original.ID).
+		Str("reversal_id", reversal.ID).
+		Str("admin_user_id", adminUserID).
+		Str("reason", reason).
+		Str("trace_id", traceID).
+		Msg("transaction reversed")
+
+	return mapTransaction(reversal), nil
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentService_CancelTransaction(t *testing.T) {
+	initiated := gen.Transaction{
+		ID:           "tx_1",
+		FromWalletID: sql.NullString{String: "wallet_1", Valid: true},
+		Type:         string(models.TransactionTypeInternal),
+		Status:       string(models.TransactionStatusInitiated),
+	}
+
+	t.Run("not owner", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockWallet := new(mocks.MockWalletService)
+		ps := &paymentService{queries: mockQueries, wallet: mockWallet}
+
+		mockQueries.On("GetTransactionByID", mock.Anything, "tx_1").Return(initiated, nil)
+		mockWallet.On("GetWalletByID", mock.Anything, "wallet_1").Return(&models.Wallet{ID: "wallet_1", UserID: "user_2"}, nil)
+
+		_, err := ps.CancelTransaction(context.Background(), "tx_1", "user_1")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "does not belong to user")
+		mockQueries.AssertNotCalled(t, "TransitionTransactionStatus", mock.Anything, mock.Anything)
+	})
+
+	t.Run("not initiated", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockWallet := new(mocks.MockWalletService)
+		ps := &paymentService{queries: mockQueries, wallet: mockWallet}
+
+		completed := initiated
+		completed.Status = string(models.TransactionStatusCompleted)
+
+		mockQueries.On("GetTransactionByID", mock.Anything, "tx_1").Return(completed, nil)
+		mockWallet.On("GetWalletByID", mock.Anything, "wallet_1").Return(&models.Wallet{ID: "wallet_1", UserID: "user_1"}, nil)
+
+		_, err := ps.CancelTransaction(context.Background(), "tx_1", "user_1")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "only initiated transactions can be cancelled")
+	})
+}
+
+func TestAbandonInitiatedTransaction(t *testing.T) {
+	t.Run("cancels and releases hold", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+
+		mockQueries.On("TransitionTransactionStatus", mock.Anything, gen.TransitionTransactionStatusParams{
+			NewStatus:     string(models.TransactionStatusCancelled),
+			ID:            "tx_1",
+			CurrentStatus: string(models.TransactionStatusInitiated),
+		}).Return(int64(1), nil)
+		mockQueries.On("CreateTransactionStatusHistory", mock.Anything, gen.CreateTransactionStatusHistoryParams{
+			TransactionID: "tx_1",
+			FromStatus:    string(models.TransactionStatusInitiated),
+			ToStatus:      string(models.TransactionStatusCancelled),
+			Reason:        sql.NullString{String: "cancelled by user", Valid: true},
+		}).Return(nil)
+		expectAuditEvent(mockQueries, "transaction.status_changed", "tx_1")
+		expectAuditEvent(mockQueries, "wallet.hold_released", "wallet_1")
+		mockQueries.On("ReleaseWalletHold", mock.Anything, gen.ReleaseWalletHoldParams{
+			Status:        string(models.WalletHoldStatusReleased),
+			TransactionID: "tx_1",
+		}).Return(gen.WalletHold{WalletID: "wallet_1", TransactionID: "tx_1", Amount: 5000}, nil)
+		mockQueries.On("AdjustWalletHeldBalance", mock.Anything, gen.AdjustWalletHeldBalanceParams{
+			Delta: -5000,
+			ID:    "wallet_1",
+		}).Return(nil)
+
+		err := abandonInitiatedTransaction(context.Background(), mockQueries, "tx_1", models.TransactionStatusCancelled, "cancelled by user")
+
+		require.NoError(t, err)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("lost race with confirm", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("TransitionTransactionStatus", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+		err := abandonInitiatedTransaction(context.Background(), mockQueries, "tx_1", models.TransactionStatusCancelled, "cancelled by user")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "no longer in initiated status")
+		mockQueries.AssertNotCalled(t, "CreateTransactionStatusHistory", mock.Anything, mock.Anything)
+		mockQueries.AssertNotCalled(t, "ReleaseWalletHold", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPaymentService_ReverseTransaction_Validation(t *testing.T) {
+	tests := []struct {
+		name        string
+		transaction gen.Transaction
+		expected    string
+	}{
+		{
+			name:        "external transfer",
+			transaction: gen.Transaction{ID: "tx_1", Type: string(models.TransactionTypeExternal), Status: string(models.TransactionStatusCompleted)},
+			expected:    "only internal transfers can be reversed",
+		},
+		{
+			name:        "not completed",
+			transaction: gen.Transaction{ID: "tx_1", Type: string(models.TransactionTypeInternal), Status: string(models.TransactionStatusInitiated)},
+			expected:    "only completed transactions can be reversed",
+		},
+		{
+			name:        "already reversed",
+			transaction: gen.Transaction{ID: "tx_1", Type: string(models.TransactionTypeInternal), Status: string(models.TransactionStatusReversed)},
+			expected:    "only completed transactions can be reversed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockQueries := new(mocks.MockQuerier)
+			ps := &paymentService{queries: mockQueries}
+
+			mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "rev_key").Return(nil, sql.ErrNoRows)
+			mockQueries.On("GetTransactionByID", mock.Anything, "tx_1").Return(tt.transaction, nil)
+
+			_, err := ps.ReverseTransaction(context.Background(), "tx_1", "admin_1", "mistake", "rev_key")
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+
+	t.Run("idempotent replay returns existing reversal", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &paymentService{queries: mockQueries}
+
+		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "rev_key").Return(gen.Transaction{
+			ID:                  "rev_1",
+			Type:                string(models.TransactionTypeReversal),
+			Status:              string(models.TransactionStatusCompleted),
+			ParentTransactionID: sql.NullString{String: "tx_1", Valid: true},
+		}, nil)
+
+		result, err := ps.ReverseTransaction(context.Background(), "tx_1", "admin_1", "mistake", "rev_key")
+
+		require.NoError(t, err)
+		assert.Equal(t, "rev_1", result.ID)
+		mockQueries.AssertNotCalled(t, "GetTransactionByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("idempotency key reused for another transaction", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &paymentService{queries: mockQueries}
+
+		mockQueries.On("GetTransactionByIdempotencyKey", mock.Anything, "rev_key").Return(gen.Transaction{ID: "tx_other"}, nil)
+
+		_, err := ps.ReverseTransaction(context.Background(), "tx_1", "admin_1", "mistake", "rev_key")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "idempotency key already used")
+	})
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+)
+
+// declineTransaction fails an initiated transaction that risk screening denied
+// and releases its hold. The returned error does not say which rules fired.
+func (ps *paymentService) declineTransaction(ctx context.Context, transactionID string) error {
+	tx, err := ps.db.BeginTx(ctx, nil)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := ps.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ps.queries
+	}
+
+	if err := abandonInitiatedTransaction(ctx, queries, transactionID, models.TransactionStatusFailed, "declined by risk screening"); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	return utils.DeclinedErr("transaction declined")
+}
+
+// holdTransactionForReview parks an initiated transaction until an analyst
+// decides on it. The wallet hold stays in place so the funds remain reserved.
+func (ps *paymentService) holdTransactionForReview(ctx context.Context, transactionID string, reason string) (*models.Transaction, error) {
+	if err := transitionTransactionStatus(ctx, ps.queries, transactionID, models.TransactionStatusInitiated, models.TransactionStatusOnHold, reason); err != nil {
+		return nil, err
+	}
+
+	return ps.getTransaction(ctx, transactionID)
+}
+
+// ReviewTransaction records an analyst's decision on an on_hold transaction.
+// Approval completes the confirmation the user already authorised; rejection
+// fails the transaction and releases its hold.
+func (ps *paymentService) ReviewTransaction(ctx context.Context, transactionID string, adminUserID string, decision models.RiskReviewDecision, note string) (*models.Transaction, error) {
+	if decision != models.RiskReviewApproved && decision != models.RiskReviewRejected {
+		return nil, utils.BadRequestErr("decision must be approved or rejected")
+	}
+
+	transaction, err := ps.queries.GetTransactionByID(ctx, transactionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.NotFoundErr("transaction not found")
+		}
+		return nil, utils.ServerErr(fmt.Errorf("get transaction: %w", err))
+	}
+
+	if transaction.Status != string(models.TransactionStatusOnHold) {
+		return nil, utils.BadRequestErr("transaction is not on hold for review")
+	}
+
+	assessment, err := ps.queries.GetLatestRiskAssessment(ctx, transaction.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.ServerErr(fmt.Errorf("risk assessment not found for on_hold transaction"))
+		}
+		return nil, utils.ServerErr(fmt.Errorf("get risk assessment: %w", err))
+	}
+
+	review := gen.RecordRiskReviewParams{
+		ReviewDecision: sql.NullString{String: string(decision), Valid: true},
+		ReviewedBy:     sql.NullString{String: adminUserID, Valid: true},
+		ReviewNote:     sql.NullString{String: note, Valid: note != ""},
+		ID:             assessment.ID,
+	}
+
+	utils.Logger.Warn().
+		Str("event", "risk_review_decided").
+		Str("transaction_id", transaction.ID).
+		Str("admin_user_id", adminUserID).
+		Str("decision", string(decision)).
+		Str("trace_id", utils.TraceIDFromContext(ctx)).
+		Msg("risk review decided")
+
+	if decision == models.RiskReviewRejected {
+		return ps.rejectHeldTransaction(ctx, transaction.ID, review)
+	}
+
+	// The confirm flows only succeed while the transaction is still on_hold,
+	// so a concurrent decision cannot complete it twice. The review is
+	// recorded in the confirm transaction, so an approval never moves money
+	// without leaving its decision behind.
+	return ps.completeConfirmation(ctx, transaction, &review)
+}
+
+func (ps *paymentService) rejectHeldTransaction(ctx context.Context, transactionID string, review gen.RecordRiskReviewParams) (*models.Transaction, error) {
+	tx, err := ps.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := ps.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ps.queries
+	}
+
+	if err := abandonTransaction(ctx, queries, transactionID, models.TransactionStatusOnHold, models.TransactionStatusFailed, "rejected by risk review"); err != nil {
+		return nil, err
+	}
+
+	if err := recordRiskReview(ctx, queries, transactionID, review); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	return ps.getTransaction(ctx, transactionID)
+}
+
+// recordRiskReview stores the decision on the transaction's risk assessment
+// and audits it. It must run in the transaction that applies the decision.
+func recordRiskReview(ctx context.Context, queries gen.Querier, transactionID string, review gen.RecordRiskReviewParams) error {
+	rows, err := queries.RecordRiskReview(ctx, review)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("record risk review: %w", err))
+	}
+	if rows == 0 {
+		return utils.BadRequestErr("transaction has already been reviewed")
+	}
+
+	return recordRiskReviewAudit(ctx, queries, transactionID, review)
+}
+
+func recordRiskReviewAudit(ctx context.Context, queries gen.Querier, transactionID string, review gen.RecordRiskReviewParams) error {
+	return recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "risk_review.decided",
+		EntityType: auditEntityTransaction,
+		EntityID:   transactionID,
+		Before:     map[string]any{"risk_assessment_id": review.ID},
+		After: map[string]any{
+			"risk_assessment_id": review.ID,
+			"decision":           review.ReviewDecision.String,
+			"note":               review.ReviewNote.String,
+		},
+	})
+}
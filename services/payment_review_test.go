@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPaymentService_ReviewTransaction(t *testing.T) {
+	held := gen.Transaction{
+		ID:           "tx_1",
+		FromWalletID: sql.NullString{String: "wallet_1", Valid: true},
+		Type:         string(models.TransactionTypeInternal),
+		Status:       string(models.TransactionStatusOnHold),
+	}
+
+	t.Run("invalid decision", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &paymentService{queries: mockQueries}
+
+		_, err := ps.ReviewTransaction(context.Background(), "tx_1", "admin_1", "maybe", "")
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		mockQueries.AssertNotCalled(t, "GetTransactionByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("not on hold", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &paymentService{queries: mockQueries}
+
+		initiated := held
+		initiated.Status = string(models.TransactionStatusInitiated)
+		mockQueries.On("GetTransactionByID", mock.Anything, "tx_1").Return(initiated, nil)
+
+		_, err := ps.ReviewTransaction(context.Background(), "tx_1", "admin_1", models.RiskReviewApproved, "")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not on hold for review")
+		mockQueries.AssertNotCalled(t, "GetLatestRiskAssessment", mock.Anything, mock.Anything)
+	})
+
+	t.Run("approval records the review inside the confirm transaction", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		external := &confirmRecorder{}
+		ps := &paymentService{queries: mockQueries, externalTransfer: external}
+
+		outbound := held
+		outbound.Type = string(models.TransactionTypeExternal)
+		mockQueries.On("GetTransactionByID", mock.Anything, "tx_1").Return(outbound, nil)
+		mockQueries.On("GetLatestRiskAssessment", mock.Anything, "tx_1").Return(gen.RiskAssessment{ID: "ra_1", TransactionID: "tx_1"}, nil)
+
+		_, err := ps.ReviewTransaction(context.Background(), "tx_1", "admin_1", models.RiskReviewApproved, "known customer")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &gen.RecordRiskReviewParams{
+			ReviewDecision: sql.NullString{String: "approved", Valid: true},
+			ReviewedBy:     sql.NullString{String: "admin_1", Valid: true},
+			ReviewNote:     sql.NullString{String: "known customer", Valid: true},
+			ID:             "ra_1",
+		}, external.review)
+		mockQueries.AssertNotCalled(t, "RecordRiskReview", mock.Anything, mock.Anything)
+		mockQueries.AssertNotCalled(t, "CreateAuditEvent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("transaction not found", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &paymentService{queries: mockQueries}
+
+		mockQueries.On("GetTransactionByID", mock.Anything, "tx_1").Return(nil, sql.ErrNoRows)
+
+		_, err := ps.ReviewTransaction(context.Background(), "tx_1", "admin_1", models.RiskReviewRejected, "")
+
+		assert.ErrorIs(t, err, utils.ErrNotFound)
+	})
+}
+
+func TestAbandonTransaction_FromOnHold(t *testing.T) {
+	mockQueries := new(mocks.MockQuerier)
+
+	mockQueries.On("TransitionTransactionStatus", mock.Anything, gen.TransitionTransactionStatusParams{
+		NewStatus:     "failed",
+		ID:            "tx_1",
+		CurrentStatus: "on_hold",
+	}).Return(int64(1), nil)
+	mockQueries.On("CreateTransactionStatusHistory", mock.Anything, gen.CreateTransactionStatusHistoryParams{
+		TransactionID: "tx_1",
+		FromStatus:    "on_hold",
+		ToStatus:      "failed",
+		Reason:        sql.NullString{String: "rejected by risk review", Valid: true},
+	}).Return(nil)
+	mockQueries.On("ReleaseWalletHold", mock.Anything, gen.ReleaseWalletHoldParams{Status: "released", TransactionID: "tx_1"}).
+		Return(gen.WalletHold{WalletID: "wallet_1", Amount: 5000}, nil)
+	mockQueries.On("AdjustWalletHeldBalance", mock.Anything, gen.AdjustWalletHeldBalanceParams{Delta: -5000, ID: "wallet_1"}).Return(nil)
+	expectAuditEvent(mockQueries, "transaction.status_changed", "tx_1")
+	expectAuditEvent(mockQueries, "wallet.hold_released", "wallet_1")
+
+	err := abandonTransaction(context.Background(), mockQueries, "tx_1", models.TransactionStatusOnHold, models.TransactionStatusFailed, "rejected by risk review")
+
+	assert.NoError(t, err)
+	mockQueries.AssertExpectations(t)
+}
+
+// confirmRecorder stands in for the external transfer service and keeps the
+// review it was asked to record with the confirmation.
+type confirmRecorder struct {
+	ExternalTransferService
+	review *gen.RecordRiskReviewParams
+}
+
+func (c *confirmRecorder) confirmExternalTransfer(_ context.Context, transaction gen.Transaction, review *gen.RecordRiskReviewParams) (*models.Transaction, error) {
+	c.review = review
+	return &models.Transaction{ID: transaction.ID}, nil
+}
+
+func TestRecordRiskReview(t *testing.T) {
+	review := gen.RecordRiskReviewParams{
+		ReviewDecision: sql.NullString{String: "approved", Valid: true},
+		ReviewedBy:     sql.NullString{String: "admin_1", Valid: true},
+		ID:             "ra_1",
+	}
+
+	t.Run("records and audits the decision", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("RecordRiskReview", mock.Anything, review).Return(int64(1), nil)
+		expectAuditEvent(mockQueries, "risk_review.decided", "tx_1")
+
+		err := recordRiskReview(context.Background(), mockQueries, "tx_1", review)
+
+		assert.NoError(t, err)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("already reviewed", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("RecordRiskReview", mock.Anything, review).Return(int64(0), nil)
+
+		err := recordRiskReview(context.Background(), mockQueries, "tx_1", review)
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		mockQueries.AssertNotCalled(t, "CreateAuditEvent", mock.Anything, mock.Anything)
+	})
+}
@@ -43,14 +43,14 @@ func TestPaymentService_CreateInternalTransfer_Validation(t *testing.T) {
 
 	t.Run("zero amount should fail", func(t *testing.T) {
 		zeroAmount := money.NewMoney(0, money.USD)
-		_, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", money.USD, zeroAmount, "key_1")
+		_, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", "", "", money.USD, zeroAmount, "key_1")
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "amount must be positive")
 	})
 
 	t.Run("negative amount should fail", func(t *testing.T) {
 		negativeAmount := money.NewMoney(-100, money.USD)
-		_, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", money.USD, negativeAmount, "key_1")
+		_, err := ps.CreateInternalTransfer(context.Background(), "user_1", "1234567890", "044", "", "", money.USD, negativeAmount, "key_1")
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "amount must be positive")
 	})
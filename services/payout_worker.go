@@ -22,15 +22,19 @@ type PayoutWorker interface {
 
 type payoutWorker struct {
 	queries  *gen.Queries
+	db       *sql.DB
 	provider *providers.Processor
 	queue    queue.Queue
+	fields   fieldCipher
 }
 
-func newPayoutWorker(queries *gen.Queries, provider *providers.Processor, queue queue.Queue) PayoutWorker {
+func newPayoutWorker(queries *gen.Queries, db *sql.DB, provider *providers.Processor, queue queue.Queue, fields fieldCipher) PayoutWorker {
 	return &payoutWorker{
 		queries:  queries,
+		db:       db,
 		provider: provider,
 		queue:    queue,
+		fields:   fields,
 	}
 }
 
@@ -41,12 +45,11 @@ func (pw *payoutWorker) ProcessPayoutJob(ctx context.Context, job *queue.Job) er
 	}
 
 	ctx = utils.WithTraceID(ctx, payload.TraceID)
+	ctx = utils.WithActor(ctx, utils.SystemActor("payout_worker"))
 
 	utils.Logger.Info().
 		Str("trace_id", payload.TraceID).
 		Str("transaction_id", payload.TransactionID).
-		Str("account_number", payload.AccountNumber).
-		Str("bank_code", payload.BankCode).
 		Int64("amount", payload.Amount).
 		Str("currency", payload.Currency).
 		Msg("processing payout job")
@@ -65,6 +68,16 @@ func (pw *payoutWorker) ProcessPayoutJob(ctx context.Context, job *queue.Job) er
 		return fmt.Errorf("invalid currency: %w", err)
 	}
 
+	recipient, err := loadTransferRecipient(ctx, pw.queries, pw.fields, transaction)
+	if err != nil {
+		return fmt.Errorf("load transfer recipient: %w", err)
+	}
+
+	accountName := ""
+	if recipient.AccountName != nil {
+		accountName = *recipient.AccountName
+	}
+
 	providerRef := pw.generateProviderReference(payload.TransactionID)
 
 	err = pw.queries.UpdateTransactionWithProvider(ctx, gen.UpdateTransactionWithProviderParams{
@@ -81,9 +94,9 @@ func (pw *payoutWorker) ProcessPayoutJob(ctx context.Context, job *queue.Job) er
 		Amount: money.NewMoney(payload.Amount, currency),
 		Destination: providers.BankAccount{
 			BankName:      "",
-			BankCode:      payload.BankCode,
-			AccountNumber: payload.AccountNumber,
-			AccountName:   "",
+			BankCode:      recipient.BankCode,
+			AccountNumber: recipient.AccountNumber,
+			AccountName:   accountName,
 			Currency:      currency,
 		},
 		Metadata: map[string]string{
@@ -106,17 +119,7 @@ func (pw *payoutWorker) ProcessPayoutJob(ctx context.Context, job *queue.Job) er
 		providerRef = payoutResp.ProviderRef
 	}
 
-	err = pw.queries.UpdateTransactionWithProvider(ctx, gen.UpdateTransactionWithProviderParams{
-		ProviderName:      sql.NullString{String: payoutResp.ProviderName, Valid: true},
-		ProviderReference: sql.NullString{String: string(providerRef), Valid: true},
-		Status:            string(models.TransactionStatusCompleted),
-		ID:                payload.TransactionID,
-	})
-	if err != nil {
-		return fmt.Errorf("update transaction: %w", err)
-	}
-
-	return nil
+	return pw.completeTransaction(ctx, payload.TransactionID, payoutResp.ProviderName, providerRef)
 }
 
 func (pw *payoutWorker) StartWorker(ctx context.Context) error {
@@ -138,13 +141,54 @@ func (pw *payoutWorker) StartWorker(ctx context.Context) error {
 	}
 }
 
+func (pw *payoutWorker) completeTransaction(ctx context.Context, transactionID string, providerName string, providerRef providers.ProviderReference) error {
+	return pw.inTx(ctx, func(queries *gen.Queries) error {
+		err := queries.UpdateTransactionWithProvider(ctx, gen.UpdateTransactionWithProviderParams{
+			ProviderName:      sql.NullString{String: providerName, Valid: true},
+			ProviderReference: sql.NullString{String: string(providerRef), Valid: true},
+			Status:            string(models.TransactionStatusCompleted),
+			ID:                transactionID,
+		})
+		if err != nil {
+			return fmt.Errorf("update transaction: %w", err)
+		}
+
+		return recordStatusChangeAudit(ctx, queries, transactionID, models.TransactionStatusPending, models.TransactionStatusCompleted, "")
+	})
+}
+
 func (pw *payoutWorker) failTransaction(ctx context.Context, transactionID string, reason string) error {
-	err := pw.queries.UpdateTransactionFailure(ctx, gen.UpdateTransactionFailureParams{
-		Status:        string(models.TransactionStatusFailed),
-		FailureReason: sql.NullString{String: reason, Valid: true},
-		ID:            transactionID,
+	return pw.inTx(ctx, func(queries *gen.Queries) error {
+		err := queries.UpdateTransactionFailure(ctx, gen.UpdateTransactionFailureParams{
+			Status:        string(models.TransactionStatusFailed),
+			FailureReason: sql.NullString{String: reason, Valid: true},
+			ID:            transactionID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordStatusChangeAudit(ctx, queries, transactionID, models.TransactionStatusPending, models.TransactionStatusFailed, reason)
 	})
-	return err
+}
+
+// inTx runs fn with queries bound to a new transaction, so a status update
+// and its audit event commit together.
+func (pw *payoutWorker) inTx(ctx context.Context, fn func(queries *gen.Queries) error) error {
+	tx, err := pw.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(pw.queries.WithTx(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
 }
 
 func (pw *payoutWorker) generateProviderReference(transactionID string) providers.ProviderReference {
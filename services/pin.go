@@ -0,0 +1,598 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+)
+
+const (
+	defaultMaxPINAttempts     = 5
+	defaultPINLockoutDuration = 30 * time.Minute
+	defaultPINHistorySize     = 5
+	defaultPINResetTTL        = 15 * time.Minute
+
+	// maxPINLockoutDoublings caps progressive lockouts at 16x the base duration.
+	maxPINLockoutDoublings = 4
+
+	// pinClaimTimeout is how long a full set of claimed attempts with no
+	// lockout may stay unresolved before the next attempt starts counting
+	// again. It is far longer than any PIN or code check takes.
+	pinClaimTimeout = time.Minute
+)
+
+type PINService interface {
+	VerifyPIN(ctx context.Context, userID string, pin string) error
+	UnlockPIN(ctx context.Context, userID string, adminUserID string) error
+	SetPIN(ctx context.Context, userID string, pin string) error
+	ChangePIN(ctx context.Context, userID string, oldPIN string, newPIN string) error
+	CreatePINResetToken(ctx context.Context, userID string, adminUserID string) (*models.PINResetToken, error)
+	ResetPIN(ctx context.Context, userID string, token string, newPIN string) error
+	claimAttempt(ctx context.Context, userID string) (gen.PinAttempt, error)
+	recordFailure(ctx context.Context, userID string, attempt gen.PinAttempt, message string) error
+	clearFailures(ctx context.Context, userID string) error
+}
+
+type pinService struct {
+	queries         gen.Querier
+	db              *sql.DB
+	maxAttempts     int
+	lockoutDuration time.Duration
+	historySize     int
+	resetTokenTTL   time.Duration
+}
+
+func newPINService(queries gen.Querier, db *sql.DB, maxAttempts int, lockoutDuration time.Duration, historySize int, resetTokenTTL time.Duration) PINService {
+	return &pinService{
+		queries:         queries,
+		db:              db,
+		maxAttempts:     maxAttempts,
+		lockoutDuration: lockoutDuration,
+		historySize:     historySize,
+		resetTokenTTL:   resetTokenTTL,
+	}
+}
+
+// VerifyPIN checks a user's PIN while counting failures. After maxAttempts
+// consecutive failures the PIN is locked; each lockout since the last success
+// doubles the lock duration.
+func (ps *pinService) VerifyPIN(ctx context.Context, userID string, pin string) error {
+	user, err := ps.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return utils.NotFoundErr("user not found")
+		}
+		return utils.ServerErr(fmt.Errorf("get user: %w", err))
+	}
+
+	if !user.PinHash.Valid {
+		return utils.BadRequestErr("PIN not set for user")
+	}
+
+	attempt, err := ps.claimAttempt(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := utils.VerifyPIN(user.PinHash.String, pin); err != nil {
+		return ps.recordFailure(ctx, userID, attempt, "invalid PIN")
+	}
+
+	return ps.clearFailures(ctx, userID)
+}
+
+// claimAttempt counts an attempt before the factor is checked, so parallel
+// requests cannot all get past the lockout and at most maxAttempts guesses
+// are in play per lockout window. It returns a PIN locked error while a
+// lockout is in force or while maxAttempts attempts are still being checked.
+// The lockout covers every confirmation factor, so a locked user cannot
+// switch to TOTP codes to keep guessing. Claims that a failed request never
+// resolved stop counting after pinClaimTimeout, so they cannot block the user
+// for good.
+func (ps *pinService) claimAttempt(ctx context.Context, userID string) (gen.PinAttempt, error) {
+	attempt, err := ps.queries.ClaimPINAttempt(ctx, gen.ClaimPINAttemptParams{
+		UserID:      userID,
+		MaxAttempts: int32(ps.maxPINAttempts()),
+		StaleBefore: time.Now().Add(-pinClaimTimeout),
+	})
+	if err == nil {
+		return attempt, nil
+	}
+	if err != sql.ErrNoRows {
+		return gen.PinAttempt{}, utils.ServerErr(fmt.Errorf("claim pin attempt: %w", err))
+	}
+
+	return gen.PinAttempt{}, ps.lockedErr(ctx, userID)
+}
+
+// recordFailure handles a wrong confirmation factor for an attempt already
+// counted by claimAttempt, and locks the PIN once the limit is reached. It
+// returns message as a bad request below the limit. The lockout is written
+// even if the caller has gone away, so it is not left unresolved.
+func (ps *pinService) recordFailure(ctx context.Context, userID string, attempt gen.PinAttempt, message string) error {
+	if int(attempt.FailedAttempts) < ps.maxPINAttempts() {
+		return utils.BadRequestErr(message)
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	lockedUntil := time.Now().Add(ps.lockoutFor(int(attempt.LockoutCount)))
+
+	tx, err := ps.db.BeginTx(ctx, nil)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := ps.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ps.queries
+	}
+
+	if err := lockPIN(ctx, queries, userID, lockedUntil, attempt.LockoutCount+1); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	utils.Logger.Warn().
+		Str("event", "pin_locked").
+		Str("user_id", userID).
+		Int32("lockout_count", attempt.LockoutCount+1).
+		Time("locked_until", lockedUntil).
+		Str("trace_id", utils.TraceIDFromContext(ctx)).
+		Msg("PIN locked after too many failed attempts")
+
+	return pinLockedErr(lockedUntil)
+}
+
+// lockPIN starts a lockout and audits it. lockoutCount is the number of
+// lockouts since the last success, including this one.
+func lockPIN(ctx context.Context, queries gen.Querier, userID string, lockedUntil time.Time, lockoutCount int32) error {
+	if err := queries.LockPIN(ctx, gen.LockPINParams{
+		LockedUntil: sql.NullTime{Time: lockedUntil, Valid: true},
+		UserID:      userID,
+	}); err != nil {
+		return utils.ServerErr(fmt.Errorf("lock pin: %w", err))
+	}
+
+	return recordPINAuditEvent(ctx, queries, "pin.locked", userID, map[string]any{
+		"lockout_count": lockoutCount,
+		"locked_until":  lockedUntil.UTC().Format(time.RFC3339),
+	})
+}
+
+// clearFailures resets the counters after a correct factor. A lockout started
+// by a parallel wrong guess while this one was being checked still stands, and
+// the correct guess is rejected with it. Like recordFailure, it runs even if
+// the caller has gone away.
+func (ps *pinService) clearFailures(ctx context.Context, userID string) error {
+	ctx = context.WithoutCancel(ctx)
+	cleared, err := ps.queries.ClearPINAttempts(ctx, userID)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("clear pin attempts: %w", err))
+	}
+	if cleared == 0 {
+		return ps.lockedErr(ctx, userID)
+	}
+	return nil
+}
+
+// lockedErr reports why an attempt cannot proceed: the lockout in force, or
+// too many attempts still being checked when none has started yet.
+func (ps *pinService) lockedErr(ctx context.Context, userID string) error {
+	attempts, err := ps.queries.GetPINAttempts(ctx, userID)
+	if err != nil && err != sql.ErrNoRows {
+		return utils.ServerErr(fmt.Errorf("get pin attempts: %w", err))
+	}
+	if err == nil && attempts.LockedUntil.Valid && time.Now().Before(attempts.LockedUntil.Time) {
+		return pinLockedErr(attempts.LockedUntil.Time)
+	}
+	return utils.PINLockedErr("too many PIN attempts in progress, try again shortly")
+}
+
+// UnlockPIN clears a lockout and the failure counters so the user can try
+// again immediately.
+func (ps *pinService) UnlockPIN(ctx context.Context, userID string, adminUserID string) error {
+	if _, err := ps.queries.GetUserByID(ctx, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return utils.NotFoundErr("user not found")
+		}
+		return utils.ServerErr(fmt.Errorf("get user: %w", err))
+	}
+
+	tx, err := ps.db.BeginTx(ctx, nil)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := ps.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ps.queries
+	}
+
+	if err := unlockPIN(ctx, queries, userID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	utils.Logger.Info().
+		Str("event", "pin_unlocked").
+		Str("user_id", userID).
+		Str("admin_user_id", adminUserID).
+		Str("trace_id", utils.TraceIDFromContext(ctx)).
+		Msg("PIN unlocked by admin")
+
+	return nil
+}
+
+func unlockPIN(ctx context.Context, queries gen.Querier, userID string) error {
+	if err := queries.ResetPINAttempts(ctx, userID); err != nil {
+		return utils.ServerErr(fmt.Errorf("reset pin attempts: %w", err))
+	}
+
+	return recordPINAuditEvent(ctx, queries, "pin.unlocked", userID, nil)
+}
+
+// SetPIN sets the first PIN for a user who has none.
+func (ps *pinService) SetPIN(ctx context.Context, userID string, pin string) error {
+	if err := validateNewPIN(pin); err != nil {
+		return err
+	}
+
+	pinHash, err := utils.HashPIN(pin)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("hash pin: %w", err))
+	}
+
+	tx, err := ps.db.BeginTx(ctx, nil)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := ps.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ps.queries
+	}
+
+	updated, err := queries.SetInitialUserPIN(ctx, gen.SetInitialUserPINParams{
+		PinHash: sql.NullString{String: pinHash, Valid: true},
+		UserID:  userID,
+	})
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("set pin: %w", err))
+	}
+
+	if updated == 0 {
+		if _, err := queries.GetUserByID(ctx, userID); err == sql.ErrNoRows {
+			return utils.NotFoundErr("user not found")
+		}
+		return utils.BadRequestErr("PIN already set; use change PIN instead")
+	}
+
+	if err := queries.CreatePINHistory(ctx, gen.CreatePINHistoryParams{
+		UserID:  userID,
+		PinHash: pinHash,
+	}); err != nil {
+		return utils.ServerErr(fmt.Errorf("record pin history: %w", err))
+	}
+
+	if err := recordPINAuditEvent(ctx, queries, "pin.set", userID, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	return nil
+}
+
+// ChangePIN replaces a user's PIN after checking the current one. Wrong
+// current PINs count towards the lockout like any other PIN check.
+func (ps *pinService) ChangePIN(ctx context.Context, userID string, oldPIN string, newPIN string) error {
+	if err := validateNewPIN(newPIN); err != nil {
+		return err
+	}
+
+	if err := ps.VerifyPIN(ctx, userID, oldPIN); err != nil {
+		return err
+	}
+
+	tx, err := ps.db.BeginTx(ctx, nil)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := ps.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ps.queries
+	}
+
+	if err := replacePIN(ctx, queries, userID, newPIN, ps.pinHistorySize()); err != nil {
+		return err
+	}
+
+	if err := recordPINAuditEvent(ctx, queries, "pin.changed", userID, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	return nil
+}
+
+// CreatePINResetToken issues a single-use token that lets the user choose a
+// new PIN without the old one. Earlier unused tokens are revoked. Only the
+// token's hash is stored.
+func (ps *pinService) CreatePINResetToken(ctx context.Context, userID string, adminUserID string) (*models.PINResetToken, error) {
+	if _, err := ps.queries.GetUserByID(ctx, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.NotFoundErr("user not found")
+		}
+		return nil, utils.ServerErr(fmt.Errorf("get user: %w", err))
+	}
+
+	token, err := generatePINResetToken()
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("generate reset token: %w", err))
+	}
+
+	ttl := ps.resetTokenTTL
+	if ttl <= 0 {
+		ttl = defaultPINResetTTL
+	}
+	expiresAt := time.Now().Add(ttl)
+
+	tx, err := ps.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := ps.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ps.queries
+	}
+
+	if err := queries.RevokePINResetTokens(ctx, userID); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("revoke reset tokens: %w", err))
+	}
+
+	if _, err := queries.CreatePINResetToken(ctx, gen.CreatePINResetTokenParams{
+		UserID:    userID,
+		TokenHash: hashPINResetToken(token),
+		CreatedBy: adminUserID,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("create reset token: %w", err))
+	}
+
+	if err := recordPINAuditEvent(ctx, queries, "pin.reset_token_issued", userID, map[string]any{
+		"expires_at": expiresAt.UTC().Format(time.RFC3339),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	utils.Logger.Info().
+		Str("event", "pin_reset_token_issued").
+		Str("user_id", userID).
+		Str("admin_user_id", adminUserID).
+		Time("expires_at", expiresAt).
+		Str("trace_id", utils.TraceIDFromContext(ctx)).
+		Msg("PIN reset token issued")
+
+	return &models.PINResetToken{
+		UserID:    userID,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// ResetPIN consumes a reset token issued to userID and sets a new PIN. A
+// successful reset also clears any PIN lockout.
+func (ps *pinService) ResetPIN(ctx context.Context, userID string, token string, newPIN string) error {
+	if err := validateNewPIN(newPIN); err != nil {
+		return err
+	}
+
+	tx, err := ps.db.BeginTx(ctx, nil)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := ps.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ps.queries
+	}
+
+	if err := resetPINWithToken(ctx, queries, userID, token, newPIN, ps.pinHistorySize()); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	utils.Logger.Info().
+		Str("event", "pin_reset").
+		Str("user_id", userID).
+		Str("trace_id", utils.TraceIDFromContext(ctx)).
+		Msg("PIN reset with token")
+
+	return nil
+}
+
+func resetPINWithToken(ctx context.Context, queries gen.Querier, userID string, token string, newPIN string, historySize int) error {
+	resetToken, err := queries.ConsumePINResetToken(ctx, hashPINResetToken(token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return utils.BadRequestErr("invalid or expired reset token")
+		}
+		return utils.ServerErr(fmt.Errorf("consume reset token: %w", err))
+	}
+
+	// A token issued to someone else is reported exactly like an unknown one;
+	// the caller's transaction rolls back so it stays usable by its owner.
+	if resetToken.UserID != userID {
+		return utils.BadRequestErr("invalid or expired reset token")
+	}
+
+	if err := replacePIN(ctx, queries, userID, newPIN, historySize); err != nil {
+		return err
+	}
+
+	if err := queries.ResetPINAttempts(ctx, userID); err != nil {
+		return utils.ServerErr(fmt.Errorf("reset pin attempts: %w", err))
+	}
+
+	return recordPINAuditEvent(ctx, queries, "pin.reset", userID, nil)
+}
+
+// replacePIN stores newPIN as the user's PIN unless it matches the current PIN
+// or one of the last historySize PINs.
+func replacePIN(ctx context.Context, queries gen.Querier, userID string, newPIN string, historySize int) error {
+	user, err := queries.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return utils.NotFoundErr("user not found")
+		}
+		return utils.ServerErr(fmt.Errorf("get user: %w", err))
+	}
+
+	previous, err := queries.ListRecentPINHashes(ctx, gen.ListRecentPINHashesParams{
+		UserID:      userID,
+		HistorySize: int32(historySize),
+	})
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("list pin history: %w", err))
+	}
+	if user.PinHash.Valid {
+		previous = append(previous, user.PinHash.String)
+	}
+
+	for _, hash := range previous {
+		if utils.VerifyPIN(hash, newPIN) == nil {
+			return utils.BadRequestErr(fmt.Sprintf("PIN must differ from your last %d PINs", historySize))
+		}
+	}
+
+	pinHash, err := utils.HashPIN(newPIN)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("hash pin: %w", err))
+	}
+
+	if err := queries.UpdateUserPIN(ctx, gen.UpdateUserPINParams{
+		PinHash: sql.NullString{String: pinHash, Valid: true},
+		UserID:  userID,
+	}); err != nil {
+		return utils.ServerErr(fmt.Errorf("update pin: %w", err))
+	}
+
+	if err := queries.CreatePINHistory(ctx, gen.CreatePINHistoryParams{
+		UserID:  userID,
+		PinHash: pinHash,
+	}); err != nil {
+		return utils.ServerErr(fmt.Errorf("record pin history: %w", err))
+	}
+
+	return nil
+}
+
+// recordPINAuditEvent audits a PIN change. PINs and their hashes are never
+// part of the snapshot.
+func recordPINAuditEvent(ctx context.Context, queries gen.Querier, action string, userID string, after map[string]any) error {
+	return recordAuditEvent(ctx, queries, auditEvent{
+		Action:     action,
+		EntityType: auditEntityUser,
+		EntityID:   userID,
+		After:      after,
+	})
+}
+
+func validateNewPIN(pin string) error {
+	if !utils.IsValidPIN(pin) {
+		return utils.BadRequestErr("PIN must be exactly 5 numeric digits")
+	}
+	if utils.IsWeakPIN(pin) {
+		return utils.BadRequestErr("PIN is too easy to guess")
+	}
+	return nil
+}
+
+func generatePINResetToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashPINResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (ps *pinService) pinHistorySize() int {
+	if ps.historySize <= 0 {
+		return defaultPINHistorySize
+	}
+	return ps.historySize
+}
+
+func (ps *pinService) maxPINAttempts() int {
+	if ps.maxAttempts <= 0 {
+		return defaultMaxPINAttempts
+	}
+	return ps.maxAttempts
+}
+
+// lockoutFor returns the lock duration after previousLockouts earlier
+// lockouts.
+func (ps *pinService) lockoutFor(previousLockouts int) time.Duration {
+	base := ps.lockoutDuration
+	if base <= 0 {
+		base = defaultPINLockoutDuration
+	}
+	return base << min(previousLockouts, maxPINLockoutDoublings)
+}
+
+func pinLockedErr(lockedUntil time.Time) error {
+	return utils.PINLockedErr(fmt.Sprintf("PIN is locked until %s", lockedUntil.UTC().Format(time.RFC3339)))
+}
@@ -0,0 +1,350 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// expectedClaim matches the ClaimPINAttempt arguments for userID, with the
+// stale cutoff pinClaimTimeout in the past.
+func expectedClaim(userID string, maxAttempts int32) any {
+	return mock.MatchedBy(func(arg gen.ClaimPINAttemptParams) bool {
+		return arg.UserID == userID && arg.MaxAttempts == maxAttempts && time.Since(arg.StaleBefore) >= pinClaimTimeout
+	})
+}
+
+func TestPINService_VerifyPIN(t *testing.T) {
+	hashedPIN, err := utils.HashPIN("12345")
+	require.NoError(t, err)
+	user := gen.User{UserID: "user_1", PinHash: sql.NullString{String: hashedPIN, Valid: true}}
+	claim := expectedClaim("user_1", 5)
+
+	t.Run("correct PIN resets failures", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &pinService{queries: mockQueries, maxAttempts: 5, lockoutDuration: 30 * time.Minute}
+
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(user, nil)
+		mockQueries.On("ClaimPINAttempt", mock.Anything, claim).Return(gen.PinAttempt{UserID: "user_1", FailedAttempts: 4}, nil)
+		mockQueries.On("ClearPINAttempts", mock.Anything, "user_1").Return(int64(1), nil)
+
+		err := ps.VerifyPIN(context.Background(), "user_1", "12345")
+
+		require.NoError(t, err)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("wrong PIN below threshold", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &pinService{queries: mockQueries, maxAttempts: 5, lockoutDuration: 30 * time.Minute}
+
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(user, nil)
+		mockQueries.On("ClaimPINAttempt", mock.Anything, claim).Return(gen.PinAttempt{UserID: "user_1", FailedAttempts: 4}, nil)
+
+		err := ps.VerifyPIN(context.Background(), "user_1", "00000")
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		assert.Contains(t, err.Error(), "invalid PIN")
+		mockQueries.AssertNotCalled(t, "LockPIN", mock.Anything, mock.Anything)
+		mockQueries.AssertNotCalled(t, "ClearPINAttempts", mock.Anything, mock.Anything)
+	})
+
+	t.Run("wrong PIN at threshold locks", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &pinService{queries: mockQueries, db: newNopDB(), maxAttempts: 5, lockoutDuration: 30 * time.Minute}
+
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(user, nil)
+		mockQueries.On("ClaimPINAttempt", mock.Anything, claim).Return(gen.PinAttempt{UserID: "user_1", FailedAttempts: 5}, nil)
+		mockQueries.On("LockPIN", mock.Anything, mock.MatchedBy(func(arg gen.LockPINParams) bool {
+			until := time.Until(arg.LockedUntil.Time)
+			return arg.UserID == "user_1" && arg.LockedUntil.Valid && until > 29*time.Minute && until <= 30*time.Minute
+		})).Return(nil)
+		expectAuditEvent(mockQueries, "pin.locked", "user_1")
+
+		err := ps.VerifyPIN(context.Background(), "user_1", "00000")
+
+		assert.ErrorIs(t, err, utils.ErrPINLocked)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("locked PIN is rejected without checking it", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &pinService{queries: mockQueries, maxAttempts: 5, lockoutDuration: 30 * time.Minute}
+
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(user, nil)
+		mockQueries.On("ClaimPINAttempt", mock.Anything, claim).Return(nil, sql.ErrNoRows)
+		mockQueries.On("GetPINAttempts", mock.Anything, "user_1").Return(gen.PinAttempt{
+			UserID:      "user_1",
+			LockedUntil: sql.NullTime{Time: time.Now().Add(10 * time.Minute), Valid: true},
+		}, nil)
+
+		err := ps.VerifyPIN(context.Background(), "user_1", "12345")
+
+		assert.ErrorIs(t, err, utils.ErrPINLocked)
+		assert.Contains(t, err.Error(), "PIN is locked until")
+		mockQueries.AssertNotCalled(t, "ClearPINAttempts", mock.Anything, mock.Anything)
+	})
+
+	t.Run("attempts beyond the limit in flight are rejected", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &pinService{queries: mockQueries, maxAttempts: 5, lockoutDuration: 30 * time.Minute}
+
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(user, nil)
+		mockQueries.On("ClaimPINAttempt", mock.Anything, claim).Return(nil, sql.ErrNoRows)
+		mockQueries.On("GetPINAttempts", mock.Anything, "user_1").Return(gen.PinAttempt{UserID: "user_1", FailedAttempts: 5}, nil)
+
+		err := ps.VerifyPIN(context.Background(), "user_1", "12345")
+
+		assert.ErrorIs(t, err, utils.ErrPINLocked)
+		assert.Contains(t, err.Error(), "too many PIN attempts in progress")
+		mockQueries.AssertNotCalled(t, "ClearPINAttempts", mock.Anything, mock.Anything)
+	})
+
+	t.Run("correct PIN does not clear a lockout started while it was checked", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &pinService{queries: mockQueries, maxAttempts: 5, lockoutDuration: 30 * time.Minute}
+
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(user, nil)
+		mockQueries.On("ClaimPINAttempt", mock.Anything, claim).Return(gen.PinAttempt{UserID: "user_1", FailedAttempts: 3}, nil)
+		mockQueries.On("ClearPINAttempts", mock.Anything, "user_1").Return(int64(0), nil)
+		mockQueries.On("GetPINAttempts", mock.Anything, "user_1").Return(gen.PinAttempt{
+			UserID:       "user_1",
+			LockoutCount: 1,
+			LockedUntil:  sql.NullTime{Time: time.Now().Add(30 * time.Minute), Valid: true},
+		}, nil)
+
+		err := ps.VerifyPIN(context.Background(), "user_1", "12345")
+
+		assert.ErrorIs(t, err, utils.ErrPINLocked)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("claims left unresolved do not block later attempts", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &pinService{queries: mockQueries, maxAttempts: 5, lockoutDuration: 30 * time.Minute}
+
+		// Five attempts were claimed and never resolved: their requests
+		// failed before recording a result, so no lockout was written.
+		stuck := gen.PinAttempt{UserID: "user_1", FailedAttempts: 5, UpdatedAt: time.Now().Add(-2 * pinClaimTimeout)}
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(user, nil)
+		mockQueries.On("ClaimPINAttempt", mock.Anything, mock.MatchedBy(func(arg gen.ClaimPINAttemptParams) bool {
+			return arg.StaleBefore.After(stuck.UpdatedAt)
+		})).Return(gen.PinAttempt{UserID: "user_1", FailedAttempts: 1}, nil)
+		mockQueries.On("ClearPINAttempts", mock.Anything, "user_1").Return(int64(1), nil)
+
+		err := ps.VerifyPIN(context.Background(), "user_1", "12345")
+
+		require.NoError(t, err)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("attempt is resolved after the caller goes away", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &pinService{queries: mockQueries, maxAttempts: 5, lockoutDuration: 30 * time.Minute}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(user, nil)
+		mockQueries.On("ClaimPINAttempt", mock.Anything, claim).Run(func(mock.Arguments) { cancel() }).
+			Return(gen.PinAttempt{UserID: "user_1", FailedAttempts: 1}, nil)
+		mockQueries.On("ClearPINAttempts", mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil }), "user_1").
+			Return(int64(1), nil)
+
+		err := ps.VerifyPIN(ctx, "user_1", "12345")
+
+		require.NoError(t, err)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("expired lock allows retry", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &pinService{queries: mockQueries, maxAttempts: 5, lockoutDuration: 30 * time.Minute}
+
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(user, nil)
+		mockQueries.On("ClaimPINAttempt", mock.Anything, claim).Return(gen.PinAttempt{
+			UserID:         "user_1",
+			FailedAttempts: 1,
+			LockoutCount:   1,
+			LockedUntil:    sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true},
+		}, nil)
+		mockQueries.On("ClearPINAttempts", mock.Anything, "user_1").Return(int64(1), nil)
+
+		err := ps.VerifyPIN(context.Background(), "user_1", "12345")
+
+		require.NoError(t, err)
+		mockQueries.AssertExpectations(t)
+	})
+}
+
+func TestLockPIN(t *testing.T) {
+	mockQueries := new(mocks.MockQuerier)
+	lockedUntil := time.Now().Add(30 * time.Minute)
+
+	mockQueries.On("LockPIN", mock.Anything, gen.LockPINParams{
+		LockedUntil: sql.NullTime{Time: lockedUntil, Valid: true},
+		UserID:      "user_1",
+	}).Return(nil)
+	mockQueries.On("CreateAuditEvent", mock.Anything, mock.MatchedBy(func(arg gen.CreateAuditEventParams) bool {
+		return arg.Action == "pin.locked" &&
+			arg.EntityType == auditEntityUser &&
+			arg.EntityID == "user_1" &&
+			strings.Contains(string(arg.After), `"lockout_count":2`)
+	})).Return(nil)
+
+	err := lockPIN(context.Background(), mockQueries, "user_1", lockedUntil, 2)
+
+	require.NoError(t, err)
+	mockQueries.AssertExpectations(t)
+}
+
+func TestPINService_LockoutFor(t *testing.T) {
+	ps := &pinService{lockoutDuration: 30 * time.Minute}
+
+	assert.Equal(t, 30*time.Minute, ps.lockoutFor(0))
+	assert.Equal(t, time.Hour, ps.lockoutFor(1))
+	assert.Equal(t, 8*time.Hour, ps.lockoutFor(4))
+	assert.Equal(t, 8*time.Hour, ps.lockoutFor(10))
+
+	assert.Equal(t, defaultPINLockoutDuration, (&pinService{}).lockoutFor(0))
+}
+
+func TestPINService_UnlockPIN(t *testing.T) {
+	t.Run("resets attempts", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+
+		mockQueries.On("ResetPINAttempts", mock.Anything, "user_1").Return(nil)
+		expectAuditEvent(mockQueries, "pin.unlocked", "user_1")
+
+		err := unlockPIN(context.Background(), mockQueries, "user_1")
+
+		require.NoError(t, err)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ps := &pinService{queries: mockQueries}
+
+		mockQueries.On("GetUserByID", mock.Anything, "user_9").Return(gen.User{}, sql.ErrNoRows)
+
+		err := ps.UnlockPIN(context.Background(), "user_9", "admin_1")
+
+		assert.ErrorIs(t, err, utils.ErrNotFound)
+	})
+}
+
+func TestValidateNewPIN(t *testing.T) {
+	assert.NoError(t, validateNewPIN("27491"))
+
+	err := validateNewPIN("1234")
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+
+	err = validateNewPIN("11111")
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+	assert.Contains(t, err.Error(), "too easy to guess")
+}
+
+func TestReplacePIN(t *testing.T) {
+	currentHash, err := utils.HashPIN("27491")
+	require.NoError(t, err)
+	oldHash, err := utils.HashPIN("90817")
+	require.NoError(t, err)
+	user := gen.User{UserID: "user_1", PinHash: sql.NullString{String: currentHash, Valid: true}}
+	historyParams := gen.ListRecentPINHashesParams{UserID: "user_1", HistorySize: 5}
+
+	t.Run("rejects a recent PIN", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(user, nil)
+		mockQueries.On("ListRecentPINHashes", mock.Anything, historyParams).Return([]string{oldHash}, nil)
+
+		err := replacePIN(context.Background(), mockQueries, "user_1", "90817", 5)
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		assert.Contains(t, err.Error(), "last 5 PINs")
+		mockQueries.AssertNotCalled(t, "UpdateUserPIN", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects the current PIN", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(user, nil)
+		mockQueries.On("ListRecentPINHashes", mock.Anything, historyParams).Return(nil, nil)
+
+		err := replacePIN(context.Background(), mockQueries, "user_1", "27491", 5)
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+	})
+
+	t.Run("stores a new PIN and records it", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(user, nil)
+		mockQueries.On("ListRecentPINHashes", mock.Anything, historyParams).Return([]string{oldHash}, nil)
+		mockQueries.On("UpdateUserPIN", mock.Anything, mock.MatchedBy(func(arg gen.UpdateUserPINParams) bool {
+			return arg.UserID == "user_1" && utils.VerifyPIN(arg.PinHash.String, "38160") == nil
+		})).Return(nil)
+		mockQueries.On("CreatePINHistory", mock.Anything, mock.MatchedBy(func(arg gen.CreatePINHistoryParams) bool {
+			return arg.UserID == "user_1" && utils.VerifyPIN(arg.PinHash, "38160") == nil
+		})).Return(nil)
+
+		err := replacePIN(context.Background(), mockQueries, "user_1", "38160", 5)
+
+		require.NoError(t, err)
+		mockQueries.AssertExpectations(t)
+	})
+}
+
+func TestResetPINWithToken(t *testing.T) {
+	tokenHash := hashPINResetToken("reset-token")
+
+	t.Run("unknown or expired token", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("ConsumePINResetToken", mock.Anything, tokenHash).Return(nil, sql.ErrNoRows)
+
+		err := resetPINWithToken(context.Background(), mockQueries, "user_1", "reset-token", "38160", 5)
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		assert.Contains(t, err.Error(), "invalid or expired reset token")
+	})
+
+	t.Run("token issued to another user", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("ConsumePINResetToken", mock.Anything, tokenHash).Return(gen.PinResetToken{UserID: "user_2"}, nil)
+
+		err := resetPINWithToken(context.Background(), mockQueries, "user_1", "reset-token", "38160", 5)
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		mockQueries.AssertNotCalled(t, "UpdateUserPIN", mock.Anything, mock.Anything)
+	})
+
+	t.Run("sets PIN and clears lockout", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("ConsumePINResetToken", mock.Anything, tokenHash).Return(gen.PinResetToken{UserID: "user_1"}, nil)
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(gen.User{UserID: "user_1"}, nil)
+		mockQueries.On("ListRecentPINHashes", mock.Anything, mock.Anything).Return(nil, nil)
+		mockQueries.On("UpdateUserPIN", mock.Anything, mock.Anything).Return(nil)
+		mockQueries.On("CreatePINHistory", mock.Anything, mock.Anything).Return(nil)
+		mockQueries.On("ResetPINAttempts", mock.Anything, "user_1").Return(nil)
+		expectAuditEvent(mockQueries, "pin.reset", "user_1")
+
+		err := resetPINWithToken(context.Background(), mockQueries, "user_1", "reset-token", "38160", 5)
+
+		require.NoError(t, err)
+		mockQueries.AssertExpectations(t)
+	})
+}
+
+func TestHashPINResetToken(t *testing.T) {
+	token, err := generatePINResetToken()
+	require.NoError(t, err)
+
+	assert.Len(t, token, 43)
+	assert.NotEqual(t, token, hashPINResetToken(token))
+	assert.Equal(t, hashPINResetToken(token), hashPINResetToken(token))
+}
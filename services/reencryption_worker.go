@@ -0,0 +1,403 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+)
+
+const (
+	defaultReencryptionInterval = time.Hour
+	reencryptionBatchSize       = int32(100)
+)
+
+// ReencryptionWorker brings encrypted columns up to the keyring's active key.
+// It encrypts rows written before encryption was enabled and re-encrypts rows
+// wrapped with a retired key, so a retired key can be removed from the keyring
+// once a pass finds nothing left under it. It also replaces account numbers in
+// older risk assessment recipients with their blind index.
+type ReencryptionWorker interface {
+	StartWorker(ctx context.Context) error
+}
+
+type reencryptionWorker struct {
+	queries  gen.Querier
+	fields   fieldCipher
+	interval time.Duration
+}
+
+func newReencryptionWorker(queries gen.Querier, fields fieldCipher, interval time.Duration) ReencryptionWorker {
+	if interval <= 0 {
+		interval = defaultReencryptionInterval
+	}
+	return &reencryptionWorker{
+		queries:  queries,
+		fields:   fields,
+		interval: interval,
+	}
+}
+
+// reencryptionResult counts the rows one pass over a column rewrote, and the
+// rows it could not decrypt, typically because their key has already been
+// removed from the keyring.
+type reencryptionResult struct {
+	Reencrypted int
+	Failed      int
+}
+
+// errUndecryptable marks a stored value a pass cannot read. The row is logged,
+// counted as failed and skipped; any other error ends the pass.
+var errUndecryptable = errors.New("cannot decrypt stored value")
+
+func (rw *reencryptionWorker) StartWorker(ctx context.Context) error {
+	if !rw.fields.enabled() {
+		utils.Logger.Info().Msg("no encryption keyring configured; re-encryption worker idle")
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	utils.Logger.Info().Str("active_key", rw.fields.keyring.ActiveKeyID()).Msg("re-encryption worker started")
+	ticker := time.NewTicker(rw.interval)
+	defer ticker.Stop()
+
+	for {
+		rw.reencryptAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			utils.Logger.Info().Msg("re-encryption worker stopping")
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// reencryptAll makes one pass over every encrypted column. A pass walks each
+// table in id order, so rows that fail to decrypt are skipped rather than
+// returned in every batch. Updates only apply if the stored value is
+// unchanged, which leaves a row rewritten concurrently for the next pass.
+func (rw *reencryptionWorker) reencryptAll(ctx context.Context) {
+	columns := []struct {
+		field string
+		run   func(context.Context, gen.Querier, fieldCipher) (reencryptionResult, error)
+	}{
+		{fieldBankAccountNumber, reencryptBankAccountNumbers},
+		{fieldBeneficiaryAccount, reencryptBeneficiaryAccountNumbers},
+		{fieldRecipientDetails, reencryptRecipientDetails},
+		{fieldRecipientAccountNumber, reencryptRecipientAccountNumbers},
+		{fieldWebhookPayload, reencryptWebhookPayloads},
+		{fieldKYCDocumentNumber, reencryptKYCDocumentNumbers},
+		{fieldRiskRecipientAccount, indexRiskRecipients},
+	}
+
+	for _, column := range columns {
+		result, err := column.run(ctx, rw.queries, rw.fields)
+		if err != nil {
+			utils.Logger.Error().Err(err).Str("field", column.field).Msg("error re-encrypting column")
+			continue
+		}
+		if result.Reencrypted > 0 || result.Failed > 0 {
+			utils.Logger.Info().
+				Str("field", column.field).
+				Int("reencrypted", result.Reencrypted).
+				Int("failed", result.Failed).
+				Msg("re-encrypted column")
+		}
+	}
+}
+
+// reencryptColumn pages through one column in id order. list returns the batch
+// after afterID, and update rewrites one row, returning the number of rows it
+// changed.
+func reencryptColumn[Row any](ctx context.Context, field string, list func(ctx context.Context, afterID string) ([]Row, error), rowID func(Row) string, update func(ctx context.Context, row Row) (int64, error)) (reencryptionResult, error) {
+	var result reencryptionResult
+	afterID := ""
+	for {
+		rows, err := list(ctx, afterID)
+		if err != nil {
+			return result, err
+		}
+
+		for _, row := range rows {
+			afterID = rowID(row)
+			updated, err := update(ctx, row)
+			if errors.Is(err, errUndecryptable) {
+				utils.Logger.Warn().Err(err).Str("field", field).Str("id", afterID).Msg("cannot re-encrypt value")
+				result.Failed++
+				continue
+			}
+			if err != nil {
+				return result, err
+			}
+			result.Reencrypted += int(updated)
+		}
+
+		if len(rows) < int(reencryptionBatchSize) {
+			return result, nil
+		}
+	}
+}
+
+func reencryptBankAccountNumbers(ctx context.Context, queries gen.Querier, fields fieldCipher) (reencryptionResult, error) {
+	list := func(ctx context.Context, afterID string) ([]gen.ListBankAccountsToEncryptRow, error) {
+		rows, err := queries.ListBankAccountsToEncrypt(ctx, gen.ListBankAccountsToEncryptParams{
+			AfterID:   afterID,
+			KeyPrefix: fields.keyring.ActivePrefix(),
+			RowLimit:  reencryptionBatchSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list bank accounts to encrypt: %w", err)
+		}
+		return rows, nil
+	}
+
+	update := func(ctx context.Context, row gen.ListBankAccountsToEncryptRow) (int64, error) {
+		accountNumber, err := fields.openAccountNumber(row.AccountNumber)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", errUndecryptable, err)
+		}
+		sealed, err := fields.sealAccountNumber(accountNumber)
+		if err != nil {
+			return 0, err
+		}
+
+		updated, err := queries.UpdateBankAccountNumberEncryption(ctx, gen.UpdateBankAccountNumberEncryptionParams{
+			AccountNumber:        sealed,
+			AccountNumberHash:    fields.accountNumberIndex(accountNumber),
+			ID:                   row.ID,
+			CurrentAccountNumber: row.AccountNumber,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("update bank account %s: %w", row.ID, err)
+		}
+		return updated, nil
+	}
+
+	return reencryptColumn(ctx, fieldBankAccountNumber, list, func(row gen.ListBankAccountsToEncryptRow) string { return row.ID }, update)
+}
+
+func reencryptBeneficiaryAccountNumbers(ctx context.Context, queries gen.Querier, fields fieldCipher) (reencryptionResult, error) {
+	list := func(ctx context.Context, afterID string) ([]gen.ListBeneficiariesToEncryptRow, error) {
+		rows, err := queries.ListBeneficiariesToEncrypt(ctx, gen.ListBeneficiariesToEncryptParams{
+			AfterID:   afterID,
+			KeyPrefix: fields.keyring.ActivePrefix(),
+			RowLimit:  reencryptionBatchSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list beneficiaries to encrypt: %w", err)
+		}
+		return rows, nil
+	}
+
+	update := func(ctx context.Context, row gen.ListBeneficiariesToEncryptRow) (int64, error) {
+		accountNumber, err := fields.openBeneficiaryAccountNumber(row.AccountNumber)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", errUndecryptable, err)
+		}
+		sealed, err := fields.sealBeneficiaryAccountNumber(accountNumber)
+		if err != nil {
+			return 0, err
+		}
+
+		updated, err := queries.UpdateBeneficiaryAccountNumberEncryption(ctx, gen.UpdateBeneficiaryAccountNumberEncryptionParams{
+			AccountNumber:        sealed,
+			AccountNumberHash:    fields.beneficiaryAccountIndex(accountNumber),
+			ID:                   row.ID,
+			CurrentAccountNumber: row.AccountNumber,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("update beneficiary %s: %w", row.ID, err)
+		}
+		return updated, nil
+	}
+
+	return reencryptColumn(ctx, fieldBeneficiaryAccount, list, func(row gen.ListBeneficiariesToEncryptRow) string { return row.ID }, update)
+}
+
+func reencryptRecipientDetails(ctx context.Context, queries gen.Querier, fields fieldCipher) (reencryptionResult, error) {
+	list := func(ctx context.Context, afterID string) ([]gen.ListTransactionsToEncryptRow, error) {
+		rows, err := queries.ListTransactionsToEncrypt(ctx, gen.ListTransactionsToEncryptParams{
+			AfterID:   afterID,
+			KeyPrefix: fields.keyring.ActivePrefix(),
+			RowLimit:  reencryptionBatchSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list transactions to encrypt: %w", err)
+		}
+		return rows, nil
+	}
+
+	update := func(ctx context.Context, row gen.ListTransactionsToEncryptRow) (int64, error) {
+		recipientJSON, err := fields.open(fieldRecipientDetails, row.ProviderReference.String)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", errUndecryptable, err)
+		}
+		sealed, err := fields.seal(fieldRecipientDetails, recipientJSON)
+		if err != nil {
+			return 0, err
+		}
+
+		updated, err := queries.UpdateTransactionProviderReferenceEncryption(ctx, gen.UpdateTransactionProviderReferenceEncryptionParams{
+			ProviderReference:        sql.NullString{String: sealed, Valid: true},
+			ID:                       row.ID,
+			CurrentProviderReference: row.ProviderReference,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("update transaction %s: %w", row.ID, err)
+		}
+		return updated, nil
+	}
+
+	return reencryptColumn(ctx, fieldRecipientDetails, list, func(row gen.ListTransactionsToEncryptRow) string { return row.ID }, update)
+}
+
+func reencryptRecipientAccountNumbers(ctx context.Context, queries gen.Querier, fields fieldCipher) (reencryptionResult, error) {
+	list := func(ctx context.Context, afterID string) ([]gen.ListTransferRecipientsToEncryptRow, error) {
+		rows, err := queries.ListTransferRecipientsToEncrypt(ctx, gen.ListTransferRecipientsToEncryptParams{
+			AfterID:   afterID,
+			KeyPrefix: fields.keyring.ActivePrefix(),
+			RowLimit:  reencryptionBatchSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list transfer recipients to encrypt: %w", err)
+		}
+		return rows, nil
+	}
+
+	update := func(ctx context.Context, row gen.ListTransferRecipientsToEncryptRow) (int64, error) {
+		accountNumber, err := fields.openRecipientAccountNumber(row.AccountNumber)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", errUndecryptable, err)
+		}
+		sealed, err := fields.sealRecipientAccountNumber(accountNumber)
+		if err != nil {
+			return 0, err
+		}
+
+		updated, err := queries.UpdateTransferRecipientAccountNumberEncryption(ctx, gen.UpdateTransferRecipientAccountNumberEncryptionParams{
+			AccountNumber:        sealed,
+			ID:                   row.ID,
+			CurrentAccountNumber: row.AccountNumber,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("update transfer recipient %s: %w", row.ID, err)
+		}
+		return updated, nil
+	}
+
+	return reencryptColumn(ctx, fieldRecipientAccountNumber, list, func(row gen.ListTransferRecipientsToEncryptRow) string { return row.ID }, update)
+}
+
+func reencryptWebhookPayloads(ctx context.Context, queries gen.Querier, fields fieldCipher) (reencryptionResult, error) {
+	list := func(ctx context.Context, afterID string) ([]gen.ListWebhookEventsToEncryptRow, error) {
+		rows, err := queries.ListWebhookEventsToEncrypt(ctx, gen.ListWebhookEventsToEncryptParams{
+			AfterID:   afterID,
+			KeyPrefix: fields.keyring.ActivePrefix(),
+			RowLimit:  reencryptionBatchSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list webhook events to encrypt: %w", err)
+		}
+		return rows, nil
+	}
+
+	update := func(ctx context.Context, row gen.ListWebhookEventsToEncryptRow) (int64, error) {
+		payload, err := fields.openWebhookPayload(row.Payload)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", errUndecryptable, err)
+		}
+		sealed, err := fields.sealWebhookPayload(payload)
+		if err != nil {
+			return 0, err
+		}
+
+		updated, err := queries.UpdateWebhookEventPayloadEncryption(ctx, gen.UpdateWebhookEventPayloadEncryptionParams{
+			Payload:        sealed,
+			ID:             row.ID,
+			CurrentPayload: row.Payload,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("update webhook event %s: %w", row.ID, err)
+		}
+		return updated, nil
+	}
+
+	return reencryptColumn(ctx, fieldWebhookPayload, list, func(row gen.ListWebhookEventsToEncryptRow) string { return row.ID }, update)
+}
+
+func reencryptKYCDocumentNumbers(ctx context.Context, queries gen.Querier, fields fieldCipher) (reencryptionResult, error) {
+	list := func(ctx context.Context, afterID string) ([]gen.ListKYCDocumentsToEncryptRow, error) {
+		rows, err := queries.ListKYCDocumentsToEncrypt(ctx, gen.ListKYCDocumentsToEncryptParams{
+			AfterID:   afterID,
+			KeyPrefix: fields.keyring.ActivePrefix(),
+			RowLimit:  reencryptionBatchSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list kyc documents to encrypt: %w", err)
+		}
+		return rows, nil
+	}
+
+	update := func(ctx context.Context, row gen.ListKYCDocumentsToEncryptRow) (int64, error) {
+		documentNumber, err := fields.openKYCDocumentNumber(row.DocumentNumber)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", errUndecryptable, err)
+		}
+		sealed, err := fields.sealKYCDocumentNumber(documentNumber)
+		if err != nil {
+			return 0, err
+		}
+
+		updated, err := queries.UpdateKYCDocumentNumberEncryption(ctx, gen.UpdateKYCDocumentNumberEncryptionParams{
+			DocumentNumber:        sealed,
+			ID:                    row.ID,
+			CurrentDocumentNumber: row.DocumentNumber,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("update kyc document %s: %w", row.ID, err)
+		}
+		return updated, nil
+	}
+
+	return reencryptColumn(ctx, fieldKYCDocumentNumber, list, func(row gen.ListKYCDocumentsToEncryptRow) string { return row.ID }, update)
+}
+
+// indexRiskRecipients replaces account numbers stored in risk assessment
+// recipients before they were keyed on the blind index.
+func indexRiskRecipients(ctx context.Context, queries gen.Querier, fields fieldCipher) (reencryptionResult, error) {
+	list := func(ctx context.Context, afterID string) ([]gen.ListRiskRecipientsToIndexRow, error) {
+		rows, err := queries.ListRiskRecipientsToIndex(ctx, gen.ListRiskRecipientsToIndexParams{
+			AfterID:  afterID,
+			RowLimit: reencryptionBatchSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list risk recipients to index: %w", err)
+		}
+		return rows, nil
+	}
+
+	update := func(ctx context.Context, row gen.ListRiskRecipientsToIndexRow) (int64, error) {
+		bankCode, accountNumber, found := strings.Cut(strings.TrimPrefix(row.Recipient, "account:"), ":")
+		if !found || accountNumber == "" {
+			return 0, fmt.Errorf("%w: malformed account recipient", errUndecryptable)
+		}
+
+		updated, err := queries.UpdateRiskAssessmentRecipient(ctx, gen.UpdateRiskAssessmentRecipientParams{
+			Recipient:        fields.accountRecipient(bankCode, accountNumber),
+			ID:               row.ID,
+			CurrentRecipient: row.Recipient,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("update risk assessment %s: %w", row.ID, err)
+		}
+		return updated, nil
+	}
+
+	return reencryptColumn(ctx, fieldRiskRecipientAccount, list, func(row gen.ListRiskRecipientsToIndexRow) string { return row.ID }, update)
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReencryptBankAccountNumbers(t *testing.T) {
+	old := testFieldCipher(t, "k1")
+	fields := testFieldCipher(t, "k2")
+
+	sealedOld, err := old.sealAccountNumber("1111111111")
+	require.NoError(t, err)
+
+	mockQueries := new(mocks.MockQuerier)
+	mockQueries.On("ListBankAccountsToEncrypt", mock.Anything, gen.ListBankAccountsToEncryptParams{
+		AfterID:   "",
+		KeyPrefix: "enc:v1:k2:",
+		RowLimit:  reencryptionBatchSize,
+	}).Return([]gen.ListBankAccountsToEncryptRow{
+		{ID: "ba_1", AccountNumber: "0123456789"},
+		{ID: "ba_2", AccountNumber: sealedOld},
+	}, nil)
+
+	expectUpdate := func(id, current, accountNumber string) {
+		mockQueries.On("UpdateBankAccountNumberEncryption", mock.Anything, mock.MatchedBy(func(arg gen.UpdateBankAccountNumberEncryptionParams) bool {
+			if arg.ID != id || arg.CurrentAccountNumber != current || arg.AccountNumberHash != fields.accountNumberIndex(accountNumber) {
+				return false
+			}
+			opened, err := fields.openAccountNumber(arg.AccountNumber)
+			return err == nil && opened == accountNumber && strings.HasPrefix(arg.AccountNumber, "enc:v1:k2:")
+		})).Return(int64(1), nil)
+	}
+	expectUpdate("ba_1", "0123456789", "0123456789")
+	expectUpdate("ba_2", sealedOld, "1111111111")
+
+	result, err := reencryptBankAccountNumbers(context.Background(), mockQueries, fields)
+
+	require.NoError(t, err)
+	assert.Equal(t, reencryptionResult{Reencrypted: 2}, result)
+	mockQueries.AssertExpectations(t)
+}
+
+func TestReencryptBeneficiaryAccountNumbers(t *testing.T) {
+	fields := testFieldCipher(t, "k2")
+
+	mockQueries := new(mocks.MockQuerier)
+	mockQueries.On("ListBeneficiariesToEncrypt", mock.Anything, gen.ListBeneficiariesToEncryptParams{
+		AfterID:   "",
+		KeyPrefix: "enc:v1:k2:",
+		RowLimit:  reencryptionBatchSize,
+	}).Return([]gen.ListBeneficiariesToEncryptRow{
+		{ID: "ben_1", AccountNumber: "0123456789"},
+	}, nil)
+	mockQueries.On("UpdateBeneficiaryAccountNumberEncryption", mock.Anything, mock.MatchedBy(func(arg gen.UpdateBeneficiaryAccountNumberEncryptionParams) bool {
+		if arg.ID != "ben_1" || arg.CurrentAccountNumber != "0123456789" || arg.AccountNumberHash != fields.beneficiaryAccountIndex("0123456789") {
+			return false
+		}
+		opened, err := fields.openBeneficiaryAccountNumber(arg.AccountNumber)
+		return err == nil && opened == "0123456789"
+	})).Return(int64(1), nil)
+
+	result, err := reencryptBeneficiaryAccountNumbers(context.Background(), mockQueries, fields)
+
+	require.NoError(t, err)
+	assert.Equal(t, reencryptionResult{Reencrypted: 1}, result)
+	mockQueries.AssertExpectations(t)
+}
+
+func TestReencryptRecipientDetails(t *testing.T) {
+	fields := testFieldCipher(t, "k2")
+
+	t.Run("pages past rows it cannot decrypt", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+
+		firstPage := make([]gen.ListTransactionsToEncryptRow, 0, reencryptionBatchSize)
+		for i := int32(0); i < reencryptionBatchSize; i++ {
+			firstPage = append(firstPage, gen.ListTransactionsToEncryptRow{
+				ID:                "tx_a",
+				ProviderReference: sql.NullString{String: "enc:v1:k9:AAAA:AAAA", Valid: true},
+			})
+		}
+		mockQueries.On("ListTransactionsToEncrypt", mock.Anything, mock.MatchedBy(func(arg gen.ListTransactionsToEncryptParams) bool {
+			return arg.AfterID == ""
+		})).Return(firstPage, nil)
+
+		plaintext := sql.NullString{String: `{"account_number":"0123456789","bank_code":"044"}`, Valid: true}
+		mockQueries.On("ListTransactionsToEncrypt", mock.Anything, mock.MatchedBy(func(arg gen.ListTransactionsToEncryptParams) bool {
+			return arg.AfterID == "tx_a"
+		})).Return([]gen.ListTransactionsToEncryptRow{{ID: "tx_b", ProviderReference: plaintext}}, nil)
+
+		mockQueries.On("UpdateTransactionProviderReferenceEncryption", mock.Anything, mock.MatchedBy(func(arg gen.UpdateTransactionProviderReferenceEncryptionParams) bool {
+			return arg.ID == "tx_b" && arg.CurrentProviderReference == plaintext && strings.HasPrefix(arg.ProviderReference.String, "enc:v1:k2:")
+		})).Return(int64(0), nil)
+
+		result, err := reencryptRecipientDetails(context.Background(), mockQueries, fields)
+
+		require.NoError(t, err)
+		assert.Equal(t, reencryptionResult{Reencrypted: 0, Failed: int(reencryptionBatchSize)}, result)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("ListTransactionsToEncrypt", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+
+		_, err := reencryptRecipientDetails(context.Background(), mockQueries, fields)
+		assert.Error(t, err)
+	})
+}
+
+func TestReencryptRecipientAccountNumbers(t *testing.T) {
+	fields := testFieldCipher(t, "k2")
+
+	mockQueries := new(mocks.MockQuerier)
+	mockQueries.On("ListTransferRecipientsToEncrypt", mock.Anything, gen.ListTransferRecipientsToEncryptParams{
+		AfterID:   "",
+		KeyPrefix: "enc:v1:k2:",
+		RowLimit:  reencryptionBatchSize,
+	}).Return([]gen.ListTransferRecipientsToEncryptRow{{ID: "tr_1", AccountNumber: "9999999999"}}, nil)
+	mockQueries.On("UpdateTransferRecipientAccountNumberEncryption", mock.Anything, mock.MatchedBy(func(arg gen.UpdateTransferRecipientAccountNumberEncryptionParams) bool {
+		opened, err := fields.openRecipientAccountNumber(arg.AccountNumber)
+		return arg.ID == "tr_1" && arg.CurrentAccountNumber == "9999999999" && err == nil && opened == "9999999999"
+	})).Return(int64(1), nil)
+
+	result, err := reencryptRecipientAccountNumbers(context.Background(), mockQueries, fields)
+
+	require.NoError(t, err)
+	assert.Equal(t, reencryptionResult{Reencrypted: 1}, result)
+	mockQueries.AssertExpectations(t)
+}
+
+func TestReencryptWebhookPayloads(t *testing.T) {
+	fields := testFieldCipher(t, "k1")
+	payload := json.RawMessage(`{"event":"payout.completed"}`)
+
+	mockQueries := new(mocks.MockQuerier)
+	mockQueries.On("ListWebhookEventsToEncrypt", mock.Anything, mock.Anything).Return([]gen.ListWebhookEventsToEncryptRow{
+		{ID: "wh_1", Payload: payload},
+	}, nil)
+	mockQueries.On("UpdateWebhookEventPayloadEncryption", mock.Anything, mock.MatchedBy(func(arg gen.UpdateWebhookEventPayloadEncryptionParams) bool {
+		opened, err := fields.openWebhookPayload(arg.Payload)
+		return arg.ID == "wh_1" && string(arg.CurrentPayload) == string(payload) && err == nil && string(opened) == string(payload)
+	})).Return(int64(1), nil)
+
+	result, err := reencryptWebhookPayloads(context.Background(), mockQueries, fields)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Reencrypted)
+	mockQueries.AssertExpectations(t)
+}
+
+func TestReencryptKYCDocumentNumbers(t *testing.T) {
+	fields := testFieldCipher(t, "k1")
+
+	mockQueries := new(mocks.MockQuerier)
+	mockQueries.On("ListKYCDocumentsToEncrypt", mock.Anything, gen.ListKYCDocumentsToEncryptParams{
+		AfterID:   "",
+		KeyPrefix: "enc:v1:k1:",
+		RowLimit:  reencryptionBatchSize,
+	}).Return([]gen.ListKYCDocumentsToEncryptRow{{ID: "kyc_1", DocumentNumber: "A12345678"}}, nil)
+	mockQueries.On("UpdateKYCDocumentNumberEncryption", mock.Anything, mock.MatchedBy(func(arg gen.UpdateKYCDocumentNumberEncryptionParams) bool {
+		opened, err := fields.openKYCDocumentNumber(arg.DocumentNumber)
+		return arg.ID == "kyc_1" && arg.CurrentDocumentNumber == "A12345678" && err == nil && opened == "A12345678"
+	})).Return(int64(1), nil)
+
+	result, err := reencryptKYCDocumentNumbers(context.Background(), mockQueries, fields)
+
+	require.NoError(t, err)
+	assert.Equal(t, reencryptionResult{Reencrypted: 1}, result)
+	mockQueries.AssertExpectations(t)
+}
+
+func TestIndexRiskRecipients(t *testing.T) {
+	fields := testFieldCipher(t, "k1")
+	indexed := fields.accountRecipient("044", "9999999999")
+
+	mockQueries := new(mocks.MockQuerier)
+	mockQueries.On("ListRiskRecipientsToIndex", mock.Anything, gen.ListRiskRecipientsToIndexParams{
+		AfterID:  "",
+		RowLimit: reencryptionBatchSize,
+	}).Return([]gen.ListRiskRecipientsToIndexRow{
+		{ID: "ra_1", Recipient: "account:044:9999999999"},
+		{ID: "ra_2", Recipient: "account:broken"},
+	}, nil)
+	mockQueries.On("UpdateRiskAssessmentRecipient", mock.Anything, gen.UpdateRiskAssessmentRecipientParams{
+		Recipient:        indexed,
+		ID:               "ra_1",
+		CurrentRecipient: "account:044:9999999999",
+	}).Return(int64(1), nil)
+
+	result, err := indexRiskRecipients(context.Background(), mockQueries, fields)
+
+	require.NoError(t, err)
+	assert.Equal(t, reencryptionResult{Reencrypted: 1, Failed: 1}, result)
+	assert.NotContains(t, indexed, "9999999999")
+	mockQueries.AssertExpectations(t)
+}
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/lib/pq"
+)
+
+type RefundService interface {
+	CreateRefund(ctx context.Context, transactionID string, adminUserID string, amount money.Money, reason string, idempotencyKey string) (*models.Refund, error)
+	ListRefunds(ctx context.Context, transactionID string) ([]*models.Refund, error)
+}
+
+type refundService struct {
+	queries gen.Querier
+	db      *sql.DB
+	wallet  WalletService
+	ledger  LedgerService
+}
+
+func newRefundService(queries gen.Querier, db *sql.DB, wallet WalletService, ledger LedgerService) RefundService {
+	return &refundService{
+		queries: queries,
+		db:      db,
+		wallet:  wallet,
+		ledger:  ledger,
+	}
+}
+
+// CreateRefund returns part or all of a completed transfer to the sender. The
+// amount is given in the original transaction currency and the sender is
+// credited at the original exchange rate. Fees are not refunded.
+func (rs *refundService) CreateRefund(ctx context.Context, transactionID string, adminUserID string, amount money.Money, reason string, idempotencyKey string) (*models.Refund, error) {
+	if !amount.IsPositive() {
+		return nil, utils.BadRequestErr("refund amount must be positive")
+	}
+
+	existing, err := rs.queries.GetRefundByIdempotencyKey(ctx, idempotencyKey)
+	if err == nil {
+		if existing.TransactionID != transactionID {
+			return nil, utils.DuplicateKeyErr("idempotency key already used for another transaction")
+		}
+		return mapRefund(existing), nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, utils.ServerErr(fmt.Errorf("check idempotency: %w", err))
+	}
+
+	tx, err := rs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := rs.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = rs.queries
+	}
+
+	// Locking the original serialises concurrent refunds so the running total
+	// read below cannot go stale.
+	original, err := queries.GetTransactionByIDForUpdate(ctx, transactionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.NotFoundErr("transaction not found")
+		}
+		return nil, utils.ServerErr(fmt.Errorf("get transaction: %w", err))
+	}
+
+	if err := validateRefund(original, amount); err != nil {
+		return nil, err
+	}
+
+	exchangeRate, err := strconv.ParseFloat(original.ExchangeRate.String, 64)
+	if err != nil || exchangeRate <= 0 {
+		return nil, utils.ServerErr(fmt.Errorf("invalid exchange rate: %s", original.ExchangeRate.String))
+	}
+
+	totals, err := queries.GetRefundTotals(ctx, original.ID)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("get refund totals: %w", err))
+	}
+
+	remaining := original.Amount - totals.RefundedAmount
+	if amount.Amount > remaining {
+		return nil, utils.BadRequestErr(fmt.Sprintf("refund exceeds remaining refundable amount of %.2f %s", money.ToMajorUnits(remaining), original.Currency))
+	}
+
+	fullyRefunded := amount.Amount == remaining
+	internal := original.Type == string(models.TransactionTypeInternal)
+
+	lockedSenderWallet, err := rs.wallet.LockWalletForUpdate(ctx, tx, original.FromWalletID.String)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWalletCredit(lockedSenderWallet, "sender"); err != nil {
+		return nil, err
+	}
+
+	var lockedRecipientWallet *models.Wallet
+	if internal {
+		if !original.ToWalletID.Valid {
+			return nil, utils.ServerErr(fmt.Errorf("to wallet ID not found in transaction"))
+		}
+		lockedRecipientWallet, err = rs.wallet.LockWalletForUpdate(ctx, tx, original.ToWalletID.String)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkWalletDebit(lockedRecipientWallet, "recipient"); err != nil {
+			return nil, err
+		}
+		if lockedRecipientWallet.AvailableBalance() < amount.Amount {
+			return nil, utils.BadRequestErr("recipient has insufficient balance for refund")
+		}
+	}
+
+	senderCurrency, err := money.ParseCurrency(lockedSenderWallet.Currency)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("parse from currency: %w", err))
+	}
+
+	creditedAmount := refundCreditAmount(original.Amount, totals.CreditedAmount, amount.Amount, fullyRefunded, exchangeRate)
+	if creditedAmount <= 0 {
+		return nil, utils.BadRequestErr("refund amount is too small to credit the sender")
+	}
+	credited := money.NewMoney(creditedAmount, senderCurrency)
+
+	var fromWalletID sql.NullString
+	if internal {
+		fromWalletID = sql.NullString{String: lockedRecipientWallet.ID, Valid: true}
+	}
+
+	reverseRateStr := strconv.FormatFloat(1/exchangeRate, 'f', 8, 64)
+	traceID := utils.TraceIDFromContext(ctx)
+	refundTransaction, err := queries.CreateTransaction(ctx, gen.CreateTransactionParams{
+		IdempotencyKey:      idempotencyKey,
+		TraceID:             sql.NullString{String: traceID, Valid: traceID != ""},
+		FromWalletID:        fromWalletID,
+		ToWalletID:          sql.NullString{String: lockedSenderWallet.ID, Valid: true},
+		Type:                string(models.TransactionTypeRefund),
+		Amount:              credited.Amount,
+		Currency:            credited.Currency.String(),
+		Status:              string(models.TransactionStatusCompleted),
+		ExchangeRate:        sql.NullString{String: reverseRateStr, Valid: true},
+		ParentTransactionID: sql.NullString{String: original.ID, Valid: true},
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, utils.DuplicateKeyErr("transaction with this idempotency key already exists")
+		}
+		return nil, utils.ServerErr(fmt.Errorf("create refund transaction: %w", err))
+	}
+
+	if err := recordTransactionCreated(ctx, queries, refundTransaction); err != nil {
+		return nil, err
+	}
+
+	if internal {
+		if err := rs.ledger.CreateDebitEntry(ctx, tx, lockedRecipientWallet.ID, refundTransaction.ID, -amount.Amount, amount.Currency); err != nil {
+			return nil, err
+		}
+
+		newRecipientBalance, err := money.NewMoney(lockedRecipientWallet.Balance, amount.Currency).Subtract(amount)
+		if err != nil {
+			return nil, utils.ServerErr(fmt.Errorf("calculate new recipient balance: %w", err))
+		}
+
+		if err := queries.UpdateWalletBalance(ctx, gen.UpdateWalletBalanceParams{
+			Balance: newRecipientBalance.Amount,
+			ID:      lockedRecipientWallet.ID,
+		}); err != nil {
+			return nil, utils.ServerErr(fmt.Errorf("update recipient wallet balance: %w", err))
+		}
+	} else {
+		if err := rs.ledger.CreateExternalSystemDebitEntry(ctx, tx, refundTransaction.ID, -amount.Amount, amount.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := rs.ledger.CreateCreditEntry(ctx, tx, lockedSenderWallet.ID, refundTransaction.ID, credited.Amount, credited.Currency); err != nil {
+		return nil, err
+	}
+
+	newSenderBalance, err := money.NewMoney(lockedSenderWallet.Balance, senderCurrency).Add(credited)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("calculate new sender balance: %w", err))
+	}
+
+	if err := queries.UpdateWalletBalance(ctx, gen.UpdateWalletBalanceParams{
+		Balance: newSenderBalance.Amount,
+		ID:      lockedSenderWallet.ID,
+	}); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("update sender wallet balance: %w", err))
+	}
+
+	if fullyRefunded {
+		if err := transitionTransactionStatus(ctx, queries, original.ID, models.TransactionStatusCompleted, models.TransactionStatusRefunded, reason); err != nil {
+			return nil, err
+		}
+	}
+
+	refund, err := queries.CreateRefund(ctx, gen.CreateRefundParams{
+		TransactionID:       original.ID,
+		RefundTransactionID: refundTransaction.ID,
+		Amount:              amount.Amount,
+		Currency:            amount.Currency.String(),
+		CreditedAmount:      credited.Amount,
+		CreditedCurrency:    credited.Currency.String(),
+		Reason:              reason,
+		IdempotencyKey:      idempotencyKey,
+		CreatedBy:           adminUserID,
+	})
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("create refund: %w", err))
+	}
+
+	if err := recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "refund.created",
+		EntityType: auditEntityRefund,
+		EntityID:   refund.ID,
+		After: map[string]any{
+			"transaction_id":        original.ID,
+			"refund_transaction_id": refundTransaction.ID,
+			"amount":                amount.Amount,
+			"currency":              amount.Currency.String(),
+			"credited_amount":       credited.Amount,
+			"credited_currency":     credited.Currency.String(),
+			"reason":                reason,
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	_, err = queries.CreateIdempotencyKey(ctx, gen.CreateIdempotencyKeyParams{
+		Key:           idempotencyKey,
+		TransactionID: refundTransaction.ID,
+	})
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("create idempotency key: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	utils.Logger.Info().
+		Str("transaction_id", original.ID).
+		Str("refund_id", refund.ID).
+		Str("admin_user_id", adminUserID).
+		Int64("amount", amount.Amount).
+		Str("currency", amount.Currency.String()).
+		Bool("fully_refunded", fullyRefunded).
+		Str("trace_id", traceID).
+		Msg("transaction refunded")
+
+	return mapRefund(refund), nil
+}
+
+func validateRefund(original gen.Transaction, amount money.Money) error {
+	if original.Type != string(models.TransactionTypeInternal) && original.Type != string(models.TransactionTypeExternal) {
+		return utils.BadRequestErr("only transfers can be refunded")
+	}
+
+	if original.Status != string(models.TransactionStatusCompleted) {
+		return utils.BadRequestErr("only completed transactions can be refunded")
+	}
+
+	if amount.Currency.String() != original.Currency {
+		return utils.BadRequestErr("refund currency must match transaction currency")
+	}
+
+	if !original.FromWalletID.Valid {
+		return utils.BadRequestErr("transaction has no sender wallet to refund")
+	}
+
+	if !original.ExchangeRate.Valid {
+		return utils.ServerErr(fmt.Errorf("exchange rate not found in transaction"))
+	}
+
+	return nil
+}
+
+// refundCreditAmount converts a refund back into the sender's currency. The
+// principal is recomputed the same way confirmation computed the debit, and
+// the final refund credits exactly what is left of it so rounding on partial
+// refunds never leaks.
+func refundCreditAmount(originalAmount int64, creditedSoFar int64, refundAmount int64, fullyRefunded bool, exchangeRate float64) int64 {
+	principal := int64(float64(originalAmount) / exchangeRate)
+	remaining := principal - creditedSoFar
+
+	credited := int64(float64(refundAmount) / exchangeRate)
+	if fullyRefunded || credited > remaining {
+		credited = remaining
+	}
+	return credited
+}
+
+func (rs *refundService) ListRefunds(ctx context.Context, transactionID string) ([]*models.Refund, error) {
+	refunds, err := rs.queries.ListRefundsByTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("list refunds: %w", err))
+	}
+
+	result := make([]*models.Refund, 0, len(refunds))
+	for _, r := range refunds {
+		result = append(result, mapRefund(r))
+	}
+
+	return result, nil
+}
+
+func mapRefund(r gen.Refund) *models.Refund {
+	return &models.Refund{
+		ID:                  r.ID,
+		TransactionID:       r.TransactionID,
+		RefundTransactionID: r.RefundTransactionID,
+		Amount:              r.Amount,
+		Currency:            r.Currency,
+		CreditedAmount:      r.CreditedAmount,
+		CreditedCurrency:    r.CreditedCurrency,
+		Reason:              r.Reason,
+		IdempotencyKey:      r.IdempotencyKey,
+		CreatedBy:           r.CreatedBy,
+		CreatedAt:           r.CreatedAt,
+	}
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRefund(t *testing.T) {
+	completed := gen.Transaction{
+		ID:           "tx_1",
+		FromWalletID: sql.NullString{String: "wallet_1", Valid: true},
+		ToWalletID:   sql.NullString{String: "wallet_2", Valid: true},
+		Type:         string(models.TransactionTypeInternal),
+		Amount:       10000,
+		Currency:     "EUR",
+		Status:       string(models.TransactionStatusCompleted),
+		ExchangeRate: sql.NullString{String: "0.85000000", Valid: true},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, validateRefund(completed, money.NewMoney(2500, money.EUR)))
+	})
+
+	tests := []struct {
+		name     string
+		modify   func(tx *gen.Transaction)
+		amount   money.Money
+		expected string
+	}{
+		{
+			name:     "not completed",
+			modify:   func(tx *gen.Transaction) { tx.Status = string(models.TransactionStatusPending) },
+			amount:   money.NewMoney(2500, money.EUR),
+			expected: "only completed transactions can be refunded",
+		},
+		{
+			name:     "already reversed",
+			modify:   func(tx *gen.Transaction) { tx.Status = string(models.TransactionStatusReversed) },
+			amount:   money.NewMoney(2500, money.EUR),
+			expected: "only completed transactions can be refunded",
+		},
+		{
+			name:     "refund of a reversal",
+			modify:   func(tx *gen.Transaction) { tx.Type = string(models.TransactionTypeReversal) },
+			amount:   money.NewMoney(2500, money.EUR),
+			expected: "only transfers can be refunded",
+		},
+		{
+			name:     "currency mismatch",
+			modify:   func(tx *gen.Transaction) {},
+			amount:   money.NewMoney(2500, money.USD),
+			expected: "refund currency must match transaction currency",
+		},
+		{
+			name:     "missing sender wallet",
+			modify:   func(tx *gen.Transaction) { tx.FromWalletID = sql.NullString{} },
+			amount:   money.NewMoney(2500, money.EUR),
+			expected: "no sender wallet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction := completed
+			tt.modify(&transaction)
+
+			err := validateRefund(transaction, tt.amount)
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestRefundCreditAmount(t *testing.T) {
+	// 100.00 EUR bought with 117.64 USD at 0.85.
+	const rate = 0.85
+
+	t.Run("partial refund converts at original rate", func(t *testing.T) {
+		assert.Equal(t, int64(2941), refundCreditAmount(10000, 0, 2500, false, rate))
+	})
+
+	t.Run("final refund credits remaining principal", func(t *testing.T) {
+		credited := refundCreditAmount(10000, 0, 3333, false, rate)
+		credited += refundCreditAmount(10000, credited, 3333, false, rate)
+		credited += refundCreditAmount(10000, credited, 3334, true, rate)
+
+		assert.Equal(t, int64(11764), credited)
+	})
+
+	t.Run("never credits more than the remaining principal", func(t *testing.T) {
+		assert.Equal(t, int64(100), refundCreditAmount(10000, 11664, 9999, false, rate))
+	})
+}
+
+func TestRefundService_CreateRefund(t *testing.T) {
+	t.Run("rejects non-positive amount", func(t *testing.T) {
+		rs := &refundService{queries: new(mocks.MockQuerier)}
+
+		_, err := rs.CreateRefund(context.Background(), "tx_1", "admin_1", money.NewMoney(0, money.EUR), "dispute", "refund_key")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "refund amount must be positive")
+	})
+
+	t.Run("idempotent replay returns existing refund", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		rs := &refundService{queries: mockQueries}
+
+		mockQueries.On("GetRefundByIdempotencyKey", mock.Anything, "refund_key").Return(gen.Refund{
+			ID:            "refund_1",
+			TransactionID: "tx_1",
+			Amount:        2500,
+			Currency:      "EUR",
+		}, nil)
+
+		refund, err := rs.CreateRefund(context.Background(), "tx_1", "admin_1", money.NewMoney(2500, money.EUR), "dispute", "refund_key")
+
+		require.NoError(t, err)
+		assert.Equal(t, "refund_1", refund.ID)
+	})
+
+	t.Run("idempotency key reused for another transaction", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		rs := &refundService{queries: mockQueries}
+
+		mockQueries.On("GetRefundByIdempotencyKey", mock.Anything, "refund_key").Return(gen.Refund{ID: "refund_1", TransactionID: "tx_other"}, nil)
+
+		_, err := rs.CreateRefund(context.Background(), "tx_1", "admin_1", money.NewMoney(2500, money.EUR), "dispute", "refund_key")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "idempotency key already used")
+	})
+}
+
+func TestRefundService_ListRefunds(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		rs := &refundService{queries: mockQueries}
+
+		mockQueries.On("ListRefundsByTransaction", mock.Anything, "tx_1").Return([]gen.Refund{
+			{ID: "refund_1", TransactionID: "tx_1", Amount: 2500, Currency: "EUR", CreditedAmount: 2941, CreditedCurrency: "USD"},
+		}, nil)
+
+		refunds, err := rs.ListRefunds(context.Background(), "tx_1")
+
+		require.NoError(t, err)
+		require.Len(t, refunds, 1)
+		assert.Equal(t, int64(2941), refunds[0].CreditedAmount)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		rs := &refundService{queries: mockQueries}
+
+		mockQueries.On("ListRefundsByTransaction", mock.Anything, "tx_1").Return(nil, errors.New("db error"))
+
+		_, err := rs.ListRefunds(context.Background(), "tx_1")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "list refunds")
+	})
+}
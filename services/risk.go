@@ -0,0 +1,361 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+)
+
+const (
+	riskRuleVelocity                  = "velocity"
+	riskRuleNewBeneficiaryLargeAmount = "new_beneficiary_large_amount"
+	riskRuleUnusualCorridor           = "unusual_corridor"
+	riskRuleNearLimit                 = "near_limit"
+	riskRuleNewBeneficiaryCooldown    = "new_beneficiary_cooldown"
+
+	riskScoreVelocity                  = 50
+	riskScoreNewBeneficiaryLargeAmount = 50
+	riskScoreUnusualCorridor           = 30
+	riskScoreNearLimit                 = 30
+	riskScoreNewBeneficiaryCooldown    = 30
+)
+
+// RiskPolicy configures screening at confirmation. Every rule that fires adds
+// its score, and the total is compared with ReviewScore and DenyScore.
+// LargeAmount is in major units of the source currency. BeneficiaryCooldown is
+// how long a saved beneficiary counts as new after it was last verified. A
+// zero threshold turns off the rule or decision it controls.
+type RiskPolicy struct {
+	Enabled             bool
+	ReviewScore         int
+	DenyScore           int
+	VelocityWindow      time.Duration
+	VelocityMaxCount    int
+	LargeAmount         float64
+	NearLimitRatio      float64
+	BeneficiaryCooldown time.Duration
+}
+
+type RiskService interface {
+	Screen(ctx context.Context, transaction gen.Transaction, fromWallet *models.Wallet) (*models.RiskAssessment, error)
+	ListPendingReviews(ctx context.Context, limit int32) ([]*models.RiskAssessment, error)
+}
+
+type riskService struct {
+	queries gen.Querier
+	limits  LimitService
+	policy  RiskPolicy
+	fields  fieldCipher
+}
+
+func newRiskService(queries gen.Querier, limits LimitService, policy RiskPolicy, fields fieldCipher) RiskService {
+	return &riskService{
+		queries: queries,
+		limits:  limits,
+		policy:  policy,
+		fields:  fields,
+	}
+}
+
+// riskSignals are the facts the rules are evaluated against. They are stored
+// with every assessment, so fields should only ever be added.
+type riskSignals struct {
+	TransactionType     string `json:"transaction_type"`
+	SourceAmount        int64  `json:"source_amount"`
+	SourceCurrency      string `json:"source_currency"`
+	DestinationCurrency string `json:"destination_currency"`
+	RecentTransfers     int64  `json:"recent_transfers"`
+	PriorTransfers      int64  `json:"prior_transfers"`
+	CorridorTransfers   int64  `json:"corridor_transfers"`
+	RecipientTransfers  int64  `json:"recipient_transfers"`
+	PerTransactionMax   *int64 `json:"per_transaction_max"`
+	DailyRemaining      *int64 `json:"daily_remaining"`
+	// BeneficiaryAgeSeconds is how long ago the saved beneficiary the
+	// transfer pays was verified, or nil when it names no beneficiary.
+	BeneficiaryAgeSeconds *int64 `json:"beneficiary_age_seconds"`
+}
+
+// Screen scores an initiated transaction and stores the assessment. It returns
+// nil when screening is disabled, which callers treat as allow.
+func (rs *riskService) Screen(ctx context.Context, transaction gen.Transaction, fromWallet *models.Wallet) (*models.RiskAssessment, error) {
+	if !rs.policy.Enabled {
+		return nil, nil
+	}
+
+	recipient, err := riskRecipient(ctx, rs.queries, rs.fields, transaction)
+	if err != nil {
+		return nil, utils.ServerErr(err)
+	}
+
+	signals, err := rs.signals(ctx, transaction, fromWallet, recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	decision, score, reasons := evaluateRisk(rs.policy, signals)
+
+	reasonsJSON, err := json.Marshal(reasons)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("marshal risk reasons: %w", err))
+	}
+	featuresJSON, err := json.Marshal(signals)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("marshal risk features: %w", err))
+	}
+
+	row, err := rs.queries.CreateRiskAssessment(ctx, gen.CreateRiskAssessmentParams{
+		TransactionID: transaction.ID,
+		UserID:        fromWallet.UserID,
+		Recipient:     recipient,
+		Decision:      string(decision),
+		Score:         int32(score),
+		Reasons:       reasonsJSON,
+		Features:      featuresJSON,
+	})
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("create risk assessment: %w", err))
+	}
+
+	if decision != models.RiskDecisionAllow {
+		utils.Logger.Warn().
+			Str("event", "risk_screening_flagged").
+			Str("transaction_id", transaction.ID).
+			Str("user_id", fromWallet.UserID).
+			Str("decision", string(decision)).
+			Int("score", score).
+			Str("trace_id", utils.TraceIDFromContext(ctx)).
+			Msg("transaction flagged by risk screening")
+	}
+
+	return mapRiskAssessment(row)
+}
+
+// ListPendingReviews returns on-hold transactions awaiting an analyst, oldest
+// first.
+func (rs *riskService) ListPendingReviews(ctx context.Context, limit int32) ([]*models.RiskAssessment, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	rows, err := rs.queries.ListPendingRiskReviews(ctx, limit)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("list pending risk reviews: %w", err))
+	}
+
+	assessments := make([]*models.RiskAssessment, 0, len(rows))
+	for _, row := range rows {
+		assessment, err := mapRiskAssessment(row)
+		if err != nil {
+			return nil, err
+		}
+		assessments = append(assessments, assessment)
+	}
+	return assessments, nil
+}
+
+func (rs *riskService) signals(ctx context.Context, transaction gen.Transaction, fromWallet *models.Wallet, recipient string) (riskSignals, error) {
+	fromCurrency, err := money.ParseCurrency(fromWallet.Currency)
+	if err != nil {
+		return riskSignals{}, utils.ServerErr(fmt.Errorf("parse from currency: %w", err))
+	}
+
+	fromAmount, err := transactionSourceAmount(transaction, fromCurrency)
+	if err != nil {
+		return riskSignals{}, utils.ServerErr(err)
+	}
+
+	history, err := rs.queries.GetRiskTransferHistory(ctx, gen.GetRiskTransferHistoryParams{
+		Since:               time.Now().Add(-rs.policy.VelocityWindow),
+		SourceCurrency:      fromWallet.Currency,
+		DestinationCurrency: transaction.Currency,
+		Type:                transaction.Type,
+		UserID:              fromWallet.UserID,
+	})
+	if err != nil {
+		return riskSignals{}, utils.ServerErr(fmt.Errorf("get risk transfer history: %w", err))
+	}
+
+	recipientTransfers, err := rs.queries.CountRecipientTransfers(ctx, gen.CountRecipientTransfersParams{
+		UserID:    fromWallet.UserID,
+		Recipient: recipient,
+	})
+	if err != nil {
+		return riskSignals{}, utils.ServerErr(fmt.Errorf("count recipient transfers: %w", err))
+	}
+
+	limit, err := rs.limits.GetTransferLimit(ctx, fromWallet.UserID, models.TransactionType(transaction.Type), fromCurrency)
+	if err != nil {
+		return riskSignals{}, err
+	}
+
+	signals := riskSignals{
+		TransactionType:     transaction.Type,
+		SourceAmount:        fromAmount.Amount,
+		SourceCurrency:      fromWallet.Currency,
+		DestinationCurrency: transaction.Currency,
+		RecentTransfers:     history.RecentCount,
+		PriorTransfers:      history.TotalCount,
+		CorridorTransfers:   history.CorridorCount,
+		RecipientTransfers:  recipientTransfers,
+	}
+	// A beneficiary deleted since the transfer was created leaves the signal
+	// unset.
+	if transaction.BeneficiaryID.Valid {
+		verifiedAt, err := rs.queries.GetBeneficiaryVerifiedAt(ctx, transaction.BeneficiaryID.String)
+		switch {
+		case err == nil:
+			age := int64(time.Since(verifiedAt).Seconds())
+			signals.BeneficiaryAgeSeconds = &age
+		case err != sql.ErrNoRows:
+			return riskSignals{}, utils.ServerErr(fmt.Errorf("get beneficiary verified at: %w", err))
+		}
+	}
+	if limit != nil {
+		signals.PerTransactionMax = limit.PerTransactionMax
+		if limit.Daily.AmountLimit != nil {
+			remaining := max(*limit.Daily.AmountLimit-limit.Daily.AmountUsed, 0)
+			signals.DailyRemaining = &remaining
+		}
+	}
+	return signals, nil
+}
+
+// evaluateRisk applies the policy rules to the signals and returns the
+// decision, the total score and the rules that fired.
+func evaluateRisk(policy RiskPolicy, s riskSignals) (models.RiskDecision, int, []models.RiskReason) {
+	var reasons []models.RiskReason
+
+	if policy.VelocityMaxCount > 0 && s.RecentTransfers >= int64(policy.VelocityMaxCount) {
+		reasons = append(reasons, models.RiskReason{
+			Rule:   riskRuleVelocity,
+			Score:  riskScoreVelocity,
+			Detail: fmt.Sprintf("%d transfers in the last %s", s.RecentTransfers, policy.VelocityWindow),
+		})
+	}
+
+	if policy.LargeAmount > 0 && s.RecipientTransfers == 0 && money.ToMajorUnits(s.SourceAmount) >= policy.LargeAmount {
+		reasons = append(reasons, models.RiskReason{
+			Rule:   riskRuleNewBeneficiaryLargeAmount,
+			Score:  riskScoreNewBeneficiaryLargeAmount,
+			Detail: "large first transfer to this recipient",
+		})
+	}
+
+	if s.PriorTransfers > 0 && s.CorridorTransfers == 0 {
+		reasons = append(reasons, models.RiskReason{
+			Rule:   riskRuleUnusualCorridor,
+			Score:  riskScoreUnusualCorridor,
+			Detail: fmt.Sprintf("first %s to %s %s transfer", s.SourceCurrency, s.DestinationCurrency, s.TransactionType),
+		})
+	}
+
+	if detail := nearLimit(policy.NearLimitRatio, s); detail != "" {
+		reasons = append(reasons, models.RiskReason{
+			Rule:   riskRuleNearLimit,
+			Score:  riskScoreNearLimit,
+			Detail: detail,
+		})
+	}
+
+	if policy.BeneficiaryCooldown > 0 && s.BeneficiaryAgeSeconds != nil && time.Duration(*s.BeneficiaryAgeSeconds)*time.Second < policy.BeneficiaryCooldown {
+		reasons = append(reasons, models.RiskReason{
+			Rule:   riskRuleNewBeneficiaryCooldown,
+			Score:  riskScoreNewBeneficiaryCooldown,
+			Detail: fmt.Sprintf("beneficiary verified %s ago", time.Duration(*s.BeneficiaryAgeSeconds)*time.Second),
+		})
+	}
+
+	score := 0
+	for _, reason := range reasons {
+		score += reason.Score
+	}
+
+	switch {
+	case policy.DenyScore > 0 && score >= policy.DenyScore:
+		return models.RiskDecisionDeny, score, reasons
+	case policy.ReviewScore > 0 && score >= policy.ReviewScore:
+		return models.RiskDecisionReview, score, reasons
+	default:
+		return models.RiskDecisionAllow, score, reasons
+	}
+}
+
+// nearLimit reports an amount sized to slip just under the per-transaction
+// limit or what is left of the daily limit.
+func nearLimit(ratio float64, s riskSignals) string {
+	if ratio <= 0 || ratio >= 1 {
+		return ""
+	}
+
+	amount := float64(s.SourceAmount)
+	if s.PerTransactionMax != nil && *s.PerTransactionMax > 0 && amount >= ratio*float64(*s.PerTransactionMax) {
+		return "amount is just under the per-transaction limit"
+	}
+	if s.DailyRemaining != nil && *s.DailyRemaining > 0 && amount >= ratio*float64(*s.DailyRemaining) {
+		return "amount is just under the remaining daily limit"
+	}
+	return ""
+}
+
+// riskRecipient identifies who receives the funds, so repeat transfers to the
+// same beneficiary can be recognised: the destination wallet for internal
+// transfers and the bank account, by blind index, for external ones.
+func riskRecipient(ctx context.Context, queries gen.Querier, fields fieldCipher, transaction gen.Transaction) (string, error) {
+	if transaction.Type == string(models.TransactionTypeInternal) {
+		if !transaction.ToWalletID.Valid {
+			return "", fmt.Errorf("to wallet ID not found in transaction")
+		}
+		return "wallet:" + transaction.ToWalletID.String, nil
+	}
+
+	recipient, err := loadTransferRecipient(ctx, queries, fields, transaction)
+	if err != nil {
+		return "", err
+	}
+	return fields.accountRecipient(recipient.BankCode, recipient.AccountNumber), nil
+}
+
+func mapRiskAssessment(row gen.RiskAssessment) (*models.RiskAssessment, error) {
+	assessment := &models.RiskAssessment{
+		ID:            row.ID,
+		TransactionID: row.TransactionID,
+		UserID:        row.UserID,
+		Recipient:     row.Recipient,
+		Decision:      models.RiskDecision(row.Decision),
+		Score:         int(row.Score),
+		CreatedAt:     row.CreatedAt,
+	}
+
+	if err := json.Unmarshal(row.Reasons, &assessment.Reasons); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("unmarshal risk reasons: %w", err))
+	}
+	if err := json.Unmarshal(row.Features, &assessment.Features); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("unmarshal risk features: %w", err))
+	}
+
+	if row.ReviewDecision.Valid {
+		decision := models.RiskReviewDecision(row.ReviewDecision.String)
+		assessment.ReviewDecision = &decision
+	}
+	if row.ReviewedBy.Valid {
+		assessment.ReviewedBy = &row.ReviewedBy.String
+	}
+	if row.ReviewNote.Valid {
+		assessment.ReviewNote = &row.ReviewNote.String
+	}
+	if row.ReviewedAt.Valid {
+		assessment.ReviewedAt = &row.ReviewedAt.Time
+	}
+
+	return assessment, nil
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testRiskPolicy() RiskPolicy {
+	return RiskPolicy{
+		Enabled:             true,
+		ReviewScore:         50,
+		DenyScore:           100,
+		VelocityWindow:      time.Hour,
+		VelocityMaxCount:    5,
+		LargeAmount:         1000,
+		NearLimitRatio:      0.9,
+		BeneficiaryCooldown: 24 * time.Hour,
+	}
+}
+
+func TestEvaluateRisk(t *testing.T) {
+	perTransactionMax := int64(100000)
+	dailyRemaining := int64(50000)
+	newBeneficiary := int64(3600)
+	establishedBeneficiary := int64(7 * 24 * 3600)
+
+	// base is an ordinary transfer to a known recipient on a familiar corridor.
+	base := riskSignals{
+		TransactionType:     "internal",
+		SourceAmount:        10000,
+		SourceCurrency:      "USD",
+		DestinationCurrency: "EUR",
+		RecentTransfers:     1,
+		PriorTransfers:      10,
+		CorridorTransfers:   4,
+		RecipientTransfers:  2,
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(s *riskSignals)
+		decision models.RiskDecision
+		score    int
+		rules    []string
+	}{
+		{name: "ordinary transfer", modify: func(s *riskSignals) {}, decision: models.RiskDecisionAllow},
+		{name: "velocity", modify: func(s *riskSignals) { s.RecentTransfers = 5 }, decision: models.RiskDecisionReview, score: 50, rules: []string{riskRuleVelocity}},
+		{name: "large amount to new recipient", modify: func(s *riskSignals) { s.RecipientTransfers = 0; s.SourceAmount = 100000 }, decision: models.RiskDecisionReview, score: 50, rules: []string{riskRuleNewBeneficiaryLargeAmount}},
+		{name: "small amount to new recipient", modify: func(s *riskSignals) { s.RecipientTransfers = 0 }, decision: models.RiskDecisionAllow},
+		{name: "unusual corridor", modify: func(s *riskSignals) { s.CorridorTransfers = 0 }, decision: models.RiskDecisionAllow, score: 30, rules: []string{riskRuleUnusualCorridor}},
+		{name: "first transfer is not an unusual corridor", modify: func(s *riskSignals) { s.PriorTransfers = 0; s.CorridorTransfers = 0 }, decision: models.RiskDecisionAllow},
+		{name: "just under per-transaction limit", modify: func(s *riskSignals) { s.SourceAmount = 95000; s.PerTransactionMax = &perTransactionMax }, decision: models.RiskDecisionAllow, score: 30, rules: []string{riskRuleNearLimit}},
+		{name: "just under remaining daily limit", modify: func(s *riskSignals) { s.SourceAmount = 45000; s.DailyRemaining = &dailyRemaining }, decision: models.RiskDecisionAllow, score: 30, rules: []string{riskRuleNearLimit}},
+		{name: "beneficiary in cool-down", modify: func(s *riskSignals) { s.BeneficiaryAgeSeconds = &newBeneficiary }, decision: models.RiskDecisionAllow, score: 30, rules: []string{riskRuleNewBeneficiaryCooldown}},
+		{name: "beneficiary past cool-down", modify: func(s *riskSignals) { s.BeneficiaryAgeSeconds = &establishedBeneficiary }, decision: models.RiskDecisionAllow},
+		{
+			name: "unusual corridor near limit",
+			modify: func(s *riskSignals) {
+				s.CorridorTransfers = 0
+				s.SourceAmount = 95000
+				s.PerTransactionMax = &perTransactionMax
+			},
+			decision: models.RiskDecisionReview,
+			score:    60,
+			rules:    []string{riskRuleUnusualCorridor, riskRuleNearLimit},
+		},
+		{
+			name: "velocity and large amount to new recipient",
+			modify: func(s *riskSignals) {
+				s.RecentTransfers = 8
+				s.RecipientTransfers = 0
+				s.SourceAmount = 200000
+			},
+			decision: models.RiskDecisionDeny,
+			score:    100,
+			rules:    []string{riskRuleVelocity, riskRuleNewBeneficiaryLargeAmount},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signals := base
+			tt.modify(&signals)
+
+			decision, score, reasons := evaluateRisk(testRiskPolicy(), signals)
+
+			assert.Equal(t, tt.decision, decision)
+			assert.Equal(t, tt.score, score)
+			rules := make([]string, 0, len(reasons))
+			for _, reason := range reasons {
+				rules = append(rules, reason.Rule)
+			}
+			assert.ElementsMatch(t, tt.rules, rules)
+		})
+	}
+
+	t.Run("zero thresholds disable rules and decisions", func(t *testing.T) {
+		signals := base
+		signals.RecentTransfers = 100
+		signals.RecipientTransfers = 0
+		signals.SourceAmount = 1 << 30
+		signals.BeneficiaryAgeSeconds = &newBeneficiary
+
+		decision, score, reasons := evaluateRisk(RiskPolicy{Enabled: true}, signals)
+
+		assert.Equal(t, models.RiskDecisionAllow, decision)
+		assert.Zero(t, score)
+		assert.Empty(t, reasons)
+	})
+}
+
+func TestRiskRecipient(t *testing.T) {
+	ctx := context.Background()
+	mockQueries := new(mocks.MockQuerier)
+
+	internal := gen.Transaction{
+		Type:       "internal",
+		ToWalletID: sql.NullString{String: "wallet_2", Valid: true},
+	}
+	recipient, err := riskRecipient(ctx, mockQueries, fieldCipher{}, internal)
+	require.NoError(t, err)
+	assert.Equal(t, "wallet:wallet_2", recipient)
+
+	mockQueries.On("GetTransferRecipientByTransactionID", mock.Anything, "tx_1").Return(testTransferRecipient("tx_1"), nil)
+	recipient, err = riskRecipient(ctx, mockQueries, fieldCipher{}, gen.Transaction{ID: "tx_1", Type: "external"})
+	require.NoError(t, err)
+	assert.Equal(t, "account:044:9999999999", recipient)
+
+	mockQueries.On("GetTransferRecipientByTransactionID", mock.Anything, "tx_2").Return(nil, sql.ErrNoRows)
+	_, err = riskRecipient(ctx, mockQueries, fieldCipher{}, gen.Transaction{ID: "tx_2", Type: "external"})
+	assert.Error(t, err)
+
+	t.Run("account number is stored as its blind index", func(t *testing.T) {
+		fields := testFieldCipher(t, "k1")
+
+		recipient, err := riskRecipient(ctx, mockQueries, fields, gen.Transaction{ID: "tx_1", Type: "external"})
+
+		require.NoError(t, err)
+		assert.Regexp(t, `^account:044:[0-9a-f]{64}$`, recipient)
+		assert.NotContains(t, recipient, "9999999999")
+		assert.Equal(t, recipient, testFieldCipher(t, "k2").accountRecipient("044", "9999999999"), "same account under another data key")
+	})
+}
+
+func TestRiskService_Screen(t *testing.T) {
+	transaction := gen.Transaction{
+		ID:           "tx_123",
+		FromWalletID: sql.NullString{String: "wallet_1", Valid: true},
+		ToWalletID:   sql.NullString{String: "wallet_2", Valid: true},
+		Type:         "internal",
+		Amount:       170000,
+		Currency:     "EUR",
+		ExchangeRate: sql.NullString{String: "0.85000000", Valid: true},
+		Status:       "initiated",
+	}
+	fromWallet := &models.Wallet{ID: "wallet_1", UserID: "user_1", Currency: "USD"}
+
+	t.Run("disabled", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		rs := &riskService{queries: mockQueries, policy: RiskPolicy{}}
+
+		assessment, err := rs.Screen(context.Background(), transaction, fromWallet)
+
+		require.NoError(t, err)
+		assert.Nil(t, assessment)
+		mockQueries.AssertNotCalled(t, "CreateRiskAssessment", mock.Anything, mock.Anything)
+	})
+
+	t.Run("stores the assessment", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockLimits := new(mocks.MockLimitService)
+		rs := &riskService{queries: mockQueries, limits: mockLimits, policy: testRiskPolicy()}
+
+		mockQueries.On("GetRiskTransferHistory", mock.Anything, mock.MatchedBy(func(arg gen.GetRiskTransferHistoryParams) bool {
+			return arg.UserID == "user_1" && arg.SourceCurrency == "USD" && arg.DestinationCurrency == "EUR" && arg.Type == "internal"
+		})).Return(gen.GetRiskTransferHistoryRow{RecentCount: 1, TotalCount: 3, CorridorCount: 3}, nil)
+		mockQueries.On("CountRecipientTransfers", mock.Anything, gen.CountRecipientTransfersParams{UserID: "user_1", Recipient: "wallet:wallet_2"}).Return(int64(0), nil)
+		mockLimits.On("GetTransferLimit", mock.Anything, "user_1", models.TransactionTypeInternal, money.USD).Return(nil, nil)
+
+		var stored gen.CreateRiskAssessmentParams
+		mockQueries.On("CreateRiskAssessment", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(gen.CreateRiskAssessmentParams) }).
+			Return(gen.RiskAssessment{
+				ID:            "ra_1",
+				TransactionID: "tx_123",
+				UserID:        "user_1",
+				Recipient:     "wallet:wallet_2",
+				Decision:      "review",
+				Score:         50,
+				Reasons:       json.RawMessage(`[{"rule":"new_beneficiary_large_amount","score":50,"detail":"large first transfer to this recipient"}]`),
+				Features:      json.RawMessage(`{"source_amount":200000}`),
+			}, nil)
+
+		assessment, err := rs.Screen(context.Background(), transaction, fromWallet)
+
+		require.NoError(t, err)
+		assert.Equal(t, "tx_123", stored.TransactionID)
+		assert.Equal(t, "wallet:wallet_2", stored.Recipient)
+		assert.Equal(t, "review", stored.Decision)
+		assert.Equal(t, int32(50), stored.Score)
+		assert.Contains(t, string(stored.Reasons), riskRuleNewBeneficiaryLargeAmount)
+		assert.Contains(t, string(stored.Features), `"source_amount":200000`)
+
+		assert.Equal(t, models.RiskDecisionReview, assessment.Decision)
+		require.Len(t, assessment.Reasons, 1)
+		assert.Equal(t, riskRuleNewBeneficiaryLargeAmount, assessment.Reasons[0].Rule)
+		assert.Equal(t, float64(200000), assessment.Features["source_amount"])
+		mockQueries.AssertExpectations(t)
+	})
+	t.Run("measures beneficiary age from verification", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockLimits := new(mocks.MockLimitService)
+		rs := &riskService{queries: mockQueries, limits: mockLimits, policy: testRiskPolicy()}
+
+		withBeneficiary := transaction
+		withBeneficiary.BeneficiaryID = sql.NullString{String: "ben_1", Valid: true}
+
+		mockQueries.On("GetRiskTransferHistory", mock.Anything, mock.Anything).Return(gen.GetRiskTransferHistoryRow{RecentCount: 1, TotalCount: 3, CorridorCount: 3}, nil)
+		mockQueries.On("CountRecipientTransfers", mock.Anything, mock.Anything).Return(int64(2), nil)
+		mockQueries.On("GetBeneficiaryVerifiedAt", mock.Anything, "ben_1").Return(time.Now().Add(-2*time.Hour), nil)
+		mockLimits.On("GetTransferLimit", mock.Anything, "user_1", models.TransactionTypeInternal, money.USD).Return(nil, nil)
+
+		signals, err := rs.signals(context.Background(), withBeneficiary, fromWallet, "wallet:wallet_2")
+
+		require.NoError(t, err)
+		require.NotNil(t, signals.BeneficiaryAgeSeconds)
+		assert.InDelta(t, 7200, *signals.BeneficiaryAgeSeconds, 5)
+	})
+
+	t.Run("deleted beneficiary leaves age unset", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockLimits := new(mocks.MockLimitService)
+		rs := &riskService{queries: mockQueries, limits: mockLimits, policy: testRiskPolicy()}
+
+		withBeneficiary := transaction
+		withBeneficiary.BeneficiaryID = sql.NullString{String: "ben_1", Valid: true}
+
+		mockQueries.On("GetRiskTransferHistory", mock.Anything, mock.Anything).Return(gen.GetRiskTransferHistoryRow{}, nil)
+		mockQueries.On("CountRecipientTransfers", mock.Anything, mock.Anything).Return(int64(0), nil)
+		mockQueries.On("GetBeneficiaryVerifiedAt", mock.Anything, "ben_1").Return(nil, sql.ErrNoRows)
+		mockLimits.On("GetTransferLimit", mock.Anything, "user_1", models.TransactionTypeInternal, money.USD).Return(nil, nil)
+
+		signals, err := rs.signals(context.Background(), withBeneficiary, fromWallet, "wallet:wallet_2")
+
+		require.NoError(t, err)
+		assert.Nil(t, signals.BeneficiaryAgeSeconds)
+	})
+}
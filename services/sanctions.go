@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/sanctions"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+)
+
+const riskRuleSanctionsMatch = "sanctions_match"
+
+type SanctionsService interface {
+	ScreenPayout(ctx context.Context, transaction gen.Transaction, fromWallet *models.Wallet) (*SanctionsScreening, error)
+}
+
+// SanctionsScreening is the outcome of screening one beneficiary name.
+// Matches is empty when Result is clear.
+type SanctionsScreening struct {
+	Result      models.ScreeningResult
+	ListVersion string
+	Name        string
+	Matches     []sanctions.Match
+}
+
+type sanctionsService struct {
+	queries     gen.Querier
+	nameEnquiry NameEnquiryService
+	list        *sanctions.List
+	threshold   float64
+	fields      fieldCipher
+}
+
+// newSanctionsService screens against list. A nil list turns screening off.
+func newSanctionsService(queries gen.Querier, nameEnquiry NameEnquiryService, list *sanctions.List, threshold float64, fields fieldCipher) SanctionsService {
+	return &sanctionsService{
+		queries:     queries,
+		nameEnquiry: nameEnquiry,
+		list:        list,
+		threshold:   threshold,
+		fields:      fields,
+	}
+}
+
+// sanctionsFeatures is stored as the features of the risk assessment created
+// for a match, so reviewers can see what was compared.
+type sanctionsFeatures struct {
+	ScreenedName string                 `json:"screened_name"`
+	ListVersion  string                 `json:"list_version"`
+	Threshold    float64                `json:"threshold"`
+	Matches      []sanctionsMatchDetail `json:"matches"`
+}
+
+type sanctionsMatchDetail struct {
+	EntryID     string  `json:"entry_id"`
+	EntryName   string  `json:"entry_name"`
+	MatchedName string  `json:"matched_name"`
+	Score       float64 `json:"score"`
+}
+
+// ScreenPayout checks the beneficiary name of an external transfer, as
+// returned by name enquiry, against the sanctions list and records the result
+// on the transaction. A match also stores a review assessment so the transfer
+// goes through the risk review queue. It returns nil for internal transfers
+// and when no list is loaded.
+func (ss *sanctionsService) ScreenPayout(ctx context.Context, transaction gen.Transaction, fromWallet *models.Wallet) (*SanctionsScreening, error) {
+	if ss.list == nil || transaction.Type != string(models.TransactionTypeExternal) {
+		return nil, nil
+	}
+
+	recipient, err := loadTransferRecipient(ctx, ss.queries, ss.fields, transaction)
+	if err != nil {
+		return nil, utils.ServerErr(err)
+	}
+
+	enquiry, err := ss.nameEnquiry.EnquireAccountName(ctx, recipient.AccountNumber, recipient.BankCode)
+	if err != nil {
+		return nil, err
+	}
+	if enquiry.AccountName == "" {
+		return nil, utils.ServerErr(fmt.Errorf("name enquiry returned no beneficiary name to screen"))
+	}
+
+	screening := &SanctionsScreening{
+		Result:      models.ScreeningResultClear,
+		ListVersion: ss.list.Version,
+		Name:        enquiry.AccountName,
+		Matches:     ss.list.Match(enquiry.AccountName, ss.threshold),
+	}
+	if len(screening.Matches) > 0 {
+		screening.Result = models.ScreeningResultMatch
+	}
+
+	if err := ss.queries.UpdateTransactionScreening(ctx, gen.UpdateTransactionScreeningParams{
+		ScreeningResult:      sql.NullString{String: string(screening.Result), Valid: true},
+		ScreeningListVersion: sql.NullString{String: screening.ListVersion, Valid: true},
+		ScreenedName:         sql.NullString{String: screening.Name, Valid: true},
+		ID:                   transaction.ID,
+	}); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("record sanctions screening: %w", err))
+	}
+
+	if screening.Result == models.ScreeningResultMatch {
+		if err := ss.recordMatch(ctx, transaction, fromWallet, screening); err != nil {
+			return nil, err
+		}
+	}
+
+	return screening, nil
+}
+
+func (ss *sanctionsService) recordMatch(ctx context.Context, transaction gen.Transaction, fromWallet *models.Wallet, screening *SanctionsScreening) error {
+	recipient, err := riskRecipient(ctx, ss.queries, ss.fields, transaction)
+	if err != nil {
+		return utils.ServerErr(err)
+	}
+
+	reasons := make([]models.RiskReason, 0, len(screening.Matches))
+	features := sanctionsFeatures{
+		ScreenedName: screening.Name,
+		ListVersion:  screening.ListVersion,
+		Threshold:    ss.threshold,
+		Matches:      make([]sanctionsMatchDetail, 0, len(screening.Matches)),
+	}
+	for _, match := range screening.Matches {
+		reasons = append(reasons, models.RiskReason{
+			Rule:   riskRuleSanctionsMatch,
+			Score:  sanctionsScore(match.Score),
+			Detail: fmt.Sprintf("beneficiary name resembles %s (%s)", match.MatchedName, match.Entry.ID),
+		})
+		features.Matches = append(features.Matches, sanctionsMatchDetail{
+			EntryID:     match.Entry.ID,
+			EntryName:   match.Entry.Name,
+			MatchedName: match.MatchedName,
+			Score:       match.Score,
+		})
+	}
+
+	reasonsJSON, err := json.Marshal(reasons)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("marshal sanctions reasons: %w", err))
+	}
+	featuresJSON, err := json.Marshal(features)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("marshal sanctions features: %w", err))
+	}
+
+	if _, err := ss.queries.CreateRiskAssessment(ctx, gen.CreateRiskAssessmentParams{
+		TransactionID: transaction.ID,
+		UserID:        fromWallet.UserID,
+		Recipient:     recipient,
+		Decision:      string(models.RiskDecisionReview),
+		Score:         int32(sanctionsScore(screening.Matches[0].Score)),
+		Reasons:       reasonsJSON,
+		Features:      featuresJSON,
+	}); err != nil {
+		return utils.ServerErr(fmt.Errorf("create risk assessment: %w", err))
+	}
+
+	utils.Logger.Warn().
+		Str("event", "sanctions_screening_match").
+		Str("transaction_id", transaction.ID).
+		Str("user_id", fromWallet.UserID).
+		Str("entry_id", screening.Matches[0].Entry.ID).
+		Float64("match_score", screening.Matches[0].Score).
+		Str("list_version", screening.ListVersion).
+		Str("trace_id", utils.TraceIDFromContext(ctx)).
+		Msg("beneficiary matched sanctions list")
+
+	return nil
+}
+
+// sanctionsScore expresses a name similarity as a whole percentage, so it fits
+// the integer score stored with risk assessments.
+func sanctionsScore(similarity float64) int {
+	return int(math.Round(similarity * 100))
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/namematch"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/sanctions"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type stubNameEnquiry struct {
+	accountName string
+	err         error
+}
+
+func (s stubNameEnquiry) EnquireAccountName(ctx context.Context, accountNumber, bankCode string) (*NameEnquiryResult, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &NameEnquiryResult{AccountName: s.accountName}, nil
+}
+
+func (s stubNameEnquiry) MatchAccountName(supplied, resolved string) namematch.Result {
+	return namematch.Compare(supplied, resolved, namematch.Thresholds{Match: 0.9, Partial: 0.75})
+}
+
+func testSanctionsList(t *testing.T) *sanctions.List {
+	t.Helper()
+	list, err := sanctions.LoadXML([]byte(`<sanctionsList version="2025-01-15">
+  <entry id="SL-1"><name>Viktor Petrovsky</name><alias>Viktor Petrovski</alias></entry>
+</sanctionsList>`))
+	require.NoError(t, err)
+	return list
+}
+
+func externalTransaction() gen.Transaction {
+	return gen.Transaction{
+		ID:           "tx_123",
+		FromWalletID: sql.NullString{String: "wallet_1", Valid: true},
+		Type:         "external",
+		Amount:       50000,
+		Currency:     "USD",
+		Status:       "initiated",
+	}
+}
+
+func TestSanctionsService_ScreenPayout(t *testing.T) {
+	fromWallet := &models.Wallet{ID: "wallet_1", UserID: "user_1", Currency: "USD"}
+
+	t.Run("no list loaded", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ss := &sanctionsService{queries: mockQueries, nameEnquiry: stubNameEnquiry{accountName: "Viktor Petrovsky"}, threshold: 0.9}
+
+		screening, err := ss.ScreenPayout(context.Background(), externalTransaction(), fromWallet)
+
+		require.NoError(t, err)
+		assert.Nil(t, screening)
+		mockQueries.AssertNotCalled(t, "UpdateTransactionScreening", mock.Anything, mock.Anything)
+	})
+
+	t.Run("internal transfers are not screened", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ss := &sanctionsService{queries: mockQueries, nameEnquiry: stubNameEnquiry{accountName: "Viktor Petrovsky"}, list: testSanctionsList(t), threshold: 0.9}
+
+		transaction := externalTransaction()
+		transaction.Type = "internal"
+		screening, err := ss.ScreenPayout(context.Background(), transaction, fromWallet)
+
+		require.NoError(t, err)
+		assert.Nil(t, screening)
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ss := &sanctionsService{queries: mockQueries, nameEnquiry: stubNameEnquiry{accountName: "Mock Account Holder 9999"}, list: testSanctionsList(t), threshold: 0.9}
+		mockQueries.On("GetTransferRecipientByTransactionID", mock.Anything, "tx_123").Return(testTransferRecipient("tx_123"), nil)
+
+		mockQueries.On("UpdateTransactionScreening", mock.Anything, gen.UpdateTransactionScreeningParams{
+			ScreeningResult:      sql.NullString{String: "clear", Valid: true},
+			ScreeningListVersion: sql.NullString{String: "2025-01-15", Valid: true},
+			ScreenedName:         sql.NullString{String: "Mock Account Holder 9999", Valid: true},
+			ID:                   "tx_123",
+		}).Return(nil)
+
+		screening, err := ss.ScreenPayout(context.Background(), externalTransaction(), fromWallet)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.ScreeningResultClear, screening.Result)
+		assert.Empty(t, screening.Matches)
+		mockQueries.AssertExpectations(t)
+		mockQueries.AssertNotCalled(t, "CreateRiskAssessment", mock.Anything, mock.Anything)
+	})
+
+	t.Run("match stores a review assessment", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ss := &sanctionsService{queries: mockQueries, nameEnquiry: stubNameEnquiry{accountName: "PETROVSKI, Victor"}, list: testSanctionsList(t), threshold: 0.9}
+		mockQueries.On("GetTransferRecipientByTransactionID", mock.Anything, "tx_123").Return(testTransferRecipient("tx_123"), nil)
+
+		mockQueries.On("UpdateTransactionScreening", mock.Anything, mock.MatchedBy(func(arg gen.UpdateTransactionScreeningParams) bool {
+			return arg.ScreeningResult.String == "match" && arg.ScreeningListVersion.String == "2025-01-15" && arg.ID == "tx_123"
+		})).Return(nil)
+
+		var stored gen.CreateRiskAssessmentParams
+		mockQueries.On("CreateRiskAssessment", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(gen.CreateRiskAssessmentParams) }).
+			Return(gen.RiskAssessment{ID: "ra_1"}, nil)
+
+		screening, err := ss.ScreenPayout(context.Background(), externalTransaction(), fromWallet)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.ScreeningResultMatch, screening.Result)
+		require.Len(t, screening.Matches, 1)
+		assert.Equal(t, "SL-1", screening.Matches[0].Entry.ID)
+
+		assert.Equal(t, "review", stored.Decision)
+		assert.Equal(t, "user_1", stored.UserID)
+		assert.Equal(t, "account:044:9999999999", stored.Recipient)
+		assert.Contains(t, string(stored.Reasons), riskRuleSanctionsMatch)
+		assert.Contains(t, string(stored.Features), `"list_version":"2025-01-15"`)
+		assert.Contains(t, string(stored.Features), `"entry_id":"SL-1"`)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("name enquiry failure", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ss := &sanctionsService{queries: mockQueries, nameEnquiry: stubNameEnquiry{err: errors.New("provider down")}, list: testSanctionsList(t), threshold: 0.9}
+		mockQueries.On("GetTransferRecipientByTransactionID", mock.Anything, "tx_123").Return(testTransferRecipient("tx_123"), nil)
+
+		screening, err := ss.ScreenPayout(context.Background(), externalTransaction(), fromWallet)
+
+		assert.Error(t, err)
+		assert.Nil(t, screening)
+		mockQueries.AssertNotCalled(t, "UpdateTransactionScreening", mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing recipient details", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ss := &sanctionsService{queries: mockQueries, nameEnquiry: stubNameEnquiry{accountName: "Viktor Petrovsky"}, list: testSanctionsList(t), threshold: 0.9}
+
+		mockQueries.On("GetTransferRecipientByTransactionID", mock.Anything, "tx_123").Return(nil, sql.ErrNoRows)
+
+		_, err := ss.ScreenPayout(context.Background(), externalTransaction(), fromWallet)
+
+		assert.Error(t, err)
+	})
+}
@@ -3,26 +3,48 @@ package services
 import (
 	"context"
 	"database/sql"
+	"strings"
 
 	"github.com/IfedayoAwe/payment-processing-service/config"
 	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/bankdirectory"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/keyring"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/namematch"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/sanctions"
 	"github.com/IfedayoAwe/payment-processing-service/providers"
 	"github.com/IfedayoAwe/payment-processing-service/queue"
 	"github.com/IfedayoAwe/payment-processing-service/utils"
 )
 
 type Services struct {
-	Payment          PaymentService
-	Wallet           WalletService
-	Ledger           LedgerService
-	ExternalTransfer ExternalTransferService
-	NameEnquiry      NameEnquiryService
-	Webhook          WebhookService
-	PayoutWorker     PayoutWorker
-	WebhookWorker    WebhookWorker
-	OutboxWorker     OutboxWorker
-	Queries          *gen.Queries
-	Queue            queue.Queue
+	Payment            PaymentService
+	Wallet             WalletService
+	Ledger             LedgerService
+	Fee                FeeService
+	PIN                PINService
+	TOTP               TOTPService
+	Limit              LimitService
+	Risk               RiskService
+	Audit              AuditService
+	Refund             RefundService
+	Deposit            DepositService
+	PaymentRequest     PaymentRequestService
+	ExternalTransfer   ExternalTransferService
+	NameEnquiry        NameEnquiryService
+	Beneficiary        BeneficiaryService
+	Bank               BankService
+	User               UserService
+	Webhook            WebhookService
+	PayoutWorker       PayoutWorker
+	WebhookWorker      WebhookWorker
+	OutboxWorker       OutboxWorker
+	NotificationWorker NotificationWorker
+	ExpiryWorker       ExpiryWorker
+	ReencryptionWorker ReencryptionWorker
+	Queries            *gen.Queries
+	Queue              queue.Queue
 }
 
 func NewServices(db *sql.DB, queries *gen.Queries, cfg *config.Config) *Services {
@@ -37,31 +59,190 @@ func NewServices(db *sql.DB, queries *gen.Queries, cfg *config.Config) *Services
 		}
 	}
 
+	fields := fieldCipher{keyring: loadKeyring(cfg.EncryptionKeyringFile)}
+
 	ledgerService := newLedgerService(queries)
-	walletService := newWalletService(queries, db)
-	externalTransferService := newExternalTransferService(queries, db, walletService, ledgerService, q, processor)
-	paymentService := newPaymentService(queries, db, walletService, ledgerService, externalTransferService, processor)
-	nameEnquiryService := newNameEnquiryService(queries, processor)
-	webhookService := newWebhookService(queries)
-	payoutWorker := newPayoutWorker(queries, processor, q)
-	webhookWorker := newWebhookWorker(queries, q)
+	virtualAccount := VirtualAccountPolicy{
+		BIN:      cfg.VirtualAccountBIN,
+		Length:   cfg.VirtualAccountLength,
+		BankCode: cfg.VirtualAccountBankCode,
+	}
+	if err := virtualAccount.validate(); err != nil {
+		utils.Logger.Fatal().Err(err).Msg("invalid virtual account configuration")
+	}
+	kyc := KYCPolicy{
+		Enabled: cfg.KYCEnabled,
+		Tiers: map[models.KYCTier]KYCTierPolicy{
+			models.KYCTierIdentity: {Currencies: parseKYCCurrencies("KYC_TIER1_CURRENCIES", cfg.KYCTier1Currencies), LimitTier: cfg.KYCTier1LimitTier},
+			models.KYCTierFull:     {Currencies: parseKYCCurrencies("KYC_TIER2_CURRENCIES", cfg.KYCTier2Currencies), LimitTier: cfg.KYCTier2LimitTier},
+		},
+	}
+	walletService := newWalletService(queries, db, fields, processor, virtualAccount, kyc)
+	feeService := newFeeService(queries)
+	pinService := newPINService(queries, db, cfg.PINMaxAttempts, cfg.PINLockoutDuration, cfg.PINHistorySize, cfg.PINResetTokenTTL)
+	totpService := newTOTPService(queries, db, pinService, cfg.TOTPIssuer)
+	limitService := newLimitService(queries, kyc)
+	riskService := newRiskService(queries, limitService, RiskPolicy{
+		Enabled:             cfg.RiskEnabled,
+		ReviewScore:         cfg.RiskReviewScore,
+		DenyScore:           cfg.RiskDenyScore,
+		VelocityWindow:      cfg.RiskVelocityWindow,
+		VelocityMaxCount:    cfg.RiskVelocityMaxCount,
+		LargeAmount:         cfg.RiskLargeAmount,
+		NearLimitRatio:      cfg.RiskNearLimitRatio,
+		BeneficiaryCooldown: cfg.RiskBeneficiaryCooldown,
+	}, fields)
+	loadBankDirectory(db, queries, cfg.BankDirectoryFile)
+	bankService := newBankService(queries)
+	processor.SetBankRouter(bankService)
+	nameEnquiryService := newNameEnquiryService(queries, bankService, processor, fields, cfg.NameEnquiryCacheTTL, namematch.Thresholds{
+		Match:   cfg.NameMatchThreshold,
+		Partial: cfg.NameMatchPartialThreshold,
+	})
+	sanctionsService := newSanctionsService(queries, nameEnquiryService, loadSanctionsList(cfg.SanctionsListFile), cfg.SanctionsMatchThreshold, fields)
+	beneficiaryService := newBeneficiaryService(queries, db, nameEnquiryService, fields)
+	stepUpPolicy := StepUpPolicy{Thresholds: make(map[money.Currency]float64), Mode: StepUpMode(cfg.StepUpMode)}
+	for currency, threshold := range cfg.StepUpThresholds {
+		stepUpPolicy.Thresholds[money.Currency(currency)] = threshold
+	}
+	externalTransferService := newExternalTransferService(queries, db, walletService, ledgerService, feeService, limitService, q, nameEnquiryService, fields)
+	paymentService := newPaymentService(queries, db, walletService, ledgerService, externalTransferService, feeService, pinService, totpService, stepUpPolicy, limitService, riskService, sanctionsService, beneficiaryService, nameEnquiryService, bankService, processor, fields, cfg.TransactionTTL)
+	refundService := newRefundService(queries, db, walletService, ledgerService)
+	depositService := newDepositService(queries, db, walletService, ledgerService, fields)
+	paymentRequestService := newPaymentRequestService(queries, db, walletService, paymentService, cfg.PaymentRequestDefaultTTL, cfg.PaymentRequestMaxTTL)
+	webhookService := newWebhookService(queries, fields)
+	auditService := newAuditService(queries)
+	userService := newUserService(queries, db, fields, kyc)
+	payoutWorker := newPayoutWorker(queries, db, processor, q, fields)
+	webhookWorker := newWebhookWorker(queries, q, fields, depositService)
 	outboxWorker := newOutboxWorker(queries, db, q)
+	notificationWorker := newNotificationWorker(q)
+	expiryWorker := newExpiryWorker(queries, db, cfg.TransactionTTL, cfg.TransactionExpiryInterval)
+	reencryptionWorker := newReencryptionWorker(queries, fields, cfg.EncryptionReencryptInterval)
 
 	return &Services{
-		Payment:          paymentService,
-		Wallet:           walletService,
-		Ledger:           ledgerService,
-		ExternalTransfer: externalTransferService,
-		NameEnquiry:      nameEnquiryService,
-		Webhook:          webhookService,
-		PayoutWorker:     payoutWorker,
-		WebhookWorker:    webhookWorker,
-		OutboxWorker:     outboxWorker,
-		Queries:          queries,
-		Queue:            q,
+		Payment:            paymentService,
+		Wallet:             walletService,
+		Ledger:             ledgerService,
+		Fee:                feeService,
+		PIN:                pinService,
+		TOTP:               totpService,
+		Limit:              limitService,
+		Risk:               riskService,
+		Audit:              auditService,
+		Refund:             refundService,
+		Deposit:            depositService,
+		PaymentRequest:     paymentRequestService,
+		ExternalTransfer:   externalTransferService,
+		NameEnquiry:        nameEnquiryService,
+		Beneficiary:        beneficiaryService,
+		Bank:               bankService,
+		User:               userService,
+		Webhook:            webhookService,
+		PayoutWorker:       payoutWorker,
+		WebhookWorker:      webhookWorker,
+		OutboxWorker:       outboxWorker,
+		NotificationWorker: notificationWorker,
+		ExpiryWorker:       expiryWorker,
+		ReencryptionWorker: reencryptionWorker,
+		Queries:            queries,
+		Queue:              q,
 	}
 }
 
+// parseKYCCurrencies reads the currencies a KYC tier allows. An unknown
+// currency stops startup rather than silently narrowing the tier.
+func parseKYCCurrencies(key string, values []string) []money.Currency {
+	currencies := make([]money.Currency, 0, len(values))
+	for _, value := range values {
+		currency, err := money.ParseCurrency(strings.ToUpper(value))
+		if err != nil {
+			utils.Logger.Fatal().Err(err).Str("key", key).Msg("invalid kyc tier currency")
+		}
+		currencies = append(currencies, currency)
+	}
+	return currencies
+}
+
+// loadSanctionsList loads the list external payouts are screened against. An
+// empty path leaves screening off; a list that fails to load stops startup
+// rather than letting payouts through unscreened.
+func loadSanctionsList(path string) *sanctions.List {
+	if path == "" {
+		utils.Logger.Warn().Msg("SANCTIONS_LIST_FILE is not set; external payouts will not be sanctions screened")
+		return nil
+	}
+
+	list, err := sanctions.LoadFile(path)
+	if err != nil {
+		utils.Logger.Fatal().Err(err).Str("path", path).Msg("failed to load sanctions list")
+	}
+
+	utils.Logger.Info().
+		Str("path", path).
+		Str("version", list.Version).
+		Int("entries", len(list.Entries)).
+		Msg("sanctions list loaded")
+	return list
+}
+
+// loadBankDirectory imports the bank directory file into the banks table, so
+// the table always matches the file the service was started with. An empty
+// path leaves the table as it is; a file that fails to import stops startup.
+func loadBankDirectory(db *sql.DB, queries *gen.Queries, path string) {
+	if path == "" {
+		utils.Logger.Warn().Msg("BANK_DIRECTORY_FILE is not set; the bank directory will not be refreshed")
+		return
+	}
+
+	banks, err := bankdirectory.LoadFile(path)
+	if err != nil {
+		utils.Logger.Fatal().Err(err).Str("path", path).Msg("failed to load bank directory")
+	}
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		utils.Logger.Fatal().Err(err).Msg("failed to begin bank directory import")
+	}
+	defer tx.Rollback()
+
+	deactivated, err := importBankDirectory(ctx, queries.WithTx(tx), banks)
+	if err != nil {
+		utils.Logger.Fatal().Err(err).Str("path", path).Msg("failed to import bank directory")
+	}
+	if err := tx.Commit(); err != nil {
+		utils.Logger.Fatal().Err(err).Msg("failed to commit bank directory import")
+	}
+
+	utils.Logger.Info().
+		Str("path", path).
+		Int("banks", len(banks)).
+		Int64("deactivated", deactivated).
+		Msg("bank directory imported")
+}
+
+// loadKeyring loads the keys used for field-level encryption. An empty path
+// stores sensitive columns in plaintext; a keyring that fails to load stops
+// startup, since values already encrypted could not be read.
+func loadKeyring(path string) *keyring.Keyring {
+	if path == "" {
+		utils.Logger.Warn().Msg("ENCRYPTION_KEYRING_FILE is not set; sensitive columns will be stored in plaintext")
+		return nil
+	}
+
+	kr, err := keyring.LoadFile(path)
+	if err != nil {
+		utils.Logger.Fatal().Err(err).Str("path", path).Msg("failed to load encryption keyring")
+	}
+
+	utils.Logger.Info().
+		Str("path", path).
+		Str("active_key", kr.ActiveKeyID()).
+		Msg("encryption keyring loaded")
+	return kr
+}
+
 func (s *Services) StartWorkers(ctx context.Context) {
 	workers := []struct {
 		name   string
@@ -70,6 +251,9 @@ func (s *Services) StartWorkers(ctx context.Context) {
 		{"outbox", s.OutboxWorker.StartWorker},
 		{"payout", s.PayoutWorker.StartWorker},
 		{"webhook", s.WebhookWorker.StartWorker},
+		{"notification", s.NotificationWorker.StartWorker},
+		{"expiry", s.ExpiryWorker.StartWorker},
+		{"reencryption", s.ReencryptionWorker.StartWorker},
 	}
 
 	for _, w := range workers {
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/totp"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+)
+
+const (
+	defaultTOTPIssuer = "Payment Processing Service"
+
+	// totpSkew accepts codes one step either side of now to allow for clock
+	// drift on the user's device.
+	totpSkew          = 1
+	recoveryCodeCount = 10
+)
+
+type StepUpMode string
+
+const (
+	// StepUpModeAdditional asks for the PIN and a TOTP code.
+	StepUpModeAdditional StepUpMode = "additional"
+	// StepUpModeReplace asks for a TOTP code instead of the PIN.
+	StepUpModeReplace StepUpMode = "replace"
+)
+
+// StepUpPolicy decides which factors confirm a transaction. Thresholds are per
+// currency, in major units, and an amount is only compared with the threshold
+// for its own currency; no exchange rate is applied. Amounts at or above it
+// need a TOTP code. Currencies without a threshold, or with a zero one, never
+// need step-up.
+type StepUpPolicy struct {
+	Thresholds map[money.Currency]float64
+	Mode       StepUpMode
+}
+
+func (p StepUpPolicy) Requires(amount money.Money) bool {
+	threshold := p.Thresholds[amount.Currency]
+	return threshold > 0 && money.ToMajorUnits(amount.Amount) >= threshold
+}
+
+func (p StepUpPolicy) requiresPIN(amount money.Money) bool {
+	return !p.Requires(amount) || p.Mode != StepUpModeReplace
+}
+
+type TOTPService interface {
+	Enroll(ctx context.Context, userID string) (*models.TOTPEnrollment, error)
+	Activate(ctx context.Context, userID string, code string) ([]string, error)
+	Disable(ctx context.Context, userID string, code string) error
+	IsEnabled(ctx context.Context, userID string) (bool, error)
+	Verify(ctx context.Context, userID string, code string) error
+}
+
+type totpService struct {
+	queries gen.Querier
+	db      *sql.DB
+	pin     PINService
+	issuer  string
+}
+
+func newTOTPService(queries gen.Querier, db *sql.DB, pin PINService, issuer string) TOTPService {
+	if issuer == "" {
+		issuer = defaultTOTPIssuer
+	}
+	return &totpService{
+		queries: queries,
+		db:      db,
+		pin:     pin,
+		issuer:  issuer,
+	}
+}
+
+// Enroll starts TOTP enrollment with a fresh secret. Calling it again before
+// activation replaces the pending secret.
+func (ts *totpService) Enroll(ctx context.Context, userID string) (*models.TOTPEnrollment, error) {
+	if _, err := ts.queries.GetUserByID(ctx, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.NotFoundErr("user not found")
+		}
+		return nil, utils.ServerErr(fmt.Errorf("get user: %w", err))
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("generate totp secret: %w", err))
+	}
+
+	if _, err := ts.queries.UpsertPendingUserTOTP(ctx, gen.UpsertPendingUserTOTPParams{
+		UserID: userID,
+		Secret: secret,
+	}); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.BadRequestErr("TOTP already enabled; disable it before enrolling again")
+		}
+		return nil, utils.ServerErr(fmt.Errorf("save totp secret: %w", err))
+	}
+
+	return &models.TOTPEnrollment{
+		UserID:          userID,
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(ts.issuer, userID, secret),
+	}, nil
+}
+
+// Activate turns on a pending enrollment once the user supplies a matching
+// code, and returns single-use recovery codes. The codes are only stored
+// hashed, so this is the only time they are shown.
+func (ts *totpService) Activate(ctx context.Context, userID string, code string) ([]string, error) {
+	record, err := ts.queries.GetUserTOTP(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.BadRequestErr("no pending TOTP enrollment")
+		}
+		return nil, utils.ServerErr(fmt.Errorf("get totp: %w", err))
+	}
+
+	if record.ConfirmedAt.Valid {
+		return nil, utils.BadRequestErr("TOTP already enabled")
+	}
+
+	step, ok, err := totp.Validate(record.Secret, code, time.Now(), totpSkew)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("validate totp code: %w", err))
+	}
+	if !ok {
+		return nil, utils.BadRequestErr("invalid TOTP code")
+	}
+
+	codes, err := generateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("generate recovery codes: %w", err))
+	}
+
+	tx, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := ts.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ts.queries
+	}
+
+	if err := activateTOTP(ctx, queries, userID, step, codes); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	utils.Logger.Info().
+		Str("event", "totp_enabled").
+		Str("user_id", userID).
+		Str("trace_id", utils.TraceIDFromContext(ctx)).
+		Msg("TOTP enabled")
+
+	return codes, nil
+}
+
+// Disable removes the authenticator and its recovery codes. It needs a valid
+// TOTP or recovery code so a stolen session alone cannot turn step-up off.
+func (ts *totpService) Disable(ctx context.Context, userID string, code string) error {
+	if err := ts.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+
+	tx, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := ts.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ts.queries
+	}
+
+	if err := queries.DeleteUserTOTP(ctx, userID); err != nil {
+		return utils.ServerErr(fmt.Errorf("delete totp: %w", err))
+	}
+
+	if err := queries.DeleteTOTPRecoveryCodes(ctx, userID); err != nil {
+		return utils.ServerErr(fmt.Errorf("delete recovery codes: %w", err))
+	}
+
+	if err := recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "totp.disabled",
+		EntityType: auditEntityUser,
+		EntityID:   userID,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	utils.Logger.Info().
+		Str("event", "totp_disabled").
+		Str("user_id", userID).
+		Str("trace_id", utils.TraceIDFromContext(ctx)).
+		Msg("TOTP disabled")
+
+	return nil
+}
+
+func (ts *totpService) IsEnabled(ctx context.Context, userID string) (bool, error) {
+	record, err := ts.queries.GetUserTOTP(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, utils.ServerErr(fmt.Errorf("get totp: %w", err))
+	}
+	return record.ConfirmedAt.Valid, nil
+}
+
+// Verify accepts a current TOTP code or an unused recovery code. Each TOTP
+// step is accepted once, and wrong codes count towards the same lockout as
+// wrong PINs.
+func (ts *totpService) Verify(ctx context.Context, userID string, code string) error {
+	record, err := ts.queries.GetUserTOTP(ctx, userID)
+	if err != nil && err != sql.ErrNoRows {
+		return utils.ServerErr(fmt.Errorf("get totp: %w", err))
+	}
+	if err == sql.ErrNoRows || !record.ConfirmedAt.Valid {
+		return utils.TOTPRequiredErr("TOTP is not enabled for this user")
+	}
+
+	attempt, err := ts.pin.claimAttempt(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	ok, err := ts.checkCode(ctx, record, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ts.pin.recordFailure(ctx, userID, attempt, "invalid TOTP code")
+	}
+
+	return ts.pin.clearFailures(ctx, userID)
+}
+
+func (ts *totpService) checkCode(ctx context.Context, record gen.UserTotp, code string) (bool, error) {
+	if len(code) == totp.Digits {
+		step, ok, err := totp.Validate(record.Secret, code, time.Now(), totpSkew)
+		if err != nil {
+			return false, utils.ServerErr(fmt.Errorf("validate totp code: %w", err))
+		}
+		if !ok {
+			return false, nil
+		}
+
+		// A replayed code fails the last_used_step guard and is treated
+		// like a wrong one.
+		updated, err := ts.queries.UseTOTPStep(ctx, gen.UseTOTPStepParams{
+			Step:   step,
+			UserID: record.UserID,
+		})
+		if err != nil {
+			return false, utils.ServerErr(fmt.Errorf("record totp step: %w", err))
+		}
+		return updated > 0, nil
+	}
+
+	used, err := ts.queries.UseTOTPRecoveryCode(ctx, gen.UseTOTPRecoveryCodeParams{
+		UserID:   record.UserID,
+		CodeHash: hashRecoveryCode(code),
+	})
+	if err != nil {
+		return false, utils.ServerErr(fmt.Errorf("use recovery code: %w", err))
+	}
+	if used == 0 {
+		return false, nil
+	}
+
+	utils.Logger.Warn().
+		Str("event", "totp_recovery_code_used").
+		Str("user_id", record.UserID).
+		Str("trace_id", utils.TraceIDFromContext(ctx)).
+		Msg("TOTP recovery code used")
+
+	return true, nil
+}
+
+func activateTOTP(ctx context.Context, queries gen.Querier, userID string, step int64, codes []string) error {
+	updated, err := queries.ConfirmUserTOTP(ctx, gen.ConfirmUserTOTPParams{
+		Step:   step,
+		UserID: userID,
+	})
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("confirm totp: %w", err))
+	}
+	if updated == 0 {
+		return utils.BadRequestErr("TOTP already enabled")
+	}
+
+	if err := queries.DeleteTOTPRecoveryCodes(ctx, userID); err != nil {
+		return utils.ServerErr(fmt.Errorf("delete recovery codes: %w", err))
+	}
+
+	for _, code := range codes {
+		if err := queries.CreateTOTPRecoveryCode(ctx, gen.CreateTOTPRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: hashRecoveryCode(code),
+		}); err != nil {
+			return utils.ServerErr(fmt.Errorf("create recovery code: %w", err))
+		}
+	}
+
+	return recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "totp.enabled",
+		EntityType: auditEntityUser,
+		EntityID:   userID,
+		After:      map[string]any{"recovery_codes": len(codes)},
+	})
+}
+
+// generateRecoveryCodes returns codes formatted as xxxx-xxxx-xxxx-xxxx.
+func generateRecoveryCodes(n int) ([]string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		raw := strings.ToLower(encoding.EncodeToString(b))
+		codes = append(codes, raw[0:4]+"-"+raw[4:8]+"-"+raw[8:12]+"-"+raw[12:16])
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode ignores case, spaces and hyphens so users can type codes
+// the way they were written down.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/totp"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+func newTestTOTPService(mockQueries *mocks.MockQuerier) *totpService {
+	return &totpService{
+		queries: mockQueries,
+		pin:     &pinService{queries: mockQueries, db: newNopDB(), maxAttempts: 5, lockoutDuration: 30 * time.Minute},
+		issuer:  defaultTOTPIssuer,
+	}
+}
+
+func enabledTOTP() gen.UserTotp {
+	return gen.UserTotp{
+		UserID:      "user_1",
+		Secret:      testTOTPSecret,
+		ConfirmedAt: sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true},
+	}
+}
+
+func TestTOTPService_Verify(t *testing.T) {
+	t.Run("current code", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ts := newTestTOTPService(mockQueries)
+		code, err := totp.Code(testTOTPSecret, time.Now())
+		require.NoError(t, err)
+
+		mockQueries.On("GetUserTOTP", mock.Anything, "user_1").Return(enabledTOTP(), nil)
+		mockQueries.On("ClaimPINAttempt", mock.Anything, expectedClaim("user_1", 5)).Return(gen.PinAttempt{UserID: "user_1", FailedAttempts: 1}, nil)
+		mockQueries.On("UseTOTPStep", mock.Anything, mock.MatchedBy(func(arg gen.UseTOTPStepParams) bool {
+			return arg.UserID == "user_1" && arg.Step > 0
+		})).Return(int64(1), nil)
+		mockQueries.On("ClearPINAttempts", mock.Anything, "user_1").Return(int64(1), nil)
+
+		err = ts.Verify(context.Background(), "user_1", code)
+
+		require.NoError(t, err)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("replayed code counts as a failure", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ts := newTestTOTPService(mockQueries)
+		code, err := totp.Code(testTOTPSecret, time.Now())
+		require.NoError(t, err)
+
+		mockQueries.On("GetUserTOTP", mock.Anything, "user_1").Return(enabledTOTP(), nil)
+		mockQueries.On("ClaimPINAttempt", mock.Anything, expectedClaim("user_1", 5)).Return(gen.PinAttempt{UserID: "user_1", FailedAttempts: 1}, nil)
+		mockQueries.On("UseTOTPStep", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+		err = ts.Verify(context.Background(), "user_1", code)
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		assert.Contains(t, err.Error(), "invalid TOTP code")
+	})
+
+	t.Run("wrong code at threshold locks", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ts := newTestTOTPService(mockQueries)
+
+		mockQueries.On("GetUserTOTP", mock.Anything, "user_1").Return(enabledTOTP(), nil)
+		mockQueries.On("ClaimPINAttempt", mock.Anything, expectedClaim("user_1", 5)).Return(gen.PinAttempt{UserID: "user_1", FailedAttempts: 5}, nil)
+		mockQueries.On("UseTOTPRecoveryCode", mock.Anything, mock.Anything).Return(int64(0), nil)
+		mockQueries.On("LockPIN", mock.Anything, mock.Anything).Return(nil)
+		expectAuditEvent(mockQueries, "pin.locked", "user_1")
+
+		err := ts.Verify(context.Background(), "user_1", "not-a-code")
+
+		assert.ErrorIs(t, err, utils.ErrPINLocked)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("locked user is rejected without checking the code", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ts := newTestTOTPService(mockQueries)
+
+		mockQueries.On("GetUserTOTP", mock.Anything, "user_1").Return(enabledTOTP(), nil)
+		mockQueries.On("ClaimPINAttempt", mock.Anything, expectedClaim("user_1", 5)).Return(nil, sql.ErrNoRows)
+		mockQueries.On("GetPINAttempts", mock.Anything, "user_1").Return(gen.PinAttempt{
+			UserID:      "user_1",
+			LockedUntil: sql.NullTime{Time: time.Now().Add(10 * time.Minute), Valid: true},
+		}, nil)
+
+		err := ts.Verify(context.Background(), "user_1", "123456")
+
+		assert.ErrorIs(t, err, utils.ErrPINLocked)
+		mockQueries.AssertNotCalled(t, "UseTOTPStep", mock.Anything, mock.Anything)
+	})
+
+	t.Run("recovery code", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ts := newTestTOTPService(mockQueries)
+
+		mockQueries.On("GetUserTOTP", mock.Anything, "user_1").Return(enabledTOTP(), nil)
+		mockQueries.On("ClaimPINAttempt", mock.Anything, expectedClaim("user_1", 5)).Return(gen.PinAttempt{UserID: "user_1", FailedAttempts: 3}, nil)
+		mockQueries.On("UseTOTPRecoveryCode", mock.Anything, gen.UseTOTPRecoveryCodeParams{
+			UserID:   "user_1",
+			CodeHash: hashRecoveryCode("abcd-efgh-ijkl-mnop"),
+		}).Return(int64(1), nil)
+		mockQueries.On("ClearPINAttempts", mock.Anything, "user_1").Return(int64(1), nil)
+
+		err := ts.Verify(context.Background(), "user_1", "ABCD EFGH IJKL MNOP")
+
+		require.NoError(t, err)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ts := newTestTOTPService(mockQueries)
+
+		mockQueries.On("GetUserTOTP", mock.Anything, "user_1").Return(gen.UserTotp{UserID: "user_1", Secret: testTOTPSecret}, nil)
+
+		err := ts.Verify(context.Background(), "user_1", "123456")
+
+		assert.ErrorIs(t, err, utils.ErrTOTPRequired)
+	})
+}
+
+func TestTOTPService_Enroll(t *testing.T) {
+	t.Run("returns secret and provisioning URI", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ts := newTestTOTPService(mockQueries)
+
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(gen.User{UserID: "user_1"}, nil)
+		mockQueries.On("UpsertPendingUserTOTP", mock.Anything, mock.Anything).Return(gen.UserTotp{UserID: "user_1"}, nil)
+
+		enrollment, err := ts.Enroll(context.Background(), "user_1")
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, enrollment.Secret)
+		assert.Contains(t, enrollment.ProvisioningURI, "secret="+enrollment.Secret)
+	})
+
+	t.Run("already enabled", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ts := newTestTOTPService(mockQueries)
+
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(gen.User{UserID: "user_1"}, nil)
+		mockQueries.On("UpsertPendingUserTOTP", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+
+		_, err := ts.Enroll(context.Background(), "user_1")
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+	})
+}
+
+func TestActivateTOTP(t *testing.T) {
+	codes, err := generateRecoveryCodes(recoveryCodeCount)
+	require.NoError(t, err)
+	assert.Len(t, codes, recoveryCodeCount)
+	assert.Regexp(t, `^[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}$`, codes[0])
+
+	t.Run("stores hashed recovery codes", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("ConfirmUserTOTP", mock.Anything, gen.ConfirmUserTOTPParams{Step: 42, UserID: "user_1"}).Return(int64(1), nil)
+		mockQueries.On("DeleteTOTPRecoveryCodes", mock.Anything, "user_1").Return(nil)
+		mockQueries.On("CreateTOTPRecoveryCode", mock.Anything, mock.MatchedBy(func(arg gen.CreateTOTPRecoveryCodeParams) bool {
+			return arg.UserID == "user_1" && len(arg.CodeHash) == 64
+		})).Return(nil).Times(recoveryCodeCount)
+		expectAuditEvent(mockQueries, "totp.enabled", "user_1")
+
+		err := activateTOTP(context.Background(), mockQueries, "user_1", 42, codes)
+
+		require.NoError(t, err)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("already enabled", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("ConfirmUserTOTP", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+		err := activateTOTP(context.Background(), mockQueries, "user_1", 42, codes)
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		mockQueries.AssertNotCalled(t, "CreateTOTPRecoveryCode", mock.Anything, mock.Anything)
+	})
+}
+
+func TestStepUpPolicy(t *testing.T) {
+	usd := func(amount int64) money.Money { return money.NewMoney(amount, money.USD) }
+	thresholds := map[money.Currency]float64{money.USD: 1000, money.GBP: 800}
+
+	disabled := StepUpPolicy{}
+	assert.False(t, disabled.Requires(usd(1_000_000_00)))
+	assert.True(t, disabled.requiresPIN(usd(1_000_000_00)))
+
+	additional := StepUpPolicy{Thresholds: thresholds, Mode: StepUpModeAdditional}
+	assert.False(t, additional.Requires(usd(999_99)))
+	assert.True(t, additional.Requires(usd(1000_00)))
+	assert.True(t, additional.requiresPIN(usd(1000_00)))
+
+	replace := StepUpPolicy{Thresholds: thresholds, Mode: StepUpModeReplace}
+	assert.True(t, replace.requiresPIN(usd(999_99)))
+	assert.False(t, replace.requiresPIN(usd(1000_00)))
+
+	t.Run("each currency uses its own threshold", func(t *testing.T) {
+		assert.True(t, additional.Requires(money.NewMoney(800_00, money.GBP)))
+		assert.False(t, additional.Requires(money.NewMoney(799_99, money.GBP)))
+		assert.False(t, additional.Requires(money.NewMoney(1_000_000_00, money.EUR)))
+	})
+}
@@ -1,10 +1,12 @@
 package services
 
 import (
+	"database/sql"
 	"strconv"
 
 	"github.com/IfedayoAwe/payment-processing-service/db/gen"
 	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/keyring"
 )
 
 func mapTransaction(t gen.Transaction) *models.Transaction {
@@ -18,7 +20,9 @@ func mapTransaction(t gen.Transaction) *models.Transaction {
 		toWalletID = &t.ToWalletID.String
 	}
 
-	var traceID, providerName, providerRef, failureReason *string
+	var traceID, providerName, providerRef, failureReason, feeCurrency, parentTransactionID *string
+	var screeningListVersion, screenedName, beneficiaryID *string
+	var screeningResult *models.ScreeningResult
 	var exchangeRate *float64
 	if t.TraceID.Valid {
 		traceID = &t.TraceID.String
@@ -26,12 +30,33 @@ func mapTransaction(t gen.Transaction) *models.Transaction {
 	if t.ProviderName.Valid {
 		providerName = &t.ProviderName.String
 	}
-	if t.ProviderReference.Valid {
+	// Encrypted recipient details are not a provider reference and are never
+	// returned to callers.
+	if t.ProviderReference.Valid && !keyring.IsEncrypted(t.ProviderReference.String) {
 		providerRef = &t.ProviderReference.String
 	}
 	if t.FailureReason.Valid {
 		failureReason = &t.FailureReason.String
 	}
+	if t.FeeCurrency.Valid {
+		feeCurrency = &t.FeeCurrency.String
+	}
+	if t.ParentTransactionID.Valid {
+		parentTransactionID = &t.ParentTransactionID.String
+	}
+	if t.ScreeningResult.Valid {
+		result := models.ScreeningResult(t.ScreeningResult.String)
+		screeningResult = &result
+	}
+	if t.ScreeningListVersion.Valid {
+		screeningListVersion = &t.ScreeningListVersion.String
+	}
+	if t.ScreenedName.Valid {
+		screenedName = &t.ScreenedName.String
+	}
+	if t.BeneficiaryID.Valid {
+		beneficiaryID = &t.BeneficiaryID.String
+	}
 	if t.ExchangeRate.Valid {
 		if f, err := strconv.ParseFloat(t.ExchangeRate.String, 64); err == nil && f > 0 {
 			exchangeRate = &f
@@ -39,20 +64,36 @@ func mapTransaction(t gen.Transaction) *models.Transaction {
 	}
 
 	return &models.Transaction{
-		ID:                t.ID,
-		IdempotencyKey:    t.IdempotencyKey,
-		TraceID:           traceID,
-		FromWalletID:      fromWalletID,
-		ToWalletID:        toWalletID,
-		Type:              models.TransactionType(t.Type),
-		Amount:            t.Amount,
-		Currency:          t.Currency,
-		Status:            models.TransactionStatus(t.Status),
-		ProviderName:      providerName,
-		ProviderReference: providerRef,
-		ExchangeRate:      exchangeRate,
-		FailureReason:     failureReason,
-		CreatedAt:         t.CreatedAt,
-		UpdatedAt:         t.UpdatedAt,
+		ID:                   t.ID,
+		IdempotencyKey:       t.IdempotencyKey,
+		TraceID:              traceID,
+		FromWalletID:         fromWalletID,
+		ToWalletID:           toWalletID,
+		Type:                 models.TransactionType(t.Type),
+		Amount:               t.Amount,
+		Currency:             t.Currency,
+		Status:               models.TransactionStatus(t.Status),
+		ProviderName:         providerName,
+		ProviderReference:    providerRef,
+		ExchangeRate:         exchangeRate,
+		FailureReason:        failureReason,
+		FeeAmount:            t.FeeAmount,
+		FeeCurrency:          feeCurrency,
+		ParentTransactionID:  parentTransactionID,
+		ScreeningResult:      screeningResult,
+		ScreeningListVersion: screeningListVersion,
+		ScreenedName:         screenedName,
+		BeneficiaryID:        beneficiaryID,
+		PaymentRequestID:     nullStringPtr(t.PaymentRequestID),
+		CreatedAt:            t.CreatedAt,
+		UpdatedAt:            t.UpdatedAt,
+	}
+}
+
+// nullStringPtr returns a nullable column as a pointer, nil when it is null.
+func nullStringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
 	}
+	return &s.String
 }
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+)
+
+// transitionTransactionStatus moves a transaction between statuses only if it
+// is still in the expected one, so concurrent confirm/cancel/reverse calls
+// cannot both win. Every successful transition is recorded in the status
+// history with an optional reason, and in the audit log.
+func transitionTransactionStatus(ctx context.Context, queries gen.Querier, transactionID string, from models.TransactionStatus, to models.TransactionStatus, reason string) error {
+	rows, err := queries.TransitionTransactionStatus(ctx, gen.TransitionTransactionStatusParams{
+		NewStatus:     string(to),
+		ID:            transactionID,
+		CurrentStatus: string(from),
+	})
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("update transaction status: %w", err))
+	}
+	if rows == 0 {
+		return utils.BadRequestErr(fmt.Sprintf("transaction is no longer in %s status", from))
+	}
+
+	err = queries.CreateTransactionStatusHistory(ctx, gen.CreateTransactionStatusHistoryParams{
+		TransactionID: transactionID,
+		FromStatus:    string(from),
+		ToStatus:      string(to),
+		Reason:        sql.NullString{String: reason, Valid: reason != ""},
+	})
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("record transaction status history: %w", err))
+	}
+
+	return recordStatusChangeAudit(ctx, queries, transactionID, from, to, reason)
+}
+
+func recordStatusChangeAudit(ctx context.Context, queries gen.Querier, transactionID string, from models.TransactionStatus, to models.TransactionStatus, reason string) error {
+	return recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "transaction.status_changed",
+		EntityType: auditEntityTransaction,
+		EntityID:   transactionID,
+		Before:     map[string]any{"status": from},
+		After:      map[string]any{"status": to, "reason": reason},
+	})
+}
+
+// abandonInitiatedTransaction moves an initiated transaction that will never
+// be confirmed to a terminal status and frees the funds it was holding.
+func abandonInitiatedTransaction(ctx context.Context, queries gen.Querier, transactionID string, to models.TransactionStatus, reason string) error {
+	return abandonTransaction(ctx, queries, transactionID, models.TransactionStatusInitiated, to, reason)
+}
+
+// abandonTransaction ends a transaction that has not moved money yet, from
+// initiated or on_hold, and frees the funds it was holding. It locks the
+// transaction row, then the hold, then the wallet; confirmation takes the
+// transaction row first too, so the two cannot deadlock.
+func abandonTransaction(ctx context.Context, queries gen.Querier, transactionID string, from models.TransactionStatus, to models.TransactionStatus, reason string) error {
+	if err := transitionTransactionStatus(ctx, queries, transactionID, from, to, reason); err != nil {
+		return err
+	}
+
+	if _, err := releaseWalletHold(ctx, queries, transactionID, models.WalletHoldStatusReleased); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// lockTransactionForConfirm locks the transaction row before any wallet or
+// hold, the order cancellation and expiry take them in, and checks it is
+// still in the status it was read in.
+func lockTransactionForConfirm(ctx context.Context, queries gen.Querier, transaction gen.Transaction) error {
+	locked, err := queries.GetTransactionByIDForUpdate(ctx, transaction.ID)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("lock transaction: %w", err))
+	}
+	if locked.Status != transaction.Status {
+		return utils.BadRequestErr(fmt.Sprintf("transaction is no longer in %s status", transaction.Status))
+	}
+	return nil
+}
+
+// recordTransactionCreated audits a new transaction row. Money moves later
+// and is audited by the ledger and status changes.
+func recordTransactionCreated(ctx context.Context, queries gen.Querier, transaction gen.Transaction) error {
+	after := map[string]any{
+		"type":       transaction.Type,
+		"status":     transaction.Status,
+		"amount":     transaction.Amount,
+		"currency":   transaction.Currency,
+		"fee_amount": transaction.FeeAmount,
+	}
+	if transaction.FromWalletID.Valid {
+		after["from_wallet_id"] = transaction.FromWalletID.String
+	}
+	if transaction.ToWalletID.Valid {
+		after["to_wallet_id"] = transaction.ToWalletID.String
+	}
+	if transaction.ParentTransactionID.Valid {
+		after["parent_transaction_id"] = transaction.ParentTransactionID.String
+	}
+
+	return recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "transaction.created",
+		EntityType: auditEntityTransaction,
+		EntityID:   transaction.ID,
+		After:      after,
+	})
+}
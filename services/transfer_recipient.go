@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+)
+
+// createTransferRecipient stores the account an external transfer pays out
+// to, with the name and country name enquiry resolved for it.
+func createTransferRecipient(ctx context.Context, queries gen.Querier, fields fieldCipher, transactionID string, accountNumber string, bankCode string, currency string, enquiry *NameEnquiryResult) error {
+	sealed, err := fields.sealRecipientAccountNumber(accountNumber)
+	if err != nil {
+		return err
+	}
+
+	_, err = queries.CreateTransferRecipient(ctx, gen.CreateTransferRecipientParams{
+		TransactionID: transactionID,
+		AccountNumber: sealed,
+		BankCode:      bankCode,
+		AccountName:   sql.NullString{String: enquiry.AccountName, Valid: enquiry.AccountName != ""},
+		Country:       sql.NullString{String: enquiry.Country, Valid: enquiry.Country != ""},
+		Currency:      currency,
+	})
+	if err != nil {
+		return fmt.Errorf("create transfer recipient: %w", err)
+	}
+	return nil
+}
+
+// loadTransferRecipient returns the account an external transfer pays out to.
+// Transfers initiated before transfer_recipients existed whose details were
+// already encrypted could not be moved by the migration, so their details are
+// still read from provider_reference.
+func loadTransferRecipient(ctx context.Context, queries gen.Querier, fields fieldCipher, transaction gen.Transaction) (*models.TransferRecipient, error) {
+	row, err := queries.GetTransferRecipientByTransactionID(ctx, transaction.ID)
+	if err == nil {
+		return mapTransferRecipient(fields, row)
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("get transfer recipient: %w", err)
+	}
+
+	recipientDetails, err := fields.openRecipientDetails(transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	accountNumber, bankCode := recipientDetails["account_number"], recipientDetails["bank_code"]
+	if accountNumber == "" || bankCode == "" {
+		return nil, fmt.Errorf("account_number or bank_code not found in recipient details")
+	}
+	return &models.TransferRecipient{
+		AccountNumber: accountNumber,
+		BankCode:      bankCode,
+		Currency:      transaction.Currency,
+	}, nil
+}
+
+// attachTransferRecipients sets Recipient on the external transfers among
+// transactions with a single query.
+func attachTransferRecipients(ctx context.Context, queries gen.Querier, fields fieldCipher, transactions []*models.Transaction) error {
+	byID := make(map[string]*models.Transaction)
+	ids := make([]string, 0, len(transactions))
+	for _, transaction := range transactions {
+		if transaction.Type != models.TransactionTypeExternal {
+			continue
+		}
+		byID[transaction.ID] = transaction
+		ids = append(ids, transaction.ID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := queries.ListTransferRecipientsByTransactionIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("list transfer recipients: %w", err)
+	}
+
+	for _, row := range rows {
+		recipient, err := mapTransferRecipient(fields, row)
+		if err != nil {
+			return err
+		}
+		if transaction, ok := byID[row.TransactionID]; ok {
+			transaction.Recipient = recipient
+		}
+	}
+	return nil
+}
+
+func mapTransferRecipient(fields fieldCipher, row gen.TransferRecipient) (*models.TransferRecipient, error) {
+	accountNumber, err := fields.openRecipientAccountNumber(row.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	recipient := &models.TransferRecipient{
+		AccountNumber: accountNumber,
+		BankCode:      row.BankCode,
+		Currency:      row.Currency,
+	}
+	if row.AccountName.Valid {
+		recipient.AccountName = &row.AccountName.String
+	}
+	if row.Country.Valid {
+		recipient.Country = &row.Country.String
+	}
+	return recipient, nil
+}
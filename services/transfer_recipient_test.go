@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testTransferRecipient(transactionID string) gen.TransferRecipient {
+	return gen.TransferRecipient{
+		ID:            "tr_" + transactionID,
+		TransactionID: transactionID,
+		AccountNumber: "9999999999",
+		BankCode:      "044",
+		AccountName:   sql.NullString{String: "Mock Account Holder 9999", Valid: true},
+		Country:       sql.NullString{String: "US", Valid: true},
+		Currency:      "USD",
+	}
+}
+
+func TestCreateTransferRecipient(t *testing.T) {
+	fields := testFieldCipher(t, "k1")
+	mockQueries := new(mocks.MockQuerier)
+
+	mockQueries.On("CreateTransferRecipient", mock.Anything, mock.MatchedBy(func(arg gen.CreateTransferRecipientParams) bool {
+		accountNumber, err := fields.openRecipientAccountNumber(arg.AccountNumber)
+		return err == nil && accountNumber == "9999999999" &&
+			arg.AccountNumber != "9999999999" &&
+			arg.TransactionID == "tx_1" &&
+			arg.BankCode == "044" &&
+			arg.AccountName == sql.NullString{String: "Mock Account Holder 9999", Valid: true} &&
+			arg.Country == sql.NullString{String: "US", Valid: true} &&
+			arg.Currency == "EUR"
+	})).Return(gen.TransferRecipient{}, nil)
+
+	err := createTransferRecipient(context.Background(), mockQueries, fields, "tx_1", "9999999999", "044", "EUR", &NameEnquiryResult{
+		AccountName: "Mock Account Holder 9999",
+		Country:     "US",
+	})
+
+	require.NoError(t, err)
+	mockQueries.AssertExpectations(t)
+}
+
+func TestLoadTransferRecipient(t *testing.T) {
+	ctx := context.Background()
+	fields := testFieldCipher(t, "k1")
+
+	t.Run("stored recipient", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		row := testTransferRecipient("tx_1")
+		sealed, err := fields.sealRecipientAccountNumber(row.AccountNumber)
+		require.NoError(t, err)
+		row.AccountNumber = sealed
+		mockQueries.On("GetTransferRecipientByTransactionID", mock.Anything, "tx_1").Return(row, nil)
+
+		recipient, err := loadTransferRecipient(ctx, mockQueries, fields, gen.Transaction{ID: "tx_1", Type: "external"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "9999999999", recipient.AccountNumber)
+		assert.Equal(t, "044", recipient.BankCode)
+		assert.Equal(t, "Mock Account Holder 9999", *recipient.AccountName)
+		assert.Equal(t, "US", *recipient.Country)
+	})
+
+	t.Run("falls back to legacy provider_reference", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("GetTransferRecipientByTransactionID", mock.Anything, "tx_1").Return(nil, sql.ErrNoRows)
+
+		recipient, err := loadTransferRecipient(ctx, mockQueries, fields, gen.Transaction{
+			ID:                "tx_1",
+			Type:              "external",
+			Currency:          "GBP",
+			ProviderReference: sql.NullString{String: sealLegacyRecipientDetails(t, fields, "0123456789", "058"), Valid: true},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, &models.TransferRecipient{AccountNumber: "0123456789", BankCode: "058", Currency: "GBP"}, recipient)
+	})
+
+	t.Run("no recipient", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("GetTransferRecipientByTransactionID", mock.Anything, "tx_1").Return(nil, sql.ErrNoRows)
+
+		_, err := loadTransferRecipient(ctx, mockQueries, fields, gen.Transaction{ID: "tx_1", Type: "external"})
+		assert.Error(t, err)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("GetTransferRecipientByTransactionID", mock.Anything, "tx_1").Return(nil, errors.New("db down"))
+
+		_, err := loadTransferRecipient(ctx, mockQueries, fields, gen.Transaction{ID: "tx_1", Type: "external"})
+		assert.Error(t, err)
+	})
+}
+
+func TestAttachTransferRecipients(t *testing.T) {
+	mockQueries := new(mocks.MockQuerier)
+	mockQueries.On("ListTransferRecipientsByTransactionIDs", mock.Anything, []string{"tx_1", "tx_3"}).
+		Return([]gen.TransferRecipient{testTransferRecipient("tx_1")}, nil)
+
+	transactions := []*models.Transaction{
+		{ID: "tx_1", Type: models.TransactionTypeExternal},
+		{ID: "tx_2", Type: models.TransactionTypeInternal},
+		{ID: "tx_3", Type: models.TransactionTypeExternal},
+	}
+
+	err := attachTransferRecipients(context.Background(), mockQueries, fieldCipher{}, transactions)
+
+	require.NoError(t, err)
+	require.NotNil(t, transactions[0].Recipient)
+	assert.Equal(t, "9999999999", transactions[0].Recipient.AccountNumber)
+	assert.Nil(t, transactions[1].Recipient)
+	assert.Nil(t, transactions[2].Recipient)
+
+	t.Run("no external transfers", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		err := attachTransferRecipients(context.Background(), mockQueries, fieldCipher{}, []*models.Transaction{{ID: "tx_2", Type: models.TransactionTypeInternal}})
+		require.NoError(t, err)
+		mockQueries.AssertNotCalled(t, "ListTransferRecipientsByTransactionIDs", mock.Anything, mock.Anything)
+	})
+}
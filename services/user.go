@@ -0,0 +1,582 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/lib/pq"
+)
+
+const defaultKYCReviewLimit = int32(50)
+
+// kycDocumentTypes maps each accepted document type to what it proves.
+var kycDocumentTypes = map[string]models.KYCCategory{
+	"passport":        models.KYCCategoryIdentity,
+	"national_id":     models.KYCCategoryIdentity,
+	"drivers_license": models.KYCCategoryIdentity,
+	"utility_bill":    models.KYCCategoryAddress,
+	"bank_statement":  models.KYCCategoryAddress,
+}
+
+// KYCPolicy maps each KYC tier to what it unlocks. The zero value leaves KYC
+// checks off, so every user may use every currency.
+type KYCPolicy struct {
+	Enabled bool
+	Tiers   map[models.KYCTier]KYCTierPolicy
+}
+
+// KYCTierPolicy is what one KYC tier unlocks. An empty LimitTier leaves the
+// user's transfer limit tier as it is when they reach the tier.
+type KYCTierPolicy struct {
+	Currencies []money.Currency
+	LimitTier  string
+}
+
+// allowedCurrencies lists the currencies a user at tier may hold and send.
+func (p KYCPolicy) allowedCurrencies(tier models.KYCTier) []money.Currency {
+	if !p.Enabled {
+		return []money.Currency{money.USD, money.EUR, money.GBP}
+	}
+	return p.Tiers[tier].Currencies
+}
+
+// checkCurrency rejects a currency the user's KYC tier does not cover.
+func (p KYCPolicy) checkCurrency(user gen.User, currency money.Currency) error {
+	tier := models.KYCTier(user.KycTier)
+	if slices.Contains(p.allowedCurrencies(tier), currency) {
+		return nil
+	}
+	return utils.KYCRequiredErr(fmt.Sprintf("%s is not available at KYC tier %d", currency, tier))
+}
+
+// kycTierFor is the tier a user's approved document categories earn: identity
+// alone is tier 1, and identity with proof of address is tier 2. Proof of
+// address alone earns nothing.
+func kycTierFor(categories []string) models.KYCTier {
+	identity := slices.Contains(categories, string(models.KYCCategoryIdentity))
+	address := slices.Contains(categories, string(models.KYCCategoryAddress))
+	switch {
+	case identity && address:
+		return models.KYCTierFull
+	case identity:
+		return models.KYCTierIdentity
+	default:
+		return models.KYCTierUnverified
+	}
+}
+
+type UserService interface {
+	RegisterUser(ctx context.Context, userID string, profile models.UserProfileUpdate) (*models.UserProfile, error)
+	GetProfile(ctx context.Context, userID string) (*models.UserProfile, error)
+	UpdateProfile(ctx context.Context, userID string, update models.UserProfileUpdate) (*models.UserProfile, error)
+	SubmitKYCDocument(ctx context.Context, userID string, submission models.KYCSubmission) (*models.KYCDocument, error)
+	ListKYCDocuments(ctx context.Context, status models.KYCDocumentStatus, limit int32) ([]*models.KYCDocument, error)
+	ReviewKYCDocument(ctx context.Context, documentID string, adminUserID string, decision models.KYCDocumentStatus, note string) (*models.KYCDocument, error)
+}
+
+type userService struct {
+	queries gen.Querier
+	db      *sql.DB
+	fields  fieldCipher
+	kyc     KYCPolicy
+}
+
+func newUserService(queries gen.Querier, db *sql.DB, fields fieldCipher, kyc KYCPolicy) UserService {
+	return &userService{
+		queries: queries,
+		db:      db,
+		fields:  fields,
+		kyc:     kyc,
+	}
+}
+
+// RegisterUser creates the profile for an authenticated user ID that has no
+// user row yet. New users start at KYC tier 0.
+func (us *userService) RegisterUser(ctx context.Context, userID string, profile models.UserProfileUpdate) (*models.UserProfile, error) {
+	if profile.Name == nil || *profile.Name == "" {
+		return nil, utils.BadRequestErr("name is required")
+	}
+	if err := checkDateOfBirth(profile.DateOfBirth); err != nil {
+		return nil, err
+	}
+
+	tx, err := us.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := us.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = us.queries
+	}
+
+	user, err := queries.CreateUser(ctx, gen.CreateUserParams{
+		UserID:      userID,
+		Name:        toNullString(profile.Name),
+		Email:       toNullString(profile.Email),
+		Phone:       toNullString(profile.Phone),
+		Country:     toNullString(profile.Country),
+		DateOfBirth: toNullTime(profile.DateOfBirth),
+	})
+	if err != nil {
+		return nil, userWriteError("create user", err)
+	}
+
+	if err := recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "user.registered",
+		EntityType: auditEntityUser,
+		EntityID:   user.UserID,
+		After:      userSnapshot(user),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	return us.profile(user, nil), nil
+}
+
+func (us *userService) GetProfile(ctx context.Context, userID string) (*models.UserProfile, error) {
+	user, err := us.getUser(ctx, us.queries, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := us.queries.ListKYCDocumentsByUser(ctx, userID)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("list kyc documents: %w", err))
+	}
+
+	documents := make([]*models.KYCDocument, 0, len(rows))
+	for _, row := range rows {
+		document, err := us.mapKYCDocument(row)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+
+	return us.profile(user, documents), nil
+}
+
+// UpdateProfile changes the user's profile. Name, date of birth and country
+// are what identity documents are checked against, so they are fixed once
+// the user reaches KYC tier 1; email and phone can always change.
+func (us *userService) UpdateProfile(ctx context.Context, userID string, update models.UserProfileUpdate) (*models.UserProfile, error) {
+	if update == (models.UserProfileUpdate{}) {
+		return nil, utils.BadRequestErr("no profile fields to update")
+	}
+	if err := checkDateOfBirth(update.DateOfBirth); err != nil {
+		return nil, err
+	}
+
+	existing, err := us.getUser(ctx, us.queries, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.KycTier >= int16(models.KYCTierIdentity) && (update.Name != nil || update.DateOfBirth != nil || update.Country != nil) {
+		return nil, utils.BadRequestErr("name, date of birth and country cannot change after identity verification")
+	}
+
+	tx, err := us.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := us.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = us.queries
+	}
+
+	user, err := queries.UpdateUserProfile(ctx, gen.UpdateUserProfileParams{
+		Name:        toNullString(update.Name),
+		Email:       toNullString(update.Email),
+		Phone:       toNullString(update.Phone),
+		Country:     toNullString(update.Country),
+		DateOfBirth: toNullTime(update.DateOfBirth),
+		UserID:      userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.NotFoundErr("user not found")
+		}
+		return nil, userWriteError("update user", err)
+	}
+
+	if err := recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "user.updated",
+		EntityType: auditEntityUser,
+		EntityID:   user.UserID,
+		Before:     userSnapshot(existing),
+		After:      userSnapshot(user),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	return us.GetProfile(ctx, userID)
+}
+
+// SubmitKYCDocument queues a document for review. A category that is
+// already verified, or that has a document pending review, is rejected.
+func (us *userService) SubmitKYCDocument(ctx context.Context, userID string, submission models.KYCSubmission) (*models.KYCDocument, error) {
+	category, ok := kycDocumentTypes[submission.DocumentType]
+	if !ok {
+		return nil, utils.BadRequestErr(fmt.Sprintf("unsupported document type %q", submission.DocumentType))
+	}
+
+	if _, err := us.getUser(ctx, us.queries, userID); err != nil {
+		return nil, err
+	}
+
+	approved, err := us.queries.ListApprovedKYCCategories(ctx, userID)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("list approved kyc categories: %w", err))
+	}
+	if slices.Contains(approved, string(category)) {
+		return nil, utils.BadRequestErr(fmt.Sprintf("%s is already verified", category))
+	}
+
+	sealed, err := us.fields.sealKYCDocumentNumber(submission.DocumentNumber)
+	if err != nil {
+		return nil, utils.ServerErr(err)
+	}
+
+	tx, err := us.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := us.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = us.queries
+	}
+
+	row, err := queries.CreateKYCDocument(ctx, gen.CreateKYCDocumentParams{
+		UserID:         userID,
+		Category:       string(category),
+		DocumentType:   submission.DocumentType,
+		DocumentNumber: sealed,
+		IssuingCountry: submission.IssuingCountry,
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, utils.DuplicateKeyErr(fmt.Sprintf("an %s document is already pending review", category))
+		}
+		return nil, utils.ServerErr(fmt.Errorf("create kyc document: %w", err))
+	}
+
+	if err := recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "kyc.submitted",
+		EntityType: auditEntityKYCDocument,
+		EntityID:   row.ID,
+		After:      kycDocumentSnapshot(row),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	return us.mapKYCDocument(row)
+}
+
+// ListKYCDocuments returns documents in status, oldest first, for the review
+// queue.
+func (us *userService) ListKYCDocuments(ctx context.Context, status models.KYCDocumentStatus, limit int32) ([]*models.KYCDocument, error) {
+	if limit <= 0 {
+		limit = defaultKYCReviewLimit
+	}
+
+	rows, err := us.queries.ListKYCDocumentsByStatus(ctx, gen.ListKYCDocumentsByStatusParams{
+		Status:   string(status),
+		RowLimit: limit,
+	})
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("list kyc documents: %w", err))
+	}
+
+	documents := make([]*models.KYCDocument, 0, len(rows))
+	for _, row := range rows {
+		document, err := us.mapKYCDocument(row)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	return documents, nil
+}
+
+// ReviewKYCDocument records an admin's decision on a pending document. An
+// approval raises the user's KYC tier, and their transfer limit tier with it,
+// when the approved categories now earn a higher tier. Tiers are never
+// lowered here.
+func (us *userService) ReviewKYCDocument(ctx context.Context, documentID string, adminUserID string, decision models.KYCDocumentStatus, note string) (*models.KYCDocument, error) {
+	if decision != models.KYCDocumentApproved && decision != models.KYCDocumentRejected {
+		return nil, utils.BadRequestErr("decision must be approved or rejected")
+	}
+
+	tx, err := us.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := us.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = us.queries
+	}
+
+	existing, err := queries.GetKYCDocumentByIDForUpdate(ctx, documentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.NotFoundErr("kyc document not found")
+		}
+		return nil, utils.ServerErr(fmt.Errorf("lock kyc document: %w", err))
+	}
+	if existing.Status != string(models.KYCDocumentPending) {
+		return nil, utils.BadRequestErr("kyc document has already been reviewed")
+	}
+
+	row, err := queries.ReviewKYCDocument(ctx, gen.ReviewKYCDocumentParams{
+		Status:     string(decision),
+		ReviewerID: sql.NullString{String: adminUserID, Valid: adminUserID != ""},
+		ReviewNote: sql.NullString{String: note, Valid: note != ""},
+		ID:         existing.ID,
+	})
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("review kyc document: %w", err))
+	}
+
+	if err := recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "kyc." + string(decision),
+		EntityType: auditEntityKYCDocument,
+		EntityID:   row.ID,
+		Before:     kycDocumentSnapshot(existing),
+		After:      kycDocumentSnapshot(row),
+	}); err != nil {
+		return nil, err
+	}
+
+	if decision == models.KYCDocumentApproved {
+		if err := us.raiseKYCTier(ctx, queries, row.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	return us.mapKYCDocument(row)
+}
+
+// raiseKYCTier moves the user to the tier their approved documents earn, if
+// that is higher than their current tier.
+func (us *userService) raiseKYCTier(ctx context.Context, queries gen.Querier, userID string) error {
+	user, err := us.getUser(ctx, queries, userID)
+	if err != nil {
+		return err
+	}
+
+	categories, err := queries.ListApprovedKYCCategories(ctx, userID)
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("list approved kyc categories: %w", err))
+	}
+
+	tier := kycTierFor(categories)
+	if int16(tier) <= user.KycTier {
+		return nil
+	}
+
+	limitTier := us.kyc.Tiers[tier].LimitTier
+	if limitTier == "" {
+		limitTier = user.Tier
+	}
+
+	updated, err := queries.UpdateUserKYCTier(ctx, gen.UpdateUserKYCTierParams{
+		KycTier: int16(tier),
+		Tier:    limitTier,
+		UserID:  userID,
+	})
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("update kyc tier: %w", err))
+	}
+	if updated == 0 {
+		return nil
+	}
+
+	utils.Logger.Info().
+		Str("event", "kyc_tier_raised").
+		Str("user_id", userID).
+		Int("kyc_tier", int(tier)).
+		Str("limit_tier", limitTier).
+		Str("trace_id", utils.TraceIDFromContext(ctx)).
+		Msg("kyc tier raised")
+
+	return recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "user.kyc_tier_raised",
+		EntityType: auditEntityUser,
+		EntityID:   userID,
+		Before:     map[string]any{"kyc_tier": user.KycTier, "tier": user.Tier},
+		After:      map[string]any{"kyc_tier": int16(tier), "tier": limitTier},
+	})
+}
+
+func (us *userService) getUser(ctx context.Context, queries gen.Querier, userID string) (gen.User, error) {
+	user, err := queries.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return gen.User{}, utils.NotFoundErr("user not found")
+		}
+		return gen.User{}, utils.ServerErr(fmt.Errorf("get user: %w", err))
+	}
+	return user, nil
+}
+
+func (us *userService) profile(user gen.User, documents []*models.KYCDocument) *models.UserProfile {
+	currencies := us.kyc.allowedCurrencies(models.KYCTier(user.KycTier))
+	allowed := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		allowed = append(allowed, currency.String())
+	}
+
+	return &models.UserProfile{
+		User:              mapUser(user),
+		AllowedCurrencies: allowed,
+		Documents:         documents,
+	}
+}
+
+func (us *userService) mapKYCDocument(row gen.KycDocument) (*models.KYCDocument, error) {
+	documentNumber, err := us.fields.openKYCDocumentNumber(row.DocumentNumber)
+	if err != nil {
+		return nil, utils.ServerErr(err)
+	}
+
+	document := &models.KYCDocument{
+		ID:             row.ID,
+		UserID:         row.UserID,
+		Category:       models.KYCCategory(row.Category),
+		DocumentType:   row.DocumentType,
+		DocumentNumber: documentNumber,
+		IssuingCountry: row.IssuingCountry,
+		Status:         models.KYCDocumentStatus(row.Status),
+		ReviewerID:     nullStringPtr(row.ReviewerID),
+		ReviewNote:     nullStringPtr(row.ReviewNote),
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}
+	if row.ReviewedAt.Valid {
+		document.ReviewedAt = &row.ReviewedAt.Time
+	}
+	return document, nil
+}
+
+func mapUser(row gen.User) models.User {
+	user := models.User{
+		ID:        row.UserID,
+		Name:      nullStringPtr(row.Name),
+		Email:     nullStringPtr(row.Email),
+		Phone:     nullStringPtr(row.Phone),
+		Country:   nullStringPtr(row.Country),
+		KYCTier:   models.KYCTier(row.KycTier),
+		LimitTier: row.Tier,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+	if row.DateOfBirth.Valid {
+		user.DateOfBirth = &row.DateOfBirth.Time
+	}
+	return user
+}
+
+// checkDateOfBirth rejects a date of birth that is not in the past.
+func checkDateOfBirth(dateOfBirth *time.Time) error {
+	if dateOfBirth != nil && !dateOfBirth.Before(time.Now()) {
+		return utils.BadRequestErr("date of birth must be in the past")
+	}
+	return nil
+}
+
+// userWriteError maps a failed insert or update of a user row. The users
+// primary key and the email index are the only unique constraints.
+func userWriteError(operation string, err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		if pqErr.Constraint == "idx_users_email" {
+			return utils.DuplicateKeyErr("email is already registered")
+		}
+		return utils.DuplicateKeyErr("user already registered")
+	}
+	return utils.ServerErr(fmt.Errorf("%s: %w", operation, err))
+}
+
+// toNullString is the inverse of nullStringPtr: nil becomes null.
+func toNullString(value *string) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *value, Valid: true}
+}
+
+func toNullTime(value *time.Time) sql.NullTime {
+	if value == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *value, Valid: true}
+}
+
+// userSnapshot is what the audit log records about a user's profile.
+func userSnapshot(row gen.User) map[string]any {
+	snapshot := map[string]any{
+		"name":     row.Name.String,
+		"email":    row.Email.String,
+		"phone":    row.Phone.String,
+		"country":  row.Country.String,
+		"kyc_tier": row.KycTier,
+	}
+	if row.DateOfBirth.Valid {
+		snapshot["date_of_birth"] = row.DateOfBirth.Time.Format(time.DateOnly)
+	}
+	return snapshot
+}
+
+// kycDocumentSnapshot is what the audit log records about a KYC document. The
+// document number is left out so the log holds no plaintext copy of it.
+func kycDocumentSnapshot(row gen.KycDocument) map[string]any {
+	return map[string]any{
+		"user_id":         row.UserID,
+		"category":        row.Category,
+		"document_type":   row.DocumentType,
+		"issuing_country": row.IssuingCountry,
+		"status":          row.Status,
+		"reviewer_id":     row.ReviewerID.String,
+	}
+}
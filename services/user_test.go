@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKYCTierFor(t *testing.T) {
+	assert.Equal(t, models.KYCTierUnverified, kycTierFor(nil))
+	assert.Equal(t, models.KYCTierUnverified, kycTierFor([]string{"address"}))
+	assert.Equal(t, models.KYCTierIdentity, kycTierFor([]string{"identity"}))
+	assert.Equal(t, models.KYCTierFull, kycTierFor([]string{"address", "identity"}))
+}
+
+func TestKYCPolicy_CheckCurrency(t *testing.T) {
+	policy := KYCPolicy{Enabled: true, Tiers: map[models.KYCTier]KYCTierPolicy{
+		models.KYCTierIdentity: {Currencies: []money.Currency{money.USD}},
+		models.KYCTierFull:     {Currencies: []money.Currency{money.USD, money.EUR, money.GBP}},
+	}}
+
+	err := policy.checkCurrency(gen.User{KycTier: 0}, money.USD)
+	assert.ErrorIs(t, err, utils.ErrKYCRequired)
+
+	assert.NoError(t, policy.checkCurrency(gen.User{KycTier: 1}, money.USD))
+
+	err = policy.checkCurrency(gen.User{KycTier: 1}, money.EUR)
+	assert.ErrorIs(t, err, utils.ErrKYCRequired)
+	assert.Contains(t, err.Error(), "EUR is not available at KYC tier 1")
+
+	assert.NoError(t, policy.checkCurrency(gen.User{KycTier: 2}, money.GBP))
+
+	// The zero value leaves KYC off.
+	assert.NoError(t, KYCPolicy{}.checkCurrency(gen.User{KycTier: 0}, money.GBP))
+}
+
+func TestUserService_RegisterUser(t *testing.T) {
+	t.Run("name is required", func(t *testing.T) {
+		us := &userService{queries: new(mocks.MockQuerier)}
+
+		_, err := us.RegisterUser(context.Background(), "user_3", models.UserProfileUpdate{})
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+	})
+
+	t.Run("date of birth in the future", func(t *testing.T) {
+		us := &userService{queries: new(mocks.MockQuerier)}
+		name := "Ada Lovelace"
+		tomorrow := time.Now().AddDate(0, 0, 1)
+
+		_, err := us.RegisterUser(context.Background(), "user_3", models.UserProfileUpdate{Name: &name, DateOfBirth: &tomorrow})
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+	})
+}
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	t.Run("nothing to update", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		us := &userService{queries: mockQueries}
+
+		_, err := us.UpdateProfile(context.Background(), "user_1", models.UserProfileUpdate{})
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		mockQueries.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("verified fields are fixed after identity verification", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		us := &userService{queries: mockQueries}
+		country := "FR"
+
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(gen.User{UserID: "user_1", KycTier: 1}, nil)
+
+		_, err := us.UpdateProfile(context.Background(), "user_1", models.UserProfileUpdate{Country: &country})
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		assert.Contains(t, err.Error(), "cannot change after identity verification")
+		mockQueries.AssertNotCalled(t, "UpdateUserProfile", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		us := &userService{queries: mockQueries}
+		email := "ada@example.com"
+
+		mockQueries.On("GetUserByID", mock.Anything, "user_9").Return(nil, sql.ErrNoRows)
+
+		_, err := us.UpdateProfile(context.Background(), "user_9", models.UserProfileUpdate{Email: &email})
+
+		assert.ErrorIs(t, err, utils.ErrNotFound)
+	})
+}
+
+func TestUserService_SubmitKYCDocument(t *testing.T) {
+	t.Run("unsupported document type", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		us := &userService{queries: mockQueries}
+
+		_, err := us.SubmitKYCDocument(context.Background(), "user_1", models.KYCSubmission{DocumentType: "library_card"})
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		mockQueries.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("category already verified", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		us := &userService{queries: mockQueries}
+
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(gen.User{UserID: "user_1", KycTier: 1}, nil)
+		mockQueries.On("ListApprovedKYCCategories", mock.Anything, "user_1").Return([]string{"identity"}, nil)
+
+		_, err := us.SubmitKYCDocument(context.Background(), "user_1", models.KYCSubmission{
+			DocumentType:   "passport",
+			DocumentNumber: "123456789",
+			IssuingCountry: "GB",
+		})
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		assert.Contains(t, err.Error(), "identity is already verified")
+		mockQueries.AssertNotCalled(t, "CreateKYCDocument", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserService_ReviewKYCDocument(t *testing.T) {
+	mockQueries := new(mocks.MockQuerier)
+	us := &userService{queries: mockQueries}
+
+	_, err := us.ReviewKYCDocument(context.Background(), "doc_1", "admin_1", models.KYCDocumentPending, "")
+
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+	mockQueries.AssertNotCalled(t, "GetKYCDocumentByIDForUpdate", mock.Anything, mock.Anything)
+}
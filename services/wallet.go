@@ -3,12 +3,17 @@ package services
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/IfedayoAwe/payment-processing-service/db/gen"
 	"github.com/IfedayoAwe/payment-processing-service/models"
 	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/providers"
 	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/lib/pq"
 )
 
 type WalletService interface {
@@ -18,17 +23,82 @@ type WalletService interface {
 	GetUserWallets(ctx context.Context, userID string) ([]*models.WalletWithBankAccount, error)
 	LockWalletForUpdate(ctx context.Context, tx *sql.Tx, walletID string) (*models.Wallet, error)
 	LockWalletByUserAndCurrency(ctx context.Context, tx *sql.Tx, userID string, currency money.Currency) (*models.Wallet, error)
+	OpenWallet(ctx context.Context, userID string, currency money.Currency) (*models.WalletWithBankAccount, error)
+	FreezeWallet(ctx context.Context, walletID string, direction models.FreezeDirection, reason string) (*models.Wallet, error)
+	UnfreezeWallet(ctx context.Context, walletID string, reason string) (*models.Wallet, error)
+	CloseWallet(ctx context.Context, walletID string, reason string) (*models.Wallet, error)
+}
+
+// VirtualAccountPolicy describes the account numbers generated for new
+// wallets: BIN, then a zero-padded sequence number, then a Luhn check digit,
+// Length digits in all, held at the directory bank BankCode.
+type VirtualAccountPolicy struct {
+	BIN      string
+	Length   int
+	BankCode string
+}
+
+func (p VirtualAccountPolicy) validate() error {
+	if p.BIN == "" || strings.Trim(p.BIN, "0123456789") != "" {
+		return fmt.Errorf("virtual account BIN %q must be digits", p.BIN)
+	}
+	if p.Length-len(p.BIN)-1 < 1 {
+		return fmt.Errorf("virtual account length %d leaves no room after BIN %s and a check digit", p.Length, p.BIN)
+	}
+	if p.BankCode == "" {
+		return errors.New("virtual account bank code is required")
+	}
+	return nil
+}
+
+// accountNumber formats the sequence value as an account number. It fails
+// once the sequence no longer fits between the BIN and the check digit.
+func (p VirtualAccountPolicy) accountNumber(sequence int64) (string, error) {
+	width := p.Length - len(p.BIN) - 1
+	digits := strconv.FormatInt(sequence, 10)
+	if sequence < 0 || len(digits) > width {
+		return "", fmt.Errorf("virtual account range %s is exhausted", p.BIN)
+	}
+	body := p.BIN + strings.Repeat("0", width-len(digits)) + digits
+	return body + luhnCheckDigit(body), nil
+}
+
+// luhnCheckDigit returns the digit that makes body followed by it pass the
+// Luhn check.
+func luhnCheckDigit(body string) string {
+	sum := 0
+	double := true
+	for i := len(body) - 1; i >= 0; i-- {
+		digit := int(body[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return strconv.Itoa((10 - sum%10) % 10)
 }
 
 type walletService struct {
-	queries gen.Querier
-	db      *sql.DB
+	queries        gen.Querier
+	db             *sql.DB
+	fields         fieldCipher
+	processor      *providers.Processor
+	virtualAccount VirtualAccountPolicy
+	kyc            KYCPolicy
 }
 
-func newWalletService(queries gen.Querier, db *sql.DB) WalletService {
+func newWalletService(queries gen.Querier, db *sql.DB, fields fieldCipher, processor *providers.Processor, virtualAccount VirtualAccountPolicy, kyc KYCPolicy) WalletService {
 	return &walletService{
-		queries: queries,
-		db:      db,
+		queries:        queries,
+		db:             db,
+		fields:         fields,
+		processor:      processor,
+		virtualAccount: virtualAccount,
+		kyc:            kyc,
 	}
 }
 
@@ -41,16 +111,11 @@ func (ws *walletService) GetWalletByID(ctx context.Context, walletID string) (*m
 		return nil, utils.ServerErr(fmt.Errorf("get wallet: %w", err))
 	}
 
-	return &models.Wallet{
-		ID:        wallet.ID,
-		UserID:    wallet.UserID,
-		Currency:  wallet.Currency,
-		Balance:   wallet.Balance,
-		CreatedAt: wallet.CreatedAt,
-		UpdatedAt: wallet.UpdatedAt,
-	}, nil
+	return mapWallet(wallet), nil
 }
 
+// GetWalletByUserAndCurrency returns the user's open wallet in currency.
+// Closed wallets are not returned.
 func (ws *walletService) GetWalletByUserAndCurrency(ctx context.Context, userID string, currency money.Currency) (*models.Wallet, error) {
 	wallet, err := ws.queries.GetWalletByUserAndCurrency(ctx, gen.GetWalletByUserAndCurrencyParams{
 		UserID:   userID,
@@ -63,20 +128,7 @@ func (ws *walletService) GetWalletByUserAndCurrency(ctx context.Context, userID
 		return nil, utils.ServerErr(fmt.Errorf("get wallet: %w", err))
 	}
 
-	var bankAccountID *string
-	if wallet.BankAccountID.Valid {
-		bankAccountID = &wallet.BankAccountID.String
-	}
-
-	return &models.Wallet{
-		ID:            wallet.ID,
-		UserID:        wallet.UserID,
-		BankAccountID: bankAccountID,
-		Currency:      wallet.Currency,
-		Balance:       wallet.Balance,
-		CreatedAt:     wallet.CreatedAt,
-		UpdatedAt:     wallet.UpdatedAt,
-	}, nil
+	return mapWallet(wallet), nil
 }
 
 func (ws *walletService) GetWalletByBankAccount(ctx context.Context, bankAccountID string) (*models.Wallet, error) {
@@ -88,20 +140,7 @@ func (ws *walletService) GetWalletByBankAccount(ctx context.Context, bankAccount
 		return nil, utils.ServerErr(fmt.Errorf("get wallet: %w", err))
 	}
 
-	var bankAccountIDPtr *string
-	if wallet.BankAccountID.Valid {
-		bankAccountIDPtr = &wallet.BankAccountID.String
-	}
-
-	return &models.Wallet{
-		ID:            wallet.ID,
-		UserID:        wallet.UserID,
-		BankAccountID: bankAccountIDPtr,
-		Currency:      wallet.Currency,
-		Balance:       wallet.Balance,
-		CreatedAt:     wallet.CreatedAt,
-		UpdatedAt:     wallet.UpdatedAt,
-	}, nil
+	return mapWallet(wallet), nil
 }
 
 func (ws *walletService) GetUserWallets(ctx context.Context, userID string) ([]*models.WalletWithBankAccount, error) {
@@ -115,7 +154,11 @@ func (ws *walletService) GetUserWallets(ctx context.Context, userID string) ([]*
 		var accountNumber, bankName, bankCode, accountName, provider *string
 
 		if row.AccountNumber.Valid {
-			accountNumber = &row.AccountNumber.String
+			number, err := ws.fields.openAccountNumber(row.AccountNumber.String)
+			if err != nil {
+				return nil, utils.ServerErr(err)
+			}
+			accountNumber = &number
 		}
 		if row.BankName.Valid {
 			bankName = &row.BankName.String
@@ -131,16 +174,19 @@ func (ws *walletService) GetUserWallets(ctx context.Context, userID string) ([]*
 		}
 
 		wallets = append(wallets, &models.WalletWithBankAccount{
-			ID:            row.ID,
-			Currency:      row.Currency,
-			Balance:       row.Balance,
-			AccountNumber: accountNumber,
-			BankName:      bankName,
-			BankCode:      bankCode,
-			AccountName:   accountName,
-			Provider:      provider,
-			CreatedAt:     row.CreatedAt,
-			UpdatedAt:     row.UpdatedAt,
+			ID:               row.ID,
+			Currency:         row.Currency,
+			Balance:          row.Balance,
+			AvailableBalance: row.Balance - row.HeldBalance,
+			Status:           models.WalletStatus(row.Status),
+			FrozenDirection:  freezeDirection(row.FrozenDirection),
+			AccountNumber:    accountNumber,
+			BankName:         bankName,
+			BankCode:         bankCode,
+			AccountName:      accountName,
+			Provider:         provider,
+			CreatedAt:        row.CreatedAt,
+			UpdatedAt:        row.UpdatedAt,
 		})
 	}
 
@@ -162,20 +208,7 @@ func (ws *walletService) LockWalletForUpdate(ctx context.Context, tx *sql.Tx, wa
 		return nil, utils.ServerErr(fmt.Errorf("lock wallet: %w", err))
 	}
 
-	var bankAccountID *string
-	if wallet.BankAccountID.Valid {
-		bankAccountID = &wallet.BankAccountID.String
-	}
-
-	return &models.Wallet{
-		ID:            wallet.ID,
-		UserID:        wallet.UserID,
-		BankAccountID: bankAccountID,
-		Currency:      wallet.Currency,
-		Balance:       wallet.Balance,
-		CreatedAt:     wallet.CreatedAt,
-		UpdatedAt:     wallet.UpdatedAt,
-	}, nil
+	return mapWallet(wallet), nil
 }
 
 func (ws *walletService) LockWalletByUserAndCurrency(ctx context.Context, tx *sql.Tx, userID string, currency money.Currency) (*models.Wallet, error) {
@@ -196,18 +229,297 @@ func (ws *walletService) LockWalletByUserAndCurrency(ctx context.Context, tx *sq
 		return nil, utils.ServerErr(fmt.Errorf("lock wallet: %w", err))
 	}
 
-	var bankAccountID *string
+	return mapWallet(wallet), nil
+}
+
+// OpenWallet creates the user's wallet in currency with a new virtual bank
+// account in the user's name. A user has one open wallet per currency, in the
+// currencies their KYC tier allows.
+func (ws *walletService) OpenWallet(ctx context.Context, userID string, currency money.Currency) (*models.WalletWithBankAccount, error) {
+	if _, err := ws.GetWalletByUserAndCurrency(ctx, userID, currency); err == nil {
+		return nil, utils.DuplicateKeyErr(fmt.Sprintf("%s wallet already exists", currency))
+	} else if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	user, err := ws.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.NotFoundErr("user not found")
+		}
+		return nil, utils.ServerErr(fmt.Errorf("get user: %w", err))
+	}
+	if err := ws.kyc.checkCurrency(user, currency); err != nil {
+		return nil, err
+	}
+
+	bank, err := ws.queries.GetBank(ctx, ws.virtualAccount.BankCode)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.ServerErr(fmt.Errorf("virtual account bank %s is not in the bank directory", ws.virtualAccount.BankCode))
+		}
+		return nil, utils.ServerErr(fmt.Errorf("get bank: %w", err))
+	}
+	if !bank.Active || !mapBank(bank).SupportsCurrency(currency.String()) {
+		return nil, utils.BadRequestErr(fmt.Sprintf("%s wallets are not available", currency))
+	}
+
+	provider, err := ws.processor.SelectPayoutProvider(ctx, bank.Code, currency)
+	if err != nil {
+		return nil, utils.BadRequestErr(fmt.Sprintf("%s wallets are not available", currency))
+	}
+
+	tx, err := ws.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := ws.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ws.queries
+	}
+
+	sequence, err := queries.NextVirtualAccountNumber(ctx)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("next virtual account number: %w", err))
+	}
+	accountNumber, err := ws.virtualAccount.accountNumber(sequence)
+	if err != nil {
+		return nil, utils.ServerErr(err)
+	}
+	sealed, err := ws.fields.sealAccountNumber(accountNumber)
+	if err != nil {
+		return nil, utils.ServerErr(err)
+	}
+
+	account, err := queries.CreateBankAccount(ctx, gen.CreateBankAccountParams{
+		UserID:            userID,
+		BankName:          bank.Name,
+		BankCode:          bank.Code,
+		AccountNumber:     sealed,
+		AccountName:       user.Name,
+		Currency:          currency.String(),
+		Provider:          strings.ToLower(provider.Name()),
+		AccountNumberHash: ws.fields.accountNumberIndex(accountNumber),
+	})
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("create bank account: %w", err))
+	}
+
+	wallet, err := queries.CreateWallet(ctx, gen.CreateWalletParams{
+		UserID:        userID,
+		BankAccountID: sql.NullString{String: account.ID, Valid: true},
+		Currency:      currency.String(),
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, utils.DuplicateKeyErr(fmt.Sprintf("%s wallet already exists", currency))
+		}
+		return nil, utils.ServerErr(fmt.Errorf("create wallet: %w", err))
+	}
+
+	if err := recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "wallet.opened",
+		EntityType: auditEntityWallet,
+		EntityID:   wallet.ID,
+		After: map[string]any{
+			"user_id":         userID,
+			"currency":        wallet.Currency,
+			"bank_account_id": account.ID,
+			"bank_code":       account.BankCode,
+			"provider":        account.Provider,
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+
+	bankCode := account.BankCode
+	bankName := account.BankName
+	accountProvider := account.Provider
+	result := &models.WalletWithBankAccount{
+		ID:               wallet.ID,
+		Currency:         wallet.Currency,
+		Balance:          wallet.Balance,
+		AvailableBalance: wallet.Balance - wallet.HeldBalance,
+		Status:           models.WalletStatus(wallet.Status),
+		AccountNumber:    &accountNumber,
+		BankName:         &bankName,
+		BankCode:         &bankCode,
+		Provider:         &accountProvider,
+		CreatedAt:        wallet.CreatedAt,
+		UpdatedAt:        wallet.UpdatedAt,
+	}
+	if user.Name.Valid {
+		result.AccountName = &user.Name.String
+	}
+	return result, nil
+}
+
+// FreezeWallet blocks debits, credits or both on the wallet. Freezing a
+// frozen wallet replaces its direction and reason.
+func (ws *walletService) FreezeWallet(ctx context.Context, walletID string, direction models.FreezeDirection, reason string) (*models.Wallet, error) {
+	switch direction {
+	case models.FreezeDebits, models.FreezeCredits, models.FreezeBoth:
+	default:
+		return nil, utils.BadRequestErr("direction must be debits, credits or both")
+	}
+
+	return ws.updateStatus(ctx, walletID, "wallet.frozen", func(wallet *models.Wallet) (gen.UpdateWalletStatusParams, error) {
+		if wallet.Status == models.WalletStatusClosed {
+			return gen.UpdateWalletStatusParams{}, utils.BadRequestErr("wallet is closed")
+		}
+		return gen.UpdateWalletStatusParams{
+			Status:          string(models.WalletStatusFrozen),
+			FrozenDirection: sql.NullString{String: string(direction), Valid: true},
+			StatusReason:    sql.NullString{String: reason, Valid: reason != ""},
+		}, nil
+	})
+}
+
+func (ws *walletService) UnfreezeWallet(ctx context.Context, walletID string, reason string) (*models.Wallet, error) {
+	return ws.updateStatus(ctx, walletID, "wallet.unfrozen", func(wallet *models.Wallet) (gen.UpdateWalletStatusParams, error) {
+		if wallet.Status != models.WalletStatusFrozen {
+			return gen.UpdateWalletStatusParams{}, utils.BadRequestErr("wallet is not frozen")
+		}
+		return gen.UpdateWalletStatusParams{
+			Status:       string(models.WalletStatusActive),
+			StatusReason: sql.NullString{String: reason, Valid: reason != ""},
+		}, nil
+	})
+}
+
+// CloseWallet closes a wallet with nothing in it. Funds held for initiated
+// transfers count, so a wallet cannot close under a pending transfer. A
+// closed wallet cannot be reopened; the user opens a new one instead.
+func (ws *walletService) CloseWallet(ctx context.Context, walletID string, reason string) (*models.Wallet, error) {
+	return ws.updateStatus(ctx, walletID, "wallet.closed", func(wallet *models.Wallet) (gen.UpdateWalletStatusParams, error) {
+		if wallet.Status == models.WalletStatusClosed {
+			return gen.UpdateWalletStatusParams{}, utils.BadRequestErr("wallet is already closed")
+		}
+		if wallet.Balance != 0 || wallet.HeldBalance != 0 {
+			return gen.UpdateWalletStatusParams{}, utils.BadRequestErr("wallet balance must be zero to close")
+		}
+		return gen.UpdateWalletStatusParams{
+			Status:       string(models.WalletStatusClosed),
+			StatusReason: sql.NullString{String: reason, Valid: reason != ""},
+		}, nil
+	})
+}
+
+// updateStatus locks the wallet, asks next for the new status and records
+// the change in the audit log, all in one transaction.
+func (ws *walletService) updateStatus(ctx context.Context, walletID string, action string, next func(*models.Wallet) (gen.UpdateWalletStatusParams, error)) (*models.Wallet, error) {
+	tx, err := ws.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var queries gen.Querier
+	if q, ok := ws.queries.(*gen.Queries); ok {
+		queries = q.WithTx(tx)
+	} else {
+		queries = ws.queries
+	}
+
+	wallet, err := ws.LockWalletForUpdate(ctx, tx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := next(wallet)
+	if err != nil {
+		return nil, err
+	}
+	params.ID = wallet.ID
+
+	row, err := queries.UpdateWalletStatus(ctx, params)
+	if err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("update wallet status: %w", err))
+	}
+	updated := mapWallet(row)
+
+	if err := recordAuditEvent(ctx, queries, auditEvent{
+		Action:     action,
+		EntityType: auditEntityWallet,
+		EntityID:   wallet.ID,
+		Before:     walletStatusSnapshot(wallet),
+		After:      walletStatusSnapshot(updated),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.ServerErr(fmt.Errorf("commit transaction: %w", err))
+	}
+	return updated, nil
+}
+
+// checkWalletDebit rejects a transfer out of a wallet that is closed or
+// frozen for debits. role names the wallet in the error, e.g. "sender".
+func checkWalletDebit(wallet *models.Wallet, role string) error {
+	if wallet.AllowsDebits() {
+		return nil
+	}
+	return utils.WalletLockedErr(fmt.Sprintf("%s wallet is %s", role, wallet.Status))
+}
+
+// checkWalletCredit rejects a transfer into a wallet that is closed or
+// frozen for credits.
+func checkWalletCredit(wallet *models.Wallet, role string) error {
+	if wallet.AllowsCredits() {
+		return nil
+	}
+	return utils.WalletLockedErr(fmt.Sprintf("%s wallet is %s", role, wallet.Status))
+}
+
+func walletStatusSnapshot(wallet *models.Wallet) map[string]any {
+	snapshot := map[string]any{"status": wallet.Status}
+	if wallet.FrozenDirection != nil {
+		snapshot["frozen_direction"] = *wallet.FrozenDirection
+	}
+	if wallet.StatusReason != nil {
+		snapshot["reason"] = *wallet.StatusReason
+	}
+	return snapshot
+}
+
+func mapWallet(wallet gen.Wallet) *models.Wallet {
+	result := &models.Wallet{
+		ID:              wallet.ID,
+		UserID:          wallet.UserID,
+		Currency:        wallet.Currency,
+		Balance:         wallet.Balance,
+		HeldBalance:     wallet.HeldBalance,
+		Status:          models.WalletStatus(wallet.Status),
+		FrozenDirection: freezeDirection(wallet.FrozenDirection),
+		CreatedAt:       wallet.CreatedAt,
+		UpdatedAt:       wallet.UpdatedAt,
+	}
 	if wallet.BankAccountID.Valid {
-		bankAccountID = &wallet.BankAccountID.String
-	}
-
-	return &models.Wallet{
-		ID:            wallet.ID,
-		UserID:        wallet.UserID,
-		BankAccountID: bankAccountID,
-		Currency:      wallet.Currency,
-		Balance:       wallet.Balance,
-		CreatedAt:     wallet.CreatedAt,
-		UpdatedAt:     wallet.UpdatedAt,
-	}, nil
+		result.BankAccountID = &wallet.BankAccountID.String
+	}
+	if wallet.StatusReason.Valid {
+		result.StatusReason = &wallet.StatusReason.String
+	}
+	if wallet.ClosedAt.Valid {
+		result.ClosedAt = &wallet.ClosedAt.Time
+	}
+	return result
+}
+
+func freezeDirection(value sql.NullString) *models.FreezeDirection {
+	if !value.Valid {
+		return nil
+	}
+	direction := models.FreezeDirection(value.String)
+	return &direction
 }
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+)
+
+// placeWalletHold reserves funds on a wallet for an initiated transaction. The
+// caller must hold the wallet lock and have checked the available balance.
+func placeWalletHold(ctx context.Context, queries gen.Querier, walletID string, transactionID string, amount money.Money) error {
+	if !amount.IsPositive() {
+		return nil
+	}
+
+	_, err := queries.CreateWalletHold(ctx, gen.CreateWalletHoldParams{
+		WalletID:      walletID,
+		TransactionID: transactionID,
+		Amount:        amount.Amount,
+		Currency:      amount.Currency.String(),
+	})
+	if err != nil {
+		return utils.ServerErr(fmt.Errorf("create wallet hold: %w", err))
+	}
+
+	if err := queries.AdjustWalletHeldBalance(ctx, gen.AdjustWalletHeldBalanceParams{
+		Delta: amount.Amount,
+		ID:    walletID,
+	}); err != nil {
+		return utils.ServerErr(fmt.Errorf("update wallet held balance: %w", err))
+	}
+
+	return recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "wallet.hold_placed",
+		EntityType: auditEntityWallet,
+		EntityID:   walletID,
+		After: map[string]any{
+			"transaction_id": transactionID,
+			"amount":         amount.Amount,
+			"currency":       amount.Currency.String(),
+		},
+	})
+}
+
+// releaseWalletHold ends the active hold for a transaction, either because the
+// funds were captured by confirmation or because the transaction will never
+// be confirmed. It returns the amount that was held, or zero when the
+// transaction had no active hold.
+func releaseWalletHold(ctx context.Context, queries gen.Querier, transactionID string, status models.WalletHoldStatus) (int64, error) {
+	hold, err := queries.ReleaseWalletHold(ctx, gen.ReleaseWalletHoldParams{
+		Status:        string(status),
+		TransactionID: transactionID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, utils.ServerErr(fmt.Errorf("release wallet hold: %w", err))
+	}
+
+	if err := queries.AdjustWalletHeldBalance(ctx, gen.AdjustWalletHeldBalanceParams{
+		Delta: -hold.Amount,
+		ID:    hold.WalletID,
+	}); err != nil {
+		return 0, utils.ServerErr(fmt.Errorf("update wallet held balance: %w", err))
+	}
+
+	if err := recordAuditEvent(ctx, queries, auditEvent{
+		Action:     "wallet.hold_" + string(status),
+		EntityType: auditEntityWallet,
+		EntityID:   hold.WalletID,
+		Before:     map[string]any{"transaction_id": transactionID, "status": models.WalletHoldStatusActive},
+		After: map[string]any{
+			"transaction_id": transactionID,
+			"status":         status,
+			"amount":         hold.Amount,
+			"currency":       hold.Currency,
+		},
+	}); err != nil {
+		return 0, err
+	}
+
+	return hold.Amount, nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaceWalletHold(t *testing.T) {
+	t.Run("reserves funds on the wallet", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+
+		mockQueries.On("CreateWalletHold", mock.Anything, gen.CreateWalletHoldParams{
+			WalletID:      "wallet_1",
+			TransactionID: "tx_1",
+			Amount:        10100,
+			Currency:      "USD",
+		}).Return(gen.WalletHold{ID: "hold_1"}, nil)
+		mockQueries.On("AdjustWalletHeldBalance", mock.Anything, gen.AdjustWalletHeldBalanceParams{
+			Delta: 10100,
+			ID:    "wallet_1",
+		}).Return(nil)
+		expectAuditEvent(mockQueries, "wallet.hold_placed", "wallet_1")
+
+		err := placeWalletHold(context.Background(), mockQueries, "wallet_1", "tx_1", money.NewMoney(10100, money.USD))
+
+		require.NoError(t, err)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("create error", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("CreateWalletHold", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+
+		err := placeWalletHold(context.Background(), mockQueries, "wallet_1", "tx_1", money.NewMoney(10100, money.USD))
+
+		assert.Error(t, err)
+		mockQueries.AssertNotCalled(t, "AdjustWalletHeldBalance", mock.Anything, mock.Anything)
+	})
+}
+
+func TestReleaseWalletHold(t *testing.T) {
+	t.Run("captures active hold", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+
+		mockQueries.On("ReleaseWalletHold", mock.Anything, gen.ReleaseWalletHoldParams{
+			Status:        string(models.WalletHoldStatusCaptured),
+			TransactionID: "tx_1",
+		}).Return(gen.WalletHold{WalletID: "wallet_1", Amount: 10100}, nil)
+		mockQueries.On("AdjustWalletHeldBalance", mock.Anything, gen.AdjustWalletHeldBalanceParams{
+			Delta: -10100,
+			ID:    "wallet_1",
+		}).Return(nil)
+		expectAuditEvent(mockQueries, "wallet.hold_captured", "wallet_1")
+
+		held, err := releaseWalletHold(context.Background(), mockQueries, "tx_1", models.WalletHoldStatusCaptured)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(10100), held)
+		mockQueries.AssertExpectations(t)
+	})
+
+	t.Run("no active hold", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		mockQueries.On("ReleaseWalletHold", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+
+		held, err := releaseWalletHold(context.Background(), mockQueries, "tx_1", models.WalletHoldStatusReleased)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), held)
+		mockQueries.AssertNotCalled(t, "AdjustWalletHeldBalance", mock.Anything, mock.Anything)
+	})
+}
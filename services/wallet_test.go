@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
+	"github.com/IfedayoAwe/payment-processing-service/services/mocks"
+	"github.com/IfedayoAwe/payment-processing-service/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualAccountPolicy_AccountNumber(t *testing.T) {
+	policy := VirtualAccountPolicy{BIN: "30", Length: 10, BankCode: "044"}
+	require.NoError(t, policy.validate())
+
+	number, err := policy.accountNumber(1)
+	require.NoError(t, err)
+	assert.Equal(t, "3000000012", number)
+
+	number, err = policy.accountNumber(9999999)
+	require.NoError(t, err)
+	assert.Len(t, number, 10)
+	assert.Equal(t, "309999999", number[:9])
+
+	_, err = policy.accountNumber(10000000)
+	assert.ErrorContains(t, err, "exhausted")
+}
+
+func TestLuhnCheckDigit(t *testing.T) {
+	// 79927398713 is the standard Luhn example.
+	assert.Equal(t, "3", luhnCheckDigit("7992739871"))
+	assert.Equal(t, "0", luhnCheckDigit("0"))
+}
+
+func TestVirtualAccountPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy VirtualAccountPolicy
+	}{
+		{name: "non-digit BIN", policy: VirtualAccountPolicy{BIN: "3A", Length: 10, BankCode: "044"}},
+		{name: "empty BIN", policy: VirtualAccountPolicy{BIN: "", Length: 10, BankCode: "044"}},
+		{name: "no room for a sequence", policy: VirtualAccountPolicy{BIN: "123456789", Length: 10, BankCode: "044"}},
+		{name: "no bank", policy: VirtualAccountPolicy{BIN: "30", Length: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, tt.policy.validate())
+		})
+	}
+}
+
+func TestWalletService_OpenWallet(t *testing.T) {
+	policy := VirtualAccountPolicy{BIN: "30", Length: 10, BankCode: "044"}
+
+	t.Run("rejects a second open wallet in the currency", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ws := &walletService{queries: mockQueries, virtualAccount: policy}
+
+		mockQueries.On("GetWalletByUserAndCurrency", mock.Anything, gen.GetWalletByUserAndCurrencyParams{UserID: "user_1", Currency: "USD"}).
+			Return(gen.Wallet{ID: "wallet_user1_usd", UserID: "user_1", Currency: "USD", Status: "active"}, nil)
+
+		_, err := ws.OpenWallet(context.Background(), "user_1", money.USD)
+
+		assert.ErrorIs(t, err, utils.ErrDuplicatedKey)
+		mockQueries.AssertNotCalled(t, "CreateWallet", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ws := &walletService{queries: mockQueries, virtualAccount: policy}
+
+		mockQueries.On("GetWalletByUserAndCurrency", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+		mockQueries.On("GetUserByID", mock.Anything, "user_9").Return(nil, sql.ErrNoRows)
+
+		_, err := ws.OpenWallet(context.Background(), "user_9", money.USD)
+
+		assert.ErrorIs(t, err, utils.ErrNotFound)
+	})
+
+	t.Run("currency the account bank does not accept", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		ws := &walletService{queries: mockQueries, virtualAccount: policy}
+
+		mockQueries.On("GetWalletByUserAndCurrency", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+		mockQueries.On("GetUserByID", mock.Anything, "user_1").Return(gen.User{UserID: "user_1"}, nil)
+		mockQueries.On("GetBank", mock.Anything, "044").Return(testBank("044", true), nil)
+
+		_, err := ws.OpenWallet(context.Background(), "user_1", money.GBP)
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest)
+		assert.Contains(t, err.Error(), "GBP wallets are not available")
+	})
+
+	t.Run("currency the user's KYC tier does not allow", func(t *testing.T) {
+		mockQueries := new(mocks.MockQuerier)
+		kyc := KYCPolicy{Enabled: true, Tiers: map[models.KYCTier]KYCTierPolicy{
+			models.KYCTierIdentity: {Currencies: []money.Currency{money.USD}},
+		}}
+		ws := &walletService{queries: mockQueries, virtualAccount: policy, kyc: kyc}
+
+		mockQueries.On("GetWalletByUserAndCurrency", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+		mockQueries.On("GetUserByID", mock.Anything, "user_3").Return(gen.User{UserID: "user_3", KycTier: 1}, nil)
+
+		_, err := ws.OpenWallet(context.Background(), "user_3", money.EUR)
+
+		assert.ErrorIs(t, err, utils.ErrKYCRequired)
+		mockQueries.AssertNotCalled(t, "GetBank", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCheckWalletDirection(t *testing.T) {
+	frozen := func(direction models.FreezeDirection) *models.Wallet {
+		return &models.Wallet{Status: models.WalletStatusFrozen, FrozenDirection: &direction}
+	}
+
+	tests := []struct {
+		name        string
+		wallet      *models.Wallet
+		allowDebit  bool
+		allowCredit bool
+	}{
+		{name: "active", wallet: &models.Wallet{Status: models.WalletStatusActive}, allowDebit: true, allowCredit: true},
+		{name: "frozen for debits", wallet: frozen(models.FreezeDebits), allowDebit: false, allowCredit: true},
+		{name: "frozen for credits", wallet: frozen(models.FreezeCredits), allowDebit: true, allowCredit: false},
+		{name: "frozen both ways", wallet: frozen(models.FreezeBoth), allowDebit: false, allowCredit: false},
+		{name: "closed", wallet: &models.Wallet{Status: models.WalletStatusClosed}, allowDebit: false, allowCredit: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			debitErr := checkWalletDebit(tt.wallet, "sender")
+			creditErr := checkWalletCredit(tt.wallet, "recipient")
+
+			if tt.allowDebit {
+				assert.NoError(t, debitErr)
+			} else {
+				assert.ErrorIs(t, debitErr, utils.ErrWalletLocked)
+			}
+			if tt.allowCredit {
+				assert.NoError(t, creditErr)
+			} else {
+				assert.ErrorIs(t, creditErr, utils.ErrWalletLocked)
+			}
+		})
+	}
+}
@@ -3,7 +3,6 @@ package services
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 
 	"github.com/IfedayoAwe/payment-processing-service/db/gen"
@@ -16,11 +15,13 @@ type WebhookService interface {
 
 type webhookService struct {
 	queries *gen.Queries
+	fields  fieldCipher
 }
 
-func newWebhookService(queries *gen.Queries) WebhookService {
+func newWebhookService(queries *gen.Queries, fields fieldCipher) WebhookService {
 	return &webhookService{
 		queries: queries,
+		fields:  fields,
 	}
 }
 
@@ -30,12 +31,17 @@ func (ws *webhookService) ProcessWebhookEvent(ctx context.Context, providerName,
 		txID = sql.NullString{String: *transactionID, Valid: true}
 	}
 
-	_, err := ws.queries.CreateWebhookEvent(ctx, gen.CreateWebhookEventParams{
+	storedPayload, err := ws.fields.sealWebhookPayload(payload)
+	if err != nil {
+		return utils.ServerErr(err)
+	}
+
+	_, err = ws.queries.CreateWebhookEvent(ctx, gen.CreateWebhookEventParams{
 		ProviderName:      providerName,
 		EventType:         eventType,
 		ProviderReference: providerReference,
 		TransactionID:     txID,
-		Payload:           json.RawMessage(payload),
+		Payload:           storedPayload,
 	})
 	if err != nil {
 		return utils.ServerErr(fmt.Errorf("create webhook event: %w", err))
@@ -4,10 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/IfedayoAwe/payment-processing-service/db/gen"
+	"github.com/IfedayoAwe/payment-processing-service/models"
+	"github.com/IfedayoAwe/payment-processing-service/pkg/money"
 	"github.com/IfedayoAwe/payment-processing-service/queue"
 	"github.com/IfedayoAwe/payment-processing-service/utils"
 )
@@ -18,17 +21,32 @@ type WebhookWorker interface {
 }
 
 type webhookWorker struct {
-	queries *gen.Queries
-	queue   queue.Queue
+	queries  *gen.Queries
+	queue    queue.Queue
+	fields   fieldCipher
+	deposits DepositService
 }
 
-func newWebhookWorker(queries *gen.Queries, queue queue.Queue) WebhookWorker {
+func newWebhookWorker(queries *gen.Queries, queue queue.Queue, fields fieldCipher, deposits DepositService) WebhookWorker {
 	return &webhookWorker{
-		queries: queries,
-		queue:   queue,
+		queries:  queries,
+		queue:    queue,
+		fields:   fields,
+		deposits: deposits,
 	}
 }
 
+// creditReceivedPayload is the part of a credit.received webhook body that a
+// deposit needs. The handler has already validated it.
+type creditReceivedPayload struct {
+	AccountNumber string `json:"account_number"`
+	BankCode      string `json:"bank_code"`
+	Amount        struct {
+		Amount   float64 `json:"amount"`
+		Currency string  `json:"currency"`
+	} `json:"amount"`
+}
+
 func (ww *webhookWorker) ProcessWebhookJob(ctx context.Context, job *queue.Job) error {
 	var payload queue.WebhookJobPayload
 	if err := json.Unmarshal(job.Payload, &payload); err != nil {
@@ -40,20 +58,74 @@ func (ww *webhookWorker) ProcessWebhookJob(ctx context.Context, job *queue.Job)
 		txID = sql.NullString{String: *payload.TransactionID, Valid: true}
 	}
 
-	_, err := ww.queries.CreateWebhookEvent(ctx, gen.CreateWebhookEventParams{
+	storedPayload, err := ww.fields.sealWebhookPayload(payload.Payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = ww.queries.CreateWebhookEvent(ctx, gen.CreateWebhookEventParams{
 		ProviderName:      payload.ProviderName,
 		EventType:         payload.EventType,
 		ProviderReference: payload.ProviderReference,
 		TransactionID:     txID,
-		Payload:           json.RawMessage(payload.Payload),
+		Payload:           storedPayload,
 	})
 	if err != nil {
 		return fmt.Errorf("create webhook event: %w", err)
 	}
 
+	if payload.EventType == models.WebhookEventCreditReceived {
+		return ww.creditDeposit(ctx, payload)
+	}
+
 	return nil
 }
 
+// creditDeposit credits the wallet named by a credit.received event. A
+// notice that can never be credited, such as one for an unknown account, is
+// logged and dropped rather than retried; the event itself is already stored.
+func (ww *webhookWorker) creditDeposit(ctx context.Context, payload queue.WebhookJobPayload) error {
+	ctx = utils.WithActor(ctx, utils.SystemActor("webhook_worker"))
+
+	deposit, err := parseDeposit(payload)
+	if err == nil {
+		_, err = ww.deposits.CreditDeposit(ctx, deposit)
+	}
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) || errors.Is(err, utils.ErrBadRequest) {
+			utils.Logger.Warn().
+				Err(err).
+				Str("provider", payload.ProviderName).
+				Str("provider_reference", payload.ProviderReference).
+				Msg("deposit not credited")
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func parseDeposit(payload queue.WebhookJobPayload) (models.Deposit, error) {
+	var credit creditReceivedPayload
+	if err := json.Unmarshal(payload.Payload, &credit); err != nil {
+		return models.Deposit{}, utils.BadRequestErr(fmt.Sprintf("invalid credit payload: %v", err))
+	}
+
+	currency, err := money.ParseCurrency(credit.Amount.Currency)
+	if err != nil {
+		return models.Deposit{}, utils.BadRequestErr(fmt.Sprintf("invalid deposit currency: %v", err))
+	}
+
+	return models.Deposit{
+		ProviderName:      payload.ProviderName,
+		ProviderReference: payload.ProviderReference,
+		AccountNumber:     credit.AccountNumber,
+		BankCode:          credit.BankCode,
+		Amount:            money.FromMajorUnits(credit.Amount.Amount, currency),
+	}, nil
+}
+
 func (ww *webhookWorker) StartWorker(ctx context.Context) error {
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
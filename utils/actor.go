@@ -0,0 +1,49 @@
+package utils
+
+import "context"
+
+const (
+	ActorTypeUser    = "user"
+	ActorTypeService = "service"
+	ActorTypeAdmin   = "admin"
+	ActorTypeSystem  = "system"
+)
+
+// Actor is who performed an operation, as recorded in the audit log.
+type Actor struct {
+	ID   string
+	Type string
+}
+
+// SystemActor is used for work done by the service itself, such as workers.
+func SystemActor(id string) Actor {
+	return Actor{ID: id, Type: ActorTypeSystem}
+}
+
+type actorKey struct{}
+
+type clientIPKey struct{}
+
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored on ctx, or a system actor when the
+// operation did not come from an authenticated request.
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorKey{}).(Actor); ok {
+		return actor
+	}
+	return SystemActor("system")
+}
+
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+func ClientIPFromContext(ctx context.Context) string {
+	if ip, ok := ctx.Value(clientIPKey{}).(string); ok {
+		return ip
+	}
+	return ""
+}
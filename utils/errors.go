@@ -9,6 +9,13 @@ var (
 	ErrNotFound      = errors.New("not found")
 	ErrDuplicatedKey = errors.New("duplicate entity")
 	ErrBadRequest    = errors.New("bad request")
+	ErrPINLocked     = errors.New("pin locked")
+	ErrTOTPRequired  = errors.New("totp required")
+	ErrLimitExceeded = errors.New("limit exceeded")
+	ErrDeclined      = errors.New("declined")
+	ErrNameMismatch  = errors.New("name mismatch")
+	ErrWalletLocked  = errors.New("wallet locked")
+	ErrKYCRequired   = errors.New("kyc required")
 	ErrInternal      = errors.New("server error")
 )
 
@@ -60,6 +67,34 @@ func BadRequestErr(message string) error {
 	return wrapErrorMessage(ErrBadRequest, message)
 }
 
+func PINLockedErr(message string) error {
+	return wrapErrorMessage(ErrPINLocked, message)
+}
+
+func TOTPRequiredErr(message string) error {
+	return wrapErrorMessage(ErrTOTPRequired, message)
+}
+
+func LimitExceededErr(message string) error {
+	return wrapErrorMessage(ErrLimitExceeded, message)
+}
+
+func DeclinedErr(message string) error {
+	return wrapErrorMessage(ErrDeclined, message)
+}
+
+func NameMismatchErr(message string) error {
+	return wrapErrorMessage(ErrNameMismatch, message)
+}
+
+func WalletLockedErr(message string) error {
+	return wrapErrorMessage(ErrWalletLocked, message)
+}
+
+func KYCRequiredErr(message string) error {
+	return wrapErrorMessage(ErrKYCRequired, message)
+}
+
 func ServerErr(err error) error {
 	return wrapErrorMessage(ErrInternal, err.Error())
 }
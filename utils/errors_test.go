@@ -41,6 +41,48 @@ func TestErrorHelpers(t *testing.T) {
 			baseErr: ErrDuplicatedKey,
 			message: "duplicate key",
 		},
+		{
+			name:    "PINLockedErr",
+			err:     PINLockedErr("PIN is locked"),
+			baseErr: ErrPINLocked,
+			message: "PIN is locked",
+		},
+		{
+			name:    "TOTPRequiredErr",
+			err:     TOTPRequiredErr("TOTP code required"),
+			baseErr: ErrTOTPRequired,
+			message: "TOTP code required",
+		},
+		{
+			name:    "LimitExceededErr",
+			err:     LimitExceededErr("daily transfer limit exceeded"),
+			baseErr: ErrLimitExceeded,
+			message: "daily transfer limit exceeded",
+		},
+		{
+			name:    "DeclinedErr",
+			err:     DeclinedErr("transaction declined"),
+			baseErr: ErrDeclined,
+			message: "transaction declined",
+		},
+		{
+			name:    "NameMismatchErr",
+			err:     NameMismatchErr("account name does not match"),
+			baseErr: ErrNameMismatch,
+			message: "account name does not match",
+		},
+		{
+			name:    "WalletLockedErr",
+			err:     WalletLockedErr("wallet is frozen"),
+			baseErr: ErrWalletLocked,
+			message: "wallet is frozen",
+		},
+		{
+			name:    "KYCRequiredErr",
+			err:     KYCRequiredErr("complete verification to use EUR"),
+			baseErr: ErrKYCRequired,
+			message: "complete verification to use EUR",
+		},
 		{
 			name:    "ServerErr",
 			err:     ServerErr(errors.New("server error")),
@@ -47,3 +47,32 @@ func VerifyPIN(hashedPIN, pin string) error {
 func IsValidPIN(pin string) bool {
 	return len(pin) == PinMinLength && pinRegex.MatchString(pin)
 }
+
+// commonPINs are guessable PINs that the repeated and sequential checks in
+// IsWeakPIN do not catch.
+var commonPINs = map[string]struct{}{
+	"12121": {},
+	"13579": {},
+	"24680": {},
+	"97531": {},
+	"86420": {},
+	"12321": {},
+	"11223": {},
+}
+
+// IsWeakPIN reports whether a PIN is trivially guessable: a single repeated
+// digit, an ascending or descending run, or a well-known pattern.
+func IsWeakPIN(pin string) bool {
+	if _, ok := commonPINs[pin]; ok {
+		return true
+	}
+
+	repeated, ascending, descending := true, true, true
+	for i := 1; i < len(pin); i++ {
+		step := int(pin[i]) - int(pin[i-1])
+		repeated = repeated && step == 0
+		ascending = ascending && step == 1
+		descending = descending && step == -1
+	}
+	return repeated || ascending || descending
+}
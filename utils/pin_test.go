@@ -89,3 +89,26 @@ func TestVerifyPIN(t *testing.T) {
 		assert.Contains(t, err.Error(), "invalid PIN format")
 	})
 }
+
+func TestIsWeakPIN(t *testing.T) {
+	tests := []struct {
+		pin      string
+		expected bool
+	}{
+		{"11111", true},
+		{"00000", true},
+		{"12345", true},
+		{"56789", true},
+		{"54321", true},
+		{"13579", true},
+		{"12121", true},
+		{"27491", false},
+		{"90817", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pin, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsWeakPIN(tt.pin))
+		})
+	}
+}
@@ -12,8 +12,37 @@ type SuccessResponse struct {
 	Message string `json:"message"`
 }
 
+// ErrorCodePINLocked identifies responses for requests rejected because the
+// user's PIN is temporarily locked.
+const ErrorCodePINLocked = "PIN_LOCKED"
+
+// ErrorCodeTOTPRequired identifies responses for confirmations that need an
+// authenticator code the request did not supply or the user has not enrolled.
+const ErrorCodeTOTPRequired = "TOTP_REQUIRED"
+
+// ErrorCodeLimitExceeded identifies transfers rejected by a per-transaction,
+// daily or monthly transfer limit.
+const ErrorCodeLimitExceeded = "LIMIT_EXCEEDED"
+
+// ErrorCodeTransactionDeclined identifies confirmations rejected by risk
+// screening. The rules that fired are not returned to the caller.
+const ErrorCodeTransactionDeclined = "TRANSACTION_DECLINED"
+
+// ErrorCodeNameMismatch identifies transfers rejected because the account
+// name the caller supplied does not match the name on the account.
+const ErrorCodeNameMismatch = "NAME_MISMATCH"
+
+// ErrorCodeWalletLocked identifies transfers rejected because a wallet on
+// either side is frozen in that direction or closed.
+const ErrorCodeWalletLocked = "WALLET_LOCKED"
+
+// ErrorCodeKYCRequired identifies requests the user's KYC tier does not
+// cover, such as a wallet or transfer in a currency the tier does not allow.
+const ErrorCodeKYCRequired = "KYC_REQUIRED"
+
 type ErrorResponse struct {
 	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
 }
 
 type ValidationErrorResponse struct {
@@ -51,6 +80,20 @@ func HandleError(c echo.Context, err error) error {
 		return Conflict(c, message)
 	case errors.Is(baseErr, ErrBadRequest):
 		return BadRequest(c, message)
+	case errors.Is(baseErr, ErrPINLocked):
+		return PINLocked(c, message)
+	case errors.Is(baseErr, ErrTOTPRequired):
+		return TOTPRequired(c, message)
+	case errors.Is(baseErr, ErrLimitExceeded):
+		return LimitExceeded(c, message)
+	case errors.Is(baseErr, ErrDeclined):
+		return Declined(c, message)
+	case errors.Is(baseErr, ErrNameMismatch):
+		return NameMismatch(c, message)
+	case errors.Is(baseErr, ErrWalletLocked):
+		return WalletLocked(c, message)
+	case errors.Is(baseErr, ErrKYCRequired):
+		return KYCRequired(c, message)
 	case errors.Is(baseErr, ErrInternal):
 		fallthrough
 	default:
@@ -84,6 +127,55 @@ func Conflict(c echo.Context, message string) error {
 	return errorResponse(c, http.StatusConflict, message)
 }
 
+func PINLocked(c echo.Context, message string) error {
+	return c.JSON(http.StatusLocked, ErrorResponse{
+		Message: message,
+		Code:    ErrorCodePINLocked,
+	})
+}
+
+func TOTPRequired(c echo.Context, message string) error {
+	return c.JSON(http.StatusForbidden, ErrorResponse{
+		Message: message,
+		Code:    ErrorCodeTOTPRequired,
+	})
+}
+
+func LimitExceeded(c echo.Context, message string) error {
+	return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+		Message: message,
+		Code:    ErrorCodeLimitExceeded,
+	})
+}
+
+func Declined(c echo.Context, message string) error {
+	return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+		Message: message,
+		Code:    ErrorCodeTransactionDeclined,
+	})
+}
+
+func NameMismatch(c echo.Context, message string) error {
+	return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+		Message: message,
+		Code:    ErrorCodeNameMismatch,
+	})
+}
+
+func WalletLocked(c echo.Context, message string) error {
+	return c.JSON(http.StatusLocked, ErrorResponse{
+		Message: message,
+		Code:    ErrorCodeWalletLocked,
+	})
+}
+
+func KYCRequired(c echo.Context, message string) error {
+	return c.JSON(http.StatusForbidden, ErrorResponse{
+		Message: message,
+		Code:    ErrorCodeKYCRequired,
+	})
+}
+
 func InternalError(c echo.Context, err string) error {
 	return c.JSON(http.StatusInternalServerError, InternalErrorResponse{
 		Message: "internal error",
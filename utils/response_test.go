@@ -62,6 +62,46 @@ func TestHandleError(t *testing.T) {
 			err:        DuplicateKeyErr("duplicate key"),
 			statusCode: http.StatusConflict,
 		},
+		{
+			name:       "PINLocked",
+			err:        PINLockedErr("PIN is locked"),
+			statusCode: http.StatusLocked,
+		},
+		{
+			name:       "KYCRequired",
+			err:        KYCRequiredErr("complete verification to use EUR"),
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "TOTPRequired",
+			err:        TOTPRequiredErr("TOTP code required"),
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "LimitExceeded",
+			err:        LimitExceededErr("daily transfer limit exceeded"),
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Declined",
+			err:        DeclinedErr("transaction declined"),
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "NameMismatch",
+			err:        NameMismatchErr("account name does not match"),
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "WalletLocked",
+			err:        WalletLockedErr("wallet is frozen"),
+			statusCode: http.StatusLocked,
+		},
+		{
+			name:       "KYCRequired",
+			err:        KYCRequiredErr("complete verification to use EUR"),
+			statusCode: http.StatusForbidden,
+		},
 		{
 			name:       "InternalError",
 			err:        ServerErr(errors.New("internal error")),
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"errors"
+
 	"github.com/go-playground/locales/en"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
@@ -33,7 +35,29 @@ func GetTranslator() ut.Translator {
 	return translator
 }
 
+// FieldError is a validation failure found in code rather than by a validate
+// tag. FormatValidationErrors reports it under Field, like a tag failure.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
 func FormatValidationErrors(err error) map[string]string {
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		return map[string]string{
+			fieldErr.Field: fieldErr.Err.Error(),
+		}
+	}
+
 	validationErrors, ok := err.(validator.ValidationErrors)
 	if !ok {
 		return map[string]string{
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,6 +28,12 @@ func TestFormatValidationErrors(t *testing.T) {
 	assert.Contains(t, errors, "Amount")
 }
 
+func TestFormatValidationErrors_FieldError(t *testing.T) {
+	err := &FieldError{Field: "ToAccountNumber", Err: errors.New("IBAN check digits are invalid")}
+
+	assert.Equal(t, map[string]string{"ToAccountNumber": "IBAN check digits are invalid"}, FormatValidationErrors(err))
+}
+
 func TestInitValidator(t *testing.T) {
 	v1 := InitValidator()
 	v2 := InitValidator()